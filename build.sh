@@ -14,3 +14,4 @@ function buildpkg() {
 }
 
 buildpkg go-ibax "github.com/IBAX-io/go-ibax" "$HOMEDIR/main.go"
+buildpkg ibax-tool "github.com/IBAX-io/go-ibax" "$HOMEDIR/cmd/ibax-tool"
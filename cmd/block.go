@@ -0,0 +1,346 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/rollback"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// blockCmd represents the block command
+var blockCmd = &cobra.Command{
+	Use:   "block",
+	Short: "Block tools",
+}
+
+// blockInspectCmd represents the block inspect command
+var blockInspectCmd = newBlockInspectCmd()
+
+// newBlockInspectCmd returns the block inspect command, it's shared by the node and ibax-tool
+func newBlockInspectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect <hex-or-file>",
+		Short: "Decode the block binary and print its header and transactions",
+		Long: `Decode the block binary and print its header and transactions.
+The argument is the hex encoded block or the path to the file with hex encoded or raw binary block.
+The database is not used, so contract names are known only if the block contains the execution results.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := readBlockInput(args[0])
+			if err != nil {
+				log.WithError(err).Fatal("reading block")
+			}
+			if err = inspectBlock(os.Stdout, data); err != nil {
+				log.WithError(err).Fatal("inspecting block")
+			}
+		},
+	}
+}
+
+// NewToolBlockCmd returns the block command of ibax-tool, its subcommands work offline without the database
+func NewToolBlockCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "block",
+		Short: "Block tools",
+	}
+	c.AddCommand(newBlockInspectCmd())
+	return c
+}
+
+// blockCompareTraceCmd represents the block compare-trace command
+var blockCompareTraceCmd = &cobra.Command{
+	Use:   "compare-trace <file> <file>",
+	Short: "Compare the execution traces of the block from two nodes",
+	Long: `Compare the execution traces of the block from two nodes and print the first divergent record.
+The traces are written to the --blockTrace directory of the node while playing the blocks.
+The exit code is 1 if the traces differ.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var traces [2]*block.Trace
+		for i, path := range args {
+			var err error
+			if traces[i], err = block.ReadTraceFile(path); err != nil {
+				log.WithFields(log.Fields{"error": err, "path": path}).Fatal("reading block trace")
+			}
+		}
+		if !compareTraces(os.Stdout, traces[0], traces[1]) {
+			os.Exit(1)
+		}
+	},
+}
+
+// blockDiagnoseMerkleCmd represents the block diagnose-merkle command
+var blockDiagnoseMerkleCmd = &cobra.Command{
+	Use:   "diagnose-merkle <hex-or-file> <hex-or-file>",
+	Short: "Find the transaction which makes the merkle roots of the local and the remote blocks differ",
+	Long: `Find the first transaction which makes the merkle roots of the local and the remote blocks differ.
+The arguments are the hex encoded blocks or the paths to the files like in the inspect command.
+The exit code is 1 if the merkle roots differ.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var blocks [2]*block.Block
+		for i, arg := range args {
+			data, err := readBlockInput(arg)
+			if err != nil {
+				log.WithError(err).Fatal("reading block")
+			}
+			bd := &types.BlockData{}
+			if err = bd.UnmarshallBlock(data); err != nil {
+				log.WithFields(log.Fields{"error": err, "block": arg}).Fatal("unmarshalling block")
+			}
+			blocks[i] = &block.Block{BlockData: bd}
+		}
+		same, err := diagnoseMerkleRoot(os.Stdout, blocks[0], blocks[1])
+		if err != nil {
+			log.WithError(err).Fatal("diagnosing merkle root")
+		}
+		if !same {
+			os.Exit(1)
+		}
+	},
+}
+
+var traceOutput string
+
+// blockTraceCmd represents the block trace command
+var blockTraceCmd = &cobra.Command{
+	Use:   "trace <block_id>",
+	Short: "Play the existing block again and print its execution trace",
+	Long: `Play the existing block again within the db transaction which is rolled back and print its execution trace:
+the contract calls, the statements and the fuel of every transaction. The trace has the format of the --blockTrace
+files, so it can be compared with the trace of the other node by compare-trace. The node must be stopped.`,
+	Args:   cobra.ExactArgs(1),
+	PreRun: loadConfigWKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "block_id": args[0]}).Fatal("parsing block id")
+		}
+		f := utils.LockOrDie(conf.Config.DirPathConf.LockFilePath)
+		defer f.Unlock()
+
+		if err = sqldb.GormInit(conf.Config.DB); err != nil {
+			log.WithError(err).Fatal("init db")
+		}
+		if err = syspar.SysUpdate(nil); err != nil {
+			log.WithError(err).Fatal("can't read platform parameters")
+		}
+		if err = syspar.SysTableColType(nil); err != nil {
+			log.WithError(err).Error("updating sys table col type")
+		}
+		smart.InitVM()
+		if err = smart.LoadContracts(); err != nil {
+			log.WithError(err).Fatal("loading contracts")
+		}
+		w := io.Writer(os.Stdout)
+		if len(traceOutput) > 0 {
+			out, err := os.Create(traceOutput)
+			if err != nil {
+				log.WithFields(log.Fields{"error": err, "path": traceOutput}).Fatal("creating trace file")
+			}
+			defer out.Close()
+			w = out
+		}
+		if err = rollback.Replay(id, w); err != nil {
+			log.WithFields(log.Fields{"error": err, "block_id": id}).Error("replaying block")
+		}
+	},
+}
+
+func init() {
+	blockTraceCmd.Flags().StringVarP(&traceOutput, "output", "o", "", "the file of the trace, stdout by default")
+	blockCmd.AddCommand(blockInspectCmd, blockCompareTraceCmd, blockDiagnoseMerkleCmd, blockTraceCmd)
+}
+
+// diagnoseMerkleRoot prints the divergent transaction of the blocks, it returns true if the merkle roots are the same
+func diagnoseMerkleRoot(w io.Writer, local, remote *block.Block) (bool, error) {
+	d, err := block.DiagnoseMerkleRoot(local, remote)
+	if errors.Is(err, block.ErrSameMerkleRoot) {
+		fmt.Fprintf(w, "merkle roots of block %d are the same, %d transactions\n", local.Header.BlockId, len(local.TxFullData))
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	tx := func(info *block.MerkleTxInfo) string {
+		if info == nil {
+			return "<no transaction>"
+		}
+		name, ok := types.TxTypeName(byte(info.Type))
+		if !ok {
+			name = fmt.Sprintf("type %d", info.Type)
+		}
+		s := fmt.Sprintf("%s %s key_id %d", info.Hash, name, info.KeyID)
+		if len(info.Contract) > 0 {
+			s += " contract " + info.Contract
+		}
+		return s
+	}
+	fmt.Fprintf(w, "merkle roots of block %d diverge at tx %d after %d steps\n", d.BlockID, d.Index, d.Steps)
+	fmt.Fprintf(w, "  local:  %s root %s\n", tx(d.Local), d.LocalRoot)
+	fmt.Fprintf(w, "  remote: %s root %s\n", tx(d.Remote), d.RemoteRoot)
+	return false, nil
+}
+
+// compareTraces prints the first divergent record of the traces, it returns true if they are the same
+func compareTraces(w io.Writer, a, b *block.Trace) bool {
+	if a.BlockID != b.BlockID {
+		fmt.Fprintf(w, "traces are of different blocks %d and %d\n", a.BlockID, b.BlockID)
+		return false
+	}
+	i, ra, rb := block.CompareTraces(a, b)
+	if i < 0 {
+		fmt.Fprintf(w, "traces of block %d are identical, %d records\n", a.BlockID, len(a.Records))
+		return true
+	}
+	record := func(r *block.TraceRecord) string {
+		if r == nil {
+			return "<end of trace>"
+		}
+		return r.String()
+	}
+	var hash []byte
+	if ra != nil {
+		hash = ra.TxHash
+	} else {
+		hash = rb.TxHash
+	}
+	fmt.Fprintf(w, "traces of block %d diverge at record %d, tx %x\n", a.BlockID, i, hash)
+	fmt.Fprintf(w, "  first:  %s\n", record(ra))
+	fmt.Fprintf(w, "  second: %s\n", record(rb))
+	if ra != nil && rb != nil && !bytes.Equal(ra.TxHash, rb.TxHash) {
+		fmt.Fprintf(w, "  the second trace is at tx %x\n", rb.TxHash)
+	}
+	return false
+}
+
+// readBlockInput returns the block binary from the file or the hex string
+func readBlockInput(arg string) ([]byte, error) {
+	if _, err := os.Stat(arg); err != nil {
+		bin, err := hex.DecodeString(trimHex(arg))
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither the file nor the hex string", arg)
+		}
+		return bin, nil
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	if bin, err := hex.DecodeString(trimHex(string(data))); err == nil {
+		return bin, nil
+	}
+	return data, nil
+}
+
+func trimHex(s string) string {
+	return strings.TrimPrefix(strings.TrimSpace(s), "0x")
+}
+
+func inspectBlock(w io.Writer, data []byte) error {
+	block := &types.BlockData{}
+	if err := block.UnmarshallBlock(data); err != nil {
+		return err
+	}
+	header := block.GetHeader()
+	prev := block.GetPrevHeader()
+	fmt.Fprintln(w, "Block header")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "  block_id\t%d\n", header.GetBlockId())
+	fmt.Fprintf(tw, "  hash\t%x\n", header.GetBlockHash())
+	fmt.Fprintf(tw, "  prev_hash\t%x\n", prev.GetBlockHash())
+	fmt.Fprintf(tw, "  merkle_root\t%s\n", block.MerkleRoot)
+	fmt.Fprintf(tw, "  timestamp\t%d (%s)\n", header.GetTimestamp(),
+		time.Unix(header.GetTimestamp(), 0).UTC().Format(time.RFC3339))
+	fmt.Fprintf(tw, "  ecosystem_id\t%d\n", header.GetEcosystemId())
+	fmt.Fprintf(tw, "  key_id\t%d (%s)\n", header.GetKeyId(), converter.AddressToString(header.GetKeyId()))
+	fmt.Fprintf(tw, "  node_position\t%d\n", header.GetNodePosition())
+	fmt.Fprintf(tw, "  version\t%d\n", header.GetVersion())
+	format, _ := types.BlockFormatOf(data)
+	fmt.Fprintf(tw, "  format\t%d\n", format)
+	fmt.Fprintf(tw, "  consensus_mode\t%d\n", header.GetConsensusMode())
+	fmt.Fprintf(tw, "  network_id\t%d\n", header.GetNetworkId())
+	fmt.Fprintf(tw, "  sign\t%x\n", header.GetSign())
+	fmt.Fprintf(tw, "  transactions\t%d\n", len(block.TxFullData))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	contracts := make(map[string]string)
+	for _, tx := range block.GetAfterTxs().GetTxs() {
+		if tx.GetLts() != nil {
+			contracts[hex.EncodeToString(tx.GetLts().GetHash())] = tx.GetLts().GetContractName()
+		}
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tHASH\tTYPE\tKEY_ID\tECOSYSTEM\tCONTRACT\tEXPEDITE")
+	var parseErrors []string
+	for i, txData := range block.TxFullData {
+		tx, err := transaction.DecodeTransaction(txData)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("  tx %d: %v", i, err))
+			fmt.Fprintf(tw, "%d\t-\t%d\t-\t-\t-\t-\n", i, txByteType(txData))
+			continue
+		}
+		hash := hex.EncodeToString(tx.Hash())
+		name, _ := types.TxTypeName(tx.Type())
+		ecosystem := int64(1)
+		contract := name
+		if itx, ok := tx.Inner.(*transaction.SmartTransactionParser); ok {
+			ecosystem = itx.TxSmart.EcosystemID
+			if tx.Type() == types.SmartContractTxType {
+				contract = fmt.Sprintf("#%d", itx.TxSmart.ID)
+			}
+		}
+		if v, ok := contracts[hash]; ok && len(v) > 0 {
+			contract = v
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%d\t%s\t%s\n", i, hash, name, tx.KeyID(), ecosystem,
+			contract, tx.Expedite().String())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(parseErrors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Parse errors")
+		for _, v := range parseErrors {
+			fmt.Fprintln(w, v)
+		}
+	}
+	return nil
+}
+
+func txByteType(data []byte) int {
+	if len(data) == 0 {
+		return -1
+	}
+	return int(data[0])
+}
@@ -0,0 +1,50 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestInspectBlock(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, _, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keystore.NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bd := &types.BlockData{
+		Header:     &types.BlockHeader{BlockId: 5, Timestamp: 1700000000, EcosystemId: 1, NodePosition: 2, Version: 1},
+		PrevHeader: &types.BlockHeader{BlockId: 4, BlockHash: []byte("prev")},
+		TxFullData: [][]byte{{types.SmartContractTxType, 1, 2, 3}},
+	}
+	data, err := bd.MarshallBlock(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err = inspectBlock(&out, data); err != nil {
+		t.Fatal(err)
+	}
+	// the columns of the tables are compared by single spaces
+	got := strings.Join(strings.Fields(out.String()), " ")
+	for _, want := range []string{"block_id 5", "timestamp 1700000000 (2023-11-14T22:13:20Z)",
+		"node_position 2", "transactions 1", "0 - 3 - - - -", "Parse errors tx 0: "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output hasn't %q:\n%s", want, out.String())
+		}
+	}
+}
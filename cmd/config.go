@@ -14,6 +14,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -79,6 +80,7 @@ func init() {
 	cmdFlags.Int64Var(&conf.Config.LocalConf.MaxPageGenerationTime, "mpgt", 3000, "Max page generation time in ms")
 	cmdFlags.Int64Var(&conf.Config.LocalConf.HTTPServerMaxBodySize, "mbs", 1<<20, "Max server body size in byte")
 	cmdFlags.Int64Var(&conf.Config.LocalConf.NetworkID, "networkID", 1, "Network ID")
+	cmdFlags.Int64Var(&conf.Config.LocalConf.MaxFutureBlockAge, "maxFutureBlockAge", consts.MaxFutureBlockAge, "Max time in seconds the block time could be in the future")
 	cmdFlags.StringVar(&conf.Config.LocalConf.RunNodeMode, "runMode", consts.NoneCLB, "running node mode, example NONE|CLB|CLBMaster|SubNode")
 
 	// TCP Server
@@ -103,6 +105,9 @@ func init() {
 	cmdFlags.IntVar(&conf.Config.DB.IdleInTxTimeout, "dbIdleInTxTimeout", 5000, "DB idle tx timeout")
 	cmdFlags.IntVar(&conf.Config.DB.MaxIdleConns, "dbMaxIdleConns", 5, "DB sets the maximum number of connections in the idle connection pool")
 	cmdFlags.IntVar(&conf.Config.DB.MaxOpenConns, "dbMaxOpenConns", 100, "sets the maximum number of open connections to the database")
+	cmdFlags.IntVar(&conf.Config.DB.SlowStatementThreshold, "dbSlowStatementThreshold", 1000, "DB slow statement threshold of the block transactions in milliseconds, 0 disables")
+	cmdFlags.IntVar(&conf.Config.DB.MaxBinLogStatementBytes, "dbMaxBinLogStatementBytes", 1<<20, "DB max size of the binlog statement in bytes, the larger one is split or fails the transaction, 0 disables")
+	cmdFlags.IntVar(&conf.Config.DB.ReadConns, "dbReadConns", 20, "DB size of the pool of the read only connections of the API, 0 reads by the main pool")
 
 	//Redis
 	cmdFlags.BoolVar(&conf.Config.Redis.Enable, "redisEnable", false, "enable redis")
@@ -110,12 +115,20 @@ func init() {
 	cmdFlags.IntVar(&conf.Config.Redis.Port, "redisPort", 6379, "redis port")
 	cmdFlags.IntVar(&conf.Config.Redis.DbName, "redisDb", 0, "redis db")
 	cmdFlags.StringVar(&conf.Config.Redis.Password, "redisPassword", "123456", "redis password")
+	cmdFlags.BoolVar(&conf.Config.KVHook.Redis, "kvHookRedis", false, "copy the rows changed by the blocks to redis")
+	cmdFlags.IntVar(&conf.Config.KVHook.RedisDb, "kvHookRedisDb", 2, "redis db of the changed rows")
+	cmdFlags.StringVar(&conf.Config.KVHook.Prefix, "kvHookPrefix", "ibax:", "prefix of the redis keys of the changed rows")
 
 	// StatsD
 	cmdFlags.StringVar(&conf.Config.StatsD.Host, "statsdHost", "127.0.0.1", "StatsD host")
 	cmdFlags.IntVar(&conf.Config.StatsD.Port, "statsdPort", 8125, "StatsD port")
 	cmdFlags.StringVar(&conf.Config.StatsD.Name, "statsdName", "chain", "StatsD name")
 
+	// Tracing
+	cmdFlags.StringVar(&conf.Config.Tracing.Endpoint, "tracingEndpoint", "", "OTLP/HTTP endpoint (host:port) of the block processing traces, disabled if empty")
+	cmdFlags.BoolVar(&conf.Config.Tracing.Insecure, "tracingInsecure", false, "Export the traces without TLS")
+	cmdFlags.Float64Var(&conf.Config.Tracing.SampleRatio, "tracingSampleRatio", 1, "Share of the traced blocks")
+
 	// Centrifugo
 	cmdFlags.StringVar(&conf.Config.Centrifugo.Secret, "centSecret", "127.0.0.1", "Centrifugo secret")
 	cmdFlags.StringVar(&conf.Config.Centrifugo.URL, "centUrl", "127.0.0.1", "Centrifugo URL")
@@ -140,13 +153,82 @@ func init() {
 	cmdFlags.IntVar(&conf.Config.BanKey.BadTime, "badTime", 5, "Period for bad tx (minutes)")
 	cmdFlags.IntVar(&conf.Config.BanKey.BanTime, "banTime", 15, "Ban time in minutes")
 	cmdFlags.IntVar(&conf.Config.BanKey.BadTx, "badTx", 5, "Maximum bad tx during badTime minutes")
+	cmdFlags.IntVar(&conf.Config.BanKey.MaxKeys, "banMaxKeys", 10000, "Maximum keys which are tracked for the ban")
+	cmdFlags.StringToIntVar(&conf.Config.BanKey.ReasonBadTx, "badTxReason", map[string]int{}, "Maximum bad tx of the reason (ingress, queue, block) during badTime minutes")
 
 	// CryptoSettings
 	cmdFlags.StringVar(&conf.Config.CryptoSettings.Hasher, "hasher", crypto.HashAlgo_KECCAK256.String(), fmt.Sprintf("Hash Algorithm (%s | %s | %s | %s)", crypto.HashAlgo_SHA256, crypto.HashAlgo_KECCAK256, crypto.HashAlgo_SHA3_256, crypto.HashAlgo_SM3))
 	cmdFlags.StringVar(&conf.Config.CryptoSettings.Cryptoer, "cryptoer", crypto.AsymAlgo_ECC_Secp256k1.String(), fmt.Sprintf("Key and Sign Algorithm (%s | %s | %s | %s)", crypto.AsymAlgo_ECC_P256, crypto.AsymAlgo_ECC_Secp256k1, crypto.AsymAlgo_ECC_P512, crypto.AsymAlgo_SM2))
 
+	// Keystore
+	cmdFlags.StringVar(&conf.Config.Keystore.Type, "keystore", keystore.TypeFile, fmt.Sprintf("Keystore of the node private key (%s | %s | %s)", keystore.TypeFile, keystore.TypeEnv, keystore.TypeVault))
+	cmdFlags.StringVar(&conf.Config.Keystore.EnvVar, "keystoreEnv", keystore.DefaultEnvVar, "Environment variable with the node private key for env keystore")
+	cmdFlags.StringVar(&conf.Config.Keystore.PassphraseEnv, "keystorePassphraseEnv", keystore.DefaultPassphraseEnvVar, "Environment variable with the passphrase of the encrypted key file for file keystore")
+	cmdFlags.StringVar(&conf.Config.Keystore.Vault.Address, "vaultAddr", "", "Vault address for vault keystore, the token is read from VAULT_TOKEN by default")
+	cmdFlags.StringVar(&conf.Config.Keystore.Vault.Mount, "vaultMount", keystore.DefaultVaultMount, "Path of Vault transit secrets engine")
+	cmdFlags.StringVar(&conf.Config.Keystore.Vault.Key, "vaultKey", "", "Name of ecdsa-p256 key in Vault transit secrets engine")
+	cmdFlags.IntVar(&conf.Config.Keystore.Vault.Timeout, "vaultTimeout", int(keystore.DefaultVaultTimeout.Seconds()), "Vault request timeout in seconds")
+
+	// Health
+	cmdFlags.Int64Var(&conf.Config.Health.MaxBlockAge, "healthMaxBlockAge", 0, "Max age of the last block in seconds for the health check, 10 block gaps by default")
+	cmdFlags.IntVar(&conf.Config.Health.MinPeers, "healthMinPeers", 0, "Min available peers for the health check")
+	cmdFlags.Int64Var(&conf.Config.Health.MaxQueueSize, "healthMaxQueue", 0, "Max pending transactions for the health check, 0 is unlimited")
+	cmdFlags.Int64Var(&conf.Config.Health.MaxReplicationLag, "healthMaxReplLag", 30, "Max replication lag of the database replica in seconds for the health check")
+	cmdFlags.Int64Var(&conf.Config.Health.MinDiskFree, "healthMinDiskFree", 1024, "Min free space in megabytes of the data directory for the health check")
+
+	// StrictBlock
+	cmdFlags.BoolVar(&conf.Config.StrictBlock.Enabled, "strictBlockGeneration", false, "Abort the generated block on the first bad transaction instead of skipping it")
+	cmdFlags.IntVar(&conf.Config.StrictBlock.Retries, "strictBlockRetries", 3, "Assemblies of the block without the bad transactions in the strict generation")
+
+	// ProofOfWork
+
+	// BlockChunkSize
+	cmdFlags.Int64Var(&conf.Config.BlockChunkSize, "blockChunkSize", consts.BlockChunkSize, "Size in bytes of the chunks of the downloaded blocks, the larger blocks are resumed after the broken connection")
+
 	// BlockSyncMethod
 	cmdFlags.StringVar(&conf.Config.BlockSyncMethod.Method, "sync", types.BlockSyncMethod_CONTRACTVM.String(), fmt.Sprintf("Block sync method (%s | %s)", types.BlockSyncMethod_CONTRACTVM, types.BlockSyncMethod_SQLDML))
 
+	// RewardAddress
+	cmdFlags.StringVar(&conf.Config.RewardAddress, "rewardAddress", "", "Account of the block rewards, it must be registered by @1SetRewardDestination with the node key")
+
+	// MMAPBlockStorePath
+	cmdFlags.StringVar(&conf.Config.MMAPBlockStorePath, "mmapBlockStore", "", "Directory of the memory-mapped copy of the blocks for archival nodes, disabled if empty")
+
+	// DA
+	cmdFlags.StringVar(&conf.Config.DA.Type, "daType", "", "Data availability layer of the block transactions (file | blob), disabled if empty")
+	cmdFlags.StringVar(&conf.Config.DA.Path, "daPath", "", "Directory of the files of the block transactions")
+	cmdFlags.StringVar(&conf.Config.DA.BlobRelayer, "daBlobRelayer", "", "URL of the relayer which sends the blob transactions")
+	cmdFlags.StringVar(&conf.Config.DA.BeaconNode, "daBeaconNode", "", "URL of the beacon node which serves the blobs")
+	cmdFlags.IntVar(&conf.Config.DA.Timeout, "daTimeout", 30, "Timeout in seconds of the requests of the blobs")
+
+	// TxGroupWorkers
+	cmdFlags.IntVar(&conf.Config.TxGroupWorkers, "txGroupWorkers", 0, "Maximum of the groups of the transfer self and utxo transactions played at once, 0 is the number of CPUs")
+
+	// SignWorkers
+	cmdFlags.IntVar(&conf.Config.SignWorkers, "signWorkers", 0, "Workers which verify the signatures of the block transactions before the play, 0 is the number of CPUs, -1 disables them")
+
+	// PreExecution
+	cmdFlags.BoolVar(&conf.Config.PreExecution, "preExecution", false, "Play the queued contract transactions while waiting for the slot and drop the failed ones from the generated block")
+	cmdFlags.StringVar(&conf.Config.TxOrdering, "txOrdering", "fee", "Order of the queued transactions in the generated block: fifo, fee or fair")
+
+	// NotifyWorkers
+	cmdFlags.IntVar(&conf.Config.NotifyWorkers, "notifyWorkers", 4, "Number of the workers which send the notifications of the committed blocks, 0 sends them within the play of the block")
+	cmdFlags.IntVar(&conf.Config.NotifyQueueSize, "notifyQueueSize", 1024, "Maximum of the blocks and the clients which wait for the sending of the notifications")
+
+	// Pruned
+	cmdFlags.BoolVar(&conf.Config.Pruned, "pruned", false, "Delete the transactions and the rollback records of the old blocks, the headers and the state are kept")
+	cmdFlags.Int64Var(&conf.Config.PruneRetentionBlocks, "pruneRetentionBlocks", 100000, "Last blocks which are kept whole by the pruned node, rollback_blocks_1 at least")
+
+	// BlockTracePath
+	cmdFlags.StringVar(&conf.Config.BlockTracePath, "blockTrace", "", "Directory of the execution traces of the played blocks for consensus debugging, disabled if empty")
+
+	// ContractStatsDays
+	cmdFlags.IntVar(&conf.Config.ContractStatsDays, "contractStatsDays", 30, "Days of the local contract execution statistics, 0 disables them")
+	cmdFlags.IntVar(&conf.Config.ResourceUsageDays, "resourceUsageDays", 7, "Days of the resources consumed by the contract calls, 0 disables them")
+	cmdFlags.IntVar(&conf.Config.BlockResourcesDays, "blockResourcesDays", 7, "Days of the resources of every played block, the older blocks are down-sampled to hours, 0 disables them")
+	cmdFlags.IntVar(&conf.Config.BlockResourcesHourDays, "blockResourcesHourDays", 90, "Days of the hourly resources of the blocks, the older hours are down-sampled to days")
+	cmdFlags.IntVar(&conf.Config.PriorityInversionThresholdBlocks, "priorityInversionThresholdBlocks", 3, "Blocks which the high-fee transaction waits behind the low-fee transaction of its key before the warning, 0 disables the check")
+	cmdFlags.IntVar(&conf.Config.MaxTxWatchSeconds, "maxTxWatchSeconds", 300, "Seconds which the websocket of the transaction status waits for the change before the timeout")
+
 	viper.BindPFlags(configCmd.PersistentFlags())
 }
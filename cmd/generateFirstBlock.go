@@ -13,7 +13,9 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/block"
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/genesis"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
 	log "github.com/sirupsen/logrus"
@@ -23,6 +25,7 @@ import (
 var stopNetworkBundleFilepath string
 var testBlockchain bool
 var privateBlockchain bool
+var genesisSpecFilepath string
 
 // generateFirstBlockCmd represents the generateFirstBlock command
 var generateFirstBlockCmd = &cobra.Command{
@@ -43,21 +46,11 @@ func init() {
 	generateFirstBlockCmd.Flags().StringVar(&stopNetworkBundleFilepath, "stopNetworkCert", "", "Filepath to the fullchain of certificates for network stopping")
 	generateFirstBlockCmd.Flags().BoolVar(&testBlockchain, "test", false, "if true - test blockchain")
 	generateFirstBlockCmd.Flags().BoolVar(&privateBlockchain, "private", false, "if true - all transactions will be free")
+	generateFirstBlockCmd.Flags().StringVar(&genesisSpecFilepath, "genesis", "", "Filepath to the JSON or YAML genesis spec of the initial state")
 }
 
 func genesisBlock() ([]byte, error) {
 	now := time.Now().Unix()
-	header := &types.BlockHeader{
-		BlockId:       1,
-		Timestamp:     now,
-		EcosystemId:   0,
-		KeyId:         conf.Config.KeyID,
-		NetworkId:     conf.Config.LocalConf.NetworkID,
-		NodePosition:  0,
-		Version:       consts.BlockVersion,
-		RollbacksHash: crypto.Hash([]byte(`0`)),
-		ConsensusMode: consts.HonorNodeMode,
-	}
 	decodeKeyFile := func(kName string) []byte {
 		filepath := filepath.Join(conf.Config.DirPathConf.KeysDir, kName)
 		data, err := os.ReadFile(filepath)
@@ -95,6 +88,26 @@ func genesisBlock() ([]byte, error) {
 		pb = 1
 	}
 
+	var spec []byte
+	if len(genesisSpecFilepath) > 0 {
+		s, err := genesis.Load(genesisSpecFilepath)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{"filepath": genesisSpecFilepath}).Fatal("Loading genesis spec")
+		}
+		if s.Timestamp > 0 {
+			now = s.Timestamp
+		}
+		if s.Test {
+			test = 1
+		}
+		if s.Private {
+			pb = 1
+		}
+		if spec, err = s.Marshal(); err != nil {
+			log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Fatal("Marshalling genesis spec")
+		}
+	}
+
 	fbp := new(transaction.FirstBlockParser)
 	tx, err := fbp.BinMarshal(&types.FirstBlock{
 		KeyID:                 conf.Config.KeyID,
@@ -104,13 +117,20 @@ func genesisBlock() ([]byte, error) {
 		StopNetworkCertBundle: stopNetworkCert,
 		Test:                  test,
 		PrivateBlockchain:     pb,
+		Genesis:               spec,
 	})
 	if err != nil {
 		log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Fatal("first block body bin marshalling")
 	}
-	return block.MarshallBlock(types.WithCurHeader(header),
-		types.WithPrevHeader(&types.BlockHeader{
-			BlockHash:     crypto.DoubleHash([]byte(`0`)),
-			RollbacksHash: crypto.Hash([]byte(`0`)),
-		}), types.WithTxFullData([][]byte{tx}))
+	genesis, err := block.GenesisBuilder().
+		WithTimestamp(now).
+		WithKeyID(conf.Config.KeyID).
+		WithNetworkID(conf.Config.LocalConf.NetworkID).
+		AddRawTransaction(tx).
+		WithSigner(syspar.GetNodeSigner()).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return genesis.BinData, nil
 }
@@ -0,0 +1,25 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// ibax-tool is the offline tool for the data of the node, it doesn't use the database and the config of the node
+package main
+
+import (
+	"github.com/IBAX-io/go-ibax/cmd"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:   "ibax-tool",
+		Short: "ibax offline tools",
+	}
+	root.AddCommand(cmd.NewToolBlockCmd())
+	if err := root.Execute(); err != nil {
+		log.WithError(err).Fatal("Executing root command")
+	}
+}
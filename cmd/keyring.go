@@ -0,0 +1,204 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/network/tcpclient"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keyringForce         bool
+	addrsForKeyRotation  []string
+	errPassphraseConfirm = errors.New("passphrases don't match")
+)
+
+// keyringCmd manages the node private key of the file keystore
+var keyringCmd = &cobra.Command{
+	Use:   "keyring",
+	Short: "Managing the encrypted node private key",
+}
+
+var keyringGenerateCmd = &cobra.Command{
+	Use:    "generate",
+	Short:  "Generating the node key pair, the private key is encrypted with the passphrase",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		privPath := nodePrivateKeyPath()
+		if _, err := os.Stat(privPath); err == nil && !keyringForce {
+			log.WithFields(log.Fields{"path": privPath}).Fatal("node private key exists, use --force to overwrite it")
+		}
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			log.WithError(err).Fatal("reading passphrase")
+		}
+		priv, pub, err := crypto.GenKeyPair()
+		if err != nil {
+			log.WithError(err).Fatal("generating node keys")
+		}
+		if err = writeNodeKeys(priv, pub, passphrase); err != nil {
+			log.WithError(err).Fatal("writing node keys")
+		}
+		fmt.Println(crypto.PubToHex(pub))
+	},
+}
+
+var keyringRotateCmd = &cobra.Command{
+	Use:    "rotate",
+	Short:  "Generating the new node key and announcing the rotation to the network",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		privPath := nodePrivateKeyPath()
+		passphrase, err := readPassphrase(false)
+		if err != nil {
+			log.WithError(err).Fatal("reading passphrase")
+		}
+		oldPriv, err := keystore.ReadKeyFile(privPath, passphrase)
+		if err != nil {
+			log.WithError(err).Fatal("reading node private key")
+		}
+		oldKey, err := keystore.NewKeySigner(oldPriv)
+		if err != nil {
+			log.WithError(err).Fatal("reading node private key")
+		}
+		newPriv, newPub, err := crypto.GenKeyPair()
+		if err != nil {
+			log.WithError(err).Fatal("generating node keys")
+		}
+		newKey, err := keystore.NewKeySigner(newPriv)
+		if err != nil {
+			log.WithError(err).Fatal("generating node keys")
+		}
+		rotation, err := transaction.NewKeyRotation(oldKey, newKey, time.Now().UnixMilli())
+		if err != nil {
+			log.WithError(err).Fatal("signing key rotation")
+		}
+		data, err := new(transaction.KeyRotationParser).BinMarshal(rotation)
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.MarshallingError}).Fatal("marshalling key rotation")
+		}
+
+		var hash []byte
+		for _, addr := range addrsForKeyRotation {
+			h, err := tcpclient.SendKeyRotation(addr, &network.KeyRotationRequest{Data: data})
+			if err != nil {
+				log.WithFields(log.Fields{"error": err, "type": consts.NetworkError, "addr": addr}).Error("Sending request")
+				continue
+			}
+			hash = h
+			log.WithFields(log.Fields{"addr": addr, "hash": hex.EncodeToString(h)}).Info("Sending request")
+		}
+		if hash == nil {
+			log.Fatal("key rotation isn't accepted, the node key is kept")
+		}
+
+		// the old key is kept until the transaction is in the block
+		backup := fmt.Sprintf("%s.%d.bak", privPath, time.Now().Unix())
+		if err = os.Rename(privPath, backup); err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.IOError, "path": backup}).Fatal("backing up node private key")
+		}
+		if err = writeNodeKeys(newPriv, newPub, passphrase); err != nil {
+			log.WithError(err).Fatal("writing node keys")
+		}
+		log.WithFields(log.Fields{"public_key": crypto.PubToHex(newPub), "backup": backup}).
+			Info("node key is rotated, restart the node when the transaction is in the block")
+	},
+}
+
+var keyringExportCmd = &cobra.Command{
+	Use:    "export",
+	Short:  "Printing the decrypted node private key",
+	PreRun: loadConfig,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, err := readPassphrase(false)
+		if err != nil {
+			log.WithError(err).Fatal("reading passphrase")
+		}
+		priv, err := keystore.ReadKeyFile(nodePrivateKeyPath(), passphrase)
+		if err != nil {
+			log.WithError(err).Fatal("reading node private key")
+		}
+		fmt.Println(hex.EncodeToString(priv))
+	},
+}
+
+func nodePrivateKeyPath() string {
+	return filepath.Join(conf.Config.DirPathConf.KeysDir, consts.NodePrivateKeyFilename)
+}
+
+// writeNodeKeys writes the private key encrypted with the passphrase and the public key
+func writeNodeKeys(priv, pub []byte, passphrase string) error {
+	data, err := keystore.EncryptKey(priv, passphrase)
+	if err != nil {
+		return err
+	}
+	if err = createFile(nodePrivateKeyPath(), data); err != nil {
+		return err
+	}
+	return createFile(filepath.Join(conf.Config.DirPathConf.KeysDir, consts.NodePublicKeyFilename), []byte(crypto.PubToHex(pub)))
+}
+
+// readPassphrase returns the passphrase of the environment variable of the keystore or reads it
+// from the standard input, the entered new passphrase is confirmed
+func readPassphrase(confirm bool) (string, error) {
+	name := conf.Config.Keystore.PassphraseEnv
+	if name == "" {
+		name = keystore.DefaultPassphraseEnvVar
+	}
+	if passphrase := os.Getenv(name); passphrase != "" {
+		return passphrase, nil
+	}
+	reader := bufio.NewReader(os.Stdin)
+	read := func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && len(line) == 0 {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	passphrase, err := read("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", keystore.ErrEmptyPassphrase
+	}
+	if confirm {
+		again, err := read("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errPassphraseConfirm
+		}
+	}
+	return passphrase, nil
+}
+
+func init() {
+	keyringGenerateCmd.Flags().BoolVar(&keyringForce, "force", false, "Overwrite the existing node private key")
+	keyringRotateCmd.Flags().StringArrayVar(&addrsForKeyRotation, "addr", []string{}, "Node address")
+	keyringRotateCmd.MarkFlagRequired("addr")
+	keyringCmd.AddCommand(keyringGenerateCmd, keyringRotateCmd, keyringExportCmd)
+}
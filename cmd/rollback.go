@@ -8,6 +8,7 @@ package cmd
 import (
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/rollback"
 	"github.com/IBAX-io/go-ibax/packages/smart"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
@@ -49,6 +50,7 @@ var rollbackCmd = &cobra.Command{
 			log.WithError(err).Fatal("rollback to block id")
 			return
 		}
+		auditRollback(blockID)
 
 		// block id = 1, is a special case for full rollback
 		if blockID != 1 {
@@ -58,6 +60,17 @@ var rollbackCmd = &cobra.Command{
 	},
 }
 
+// createAudit appends the entry to the audit log within the db transaction
+var createAudit = (*sqldb.AuditLog).Create
+
+// auditRollback writes the rollback of the node administrator to the audit log
+func auditRollback(blockID int64) {
+	audit := sqldb.NewAuditLog(sqldb.AuditRollback, conf.Config.KeyID, blockID, nil, []byte(converter.Int64ToStr(blockID)))
+	if err := createAudit(audit, nil); err != nil {
+		log.WithError(err).Error("writing audit log")
+	}
+}
+
 func init() {
 	rollbackCmd.Flags().Int64Var(&blockID, "blockId", 1, "blockID to rollback")
 	rollbackCmd.MarkFlagRequired("blockId")
@@ -0,0 +1,30 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+func TestAuditRollback(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	prev, prevKey := createAudit, conf.Config.KeyID
+	defer func() { createAudit, conf.Config.KeyID = prev, prevKey }()
+	conf.Config.KeyID = 42
+	var audits []*sqldb.AuditLog
+	createAudit = func(a *sqldb.AuditLog, dbTx *sqldb.DbTransaction) error {
+		audits = append(audits, a)
+		return nil
+	}
+	auditRollback(10)
+	if len(audits) != 1 || audits[0].Action != sqldb.AuditRollback || audits[0].KeyID != 42 || audits[0].BlockID != 10 {
+		t.Fatalf("wrong audit of the rollback %+v", audits)
+	}
+}
@@ -39,6 +39,9 @@ func init() {
 		configCmd,
 		stopNetworkCmd,
 		versionCmd,
+		blockCmd,
+		keyringCmd,
+		snapshotCmd,
 	)
 
 	consts.BuildInfo = func() string {
@@ -0,0 +1,66 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/snapshot"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "State snapshot tools",
+}
+
+// snapshotExportCmd represents the snapshot export command
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Export the state of the node at the last block into the snapshot directory",
+	Long: `Export the state of the node at the last committed block into the chunked snapshot directory.
+The node may keep working, the blocks committed during the export aren't exported.
+The new node restores the snapshot with start --snapshot-import <dir> and continues syncing from its block.`,
+	Args:   cobra.ExactArgs(1),
+	PreRun: loadConfigWKey,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := sqldb.GormInit(conf.Config.DB); err != nil {
+			log.WithError(err).Fatal("init db")
+		}
+		m, err := snapshot.Export(context.Background(), args[0])
+		if err != nil {
+			log.WithError(err).Fatal("exporting snapshot")
+		}
+		fmt.Printf("block %d %s, %d chunks\n", m.BlockID, m.BlockHash, len(m.Chunks))
+	},
+}
+
+// snapshotVerifyCmd represents the snapshot verify command
+var snapshotVerifyCmd = &cobra.Command{
+	Use:   "verify <dir>",
+	Short: "Check the hashes of the chunks of the snapshot directory",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		m, err := snapshot.ReadManifest(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("reading snapshot manifest")
+		}
+		if err = snapshot.Verify(args[0], m); err != nil {
+			log.WithError(err).Fatal("verifying snapshot")
+		}
+		fmt.Printf("block %d %s, %d chunks are valid\n", m.BlockID, m.BlockHash, len(m.Chunks))
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotExportCmd, snapshotVerifyCmd)
+}
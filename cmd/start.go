@@ -28,4 +28,5 @@ func init() {
 	time.Local = time.UTC
 	startCmd.Flags().BoolVar(&conf.Config.TestRollBack, "testRollBack", false, "Starts special set of daemons")
 	startCmd.Flags().BoolVar(&conf.Config.FuncBench, "funcBench", false, "Disable access checking in some built-in functions for benchmarks")
+	startCmd.Flags().StringVar(&conf.Config.SnapshotDir, "snapshot-import", "", "Restore the state from the snapshot directory and continue syncing from its block")
 }
@@ -15,6 +15,8 @@ import (
 
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/types"
 
 	log "github.com/sirupsen/logrus"
@@ -30,6 +32,7 @@ type Mode struct {
 	EcosystemGetter   types.EcosystemGetter
 	ContractRunner    types.SmartContractRunner
 	ClientTxProcessor types.ClientTxPreprocessor
+	BlockAssembler    BlockAssembler
 }
 
 // Client represents data of client
@@ -39,6 +42,7 @@ type Client struct {
 	EcosystemID   int64
 	EcosystemName string
 	RoleID        int64
+	Permission    apikey.Permission // the permission of the service account, empty for the wallet session
 }
 
 func (c *Client) Prefix() string {
@@ -73,6 +77,17 @@ func errorResponse(w http.ResponseWriter, err error, code ...int) {
 	jsonResponse(w, et)
 }
 
+// readTransaction begins the read transaction of the last committed block, so the statements of the handler
+// don't wait for the played block and see the same state. The block is returned by the X-Block-Id header
+func readTransaction(w http.ResponseWriter) (*sqldb.DbTransaction, error) {
+	dbTx, blockID, err := sqldb.StartReadTransaction()
+	if err != nil {
+		return nil, err
+	}
+	w.Header().Set("X-Block-Id", converter.Int64ToStr(blockID))
+	return dbTx, nil
+}
+
 type formValidator interface {
 	Validate(r *http.Request) error
 }
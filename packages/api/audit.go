@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type auditForm struct {
+	paginatorForm
+	Action string `schema:"action"`
+	From   int64  `schema:"from"`
+	To     int64  `schema:"to"`
+}
+
+type auditResult struct {
+	Count int64            `json:"count"`
+	List  []sqldb.AuditLog `json:"list"`
+}
+
+type auditVerifyResult struct {
+	Valid    bool  `json:"valid"`
+	Checked  int64 `json:"checked"`
+	BrokenID int64 `json:"broken_id,omitempty"`
+}
+
+func (f *auditForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	if f.From < 0 || f.To < 0 || (f.To > 0 && f.From > f.To) {
+		return errUndefineval.Errorf("from/to")
+	}
+	return nil
+}
+
+func getAuditHandler(w http.ResponseWriter, r *http.Request) {
+	form := &auditForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	list, count, err := sqldb.GetAuditLogs(form.Action, form.From, form.To, form.Offset, form.Limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting audit log")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, &auditResult{Count: count, List: list})
+}
+
+func getAuditVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	broken, checked, err := sqldb.VerifyAuditLog()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("verifying audit log")
+		errorResponse(w, err)
+		return
+	}
+	if broken != 0 {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "id": broken}).Warn("audit log hash chain is broken")
+	}
+
+	jsonResponse(w, &auditVerifyResult{Valid: broken == 0, Checked: checked, BrokenID: broken})
+}
@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/gorilla/mux"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type bansResult struct {
+	List  []transaction.BanInfo `json:"list"`
+	Stats transaction.BanStats  `json:"stats"`
+}
+
+// getBansHandler returns the keys which are banned by the node for the bad transactions
+func getBansHandler(w http.ResponseWriter, r *http.Request) {
+	bans := transaction.Bans()
+	jsonResponse(w, &bansResult{List: bans.List(), Stats: bans.Stats()})
+}
+
+// liftBanHandler adds the transaction which lifts the ban of the key on all nodes, it's signed by the node key
+func liftBanHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	keyID := converter.StringToAddress(mux.Vars(r)["key"])
+	if keyID == 0 {
+		errorResponse(w, errInvalidWallet.Errorf(mux.Vars(r)["key"]))
+		return
+	}
+	data, err := transaction.NewBanLift(syspar.GetNodeSigner(), keyID, time.Now().UnixMilli())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing ban lift")
+		errorResponse(w, err)
+		return
+	}
+	hash, err := transaction.QueueBanLift(data)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "key_id": keyID}).Error("adding ban lift")
+		errorResponse(w, err)
+		return
+	}
+	logger.WithFields(log.Fields{"key_id": keyID}).Info("ban lift is added")
+	jsonResponse(w, &struct {
+		Hash string `json:"hash"`
+	}{fmt.Sprintf("%x", hash)})
+}
@@ -6,14 +6,15 @@
 package api
 
 import (
-	"bytes"
 	"errors"
 	"net/http"
 
 	"github.com/IBAX-io/go-ibax/packages/block"
 	"github.com/IBAX-io/go-ibax/packages/common"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/types"
 	"github.com/gorilla/mux"
@@ -52,6 +53,8 @@ type blockInfoResult struct {
 	RollbacksHash []byte `json:"rollbacks_hash"`
 	NodePosition  int64  `json:"node_position"`
 	ConsensusMode int32  `json:"consensus_mode"`
+	BaseGasPrice  int64  `json:"base_gas_price"`
+	GasUsed       int64  `json:"gas_used"`
 }
 
 func getBlockInfoHandler(w http.ResponseWriter, r *http.Request) {
@@ -81,9 +84,86 @@ func getBlockInfoHandler(w http.ResponseWriter, r *http.Request) {
 		RollbacksHash: block.RollbacksHash,
 		NodePosition:  block.NodePosition,
 		ConsensusMode: block.ConsensusMode,
+		BaseGasPrice:  block.BaseGasPrice,
+		GasUsed:       block.GasUsed,
 	})
 }
 
+func getBlockAttestationHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	params := mux.Vars(r)
+
+	blockID := converter.StrToInt64(params["id"])
+	attestation, err := node.SignBlockAttestation(blockID, syspar.GetNodeSigner())
+	if err != nil {
+		if errors.Is(err, node.ErrBlockNotFound) {
+			logger.WithFields(log.Fields{"type": consts.NotFound, "id": blockID}).Debug("block with id not found")
+			errorResponse(w, errNotFound)
+			return
+		}
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "id": blockID}).Error("signing block attestation")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, attestation)
+}
+
+// getCheckpointHandler returns the finalized checkpoint of the block, the last one without the block id
+func getCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	var (
+		m     = &sqldb.Checkpoint{}
+		found bool
+		err   error
+	)
+	if id, ok := mux.Vars(r)["id"]; ok {
+		found, err = m.Get(converter.StrToInt64(id))
+	} else {
+		m, found, err = sqldb.GetLastCheckpoint()
+	}
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting checkpoint")
+		errorResponse(w, err)
+		return
+	}
+	if !found {
+		errorResponse(w, errNotFound)
+		return
+	}
+	c, err := node.CheckpointFromModel(m)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling checkpoint")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, c)
+}
+
+type stateDiffResult struct {
+	BlockID int64             `json:"block_id"`
+	Diffs   []sqldb.StateDiff `json:"diffs"`
+}
+
+// getBlockStateDiffHandler returns the rows which are changed by the transactions of the block
+func getBlockStateDiffHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	blockID := converter.StrToInt64(mux.Vars(r)["id"])
+	diffs, found, err := sqldb.GetBlockStateDiffs(blockID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "id": blockID}).Error("getting block state diff")
+		errorResponse(w, err)
+		return
+	}
+	if !found {
+		logger.WithFields(log.Fields{"type": consts.NotFound, "id": blockID}).Debug("block with id not found")
+		errorResponse(w, errNotFound)
+		return
+	}
+	jsonResponse(w, &stateDiffResult{BlockID: blockID, Diffs: diffs})
+}
+
 type TxInfo struct {
 	Hash         []byte         `json:"hash"`
 	ContractName string         `json:"contract_name"`
@@ -138,7 +218,7 @@ func getBlocksTxInfoHandler(w http.ResponseWriter, r *http.Request) {
 
 	result := map[int64][]TxInfo{}
 	for _, blockModel := range blocks {
-		blck, err := block.UnmarshallBlock(bytes.NewBuffer(blockModel.Data), false)
+		blck, err := block.UnmarshallBlockChain(&blockModel, false)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "bolck_id": blockModel.ID}).Error("on unmarshalling block")
 			errorResponse(w, err)
@@ -194,6 +274,7 @@ type BlockHeaderInfo struct {
 	Sign         []byte `json:"-"`
 	Hash         []byte `json:"-"`
 	Version      int    `json:"version"`
+	BaseGasPrice int64  `json:"base_gas_price"`
 }
 
 type BlockDetailedInfo struct {
@@ -242,7 +323,7 @@ func getBlocksDetailedInfoHandler(w http.ResponseWriter, r *http.Request) {
 
 	result := map[int64]BlockDetailedInfo{}
 	for _, blockModel := range blocks {
-		blck, err := block.UnmarshallBlock(bytes.NewBuffer(blockModel.Data), false)
+		blck, err := block.UnmarshallBlockChain(&blockModel, false)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "block_id": blockModel.ID}).Error("on unmarshalling block")
 			errorResponse(w, err)
@@ -288,6 +369,7 @@ func getBlocksDetailedInfoHandler(w http.ResponseWriter, r *http.Request) {
 			Sign:         blck.Header.Sign,
 			Hash:         blck.Header.BlockHash,
 			Version:      int(blck.Header.Version),
+			BaseGasPrice: blck.Header.BaseGasPrice,
 		}
 
 		bdi := BlockDetailedInfo{
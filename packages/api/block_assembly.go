@@ -0,0 +1,33 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+)
+
+// BlockAssembler runs the dry run of the block which the node would generate
+type BlockAssembler interface {
+	DryRunBlock(ctx context.Context) (*block.DryRunReport, error)
+}
+
+// getBlockAssemblyHandler returns the transactions of the queue which the next block of the node would include,
+// reject or skip. Nothing is written by the dry run
+func (m Mode) getBlockAssemblyHandler(w http.ResponseWriter, r *http.Request) {
+	if m.BlockAssembler == nil {
+		errorResponse(w, errNotImplemented)
+		return
+	}
+	report, err := m.BlockAssembler.DryRunBlock(r.Context())
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, report)
+}
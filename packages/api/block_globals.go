@@ -0,0 +1,17 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+)
+
+// getBlockGlobalsHandler returns the state of the groups of the transactions of the block play
+func getBlockGlobalsHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, block.GetTxGroupsSnapshot())
+}
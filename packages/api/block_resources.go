@@ -0,0 +1,158 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultResourcesWindow = 24 * time.Hour
+	// maxResourcesWindow limits the rows which are aggregated by one request
+	maxResourcesWindow = 31 * 24 * time.Hour
+)
+
+type blockResourcesForm struct {
+	paginatorForm
+	From       int64  `schema:"from"` // unix time, to minus one day by default
+	To         int64  `schema:"to"`   // unix time, now by default
+	Resolution string `schema:"resolution"`
+
+	from, to time.Time
+}
+
+func (f *blockResourcesForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	if len(f.Resolution) == 0 {
+		f.Resolution = sqldb.ResolutionBlock
+	}
+	if !sqldb.IsResolution(f.Resolution) {
+		return errUndefineval.Errorf("resolution")
+	}
+	f.to = time.Now()
+	if f.To > 0 {
+		f.to = time.Unix(f.To, 0)
+	}
+	f.from = f.to.Add(-defaultResourcesWindow)
+	if f.From > 0 {
+		f.from = time.Unix(f.From, 0)
+	}
+	if f.From < 0 || f.To < 0 || !f.from.Before(f.to) {
+		return errUndefineval.Errorf("from")
+	}
+	if f.Resolution != sqldb.ResolutionBlock && f.to.Sub(f.from) > maxResourcesWindow {
+		return errUndefineval.Errorf("to")
+	}
+	return nil
+}
+
+// blockResourcesValues are the resources of the block
+type blockResourcesValues struct {
+	TxCount       float64 `json:"tx_count"`
+	Size          float64 `json:"size"`
+	Fuel          float64 `json:"fuel"`
+	ExecNs        float64 `json:"exec_ns"`
+	Savepoints    float64 `json:"savepoints"`
+	Notifications float64 `json:"notifications"`
+}
+
+type blockResourcesItem struct {
+	BlockID     int64                `json:"block_id"`
+	LastBlockID int64                `json:"last_block_id"`
+	PeriodStart time.Time            `json:"period_start"`
+	Blocks      int64                `json:"blocks"`
+	TxTypes     map[int]int64        `json:"tx_types"`
+	Avg         blockResourcesValues `json:"avg"`
+	Max         blockResourcesValues `json:"max"`
+}
+
+type blockResourcesResult struct {
+	Resolution string               `json:"resolution"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	List       []blockResourcesItem `json:"list"`
+}
+
+// newBlockResourcesItem returns the averages and the maxima of the resources of the blocks of r
+func newBlockResourcesItem(r *sqldb.BlockResources) blockResourcesItem {
+	item := blockResourcesItem{
+		BlockID:     r.BlockID,
+		LastBlockID: r.LastBlockID,
+		PeriodStart: r.PeriodStart,
+		Blocks:      r.Blocks,
+		TxTypes:     r.TxCounts,
+		Max: blockResourcesValues{
+			TxCount:       float64(r.TxCountMax),
+			Size:          float64(r.SizeMax),
+			Fuel:          float64(r.FuelMax),
+			ExecNs:        float64(r.ExecNsMax),
+			Savepoints:    float64(r.SavepointsMax),
+			Notifications: float64(r.NotificationsMax),
+		},
+	}
+	if r.Blocks > 0 {
+		blocks := float64(r.Blocks)
+		item.Avg = blockResourcesValues{
+			TxCount:       float64(r.TxCount) / blocks,
+			Size:          float64(r.Size) / blocks,
+			Fuel:          float64(r.Fuel) / blocks,
+			ExecNs:        float64(r.ExecNs) / blocks,
+			Savepoints:    float64(r.Savepoints) / blocks,
+			Notifications: float64(r.Notifications) / blocks,
+		}
+	}
+	return item
+}
+
+func getBlockResourcesHandler(w http.ResponseWriter, r *http.Request) {
+	form := &blockResourcesForm{paginatorForm: paginatorForm{defaultLimit: 100}}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	var (
+		rows []*sqldb.BlockResources
+		from = form.from
+	)
+	if form.Resolution == sqldb.ResolutionBlock {
+		list, err := sqldb.GetBlockResources(from, form.to, form.Limit)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block resources")
+			errorResponse(w, err)
+			return
+		}
+		for i := range list {
+			rows = append(rows, &list[i])
+		}
+	} else {
+		// the first period is complete
+		from = sqldb.PeriodStart(from, form.Resolution)
+		list, err := sqldb.GetBlockResources(from, form.to, 0)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block resources")
+			errorResponse(w, err)
+			return
+		}
+		rows = sqldb.AggregateBlockResources(list, form.Resolution)
+	}
+
+	result := &blockResourcesResult{Resolution: form.Resolution, From: from, To: form.to,
+		List: make([]blockResourcesItem, 0, len(rows))}
+	for _, row := range rows {
+		result.List = append(result.List, newBlockResourcesItem(row))
+	}
+	jsonResponse(w, result)
+}
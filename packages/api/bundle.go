@@ -0,0 +1,95 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/bundle"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type bundleExportForm struct {
+	ecosystemForm
+	Seeds string `schema:"seeds"`
+}
+
+type bundlePlanForm struct {
+	ecosystemForm
+	Data      string `schema:"data"`
+	Overwrite bool   `schema:"overwrite"`
+}
+
+func (f *bundlePlanForm) Validate(r *http.Request) error {
+	if len(f.Data) == 0 {
+		return errUndefineval.Errorf("data")
+	}
+	return f.ecosystemForm.Validate(r)
+}
+
+type bundlePlanResult struct {
+	*bundle.Plan
+	Conflicts int `json:"conflicts"`
+	Pending   int `json:"pending"`
+}
+
+func (m Mode) getBundleExportHandler(w http.ResponseWriter, r *http.Request) {
+	form := &bundleExportForm{ecosystemForm: ecosystemForm{Validator: m.EcosystemGetter}}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	var seeds []string
+	for _, name := range strings.Split(form.Seeds, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			seeds = append(seeds, name)
+		}
+	}
+	b, err := bundle.Export(bundle.NewDBSource(nil), converter.StrToInt64(form.EcosystemPrefix), seeds)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("exporting bundle")
+		errorResponse(w, err)
+		return
+	}
+	if err = b.Sign(syspar.GetNodeSigner()); err != nil {
+		logger.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("signing bundle")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, b)
+}
+
+// postBundlePlanHandler returns what the import of the bundle would change without applying it
+func (m Mode) postBundlePlanHandler(w http.ResponseWriter, r *http.Request) {
+	form := &bundlePlanForm{ecosystemForm: ecosystemForm{Validator: m.EcosystemGetter}}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	b, err := bundle.Parse([]byte(form.Data))
+	if err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+	plan, err := bundle.MakePlan(bundle.NewDBSource(nil), converter.StrToInt64(form.EcosystemPrefix), b, form.Overwrite)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("planning bundle import")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, &bundlePlanResult{Plan: plan, Conflicts: len(plan.Conflicts()), Pending: len(plan.Pending())})
+}
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxCausedDelays is the most number of the delayed contracts which are traced to the transaction
+const maxCausedDelays = 1000
+
+// getCausedDelays returns the delayed contracts which are traced to the transaction
+var getCausedDelays = sqldb.GetCausedDelays
+
+type causedDelayResult struct {
+	ID            int64  `json:"id"`
+	Contract      string `json:"contract"`
+	KeyID         string `json:"key_id"`
+	BlockID       int64  `json:"block_id"`
+	EveryBlock    int64  `json:"every_block"`
+	Counter       int64  `json:"counter"`
+	Limit         int64  `json:"limit"`
+	Deleted       bool   `json:"deleted"`
+	OriginTxHash  string `json:"origin_tx_hash"`
+	OriginBlockID int64  `json:"origin_block_id"`
+	Depth         int64  `json:"depth"`
+}
+
+// getCausedDelaysHandler returns the delayed contracts which are created by the transaction and by the
+// transactions of these delayed contracts, depth 1 is for the contracts created by the transaction itself
+func getCausedDelaysHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	params := mux.Vars(r)
+
+	hash, err := hex.DecodeString(params["hash"])
+	if err != nil || len(hash) == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding tx hash from hex")
+		errorResponse(w, errHashWrong)
+		return
+	}
+	delays, err := getCausedDelays(nil, hash, maxCausedDelays)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting caused delayed contracts")
+		errorResponse(w, err)
+		return
+	}
+
+	result := make([]causedDelayResult, 0, len(delays))
+	for _, d := range delays {
+		result = append(result, causedDelayResult{
+			ID:            d.ID,
+			Contract:      d.Contract,
+			KeyID:         converter.Int64ToStr(d.KeyID),
+			BlockID:       d.BlockID,
+			EveryBlock:    d.EveryBlock,
+			Counter:       d.Counter,
+			Limit:         d.Limit,
+			Deleted:       d.Delete,
+			OriginTxHash:  hex.EncodeToString(d.OriginTxHash),
+			OriginBlockID: d.OriginBlockID,
+			Depth:         d.Depth,
+		})
+	}
+	jsonResponse(w, result)
+}
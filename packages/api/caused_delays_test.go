@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/gorilla/mux"
+)
+
+func TestCausedDelaysHandler(t *testing.T) {
+	origin, delayedTx := []byte{0xab, 0xcd}, []byte{0x01}
+	defer func(get func(*sqldb.DbTransaction, []byte, int) ([]sqldb.CausedDelay, error)) {
+		getCausedDelays = get
+	}(getCausedDelays)
+	getCausedDelays = func(_ *sqldb.DbTransaction, hash []byte, limit int) ([]sqldb.CausedDelay, error) {
+		if !bytes.Equal(hash, origin) || limit != maxCausedDelays {
+			return nil, nil
+		}
+		return []sqldb.CausedDelay{
+			{DelayedContract: &sqldb.DelayedContract{ID: 3, Contract: "@1First", KeyID: -5, BlockID: 11, EveryBlock: 1,
+				Limit: 1, OriginTxHash: origin, OriginBlockID: 10}, Depth: 1},
+			{DelayedContract: &sqldb.DelayedContract{ID: 4, Contract: "@1Second", KeyID: -5, Delete: true,
+				OriginTxHash: delayedTx, OriginBlockID: 11}, Depth: 2},
+		}, nil
+	}
+
+	r := mux.NewRouter()
+	r.Use(loggerMiddleware)
+	r.HandleFunc("/tx/{hash}/caused-delays", getCausedDelaysHandler).Methods("GET")
+	get := func(hash string, code int) []causedDelayResult {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tx/"+hash+"/caused-delays", nil))
+		if w.Code != code {
+			t.Fatalf("%s: expected %d, got %d %s", hash, code, w.Code, w.Body.String())
+		}
+		var result []causedDelayResult
+		if code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return result
+	}
+
+	got := get("abcd", http.StatusOK)
+	want := []causedDelayResult{
+		{ID: 3, Contract: "@1First", KeyID: "-5", BlockID: 11, EveryBlock: 1, Limit: 1, OriginTxHash: "abcd",
+			OriginBlockID: 10, Depth: 1},
+		{ID: 4, Contract: "@1Second", KeyID: "-5", Deleted: true, OriginTxHash: "01", OriginBlockID: 11, Depth: 2},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("wrong caused delays %+v", got)
+	}
+	// the transaction without delayed contracts returns the empty list
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tx/ef/caused-delays", nil))
+	if w.Code != http.StatusOK || bytes.TrimSpace(w.Body.Bytes())[0] != '[' {
+		t.Errorf("expected the empty list, got %d %s", w.Code, w.Body.String())
+	}
+	get("xyz", http.StatusBadRequest)
+}
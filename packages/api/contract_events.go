@@ -0,0 +1,99 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type contractEventsForm struct {
+	paginatorForm
+	Ecosystem int64  `schema:"ecosystem"`
+	Contract  string `schema:"contract"`
+	Event     string `schema:"event"`
+	Address   string `schema:"address"`
+	FromBlock int64  `schema:"from_block"`
+	keyID     int64
+}
+
+func (f *contractEventsForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	if f.Ecosystem < 0 {
+		return errUndefineval.Errorf("ecosystem")
+	}
+	if f.FromBlock < 0 {
+		return errUndefineval.Errorf("from_block")
+	}
+	if len(f.Address) > 0 {
+		if f.keyID = converter.AddressToID(f.Address); f.keyID == 0 {
+			return errInvalidWallet.Errorf(f.Address)
+		}
+	}
+	return nil
+}
+
+type contractEventItem struct {
+	BlockID      int64           `json:"block_id"`
+	TxHash       string          `json:"tx_hash"`
+	LogIndex     int64           `json:"log_index"`
+	EcosystemID  int64           `json:"ecosystem_id"`
+	Address      string          `json:"address"`
+	ContractName string          `json:"contract_name"`
+	EventName    string          `json:"event_name"`
+	Data         json.RawMessage `json:"data"`
+}
+
+type contractEventsResult struct {
+	Count int64               `json:"count"`
+	List  []contractEventItem `json:"list"`
+}
+
+func getContractEventsHandler(w http.ResponseWriter, r *http.Request) {
+	form := &contractEventsForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	events, count, err := sqldb.GetContractEvents(sqldb.ContractEventFilter{
+		EcosystemID:  form.Ecosystem,
+		ContractName: form.Contract,
+		EventName:    form.Event,
+		KeyID:        form.keyID,
+		FromBlock:    form.FromBlock,
+	}, form.Offset, form.Limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contract events")
+		errorResponse(w, err)
+		return
+	}
+
+	list := make([]contractEventItem, 0, len(events))
+	for _, e := range events {
+		list = append(list, contractEventItem{
+			BlockID:      e.BlockID,
+			TxHash:       hex.EncodeToString(e.TxHash),
+			LogIndex:     e.LogIndex,
+			EcosystemID:  e.EcosystemID,
+			Address:      converter.AddressToString(e.KeyID),
+			ContractName: e.ContractName,
+			EventName:    e.EventName,
+			Data:         json.RawMessage(e.Data),
+		})
+	}
+	jsonResponse(w, &contractEventsResult{Count: count, List: list})
+}
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/script"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+type graphNodeData struct {
+	ID       string `json:"id"`
+	External bool   `json:"external"`
+}
+
+type graphEdgeData struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+type graphNode struct {
+	Data graphNodeData `json:"data"`
+}
+
+type graphEdge struct {
+	Data graphEdgeData `json:"data"`
+}
+
+type contractGraphResult struct {
+	Elements struct {
+		Nodes []graphNode `json:"nodes"`
+		Edges []graphEdge `json:"edges"`
+	} `json:"elements"`
+	Cycles [][]string `json:"cycles"`
+}
+
+// getContractGraphHandler returns the graph of the calls between the contracts of the ecosystem in the
+// elements format of Cytoscape.js, the cycles of the calls are the lists of the contracts
+func getContractGraphHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	params := mux.Vars(r)
+
+	ecosystem := converter.StrToInt64(params["id"])
+	if ecosystem <= 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": params["id"]}).Error("converting ecosystem id")
+		errorResponse(w, errEcosystem.Errorf(ecosystem))
+		return
+	}
+	graph, err := script.BuildCallGraph(ecosystem, nil)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	result := &contractGraphResult{Cycles: graph.Cycles}
+	result.Elements.Nodes = make([]graphNode, 0, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		result.Elements.Nodes = append(result.Elements.Nodes, graphNode{
+			Data: graphNodeData{ID: n.Name, External: n.External}})
+	}
+	result.Elements.Edges = make([]graphEdge, 0, len(graph.Edges))
+	for _, e := range graph.Edges {
+		result.Elements.Edges = append(result.Elements.Edges, graphEdge{
+			Data: graphEdgeData{ID: e.Caller + "->" + e.Callee, Source: e.Caller, Target: e.Callee}})
+	}
+	jsonResponse(w, result)
+}
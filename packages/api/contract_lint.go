@@ -0,0 +1,48 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+)
+
+type contractLintForm struct {
+	Code string `schema:"code"`
+}
+
+func (f *contractLintForm) Validate(r *http.Request) error {
+	if len(f.Code) == 0 {
+		return errUndefineval.Errorf("code")
+	}
+	return nil
+}
+
+type contractLintResult struct {
+	Warnings []string                 `json:"warnings"`
+	Findings []script.AnalysisFinding `json:"findings"`
+}
+
+// contractLintHandler returns the warnings and the findings of the static analysis of the source of the
+// contract before it's uploaded
+func contractLintHandler(w http.ResponseWriter, r *http.Request) {
+	form := &contractLintForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+	warnings := smart.LintContract(form.Code)
+	if warnings == nil {
+		warnings = []string{}
+	}
+	findings := script.Analyze(form.Code)
+	if findings == nil {
+		findings = []script.AnalysisFinding{}
+	}
+	jsonResponse(w, &contractLintResult{Warnings: warnings, Findings: findings})
+}
@@ -0,0 +1,189 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/gorilla/mux"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// the orders of the top contracts
+const (
+	statsByFuel        = "fuel"
+	statsByFuelP95     = "fuel_p95"
+	statsByInvocations = "invocations"
+	statsByFailed      = "failed"
+	statsByRows        = "rows"
+)
+
+type contractStatsForm struct {
+	Ecosystem int64 `schema:"ecosystem"` // the ecosystem of the callers, zero is all the ecosystems
+	Days      int   `schema:"days"`      // the last days including today, all the kept days by default
+}
+
+func (f *contractStatsForm) Validate(r *http.Request) error {
+	if f.Ecosystem < 0 {
+		return errUndefineval.Errorf("ecosystem")
+	}
+	if f.Days <= 0 || f.Days > conf.Config.ContractStatsDays {
+		f.Days = conf.Config.ContractStatsDays
+	}
+	return nil
+}
+
+// from returns the first day of the stats
+func (f *contractStatsForm) from(now time.Time) string {
+	return sqldb.StatsDay(now.AddDate(0, 0, 1-f.Days))
+}
+
+type contractsTopForm struct {
+	paginatorForm
+	contractStatsForm
+	By string `schema:"by"`
+}
+
+func (f *contractsTopForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	if len(f.By) == 0 {
+		f.By = statsByFuel
+	}
+	if statsValue(&sqldb.ContractStats{}, f.By) == nil {
+		return errContractStatsBy.Errorf(f.By)
+	}
+	return f.contractStatsForm.Validate(r)
+}
+
+type contractStatsItem struct {
+	sqldb.ContractStats
+	Invocations int64 `json:"invocations"`
+	FuelP95     int64 `json:"fuel_p95"`
+}
+
+type contractStatsResult struct {
+	Contract string              `json:"contract"`
+	From     string              `json:"from"`
+	Total    contractStatsItem   `json:"total"`
+	Days     []contractStatsItem `json:"days"`
+}
+
+type contractsTopResult struct {
+	From string              `json:"from"`
+	By   string              `json:"by"`
+	List []contractStatsItem `json:"list"`
+}
+
+func newContractStatsItem(s *sqldb.ContractStats) contractStatsItem {
+	return contractStatsItem{ContractStats: *s, Invocations: s.Invocations(), FuelP95: s.FuelP95()}
+}
+
+// statsValue returns the value of the order by or nil if the order is unknown
+func statsValue(s *sqldb.ContractStats, by string) func() int64 {
+	switch by {
+	case statsByFuel:
+		return func() int64 { return s.FuelTotal }
+	case statsByFuelP95:
+		return s.FuelP95
+	case statsByInvocations:
+		return s.Invocations
+	case statsByFailed:
+		return func() int64 { return s.Failed }
+	case statsByRows:
+		return func() int64 { return s.RowsWritten }
+	}
+	return nil
+}
+
+func getContractStatsHandler(w http.ResponseWriter, r *http.Request) {
+	form := &contractStatsForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	name := mux.Vars(r)["name"]
+	if !strings.HasPrefix(name, "@") {
+		name = fmt.Sprintf("@%d%s", getClient(r).EcosystemID, name)
+	}
+	from := form.from(time.Now())
+	list, err := sqldb.GetContractStats(name, form.Ecosystem, from)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "contract": name}).Error("getting contract stats")
+		errorResponse(w, err)
+		return
+	}
+
+	total := &sqldb.ContractStats{Ecosystem: form.Ecosystem, Contract: name}
+	days := make([]contractStatsItem, 0, len(list))
+	for i := range list {
+		total.Merge(&list[i])
+		days = append(days, newContractStatsItem(&list[i]))
+	}
+	jsonResponse(w, &contractStatsResult{Contract: name, From: from, Total: newContractStatsItem(total), Days: days})
+}
+
+func getContractsTopHandler(w http.ResponseWriter, r *http.Request) {
+	form := &contractsTopForm{paginatorForm: paginatorForm{defaultLimit: 10}}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	from := form.from(time.Now())
+	list, err := sqldb.GetContractStatsFrom(form.Ecosystem, from)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contract stats")
+		errorResponse(w, err)
+		return
+	}
+
+	totals := make(map[sqldb.ContractStatsKey]*sqldb.ContractStats)
+	for i := range list {
+		key := sqldb.ContractStatsKey{Ecosystem: list[i].Ecosystem, Contract: list[i].Contract}
+		total, ok := totals[key]
+		if !ok {
+			total = &sqldb.ContractStats{Ecosystem: key.Ecosystem, Contract: key.Contract}
+			totals[key] = total
+		}
+		total.Merge(&list[i])
+	}
+	items := make([]contractStatsItem, 0, len(totals))
+	for _, total := range totals {
+		items = append(items, newContractStatsItem(total))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		vi, vj := statsValue(&items[i].ContractStats, form.By)(), statsValue(&items[j].ContractStats, form.By)()
+		if vi != vj {
+			return vi > vj
+		}
+		if items[i].Contract != items[j].Contract {
+			return items[i].Contract < items[j].Contract
+		}
+		return items[i].Ecosystem < items[j].Ecosystem
+	})
+	if form.Offset < 0 || form.Offset >= len(items) {
+		items = items[:0]
+	} else {
+		items = items[form.Offset:]
+	}
+	if len(items) > form.Limit {
+		items = items[:form.Limit]
+	}
+	jsonResponse(w, &contractsTopResult{From: from, By: form.By, List: items})
+}
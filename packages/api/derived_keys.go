@@ -0,0 +1,78 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+type childKeysForm struct {
+	paginatorForm
+	identityForm
+}
+
+func (f *childKeysForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	return f.identityForm.Validate(r)
+}
+
+type childKeyResult struct {
+	KeyID           string `json:"key_id"`
+	Account         string `json:"account"`
+	PublicKey       string `json:"public_key"`
+	DerivationIndex int64  `json:"derivation_index"`
+}
+
+type childKeysResult struct {
+	Parent string           `json:"parent"`
+	List   []childKeyResult `json:"list"`
+}
+
+// getChildKeysHandler returns the keys which are registered as derived from the key, the key is
+// the id or the address
+func getChildKeysHandler(w http.ResponseWriter, r *http.Request) {
+	form := &childKeysForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	key := mux.Vars(r)["id"]
+	keyID := converter.AddressToID(key)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": key}).Error("converting key to address")
+		errorResponse(w, errInvalidWallet.Errorf(key))
+		return
+	}
+
+	keys, err := sqldb.GetChildKeys(form.Ecosystem, keyID, form.Limit, form.Offset)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "key_id": keyID, "ecosystem": form.Ecosystem}).Error("getting child keys")
+		errorResponse(w, err)
+		return
+	}
+	result := &childKeysResult{Parent: converter.AddressToString(keyID), List: make([]childKeyResult, 0, len(keys))}
+	for _, k := range keys {
+		result.List = append(result.List, childKeyResult{
+			KeyID:           converter.Int64ToStr(k.ID),
+			Account:         k.AccountID,
+			PublicKey:       crypto.PubToHex(k.PublicKey),
+			DerivationIndex: k.DerivationIndex,
+		})
+	}
+	jsonResponse(w, result)
+}
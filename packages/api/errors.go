@@ -14,6 +14,7 @@ import (
 var (
 	defaultStatus        = http.StatusBadRequest
 	ErrEcosystemNotFound = errors.New("Ecosystem not found")
+	errAPIKeyIP          = errType{"E_APIKEYIP", "IP address is not allowed for the api key", http.StatusForbidden}
 	errContract          = errType{"E_CONTRACT", "There is not %s contract", http.StatusNotFound}
 	errDBNil             = errType{"E_DBNIL", "DB is nil", defaultStatus}
 	errDeletedKey        = errType{"E_DELETEDKEY", "The key is deleted", http.StatusForbidden}
@@ -28,6 +29,10 @@ var (
 	errInvalidWallet     = errType{"E_INVALIDWALLET", "Wallet %s is not valid", http.StatusBadRequest}
 	errLimitForsign      = errType{"E_LIMITFORSIGN", "Length of forsign is too big (%d)", defaultStatus}
 	errLimitTxSize       = errType{"E_LIMITTXSIZE", "The size of tx is too big (%d)", defaultStatus}
+	errTxMalformed       = errType{"E_TXMALFORMED", "Transaction is malformed (%v)", defaultStatus}
+	errTxDepth           = errType{"E_TXDEPTH", "Transaction is nested too deep (%v)", defaultStatus}
+	errTxParams          = errType{"E_TXPARAMS", "Transaction has too many parameters (%v)", defaultStatus}
+	errTxParamSize       = errType{"E_TXPARAMSIZE", "The size of tx parameter is too big (%v)", defaultStatus}
 	errNotFound          = errType{"E_NOTFOUND", "Page not found", http.StatusNotFound}
 	errNotFoundRecord    = errType{"E_NOTFOUND", "Record not found", http.StatusNotFound}
 	errParamNotFound     = errType{"E_PARAMNOTFOUND", "Parameter %s has not been found", http.StatusNotFound}
@@ -55,6 +60,10 @@ var (
 	errBanned            = errType{"E_BANNED", "The key %d is banned till %s", http.StatusForbidden}
 	errCheckRole         = errType{"E_CHECKROLE", "Access denied", http.StatusForbidden}
 	errNewUser           = errType{"E_NEWUSER", "The block packing in progress, please wait", http.StatusUnauthorized}
+	errFeeTxType         = errType{"E_FEETXTYPE", "Transaction type %d is not supported", defaultStatus}
+	errFeePriority       = errType{"E_FEEPRIORITY", "Priority %s is unknown", defaultStatus}
+	errContractStatsBy   = errType{"E_STATSBY", "Order %s of the contract stats is unknown", defaultStatus}
+	errResourceMetric    = errType{"E_RESOURCEMETRIC", "Metric %s is unknown", defaultStatus}
 	errEcoNotOpen        = errType{"E_ECONOTOPEN", "The ecosystem (%d) is not open and cannot be registered address", http.StatusUnauthorized}
 )
 
@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type eventsBloomForm struct {
+	Ecosystem int64  `schema:"ecosystem"`
+	Contract  string `schema:"contract"`
+	Event     string `schema:"event"`
+	Address   string `schema:"address"`
+	FromBlock int64  `schema:"from_block"`
+	ToBlock   int64  `schema:"to_block"`
+	keyID     int64
+}
+
+func (f *eventsBloomForm) Validate(r *http.Request) error {
+	if f.Ecosystem < 0 {
+		return errUndefineval.Errorf("ecosystem")
+	}
+	if (len(f.Contract) > 0 || len(f.Event) > 0) && f.Ecosystem == 0 {
+		return errParamNotFound.Errorf("ecosystem")
+	}
+	if len(f.Event) > 0 && len(f.Contract) == 0 {
+		return errParamNotFound.Errorf("contract")
+	}
+	if len(f.Address) > 0 {
+		if f.keyID = converter.AddressToID(f.Address); f.keyID == 0 {
+			return errInvalidWallet.Errorf(f.Address)
+		}
+	}
+	if f.FromBlock < 1 {
+		f.FromBlock = 1
+	}
+	// the range is limited by the blocks which are scanned at once
+	if f.ToBlock <= 0 || f.ToBlock-f.FromBlock >= block.MaxBloomBlocks {
+		f.ToBlock = f.FromBlock + block.MaxBloomBlocks - 1
+	}
+	if f.ToBlock < f.FromBlock {
+		return errUndefineval.Errorf("to_block")
+	}
+	return nil
+}
+
+type eventsBloomResult struct {
+	FromBlock int64   `json:"from_block"`
+	ToBlock   int64   `json:"to_block"`
+	Blocks    []int64 `json:"blocks"`
+}
+
+// getEventsBloomHandler returns the blocks of the range whose blooms may have the events of the filter,
+// the events of the blocks are got by /events
+func getEventsBloomHandler(w http.ResponseWriter, r *http.Request) {
+	form := &eventsBloomForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	ids, err := block.ScanEventsBlooms(form.FromBlock, form.ToBlock, block.BloomFilter{
+		EcosystemID: form.Ecosystem,
+		Contract:    form.Contract,
+		Event:       form.Event,
+		KeyID:       form.keyID,
+	})
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("scanning events blooms")
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, &eventsBloomResult{FromBlock: form.FromBlock, ToBlock: form.ToBlock, Blocks: ids})
+}
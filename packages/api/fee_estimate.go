@@ -0,0 +1,87 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/service/fee"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type feeEstimateForm struct {
+	Type     int          `schema:"type"`
+	Priority fee.Priority `schema:"priority"`
+}
+
+func (f *feeEstimateForm) Validate(r *http.Request) error {
+	switch f.Type {
+	case types.SmartContractTxType, types.UtxoTxType, types.TransferSelfTxType:
+	default:
+		return errFeeTxType.Errorf(f.Type)
+	}
+	if len(f.Priority) == 0 {
+		f.Priority = fee.PriorityStandard
+	}
+	if _, err := f.Priority.Percentile(); err != nil {
+		return errFeePriority.Errorf(f.Priority)
+	}
+	return nil
+}
+
+func getFeeEstimateHandler(w http.ResponseWriter, r *http.Request) {
+	form := &feeEstimateForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	block := &sqldb.BlockChain{}
+	if _, err := block.GetMaxBlock(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		errorResponse(w, err)
+		return
+	}
+	list, err := sqldb.GetBlockFeeStats(form.Type, block.ID-sqldb.FeeStatsWindow)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block fee stats")
+		errorResponse(w, err)
+		return
+	}
+	blocks := make([][]int64, 0, len(list))
+	for i := range list {
+		prices, err := list[i].Prices()
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err, "block_id": list[i].BlockID}).Error("unmarshalling gas prices")
+			errorResponse(w, err)
+			return
+		}
+		blocks = append(blocks, prices)
+	}
+
+	estimate, err := fee.Estimate(blocks, form.Priority)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	expedite := decimal.NewFromInt(estimate.GasPrice).Shift(-consts.MoneyDigits)
+	pending, err := sqldb.GetPendingTxCount(form.Type, expedite.String())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending transactions count")
+		errorResponse(w, err)
+		return
+	}
+	estimate.EstimatedInclusionBlocks = fee.InclusionBlocks(pending, syspar.GetMaxTxCount())
+
+	jsonResponse(w, &estimate)
+}
@@ -0,0 +1,36 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/service/health"
+	log "github.com/sirupsen/logrus"
+)
+
+const healthTimeout = 3 * time.Second
+
+// getHealthHandler is the readiness probe, it responds with 503 if the node is unhealthy
+func getHealthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+	defer cancel()
+	report := health.Check(ctx, health.NodeSource(), health.FromConfig(conf.Config.Health), time.Now())
+	jsonResult, err := json.Marshal(report)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling health report to json")
+		errorResponse(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(report.Status.StatusCode())
+	w.Write(jsonResult)
+}
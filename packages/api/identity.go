@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+type identityForm struct {
+	Ecosystem int64 `schema:"ecosystem"`
+}
+
+func (f *identityForm) Validate(r *http.Request) error {
+	if f.Ecosystem < 0 {
+		return errUndefineval.Errorf("ecosystem")
+	}
+	if f.Ecosystem == 0 {
+		f.Ecosystem = consts.DefaultTokenEcosystem
+		if client := getClient(r); client != nil && client.EcosystemID > 0 {
+			f.Ecosystem = client.EcosystemID
+		}
+	}
+	return nil
+}
+
+// getIdentity returns the identity of the key in the ecosystem
+var getIdentity = func(ecosystem, keyID int64) (*sqldb.Identity, bool, error) {
+	identity := &sqldb.Identity{}
+	found, err := identity.Get(ecosystem, keyID)
+	return identity, found, err
+}
+
+type identityResult struct {
+	*sqldb.Identity
+	Account    string `json:"account"`
+	Verified   bool   `json:"verified"`
+	VerifiedBy string `json:"verified_by,omitempty"`
+}
+
+// getIdentityHandler returns the registered identity of the key, the key is the id or the address
+func getIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	form := &identityForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	key := mux.Vars(r)["key_id"]
+	keyID := converter.AddressToID(key)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": key}).Error("converting key to address")
+		errorResponse(w, errInvalidWallet.Errorf(key))
+		return
+	}
+
+	identity, found, err := getIdentity(form.Ecosystem, keyID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "key_id": keyID, "ecosystem": form.Ecosystem}).Error("getting identity")
+		errorResponse(w, err)
+		return
+	}
+	if !found {
+		errorResponse(w, errNotFoundRecord)
+		return
+	}
+
+	result := &identityResult{Identity: identity, Account: converter.AddressToString(keyID)}
+	if identity.VerifiedByKeyID != 0 {
+		result.Verified = true
+		result.VerifiedBy = converter.AddressToString(identity.VerifiedByKeyID)
+	}
+	jsonResponse(w, result)
+}
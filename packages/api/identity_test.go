@@ -0,0 +1,64 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/gorilla/mux"
+)
+
+func TestIdentityHandler(t *testing.T) {
+	const keyID, verifier = int64(-1744264011260937456), int64(42)
+	identities := map[[2]int64]*sqldb.Identity{
+		{1, keyID}: {ID: 1, KeyID: keyID, DisplayName: "alice", VerifiedByKeyID: verifier, Ecosystem: 1},
+		{2, keyID}: {ID: 2, KeyID: keyID, DisplayName: "alice2", Ecosystem: 2},
+	}
+	defer func(get func(int64, int64) (*sqldb.Identity, bool, error)) { getIdentity = get }(getIdentity)
+	getIdentity = func(ecosystem, keyID int64) (*sqldb.Identity, bool, error) {
+		identity, ok := identities[[2]int64{ecosystem, keyID}]
+		return identity, ok, nil
+	}
+
+	r := mux.NewRouter()
+	r.Use(loggerMiddleware)
+	r.HandleFunc("/identity/{key_id}", getIdentityHandler).Methods("GET")
+	get := func(path string, code int) *identityResult {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != code {
+			t.Fatalf("%s: expected %d, got %d %s", path, code, w.Code, w.Body.String())
+		}
+		result := &identityResult{Identity: &sqldb.Identity{}}
+		if code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return result
+	}
+
+	address := converter.AddressToString(keyID)
+	for _, key := range []string{converter.Int64ToStr(keyID), address} {
+		got := get("/identity/"+key, http.StatusOK)
+		if got.DisplayName != "alice" || got.Account != address || !got.Verified ||
+			got.VerifiedBy != converter.AddressToString(verifier) {
+			t.Errorf("wrong identity of %s %+v", key, got)
+		}
+	}
+	if got := get("/identity/"+address+"?ecosystem=2", http.StatusOK); got.DisplayName != "alice2" || got.Verified {
+		t.Errorf("wrong identity of the ecosystem %+v", got)
+	}
+	get("/identity/"+address+"?ecosystem=3", http.StatusNotFound)
+	get("/identity/wrong", http.StatusBadRequest)
+	get("/identity/"+address+"?ecosystem=-1", http.StatusBadRequest)
+}
@@ -102,7 +102,13 @@ func getListHandler(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, err)
 		return
 	}
-	q := sqldb.GetTableQuery(params["name"], client.EcosystemID)
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	q := sqldb.GetTableQuery(dbTx, params["name"], client.EcosystemID)
 
 	if len(form.Columns) > 0 {
 		q = q.Select("id," + form.Columns)
@@ -166,8 +172,13 @@ func getListWhereHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	//q := sqldb.GetTableQuery(params["name"], client.EcosystemID)
-	q := sqldb.GetTableListQuery(params["name"], client.EcosystemID)
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	q := sqldb.GetTableListQuery(dbTx, params["name"], client.EcosystemID)
 	if len(form.Columns) > 0 {
 		q = q.Select("id," + smart.PrepareColumns([]string{form.Columns}))
 	}
@@ -304,7 +315,13 @@ func getsumWhereHandler(w http.ResponseWriter, r *http.Request) {
 		//q = q.Where(where)
 	}
 
-	count, err := sqldb.NewDbTransaction(nil).GetSumColumnCount(table, form.Column, where)
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	count, err := dbTx.GetSumColumnCount(table, form.Column, where)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Errorf("selecting rows from table %s select %s where %s", table, smart.PrepareColumns([]string{form.Column}), where)
 		errorResponse(w, err)
@@ -313,7 +330,7 @@ func getsumWhereHandler(w http.ResponseWriter, r *http.Request) {
 
 	result := new(sumResult)
 	if count > 0 {
-		sum, err := sqldb.NewDbTransaction(nil).GetSumColumn(table, form.Column, where)
+		sum, err := dbTx.GetSumColumn(table, form.Column, where)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).
 				Errorf("selecting rows from table %s select %s where %s", table, smart.PrepareColumns([]string{form.Column}), where)
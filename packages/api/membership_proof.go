@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// getMembershipProofHandler returns the membership proof of the key signed by the node, the key is
+// the id or the address
+func getMembershipProofHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	params := mux.Vars(r)
+
+	ecosystem := converter.StrToInt64(params["id"])
+	if ecosystem <= 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": params["id"]}).Error("converting ecosystem id")
+		errorResponse(w, errEcosystem.Errorf(ecosystem))
+		return
+	}
+	keyID := converter.AddressToID(params["key_id"])
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": params["key_id"]}).Error("converting key to address")
+		errorResponse(w, errInvalidWallet.Errorf(params["key_id"]))
+		return
+	}
+
+	proof, err := node.SignMembershipProof(ecosystem, keyID, syspar.GetNodeSigner())
+	if err != nil {
+		if errors.Is(err, node.ErrNotMember) {
+			logger.WithFields(log.Fields{"type": consts.NotFound, "ecosystem": ecosystem, "key_id": keyID}).Debug("key isn't member of ecosystem")
+			errorResponse(w, errNotFound)
+			return
+		}
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": ecosystem, "key_id": keyID}).Error("signing membership proof")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, proof)
+}
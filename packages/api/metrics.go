@@ -10,6 +10,7 @@ import (
 	"runtime"
 
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/notificator"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
 
 	"github.com/gorilla/mux"
@@ -163,6 +164,11 @@ func memStatHandler(w http.ResponseWriter, _ *http.Request) {
 	jsonResponse(w, memMetric{Alloc: m.Alloc, Sys: m.Sys})
 }
 
+func notificationsStatHandler(w http.ResponseWriter, _ *http.Request) {
+	stats, _ := notificator.GetDispatcherStats()
+	jsonResponse(w, stats)
+}
+
 func banStatHandler(w http.ResponseWriter, _ *http.Request) {
 	nodes := syspar.GetNodes()
 	list := make([]banMetric, 0, len(nodes))
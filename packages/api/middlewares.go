@@ -6,12 +6,16 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"runtime/debug"
 	"time"
 
+	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
 	"github.com/IBAX-io/go-ibax/packages/statsd"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
@@ -34,6 +38,38 @@ func authRequire(next func(w http.ResponseWriter, r *http.Request)) func(w http.
 	}
 }
 
+// nodeOwnerRequire allows the request only for the key of this node and the service accounts
+// with the admin permission
+func nodeOwnerRequire(next func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return authRequire(func(w http.ResponseWriter, r *http.Request) {
+		client := getClient(r)
+		if (client.Permission == "" && client.KeyID == conf.Config.KeyID) || client.Permission.Allows(apikey.PermAdmin) {
+			next(w, r)
+			return
+		}
+
+		logger := getLogger(r)
+		logger.WithFields(log.Fields{"type": consts.AccessDenied}).Warning("request is allowed only for node owner")
+		errorResponse(w, errPermission)
+	})
+}
+
+// submitRequire allows sending the transactions for the wallet sessions and the service accounts
+// with the submit-tx permission
+func submitRequire(next func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+	return authRequire(func(w http.ResponseWriter, r *http.Request) {
+		client := getClient(r)
+		if client.Permission == "" || client.Permission.Allows(apikey.PermSubmit) {
+			next(w, r)
+			return
+		}
+
+		logger := getLogger(r)
+		logger.WithFields(log.Fields{"type": consts.AccessDenied, "permission": client.Permission}).Warning("api key isn't allowed to send transactions")
+		errorResponse(w, errPermission)
+	})
+}
+
 func loggerFromRequest(r *http.Request) *log.Entry {
 	return log.WithFields(log.Fields{
 		"headers":  r.Header,
@@ -89,10 +125,33 @@ func nodeStateMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// apiKeyStore keeps the keys of the service accounts
+var apiKeyStore = apikey.NodeStore()
+
+// apiKeyMiddleware authenticates the service accounts by the api keys
+func apiKeyMiddleware(next http.Handler) http.Handler {
+	return apikey.Middleware(apiKeyStore, denyAPIKey)(next)
+}
+
+func denyAPIKey(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, apikey.ErrInvalidKey):
+		errorResponse(w, errUnauthorized)
+	case errors.Is(err, apikey.ErrIPNotAllowed):
+		errorResponse(w, errAPIKeyIP)
+	default:
+		errorResponse(w, err)
+	}
+}
+
 func tokenMiddleware(next http.Handler) http.Handler {
 	const authHeader = "AUTHORIZATION"
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apikey.HasScheme(r.Header.Get(authHeader)) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		//token, err := RefreshToken(r.Header.Get(authHeader))
 		token, err := parseJWTToken(r.Header.Get(authHeader))
 		if err != nil {
@@ -110,7 +169,20 @@ func (m Mode) clientMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := getToken(r)
 		var client *Client
-		if token != nil { // get client from token
+		if key := apikey.FromRequest(r); key != nil {
+			name, err := m.EcosystemGetter.GetEcosystemName(key.EcosystemID)
+			if err != nil {
+				errorResponse(w, err)
+				return
+			}
+			client = &Client{
+				KeyID:         key.KeyID,
+				AccountID:     converter.AddressToString(key.KeyID),
+				EcosystemID:   key.EcosystemID,
+				EcosystemName: name,
+				Permission:    apikey.Permission(key.Permission),
+			}
+		} else if token != nil { // get client from token
 			var err error
 			if client, err = getClientFromToken(token, m.EcosystemGetter); err != nil {
 				errorResponse(w, err)
@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultConsumersWindow = 24 * time.Hour
+
+type topConsumersForm struct {
+	paginatorForm
+	Metric    string `schema:"metric"`
+	Window    string `schema:"window"`
+	Ecosystem int64  `schema:"ecosystem"`
+
+	window time.Duration
+}
+
+func (f *topConsumersForm) Validate(r *http.Request) error {
+	if err := f.paginatorForm.Validate(r); err != nil {
+		return err
+	}
+	if len(f.Metric) == 0 {
+		f.Metric = sqldb.ResourceMetricCPU
+	}
+	if !sqldb.IsResourceMetric(f.Metric) {
+		return errResourceMetric.Errorf(f.Metric)
+	}
+	f.window = defaultConsumersWindow
+	if len(f.Window) > 0 {
+		window, err := time.ParseDuration(f.Window)
+		if err != nil || window <= 0 {
+			return errUndefineval.Errorf("window")
+		}
+		f.window = window
+	}
+	if f.Ecosystem < 0 {
+		return errUndefineval.Errorf("ecosystem")
+	}
+	return nil
+}
+
+type topConsumersResult struct {
+	Metric string                   `json:"metric"`
+	From   time.Time                `json:"from"`
+	List   []sqldb.ResourceConsumer `json:"list"`
+}
+
+func getTopConsumersHandler(w http.ResponseWriter, r *http.Request) {
+	form := &topConsumersForm{paginatorForm: paginatorForm{defaultLimit: 10}}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	from := time.Now().Add(-form.window)
+	list, err := sqldb.GetTopConsumers(form.Metric, from, form.Ecosystem, form.Limit)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "metric": form.Metric}).Error("getting top consumers")
+		errorResponse(w, err)
+		return
+	}
+	if list == nil {
+		list = []sqldb.ResourceConsumer{}
+	}
+
+	jsonResponse(w, &topConsumersResult{Metric: form.Metric, From: from, List: list})
+}
@@ -38,7 +38,7 @@ func (m Mode) SetCommonRoutes(r Router) {
 	NoneMiddlewareRoutes(r.NewVersion("/api/v2"), m)
 
 	api := r.NewVersion("/api/v2")
-	api.Use(nodeStateMiddleware, tokenMiddleware, m.clientMiddleware)
+	api.Use(nodeStateMiddleware, apiKeyMiddleware, tokenMiddleware, m.clientMiddleware)
 
 	SetOtherCommonRoutes(api, m)
 	api.HandleFunc("/data/{id}/data/{hash}", getBinaryHandler).Methods("GET")
@@ -69,7 +69,7 @@ func (m Mode) SetCommonRoutes(r Router) {
 	api.HandleFunc("/content/menu/{name}", authRequire(getMenuHandler)).Methods("POST")
 	api.HandleFunc("/content", jsonContentHandler).Methods("POST")
 	api.HandleFunc("/login", m.loginHandler).Methods("POST")
-	api.HandleFunc("/sendTx", authRequire(m.sendTxHandler)).Methods("POST")
+	api.HandleFunc("/sendTx", submitRequire(m.sendTxHandler)).Methods("POST")
 	api.HandleFunc("/node/{name}", nodeContractHandler).Methods("POST")
 	api.HandleFunc("/txstatus", authRequire(getTxStatusHandler)).Methods("POST")
 	api.HandleFunc("/metrics/blocks", blocksCountHandler).Methods("GET")
@@ -78,6 +78,20 @@ func (m Mode) SetCommonRoutes(r Router) {
 	api.HandleFunc("/metrics/keys", keysCountHandler).Methods("GET")
 	api.HandleFunc("/metrics/mem", memStatHandler).Methods("GET")
 	api.HandleFunc("/metrics/ban", banStatHandler).Methods("GET")
+	api.HandleFunc("/metrics/notifications", notificationsStatHandler).Methods("GET")
+	api.HandleFunc("/metrics/slowstatements", nodeOwnerRequire(getSlowStatementsHandler)).Methods("GET")
+	api.HandleFunc("/audit", nodeOwnerRequire(getAuditHandler)).Methods("GET")
+	api.HandleFunc("/audit/verify", nodeOwnerRequire(getAuditVerifyHandler)).Methods("GET")
+	api.HandleFunc("/service-keys", nodeOwnerRequire(getServiceKeysHandler)).Methods("GET")
+	api.HandleFunc("/service-keys", nodeOwnerRequire(createServiceKeyHandler)).Methods("POST")
+	api.HandleFunc("/service-keys/{name}/revoke", nodeOwnerRequire(revokeServiceKeyHandler)).Methods("POST")
+	api.HandleFunc("/admin/debug/block-globals", nodeOwnerRequire(getBlockGlobalsHandler)).Methods("GET")
+	api.HandleFunc("/admin/debug/block-assembly", nodeOwnerRequire(m.getBlockAssemblyHandler)).Methods("GET")
+	api.HandleFunc("/admin/bans", nodeOwnerRequire(getBansHandler)).Methods("GET")
+	api.HandleFunc("/admin/bans/{key}/lift", nodeOwnerRequire(liftBanHandler)).Methods("POST")
+
+	apiV3 := r.NewVersion("/api/v3")
+	apiV3.Use(nodeStateMiddleware, apiKeyMiddleware, tokenMiddleware, m.clientMiddleware)
 
 }
 
@@ -90,16 +104,42 @@ func (m Mode) SetBlockchainRoutes(r Router) {
 	api.HandleFunc("/appparam/{appID}/{name}", authRequire(m.GetAppParamHandler)).Methods("GET")
 	api.HandleFunc("/appparams/{appID}", authRequire(m.getAppParamsHandler)).Methods("GET")
 	api.HandleFunc("/appcontent/{appID}", authRequire(m.getAppContentHandler)).Methods("GET")
+	api.HandleFunc("/bundle/export", authRequire(m.getBundleExportHandler)).Methods("GET")
+	api.HandleFunc("/bundle/plan", authRequire(m.postBundlePlanHandler)).Methods("POST")
 	api.HandleFunc("/history/{name}/{id}", authRequire(getHistoryHandler)).Methods("GET")
 	api.HandleFunc("/balance/{wallet}", m.getBalanceHandler).Methods("GET")
 	api.HandleFunc("/block/{id}", getBlockInfoHandler).Methods("GET")
+	api.HandleFunc("/block/{id}/attestation", getBlockAttestationHandler).Methods("GET")
+	api.HandleFunc("/checkpoint", getCheckpointHandler).Methods("GET")
+	api.HandleFunc("/checkpoint/{id}", getCheckpointHandler).Methods("GET")
 	api.HandleFunc("/maxblockid", getMaxBlockHandler).Methods("GET")
 	api.HandleFunc("/blocks", getBlocksTxInfoHandler).Methods("GET")
+	api.HandleFunc("/blocks/resources", getBlockResourcesHandler).Methods("GET")
 	api.HandleFunc("/detailed_blocks", getBlocksDetailedInfoHandler).Methods("GET")
 	api.HandleFunc("/ecosystemparams", authRequire(m.getEcosystemParamsHandler)).Methods("GET")
 	api.HandleFunc("/systemparams", authRequire(getPlatformParamsHandler)).Methods("GET")
 	api.HandleFunc("/ecosystemparam/{name}", authRequire(m.getEcosystemParamHandler)).Methods("GET")
 	api.HandleFunc("/ecosystemname", getEcosystemNameHandler).Methods("GET")
+	api.HandleFunc("/contracts/top", getContractsTopHandler).Methods("GET")
+	api.HandleFunc("/contracts/{name}/stats", getContractStatsHandler).Methods("GET")
+
+	apiV3 := r.GetAPIVersion("/api/v3")
+	apiV3.HandleFunc("/fee-estimate", getFeeEstimateHandler).Methods("GET")
+	apiV3.HandleFunc("/contracts/top-consumers", getTopConsumersHandler).Methods("GET")
+	apiV3.HandleFunc("/blocks/{id}/state-diff", getBlockStateDiffHandler).Methods("GET")
+	apiV3.HandleFunc("/blocks/{id}/state-proof", getStateProofHandler).Methods("GET")
+	apiV3.HandleFunc("/identity/{key_id}", getIdentityHandler).Methods("GET")
+	apiV3.HandleFunc("/keys/{id}/children", getChildKeysHandler).Methods("GET")
+	apiV3.HandleFunc("/ecosystems/{id}/membership-proof/{key_id}", getMembershipProofHandler).Methods("GET")
+	apiV3.HandleFunc("/ecosystems/{id}/contract-graph", getContractGraphHandler).Methods("GET")
+	apiV3.HandleFunc("/tx/{hash}/caused-delays", getCausedDelaysHandler).Methods("GET")
+	apiV3.HandleFunc("/tx/{hash}/receipt", getTxReceiptHandler).Methods("GET")
+	apiV3.HandleFunc("/syspar/snapshot", getSysparSnapshotHandler).Methods("GET")
+	apiV3.HandleFunc("/ws/tx/{hash}", txWatchHandler).Methods("GET")
+	apiV3.HandleFunc("/time_locks/{key_id}", getTimeLocksHandler).Methods("GET")
+	apiV3.HandleFunc("/contracts/lint", contractLintHandler).Methods("POST")
+	apiV3.HandleFunc("/events", getContractEventsHandler).Methods("GET")
+	apiV3.HandleFunc("/events/bloom", getEventsBloomHandler).Methods("GET")
 }
 
 func NoneMiddlewareRoutes(api *mux.Router, m Mode) {
@@ -124,6 +164,10 @@ func NewRouter(m Mode) Router {
 	r := mux.NewRouter()
 	r.StrictSlash(true)
 	r.Use(loggerMiddleware, recoverMiddleware, statsdMiddleware)
+	// the probe works while the node is paused so it isn't behind nodeStateMiddleware
+	r.HandleFunc("/healthz", getHealthHandler).Methods("GET")
+	// the sync status is also needed while the node is updating the blockchain
+	r.HandleFunc("/api/v3/node/sync-status", getSyncStatusHandler).Methods("GET")
 
 	api := Router{
 		main:        r,
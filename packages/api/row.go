@@ -48,8 +48,6 @@ func getRowHandler(w http.ResponseWriter, r *http.Request) {
 	client := getClient(r)
 	logger := getLogger(r)
 
-	q := sqldb.GetDB(nil).Limit(1)
-
 	var (
 		err   error
 		table string
@@ -59,6 +57,13 @@ func getRowHandler(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, err)
 		return
 	}
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	q := sqldb.GetDB(dbTx).Limit(1)
 	col := `id`
 	if len(params["column"]) > 0 {
 		col = converter.Sanitize(params["column"], `-`)
@@ -47,10 +47,16 @@ func getSectionsHandler(w http.ResponseWriter, r *http.Request) {
 	logger := getLogger(r)
 
 	table := "1_sections"
-	q := sqldb.GetDB(nil).Table(table).Where("ecosystem = ? AND status > 0", client.EcosystemID).Order("id ASC")
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	q := sqldb.GetDB(dbTx).Table(table).Where("ecosystem = ? AND status > 0", client.EcosystemID).Order("id ASC")
 
 	result := new(listResult)
-	err := q.Count(&result.Count).Error
+	err = q.Count(&result.Count).Error
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting table records count")
 		errorResponse(w, errTableNotFound.Errorf(table))
@@ -7,12 +7,12 @@ package api
 
 import (
 	"encoding/hex"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 
-	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 
 	log "github.com/sirupsen/logrus"
@@ -90,13 +90,13 @@ func txHandlerBatches(r *http.Request, m Mode, mtx map[string][]byte) ([]string,
 	logger := getLogger(r)
 	var txData [][]byte
 	for _, datum := range mtx {
+		if err := transaction.CheckIngress(datum); err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err, "size": len(datum)}).Error("transaction is rejected")
+			transaction.BadTxForBan(client.KeyID, transaction.BanReasonIngress)
+			return nil, ingressError(err, len(datum))
+		}
 		txData = append(txData, datum)
 	}
-	if int64(len(txData)) > syspar.GetMaxTxSize() {
-		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_size": syspar.GetMaxTxSize(), "size": len(txData)}).Error("transaction size exceeds max size")
-		transaction.BadTxForBan(client.KeyID)
-		return nil, errLimitTxSize.Errorf(len(txData))
-	}
 
 	hash, err := m.ClientTxProcessor.ProcessClientTxBatches(txData, client.KeyID, logger)
 	if err != nil {
@@ -105,3 +105,17 @@ func txHandlerBatches(r *http.Request, m Mode, mtx map[string][]byte) ([]string,
 
 	return hash, nil
 }
+
+func ingressError(err error, size int) errType {
+	switch {
+	case errors.Is(err, transaction.ErrTxSize):
+		return errLimitTxSize.Errorf(size)
+	case errors.Is(err, transaction.ErrTxDepth):
+		return errTxDepth.Errorf(err)
+	case errors.Is(err, transaction.ErrTxParams):
+		return errTxParams.Errorf(err)
+	case errors.Is(err, transaction.ErrTxParamSize):
+		return errTxParamSize.Errorf(err)
+	}
+	return errTxMalformed.Errorf(err)
+}
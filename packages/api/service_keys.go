@@ -0,0 +1,97 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/gorilla/mux"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type serviceKeyForm struct {
+	Name       string `schema:"name"`
+	Permission string `schema:"permission"`
+	AllowedIPs string `schema:"allowed_ips"` // comma separated ips and cidrs, empty allows all
+}
+
+func (f *serviceKeyForm) Validate(r *http.Request) error {
+	f.Name = strings.TrimSpace(f.Name)
+	if len(f.Name) == 0 {
+		return errUndefineval.Errorf("name")
+	}
+	if !apikey.Permission(f.Permission).Valid() {
+		return errUndefineval.Errorf("permission")
+	}
+	if _, err := apikey.ParseIPs(f.AllowedIPs); err != nil {
+		return errUndefineval.Errorf("allowed_ips")
+	}
+	return nil
+}
+
+type serviceKeyResult struct {
+	*sqldb.ServiceKey
+	Secret string `json:"secret"` // it's returned only once
+}
+
+type serviceKeysResult struct {
+	List []sqldb.ServiceKey `json:"list"`
+}
+
+func getServiceKeysHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	list, err := sqldb.GetServiceKeys()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting service keys")
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, &serviceKeysResult{List: list})
+}
+
+// createServiceKeyHandler creates the key which acts as the account of the caller
+func createServiceKeyHandler(w http.ResponseWriter, r *http.Request) {
+	form := &serviceKeyForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	client := getClient(r)
+	logger := getLogger(r)
+	secret, key, err := apikey.Create(form.Name, apikey.Permission(form.Permission), form.AllowedIPs, client.KeyID, client.EcosystemID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "name": form.Name}).Error("creating service key")
+		errorResponse(w, err)
+		return
+	}
+	logger.WithFields(log.Fields{"name": key.Name, "permission": key.Permission}).Info("service key is created")
+	jsonResponse(w, &serviceKeyResult{ServiceKey: key, Secret: secret})
+}
+
+func revokeServiceKeyHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	name := mux.Vars(r)["name"]
+	ok, err := apikey.Revoke(name, getClient(r).KeyID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "name": name}).Error("revoking service key")
+		errorResponse(w, err)
+		return
+	}
+	if !ok {
+		errorResponse(w, errNotFoundRecord)
+		return
+	}
+	logger.WithFields(log.Fields{"name": name}).Info("service key is revoked")
+	jsonResponse(w, &struct {
+		Revoked bool `json:"revoked"`
+	}{true})
+}
@@ -0,0 +1,105 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/gorilla/mux"
+)
+
+// testKeyStore emulates service_keys, the revoked keys are removed
+type testKeyStore map[string]*sqldb.ServiceKey
+
+func (s testKeyStore) GetByHash(hash []byte) (*sqldb.ServiceKey, error) {
+	for _, key := range s {
+		if string(key.KeyHash) == string(hash) {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s testKeyStore) Touch(string, time.Time) error { return nil }
+
+type testEcosystems struct {
+	types.EcosystemGetter
+}
+
+func (testEcosystems) GetEcosystemName(int64) (string, error) { return "platform", nil }
+
+func TestServiceKeyRoutes(t *testing.T) {
+	defer func(s apikey.Store, keyID int64) { apiKeyStore, conf.Config.KeyID = s, keyID }(apiKeyStore, conf.Config.KeyID)
+	conf.Config.KeyID = 100
+
+	store := testKeyStore{}
+	apiKeyStore = store
+	secrets := make(map[apikey.Permission]string)
+	for _, perm := range []apikey.Permission{apikey.PermRead, apikey.PermSubmit, apikey.PermAdmin} {
+		secret, err := apikey.NewSecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets[perm] = secret
+		store[string(perm)] = &sqldb.ServiceKey{Name: string(perm), KeyHash: apikey.Hash(secret),
+			Permission: string(perm), KeyID: 7, EcosystemID: 1}
+	}
+	restricted, _ := apikey.NewSecret()
+	store["restricted"] = &sqldb.ServiceKey{Name: "restricted", KeyHash: apikey.Hash(restricted),
+		Permission: string(apikey.PermAdmin), AllowedIPs: "10.0.0.0/8", KeyID: 7, EcosystemID: 1}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { jsonResponse(w, getClient(r).AccountID) }
+	r := mux.NewRouter()
+	r.Use(loggerMiddleware, apiKeyMiddleware, tokenMiddleware, Mode{EcosystemGetter: testEcosystems{}}.clientMiddleware)
+	r.HandleFunc("/read", authRequire(ok))
+	r.HandleFunc("/submit", submitRequire(ok))
+	r.HandleFunc("/admin", nodeOwnerRequire(ok))
+
+	check := func(secret, remote, path string, code int) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote
+		if len(secret) > 0 {
+			req.Header.Set("Authorization", apikey.Scheme+secret)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Errorf("%s from %s: expected %d, got %d %s", path, remote, code, w.Code, w.Body.String())
+		}
+	}
+
+	const remote = "10.1.2.3:5000"
+	for _, c := range []struct {
+		perm                apikey.Permission
+		read, submit, admin int
+	}{
+		{apikey.PermRead, http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized},
+		{apikey.PermSubmit, http.StatusOK, http.StatusOK, http.StatusUnauthorized},
+		{apikey.PermAdmin, http.StatusOK, http.StatusOK, http.StatusOK},
+	} {
+		check(secrets[c.perm], remote, "/read", c.read)
+		check(secrets[c.perm], remote, "/submit", c.submit)
+		check(secrets[c.perm], remote, "/admin", c.admin)
+	}
+	check("", remote, "/read", http.StatusUnauthorized)
+	check("ibax_unknown", remote, "/read", http.StatusUnauthorized)
+
+	// the ip allow-list
+	check(restricted, remote, "/admin", http.StatusOK)
+	check(restricted, "192.168.0.1:5000", "/read", http.StatusForbidden)
+
+	// the revoked key is rejected by the next request
+	delete(store, string(apikey.PermSubmit))
+	check(secrets[apikey.PermSubmit], remote, "/read", http.StatusUnauthorized)
+}
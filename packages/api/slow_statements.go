@@ -0,0 +1,23 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+type slowStatementsResult struct {
+	Count int                   `json:"count"`
+	List  []sqldb.SlowStatement `json:"list"`
+}
+
+// getSlowStatementsHandler returns the recent slow statements of the block transactions
+func getSlowStatementsHandler(w http.ResponseWriter, r *http.Request) {
+	list := sqldb.GetSlowStatements()
+	jsonResponse(w, &slowStatementsResult{Count: len(list), List: list})
+}
@@ -0,0 +1,46 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// getStateProofHandler returns the merkle proof of the row which is changed by the block for its state root
+func getStateProofHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	blockID := converter.StrToInt64(mux.Vars(r)["id"])
+	table, key := r.FormValue("table"), r.FormValue("key")
+	if len(table) == 0 {
+		errorResponse(w, errParamNotFound.Errorf("table"))
+		return
+	}
+	if len(key) == 0 {
+		errorResponse(w, errParamNotFound.Errorf("key"))
+		return
+	}
+
+	proof, err := block.GetStateProof(blockID, table, key)
+	if err != nil {
+		if errors.Is(err, block.ErrStateProof) {
+			logger.WithFields(log.Fields{"type": consts.NotFound, "id": blockID, "table": table, "key": key, "error": err}).Debug("state proof not found")
+			errorResponse(w, errNotFound)
+			return
+		}
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "id": blockID}).Error("getting state proof")
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, proof)
+}
@@ -0,0 +1,26 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// getSyncStatusHandler returns the last local block and the last known blocks of the peers
+func getSyncStatusHandler(w http.ResponseWriter, r *http.Request) {
+	status, err := node.GetSyncStatus()
+	if err != nil {
+		getLogger(r).WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting sync status")
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, status)
+}
@@ -0,0 +1,26 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// getSysparSnapshotHandler returns the platform parameters at the last block signed by the node
+func getSysparSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := syspar.SignSnapshot(syspar.GetNodeSigner())
+	if err != nil {
+		getLogger(r).WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("signing syspar snapshot")
+		errorResponse(w, err)
+		return
+	}
+	jsonResponse(w, snapshot)
+}
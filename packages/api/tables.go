@@ -39,14 +39,21 @@ func getTablesHandler(w http.ResponseWriter, r *http.Request) {
 	table := &sqldb.Table{}
 	table.SetTablePrefix(prefix)
 
-	count, err := table.Count()
+	dbTx, err := readTransaction(w)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	defer dbTx.Rollback()
+	var count int64
+	err = sqldb.GetDB(dbTx).Table(table.TableName()).Where("ecosystem = ?", client.EcosystemID).Count(&count).Error
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting records count from tables")
 		errorResponse(w, err)
 		return
 	}
 
-	rows, err := sqldb.GetDB(nil).Table(table.TableName()).Where("ecosystem = ?", client.EcosystemID).Offset(form.Offset).Limit(form.Limit).Rows()
+	rows, err := sqldb.GetDB(dbTx).Table(table.TableName()).Where("ecosystem = ?", client.EcosystemID).Offset(form.Offset).Limit(form.Limit).Rows()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("Getting rows from table")
 		errorResponse(w, err)
@@ -65,7 +72,7 @@ func getTablesHandler(w http.ResponseWriter, r *http.Request) {
 		List:  make([]tableInfo, len(list)),
 	}
 	for i, item := range list {
-		err = sqldb.GetTableQuery(item["name"], client.EcosystemID).Count(&count).Error
+		err = sqldb.GetTableQuery(dbTx, item["name"], client.EcosystemID).Count(&count).Error
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting count from table")
 			errorResponse(w, err)
@@ -0,0 +1,75 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/gorilla/mux"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+type timeLockResult struct {
+	sqldb.TimeLock
+	Sender     string          `json:"sender"`
+	Recipient  string          `json:"recipient"`
+	Vested     decimal.Decimal `json:"vested"`
+	Releasable decimal.Decimal `json:"releasable"`
+}
+
+type timeLocksResult struct {
+	BlockID int64            `json:"block_id"`
+	List    []timeLockResult `json:"list"`
+}
+
+// getTimeLocksHandler returns the time locks of the recipient, the vested and releasable amounts
+// are computed at the last block
+func getTimeLocksHandler(w http.ResponseWriter, r *http.Request) {
+	form := &identityForm{}
+	if err := parseForm(r, form); err != nil {
+		errorResponse(w, err, http.StatusBadRequest)
+		return
+	}
+
+	logger := getLogger(r)
+	key := mux.Vars(r)["key_id"]
+	keyID := converter.AddressToID(key)
+	if keyID == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "value": key}).Error("converting key to address")
+		errorResponse(w, errInvalidWallet.Errorf(key))
+		return
+	}
+
+	infoBlock := &sqldb.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		errorResponse(w, err)
+		return
+	}
+	locks, err := sqldb.GetTimeLocks(form.Ecosystem, keyID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "key_id": keyID, "ecosystem": form.Ecosystem}).Error("getting time locks")
+		errorResponse(w, err)
+		return
+	}
+
+	result := &timeLocksResult{BlockID: infoBlock.BlockID, List: make([]timeLockResult, 0, len(locks))}
+	for _, lock := range locks {
+		result.List = append(result.List, timeLockResult{
+			TimeLock:   lock,
+			Sender:     converter.AddressToString(lock.SenderID),
+			Recipient:  converter.AddressToString(lock.RecipientID),
+			Vested:     lock.Vested(infoBlock.Time, infoBlock.BlockID),
+			Releasable: lock.Releasable(infoBlock.Time, infoBlock.BlockID),
+		})
+	}
+	jsonResponse(w, result)
+}
@@ -0,0 +1,69 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+type txReceiptResult struct {
+	Hash         string `json:"hash"`
+	BlockID      int64  `json:"block_id"`
+	EcosystemID  int64  `json:"ecosystem_id"`
+	ContractName string `json:"contract_name"`
+	Fuel         int64  `json:"fuel"`
+	RowsTouched  int64  `json:"rows_touched"`
+	Events       int64  `json:"events"`
+	Status       int64  `json:"status"`
+}
+
+// getTxReceipt returns the receipt of the transaction by its hash
+var getTxReceipt = func(hash []byte) (*sqldb.TxReceipt, bool, error) {
+	receipt := &sqldb.TxReceipt{}
+	found, err := receipt.GetByHash(nil, hash)
+	return receipt, found, err
+}
+
+// getTxReceiptHandler returns the execution receipt of the played transaction, so the cost of the
+// transaction is known without its replay
+func getTxReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	params := mux.Vars(r)
+
+	hash, err := hex.DecodeString(params["hash"])
+	if err != nil || len(hash) == 0 {
+		logger.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding tx hash from hex")
+		errorResponse(w, errHashWrong)
+		return
+	}
+	receipt, found, err := getTxReceipt(hash)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting tx receipt")
+		errorResponse(w, err)
+		return
+	}
+	if !found {
+		errorResponse(w, errHashNotFound.Errorf(params["hash"]))
+		return
+	}
+	jsonResponse(w, &txReceiptResult{
+		Hash:         hex.EncodeToString(receipt.TxHash),
+		BlockID:      receipt.BlockID,
+		EcosystemID:  receipt.EcosystemID,
+		ContractName: receipt.ContractName,
+		Fuel:         receipt.Fuel,
+		RowsTouched:  receipt.RowsTouched,
+		Events:       receipt.Events,
+		Status:       receipt.Status,
+	})
+}
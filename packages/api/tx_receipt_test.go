@@ -0,0 +1,81 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/pbgo"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/gorilla/mux"
+)
+
+func TestTxReceiptHandler(t *testing.T) {
+	receipts := map[string]*sqldb.TxReceipt{
+		"abcd": {TxHash: []byte{0xab, 0xcd}, BlockID: 9, EcosystemID: 2, ContractName: "@2Transfer", Fuel: 120,
+			RowsTouched: 3, Events: 2, Status: int64(pbgo.TxInvokeStatusCode_SUCCESS)},
+		"01": {TxHash: []byte{0x01}, BlockID: 9, EcosystemID: 1, ContractName: "@1Fail", Fuel: 40,
+			RowsTouched: 1, Status: int64(pbgo.TxInvokeStatusCode_PENALTY)},
+	}
+	defer func(get func([]byte) (*sqldb.TxReceipt, bool, error)) { getTxReceipt = get }(getTxReceipt)
+	getTxReceipt = func(hash []byte) (*sqldb.TxReceipt, bool, error) {
+		if hex.EncodeToString(hash) == "ffff" {
+			return nil, false, errors.New("db is down")
+		}
+		receipt, ok := receipts[hex.EncodeToString(hash)]
+		return receipt, ok, nil
+	}
+
+	r := mux.NewRouter()
+	r.Use(loggerMiddleware)
+	r.HandleFunc("/tx/{hash}/receipt", getTxReceiptHandler).Methods("GET")
+	get := func(hash string, code int) []byte {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tx/"+hash+"/receipt", nil))
+		if w.Code != code {
+			t.Fatalf("%s: expected %d, got %d %s", hash, code, w.Code, w.Body.String())
+		}
+		return w.Body.Bytes()
+	}
+
+	for hash, want := range map[string]txReceiptResult{
+		"abcd": {Hash: "abcd", BlockID: 9, EcosystemID: 2, ContractName: "@2Transfer", Fuel: 120, RowsTouched: 3,
+			Events: 2, Status: int64(pbgo.TxInvokeStatusCode_SUCCESS)},
+		"01": {Hash: "01", BlockID: 9, EcosystemID: 1, ContractName: "@1Fail", Fuel: 40, RowsTouched: 1,
+			Status: int64(pbgo.TxInvokeStatusCode_PENALTY)},
+	} {
+		var got txReceiptResult
+		if err := json.Unmarshal(get(hash, http.StatusOK), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", hash, want, got)
+		}
+	}
+
+	for hash, want := range map[string]struct {
+		code int
+		err  string
+	}{
+		"0202": {http.StatusBadRequest, "E_HASHNOTFOUND"},
+		"xyz":  {http.StatusBadRequest, "E_HASHWRONG"},
+		"ffff": {http.StatusBadRequest, "E_SERVER"},
+	} {
+		var got errType
+		if err := json.Unmarshal(get(hash, want.code), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Err != want.err {
+			t.Errorf("%s: expected %s, got %s", hash, want.err, got.Err)
+		}
+	}
+}
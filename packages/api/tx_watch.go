@@ -0,0 +1,156 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+)
+
+// The statuses of the messages of the transaction websocket
+const (
+	txWatchPending  = "pending"
+	txWatchIncluded = "included"
+	txWatchFailed   = "failed"
+	txWatchTimeout  = "timeout"
+
+	defaultMaxTxWatch = 300 * time.Second
+)
+
+// PendingTxWatcher keeps the websockets which wait for the changes of the statuses of the transactions.
+// The statuses are checked again after every committed block, the transaction can fail without the block
+type PendingTxWatcher struct {
+	mutex    sync.Mutex
+	once     sync.Once
+	watchers map[string]map[chan struct{}]bool
+}
+
+var (
+	txWatcher = &PendingTxWatcher{watchers: make(map[string]map[chan struct{}]bool)}
+	// txWatchStatus returns the status of the watched transaction
+	txWatchStatus = getTxStatus
+)
+
+// Watch registers the watcher of the hash, the channel is signaled after the committed blocks
+// until stop is called
+func (w *PendingTxWatcher) Watch(hash string) (signal chan struct{}, stop func()) {
+	w.once.Do(func() {
+		block.SubscribeBlockCommitted("api.tx_watch", w.notify)
+	})
+	signal = make(chan struct{}, 1)
+	w.mutex.Lock()
+	if w.watchers[hash] == nil {
+		w.watchers[hash] = make(map[chan struct{}]bool)
+	}
+	w.watchers[hash][signal] = true
+	w.mutex.Unlock()
+	return signal, func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		delete(w.watchers[hash], signal)
+		if len(w.watchers[hash]) == 0 {
+			delete(w.watchers, hash)
+		}
+	}
+}
+
+func (w *PendingTxWatcher) notify(*block.BlockCommittedEvent) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, list := range w.watchers {
+		for signal := range list {
+			select {
+			case signal <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+type txWatchMessage struct {
+	Hash   string `json:"hash"`
+	Status string `json:"status"`
+	*txstatusResult
+}
+
+func newTxWatchMessage(hash string, status *txstatusResult) *txWatchMessage {
+	msg := &txWatchMessage{Hash: hash, Status: txWatchPending, txstatusResult: status}
+	switch {
+	case status.Message != nil:
+		msg.Status = txWatchFailed
+	case len(status.BlockID) > 0:
+		msg.Status = txWatchIncluded
+	}
+	return msg
+}
+
+// txWatchHandler sends the status of the transaction over the websocket, then it sends the changed
+// status or the timeout after MaxTxWatchSeconds and closes the connection
+func txWatchHandler(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+	hash := strings.ToLower(mux.Vars(r)["hash"])
+	signal, stop := txWatcher.Watch(hash)
+	status, err := txWatchStatus(r, hash)
+	if err != nil {
+		stop()
+		errorResponse(w, err)
+		return
+	}
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer stop()
+		defer ws.Close()
+		msg := newTxWatchMessage(hash, status)
+		if err := websocket.JSON.Send(ws, msg); err != nil || msg.Status != txWatchPending {
+			return
+		}
+		closed := make(chan struct{})
+		go func() {
+			// the client doesn't send the messages, the read fails when the connection is closed
+			var buf [64]byte
+			for {
+				if _, err := ws.Read(buf[:]); err != nil {
+					close(closed)
+					return
+				}
+			}
+		}()
+		wait := defaultMaxTxWatch
+		if conf.Config.MaxTxWatchSeconds > 0 {
+			wait = time.Duration(conf.Config.MaxTxWatchSeconds) * time.Second
+		}
+		timeout := time.NewTimer(wait)
+		defer timeout.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-timeout.C:
+				websocket.JSON.Send(ws, &txWatchMessage{Hash: hash, Status: txWatchTimeout, txstatusResult: msg.txstatusResult})
+				return
+			case <-signal:
+				status, err := txWatchStatus(r, hash)
+				if err != nil {
+					logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "hash": hash}).Error("getting watched transaction status")
+					return
+				}
+				if next := newTxWatchMessage(hash, status); next.Status != msg.Status {
+					websocket.JSON.Send(ws, next)
+					return
+				}
+			}
+		}
+	}}.ServeHTTP(w, r)
+}
@@ -0,0 +1,83 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/websocket"
+)
+
+func TestTxWatch(t *testing.T) {
+	var included atomic.Bool
+	txWatchStatus = func(r *http.Request, hash string) (*txstatusResult, error) {
+		if hash == "00" {
+			return nil, errHashNotFound.Errorf(hash)
+		}
+		if included.Load() {
+			return &txstatusResult{BlockID: "10"}, nil
+		}
+		return &txstatusResult{}, nil
+	}
+	defer func() { txWatchStatus = getTxStatus }()
+	defer func(seconds int) { conf.Config.MaxTxWatchSeconds = seconds }(conf.Config.MaxTxWatchSeconds)
+	conf.Config.MaxTxWatchSeconds = 1
+
+	router := mux.NewRouter()
+	router.HandleFunc("/ws/tx/{hash}", txWatchHandler).Methods("GET")
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/tx/"
+
+	receive := func(ws *websocket.Conn, status string) {
+		t.Helper()
+		msg := &txWatchMessage{txstatusResult: &txstatusResult{}}
+		ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if err := websocket.JSON.Receive(ws, msg); err != nil {
+			t.Fatalf("receiving %s: %v", status, err)
+		}
+		if msg.Status != status {
+			t.Fatalf("status is %s, want %s", msg.Status, status)
+		}
+	}
+
+	if _, err := websocket.Dial(url+"00", "", srv.URL); err == nil {
+		t.Error("unknown transaction is watched")
+	}
+
+	ws, err := websocket.Dial(url+"ab", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive(ws, txWatchPending)
+	included.Store(true)
+	txWatcher.notify(&block.BlockCommittedEvent{BlockID: 10})
+	receive(ws, txWatchIncluded)
+	ws.Close()
+
+	// the included transaction is sent once
+	if ws, err = websocket.Dial(url+"ab", "", srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	receive(ws, txWatchIncluded)
+	ws.Close()
+
+	included.Store(false)
+	if ws, err = websocket.Dial(url+"cd", "", srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	receive(ws, txWatchPending)
+	receive(ws, txWatchTimeout)
+	ws.Close()
+}
@@ -0,0 +1,89 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+type testAudit struct {
+	entry *sqldb.AuditLog
+	dbTx  *sqldb.DbTransaction
+}
+
+func stubAudit(t *testing.T) *[]testAudit {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	prev := createAudit
+	t.Cleanup(func() { createAudit = prev })
+	var audits []testAudit
+	createAudit = func(a *sqldb.AuditLog, dbTx *sqldb.DbTransaction) error {
+		audits = append(audits, testAudit{entry: a, dbTx: dbTx})
+		return nil
+	}
+	return &audits
+}
+
+func stopNetworkTx(dbTx *sqldb.DbTransaction) *transaction.Transaction {
+	return &transaction.Transaction{
+		InToCxt: &transaction.InToCxt{DbTransaction: dbTx},
+		OutCtx:  &transaction.OutCtx{},
+		Inner: &transaction.StopNetworkParser{
+			Data:    &types.StopNetwork{KeyID: 7},
+			TxHash:  []byte("hash"),
+			Payload: []byte("payload"),
+		},
+	}
+}
+
+// TestAuditSysUpdate checks that the changed parameters are audited after the commit of the block
+func TestAuditSysUpdate(t *testing.T) {
+	audits := stubAudit(t)
+	b := &Block{BlockData: &types.BlockData{Header: &types.BlockHeader{BlockId: 5}}}
+	tx := stopNetworkTx(nil)
+	tx.SysUpdate = true
+	b.auditTx(tx)
+	if !b.SysUpdate || tx.SysUpdate || len(b.AuditLogs) != 1 || len(*audits) != 0 {
+		t.Fatalf("sys update isn't collected: %v %d %d", b.SysUpdate, len(b.AuditLogs), len(*audits))
+	}
+	b.writeAuditLogs()
+	if len(*audits) != 1 || len(b.AuditLogs) != 0 {
+		t.Fatalf("audit logs aren't written: %d", len(*audits))
+	}
+	a := (*audits)[0]
+	if a.dbTx != nil || a.entry.Action != sqldb.AuditSysUpdate || a.entry.KeyID != 7 || a.entry.BlockID != 5 || string(a.entry.TxHash) != "hash" {
+		t.Errorf("wrong sys update audit %+v", a.entry)
+	}
+}
+
+// TestAuditStopNetwork checks that the stop network entry is written out of the block transaction
+// which is rolled back
+func TestAuditStopNetwork(t *testing.T) {
+	audits := stubAudit(t)
+	defer node.PauseNodeActivity(node.NoPause)
+	b := &Block{BlockData: &types.BlockData{Header: &types.BlockHeader{BlockId: 6}}}
+	b.stopNetwork(stopNetworkTx(&sqldb.DbTransaction{}))
+	if node.NodePauseType() != node.PauseTypeStopingNetwork {
+		t.Error("node isn't paused")
+	}
+	if len(*audits) != 0 {
+		t.Fatalf("audit entry is written before the rollback")
+	}
+	b.writeStopAudit()
+	b.writeStopAudit()
+	if len(*audits) != 1 {
+		t.Fatalf("expected one audit entry, got %d", len(*audits))
+	}
+	a := (*audits)[0]
+	if a.dbTx != nil || a.entry.Action != sqldb.AuditStopNetwork || a.entry.BlockID != 6 || a.entry.KeyID != 7 {
+		t.Errorf("wrong stop network audit %+v", a.entry)
+	}
+}
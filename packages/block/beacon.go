@@ -0,0 +1,142 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/common/random"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+)
+
+var ErrRandomBeacon = errors.New("Incorrect random beacon")
+
+// beaconSecret returns the secret of the block which the node commits, it's derived from the node key
+// so the node reveals it by the next block without keeping it. It's nil if the node key isn't exported
+func beaconSecret(blockID int64) []byte {
+	key := syspar.GetNodePrivKey()
+	if len(key) == 0 {
+		return nil
+	}
+	return crypto.Hash(append(append([]byte{}, key...), converter.Int64ToByte(blockID)...))
+}
+
+// nextBeacon returns the beacon of the block after prev, the hash of the block is the beacon of the
+// chain before the beacon is enabled
+func nextBeacon(prev *sqldb.BlockChain, reveal []byte) []byte {
+	beacon := prev.RandomBeacon
+	if len(beacon) == 0 {
+		beacon = prev.Hash
+	}
+	return crypto.Hash(append(append([]byte{}, beacon...), reveal...))
+}
+
+// beaconBlocks returns the previous block and the last block of the key of the block blockID
+func beaconBlocks(blockID, keyID int64) (prev, last *sqldb.BlockChain, err error) {
+	prev = &sqldb.BlockChain{}
+	found, err := prev.Get(blockID - 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("block %d isn't found", blockID-1)
+	}
+	last = &sqldb.BlockChain{}
+	if found, err = last.GetLastOfKey(keyID, blockID); err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		last = nil
+	}
+	return prev, last, nil
+}
+
+// SetRandomBeacon sets the random beacon of the header which the node generates. The node reveals the
+// secret of its last block and commits the secret of the header
+func SetRandomBeacon(header *types.BlockHeader) error {
+	header.RandomBeacon, header.RandomReveal, header.RandomCommit = nil, nil, nil
+	if header.BlockId <= 1 || !syspar.IsRandomBeaconAt(header.BlockId) {
+		return nil
+	}
+	prev, last, err := beaconBlocks(header.BlockId, header.KeyId)
+	if err != nil {
+		return err
+	}
+	if last != nil && len(last.RandomCommit) > 0 {
+		if secret := beaconSecret(last.ID); secret != nil && bytes.Equal(crypto.Hash(secret), last.RandomCommit) {
+			header.RandomReveal = secret
+		}
+	}
+	if secret := beaconSecret(header.BlockId); secret != nil {
+		header.RandomCommit = crypto.Hash(secret)
+	}
+	header.RandomBeacon = nextBeacon(prev, header.RandomReveal)
+	return nil
+}
+
+// checkRandomBeacon rejects the block if its reveal isn't the secret which the last block of its key has
+// committed, the reveal is missing or its beacon isn't the beacon of the previous block with the reveal
+func (b *Block) checkRandomBeacon() error {
+	h := b.Header
+	if !syspar.IsRandomBeaconAt(h.BlockId) {
+		if len(h.RandomBeacon) > 0 || len(h.RandomReveal) > 0 || len(h.RandomCommit) > 0 {
+			return utils.WithBan(fmt.Errorf("%w: beacon is disabled", ErrRandomBeacon))
+		}
+		return nil
+	}
+	prev, last, err := beaconBlocks(h.BlockId, h.KeyId)
+	if err != nil {
+		return err
+	}
+	if len(h.RandomCommit) > 0 && len(h.RandomCommit) != len(crypto.Hash(nil)) {
+		return utils.WithBan(fmt.Errorf("%w: wrong commit size %d", ErrRandomBeacon, len(h.RandomCommit)))
+	}
+	var node *syspar.HonorNode
+	if !syspar.IsCandidateNodeMode() {
+		node, _ = syspar.GetNodeByPosition(h.NodePosition)
+	}
+	if err = checkReveal(h, last, node); err != nil {
+		return err
+	}
+	if !bytes.Equal(h.RandomBeacon, nextBeacon(prev, h.RandomReveal)) {
+		return utils.WithBan(fmt.Errorf("%w: %x", ErrRandomBeacon, h.RandomBeacon))
+	}
+	return nil
+}
+
+// checkReveal checks the reveal of the header against the commit of the last block of its key. The reveal
+// may be missing only if the node of the block has rotated its key and the last block is signed up to the
+// end of the rotation, then the secret of the commit is derived from the previous key of the node
+func checkReveal(h *types.BlockHeader, last *sqldb.BlockChain, node *syspar.HonorNode) error {
+	if len(h.RandomReveal) > 0 {
+		if last == nil || len(last.RandomCommit) == 0 || !bytes.Equal(crypto.Hash(h.RandomReveal), last.RandomCommit) {
+			return utils.WithBan(fmt.Errorf("%w: reveal doesn't match the commit", ErrRandomBeacon))
+		}
+		return nil
+	}
+	if last == nil || len(last.RandomCommit) == 0 {
+		return nil
+	}
+	if node != nil && len(node.PreviousPublicKey) > 0 && last.ID <= node.PreviousKeyUntil {
+		return nil
+	}
+	return utils.WithBan(fmt.Errorf("%w: reveal of the commit of block %d is missing", ErrRandomBeacon, last.ID))
+}
+
+// blockRand returns the random of the contracts of the block, it's seeded by the beacon of the block
+func blockRand(header *types.BlockHeader) *random.Rand {
+	if len(header.RandomBeacon) > 0 {
+		return random.NewBeaconRand(header.RandomBeacon)
+	}
+	return random.NewRand(header.Timestamp)
+}
@@ -0,0 +1,89 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+)
+
+func TestNextBeacon(t *testing.T) {
+	// the hash of the block is the beacon of the chain before the beacon is enabled
+	first := nextBeacon(&sqldb.BlockChain{Hash: []byte("hash")}, nil)
+	if !bytes.Equal(first, nextBeacon(&sqldb.BlockChain{RandomBeacon: []byte("hash")}, nil)) {
+		t.Error("hash of the block isn't the beacon")
+	}
+	prev := &sqldb.BlockChain{Hash: []byte("hash"), RandomBeacon: first}
+	if bytes.Equal(nextBeacon(prev, nil), nextBeacon(prev, []byte("secret"))) {
+		t.Error("reveal doesn't change the beacon")
+	}
+
+	header := &types.BlockHeader{BlockId: 10, RandomBeacon: first, RandomReveal: []byte("secret"), RandomCommit: []byte("commit")}
+	data, err := header.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &types.BlockHeader{}
+	if err = got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.RandomBeacon, first) || string(got.RandomReveal) != "secret" || string(got.RandomCommit) != "commit" {
+		t.Errorf("wrong beacon of the unmarshalled header %+v", got)
+	}
+}
+
+func TestBlockRand(t *testing.T) {
+	hash := []byte("tx")
+	beacon := &types.BlockHeader{Timestamp: 1700000000, RandomBeacon: []byte("beacon")}
+	same := &types.BlockHeader{Timestamp: 1700000004, RandomBeacon: []byte("beacon")}
+	if blockRand(beacon).BytesSeed(hash).Int63() != blockRand(same).BytesSeed(hash).Int63() {
+		t.Error("timestamp changes the random of the beacon")
+	}
+	other := &types.BlockHeader{Timestamp: 1700000000, RandomBeacon: []byte("other")}
+	if blockRand(beacon).BytesSeed(hash).Int63() == blockRand(other).BytesSeed(hash).Int63() {
+		t.Error("beacon doesn't change the random")
+	}
+}
+
+func TestCheckReveal(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	secret := []byte("secret")
+	last := &sqldb.BlockChain{ID: 20, RandomCommit: crypto.Hash(secret)}
+	rotated := &syspar.HonorNode{PreviousPublicKey: []byte("previous"), PreviousKeyUntil: 20}
+	for _, item := range []struct {
+		name   string
+		reveal []byte
+		last   *sqldb.BlockChain
+		node   *syspar.HonorNode
+		valid  bool
+	}{
+		{"reveal", secret, last, nil, true},
+		{"wrong reveal", []byte("other"), last, nil, false},
+		{"reveal without commit", secret, &sqldb.BlockChain{ID: 20}, nil, false},
+		{"reveal without last block", secret, nil, nil, false},
+		{"no commit", nil, &sqldb.BlockChain{ID: 20}, nil, true},
+		{"first block of key", nil, nil, nil, true},
+		{"missing reveal", nil, last, &syspar.HonorNode{}, false},
+		// the secret of the commit is derived from the previous key of the node
+		{"missing reveal after rotation", nil, last, rotated, true},
+		{"missing reveal after end of rotation", nil, &sqldb.BlockChain{ID: 21, RandomCommit: last.RandomCommit}, rotated, false},
+	} {
+		err := checkReveal(&types.BlockHeader{BlockId: 30, RandomReveal: item.reveal}, item.last, item.node)
+		if item.valid && err != nil {
+			t.Errorf("%s: %v", item.name, err)
+		}
+		if !item.valid && (!errors.Is(err, ErrRandomBeacon) || !utils.IsBanError(err)) {
+			t.Errorf("%s: expected %v with the ban, got %v", item.name, ErrRandomBeacon, err)
+		}
+	}
+}
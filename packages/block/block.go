@@ -6,6 +6,9 @@
 package block
 
 import (
+	"encoding/hex"
+
+	logtools "github.com/IBAX-io/go-ibax/packages/common/log"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
@@ -18,6 +21,17 @@ var (
 	ErrIncorrectRollbackHash = errors.New("Rollback hash doesn't match")
 	ErrEmptyBlock            = errors.New("Block doesn't contain transactions")
 	ErrIncorrectBlockTime    = utils.WithBan(errors.New("Incorrect block time"))
+	ErrBlockTimeInFuture     = errors.New("Block time is too far in the future")
+	ErrBlockTimeInPast       = errors.New("Block time is too far in the past")
+	ErrBlockTimeOrder        = errors.New("Block time isn't after the previous block")
+	ErrBlockTimeSlot         = errors.New("Block time is out of the generation slot of the node")
+	ErrTxOrder               = errors.New("Transaction is out of the execution order")
+	ErrTxGroupSection        = errors.New("Transaction hasn't the section of its group")
+	ErrBaseGasPrice          = errors.New("Incorrect base gas price")
+	ErrNotEnoughSignatures   = errors.New("Block doesn't have enough validator signatures")
+	ErrBlockSignature        = errors.New("Incorrect validator signature of the block")
+	ErrNotValidator          = errors.New("Key isn't the validator of the block")
+	ErrBlockFuel             = errors.New("Fuel of the block exceeds max_fuel_block")
 )
 
 // Block is storing block data
@@ -25,18 +39,54 @@ type Block struct {
 	*types.BlockData
 	PrevRollbacksHash []byte
 	Transactions      []*transaction.Transaction
-	GenBlock          bool // it equals true when we are generating a new block
+	GenBlock          bool   // it equals true when we are generating a new block
+	Origin            Origin // how the block is received, the time of the downloaded blocks isn't checked against the local clock
 	Notifications     []types.Notifications
 	OutputsMap        map[sqldb.KeyUTXO][]sqldb.SpentInfo
 	ClassifyTxsMap    map[int][]*transaction.Transaction
 	PrevSysPar        map[string]string
 	EcoParams         []sqldb.EcoParam // combustion percent,digits for each ecosystem
+	AuditLogs         []*sqldb.AuditLog
+	stopAudit         *sqldb.AuditLog                                 // audit entry of the stop network transaction, it's written after the rollback of the block
+	FeeStats          map[int][]int64                                 // gas prices of the played transactions by type
+	ContractStats     map[sqldb.ContractStatsKey]*sqldb.ContractStats // invocations of the played contracts
+	ResourceUsage     []*sqldb.ResourceUsage                          // resources consumed by the played contracts
+	Receipts          []*sqldb.TxReceipt                              // execution receipts of the played transactions
+	BinLogSql         [][]byte                                        // DML statements of the played transactions
+	execTrace         *blockTrace                                     // execution trace of the played block, nil if it's disabled
+	DACommitment      []byte                                          // commitment of the block data in the data availability layer
+	GasUsed           int64                                           // fuel of the played transactions, it adjusts the base gas price of the next block
+	Savepoints        int64                                           // savepoints of the played transactions
+	replay            bool                                            // the block is played again by Replay, the state of the node isn't changed
+	rejected          []badTxStruct                                   // the bad transactions of the replayed block, they aren't marked
+	liftedBans        []int64                                         // the keys whose bans are lifted after the commit
+	batch             *txBatch                                        // the savepoint batch of the played transactions, nil if it's closed
 }
 
-// GetLogger is returns logger
+// GetLogger is returns logger, the entries are correlated by the id, the hash and the mode of the block
 func (b *Block) GetLogger() *log.Entry {
-	return log.WithFields(log.Fields{"block_id": b.Header.BlockId, "block_time": b.Header.Timestamp, "block_wallet_id": b.Header.KeyId,
-		"block_state_id": b.Header.EcosystemId, "block_hash": b.Header.BlockHash, "block_version": b.Header.Version})
+	return log.WithFields(log.Fields{logtools.FieldBlockID: b.Header.BlockId, logtools.FieldBlockHash: hex.EncodeToString(b.Header.BlockHash),
+		logtools.FieldBlockMode: logtools.BlockMode(b.GenBlock), "block_time": b.Header.Timestamp, "block_wallet_id": b.Header.KeyId,
+		"block_state_id": b.Header.EcosystemId, "block_version": b.Header.Version})
+}
+
+// txLogger returns the logger of the block with the fields of the transaction
+func (b *Block) txLogger(t *transaction.Transaction) *log.Entry {
+	eco, contract := txContract(t)
+	return b.GetLogger().WithFields(log.Fields{logtools.FieldTxHash: hex.EncodeToString(t.Hash()),
+		logtools.FieldContract: contract, logtools.FieldEco: eco})
+}
+
+// txContract returns the ecosystem and the contract of the transaction
+func txContract(t *transaction.Transaction) (eco int64, contract string) {
+	eco = 1
+	if t.IsSmartContract() {
+		eco = t.SmartContract().TxSmart.EcosystemID
+		if t.SmartContract().TxContract != nil {
+			contract = t.SmartContract().TxContract.Name
+		}
+	}
+	return
 }
 
 func (b *Block) IsGenesis() bool {
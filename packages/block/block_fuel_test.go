@@ -0,0 +1,44 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestBlockFuelError(t *testing.T) {
+	const (
+		none = iota
+		rejected
+		stopped
+	)
+	for _, c := range []struct {
+		used, fuel, limit int64
+		genBlock          bool
+		expected          int
+	}{
+		{0, 100, 0, false, none},       // no limit
+		{50, 50, 100, false, none},     // the block is full
+		{50, 51, 100, false, rejected}, // the validator rejects the block
+		{50, 51, 100, true, stopped},   // the generator stops the block
+		{0, 101, 100, true, rejected},  // the transaction doesn't fit the empty block
+	} {
+		err := blockFuelError(c.used, c.fuel, c.limit, c.genBlock)
+		got := none
+		if pkgerrors.Cause(err) == transaction.ErrLimitStop {
+			got = stopped
+		} else if errors.Is(err, ErrBlockFuel) {
+			got = rejected
+		}
+		if got != c.expected {
+			t.Errorf("used %d, fuel %d, limit %d, gen %v: unexpected %v", c.used, c.fuel, c.limit, c.genBlock, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"encoding/hex"
+	"testing"
+
+	logtools "github.com/IBAX-io/go-ibax/packages/common/log"
+	"github.com/IBAX-io/go-ibax/packages/notificator"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+type loggingTxHandler struct{}
+
+func (loggingTxHandler) Execute(t *transaction.Transaction, dbTx *sqldb.DbTransaction) error {
+	t.GetLogger().Info("executing custom tx")
+	dbTx.GetLogger().Info("writing custom tx")
+	return nil
+}
+
+// TestBlockLogger executes the transaction like executeTx does and checks that the entries
+// of the transaction and the db transaction carry the correlation fields of the block
+func TestBlockLogger(t *testing.T) {
+	defer func(f func(int) bool) { isRegisteredTxType = f }(isRegisteredTxType)
+	isRegisteredTxType = func(typeID int) bool { return typeID == 20 }
+	if err := RegisterTxTypeHandler(20, loggingTxHandler{}); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterTxTypeHandler(20, nil)
+
+	hook := test.NewGlobal()
+	defer log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+
+	tx := newCustomTx(t, 20)
+	for _, gen := range []bool{false, true} {
+		hook.Reset()
+		b := mustBuild(t, newTestBuilder(10).AddTransaction(tx).WithGenBlock(gen))
+		dbTx := sqldb.NewDbTransaction(nil)
+		dbTx.SetLogger(b.GetLogger())
+		logger := b.txLogger(tx)
+		if err := tx.WithOption(notificator.NewQueueWithLogger(logger), b.GenBlock, b.Header, b.PrevHeader, dbTx, nil, nil,
+			"", nil, nil, nil, transaction.WithLogger(logger)); err != nil {
+			t.Fatal(err)
+		}
+		if err := executeCustomTx(tx, dbTx); err != nil {
+			t.Fatal(err)
+		}
+
+		mode := logtools.BlockMode(gen)
+		entries := hook.AllEntries()
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(entries))
+		}
+		for _, e := range entries {
+			if e.Data[logtools.FieldBlockID] != int64(10) || e.Data[logtools.FieldBlockMode] != mode ||
+				e.Data[logtools.FieldBlockHash] != hex.EncodeToString(b.Header.BlockHash) {
+				t.Errorf("%q: wrong block fields %v", e.Message, e.Data)
+			}
+		}
+		if data := entries[0].Data; data[logtools.FieldTxHash] != hex.EncodeToString(tx.Hash()) ||
+			data[logtools.FieldEco] != int64(1) || data["tx_type"] != byte(20) {
+			t.Errorf("wrong tx fields %v", data)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrBuilderNoHeader   = errors.New("block header isn't set")
+	ErrBuilderPrevHeader = errors.New("previous block header doesn't precede the block")
+	ErrBuilderTx         = errors.New("transaction can't be added to the block")
+	ErrBuilderStopTx     = errors.New("stop network transaction must be alone in the block")
+)
+
+// builderTx is the added transaction, txType is the forced class of ClassifyTxsMap or -1
+type builderTx struct {
+	tx     *transaction.Transaction
+	txType int
+}
+
+// Builder constructs the Block for the tests and the tools. The errors of the chained
+// calls are returned by Build
+type Builder struct {
+	header        *types.BlockHeader
+	prev          *types.BlockHeader
+	txs           []builderTx
+	genBlock      bool
+	sysUpdate     bool
+	afterTxs      *types.AfterTxs
+	outputs       map[sqldb.KeyUTXO][]sqldb.SpentInfo
+	contractNames []string
+	signer        keystore.Signer
+	err           error
+}
+
+// NewBuilder returns the empty builder, the header must be set before Build
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// GenesisBuilder returns the builder of the first block with the header of the current
+// block version and the zero previous header
+func GenesisBuilder() *Builder {
+	return NewBuilder().
+		WithHeader(&types.BlockHeader{
+			BlockId:       1,
+			Version:       consts.BlockVersion,
+			RollbacksHash: crypto.Hash([]byte(`0`)),
+			ConsensusMode: consts.HonorNodeMode,
+		}).
+		WithPrevHeader(&types.BlockHeader{
+			BlockHash:     crypto.DoubleHash([]byte(`0`)),
+			RollbacksHash: crypto.Hash([]byte(`0`)),
+		})
+}
+
+// NormalBlockBuilder returns the builder of the block following prev. The header has the next
+// block id, the next second after prev and the network of prev
+func NormalBlockBuilder(prev *types.BlockHeader) *Builder {
+	b := NewBuilder().WithPrevHeader(prev)
+	if prev == nil {
+		b.err = ErrBuilderPrevHeader
+		return b
+	}
+	return b.WithHeader(&types.BlockHeader{
+		BlockId:       prev.BlockId + 1,
+		Timestamp:     prev.Timestamp + 1,
+		NetworkId:     prev.NetworkId,
+		Version:       consts.BlockVersion,
+		ConsensusMode: consts.HonorNodeMode,
+	})
+}
+
+// WithHeader sets the header of the block
+func (b *Builder) WithHeader(h *types.BlockHeader) *Builder {
+	b.header = h
+	return b
+}
+
+// WithPrevHeader sets the header of the previous block
+func (b *Builder) WithPrevHeader(h *types.BlockHeader) *Builder {
+	b.prev = h
+	return b
+}
+
+// WithTimestamp sets the time of the block
+func (b *Builder) WithTimestamp(ts int64) *Builder {
+	return b.setHeader(func(h *types.BlockHeader) { h.Timestamp = ts })
+}
+
+// WithKeyID sets the key of the block producer
+func (b *Builder) WithKeyID(keyID int64) *Builder {
+	return b.setHeader(func(h *types.BlockHeader) { h.KeyId = keyID })
+}
+
+// WithNetworkID sets the network of the block
+func (b *Builder) WithNetworkID(networkID int64) *Builder {
+	return b.setHeader(func(h *types.BlockHeader) { h.NetworkId = networkID })
+}
+
+// WithBaseGasPrice sets the base gas price of the block
+func (b *Builder) WithBaseGasPrice(price int64) *Builder {
+	return b.setHeader(func(h *types.BlockHeader) { h.BaseGasPrice = price })
+}
+
+func (b *Builder) setHeader(set func(h *types.BlockHeader)) *Builder {
+	if b.header == nil {
+		b.header = &types.BlockHeader{}
+	}
+	set(b.header)
+	return b
+}
+
+// AddTransaction adds the decoded transaction, it's classified by its type
+func (b *Builder) AddTransaction(txs ...*transaction.Transaction) *Builder {
+	for _, tx := range txs {
+		if tx == nil || tx.Inner == nil || len(tx.FullData) == 0 {
+			b.fail(errors.Wrap(ErrBuilderTx, "transaction isn't decoded"))
+			continue
+		}
+		b.txs = append(b.txs, builderTx{tx: tx, txType: -1})
+	}
+	return b
+}
+
+// AddTransactionAs adds the transaction with the class of ClassifyTxsMap. It's used when
+// the transaction isn't decoded or the class differs from the type of the transaction
+func (b *Builder) AddTransactionAs(txType int, tx *transaction.Transaction) *Builder {
+	if tx == nil {
+		return b.fail(errors.Wrap(ErrBuilderTx, "transaction is nil"))
+	}
+	b.txs = append(b.txs, builderTx{tx: tx, txType: txType})
+	return b
+}
+
+// AddRawTransaction decodes the binary transactions and adds them
+func (b *Builder) AddRawTransaction(data ...[]byte) *Builder {
+	for _, item := range data {
+		tx, err := transaction.UnmarshallTransaction(bytes.NewBuffer(item), true)
+		if err != nil {
+			b.fail(errors.Wrap(ErrBuilderTx, err.Error()))
+			continue
+		}
+		b.AddTransaction(tx)
+	}
+	return b
+}
+
+// WithGenBlock marks the block which is generated by this node
+func (b *Builder) WithGenBlock(gen bool) *Builder {
+	b.genBlock = gen
+	return b
+}
+
+// WithSysUpdate sets the flag of the changed platform parameters
+func (b *Builder) WithSysUpdate(sysUpdate bool) *Builder {
+	b.sysUpdate = sysUpdate
+	return b
+}
+
+// WithAfterTxs sets the results of the transactions of the generated block
+func (b *Builder) WithAfterTxs(a *types.AfterTxs) *Builder {
+	b.afterTxs = a
+	return b
+}
+
+// WithOutputs sets the utxo outputs which are available to the transactions of the block
+func (b *Builder) WithOutputs(outputs map[sqldb.KeyUTXO][]sqldb.SpentInfo) *Builder {
+	b.outputs = outputs
+	return b
+}
+
+// WithDelayedContracts sets the contracts of the delayed transactions, the smart contract
+// transactions calling them are classified as types.DelayTxType
+func (b *Builder) WithDelayedContracts(names ...string) *Builder {
+	b.contractNames = names
+	return b
+}
+
+// WithSigner signs the block with the node key, Build marshals the signed block into BinData
+func (b *Builder) WithSigner(signer keystore.Signer) *Builder {
+	b.signer = signer
+	return b
+}
+
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+func (b *Builder) validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.header == nil {
+		return ErrBuilderNoHeader
+	}
+	if b.header.BlockId > 1 && (b.prev == nil || b.prev.BlockId != b.header.BlockId-1) {
+		return errors.Wrapf(ErrBuilderPrevHeader, "block %d", b.header.BlockId)
+	}
+	return nil
+}
+
+// Build validates the fields and returns the block. ClassifyTxsMap is filled with the added
+// transactions in the order of adding, the Merkle root and the hash of the header are computed.
+// BinData and the signature of the header are set only with WithSigner
+func (b *Builder) Build() (*Block, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	prev := b.prev
+	if prev == nil {
+		prev = &types.BlockHeader{}
+	}
+	data := &types.BlockData{
+		Header:     b.header,
+		PrevHeader: prev,
+		AfterTxs:   b.afterTxs,
+		SysUpdate:  b.sysUpdate,
+	}
+	for _, item := range b.txs {
+		data.TxFullData = append(data.TxFullData, item.tx.FullData)
+	}
+	if err := b.seal(data); err != nil {
+		return nil, err
+	}
+
+	block := &Block{
+		BlockData:         data,
+		PrevRollbacksHash: prev.RollbacksHash,
+		GenBlock:          b.genBlock,
+		OutputsMap:        b.outputs,
+		ClassifyTxsMap:    make(map[int][]*transaction.Transaction),
+		Transactions:      make([]*transaction.Transaction, 0, len(b.txs)),
+	}
+	for _, item := range b.txs {
+		txType, ok := item.txType, item.txType != -1
+		if !ok {
+			txType, ok = classifyTx(item.tx, b.contractNames)
+		}
+		if ok {
+			block.ClassifyTxsMap[txType] = append(block.ClassifyTxsMap[txType], item.tx)
+		}
+		block.Transactions = append(block.Transactions, item.tx)
+	}
+	if len(block.ClassifyTxsMap[types.StopNetworkTxType]) > 0 && len(block.Transactions) > 1 {
+		return nil, ErrBuilderStopTx
+	}
+	return block, nil
+}
+
+// seal computes the Merkle root of the compressed transactions like MarshallBlock does,
+// the transactions of data stay uncompressed like in the unmarshalled block
+func (b *Builder) seal(data *types.BlockData) error {
+	sealed := &types.BlockData{
+		Header:     data.Header,
+		PrevHeader: data.PrevHeader,
+		AfterTxs:   data.AfterTxs,
+		SysUpdate:  data.SysUpdate,
+		TxFullData: make([][]byte, len(data.TxFullData)),
+	}
+	for i, tx := range data.TxFullData {
+		sealed.TxFullData[i] = append([]byte(nil), tx...)
+	}
+	if b.signer == nil {
+		for i := range sealed.TxFullData {
+			sealed.TxFullData[i] = types.DoZlibCompress(sealed.TxFullData[i])
+		}
+		data.MerkleRoot = sealed.GenMerkleRoot()
+		data.Header.BlockHash = data.Header.GenHash(data.PrevHeader, data.MerkleRoot)
+		return nil
+	}
+	bin, err := sealed.MarshallBlock(b.signer)
+	if err != nil {
+		return errors.Wrap(err, "marshalling block")
+	}
+	data.MerkleRoot = sealed.MerkleRoot
+	data.BinData = bin
+	return nil
+}
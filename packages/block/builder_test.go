@@ -0,0 +1,119 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func mustBuild(t *testing.T, b *Builder) *Block {
+	t.Helper()
+	block, err := b.Build()
+	if err != nil {
+		t.Fatalf("building block: %v", err)
+	}
+	return block
+}
+
+// newTestBuilder returns the builder of the block blockID after the block of the previous second
+func newTestBuilder(blockID int64) *Builder {
+	return NormalBlockBuilder(&types.BlockHeader{BlockId: blockID - 1, Timestamp: 1699999999})
+}
+
+func newCustomTx(t *testing.T, typeID byte) *transaction.Transaction {
+	t.Helper()
+	data, err := new(transaction.CustomTxParser).BinMarshal(&types.CustomTransaction{
+		Type: typeID, KeyID: 1, Time: 1700000000000, Data: []byte{typeID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := transaction.UnmarshallTransaction(bytes.NewBuffer(data), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestBuilder(t *testing.T) {
+	first, second := newCustomTx(t, 20), newCustomTx(t, 21)
+	b := mustBuild(t, newTestBuilder(10).WithKeyID(5).AddTransaction(first, second).WithGenBlock(true))
+	if b.Header.BlockId != 10 || b.Header.Timestamp != 1700000000 || b.Header.KeyId != 5 || !b.GenBlock {
+		t.Errorf("wrong header %+v", b.Header)
+	}
+	if len(b.Transactions) != 2 || len(b.ClassifyTxsMap[20]) != 1 || b.ClassifyTxsMap[21][0] != second {
+		t.Errorf("wrong classified transactions %v", b.ClassifyTxsMap)
+	}
+	if len(b.MerkleRoot) == 0 || len(b.Header.BlockHash) == 0 {
+		t.Error("merkle root and hash aren't computed")
+	}
+	if !bytes.Equal(b.TxFullData[0], first.FullData) {
+		t.Error("transactions must be stored uncompressed")
+	}
+	// the root depends on the transactions
+	other := mustBuild(t, newTestBuilder(10).AddTransaction(second, first))
+	if bytes.Equal(other.MerkleRoot, b.MerkleRoot) {
+		t.Error("merkle root doesn't depend on the order of transactions")
+	}
+
+	// the signed block is decoded with the same root and the body is the same as the unsigned one
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	priv, _, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, err := hex.DecodeString(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keystore.NewKeySigner(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := mustBuild(t, newTestBuilder(10).AddTransaction(first, second).WithSigner(signer))
+	decoded := &types.BlockData{}
+	if err = decoded.UnmarshallBlock(signed.BinData); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Header.Sign) == 0 || !bytes.Equal(decoded.MerkleRoot, signed.MerkleRoot) ||
+		!bytes.Equal(decoded.Header.BlockHash, signed.Header.BlockHash) {
+		t.Error("wrong signed block")
+	}
+	if unsigned := mustBuild(t, newTestBuilder(10).AddTransaction(first, second)); !bytes.Equal(unsigned.MerkleRoot, decoded.MerkleRoot) {
+		t.Error("merkle root of the unsigned block differs from the marshalled one")
+	}
+
+	genesis := mustBuild(t, GenesisBuilder().WithTimestamp(1700000000))
+	if !genesis.IsGenesis() || genesis.PrevHeader == nil || len(genesis.PrevRollbacksHash) == 0 {
+		t.Errorf("wrong genesis block %+v", genesis.Header)
+	}
+
+	for name, item := range map[string]struct {
+		builder *Builder
+		err     error
+	}{
+		"no header":      {NewBuilder(), ErrBuilderNoHeader},
+		"no prev":        {NewBuilder().WithHeader(&types.BlockHeader{BlockId: 10}), ErrBuilderPrevHeader},
+		"wrong prev":     {NewBuilder().WithHeader(&types.BlockHeader{BlockId: 10}).WithPrevHeader(&types.BlockHeader{BlockId: 8}), ErrBuilderPrevHeader},
+		"nil prev":       {NormalBlockBuilder(nil), ErrBuilderPrevHeader},
+		"empty tx":       {newTestBuilder(10).AddTransaction(&transaction.Transaction{}), ErrBuilderTx},
+		"wrong raw tx":   {newTestBuilder(10).AddRawTransaction([]byte{}), ErrBuilderTx},
+		"stop with more": {newTestBuilder(10).AddTransactionAs(types.StopNetworkTxType, first).AddTransaction(second), ErrBuilderStopTx},
+	} {
+		if _, err := item.builder.Build(); !errors.Is(err, item.err) {
+			t.Errorf("%s: expected %v got %v", name, item.err, err)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/protocols"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/utils"
 	"github.com/pkg/errors"
@@ -28,6 +29,10 @@ func (b *Block) Check() error {
 		return nil
 	}
 	logger := b.GetLogger()
+	// the transactions kept off-chain must be available to be checked
+	if err := b.retrieveTxs(); err != nil {
+		return err
+	}
 	if b.PrevHeader.BlockId != b.Header.BlockId-1 {
 		var err error
 		b.PrevHeader, err = GetBlockHeaderFromBlockChain(b.Header.BlockId - 1)
@@ -37,30 +42,41 @@ func (b *Block) Check() error {
 		}
 	}
 
-	if b.Header.Timestamp > time.Now().Unix() {
-		logger.WithFields(log.Fields{"type": consts.ParameterExceeded}).Error("block time is larger than now")
-		return ErrIncorrectBlockTime
-	}
-	var (
-		exists bool
-		err    error
-	)
-	if syspar.IsHonorNodeMode() {
-		// is this block too early? Allowable error = error_time
-		exists, err = protocols.NewBlockTimeCounter().BlockForTimeExists(time.Unix(b.Header.Timestamp, 0), int(b.Header.NodePosition))
+	// the blocks before the activation of the strict block time could have the time of the previous block
+	strict := syspar.IsStrictBlockTimeAt(b.Header.BlockId)
+	if strict {
+		if err := b.checkTimeOrder(); err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err}).Error("checking block time")
+			return err
+		}
 	}
-	if err != nil {
-		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("calculating block time")
+	if err := b.checkTimestamp(time.Now(), syspar.GetMaxPastBlockAge()); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err}).Error("checking block time")
 		return err
 	}
-
-	if exists {
+	var err error
+	// the slots of the archived blocks were counted by the honor nodes of their time, not the current ones
+	if syspar.IsHonorNodeMode() && b.Origin != OriginArchive {
+		var counter slotCounter
+		if counter, err = newSlotCounter(b.Header.BlockId); err == nil {
+			err = b.checkSlot(counter, syspar.GetMinPoWBits(), strict)
+		}
+	}
+	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Warn("incorrect block time")
-		return utils.WithBan(fmt.Errorf("%s %d", ErrIncorrectBlockTime, b.PrevHeader.Timestamp))
+		return err
 	}
 	if !bytes.Equal(b.PrevRollbacksHash, b.PrevHeader.RollbacksHash) {
 		return ErrIncorrectRollbackHash
 	}
+	if err = b.checkBaseGasPrice(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err}).Error("checking base gas price")
+		return err
+	}
+	if err = b.checkRandomBeacon(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("checking random beacon")
+		return err
+	}
 	// check each transaction
 	txCounter := make(map[int64]int)
 	txHashes := make(map[string]struct{})
@@ -94,23 +110,115 @@ func (b *Block) Check() error {
 		transaction.CleanCache()
 		return err
 	}
+	if err = b.CheckSignatures(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("checking validator signatures")
+		transaction.CleanCache()
+		return err
+	}
 	return nil
 }
 
+// Origin is how the node has received the block, it defines the checks of the block time
+type Origin int
+
+const (
+	// OriginSync is the block which is downloaded while the node catches up the chain or switches to the other branch
+	OriginSync Origin = iota
+	// OriginBroadcast is the block which is announced to the node by its generator, its time is checked against the local clock
+	OriginBroadcast
+	// OriginArchive is the block which is replayed from the archive, neither its time nor its slot is checked
+	OriginArchive
+)
+
+// checkTimeOrder rejects the block if its time isn't after the previous block
+func (b *Block) checkTimeOrder() error {
+	if b.PrevHeader != nil && b.Header.Timestamp <= b.PrevHeader.Timestamp {
+		return utils.WithBan(fmt.Errorf("%w: %d is not after %d", ErrBlockTimeOrder, b.Header.Timestamp, b.PrevHeader.Timestamp))
+	}
+	return nil
+}
+
+// checkTimestamp rejects the block if its time is too far from now. The past limit maxPast is checked
+// for the broadcast block only, the downloaded blocks could be older. The time of the archived block
+// isn't checked against now
+func (b *Block) checkTimestamp(now time.Time, maxPast time.Duration) error {
+	if b.Origin == OriginArchive {
+		return nil
+	}
+	blockTime := time.Unix(b.Header.Timestamp, 0)
+	if blockTime.After(now.Add(conf.Config.GetMaxFutureBlockAge())) {
+		return utils.WithBan(fmt.Errorf("%w: %d is ahead of %d", ErrBlockTimeInFuture, b.Header.Timestamp, now.Unix()))
+	}
+	if b.Origin != OriginBroadcast {
+		return nil
+	}
+	if blockTime.Before(now.Add(-maxPast)) {
+		return fmt.Errorf("%w: %d is behind %d", ErrBlockTimeInPast, b.Header.Timestamp, now.Unix())
+	}
+	return nil
+}
+
+// slotCounter is the generation queue of the honor nodes
+type slotCounter interface {
+	TimeToGenerate(at time.Time, nodePosition int) (bool, error)
+	BlockForTimeExists(t time.Time, nodePosition int) (bool, error)
+}
+
+var newSlotCounter = func(blockID int64) (slotCounter, error) {
+	return protocols.NewBlockTimeCounter(blockID)
+}
+
+// checkSlot rejects the block if the node has already generated the block in the slot or, if strict
+// is true, its time isn't in the generation slot of its node. The block out of the slot is accepted
+// with the proof of work of powBits. The slot isn't checked while the node is catching up the
+// blockchain because the queue is counted by the current number of the nodes
+func (b *Block) checkSlot(counter slotCounter, powBits int, strict bool) error {
+	blockTime := time.Unix(b.Header.Timestamp, 0)
+	exists, err := counter.BlockForTimeExists(blockTime, int(b.Header.NodePosition))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return utils.WithBan(fmt.Errorf("%w %d", ErrIncorrectBlockTime, b.PrevHeader.Timestamp))
+	}
+	if !strict || node.NodePauseType() == node.PauseTypeUpdatingBlockchain {
+		return nil
+	}
+	ok, err := counter.TimeToGenerate(blockTime, int(b.Header.NodePosition))
+	if err != nil {
+		return err
+	}
+	if !ok && !b.CheckProofOfWork(powBits) {
+		return utils.WithBan(fmt.Errorf("%w: %d, node position %d", ErrBlockTimeSlot, b.Header.Timestamp, b.Header.NodePosition))
+	}
+	return nil
+}
+
+// NextBlockTime returns the time of the block which follows the block of prevTime. It's now
+// unless the local clock has regressed, then it's the next second after the previous block
+func NextBlockTime(now time.Time, prevTime int64) time.Time {
+	if now.Unix() > prevTime {
+		return now
+	}
+	return time.Unix(prevTime+1, 0)
+}
+
 func (b *Block) CheckSign() error {
 	if b.IsGenesis() || conf.Config.IsSubNode() || b.PrevHeader == nil {
 		return nil
 	}
-	nodePub, err := syspar.GetNodePublicKeyByPosition(b.Header.NodePosition)
+	// the previous key of the rotated node key signs the blocks of the transition period
+	nodeKeys, err := syspar.GetNodePublicKeysAt(b.Header.NodePosition, b.Header.BlockId)
 	if err != nil {
 		return fmt.Errorf("%v: %w", fmt.Sprintf("get node public key by position '%d'", b.Header.NodePosition), err)
 	}
-	if len(nodePub) == 0 {
+	if len(nodeKeys[0]) == 0 {
 		return fmt.Errorf("empty nodePublicKey")
 	}
-	_, err = utils.CheckSign([][]byte{nodePub}, []byte(b.ForSign()), b.Header.Sign, true)
-	if err != nil {
-		return errors.Wrap(err, "checking block header sign")
+	for _, nodePub := range nodeKeys {
+		if _, err = utils.CheckSign([][]byte{nodePub}, []byte(b.ForSign()), b.Header.Sign, true); err == nil {
+			return nil
+		}
 	}
-	return nil
+	return errors.Wrap(err, "checking block header sign")
 }
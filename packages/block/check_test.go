@@ -0,0 +1,223 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+)
+
+const maxPast = 30 * time.Minute
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	newBlock := func(ts int64) *Block {
+		b := mustBuild(t, NewBuilder().WithTimestamp(ts))
+		b.Origin = OriginBroadcast
+		return b
+	}
+
+	for i, item := range []struct {
+		ts  int64
+		err error
+	}{
+		{now.Unix(), nil},
+		{now.Unix() + 15, nil},
+		{now.Unix() - 30*60, nil},
+		{now.Unix() + 16, ErrBlockTimeInFuture},
+		{time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), ErrBlockTimeInFuture},
+		{now.Unix() - 30*60 - 1, ErrBlockTimeInPast},
+	} {
+		err := newBlock(item.ts).checkTimestamp(now, maxPast)
+		if !errors.Is(err, item.err) {
+			t.Errorf("on %d step expected %v got %v", i, item.err, err)
+		}
+		if item.err == ErrBlockTimeInFuture && !utils.IsBanError(err) {
+			t.Errorf("on %d step expected ban error", i)
+		}
+	}
+
+	b := newBlock(now.Unix() - 24*60*60)
+	b.Origin = OriginSync
+	if err := b.checkTimestamp(now, maxPast); err != nil {
+		t.Errorf("old block must be accepted by the sync: %v", err)
+	}
+	b = newBlock(now.Unix() + 16)
+	b.Origin = OriginSync
+	if err := b.checkTimestamp(now, maxPast); !errors.Is(err, ErrBlockTimeInFuture) {
+		t.Errorf("future block must be rejected by the sync, got %v", err)
+	}
+}
+
+func TestCheckTimestampArchive(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	prev := &types.BlockHeader{BlockId: 9, Timestamp: now.Unix() - 365*24*60*60}
+	for i, item := range []struct {
+		ts  int64
+		err error
+	}{
+		{prev.Timestamp + 1, nil},
+		{now.Unix() + 60, nil},
+	} {
+		b := mustBuild(t, NormalBlockBuilder(prev).WithTimestamp(item.ts))
+		b.Origin = OriginArchive
+		if err := b.checkTimestamp(now, maxPast); !errors.Is(err, item.err) {
+			t.Errorf("on %d step expected %v got %v", i, item.err, err)
+		}
+	}
+}
+
+// TestCheckTimestampSync checks that the node which is a few old blocks behind catches up the chain
+// even if the network has been quiet and the last block of the host is old too
+func TestCheckTimestampSync(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	prev := &types.BlockHeader{BlockId: 9, Timestamp: now.Unix() - 3*60*60}
+	var chain []*Block
+	for i := 0; i < 3; i++ {
+		b := mustBuild(t, NormalBlockBuilder(prev).WithTimestamp(prev.Timestamp+10))
+		chain = append(chain, b)
+		prev = b.Header
+	}
+	for _, b := range chain {
+		if b.Origin != OriginSync {
+			t.Fatalf("block %d: the downloaded block is expected by default, got origin %d", b.Header.BlockId, b.Origin)
+		}
+		if err := b.checkTimestamp(now, maxPast); err != nil {
+			t.Fatalf("block %d is rejected: %v", b.Header.BlockId, err)
+		}
+	}
+
+	stale := mustBuild(t, NormalBlockBuilder(chain[2].Header).WithTimestamp(chain[2].Header.Timestamp+10))
+	stale.Origin = OriginBroadcast
+	if err := stale.checkTimestamp(now, maxPast); !errors.Is(err, ErrBlockTimeInPast) || utils.IsBanError(err) {
+		t.Errorf("old broadcast block must be rejected without ban, got %v", err)
+	}
+	tip := mustBuild(t, NormalBlockBuilder(chain[2].Header).WithTimestamp(now.Unix()-5))
+	tip.Origin = OriginBroadcast
+	if err := tip.checkTimestamp(now, maxPast); err != nil {
+		t.Errorf("broadcast block is rejected: %v", err)
+	}
+}
+
+func TestCheckTimestampOrder(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	prev := &types.BlockHeader{BlockId: 9, Timestamp: now.Unix() - 10}
+	for i, item := range []struct {
+		ts  int64
+		err error
+	}{
+		{prev.Timestamp + 1, nil},
+		{prev.Timestamp, ErrBlockTimeOrder},
+		{prev.Timestamp - 1, ErrBlockTimeOrder},
+	} {
+		err := mustBuild(t, NormalBlockBuilder(prev).WithTimestamp(item.ts)).checkTimeOrder()
+		if !errors.Is(err, item.err) {
+			t.Errorf("on %d step expected %v got %v", i, item.err, err)
+		}
+		if item.err != nil && !utils.IsBanError(err) {
+			t.Errorf("on %d step expected ban error", i)
+		}
+	}
+}
+
+// testSlotCounter assigns the slots of slotSize seconds to the nodes in turn
+type testSlotCounter struct {
+	slotSize  int64
+	nodes     int64
+	generated map[int64]bool
+}
+
+func (c *testSlotCounter) TimeToGenerate(at time.Time, nodePosition int) (bool, error) {
+	return at.Unix()/c.slotSize%c.nodes == int64(nodePosition), nil
+}
+
+func (c *testSlotCounter) BlockForTimeExists(at time.Time, nodePosition int) (bool, error) {
+	return c.generated[at.Unix()/c.slotSize], nil
+}
+
+func TestCheckSlot(t *testing.T) {
+	counter := &testSlotCounter{slotSize: 10, nodes: 2, generated: map[int64]bool{170000001: true}}
+	newBlock := func(ts, position int64) *Block {
+		b := mustBuild(t, newTestBuilder(10).WithTimestamp(ts))
+		b.Header.NodePosition = position
+		return b
+	}
+	for i, item := range []struct {
+		ts       int64
+		position int64
+		err      error
+	}{
+		{1700000000, 0, nil},
+		{1700000009, 0, nil},
+		{1700000020, 1, ErrBlockTimeSlot},
+		{1700000029, 0, nil},
+		{1700000030, 0, ErrBlockTimeSlot},
+		{1700000030, 1, nil},
+		{1700000015, 1, ErrIncorrectBlockTime},
+	} {
+		err := newBlock(item.ts, item.position).checkSlot(counter, 0, true)
+		if !errors.Is(err, item.err) || item.err != nil && !utils.IsBanError(err) {
+			t.Errorf("on %d step expected %v got %v", i, item.err, err)
+		}
+	}
+
+	// the blocks before the activation of the strict block time are rejected in the generated slot only
+	if err := newBlock(1700000020, 1).checkSlot(counter, 0, false); err != nil {
+		t.Errorf("slot must not be checked before the activation: %v", err)
+	}
+	if err := newBlock(1700000015, 1).checkSlot(counter, 0, false); !errors.Is(err, ErrIncorrectBlockTime) {
+		t.Errorf("expected %v before the activation, got %v", ErrIncorrectBlockTime, err)
+	}
+
+	node.PauseNodeActivity(node.PauseTypeUpdatingBlockchain)
+	defer node.PauseNodeActivity(node.NoPause)
+	if err := newBlock(1700000020, 1).checkSlot(counter, 0, true); err != nil {
+		t.Errorf("slot must not be checked while updating blockchain: %v", err)
+	}
+}
+
+func TestCheckSlotProofOfWork(t *testing.T) {
+	counter := &testSlotCounter{slotSize: 10, nodes: 2, generated: map[int64]bool{}}
+	b := mustBuild(t, newTestBuilder(10).WithTimestamp(1700000020))
+	b.Header.NodePosition = 1
+
+	if err := b.FindProofOfWork(context.Background(), 8); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.checkSlot(counter, 8, true); err != nil {
+		t.Errorf("block with proof of work must be accepted out of slot: %v", err)
+	}
+	// the wrong nonce
+	valid := b.Header.ProofOfWork
+	for b.CheckProofOfWork(8) {
+		b.Header.ProofOfWork++
+	}
+	if err := b.checkSlot(counter, 8, true); !errors.Is(err, ErrBlockTimeSlot) || !utils.IsBanError(err) {
+		t.Errorf("expected %v got %v", ErrBlockTimeSlot, err)
+	}
+	b.Header.ProofOfWork = valid
+	if err := b.checkSlot(counter, 0, true); !errors.Is(err, ErrBlockTimeSlot) {
+		t.Errorf("proof of work must be disabled, got %v", err)
+	}
+}
+
+func TestNextBlockTime(t *testing.T) {
+	now := time.Unix(1700000000, 500)
+	if got := NextBlockTime(now, now.Unix()-1); !got.Equal(now) {
+		t.Errorf("expected now, got %v", got)
+	}
+	for _, prev := range []int64{now.Unix(), now.Unix() + 5} {
+		if got := NextBlockTime(now, prev); got.Unix() != prev+1 {
+			t.Errorf("expected %d got %d", prev+1, got.Unix())
+		}
+	}
+}
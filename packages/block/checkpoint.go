@@ -0,0 +1,29 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// ErrFinalized is returned when the rollback of the chain crosses the finalized checkpoint
+var ErrFinalized = errors.New("fork is below the finalized checkpoint")
+
+// CheckFinalized returns ErrFinalized if the blocks after forkID can't be rolled back, the block of the
+// last checkpoint and the blocks before it are final
+func CheckFinalized(forkID int64) error {
+	c, found, err := sqldb.GetLastCheckpoint()
+	if err != nil {
+		return err
+	}
+	if found && forkID < c.BlockID {
+		return fmt.Errorf("%w: fork block %d, checkpoint block %d", ErrFinalized, forkID, c.BlockID)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"sort"
+	"sync"
+)
+
+// BlockCommittedEvent is the block which has been committed with the hashes of its transactions
+type BlockCommittedEvent struct {
+	BlockID  int64
+	TxHashes [][]byte
+}
+
+var (
+	committedHandlers = make(map[string]func(*BlockCommittedEvent))
+	committedMutex    sync.RWMutex
+)
+
+// SubscribeBlockCommitted registers the handler of the committed blocks with the name, nil handler
+// removes it. The handlers are called one after another after the commit, so they should be fast
+func SubscribeBlockCommitted(name string, handler func(*BlockCommittedEvent)) {
+	committedMutex.Lock()
+	defer committedMutex.Unlock()
+	if handler == nil {
+		delete(committedHandlers, name)
+		return
+	}
+	committedHandlers[name] = handler
+}
+
+// publishCommitted passes the committed block to the handlers in the order of their names
+func (b *Block) publishCommitted() {
+	committedMutex.RLock()
+	names := make([]string, 0, len(committedHandlers))
+	for name := range committedHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	handlers := make([]func(*BlockCommittedEvent), 0, len(names))
+	for _, name := range names {
+		handlers = append(handlers, committedHandlers[name])
+	}
+	committedMutex.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+	event := &BlockCommittedEvent{BlockID: b.Header.BlockId, TxHashes: make([][]byte, 0, len(b.Transactions))}
+	for _, t := range b.Transactions {
+		event.TxHashes = append(event.TxHashes, t.Hash())
+	}
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
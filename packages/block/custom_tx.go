@@ -0,0 +1,83 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+var (
+	ErrUnknownTxType   = errors.New("Transaction type isn't registered")
+	ErrNoTxTypeHandler = errors.New("Transaction type doesn't have the handler")
+)
+
+// TxTypeHandler executes the custom transaction type within the transaction of the block
+type TxTypeHandler interface {
+	Execute(t *transaction.Transaction, dbTx *sqldb.DbTransaction) error
+}
+
+var (
+	txTypeHandlers = make(map[int]TxTypeHandler)
+	txTypeMutex    sync.RWMutex
+
+	// isRegisteredTxType reports whether the type is accepted by the network
+	isRegisteredTxType = func(typeID int) bool {
+		_, ok := syspar.GetRegisteredTxType(typeID)
+		return ok
+	}
+)
+
+// RegisterTxTypeHandler registers the handler of the custom transaction type. The transactions
+// of the type are executed only if the type is also in the registered_tx_types platform parameter
+func RegisterTxTypeHandler(typeID int, handler TxTypeHandler) error {
+	if !types.IsCustomTxType(typeID) {
+		return fmt.Errorf("invalid custom transaction type %d, expected %d-%d",
+			typeID, types.CustomTxTypeMin, types.CustomTxTypeMax)
+	}
+	txTypeMutex.Lock()
+	defer txTypeMutex.Unlock()
+	if handler == nil {
+		delete(txTypeHandlers, typeID)
+		return nil
+	}
+	txTypeHandlers[typeID] = handler
+	return nil
+}
+
+// executeCustomTx executes the custom transaction by the handler of its type
+func executeCustomTx(t *transaction.Transaction, dbTx *sqldb.DbTransaction) error {
+	typeID := int(t.Type())
+	if !isRegisteredTxType(typeID) {
+		return fmt.Errorf("%w: %d", ErrUnknownTxType, typeID)
+	}
+	txTypeMutex.RLock()
+	handler, ok := txTypeHandlers[typeID]
+	txTypeMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrNoTxTypeHandler, typeID)
+	}
+	return handler.Execute(t, dbTx)
+}
+
+// customTxTypes returns the custom types of txsMap in the ascending order
+func customTxTypes(txsMap map[int][]*transaction.Transaction) []int {
+	var list []int
+	for txType := range txsMap {
+		if types.IsCustomTxType(txType) {
+			list = append(list, txType)
+		}
+	}
+	sort.Ints(list)
+	return list
+}
@@ -0,0 +1,88 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+type testTxHandler struct {
+	data [][]byte
+}
+
+func (h *testTxHandler) Execute(t *transaction.Transaction, dbTx *sqldb.DbTransaction) error {
+	h.data = append(h.data, t.Custom().Data)
+	return nil
+}
+
+func TestExecuteCustomTx(t *testing.T) {
+	registered := map[int]bool{20: true, 21: true}
+	defer func(f func(int) bool) { isRegisteredTxType = f }(isRegisteredTxType)
+	isRegisteredTxType = func(typeID int) bool { return registered[typeID] }
+
+	handler := &testTxHandler{}
+	if err := RegisterTxTypeHandler(20, handler); err != nil {
+		t.Fatal(err)
+	}
+	defer RegisterTxTypeHandler(20, nil)
+	if err := RegisterTxTypeHandler(types.TransferSelfTxType, handler); err == nil {
+		t.Error("built-in type must not be registered")
+	}
+
+	newTx := func(typeID byte) *transaction.Transaction {
+		tx := newCustomTx(t, typeID)
+		if !tx.IsCustom() || tx.Type() != typeID || tx.KeyID() != 1 {
+			t.Fatalf("wrong decoded tx %d", tx.Type())
+		}
+		if n, ok := classifyTx(tx, nil); !ok || n != int(typeID) {
+			t.Errorf("wrong class %d of type %d", n, typeID)
+		}
+		return tx
+	}
+
+	if err := executeCustomTx(newTx(20), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(handler.data) != 1 || handler.data[0][0] != 20 {
+		t.Errorf("handler isn't called: %v", handler.data)
+	}
+	if err := executeCustomTx(newTx(21), nil); !errors.Is(err, ErrNoTxTypeHandler) {
+		t.Errorf("expected no handler, got %v", err)
+	}
+	if err := executeCustomTx(newTx(22), nil); !errors.Is(err, ErrUnknownTxType) {
+		t.Errorf("expected unknown type, got %v", err)
+	}
+	if err := newTx(20).Play(); !errors.Is(err, transaction.ErrCustomTxHandler) {
+		t.Errorf("custom tx must not be played, got %v", err)
+	}
+}
+
+func TestOrderedCustomTxs(t *testing.T) {
+	txs := make([]*transaction.Transaction, 4)
+	for i := range txs {
+		txs[i] = &transaction.Transaction{}
+	}
+	b := mustBuild(t, newTestBuilder(10).
+		AddTransactionAs(types.SmartContractTxType, txs[0]).
+		AddTransactionAs(30, txs[1]).
+		AddTransactionAs(types.DelayTxType, txs[2]).
+		AddTransactionAs(17, txs[3]))
+	want := []*transaction.Transaction{txs[2], txs[3], txs[1], txs[0]}
+	got := b.orderedTxs(true)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d txs, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong tx at %d", i)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/dastore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// LoadBlock returns the block of block_chain. The block which keeps the commitment has no
+// transactions, they are retrieved from the data availability layer by Check and PlaySafe
+func LoadBlock(bc *sqldb.BlockChain) (*Block, error) {
+	b, err := UnmarshallBlock(bytes.NewBuffer(bc.Data), true)
+	if err != nil {
+		return nil, err
+	}
+	b.DACommitment = bc.TxData
+	if b.PrevHeader, err = GetBlockHeaderFromBlockChain(b.Header.BlockId - 1); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// offChainData stores the block in the data availability layer and returns the block without the
+// transactions and the commitment. The genesis block and the empty blocks are kept whole, the block
+// is kept whole if the layer fails too, so the failure of the layer doesn't stop the chain
+func (b *Block) offChainData() (data, commitment []byte) {
+	if dastore.DA == nil || b.IsGenesis() || len(b.TxFullData) == 0 {
+		return b.BinData, nil
+	}
+	logger := b.GetLogger()
+	commitment, err := dastore.DA.Store(b.Header.BlockId, b.BinData)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("storing block data")
+		return b.BinData, nil
+	}
+	if data, err = types.StripTxs(b.BinData); err != nil {
+		logger.WithFields(log.Fields{"type": consts.ParserError, "error": err}).Error("stripping block transactions")
+		return b.BinData, nil
+	}
+	b.DACommitment = commitment
+	return data, commitment
+}
+
+// retrieveTxs fills the transactions of the block which has the commitment only. The retrieved block
+// must be signed as the block of the commitment
+func (b *Block) retrieveTxs() error {
+	if len(b.DACommitment) == 0 || len(b.TxFullData) > 0 {
+		return nil
+	}
+	if dastore.DA == nil {
+		return wrapError("retrieving block data", dastore.ErrDisabled, KindInternalCorruption)
+	}
+	data, err := dastore.DA.Retrieve(b.DACommitment)
+	if err != nil {
+		kind := KindRetryable
+		if errors.Is(err, dastore.ErrCommitment) {
+			kind = KindInternalCorruption
+		}
+		b.GetLogger().WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("retrieving block data")
+		return wrapError("retrieving block data", err, kind)
+	}
+	full, err := UnmarshallBlock(bytes.NewBuffer(data), true)
+	if err != nil {
+		return wrapError("retrieving block data", err, KindInternalCorruption)
+	}
+	if full.ForSign() != b.ForSign() {
+		return wrapError("retrieving block data", dastore.ErrCommitment, KindInternalCorruption)
+	}
+	b.BinData, b.TxFullData = full.BinData, full.TxFullData
+	b.Transactions, b.ClassifyTxsMap = full.Transactions, full.ClassifyTxsMap
+	return nil
+}
+
+// UnmarshallBlockChain returns the block of block_chain with the transactions, they are retrieved from
+// the data availability layer if the block keeps the commitment
+func UnmarshallBlockChain(bc *sqldb.BlockChain, fill bool) (*Block, error) {
+	data, err := bc.BlockData()
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshallBlock(bytes.NewBuffer(data), fill)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/pbgo"
 	"github.com/IBAX-io/go-ibax/packages/protocols"
+	"github.com/IBAX-io/go-ibax/packages/storage/mmapstore"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
@@ -28,14 +29,27 @@ import (
 
 // ProcessBlockByBinData is processing block with in table previous block
 func ProcessBlockByBinData(data []byte, checkSize bool) (*Block, error) {
-	if checkSize && int64(len(data)) > syspar.GetMaxBlockSize() {
-		log.WithFields(log.Fields{"check_size": checkSize, "size": len(data), "max_size": syspar.GetMaxBlockSize(), "type": consts.ParameterExceeded}).Error("binary block size exceeds max block size")
-		return nil, types.ErrMaxBlockSize(syspar.GetMaxBlockSize(), len(data))
+	// the block id is unknown yet so the size is checked with the greatest scheduled max size
+	if checkSize && int64(len(data)) > syspar.GetMaxBlockSizeLimit() {
+		log.WithFields(log.Fields{"check_size": checkSize, "size": len(data), "max_size": syspar.GetMaxBlockSizeLimit(), "type": consts.ParameterExceeded}).Error("binary block size exceeds max block size")
+		return nil, types.ErrMaxBlockSize(syspar.GetMaxBlockSizeLimit(), len(data))
 	}
-	block, err := UnmarshallBlock(bytes.NewBuffer(data), true)
+	if checkSize {
+		if err := checkTxsIngress(data); err != nil {
+			return nil, err
+		}
+	}
+	block, err := UnmarshalVersioned(data)
 	if err != nil {
+		if errors.Is(err, ErrUnknownBlockVersion) {
+			return nil, err
+		}
 		return nil, errors.Wrap(types.ErrUnmarshallBlock, err.Error())
 	}
+	if maxSize := syspar.GetMaxBlockSizeAt(block.Header.BlockId); checkSize && int64(len(data)) > maxSize {
+		log.WithFields(log.Fields{"block_id": block.Header.BlockId, "size": len(data), "max_size": maxSize, "type": consts.ParameterExceeded}).Error("binary block size exceeds max block size")
+		return nil, types.ErrMaxBlockSize(maxSize, len(data))
+	}
 	block.PrevHeader, err = GetBlockHeaderFromBlockChain(block.Header.BlockId - 1)
 	if err != nil {
 		return nil, err
@@ -46,6 +60,26 @@ func ProcessBlockByBinData(data []byte, checkSize bool) (*Block, error) {
 	return block, nil
 }
 
+// checkTxsIngress checks transactions of the block with the same limits as the incoming transactions
+// before they are decoded
+func checkTxsIngress(data []byte) error {
+	bd := &types.BlockData{}
+	if err := bd.UnmarshallBlock(data); err != nil {
+		if errors.Is(err, ErrUnknownBlockVersion) {
+			return err
+		}
+		return errors.Wrap(types.ErrUnmarshallBlock, err.Error())
+	}
+	limits := transaction.GetIngressLimits()
+	for i, tx := range bd.TxFullData {
+		if err := limits.Check(tx); err != nil {
+			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err, "block_id": bd.Header.BlockId, "tx": i}).Error("block transaction is rejected")
+			return err
+		}
+	}
+	return nil
+}
+
 func (b *Block) GetRollbacksHash(dbTx *sqldb.DbTransaction) ([]byte, error) {
 	r := &sqldb.RollbackTx{}
 	diff, err := r.GetRollbacksDiff(dbTx, b.Header.BlockId)
@@ -97,7 +131,15 @@ func (b *Block) InsertIntoBlockchain(dbTx *sqldb.DbTransaction) error {
 	var rHash []byte
 	rHash, err = GetRollbacksHashWithDiffArr(dbTx, blockID)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("getting rollbacks hash")
+		b.GetLogger().WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("getting rollbacks hash")
+		return err
+	}
+	stateLeaves, err := b.commitStateRoot(dbTx)
+	if err != nil {
+		return err
+	}
+	eventsBloom, err := b.commitEventsBloom(dbTx)
+	if err != nil {
 		return err
 	}
 	if b.GenBlock {
@@ -106,10 +148,20 @@ func (b *Block) InsertIntoBlockchain(dbTx *sqldb.DbTransaction) error {
 			return err
 		}
 	}
+	binLog, err := sqldb.MarshalBinLogSQL(b.BinLogSql)
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("marshalling binlog of block")
+		return err
+	}
+	data, commitment := b.offChainData()
+	if syspar.IsBlockCompression() {
+		data = types.CompressBlock(data)
+	}
 	blockchain := &sqldb.BlockChain{
 		ID:             blockID,
 		Hash:           b.Header.BlockHash,
-		Data:           b.BinData,
+		Data:           data,
+		TxData:         commitment,
 		EcosystemID:    b.Header.EcosystemId,
 		KeyID:          b.Header.KeyId,
 		NodePosition:   b.Header.NodePosition,
@@ -118,32 +170,50 @@ func (b *Block) InsertIntoBlockchain(dbTx *sqldb.DbTransaction) error {
 		Tx:             int32(len(b.TxFullData)),
 		ConsensusMode:  b.Header.ConsensusMode,
 		CandidateNodes: b.Header.CandidateNodes,
+		BinLogSql:      binLog,
+		BaseGasPrice:   b.Header.BaseGasPrice,
+		GasUsed:        b.GasUsed,
+		RandomBeacon:   b.Header.RandomBeacon,
+		RandomCommit:   b.Header.RandomCommit,
+		StateLeaves:    stateLeaves,
+		EventsBloom:    eventsBloom,
 	}
 	var validBlockTime bool
 	if blockID > 1 && syspar.IsHonorNodeMode() {
-		validBlockTime, err = protocols.NewBlockTimeCounter().BlockForTimeExists(time.Unix(blockchain.Time, 0), int(blockchain.NodePosition))
+		var btc *protocols.BlockTimeCounter
+		if btc, err = protocols.NewBlockTimeCounter(blockID); err == nil {
+			validBlockTime, err = btc.BlockForTimeExists(time.Unix(blockchain.Time, 0), int(blockchain.NodePosition))
+		}
 		if err != nil {
-			log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("block validation")
+			b.GetLogger().WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("block validation")
 			return err
 		}
 		if validBlockTime {
 			err = fmt.Errorf("invalid block time: %d", b.Header.Timestamp)
-			log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("invalid block time")
+			b.GetLogger().WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("invalid block time")
 			return err
 		}
 	}
 
 	if err = blockchain.Create(dbTx); err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating block")
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating block")
 		return err
 	}
+	// the block store is the secondary copy with the transactions, so its failure doesn't stop the chain
+	if mmapstore.Store != nil {
+		archived := *blockchain
+		archived.Data, archived.TxData = b.BinData, nil
+		if err := mmapstore.Store.Write(&archived); err != nil {
+			b.GetLogger().WithFields(log.Fields{"type": consts.IOError, "error": err, "block_id": blockID}).Error("writing block to block store")
+		}
+	}
 	if err := b.upsertInfoBlock(dbTx, blockchain); err != nil {
 		return err
 	}
 	if b.SysUpdate {
 		b.SysUpdate = false
 		if err := syspar.SysUpdate(dbTx); err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+			b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
 			return err
 		}
 	}
@@ -222,13 +292,13 @@ func (b *Block) upsertInfoBlock(dbTx *sqldb.DbTransaction, block *sqldb.BlockCha
 		ib.CurrentVersion = fmt.Sprintf("%d", consts.BlockVersion)
 		err := ib.Create(dbTx)
 		if err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating info block")
+			b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("creating info block")
 			return fmt.Errorf("error insert into info_block %s", err)
 		}
 	} else {
 		ib.Sent = 0
 		if err := ib.Update(dbTx); err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating info block")
+			b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating info block")
 			return fmt.Errorf("error while updating info_block %s", err)
 		}
 	}
@@ -300,6 +370,9 @@ func (b *Block) AfterPlayTxs(dbTx *sqldb.DbTransaction) error {
 		if err := sqldb.CreateLogTransactionBatches(tx, playTx.Lts); err != nil {
 			return errors.Wrap(err, "batches insert log_transactions")
 		}
+		if err := sqldb.CreateTxReceiptBatches(tx, b.Receipts); err != nil {
+			return errors.Wrap(err, "batches insert tx_receipts")
+		}
 		spentInfos := sqldb.GetAllOutputs(b.OutputsMap)
 		if len(spentInfos) > 0 {
 			if err := sqldb.CreateSpentInfoBatches(tx, spentInfos); err != nil {
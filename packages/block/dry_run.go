@@ -0,0 +1,128 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// DryRunTx is the queued transaction of the dry run of the block
+type DryRunTx struct {
+	Hash   string `json:"hash"`
+	KeyID  int64  `json:"key_id"`
+	Error  string `json:"error,omitempty"`
+	Banned bool   `json:"banned,omitempty"` // the key is banned by the bad transactions of the block
+	spent  bool
+	reason transaction.BanReason
+}
+
+// DryRunReport is the result of the dry run of the block which the node would generate. The rejected
+// transactions would be marked bad, the skipped ones stay in the queue for the next blocks
+type DryRunReport struct {
+	BlockID  int64       `json:"block_id"`
+	Queued   int         `json:"queued"`
+	Included []*DryRunTx `json:"included"`
+	Rejected []*DryRunTx `json:"rejected"`
+	Skipped  []*DryRunTx `json:"skipped"`
+	GasUsed  int64       `json:"gas_used"`
+	Error    string      `json:"error,omitempty"` // the error of the play which rejects the block
+}
+
+// NewDryRunReport returns the empty report of the block
+func NewDryRunReport(blockID int64) *DryRunReport {
+	return &DryRunReport{BlockID: blockID, Included: []*DryRunTx{}, Rejected: []*DryRunTx{}, Skipped: []*DryRunTx{}}
+}
+
+// Reject adds the transaction which would be marked bad
+func (r *DryRunReport) Reject(hash []byte, keyID int64, err error) {
+	r.Rejected = append(r.Rejected, &DryRunTx{Hash: fmt.Sprintf("%x", hash), KeyID: keyID, Error: err.Error(),
+		reason: transaction.BanReasonQueue})
+}
+
+// Skip adds the transaction which would stay in the queue
+func (r *DryRunReport) Skip(hash []byte, keyID int64) {
+	r.Skipped = append(r.Skipped, &DryRunTx{Hash: fmt.Sprintf("%x", hash), KeyID: keyID})
+}
+
+// IsRejected returns true if the transaction is rejected
+func (r *DryRunReport) IsRejected(hash []byte) bool {
+	hexHash := fmt.Sprintf("%x", hash)
+	for _, tx := range r.Rejected {
+		if tx.Hash == hexHash {
+			return true
+		}
+	}
+	return false
+}
+
+// markBans sets Banned of the rejected transactions whose keys would be banned by them
+func (r *DryRunReport) markBans() {
+	bad := make(map[int64]map[transaction.BanReason]int)
+	for _, tx := range r.Rejected {
+		if bad[tx.KeyID] == nil {
+			bad[tx.KeyID] = make(map[transaction.BanReason]int)
+		}
+		if !tx.spent {
+			bad[tx.KeyID][tx.reason]++
+		}
+	}
+	for _, tx := range r.Rejected {
+		tx.Banned = transaction.WouldBeBanned(tx.KeyID, bad[tx.KeyID])
+	}
+}
+
+// DryRun plays the classified transactions of the block like the generated block within the db transaction
+// which is rolled back. The bad transactions aren't marked and their keys aren't banned, the contracts of the
+// vm and the platform parameters are restored after the play. The caller must hold the lock of the chain
+func DryRun(ctx context.Context, header, prev *types.BlockHeader, classifyTxsMap map[int][]*transaction.Transaction, report *DryRunReport) error {
+	dbTx, err := sqldb.StartTransaction()
+	if err != nil {
+		return dbError("starting db transaction", err)
+	}
+	script.SavepointSmartVMObjects()
+	defer func() {
+		dbTx.Rollback()
+		script.RollbackSmartVMObjects()
+		if errSys := syspar.SysUpdate(nil); errSys != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": errSys}).Error("updating syspar")
+		}
+	}()
+	b := &Block{BlockData: &types.BlockData{Header: header, PrevHeader: prev}, GenBlock: true, ClassifyTxsMap: classifyTxsMap}
+	b.Transactions = b.orderedTxs(syspar.IsTxTypeOrderAt(b.Header.BlockId))
+	b.replay = true
+	err = b.processTxs(ctx, dbTx)
+	b.replay = false
+
+	played := make(map[string]bool, len(b.TxFullData))
+	for _, data := range b.TxFullData {
+		played[string(data)] = true
+	}
+	for _, bad := range b.rejected {
+		report.Rejected = append(report.Rejected, &DryRunTx{Hash: fmt.Sprintf("%x", bad.hash), KeyID: bad.keyID, Error: bad.msg,
+			spent: bad.spent, reason: transaction.BanReasonBlock})
+	}
+	for _, t := range b.Transactions {
+		switch {
+		case played[string(t.FullData)]:
+			report.Included = append(report.Included, &DryRunTx{Hash: fmt.Sprintf("%x", t.Hash()), KeyID: t.KeyID()})
+		case !report.IsRejected(t.Hash()):
+			// the transactions after the limits of the block aren't played
+			report.Skip(t.Hash(), t.KeyID())
+		}
+	}
+	report.GasUsed = b.GasUsed
+	report.markBans()
+	return err
+}
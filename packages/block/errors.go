@@ -0,0 +1,155 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrorKind is the category of the failure of the block playing
+type ErrorKind int
+
+const (
+	// KindInvalidBlock is the deterministic failure, the block must be rejected
+	KindInvalidBlock ErrorKind = iota
+	// KindRetryable is the transient failure of the database, the same block can be played again
+	KindRetryable
+	// KindInternalCorruption is the failure of the node state, the block and the peer aren't at fault
+	KindInternalCorruption
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindRetryable:
+		return "retryable"
+	case KindInternalCorruption:
+		return "internal corruption"
+	}
+	return "invalid block"
+}
+
+// PlayError is the failure of the block playing with its category
+type PlayError struct {
+	Kind ErrorKind
+	Op   string
+	Err  error
+}
+
+func (e *PlayError) Error() string { return fmt.Sprintf("%s: %v", e.Op, e.Err) }
+func (e *PlayError) Unwrap() error { return e.Err }
+
+// Cause keeps errors.Cause of github.com/pkg/errors working for the wrapped errors
+func (e *PlayError) Cause() error { return e.Err }
+
+// BadTxError is the bad transaction which aborts the block generated in the strict mode. The
+// transaction is marked bad, the other ones stay in the queue
+type BadTxError struct {
+	Hash []byte
+	Err  error
+}
+
+func (e *BadTxError) Error() string {
+	return fmt.Sprintf("bad transaction %x: %v", e.Hash, e.Err)
+}
+
+func (e *BadTxError) Unwrap() error { return e.Err }
+
+// PanicError is the recovered panic of the played transaction, Hash is empty if the panic is out
+// of the transactions of the group
+type PanicError struct {
+	Hash  []byte
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	if len(e.Hash) == 0 {
+		return fmt.Sprintf("panic in transaction group: %v", e.Value)
+	}
+	return fmt.Sprintf("panic in transaction %x: %v", e.Hash, e.Value)
+}
+
+// ErrorKindOf returns the category of err. The errors which aren't wrapped are classified by
+// the postgres error code, the other ones invalidate the block
+func ErrorKindOf(err error) ErrorKind {
+	var pe *PlayError
+	if errors.As(err, &pe) {
+		return pe.Kind
+	}
+	return classifyError(err, KindInvalidBlock)
+}
+
+// IsRetryable returns true if the same block can be played again after err
+func IsRetryable(err error) bool {
+	return err != nil && ErrorKindOf(err) == KindRetryable
+}
+
+// wrapError wraps err of op with its category, kind is used if err isn't the database failure
+func wrapError(op string, err error, kind ErrorKind) error {
+	if err == nil {
+		return nil
+	}
+	var pe *PlayError
+	if errors.As(err, &pe) {
+		return err
+	}
+	return &PlayError{Kind: classifyError(err, kind), Op: op, Err: err}
+}
+
+// dbError wraps the failure of the database operation
+func dbError(op string, err error) error {
+	return wrapError(op, err, KindInternalCorruption)
+}
+
+func classifyError(err error, kind ErrorKind) ErrorKind {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001", // serialization_failure
+			pgErr.Code == "40P01",                // deadlock_detected
+			pgErr.Code == "55P03",                // lock_not_available
+			pgErr.Code == "57014",                // query_canceled
+			strings.HasPrefix(pgErr.Code, "08"),  // connection_exception
+			strings.HasPrefix(pgErr.Code, "53"),  // insufficient_resources, disk_full
+			strings.HasPrefix(pgErr.Code, "57P"): // operator_intervention
+			return KindRetryable
+		case strings.HasPrefix(pgErr.Code, "XX"): // internal_error, data_corrupted
+			return KindInternalCorruption
+		}
+		return kind
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, syscall.ENOSPC) || pgconn.SafeToRetry(err) {
+		return KindRetryable
+	}
+	return kind
+}
+
+// PlayRetry calls play until it succeeds, fails with the error which isn't retryable,
+// the attempts are over or ctx is done. The delay doubles after every retryable failure
+func PlayRetry(ctx context.Context, attempts int, delay time.Duration, play func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		if err = play(); !IsRetryable(err) {
+			return err
+		}
+	}
+	return err
+}
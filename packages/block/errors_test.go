@@ -0,0 +1,134 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto/asymalgo"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestErrorKindOf(t *testing.T) {
+	serialization := &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+	for _, c := range []struct {
+		err  error
+		kind ErrorKind
+	}{
+		{wrapError("processing transactions", fmt.Errorf("playing: %w", serialization), KindInvalidBlock), KindRetryable},
+		{dbError("committing db transaction", &pgconn.PgError{Code: "53100"}), KindRetryable},
+		{dbError("using savepoint", &pgconn.PgError{Code: "XX001"}), KindInternalCorruption},
+		{dbError("starting db transaction", errors.New("connection refused")), KindInternalCorruption},
+		{wrapError("processing transactions", &pgconn.PgError{Code: "23505"}, KindInvalidBlock), KindInvalidBlock},
+		{wrapError("processing transactions", asymalgo.ErrIncorrectSign, KindInvalidBlock), KindInvalidBlock},
+		{serialization, KindRetryable},
+	} {
+		if kind := ErrorKindOf(c.err); kind != c.kind {
+			t.Errorf("%v: expected %s, got %s", c.err, c.kind, kind)
+		}
+	}
+
+	// the wrapped errors are still found by errors.Is and errors.Cause
+	ban := utils.WithBan(ErrIncorrectBlockTime)
+	err := wrapError("inserting block", ban, KindInvalidBlock)
+	if !utils.IsBanError(err) || pkgerrors.Cause(err) != ban {
+		t.Error("ban error is lost")
+	}
+	if err = wrapError("processing transactions", dbError("using savepoint", serialization), KindInvalidBlock); err.Error() != "using savepoint: "+serialization.Error() {
+		t.Errorf("error is wrapped twice: %v", err)
+	}
+}
+
+func TestPlayRetry(t *testing.T) {
+	var calls int
+	err := PlayRetry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls == 1 {
+			// simulated postgres serialization failure of the transaction in the block
+			return wrapError("processing transactions", &pgconn.PgError{Code: "40001"}, KindInvalidBlock)
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected success after retry, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = PlayRetry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		return wrapError("processing transactions", asymalgo.ErrIncorrectSign, KindInvalidBlock)
+	})
+	if !errors.Is(err, asymalgo.ErrIncorrectSign) || calls != 1 {
+		t.Errorf("invalid signature must not be retried, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = PlayRetry(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		return dbError("committing db transaction", &pgconn.PgError{Code: "40P01"})
+	})
+	if !IsRetryable(err) || calls != 3 {
+		t.Errorf("expected 3 attempts, got %v after %d calls", err, calls)
+	}
+}
+
+func TestBadTxError(t *testing.T) {
+	defer func(c conf.StrictBlockConfig) { conf.Config.StrictBlock = c }(conf.Config.StrictBlock)
+	tx := newCustomTx(t, 20)
+	for _, c := range []struct {
+		gen, strict, bad bool
+	}{
+		{false, false, false},
+		{false, true, false},
+		{true, false, false},
+		{true, true, true},
+	} {
+		conf.Config.StrictBlock.Enabled = c.strict
+		b := mustBuild(t, newTestBuilder(10).AddTransaction(tx).WithGenBlock(c.gen))
+		err := wrapError("processing transactions", b.badTxError(tx, asymalgo.ErrIncorrectSign), KindInvalidBlock)
+		var badTx *BadTxError
+		if errors.As(err, &badTx) != c.bad {
+			t.Errorf("gen %v, strict %v: unexpected error %v", c.gen, c.strict, err)
+		}
+		if c.bad && string(badTx.Hash) != string(tx.Hash()) {
+			t.Errorf("wrong hash of bad transaction %x", badTx.Hash)
+		}
+		if !errors.Is(err, asymalgo.ErrIncorrectSign) {
+			t.Errorf("the error of transaction is lost: %v", err)
+		}
+	}
+}
+
+func TestCanceledTx(t *testing.T) {
+	played := errors.New("time limit exceeded")
+	ctx, cancel := context.WithCancel(context.Background())
+	txCtx, txCancel := context.WithTimeout(ctx, time.Nanosecond)
+	defer txCancel()
+	<-txCtx.Done()
+	err := canceledTx(ctx, txCtx, played)
+	var ce *TxCanceledError
+	if !errors.As(err, &ce) || !errors.Is(err, played) || IsRetryable(err) {
+		t.Fatalf("expired transaction: %v", err)
+	}
+	if ce.status() != `{"type":"canceled","error":"`+ce.Reason+`"}` {
+		t.Errorf("wrong status %s", ce.status())
+	}
+	// the canceled play of the block doesn't make the transaction bad
+	cancel()
+	if err = canceledTx(ctx, txCtx, played); !IsRetryable(err) {
+		t.Errorf("canceled block: %v", err)
+	}
+	if canceledTx(context.Background(), context.Background(), played) != nil {
+		t.Error("transaction without done context is canceled")
+	}
+}
@@ -0,0 +1,116 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrEventsBloom is returned if the bloom of the events of the block differs from the events of its transactions
+var ErrEventsBloom = errors.New("events bloom of the block doesn't match")
+
+// MaxBloomBlocks is the max count of the blocks which are scanned by the bloom of the events at once
+const MaxBloomBlocks = 10000
+
+// EventsBloom returns the bloom of the topics of the events
+func EventsBloom(events []sqldb.ContractEvent) types.Bloom {
+	var bloom types.Bloom
+	for _, e := range events {
+		for _, topic := range types.EventTopics(e.EcosystemID, e.ContractName, e.EventName, e.KeyID) {
+			bloom.Add(topic)
+		}
+	}
+	return bloom
+}
+
+// commitEventsBloom sets the bloom of the events of the generated block or checks the bloom of the played
+// block, it returns the bloom which is stored with the block
+func (b *Block) commitEventsBloom(dbTx *sqldb.DbTransaction) ([]byte, error) {
+	h := b.Header
+	if !syspar.IsEventsBloomAt(h.BlockId) {
+		if !b.GenBlock && len(h.EventsBloom) > 0 {
+			return nil, fmt.Errorf("%w: events bloom is disabled", ErrEventsBloom)
+		}
+		return nil, nil
+	}
+	events, err := sqldb.GetBlockContractEvents(dbTx, h.BlockId)
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting contract events of block")
+		return nil, err
+	}
+	bloom := EventsBloom(events)
+	if b.GenBlock {
+		h.EventsBloom = bloom.Bytes()
+	} else if !bytes.Equal(h.EventsBloom, bloom.Bytes()) {
+		b.GetLogger().WithFields(log.Fields{"type": consts.BlockError, "block_id": h.BlockId, "events": len(events)}).Error("events bloom diverged")
+		return nil, fmt.Errorf("%w: block %d", ErrEventsBloom, h.BlockId)
+	}
+	return h.EventsBloom, nil
+}
+
+// BloomFilter is the topics of the events which are searched in the blooms of the blocks, the zero fields
+// aren't checked. Contract and Event are checked within the ecosystem
+type BloomFilter struct {
+	EcosystemID int64
+	Contract    string
+	Event       string
+	KeyID       int64
+}
+
+// topics returns the topics which must be in the bloom
+func (f BloomFilter) topics() [][]byte {
+	var topics [][]byte
+	switch {
+	case len(f.Event) > 0:
+		topics = append(topics, types.EventTopic(f.EcosystemID, f.Contract, f.Event))
+	case len(f.Contract) > 0:
+		topics = append(topics, types.ContractTopic(f.EcosystemID, f.Contract))
+	case f.EcosystemID > 0:
+		topics = append(topics, types.EcosystemTopic(f.EcosystemID))
+	}
+	if f.KeyID != 0 {
+		topics = append(topics, types.AddressTopic(f.KeyID))
+	}
+	return topics
+}
+
+// Match returns true if the events of the filter may be in the bloom, the bloom of the block without
+// the events doesn't match
+func (f BloomFilter) Match(bloom types.Bloom) bool {
+	if bloom == (types.Bloom{}) {
+		return false
+	}
+	for _, topic := range f.topics() {
+		if !bloom.Test(topic) {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanEventsBlooms returns the blocks from fromID to toID inclusive whose blooms match the filter. The blocks
+// may have no events of the filter as the bloom has the false positives, the blocks without the bloom are skipped
+func ScanEventsBlooms(fromID, toID int64, filter BloomFilter) ([]int64, error) {
+	blooms, err := sqldb.GetEventsBlooms(fromID, toID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0)
+	for _, b := range blooms {
+		if filter.Match(types.BytesToBloom(b.EventsBloom)) {
+			ids = append(ids, b.ID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,49 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestEventsBloom(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	bloom := EventsBloom([]sqldb.ContractEvent{
+		{EcosystemID: 1, ContractName: "@1TokenTransfer", EventName: "Transfer", KeyID: 100},
+		{EcosystemID: 2, ContractName: "@2Vote", EventName: "Voted", KeyID: -200},
+	})
+	if restored := types.BytesToBloom(bloom.Bytes()); restored != bloom {
+		t.Fatal("bloom isn't restored from bytes")
+	}
+	for _, f := range []BloomFilter{
+		{},
+		{EcosystemID: 1},
+		{EcosystemID: 1, Contract: "@1TokenTransfer", Event: "Transfer"},
+		{EcosystemID: 2, Contract: "@2Vote", KeyID: -200},
+		{KeyID: 100},
+	} {
+		if !f.Match(bloom) {
+			t.Errorf("filter %+v doesn't match", f)
+		}
+	}
+	for _, f := range []BloomFilter{
+		{EcosystemID: 3},
+		{EcosystemID: 2, Contract: "@1TokenTransfer"},
+		{EcosystemID: 1, Contract: "@1TokenTransfer", Event: "Voted"},
+		{KeyID: 300},
+	} {
+		if f.Match(bloom) {
+			t.Errorf("filter %+v matches", f)
+		}
+	}
+	if (BloomFilter{}).Match(types.Bloom{}) {
+		t.Error("bloom of the block without events matches")
+	}
+}
@@ -0,0 +1,258 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	log "github.com/sirupsen/logrus"
+)
+
+// The kinds of the records of the block trace
+const (
+	TraceSavepoint = "savepoint" // the savepoint of the transaction is set
+	TraceRollback  = "rollback"  // the transaction is failed and rolled back to its savepoint
+	TraceSQL       = "sql"       // the statement which is executed by the transaction
+	TraceFuel      = "fuel"      // the fuel which is charged for the contract
+	TraceInput     = "input"     // the utxo which is spent by the transaction
+	TraceOutput    = "output"    // the utxo which is created by the transaction
+	TraceCall      = "call"      // the contract which is called by the other contract
+)
+
+const (
+	traceHeader = "ibax-block-trace 1"
+	// maxTraceLine is the limit of the record, the statement could contain the source of the contract
+	maxTraceLine = 64 << 20
+)
+
+var errTraceFormat = errors.New("invalid block trace")
+
+// TraceRecord is the record of the execution trace of the block
+type TraceRecord struct {
+	TxHash []byte
+	Kind   string
+	Data   string
+}
+
+func (r TraceRecord) String() string {
+	return fmt.Sprintf("%s %s", r.Kind, r.Data)
+}
+
+// Trace is the canonical execution trace of the block, the records are ordered by the transactions
+// of the block, so the trace is the same on the nodes which agree about the block
+type Trace struct {
+	BlockID int64
+	Hash    []byte
+	Records []TraceRecord
+}
+
+// blockTrace collects the records of the played transactions, the groups of the transactions could
+// be played concurrently
+type blockTrace struct {
+	mu  sync.Mutex
+	txs map[string][]TraceRecord
+}
+
+// newBlockTrace returns nil if the trace is disabled and isn't forced
+func newBlockTrace(force bool) *blockTrace {
+	if !force && len(conf.Config.BlockTracePath) == 0 {
+		return nil
+	}
+	return &blockTrace{txs: make(map[string][]TraceRecord)}
+}
+
+func (bt *blockTrace) add(hash []byte, kind, data string) {
+	if bt == nil {
+		return
+	}
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	bt.txs[string(hash)] = append(bt.txs[string(hash)], TraceRecord{TxHash: hash, Kind: kind, Data: data})
+}
+
+// drop removes the records of the transaction which is played again
+func (bt *blockTrace) drop(hash []byte) {
+	if bt == nil {
+		return
+	}
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	delete(bt.txs, string(hash))
+}
+
+// addPlayed adds the changes of the successfully played transaction
+func (bt *blockTrace) addPlayed(t *transaction.Transaction) {
+	if bt == nil {
+		return
+	}
+	for _, stmt := range t.DbTransaction.BinLogSql {
+		bt.add(t.Hash(), TraceSQL, string(stmt))
+	}
+	bt.addCalls(t)
+	if t.IsSmartContract() {
+		bt.add(t.Hash(), TraceFuel, strconv.FormatInt(t.SmartContract().TxFuel, 10))
+	}
+	bt.addUTXO(t.Hash(), TraceInput, t.OutCtx.TxInputsMap)
+	bt.addUTXO(t.Hash(), TraceOutput, t.OutCtx.TxOutputsMap)
+}
+
+// addCalls adds the nested calls of the contracts in the order they are finished, the record is the depth,
+// the name and the fuel of the call with its nested calls, the failed call has the error
+func (bt *blockTrace) addCalls(t *transaction.Transaction) {
+	if bt == nil || !t.IsSmartContract() {
+		return
+	}
+	for _, call := range t.SmartContract().Calls {
+		data := fmt.Sprintf("%d %s %d", call.Depth, call.Name, call.Fuel)
+		if call.Err != nil {
+			data += " " + call.Err.Error()
+		}
+		bt.add(t.Hash(), TraceCall, data)
+	}
+}
+
+func (bt *blockTrace) addUTXO(hash []byte, kind string, m map[sqldb.KeyUTXO][]sqldb.SpentInfo) {
+	keys := make([]sqldb.KeyUTXO, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Ecosystem != keys[j].Ecosystem {
+			return keys[i].Ecosystem < keys[j].Ecosystem
+		}
+		return keys[i].KeyId < keys[j].KeyId
+	})
+	for _, key := range keys {
+		for _, info := range m[key] {
+			bt.add(hash, kind, fmt.Sprintf("%d@%d %x:%d %d %s", key.Ecosystem, key.KeyId,
+				info.OutputTxHash, info.OutputIndex, info.OutputKeyId, info.OutputValue))
+		}
+	}
+}
+
+// collect returns the records in the order of the transactions of the block
+func (bt *blockTrace) collect(blockID int64, hash []byte, txs []*transaction.Transaction) *Trace {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	tr := &Trace{BlockID: blockID, Hash: hash}
+	for _, t := range txs {
+		tr.Records = append(tr.Records, bt.txs[string(t.Hash())]...)
+	}
+	return tr
+}
+
+// writeTrace saves the trace of the committed block to BlockTracePath, the file is named by the block hash
+func (b *Block) writeTrace() {
+	if b.execTrace == nil {
+		return
+	}
+	tr := b.execTrace.collect(b.Header.BlockId, b.Header.BlockHash, b.Transactions)
+	b.execTrace = nil
+	if err := WriteTraceFile(TraceFilePath(conf.Config.BlockTracePath, tr.Hash), tr); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing block trace")
+	}
+}
+
+// TraceFilePath returns the file of the trace of the block with the hash
+func TraceFilePath(dir string, hash []byte) string {
+	return filepath.Join(dir, hex.EncodeToString(hash)+".trace")
+}
+
+// WriteTrace writes the trace as the lines of the records, the data is quoted
+func WriteTrace(w io.Writer, tr *Trace) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %d %x\n", traceHeader, tr.BlockID, tr.Hash)
+	for _, r := range tr.Records {
+		fmt.Fprintf(bw, "%x %s %s\n", r.TxHash, r.Kind, strconv.Quote(r.Data))
+	}
+	return bw.Flush()
+}
+
+// WriteTraceFile writes the trace to the file
+func WriteTraceFile(path string, tr *Trace) error {
+	var buf bytes.Buffer
+	if err := WriteTrace(&buf, tr); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ReadTrace reads the trace which is written by WriteTrace
+func ReadTrace(r io.Reader) (*Trace, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxTraceLine)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("%w: empty", errTraceFormat)
+	}
+	tr := &Trace{}
+	var hash string
+	if _, err := fmt.Sscanf(strings.TrimPrefix(scanner.Text(), traceHeader), " %d %s", &tr.BlockID, &hash); err != nil ||
+		!strings.HasPrefix(scanner.Text(), traceHeader+" ") {
+		return nil, fmt.Errorf("%w: header", errTraceFormat)
+	}
+	var err error
+	if tr.Hash, err = hex.DecodeString(hash); err != nil {
+		return nil, fmt.Errorf("%w: header", errTraceFormat)
+	}
+	for line := 2; scanner.Scan(); line++ {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: line %d", errTraceFormat, line)
+		}
+		rec := TraceRecord{Kind: fields[1]}
+		if rec.TxHash, err = hex.DecodeString(fields[0]); err != nil {
+			return nil, fmt.Errorf("%w: line %d", errTraceFormat, line)
+		}
+		if rec.Data, err = strconv.Unquote(fields[2]); err != nil {
+			return nil, fmt.Errorf("%w: line %d", errTraceFormat, line)
+		}
+		tr.Records = append(tr.Records, rec)
+	}
+	return tr, scanner.Err()
+}
+
+// ReadTraceFile reads the trace from the file
+func ReadTraceFile(path string) (*Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTrace(f)
+}
+
+// CompareTraces returns the index of the first record which differs, -1 if the traces are the same.
+// The record is nil if the trace is shorter
+func CompareTraces(a, b *Trace) (int, *TraceRecord, *TraceRecord) {
+	for i := 0; i < len(a.Records) || i < len(b.Records); i++ {
+		var ra, rb *TraceRecord
+		if i < len(a.Records) {
+			ra = &a.Records[i]
+		}
+		if i < len(b.Records) {
+			rb = &b.Records[i]
+		}
+		if ra == nil || rb == nil || !bytes.Equal(ra.TxHash, rb.TxHash) || ra.Kind != rb.Kind || ra.Data != rb.Data {
+			return i, ra, rb
+		}
+	}
+	return -1, nil, nil
+}
@@ -0,0 +1,93 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+)
+
+func TestTraceRoundTrip(t *testing.T) {
+	tr := &Trace{BlockID: 12, Hash: []byte{0xab, 0xcd}, Records: []TraceRecord{
+		{TxHash: []byte{1}, Kind: TraceSavepoint},
+		{TxHash: []byte{1}, Kind: TraceSQL, Data: "UPDATE \"1_keys\" SET \"name\"='it''s\nmine' WHERE \"id\" = '2'"},
+		{TxHash: []byte{1}, Kind: TraceFuel, Data: "1200"},
+		{TxHash: []byte{2}, Kind: TraceRollback},
+	}}
+	var buf bytes.Buffer
+	if err := WriteTrace(&buf, tr); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(tr.Records)+1 {
+		t.Errorf("expected a line per record, got %d lines", lines)
+	}
+	got, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tr) {
+		t.Errorf("expected %+v got %+v", tr, got)
+	}
+
+	if _, err = ReadTrace(strings.NewReader("block 12 abcd\n")); !errors.Is(err, errTraceFormat) {
+		t.Errorf("expected %v got %v", errTraceFormat, err)
+	}
+	if _, err = ReadTrace(strings.NewReader(traceHeader + " 12 abcd\n01 sql unquoted\n")); !errors.Is(err, errTraceFormat) {
+		t.Errorf("expected %v got %v", errTraceFormat, err)
+	}
+}
+
+func TestCompareTraces(t *testing.T) {
+	a := &Trace{Records: []TraceRecord{
+		{TxHash: []byte{1}, Kind: TraceSavepoint},
+		{TxHash: []byte{1}, Kind: TraceFuel, Data: "100"},
+	}}
+	b := &Trace{Records: append([]TraceRecord{}, a.Records...)}
+	if i, _, _ := CompareTraces(a, b); i != -1 {
+		t.Errorf("expected identical traces, got %d", i)
+	}
+	b.Records[1].Data = "101"
+	if i, ra, rb := CompareTraces(a, b); i != 1 || ra.Data != "100" || rb.Data != "101" {
+		t.Errorf("expected divergence at 1, got %d", i)
+	}
+	b.Records = b.Records[:1]
+	if i, ra, rb := CompareTraces(a, b); i != 1 || ra == nil || rb != nil {
+		t.Errorf("expected the shorter trace at 1, got %d", i)
+	}
+}
+
+func TestTraceCalls(t *testing.T) {
+	sc := &smart.SmartContract{Hash: []byte{1}, TxContract: &smart.Contract{StackCont: []any{"@1Outer"}}}
+	sc.RecordCall("@1Skipped", 10, nil)
+	sc.TraceCalls = true
+	sc.TxContract.StackCont = append(sc.TxContract.StackCont, "@1Middle")
+	sc.RecordCall("@1Inner", 30, errors.New("denied"))
+	sc.TxContract.StackCont = sc.TxContract.StackCont[:1]
+	sc.RecordCall("@1Middle", 120, nil)
+	if want := []script.ContractCall{{Name: "@1Inner", Depth: 3, Fuel: 30, Err: sc.Calls[0].Err},
+		{Name: "@1Middle", Depth: 2, Fuel: 120}}; !reflect.DeepEqual(sc.Calls, want) {
+		t.Fatalf("expected %+v got %+v", want, sc.Calls)
+	}
+
+	bt := newBlockTrace(true)
+	tx := &transaction.Transaction{Inner: &transaction.SmartTransactionParser{SmartContract: sc}}
+	bt.addCalls(tx)
+	tr := bt.collect(1, nil, []*transaction.Transaction{tx})
+	want := []TraceRecord{
+		{TxHash: []byte{1}, Kind: TraceCall, Data: "3 @1Inner 30 denied"},
+		{TxHash: []byte{1}, Kind: TraceCall, Data: "2 @1Middle 120"},
+	}
+	if !reflect.DeepEqual(tr.Records, want) {
+		t.Errorf("expected %+v got %+v", want, tr.Records)
+	}
+}
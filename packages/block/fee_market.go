@@ -0,0 +1,86 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+)
+
+// baseGasPriceDenominator bounds the change of the base gas price by 1/8 for the block
+const baseGasPriceDenominator = 8
+
+// adjustBaseGasPrice returns the base gas price after the block of base which has used the fuel of
+// gasUsed, the fuel of the full block is limit. The price rises if the block is more than half full
+// and falls if it's less than half full
+func adjustBaseGasPrice(base, gasUsed, limit, min, max int64) int64 {
+	target := limit / 2
+	if target > 0 {
+		// newBase = base * (1 + (gasUsed - target) / target / 8)
+		delta := new(big.Int).Mul(big.NewInt(base), big.NewInt(gasUsed-target))
+		delta.Quo(delta, big.NewInt(target*baseGasPriceDenominator))
+		if delta.Sign() == 0 && gasUsed > target {
+			delta.SetInt64(1)
+		}
+		next := delta.Add(delta, big.NewInt(base))
+		if !next.IsInt64() {
+			return max
+		}
+		base = next.Int64()
+	}
+	if base < min {
+		return min
+	}
+	if base > max {
+		return max
+	}
+	return base
+}
+
+// NextBaseGasPrice returns the base gas price of blockID, prev is the previous block. It's zero if
+// the fee market is disabled, the first block of the market has BaseGasPriceUnit
+func NextBaseGasPrice(blockID int64, prev *sqldb.BlockChain) int64 {
+	min, max := syspar.GetBaseGasPriceRangeAt(blockID)
+	if max == 0 {
+		return 0
+	}
+	if prev.BaseGasPrice == 0 {
+		return adjustBaseGasPrice(syspar.BaseGasPriceUnit, 0, 0, min, max)
+	}
+	return adjustBaseGasPrice(prev.BaseGasPrice, prev.GasUsed, syspar.GetMaxBlockFuel(), min, max)
+}
+
+// BaseGasPriceOf returns the base gas price of blockID by the previous block of block_chain
+func BaseGasPriceOf(blockID int64) (int64, error) {
+	if blockID <= 1 {
+		return 0, nil
+	}
+	prev := &sqldb.BlockChain{}
+	found, err := prev.Get(blockID - 1)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("block %d isn't found", blockID-1)
+	}
+	return NextBaseGasPrice(blockID, prev), nil
+}
+
+// checkBaseGasPrice rejects the block if its base gas price isn't adjusted by the previous block
+func (b *Block) checkBaseGasPrice() error {
+	expected, err := BaseGasPriceOf(b.Header.BlockId)
+	if err != nil {
+		return err
+	}
+	if b.Header.BaseGasPrice != expected {
+		return utils.WithBan(fmt.Errorf("%w: %d, expected %d", ErrBaseGasPrice, b.Header.BaseGasPrice, expected))
+	}
+	return nil
+}
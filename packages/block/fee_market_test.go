@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"math"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestAdjustBaseGasPrice(t *testing.T) {
+	const limit = 1000
+	for _, c := range []struct {
+		base, gasUsed, limit, min, max, expected int64
+	}{
+		{1000000, limit, limit, 1, 1e9, 1125000},     // full block rises by 1/8
+		{1000000, 0, limit, 1, 1e9, 875000},          // empty block falls by 1/8
+		{1000000, limit / 2, limit, 1, 1e9, 1000000}, // target usage keeps the price
+		{1000000, 750, limit, 1, 1e9, 1062500},
+		{7, limit/2 + 1, limit, 1, 1e9, 8},           // the price rises by one at least
+		{1000000, limit, limit, 1, 1100000, 1100000}, // clamped by max
+		{100, 0, limit, 90, 1e9, 90},                 // clamped by min
+		{1000, 500, 0, 2000, 5000, 2000},             // no fuel limit keeps the clamped price
+		{math.MaxInt64 / 2, limit, limit, 1, math.MaxInt64, math.MaxInt64/2 + math.MaxInt64/16},
+	} {
+		if got := adjustBaseGasPrice(c.base, c.gasUsed, c.limit, c.min, c.max); got != c.expected {
+			t.Errorf("base %d, used %d: expected %d, got %d", c.base, c.gasUsed, c.expected, got)
+		}
+	}
+	// the prices of the blocks converge to the range
+	base := int64(1000000)
+	for i := 0; i < 100; i++ {
+		base = adjustBaseGasPrice(base, limit, limit, 1, 2000000)
+	}
+	if base != 2000000 {
+		t.Errorf("expected the max price after the full blocks, got %d", base)
+	}
+}
+
+func TestBaseGasPriceSign(t *testing.T) {
+	prev := &types.BlockHeader{BlockId: 1, BlockHash: []byte{1}}
+	header := &types.BlockHeader{BlockId: 2, Timestamp: 10, Version: 1}
+	legacy := header.ForSign(prev, nil)
+	header.BaseGasPrice = 1125000
+	if signed := header.ForSign(prev, nil); signed == legacy || signed != legacy+",1125000" {
+		t.Errorf("the base gas price isn't signed: %s", signed)
+	}
+	data, err := header.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &types.BlockHeader{}
+	if err = got.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.BaseGasPrice != header.BaseGasPrice {
+		t.Errorf("expected base gas price %d, got %d", header.BaseGasPrice, got.BaseGasPrice)
+	}
+}
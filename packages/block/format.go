@@ -0,0 +1,40 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrUnknownBlockVersion is returned if the block binary has the format which is newer than the node knows.
+// The block isn't bad, it's requested from the other node
+var ErrUnknownBlockVersion = types.ErrUnknownBlockVersion
+
+// UnmarshalVersioned returns the block of the binary in any known format, the decoder is chosen by
+// the version byte of the binary
+func UnmarshalVersioned(data []byte) (*Block, error) {
+	if version, _ := types.BlockFormatOf(data); !types.IsKnownBlockFormat(version) {
+		log.WithFields(log.Fields{"type": consts.UnmarshallingError, "version": version, "max_version": types.MaxBlockFormat}).
+			Warn("unknown block format")
+		return nil, ErrUnknownBlockVersion
+	}
+	return UnmarshallBlock(bytes.NewBuffer(data), true)
+}
+
+// blockFormatAt returns the format of the binary of the marshalled block. The node which doesn't know
+// the format of block_format_version marshals the block in its latest format
+func blockFormatAt(blockID int64) byte {
+	version := syspar.GetBlockFormatAt(blockID)
+	if version < 0 || version > int64(types.MaxBlockFormat) {
+		return types.MaxBlockFormat
+	}
+	return byte(version)
+}
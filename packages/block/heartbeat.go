@@ -0,0 +1,31 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+)
+
+// HeartbeatDue returns true if the generated block at blockTime is inserted without the transactions, it's
+// heartbeat_blocks intervals of the blocks after the previous block at prevTime. The empty blocks between
+// the heartbeats aren't generated
+func HeartbeatDue(prevTime, blockTime int64) bool {
+	return heartbeatDue(prevTime, blockTime, syspar.GetHeartbeatBlocks(), syspar.GetMaxBlockTimeDuration())
+}
+
+func heartbeatDue(prevTime, blockTime, blocks int64, interval time.Duration) bool {
+	if blocks <= 0 || interval <= 0 {
+		return false
+	}
+	return time.Duration(blockTime-prevTime)*time.Second >= time.Duration(blocks)*interval
+}
+
+// isHeartbeat returns true if the generated block without the transactions is the heartbeat block
+func (b *Block) isHeartbeat() bool {
+	return b.GenBlock && !b.IsGenesis() && b.PrevHeader != nil && HeartbeatDue(b.PrevHeader.Timestamp, b.Header.Timestamp)
+}
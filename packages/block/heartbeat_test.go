@@ -0,0 +1,28 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartbeatDue(t *testing.T) {
+	interval := 4 * time.Second
+	for _, c := range []struct {
+		prev, cur, blocks int64
+		due               bool
+	}{
+		{prev: 100, cur: 140, blocks: 0, due: false},
+		{prev: 100, cur: 139, blocks: 10, due: false},
+		{prev: 100, cur: 140, blocks: 10, due: true},
+		{prev: 100, cur: 104, blocks: 1, due: true},
+	} {
+		if due := heartbeatDue(c.prev, c.cur, c.blocks, interval); due != c.due {
+			t.Errorf("%+v: expected %v got %v", c, c.due, due)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// TxExecution is the transaction which is executed by the block
+type TxExecution struct {
+	Tx        *transaction.Transaction
+	Rollbacks []*types.RollbackTx // the rollback records of the successful transaction
+	Err       error               // the error of the failed transaction
+}
+
+// TxHook observes every transaction which is executed by the played block, e.g. the indexer or the
+// tracer. The hooks are called within the execution of the block, so they should be fast and they
+// must not change the block or the transaction.
+type TxHook interface {
+	BeforeTx(b *Block, t *transaction.Transaction)
+	AfterTx(b *Block, e *TxExecution)
+}
+
+// BlockHook observes the play of the blocks, AfterBlock gets nil if the block is committed
+type BlockHook interface {
+	BeforeBlock(b *Block)
+	AfterBlock(b *Block, err error)
+}
+
+var (
+	txHooks    = make(map[string]TxHook)
+	blockHooks = make(map[string]BlockHook)
+	hooksMutex sync.RWMutex
+)
+
+// RegisterTxHook registers the hook of the transactions with the name, nil hook removes it
+func RegisterTxHook(name string, hook TxHook) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	if hook == nil {
+		delete(txHooks, name)
+		return
+	}
+	txHooks[name] = hook
+}
+
+// RegisterBlockHook registers the hook of the blocks with the name, nil hook removes it
+func RegisterBlockHook(name string, hook BlockHook) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	if hook == nil {
+		delete(blockHooks, name)
+		return
+	}
+	blockHooks[name] = hook
+}
+
+// registeredTxHooks returns the hooks of the transactions in the order of the names
+func registeredTxHooks() (names []string, hooks []TxHook) {
+	hooksMutex.RLock()
+	defer hooksMutex.RUnlock()
+	for name := range txHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hooks = append(hooks, txHooks[name])
+	}
+	return
+}
+
+// registeredBlockHooks returns the hooks of the blocks in the order of the names
+func registeredBlockHooks() (names []string, hooks []BlockHook) {
+	hooksMutex.RLock()
+	defer hooksMutex.RUnlock()
+	for name := range blockHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hooks = append(hooks, blockHooks[name])
+	}
+	return
+}
+
+func (b *Block) beforeTx(t *transaction.Transaction) {
+	names, hooks := registeredTxHooks()
+	for i, hook := range hooks {
+		b.callHook(names[i], func() { hook.BeforeTx(b, t) })
+	}
+}
+
+func (b *Block) afterTx(e *TxExecution) {
+	names, hooks := registeredTxHooks()
+	for i, hook := range hooks {
+		b.callHook(names[i], func() { hook.AfterTx(b, e) })
+	}
+}
+
+func (b *Block) beforeBlock() {
+	names, hooks := registeredBlockHooks()
+	for i, hook := range hooks {
+		b.callHook(names[i], func() { hook.BeforeBlock(b) })
+	}
+}
+
+func (b *Block) afterBlock(err error) {
+	names, hooks := registeredBlockHooks()
+	for i, hook := range hooks {
+		b.callHook(names[i], func() { hook.AfterBlock(b, err) })
+	}
+}
+
+// callHook calls the hook, its panic is logged and doesn't break the play of the block
+func (b *Block) callHook(name string, call func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.GetLogger().WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r, "hook": name}).Error("calling block hook")
+		}
+	}()
+	call()
+}
@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+type testHook struct {
+	name  string
+	calls *[]string
+	panic bool
+}
+
+func (h *testHook) BeforeTx(b *Block, t *transaction.Transaction) {
+	*h.calls = append(*h.calls, h.name+" before tx")
+}
+
+func (h *testHook) AfterTx(b *Block, e *TxExecution) {
+	if h.panic {
+		panic("hook")
+	}
+	*h.calls = append(*h.calls, h.name+" after tx")
+}
+
+func (h *testHook) BeforeBlock(b *Block) {
+	*h.calls = append(*h.calls, h.name+" before block")
+}
+
+func (h *testHook) AfterBlock(b *Block, err error) {
+	*h.calls = append(*h.calls, h.name+" after block "+err.Error())
+}
+
+func TestBlockHooks(t *testing.T) {
+	var calls []string
+	RegisterTxHook("b", &testHook{name: "b", calls: &calls})
+	RegisterTxHook("a", &testHook{name: "a", calls: &calls, panic: true})
+	RegisterBlockHook("c", &testHook{name: "c", calls: &calls})
+	defer func() {
+		RegisterTxHook("a", nil)
+		RegisterTxHook("b", nil)
+		RegisterBlockHook("c", nil)
+	}()
+
+	b := &Block{BlockData: &types.BlockData{Header: &types.BlockHeader{BlockId: 2}}}
+	b.beforeBlock()
+	b.beforeTx(nil)
+	b.afterTx(&TxExecution{})
+	b.afterBlock(errors.New("failed"))
+	// the hooks are called in the order of the names and the panic of the hook is recovered
+	expected := []string{"c before block", "a before tx", "b before tx", "b after tx", "c after block failed"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("unexpected calls %v", calls)
+	}
+
+	RegisterTxHook("b", nil)
+	calls = nil
+	b.beforeTx(nil)
+	if !reflect.DeepEqual(calls, []string{"a before tx"}) {
+		t.Fatalf("removed hook is called: %v", calls)
+	}
+}
@@ -0,0 +1,389 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// IngestStream executes the transactions of the block in the order they arrive from txStream
+// and inserts the block when the stream is closed. The transactions are classified as they
+// arrive, the delayed and the transfer self transactions must arrive before the contract and
+// utxo transactions. Header and PrevHeader of the block must be set, BinData must be set
+// before the stream is closed unless the block is generated
+func (b *Block) IngestStream(ctx context.Context, txStream <-chan *transaction.Transaction) error {
+	var contractNames []string
+	if !b.IsGenesis() {
+		var err error
+		if contractNames, err = delayedContractNames(); err != nil {
+			return err
+		}
+	}
+	b.Transactions = make([]*transaction.Transaction, 0)
+	b.ClassifyTxsMap = make(map[int][]*transaction.Transaction)
+	return b.play(func(dbTx *sqldb.DbTransaction) error {
+		return b.processStream(ctx, dbTx, txStream, nil, func(t *transaction.Transaction) int {
+			b.Transactions = append(b.Transactions, t)
+			txType, ok := classifyTx(t, contractNames)
+			if !ok {
+				return 0
+			}
+			b.ClassifyTxsMap[txType] = append(b.ClassifyTxsMap[txType], t)
+			return txType
+		})
+	})
+}
+
+type ingestStage int
+
+const (
+	// stageSerial executes the genesis, stop network and delayed transactions and collects
+	// the transfer self transactions
+	stageSerial ingestStage = iota
+	// stageContracts executes the contract transactions and collects the utxo transactions
+	stageContracts
+)
+
+// ingest is the state machine of the block execution. The transactions which are executed
+// one after another are played immediately, the groups of transfer self and utxo
+// transactions are played in parallel when all of them have arrived
+type ingest struct {
+	b           *Block
+	dbTx        *sqldb.DbTransaction
+	txBadChan   chan badTxStruct
+	afters      *types.AfterTxs
+	processedTx *[][]byte
+
+	stage        ingestStage
+	serial       *txGroup
+	contracts    *txGroup
+	transferSelf []*transaction.Transaction
+	utxo         []*transaction.Transaction
+	keys         map[int64]bool
+	ecosystems   map[int64]bool
+	// blockOrder plays the utxo transactions which have arrived before the contract transaction
+	// ahead of it, the blocks before the activation of tx_type_order are played in their order
+	blockOrder bool
+
+	ctx       context.Context
+	groupCtx  context.Context
+	groupSpan trace.Span
+	groupType int
+}
+
+func newIngest(ctx context.Context, b *Block, dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte) *ingest {
+	return &ingest{
+		ctx:         ctx,
+		b:           b,
+		dbTx:        dbTx,
+		txBadChan:   txBadChan,
+		afters:      afters,
+		processedTx: processedTx,
+		serial:      b.newTxGroup(),
+		contracts:   b.newTxGroup(),
+		keys:        make(map[int64]bool),
+		ecosystems:  make(map[int64]bool),
+	}
+}
+
+// load queries the utxo outputs of the senders and the ecosystem parameters which haven't been loaded yet
+func (in *ingest) load(txs []*transaction.Transaction) error {
+	var keyIds, ecosystemIds []int64
+	for _, t := range txs {
+		if !in.keys[t.KeyID()] {
+			in.keys[t.KeyID()] = true
+			keyIds = append(keyIds, t.KeyID())
+		}
+		if t.IsSmartContract() && !in.ecosystems[t.SmartContract().TxSmart.EcosystemID] {
+			in.ecosystems[t.SmartContract().TxSmart.EcosystemID] = true
+			ecosystemIds = append(ecosystemIds, t.SmartContract().TxSmart.EcosystemID)
+		}
+	}
+	if len(keyIds) > 0 {
+		outputs, err := sqldb.GetTxOutputs(in.dbTx, keyIds)
+		if err != nil {
+			return err
+		}
+		// the outputs of the previous blocks go before the outputs of this block
+		loaded := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		sqldb.PutAllOutputsMap(outputs, loaded)
+		for keyUTXO, spentInfos := range loaded {
+			if in.b.batch != nil {
+				in.b.batch.state.loadOutputs(keyUTXO, spentInfos)
+			}
+			in.b.OutputsMap[keyUTXO] = append(spentInfos, in.b.OutputsMap[keyUTXO]...)
+		}
+	}
+	if len(ecosystemIds) > 0 {
+		ecoParams, err := sqldb.GetEcoParam(in.dbTx, ecosystemIds)
+		if err != nil {
+			return err
+		}
+		in.b.EcoParams = append(in.b.EcoParams, ecoParams...)
+	}
+	return nil
+}
+
+// push executes or collects the transaction of txType
+func (in *ingest) push(t *transaction.Transaction, txType int) error {
+	// the contract gets the type as $tx_type
+	if sc, ok := t.Inner.(*transaction.SmartTransactionParser); ok && sc != nil && sc.SmartContract != nil {
+		sc.TxType = txType
+	}
+	switch txType {
+	case types.TransferSelfTxType:
+		if in.stage != stageSerial {
+			return fmt.Errorf("%w: type %d", ErrTxOrder, txType)
+		}
+		if !groupSection(t, txType) {
+			return in.rejectGroupTx(t, txType)
+		}
+		if err := in.load([]*transaction.Transaction{t}); err != nil {
+			return err
+		}
+		in.transferSelf = append(in.transferSelf, t)
+		return nil
+	case types.UtxoTxType:
+		if !groupSection(t, txType) {
+			return in.rejectGroupTx(t, txType)
+		}
+		if err := in.load([]*transaction.Transaction{t}); err != nil {
+			return err
+		}
+		if err := in.enterContracts(); err != nil {
+			return err
+		}
+		in.utxo = append(in.utxo, t)
+		return nil
+	case types.SmartContractTxType:
+		if err := in.load([]*transaction.Transaction{t}); err != nil {
+			return err
+		}
+		if err := in.enterContracts(); err != nil {
+			return err
+		}
+		if in.blockOrder {
+			if err := in.playUtxo(); err != nil {
+				return err
+			}
+		}
+		return in.execute(in.contracts, txType, t)
+	}
+	if in.stage != stageSerial {
+		return fmt.Errorf("%w: type %d", ErrTxOrder, txType)
+	}
+	if err := in.load([]*transaction.Transaction{t}); err != nil {
+		return err
+	}
+	if in.b.IsGenesis() {
+		var err error
+		if t, err = transaction.UnmarshallTransaction(bytes.NewBuffer(t.FullData), false); err != nil {
+			return err
+		}
+	}
+	return in.execute(in.serial, txType, t)
+}
+
+// groupSection reports whether the transaction has the section which is grouped by
+// groupTransferSelfTxs or groupUtxoTxs
+func groupSection(t *transaction.Transaction, txType int) bool {
+	if t.Inner == nil || !t.IsSmartContract() {
+		return false
+	}
+	if sc := t.SmartContract(); sc == nil || sc.SmartContract == nil || sc.TxSmart == nil {
+		return false
+	}
+	if txType == types.TransferSelfTxType {
+		return t.SmartContract().TxSmart.TransferSelf != nil
+	}
+	return t.SmartContract().TxSmart.UTXO != nil
+}
+
+// rejectGroupTx marks bad the transaction which can't be grouped. The generated block skips
+// the transaction, otherwise the block is rejected. The transaction without the smart contract
+// has no hash, so it isn't marked
+func (in *ingest) rejectGroupTx(t *transaction.Transaction, txType int) error {
+	err := fmt.Errorf("%w: type %d", ErrTxGroupSection, txType)
+	if sc, ok := t.Inner.(*transaction.SmartTransactionParser); t.Inner == nil ||
+		ok && (sc == nil || sc.SmartContract == nil || sc.TxSmart == nil) {
+		return err
+	}
+	in.txBadChan <- badTxStruct{hash: t.Hash(), msg: err.Error(), keyID: t.KeyID()}
+	if in.b.GenBlock && !conf.Config.StrictBlock.Enabled {
+		return nil
+	}
+	return in.b.badTxError(t, err)
+}
+
+// group returns the context of the span of the transaction type group. The transactions
+// of the same type arrive one after another, so the span of the previous group is ended
+func (in *ingest) group(txType int) context.Context {
+	if in.groupSpan != nil && in.groupType == txType {
+		return in.groupCtx
+	}
+	in.endGroup()
+	in.groupCtx, in.groupSpan = tracer.Start(in.ctx, "block.ProcessTxs",
+		trace.WithAttributes(attribute.Int("tx.type", txType)))
+	in.groupType = txType
+	return in.groupCtx
+}
+
+func (in *ingest) endGroup() {
+	if in.groupSpan != nil {
+		in.groupSpan.End()
+		in.groupSpan = nil
+	}
+}
+
+func (in *ingest) execute(g *txGroup, txType int, t *transaction.Transaction) error {
+	if g.stopped {
+		return nil
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	return in.b.executeTx(in.group(txType), in.dbTx, in.txBadChan, in.afters, in.processedTx, g, t)
+}
+
+// enterContracts plays the transfer self transactions on the first contract or utxo transaction
+func (in *ingest) enterContracts() error {
+	if in.stage != stageSerial {
+		return nil
+	}
+	in.stage = stageContracts
+	if len(in.transferSelf) == 0 {
+		return nil
+	}
+	groupsLock.Lock()
+	defer groupsLock.Unlock()
+	walletAddress := make(map[int64]int64)
+	groupTransferSelfTxs(in.transferSelf, walletAddress)
+	err := in.playGroups(types.TransferSelfTxType, transferSelfTxsGroupMap)
+	transferSelfTxsGroupMap = make(map[string][]*transaction.Transaction, 0)
+	transferSelfGroupTxsList = make([]*transaction.Transaction, 0)
+	transferSelfGroupSerial = 1
+	in.transferSelf = nil
+	return err
+}
+
+// finish plays the collected transactions when the stream is closed
+func (in *ingest) finish() error {
+	if err := in.enterContracts(); err != nil {
+		return err
+	}
+	return in.playUtxo()
+}
+
+// playUtxo plays the groups of the collected utxo transactions
+func (in *ingest) playUtxo() error {
+	if len(in.utxo) == 0 {
+		return nil
+	}
+	groupsLock.Lock()
+	defer groupsLock.Unlock()
+	walletAddress := make(map[int64]int64)
+	groupUtxoTxs(in.utxo, walletAddress)
+	err := in.playGroups(types.UtxoTxType, utxoTxsGroupMap)
+	utxoTxsGroupMap = make(map[string][]*transaction.Transaction, 0)
+	utxoGroupTxsList = make([]*transaction.Transaction, 0)
+	utxoGroupSerial = 1
+	in.utxo = nil
+	return err
+}
+
+// playGroups plays the groups in parallel. Each group checks its own copy of the limits of
+// the contracts, the usage of the groups is added to them when all the groups are played
+func (in *ingest) playGroups(txType int, groups map[string][]*transaction.Transaction) error {
+	ctx := in.group(txType)
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	// the keys are the serial numbers of the groups
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i]) < len(keys[j]) || len(keys[i]) == len(keys[j]) && keys[i] < keys[j]
+	})
+	ordered := make([][]*transaction.Transaction, len(keys))
+	used := make([]*transaction.Limits, len(keys))
+	for i, key := range keys {
+		ordered[i] = groups[key]
+		used[i] = in.contracts.limits.Clone()
+	}
+	err := runGroups(groupWorkers(), ordered, func(i int, txs []*transaction.Transaction) error {
+		return in.b.serialExecuteTxs(ctx, in.dbTx, in.txBadChan, in.afters, in.processedTx, txs, used[i], lock)
+	})
+	for _, limits := range used {
+		in.contracts.limits.Merge(limits)
+	}
+	return err
+}
+
+// groupWorkers returns the maximum of the groups which are played at once
+func groupWorkers() int {
+	if conf.Config.TxGroupWorkers > 0 {
+		return conf.Config.TxGroupWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// runGroups plays the groups by the pool of the workers. The panic out of the transactions of
+// the group is recovered as PanicError, the worker goes on with the next group. It returns
+// the error of the first group which has failed
+func runGroups(workers int, groups [][]*transaction.Transaction, play func(i int, txs []*transaction.Transaction) error) error {
+	if workers > len(groups) {
+		workers = len(groups)
+	} else if workers < 1 {
+		workers = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		next = make(chan int)
+		errs = make([]error, len(groups))
+	)
+	playGroup := func(i int) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r, "stack": string(debug.Stack())}).Error("transaction group panicked")
+				err = &PanicError{Value: r}
+			}
+		}()
+		return play(i, groups[i])
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = playGroup(i)
+			}
+		}()
+	}
+	for i := range groups {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestOrderedTxs(t *testing.T) {
+	txs := make([]*transaction.Transaction, 6)
+	for i := range txs {
+		txs[i] = &transaction.Transaction{}
+	}
+	b := mustBuild(t, newTestBuilder(10).
+		AddTransactionAs(types.UtxoTxType, txs[0]).
+		AddTransactionAs(types.SmartContractTxType, txs[1]).
+		AddTransactionAs(types.SmartContractTxType, txs[2]).
+		AddTransactionAs(types.TransferSelfTxType, txs[3]).
+		AddTransactionAs(types.DelayTxType, txs[4]))
+	for _, item := range []struct {
+		typeOrder bool
+		expected  []*transaction.Transaction
+	}{
+		{true, []*transaction.Transaction{txs[4], txs[3], txs[1], txs[2], txs[0]}},
+		// the contracts and the utxo transactions keep the order of the block before the activation
+		{false, []*transaction.Transaction{txs[4], txs[3], txs[0], txs[1], txs[2]}},
+	} {
+		got := b.orderedTxs(item.typeOrder)
+		if len(got) != len(item.expected) {
+			t.Fatalf("expected %d transactions got %d", len(item.expected), len(got))
+		}
+		for i := range item.expected {
+			if got[i] != item.expected[i] {
+				t.Errorf("type order %v: wrong transaction on %d position", item.typeOrder, i)
+			}
+		}
+	}
+
+	b = mustBuild(t, newTestBuilder(10).AddTransactionAs(types.StopNetworkTxType, txs[5]))
+	if got := b.orderedTxs(false); len(got) != 1 || got[0] != txs[5] {
+		t.Errorf("stop network must be executed alone")
+	}
+}
+
+func TestIngestOrder(t *testing.T) {
+	b := mustBuild(t, newTestBuilder(10))
+	in := newIngest(context.Background(), b, nil, nil, nil, nil)
+	in.stage = stageContracts
+	for _, txType := range []int{types.TransferSelfTxType, types.DelayTxType, types.StopNetworkTxType} {
+		if err := in.push(&transaction.Transaction{}, txType); !errors.Is(err, ErrTxOrder) {
+			t.Errorf("type %d after contracts: expected %v got %v", txType, ErrTxOrder, err)
+		}
+	}
+}
+
+func TestIngestGroupSection(t *testing.T) {
+	defer func(c conf.StrictBlockConfig) { conf.Config.StrictBlock = c }(conf.Config.StrictBlock)
+	conf.Config.StrictBlock.Enabled = false
+	// the utxo transaction without the utxo section
+	tx := &transaction.Transaction{Inner: &transaction.SmartTransactionParser{SmartContract: &smart.SmartContract{
+		Hash:    []byte{1, 2, 3},
+		TxSmart: &types.SmartTransaction{Header: &types.Header{EcosystemID: 1, KeyID: 100}},
+	}}}
+	for _, gen := range []bool{false, true} {
+		b := mustBuild(t, newTestBuilder(10).WithGenBlock(gen))
+		badTxs := make(chan badTxStruct, 2)
+		in := newIngest(context.Background(), b, nil, badTxs, nil, nil)
+		err := in.push(tx, types.UtxoTxType)
+		if gen && err != nil {
+			t.Errorf("generated block must skip the transaction, got %v", err)
+		}
+		if !gen && !errors.Is(err, ErrTxGroupSection) {
+			t.Errorf("expected %v, got %v", ErrTxGroupSection, err)
+		}
+		if len(in.utxo) != 0 {
+			t.Error("the transaction mustn't be grouped")
+		}
+		if bad := <-badTxs; string(bad.hash) != string(tx.Hash()) || bad.keyID != 100 {
+			t.Errorf("wrong bad transaction %+v", bad)
+		}
+		if err = in.push(&transaction.Transaction{}, types.TransferSelfTxType); !errors.Is(err, ErrTxGroupSection) {
+			t.Errorf("expected %v for the empty transaction, got %v", ErrTxGroupSection, err)
+		}
+		if len(badTxs) != 0 {
+			t.Error("the empty transaction has no hash to be marked")
+		}
+	}
+}
+
+func TestTxPanic(t *testing.T) {
+	defer func(c conf.StrictBlockConfig) { conf.Config.StrictBlock = c }(conf.Config.StrictBlock)
+	tx := newCustomTx(t, 20)
+	for _, c := range []struct {
+		gen, strict, skipped bool
+	}{
+		{false, false, false},
+		{true, false, true},
+		{true, true, false},
+	} {
+		conf.Config.StrictBlock.Enabled = c.strict
+		b := mustBuild(t, newTestBuilder(10).AddTransaction(tx).WithGenBlock(c.gen))
+		badTxs := make(chan badTxStruct, 1)
+		err := b.txPanic(nil, badTxs, 0, tx, false, "stub contract")
+		if c.skipped != (err == nil) {
+			t.Errorf("gen %v, strict %v: unexpected error %v", c.gen, c.strict, err)
+		}
+		var panicErr *PanicError
+		if !c.skipped && (!errors.As(err, &panicErr) || string(panicErr.Hash) != string(tx.Hash())) {
+			t.Errorf("expected the panic of the transaction, got %v", err)
+		}
+		if bad := <-badTxs; string(bad.hash) != string(tx.Hash()) {
+			t.Errorf("wrong bad transaction %x", bad.hash)
+		}
+	}
+}
+
+func TestRunGroups(t *testing.T) {
+	groups := make([][]*transaction.Transaction, 8)
+	var active, maxActive, played int32
+	err := runGroups(3, groups, func(i int, txs []*transaction.Transaction) error {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for m := atomic.LoadInt32(&maxActive); n > m && !atomic.CompareAndSwapInt32(&maxActive, m, n); {
+			m = atomic.LoadInt32(&maxActive)
+		}
+		atomic.AddInt32(&played, 1)
+		switch i {
+		case 2:
+			// the stub contract dereferences the absent section
+			var sc *smart.SmartContract
+			_ = sc.TxSmart
+		case 5:
+			return ErrTxGroupSection
+		}
+		return nil
+	})
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected the panic of the first failed group, got %v", err)
+	}
+	if played != int32(len(groups)) {
+		t.Errorf("the workers must survive the panic, %d groups of %d are played", played, len(groups))
+	}
+	if maxActive > 3 {
+		t.Errorf("%d groups are played at once, the limit is 3", maxActive)
+	}
+	if err = runGroups(0, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
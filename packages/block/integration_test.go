@@ -0,0 +1,2324 @@
+//go:build integration
+
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	logtools "github.com/IBAX-io/go-ibax/packages/common/log"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/pbgo"
+	"github.com/IBAX-io/go-ibax/packages/recovery"
+	"github.com/IBAX-io/go-ibax/packages/rollback"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/service/oracle"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/dastore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+const (
+	testNetworkID = 1
+	testBlocks    = 5
+)
+
+// skipTables are changed outside of the rollback log, they aren't consensus state
+var skipTables = map[string]bool{
+	"audit_log":           true,
+	"contract_stats":      true,
+	"transactions":        true,
+	"transactions_status": true,
+	"queue_tx":            true,
+}
+
+// testChain is the node which plays the blocks signed with its own key
+type testChain struct {
+	t          *testing.T
+	hexKey     string
+	keyID      int64
+	privateKey []byte
+	start      int64
+	genesis    []byte // binary of the genesis block
+}
+
+func startPostgres(t *testing.T) conf.DBConfig {
+	ctx := context.Background()
+	container, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		postgres.WithDatabase("ibax"),
+		postgres.WithUsername("ibax"),
+		postgres.WithPassword("ibax"),
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		t.Fatalf("starting postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("terminating postgres container: %v", err)
+		}
+	})
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conf.DBConfig{
+		Name:         "ibax",
+		Host:         host,
+		Port:         port.Int(),
+		User:         "ibax",
+		Password:     "ibax",
+		MaxIdleConns: 5,
+		MaxOpenConns: 10,
+	}
+}
+
+// newTestChain recreates the schema and plays the genesis block
+func newTestChain(t *testing.T, db conf.DBConfig) *testChain {
+	return newTestChainAt(t, db, time.Now().Unix()-testBlocks-10)
+}
+
+// newTestChainAt recreates the schema and plays the genesis block of the start time
+func newTestChainAt(t *testing.T, db conf.DBConfig, start int64) *testChain {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	priv, _, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &testChain{t: t, hexKey: priv, start: start}
+	c.init(db)
+	return c
+}
+
+// replica returns the node with the same key which plays the same genesis block on another database
+func (c *testChain) replica(db conf.DBConfig) *testChain {
+	r := &testChain{t: c.t, hexKey: c.hexKey, start: c.start, genesis: c.genesis}
+	r.init(db)
+	return r
+}
+
+// init makes the node current, it recreates the schema and plays the genesis block. The genesis
+// block is built with the key of the node if it isn't set
+func (c *testChain) init(db conf.DBConfig) {
+	t := c.t
+	privateKey, err := hex.DecodeString(c.hexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.privateKey = privateKey
+
+	t.Setenv(keystore.DefaultEnvVar, c.hexKey)
+	conf.Config.Keystore = conf.KeystoreConfig{Type: keystore.TypeEnv}
+	conf.Config.KeyID = crypto.Address(publicKey)
+	conf.Config.LocalConf.NetworkID = testNetworkID
+	conf.Config.DB = db
+	c.keyID = conf.Config.KeyID
+	if err = syspar.ReadNodeKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if err = sqldb.InitDB(conf.Config.DB); err != nil {
+		t.Fatalf("initializing db: %v", err)
+	}
+	smart.InitVM()
+
+	if c.genesis == nil {
+		first, err := new(transaction.FirstBlockParser).BinMarshal(&types.FirstBlock{
+			KeyID:             c.keyID,
+			Time:              c.start,
+			PublicKey:         publicKey,
+			NodePublicKey:     publicKey,
+			Test:              1,
+			PrivateBlockchain: 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		genesis, err := block.GenesisBuilder().
+			WithTimestamp(c.start).
+			WithKeyID(c.keyID).
+			WithNetworkID(testNetworkID).
+			AddRawTransaction(first).
+			WithSigner(syspar.GetNodeSigner()).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.genesis = genesis.BinData
+	}
+	if err = block.InsertBlockWOForksNew(c.genesis, nil, false, true); err != nil {
+		t.Fatalf("inserting genesis block: %v", err)
+	}
+	if err = sqldb.UpdateSchema(); err != nil {
+		t.Fatalf("updating schema: %v", err)
+	}
+	if err = syspar.SysUpdate(nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := block.GetDataFromFirstBlock(); ok {
+		syspar.SetFirstBlockData(data)
+	}
+	if err = smart.LoadContracts(); err != nil {
+		t.Fatalf("loading contracts: %v", err)
+	}
+}
+
+// newParameterTx returns the transaction of @1NewParameter contract
+func (c *testChain) newParameterTx(name string, now int64) []byte {
+	return c.newContractTx("NewParameter", map[string]any{"Name": name, "Value": name, "Conditions": "true"}, now)
+}
+
+// newContractTx returns the transaction of the contract of the first ecosystem
+func (c *testChain) newContractTx(name string, params map[string]any, now int64) []byte {
+	return c.newContractTxOf(c.privateKey, name, params, now)
+}
+
+// newContractTxOf returns the transaction of the contract of the first ecosystem which is signed by the key
+func (c *testChain) newContractTxOf(privateKey []byte, name string, params map[string]any, now int64) []byte {
+	contract := smart.VMGetContract(script.GetVM(), name, 1)
+	if contract == nil {
+		c.t.Fatalf("%s contract isn't loaded", name)
+	}
+	publicKey, err := crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	data, _, err := transaction.NewTransactionInProc(types.SmartTransaction{
+		Header: &types.Header{
+			ID:          int(contract.Info().ID),
+			EcosystemID: 1,
+			KeyID:       crypto.Address(publicKey),
+			Time:        now,
+			NetworkID:   testNetworkID,
+		},
+		Params: params,
+	}, privateKey)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return data
+}
+
+// delayedTx returns the transaction of the delayed contract of the first ecosystem which is run by the node
+func (c *testChain) delayedTx(name string, now int64) []byte {
+	c.t.Helper()
+	contract := smart.VMGetContract(script.GetVM(), name, 1)
+	if contract == nil {
+		c.t.Fatalf("%s contract isn't loaded", name)
+	}
+	data, _, err := transaction.NewInternalTransaction(types.SmartTransaction{
+		Header: &types.Header{
+			ID:          int(contract.Info().ID),
+			EcosystemID: 1,
+			KeyID:       c.keyID,
+			Time:        now,
+			NetworkID:   testNetworkID,
+		},
+		SignedBy: c.keyID,
+		Params:   map[string]any{},
+	}, c.privateKey)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return data
+}
+
+// sectionTx returns the transaction of the utxo or the transfer self section of the key of the node
+func (c *testChain) sectionTx(tx types.SmartTransaction, now int64) []byte {
+	c.t.Helper()
+	tx.Header = &types.Header{
+		ID:          1,
+		EcosystemID: 1,
+		KeyID:       c.keyID,
+		Time:        now,
+		NetworkID:   testNetworkID,
+	}
+	data, _, err := transaction.NewTransactionInProc(tx, c.privateKey)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return data
+}
+
+// txHash returns the hash of the binary transaction
+func (c *testChain) txHash(data []byte) []byte {
+	c.t.Helper()
+	tx, err := transaction.DecodeTransaction(data)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return tx.Hash()
+}
+
+// restart reconnects to the database and reloads the caches like the restarted node
+func (c *testChain) restart() {
+	c.t.Helper()
+	if err := sqldb.GormClose(); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := sqldb.GormInit(conf.Config.DB); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := syspar.SysUpdate(nil); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := smart.LoadContracts(); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// playBlock marshals the next block with the transactions and plays it like the received one
+func (c *testChain) playBlock(txs ...[]byte) {
+	c.t.Helper()
+	b := c.nextBlock(txs...)
+	if err := b.PlaySafe(); err != nil {
+		c.t.Fatalf("playing block %d: %v", b.Header.BlockId, err)
+	}
+}
+
+// nextBlock marshals the next block with the transactions and checks it like the received one
+func (c *testChain) nextBlock(txs ...[]byte) *block.Block {
+	c.t.Helper()
+	info := &sqldb.InfoBlock{}
+	if _, err := info.Get(); err != nil {
+		c.t.Fatal(err)
+	}
+	blockID := info.BlockID + 1
+	baseGasPrice, err := block.BaseGasPriceOf(blockID)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	built, err := block.NormalBlockBuilder(&types.BlockHeader{
+		BlockId:       info.BlockID,
+		Timestamp:     c.start + info.BlockID,
+		NetworkId:     testNetworkID,
+		BlockHash:     info.Hash,
+		RollbacksHash: info.RollbacksHash,
+	}).
+		WithKeyID(c.keyID).
+		WithBaseGasPrice(baseGasPrice).
+		AddRawTransaction(txs...).
+		WithSigner(syspar.GetNodeSigner()).
+		Build()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	b, err := block.ProcessBlockByBinData(built.BinData, true)
+	if err != nil {
+		c.t.Fatalf("processing block %d: %v", blockID, err)
+	}
+	if err = b.Check(); err != nil {
+		c.t.Fatalf("checking block %d: %v", blockID, err)
+	}
+	return b
+}
+
+// generateBlock generates the next block with the transactions like the block generator and inserts it,
+// the failed transactions are left out of the block
+func (c *testChain) generateBlock(txs ...[]byte) {
+	c.t.Helper()
+	info := &sqldb.InfoBlock{}
+	if _, err := info.Get(); err != nil {
+		c.t.Fatal(err)
+	}
+	baseGasPrice, err := block.BaseGasPriceOf(info.BlockID + 1)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	built, err := block.NormalBlockBuilder(&types.BlockHeader{
+		BlockId:       info.BlockID,
+		Timestamp:     c.start + info.BlockID,
+		NetworkId:     testNetworkID,
+		BlockHash:     info.Hash,
+		RollbacksHash: info.RollbacksHash,
+	}).
+		WithKeyID(c.keyID).
+		WithBaseGasPrice(baseGasPrice).
+		WithGenBlock(true).
+		AddRawTransaction(txs...).
+		WithSigner(syspar.GetNodeSigner()).
+		Build()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err = block.InsertBlockWOForksNew(built.BinData, built.ClassifyTxsMap, true, false); err != nil {
+		c.t.Fatalf("generating block %d: %v", info.BlockID+1, err)
+	}
+}
+
+// rollbackTo rolls back the blocks one by one in the reverse order
+func (c *testChain) rollbackTo(blockID int64) {
+	c.t.Helper()
+	for {
+		last := &sqldb.BlockChain{}
+		if _, err := last.GetMaxBlock(); err != nil {
+			c.t.Fatal(err)
+		}
+		if last.ID <= blockID {
+			return
+		}
+		data, err := last.BlockData()
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		if err = rollback.RollbackBlock(data); err != nil {
+			c.t.Fatalf("rolling back block %d: %v", last.ID, err)
+		}
+	}
+}
+
+// snapshot returns the checksums of the tables of the public schema
+func snapshot(t *testing.T) map[string]string {
+	t.Helper()
+	var tables []string
+	if err := sqldb.DBConn.Raw(`SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY tablename`).
+		Scan(&tables).Error; err != nil {
+		t.Fatal(err)
+	}
+	sums := make(map[string]string, len(tables))
+	for _, table := range tables {
+		if skipTables[table] {
+			continue
+		}
+		var sum string
+		if err := sqldb.DBConn.Raw(fmt.Sprintf(`SELECT coalesce(md5(string_agg(t::text, ',' ORDER BY t::text)), '') FROM "%s" t`, table)).
+			Scan(&sum).Error; err != nil {
+			t.Fatalf("checksum of %s: %v", table, err)
+		}
+		sums[table] = sum
+	}
+	return sums
+}
+
+func compareSnapshots(t *testing.T, expected, got map[string]string) {
+	t.Helper()
+	for table, sum := range expected {
+		if got[table] != sum {
+			t.Errorf("table %s differs after the rollback", table)
+		}
+	}
+	for table := range got {
+		if _, ok := expected[table]; !ok {
+			t.Errorf("table %s is left after the rollback", table)
+		}
+	}
+}
+
+// TestPlaySafeRollback plays the blocks after the genesis block and rolls them back.
+// The genesis block can't be rolled back so the state after it is compared
+func TestPlaySafeRollback(t *testing.T) {
+	db := startPostgres(t)
+	for _, method := range []types.BlockSyncMethod{types.BlockSyncMethod_CONTRACTVM, types.BlockSyncMethod_SQLDML} {
+		t.Run(method.String(), func(t *testing.T) {
+			conf.Config.BlockSyncMethod.Method = method.String()
+			c := newTestChain(t, db)
+			genesis := snapshot(t)
+
+			for i := 0; i < testBlocks; i++ {
+				c.playBlock(c.newParameterTx(fmt.Sprintf("integration_%d", i), c.start+int64(i)+2))
+			}
+			if played := snapshot(t); played["1_parameters"] == genesis["1_parameters"] {
+				t.Fatal("blocks haven't changed the parameters")
+			}
+
+			c.rollbackTo(1)
+			compareSnapshots(t, genesis, snapshot(t))
+			if err := syspar.SysUpdate(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			// the rolled back chain accepts the same blocks again
+			c.playBlock(c.newParameterTx("integration_0", c.start+2))
+		})
+	}
+}
+
+// TestPlaySafeTracing plays the block with the recording tracer provider and checks the spans
+// of the block, the transaction type group and the transactions
+func TestPlaySafeTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	c := newTestChain(t, startPostgres(t))
+	c.playBlock(c.newParameterTx("tracing_0", c.start+2), c.newParameterTx("tracing_1", c.start+2))
+
+	var root, group sdktrace.ReadOnlySpan
+	var txs []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "block.PlaySafe":
+			if attrInt(span, "block.id") == 2 {
+				root = span
+			}
+		case "block.ProcessTxs":
+			group = span
+		case "block.tx":
+			txs = append(txs, span)
+		}
+	}
+	if root == nil || group == nil {
+		t.Fatalf("expected block and group spans, got %d spans", len(recorder.Ended()))
+	}
+	if group.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("group span isn't the child of the block span")
+	}
+	if attrInt(group, "tx.type") != types.SmartContractTxType {
+		t.Errorf("wrong group type %d", attrInt(group, "tx.type"))
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 tx spans, got %d", len(txs))
+	}
+	for _, span := range txs {
+		if span.Parent().SpanID() != group.SpanContext().SpanID() {
+			t.Error("tx span isn't the child of the group span")
+		}
+		attrs := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+		if len(attrs["tx.hash"].AsString()) != 64 || attrs["tx.key_id"].AsInt64() != c.keyID ||
+			attrs["tx.ecosystem_id"].AsInt64() != 1 || attrs["tx.contract"].AsString() != "@1NewParameter" {
+			t.Errorf("wrong tx attributes %v", span.Attributes())
+		}
+	}
+}
+
+// TestPlaySafeLogging plays the block with the debug level and checks that every entry is
+// correlated with the block and the entries of the transactions name them
+func TestPlaySafeLogging(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	b := c.nextBlock(c.newParameterTx("logging_0", c.start+2), c.newParameterTx("logging_1", c.start+2))
+
+	hook := test.NewGlobal()
+	defer log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+	defer log.SetLevel(log.GetLevel())
+	log.SetLevel(log.DebugLevel)
+	if err := b.PlaySafe(); err != nil {
+		t.Fatal(err)
+	}
+
+	formatter := logtools.NewJSONFormatter()
+	txs := make(map[string]bool)
+	for _, e := range hook.AllEntries() {
+		out, err := formatter.Format(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fields map[string]any
+		if err = json.Unmarshal(out, &fields); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{logtools.FieldTime, logtools.FieldLevel, logtools.FieldMsg} {
+			if _, ok := fields[key]; !ok {
+				t.Errorf("%q: json entry doesn't have %s", e.Message, key)
+			}
+		}
+		if fields[logtools.FieldBlockID] != float64(2) || fields[logtools.FieldBlockMode] != logtools.BlockModeValidation ||
+			fields[logtools.FieldBlockHash] != hex.EncodeToString(b.Header.BlockHash) {
+			t.Errorf("%q: entry isn't correlated with the block %v", e.Message, fields)
+		}
+		if e.Message == "transaction played" {
+			if fields[logtools.FieldContract] != "@1NewParameter" || fields[logtools.FieldEco] != float64(1) {
+				t.Errorf("wrong tx fields %v", fields)
+			}
+			txs[fmt.Sprint(fields[logtools.FieldTxHash])] = true
+		}
+	}
+	if len(txs) != 2 {
+		t.Errorf("expected entries of 2 transactions, got %d", len(txs))
+	}
+}
+
+// TestPlaySafeContractStats plays the blocks invoking several contracts and restarts the node
+// between them, the stats of the day are accumulated by every committed block
+func TestPlaySafeContractStats(t *testing.T) {
+	defer func(days int) { conf.Config.ContractStatsDays = days }(conf.Config.ContractStatsDays)
+	conf.Config.ContractStatsDays = 7
+	c := newTestChain(t, startPostgres(t))
+	menu := func(name string) []byte {
+		return c.newContractTx("NewMenu", map[string]any{"Name": name, "Value": name, "Conditions": "true"}, c.start+2)
+	}
+
+	c.playBlock(c.newParameterTx("stats_0", c.start+2), menu("stats_0"))
+	c.playBlock(c.newParameterTx("stats_1", c.start+2))
+	c.restart()
+	c.playBlock(c.newParameterTx("stats_2", c.start+2), menu("stats_1"), menu("stats_2"))
+
+	info := &sqldb.InfoBlock{}
+	if _, err := info.Get(); err != nil {
+		t.Fatal(err)
+	}
+	day := sqldb.StatsDay(time.Unix(c.start+info.BlockID, 0))
+	if day != sqldb.StatsDay(time.Unix(c.start+2, 0)) {
+		t.Skip("the blocks are played around midnight")
+	}
+	for name, count := range map[string]int64{"@1NewParameter": 3, "@1NewMenu": 3} {
+		list, err := sqldb.GetContractStats(name, 1, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 {
+			t.Fatalf("%s: expected the stats of one day, got %d", name, len(list))
+		}
+		s := list[0]
+		if s.Day != day || s.Success != count || s.Failed != 0 || s.Invocations() != count {
+			t.Errorf("%s: wrong counts %+v", name, s)
+		}
+		if s.FuelTotal <= 0 || s.FuelMax > s.FuelTotal || s.FuelP95() > s.FuelMax || s.FuelP95() < s.FuelTotal/count/2 {
+			t.Errorf("%s: wrong fuel %+v, p95 %d", name, s, s.FuelP95())
+		}
+		if s.RowsWritten < count {
+			t.Errorf("%s: expected at least %d written rows, got %d", name, count, s.RowsWritten)
+		}
+	}
+}
+
+// TestPlaySafeResourceUsage checks the resources recorded for the played contracts and their
+// removal by the rollback of the block
+func TestPlaySafeResourceUsage(t *testing.T) {
+	defer func(days int) { conf.Config.ResourceUsageDays = days }(conf.Config.ResourceUsageDays)
+	conf.Config.ResourceUsageDays = 1
+	c := newTestChain(t, startPostgres(t))
+	from := time.Now().Add(-time.Minute)
+	c.playBlock(c.newParameterTx("usage_0", c.start+2), c.newParameterTx("usage_1", c.start+2),
+		c.newContractTx("NewMenu", map[string]any{"Name": "usage_0", "Value": "usage_0", "Conditions": "true"}, c.start+2))
+
+	var list []sqldb.ResourceUsage
+	if err := sqldb.DBConn.Where("block_id = ?", 2).Find(&list).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected the usage of 3 transactions, got %d", len(list))
+	}
+	for _, u := range list {
+		if u.EcosystemID != 1 || u.CPUNs <= 0 || u.DBWriteBytes <= 0 || u.MemPeakBytes <= 0 || u.GasUsed <= 0 ||
+			u.RecordedAt.Before(from) {
+			t.Errorf("%s: wrong usage %+v", u.ContractName, u)
+		}
+	}
+
+	top, err := sqldb.GetTopConsumers(sqldb.ResourceMetricGas, from, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := make(map[string]int64)
+	for _, item := range top {
+		calls[item.ContractName] = item.Calls
+	}
+	if calls["@1NewParameter"] != 2 || calls["@1NewMenu"] != 1 || len(top) != 2 {
+		t.Errorf("wrong top consumers %+v", top)
+	}
+	if top[0].GasUsed < top[1].GasUsed {
+		t.Errorf("top consumers aren't ordered by gas %+v", top)
+	}
+
+	c.rollbackTo(1)
+	var count int64
+	if err = sqldb.DBConn.Model(&sqldb.ResourceUsage{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected the usage of the rolled back block to be deleted, got %d rows", count)
+	}
+}
+
+// TestPlaySafeTxReceipts checks the receipts of the played transactions and their removal by the
+// rollback of the block
+func TestPlaySafeTxReceipts(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	param := c.newParameterTx("receipt_0", c.start+2)
+	menu := c.newContractTx("NewMenu", map[string]any{"Name": "receipt_0", "Value": "receipt_0", "Conditions": "true"}, c.start+2)
+	c.playBlock(param, menu)
+
+	for _, item := range []struct {
+		data     []byte
+		contract string
+	}{{param, "@1NewParameter"}, {menu, "@1NewMenu"}} {
+		receipt := &sqldb.TxReceipt{}
+		found, err := receipt.GetByHash(nil, c.txHash(item.data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatalf("%s: receipt isn't found", item.contract)
+		}
+		if receipt.BlockID != 2 || receipt.EcosystemID != 1 || receipt.ContractName != item.contract ||
+			receipt.Fuel <= 0 || receipt.RowsTouched <= 0 || receipt.Status != int64(pbgo.TxInvokeStatusCode_SUCCESS) {
+			t.Errorf("%s: wrong receipt %+v", item.contract, receipt)
+		}
+	}
+
+	c.rollbackTo(1)
+	var count int64
+	if err := sqldb.DBConn.Model(&sqldb.TxReceipt{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected the receipts of the rolled back block to be deleted, got %d rows", count)
+	}
+}
+
+// TestPlaySafeBlockResources plays several blocks and checks their resources against the fuel of
+// the blocks and the contracts, the down-sampled hour keeps the sums and the maxima of the blocks
+func TestPlaySafeBlockResources(t *testing.T) {
+	defer func(days, usage int) {
+		conf.Config.BlockResourcesDays, conf.Config.ResourceUsageDays = days, usage
+	}(conf.Config.BlockResourcesDays, conf.Config.ResourceUsageDays)
+	conf.Config.BlockResourcesDays, conf.Config.ResourceUsageDays = 1, 1
+	c := newTestChain(t, startPostgres(t))
+	menu := func(name string) []byte {
+		return c.newContractTx("NewMenu", map[string]any{"Name": name, "Value": name, "Conditions": "true"}, c.start+2)
+	}
+	txs := [][][]byte{
+		{c.newParameterTx("resources_0", c.start+2)},
+		{c.newParameterTx("resources_1", c.start+2), menu("resources_0")},
+		{c.newParameterTx("resources_2", c.start+2), menu("resources_1"), menu("resources_2")},
+	}
+	for _, list := range txs {
+		c.playBlock(list...)
+	}
+
+	list, err := sqldb.GetBlockResources(time.Unix(c.start, 0), time.Unix(c.start+10, 0), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(txs) {
+		t.Fatalf("expected the resources of %d blocks, got %d", len(txs), len(list))
+	}
+	for i, r := range list {
+		count := int64(len(txs[i]))
+		bc := &sqldb.BlockChain{}
+		if _, err = bc.Get(r.BlockID); err != nil {
+			t.Fatal(err)
+		}
+		var usage []sqldb.ResourceUsage
+		if err = sqldb.DBConn.Where("block_id = ?", r.BlockID).Find(&usage).Error; err != nil {
+			t.Fatal(err)
+		}
+		var fuel int64
+		for _, u := range usage {
+			fuel += u.GasUsed
+		}
+		if r.BlockID != int64(i+2) || r.Resolution != sqldb.ResolutionBlock || r.Blocks != 1 ||
+			r.TxCount != count || r.TxCounts[types.SmartContractTxType] != count || r.Savepoints != count {
+			t.Errorf("%d: wrong counts %+v", r.BlockID, r)
+		}
+		if r.Fuel <= 0 || r.Fuel != bc.GasUsed || r.Fuel != fuel || r.FuelMax != r.Fuel {
+			t.Errorf("%d: fuel %d, block %d, contracts %d", r.BlockID, r.Fuel, bc.GasUsed, fuel)
+		}
+		if r.Size != int64(len(bc.Data)) || r.ExecNs <= 0 {
+			t.Errorf("%d: wrong size %d of %d or time %d", r.BlockID, r.Size, len(bc.Data), r.ExecNs)
+		}
+	}
+
+	c.rollbackTo(3)
+	if list, err = sqldb.GetBlockResources(time.Unix(c.start, 0), time.Unix(c.start+10, 0), 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected the resources of the rolled back block to be deleted, got %d rows", len(list))
+	}
+
+	expected := sqldb.AggregateBlockResources(list, sqldb.ResolutionHour)
+	if err = sqldb.DownsampleBlockResources(nil, time.Unix(c.start, 0).Add(2*time.Hour),
+		sqldb.ResolutionBlock, sqldb.ResolutionHour); err != nil {
+		t.Fatal(err)
+	}
+	hours, err := sqldb.GetBlockResources(time.Unix(c.start, 0).Add(-time.Hour), time.Unix(c.start, 0).Add(time.Hour), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours) != len(expected) {
+		t.Fatalf("expected %d hours, got %d", len(expected), len(hours))
+	}
+	for i, h := range hours {
+		e := expected[i]
+		if h.Resolution != sqldb.ResolutionHour || h.Blocks != e.Blocks || h.TxCount != e.TxCount || h.Fuel != e.Fuel ||
+			h.FuelMax != e.FuelMax || h.Size != e.Size || h.Savepoints != e.Savepoints || h.ExecNsMax != e.ExecNsMax {
+			t.Errorf("expected hour %+v, got %+v", e, h)
+		}
+	}
+}
+
+// TestPlaySafeTrace plays the same block on two nodes and compares their execution traces
+func TestPlaySafeTrace(t *testing.T) {
+	defer func(path string) { conf.Config.BlockTracePath = path }(conf.Config.BlockTracePath)
+	db := startPostgres(t)
+	dirs := [2]string{t.TempDir(), t.TempDir()}
+
+	conf.Config.BlockTracePath = dirs[0]
+	c := newTestChain(t, db)
+	b := c.nextBlock(c.newParameterTx("trace_0", c.start+2), c.newParameterTx("trace_1", c.start+2),
+		c.newContractTx("NewMenu", map[string]any{"Name": "trace_0", "Value": "trace_0", "Conditions": "true"}, c.start+2))
+	if err := b.PlaySafe(); err != nil {
+		t.Fatalf("playing block on the first node: %v", err)
+	}
+	if err := sqldb.DBConn.Exec(`CREATE DATABASE ibax_replica`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := sqldb.GormClose(); err != nil {
+		t.Fatal(err)
+	}
+
+	conf.Config.BlockTracePath = dirs[1]
+	replicaDB := db
+	replicaDB.Name = "ibax_replica"
+	c.replica(replicaDB)
+	rb, err := block.ProcessBlockByBinData(b.BinData, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rb.Check(); err != nil {
+		t.Fatalf("checking block on the second node: %v", err)
+	}
+	if err = rb.PlaySafe(); err != nil {
+		t.Fatalf("playing block on the second node: %v", err)
+	}
+
+	var traces [2]*block.Trace
+	for i, dir := range dirs {
+		if traces[i], err = block.ReadTraceFile(block.TraceFilePath(dir, b.Header.BlockHash)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if i, ra, rb := block.CompareTraces(traces[0], traces[1]); i >= 0 {
+		t.Fatalf("traces diverge at record %d: %v and %v", i, ra, rb)
+	}
+	kinds := make(map[string]int)
+	for _, r := range traces[0].Records {
+		kinds[r.Kind]++
+	}
+	if kinds[block.TraceSavepoint] != 3 || kinds[block.TraceFuel] != 3 || kinds[block.TraceSQL] == 0 {
+		t.Errorf("wrong records of the trace %v", kinds)
+	}
+}
+
+func attrInt(span sdktrace.ReadOnlySpan, key attribute.Key) int64 {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.AsInt64()
+		}
+	}
+	return 0
+}
+
+// TestPlaySafeTimeLocks plays the cliff and the linear time locks, the early release is rejected
+// and the rollback restores the balances
+func TestPlaySafeTimeLocks(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	genesis := snapshot(t)
+	_, recipientKey, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := hex.DecodeString(recipientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient := crypto.Address(pub)
+	balance := func(keyID int64) decimal.Decimal {
+		t.Helper()
+		var amount string
+		if err := sqldb.DBConn.Raw(`SELECT coalesce(max(amount), 0)::text FROM "1_keys" WHERE id = ? AND ecosystem = 1`, keyID).
+			Scan(&amount).Error; err != nil {
+			t.Fatal(err)
+		}
+		return decimal.RequireFromString(amount)
+	}
+	released := func(id int64) string {
+		t.Helper()
+		lock := &sqldb.TimeLock{}
+		if found, err := lock.Get(nil, 1, id); err != nil || !found {
+			t.Fatalf("getting time lock %d: %v", id, err)
+		}
+		return lock.Released.String()
+	}
+	release := func(id int64) []byte {
+		return c.newContractTx("TimeLockRelease", map[string]any{"Id": id}, c.start+2)
+	}
+
+	before := balance(c.keyID)
+	// the block 2 locks 1000 until the block 4 and 3000 vesting from the block 2 until the block 5
+	c.playBlock(
+		c.newContractTx("TimeLockTransfer", map[string]any{"Recipient": converter.AddressToString(recipient),
+			"Amount": "1000", "UnlockBlock": 4}, c.start+2),
+		c.newContractTx("TimeLockTransfer", map[string]any{"Recipient": converter.AddressToString(recipient),
+			"Amount": "3000", "VestingEnd": c.start + 5}, c.start+2))
+	if spent := before.Sub(balance(c.keyID)); spent.LessThan(decimal.NewFromInt(4000)) {
+		t.Fatalf("locked amount is spendable, spent %s", spent)
+	}
+	locks, err := sqldb.GetTimeLocks(1, recipient)
+	if err != nil || len(locks) != 2 {
+		t.Fatalf("expected two time locks, got %v %v", locks, err)
+	}
+	cliff, linear := locks[0].ID, locks[1].ID
+
+	// the block 3 releases 1000 of the linear lock, the cliff lock hasn't matured
+	c.playBlock(release(cliff), release(linear))
+	if got := released(cliff); got != "0" {
+		t.Errorf("cliff lock has been released early: %s", got)
+	}
+	if got := released(linear); got != "1000" {
+		t.Errorf("expected 1000 of the linear lock to be released, got %s", got)
+	}
+	if got := balance(recipient).String(); got != "1000" {
+		t.Errorf("wrong balance of the recipient %s", got)
+	}
+
+	// the block 4 releases the cliff lock, the block 5 releases the rest of the linear lock
+	c.playBlock(release(cliff))
+	if got := balance(recipient).String(); got != "2000" {
+		t.Errorf("wrong balance of the recipient %s", got)
+	}
+	c.playBlock(release(linear))
+	if got := balance(recipient).String(); got != "4000" {
+		t.Errorf("wrong balance of the recipient %s", got)
+	}
+
+	c.rollbackTo(1)
+	compareSnapshots(t, genesis, snapshot(t))
+}
+
+// TestPlaySafeProposalSnapshot records the balances for the proposal, the tokens spent after the
+// snapshot don't change the weight and the rollback removes the snapshot
+func TestPlaySafeProposalSnapshot(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	genesis := snapshot(t)
+	balance := func() decimal.Decimal {
+		t.Helper()
+		key := &sqldb.Key{}
+		if _, err := key.SetTablePrefix(1).Get(nil, c.keyID); err != nil {
+			t.Fatal(err)
+		}
+		return decimal.RequireFromString(key.Amount)
+	}
+
+	c.playBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract VotingTemplateRun { action { $result = SnapshotBalances() } }`}, c.start+2))
+	// the fee of the transaction is paid after the snapshot
+	before := balance()
+	c.playBlock(c.newContractTx("VotingTemplateRun", nil, c.start+2))
+	// the locked tokens leave the balance, the weight of the first proposal stays the same
+	c.playBlock(c.newContractTx("TimeLockTransfer", map[string]any{"Recipient": converter.AddressToString(c.keyID),
+		"Amount": "1000", "UnlockBlock": 10}, c.start+2))
+	after := balance()
+	c.playBlock(c.newContractTx("VotingTemplateRun", nil, c.start+2))
+
+	for block, want := range map[int64]decimal.Decimal{3: before, 5: after} {
+		s := &sqldb.ProposalSnapshot{}
+		if found, err := s.Get(nil, 1, block, c.keyID); err != nil || !found {
+			t.Fatalf("getting snapshot of block %d: %v", block, err)
+		}
+		if !s.Amount.Equal(want) {
+			t.Errorf("block %d: expected the weight %s, got %s", block, want, s.Amount)
+		}
+	}
+
+	c.rollbackTo(1)
+	compareSnapshots(t, genesis, snapshot(t))
+}
+
+// TestPlaySafeDefaultOrder plays the contract which takes the first row of the query without the order
+// on two nodes, the tuples of the first node are moved so its heap order differs
+func TestPlaySafeDefaultOrder(t *testing.T) {
+	db := startPostgres(t)
+	c := newTestChain(t, db)
+	b2 := c.nextBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract FirstParameter {
+			action {
+				var name string
+				name = DBFind("@1parameters").Columns("name").Where({"ecosystem": $ecosystem_id}).Limit(1).One("name")
+				CallContract("@1NewParameter", {"Name": "first_" + name, "Value": name, "Conditions": "true"})
+			}
+		}`}, c.start+2))
+	if err := b2.PlaySafe(); err != nil {
+		t.Fatal(err)
+	}
+	var want string
+	if err := sqldb.DBConn.Raw(`SELECT name FROM "1_parameters" WHERE ecosystem = 1 ORDER BY id LIMIT 1`).Scan(&want).Error; err != nil {
+		t.Fatal(err)
+	}
+	// the updated tuple is the last one in the heap
+	if err := sqldb.DBConn.Exec(`UPDATE "1_parameters" SET value = value WHERE ecosystem = 1 AND name = ?`, want).Error; err != nil {
+		t.Fatal(err)
+	}
+	b3 := c.nextBlock(c.newContractTx("FirstParameter", nil, c.start+2))
+	if err := b3.PlaySafe(); err != nil {
+		t.Fatal(err)
+	}
+	first := func() string {
+		t.Helper()
+		var value string
+		if err := sqldb.DBConn.Raw(`SELECT value FROM "1_parameters" WHERE ecosystem = 1 AND name LIKE 'first_%'`).
+			Scan(&value).Error; err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	if got := first(); got != want {
+		t.Fatalf("expected the first row %s, got %s", want, got)
+	}
+
+	if err := sqldb.DBConn.Exec(`CREATE DATABASE ibax_replica`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := sqldb.GormClose(); err != nil {
+		t.Fatal(err)
+	}
+	replicaDB := db
+	replicaDB.Name = "ibax_replica"
+	c.replica(replicaDB)
+	for _, data := range [][]byte{b2.BinData, b3.BinData} {
+		rb, err := block.ProcessBlockByBinData(data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = rb.Check(); err != nil {
+			t.Fatalf("checking block on the second node: %v", err)
+		}
+		if err = rb.PlaySafe(); err != nil {
+			t.Fatalf("playing block on the second node: %v", err)
+		}
+	}
+	if got := first(); got != want {
+		t.Errorf("the second node has taken the first row %s, expected %s", got, want)
+	}
+}
+
+// orderHook records the hashes of the executed transactions
+type orderHook struct {
+	hashes [][]byte
+	errs   []error
+}
+
+func (h *orderHook) BeforeTx(_ *block.Block, t *transaction.Transaction) {
+	h.hashes = append(h.hashes, t.Hash())
+}
+
+func (h *orderHook) AfterTx(_ *block.Block, e *block.TxExecution) {
+	if e.Err != nil {
+		h.errs = append(h.errs, e.Err)
+	}
+}
+
+// TestPlaySafeTxTypeOrder plays the block of the generator before the activation of tx_type_order whose
+// utxo transaction goes before the contract one. The nodes play it in the order of the block, after the
+// activation the contract transactions are played first
+func TestPlaySafeTxTypeOrder(t *testing.T) {
+	db := startPostgres(t)
+	c := newTestChain(t, db)
+	hook := &orderHook{}
+	block.RegisterTxHook("order", hook)
+	defer block.RegisterTxHook("order", nil)
+	checkOrder := func(txs ...[]byte) {
+		t.Helper()
+		if len(hook.errs) > 0 {
+			t.Fatalf("transactions have failed: %v", hook.errs)
+		}
+		if len(hook.hashes) != len(txs) {
+			t.Fatalf("expected %d played transactions, got %d", len(txs), len(hook.hashes))
+		}
+		for i, data := range txs {
+			if !bytes.Equal(hook.hashes[i], c.txHash(data)) {
+				t.Errorf("wrong transaction on %d position", i)
+			}
+		}
+		hook.hashes = nil
+	}
+	// the consensus state of the transactions, the local tables of the nodes differ
+	state := func() map[string]string {
+		t.Helper()
+		sums := make(map[string]string)
+		for _, table := range []string{"spent_info", "1_keys", "1_history", "1_parameters", "rollback_tx"} {
+			var sum string
+			if err := sqldb.DBConn.Raw(fmt.Sprintf(`SELECT coalesce(md5(string_agg(t::text, ',' ORDER BY t::text)), '') FROM "%s" t`, table)).
+				Scan(&sum).Error; err != nil {
+				t.Fatalf("checksum of %s: %v", table, err)
+			}
+			sums[table] = sum
+		}
+		return sums
+	}
+
+	utxo := c.sectionTx(types.SmartTransaction{UTXO: &types.UTXO{ToID: 1000, Value: "1000000"}}, c.start+2)
+	contract := c.newParameterTx("type_order_before", c.start+2)
+	b2 := c.nextBlock(utxo, contract)
+	if err := b2.PlaySafe(); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(utxo, contract)
+	want := state()
+
+	if err := sqldb.DBConn.Exec(`CREATE DATABASE ibax_replica`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := sqldb.GormClose(); err != nil {
+		t.Fatal(err)
+	}
+	replicaDB := db
+	replicaDB.Name = "ibax_replica"
+	r := c.replica(replicaDB)
+	hook.hashes = nil
+	rb, err := block.ProcessBlockByBinData(b2.BinData, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rb.Check(); err != nil {
+		t.Fatalf("checking block on the second node: %v", err)
+	}
+	if err = rb.PlaySafe(); err != nil {
+		t.Fatalf("playing block on the second node: %v", err)
+	}
+	checkOrder(utxo, contract)
+	got := state()
+	for table, sum := range want {
+		if got[table] != sum {
+			t.Errorf("table %s differs on the second node", table)
+		}
+	}
+
+	if err = sqldb.DBConn.Exec(`UPDATE "1_platform_parameters" SET value = '1' WHERE name = ?`, syspar.TxTypeOrder).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err = syspar.SysUpdate(nil); err != nil {
+		t.Fatal(err)
+	}
+	utxo = r.sectionTx(types.SmartTransaction{UTXO: &types.UTXO{ToID: 1000, Value: "1000000"}}, r.start+3)
+	contract = r.newParameterTx("type_order_after", r.start+3)
+	r.playBlock(utxo, contract)
+	checkOrder(contract, utxo)
+}
+
+// TestPlaySafeSavepointBatch generates the block whose batches have the failed transactions in the middle,
+// the block has the same rollback records, receipts and state root as the block without the batches
+func TestPlaySafeSavepointBatch(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	setParam := func(name, value string) {
+		t.Helper()
+		if err := sqldb.DBConn.Exec(`UPDATE "1_platform_parameters" SET value = ? WHERE name = ?`, value, name).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := syspar.SysUpdate(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setParam(syspar.StateRoot, "1")
+	newContract := func(source string) []byte {
+		return c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true", "Value": source}, c.start+1)
+	}
+	// @1UpdatePlatformParam calls the contract of the name of the parameter
+	c.playBlock(
+		newContract(`contract max_columns { data { Value string } action {} }`),
+		newContract(`contract max_indexes { data { Value string } action {} }`),
+		newContract(`contract max_tx_block_per_user { data { Value string } action {} }`),
+		newContract(`contract TestBatchFail { action {
+			CallContract("@1UpdatePlatformParam", {"Name": "max_indexes", "Value": "3"})
+			error "batch failure"
+		} }`))
+
+	update := func(name, value string) []byte {
+		return c.newContractTx("UpdatePlatformParam", map[string]any{"Name": name, "Value": value}, c.start+2)
+	}
+	failed := [][]byte{
+		c.newContractTx("TestBatchFail", map[string]any{}, c.start+2),
+		c.sectionTx(types.SmartTransaction{UTXO: &types.UTXO{ToID: 1001, Value: "1000000000000000000000000000000000"}}, c.start+2),
+	}
+	txs := [][]byte{
+		update(syspar.MaxColumns, "40"), failed[0], update(syspar.MaxBlockUserTx, "4000"),
+		c.sectionTx(types.SmartTransaction{UTXO: &types.UTXO{ToID: 1000, Value: "1000000"}}, c.start+2),
+		failed[1],
+		c.sectionTx(types.SmartTransaction{UTXO: &types.UTXO{ToID: 1002, Value: "2000000"}}, c.start+2),
+	}
+	type result struct {
+		rollbacks []string
+		receipts  []string
+		stateRoot string
+	}
+	generate := func(batch string) result {
+		t.Helper()
+		setParam(syspar.SavepointBatch, batch)
+		c.generateBlock(txs...)
+		var r result
+		rollbacks, err := (&sqldb.RollbackTx{}).GetBlockRollbackTransactions(nil, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rt := range rollbacks {
+			r.rollbacks = append(r.rollbacks, fmt.Sprintf("%x %s %s %s", rt.TxHash, rt.NameTable, rt.TableID, rt.Data))
+		}
+		var receipts []sqldb.TxReceipt
+		if err = sqldb.DBConn.Where("block_id = ?", 3).Order("tx_hash").Find(&receipts).Error; err != nil {
+			t.Fatal(err)
+		}
+		for _, receipt := range receipts {
+			r.receipts = append(r.receipts, fmt.Sprintf("%+v", receipt))
+		}
+		if len(receipts) != len(txs)-len(failed) {
+			t.Errorf("savepoint_batch %s: expected %d receipts, got %d", batch, len(txs)-len(failed), len(receipts))
+		}
+		for _, data := range failed {
+			receipt := &sqldb.TxReceipt{}
+			if found, err := receipt.GetByHash(nil, c.txHash(data)); err != nil || found {
+				t.Errorf("savepoint_batch %s: failed transaction has the receipt %v", batch, err)
+			}
+		}
+		bl := &sqldb.BlockChain{}
+		if found, err := bl.Get(3); err != nil || !found {
+			t.Fatalf("getting block 3: %v", err)
+		}
+		header, err := types.ParseBlockHeader(bytes.NewBuffer(bl.Data), syspar.GetMaxBlockSize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(header.StateRoot) == 0 {
+			t.Fatalf("savepoint_batch %s: block has no state root", batch)
+		}
+		r.stateRoot = hex.EncodeToString(header.StateRoot)
+		// the parameter of the failed transaction is discarded
+		for name, value := range map[string]string{syspar.MaxColumns: "40", syspar.MaxIndexes: "5", syspar.MaxBlockUserTx: "4000"} {
+			if got := syspar.SysString(name); got != value {
+				t.Errorf("savepoint_batch %s: %s is %s, expected %s", batch, name, got, value)
+			}
+		}
+		c.rollbackTo(2)
+		return r
+	}
+
+	want := generate("0")
+	got := generate("3")
+	if !reflect.DeepEqual(want.rollbacks, got.rollbacks) {
+		t.Errorf("rollback records differ:\n%s\n%s", strings.Join(want.rollbacks, "\n"), strings.Join(got.rollbacks, "\n"))
+	}
+	if !reflect.DeepEqual(want.receipts, got.receipts) {
+		t.Errorf("receipts differ:\n%s\n%s", strings.Join(want.receipts, "\n"), strings.Join(got.receipts, "\n"))
+	}
+	if want.stateRoot != got.stateRoot {
+		t.Errorf("state roots differ: %s %s", want.stateRoot, got.stateRoot)
+	}
+}
+
+func TestPlaySafeAbstractAccount(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	contract := smart.VMGetContract(script.GetVM(), "NewParameter", 1)
+	abstractTx := func(name, authData string, now int64) []byte {
+		t.Helper()
+		data, _, err := transaction.NewAbstractAccountTransaction(types.SmartTransaction{
+			Header: &types.Header{
+				ID:          int(contract.Info().ID),
+				EcosystemID: 1,
+				KeyID:       c.keyID,
+				Time:        now,
+				NetworkID:   testNetworkID,
+			},
+			Params: map[string]any{"Name": name, "Value": name, "Conditions": "true"},
+		}, []byte(authData))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	rejected := func(data []byte) {
+		t.Helper()
+		if err := c.nextBlock(data).PlaySafe(); !errors.Is(err, smart.ErrAuthContract) {
+			t.Fatalf("expected %v, got %v", smart.ErrAuthContract, err)
+		}
+	}
+
+	c.playBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract TestAuth { action { $result = BytesToString($AuthData) == "secret" } }`}, c.start+1))
+	// the key without the auth contract accepts the signed transactions only
+	rejected(abstractTx("abstract_first", "secret", c.start+2))
+
+	c.playBlock(c.newContractTx("SetAuthContract", map[string]any{"Contract": "TestAuth"}, c.start+2))
+	rejected(abstractTx("abstract_wrong", "wrong", c.start+3))
+	c.playBlock(abstractTx("abstract_second", "secret", c.start+3))
+
+	var names []string
+	if err := sqldb.DBConn.Raw(`SELECT name FROM "1_parameters" WHERE name LIKE 'abstract_%' ORDER BY name`).
+		Scan(&names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "abstract_second" {
+		t.Errorf("wrong parameters of the abstract account transactions %v", names)
+	}
+	// the signed transactions of the key are still accepted
+	c.playBlock(c.newParameterTx("signed_param", c.start+4))
+}
+
+func TestPlaySafeDelayedResults(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	delayedTx := c.delayedTx
+
+	for i, value := range []string{
+		`contract TestDelayedOk { action { $result = "done" } }`,
+		`contract TestDelayedFail { action { error "broken" } }`,
+		`contract TestDelayedCallback { action {
+			CallContract("@1NewParameter", {"Name": Sprintf("delayed_%d_%v", $DelayedId, $Success),
+				"Value": Sprintf("%s %s", $Contract, $Result), "Conditions": "true"})
+		} }`,
+		`contract TestDelayedBadCallback { action {
+			CallContract("@1NewParameter", {"Name": "delayed_bad", "Value": "bad", "Conditions": "true"})
+			error "callback broken"
+		} }`,
+	} {
+		c.playBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+			"Value": value}, c.start+int64(i)+1))
+	}
+	delayed := map[string]int64{}
+	for _, d := range []struct{ contract, callback string }{
+		{"@1TestDelayedOk", "@1TestDelayedCallback"},
+		{"@1TestDelayedFail", "@1TestDelayedCallback"},
+		{"@1TestDelayedOk", "@1TestDelayedBadCallback"},
+	} {
+		var id int64
+		if err := sqldb.DBConn.Raw(`INSERT INTO "1_delayed_contracts" (id, contract, key_id, block_id, every_block,
+			"limit", conditions, callback) VALUES (next_id('1_delayed_contracts'), ?, ?, 1, 1, 1, 'true', ?) RETURNING id`,
+			d.contract, c.keyID, d.callback).Scan(&id).Error; err != nil {
+			t.Fatal(err)
+		}
+		delayed[d.contract+d.callback] = id
+	}
+
+	// the delayed contracts of the same contract and key are run one by one, the limited ones are skipped
+	c.playBlock(delayedTx("TestDelayedOk", c.start+5), delayedTx("TestDelayedFail", c.start+5))
+	c.playBlock(delayedTx("TestDelayedOk", c.start+6))
+
+	for _, r := range []struct {
+		key, result, callbackErr string
+		success                  int64
+	}{
+		{"@1TestDelayedOk@1TestDelayedCallback", "done", "", 1},
+		{"@1TestDelayedFail@1TestDelayedCallback", "broken", "", 0},
+		{"@1TestDelayedOk@1TestDelayedBadCallback", "done", "callback broken", 1},
+	} {
+		results, err := sqldb.GetDelayedResults(nil, delayed[r.key])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("%s: expected one result, got %d", r.key, len(results))
+		}
+		got := results[0]
+		if got.Success != r.success || !strings.Contains(got.Result, r.result) || got.KeyID != c.keyID ||
+			!strings.Contains(got.CallbackError, r.callbackErr) || (r.callbackErr == "") != (got.CallbackError == "") {
+			t.Errorf("%s: wrong result %+v", r.key, got)
+		}
+		if got.Fuel <= 0 {
+			t.Errorf("%s: no fuel in the result", r.key)
+		}
+		var counter int64
+		if err = sqldb.DBConn.Raw(`SELECT counter FROM "1_delayed_contracts" WHERE id = ?`, delayed[r.key]).
+			Scan(&counter).Error; err != nil {
+			t.Fatal(err)
+		}
+		if counter != 1 {
+			t.Errorf("%s: expected counter 1, got %d", r.key, counter)
+		}
+	}
+
+	params := map[string]string{}
+	var rows []struct{ Name, Value string }
+	if err := sqldb.DBConn.Raw(`SELECT name, value FROM "1_parameters" WHERE name LIKE 'delayed_%'`).
+		Scan(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		params[row.Name] = row.Value
+	}
+	ok := fmt.Sprintf("delayed_%d_true", delayed["@1TestDelayedOk@1TestDelayedCallback"])
+	failed := fmt.Sprintf("delayed_%d_false", delayed["@1TestDelayedFail@1TestDelayedCallback"])
+	if len(params) != 2 || params[ok] != "@1TestDelayedOk done" || !strings.HasPrefix(params[failed], "@1TestDelayedFail ") ||
+		!strings.Contains(params[failed], "broken") {
+		t.Errorf("wrong parameters of the callbacks %v", params)
+	}
+
+	var notifications int64
+	if err := sqldb.DBConn.Raw(`SELECT count(*) FROM "1_notifications" WHERE recipient->>'account' = ?`,
+		converter.AddressToString(c.keyID)).Scan(&notifications).Error; err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 3 {
+		t.Errorf("expected 3 notifications, got %d", notifications)
+	}
+}
+
+func TestPlaySafeDataAvailability(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	store, err := dastore.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dastore.DA = store
+	defer func() { dastore.DA = nil }()
+
+	c.playBlock(c.newParameterTx("da_first", c.start+2))
+	played := snapshot(t)
+	stored := &sqldb.BlockChain{}
+	if _, err = stored.Get(2); err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.TxData) == 0 {
+		t.Fatal("block keeps the transactions on-chain")
+	}
+	if header, err := block.UnmarshallBlock(bytes.NewBuffer(stored.Data), false); err != nil || len(header.Transactions) != 0 {
+		t.Fatalf("block_chain has the transactions %v", err)
+	}
+	full, err := block.UnmarshallBlockChain(stored, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(full.Transactions) != 1 || !bytes.Equal(full.MerkleRoot, full.GenMerkleRoot()) {
+		t.Fatalf("wrong transactions of the retrieved block %d", len(full.Transactions))
+	}
+
+	// the rolled back block is played again from the data availability layer
+	c.rollbackTo(1)
+	loaded, err := block.LoadBlock(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = loaded.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if err = loaded.PlaySafe(); err != nil {
+		t.Fatal(err)
+	}
+	compareSnapshots(t, played, snapshot(t))
+
+	c.rollbackTo(1)
+	dastore.DA = nil
+	if loaded, err = block.LoadBlock(stored); err != nil {
+		t.Fatal(err)
+	}
+	if err = loaded.Check(); !errors.Is(err, dastore.ErrDisabled) {
+		t.Errorf("expected %v, got %v", dastore.ErrDisabled, err)
+	}
+}
+
+func TestPlaySafeBlockContext(t *testing.T) {
+	db := startPostgres(t)
+	c := newTestChain(t, db)
+	const delayedID = 100
+	addDelayed := func() {
+		t.Helper()
+		if err := sqldb.DBConn.Exec(`INSERT INTO "1_delayed_contracts" (id, contract, key_id, block_id, every_block,
+			"limit", conditions) VALUES (?, '@1TestContext', ?, 1, 1, 1, 'true')`, delayedID, c.keyID).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	contract := func(name string) int {
+		return int(smart.VMGetContract(script.GetVM(), name, 1).Info().ID)
+	}
+	header := func(name string, now int64) *types.Header {
+		return &types.Header{ID: contract(name), EcosystemID: 1, KeyID: c.keyID, Time: now, NetworkID: testNetworkID}
+	}
+	// genBlock generates the next block like the block generator and inserts it
+	var blocks [][]byte
+	genBlock := func(txs ...[]byte) {
+		t.Helper()
+		info := &sqldb.InfoBlock{}
+		if _, err := info.Get(); err != nil {
+			t.Fatal(err)
+		}
+		baseGasPrice, err := block.BaseGasPriceOf(info.BlockID + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		built, err := block.NormalBlockBuilder(&types.BlockHeader{
+			BlockId:       info.BlockID,
+			Timestamp:     c.start + info.BlockID,
+			NetworkId:     testNetworkID,
+			BlockHash:     info.Hash,
+			RollbacksHash: info.RollbacksHash,
+		}).
+			WithKeyID(c.keyID).
+			WithBaseGasPrice(baseGasPrice).
+			WithGenBlock(true).
+			WithDelayedContracts("@1TestContext").
+			AddRawTransaction(txs...).
+			WithSigner(syspar.GetNodeSigner()).
+			Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = block.InsertBlockWOForksNew(built.BinData, built.ClassifyTxsMap, true, false); err != nil {
+			t.Fatalf("generating block %d: %v", info.BlockID+1, err)
+		}
+		blocks = append(blocks, built.BinData)
+	}
+
+	genBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract TestContext { action {
+			CallContract("@1NewParameter", {"Name": Sprintf("ctx_%d_%d", $tx_type, $block_id),
+				"Value": Sprintf("%d %s", $block_time, $tx_hash), "Conditions": "true"})
+		} }`}, c.start+1))
+	genBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract TestAuth { action { $result = true } }`}, c.start+2))
+	genBlock(c.newContractTx("SetAuthContract", map[string]any{"Contract": "TestAuth"}, c.start+3))
+	addDelayed()
+
+	signed, _, err := transaction.NewTransactionInProc(types.SmartTransaction{
+		Header: header("TestContext", c.start+4), Params: map[string]any{}}, c.privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abstract, _, err := transaction.NewAbstractAccountTransaction(types.SmartTransaction{
+		Header: header("TestContext", c.start+5), Params: map[string]any{}}, []byte("auth"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	delayed, _, err := transaction.NewInternalTransaction(types.SmartTransaction{
+		Header: header("TestContext", c.start+6), SignedBy: c.keyID, Params: map[string]any{}}, c.privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genBlock(signed)
+	genBlock(abstract)
+	genBlock(delayed)
+
+	params := func() map[string]string {
+		t.Helper()
+		var rows []struct{ Name, Value string }
+		if err := sqldb.DBConn.Raw(`SELECT name, value FROM "1_parameters" WHERE name LIKE 'ctx_%'`).
+			Scan(&rows).Error; err != nil {
+			t.Fatal(err)
+		}
+		list := make(map[string]string, len(rows))
+		for _, row := range rows {
+			list[row.Name] = row.Value
+		}
+		return list
+	}
+	hashOf := func(data []byte) string {
+		tx, err := transaction.UnmarshallTransaction(bytes.NewBuffer(data), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hex.EncodeToString(tx.Hash())
+	}
+	generated := params()
+	for _, want := range []struct {
+		txType, blockID int
+		tx              []byte
+	}{
+		{types.SmartContractTxType, 5, signed},
+		{types.SmartContractTxType, 6, abstract},
+		{types.DelayTxType, 7, delayed},
+	} {
+		name := fmt.Sprintf("ctx_%d_%d", want.txType, want.blockID)
+		if value := fmt.Sprintf("%d %s", c.start+int64(want.blockID)-1, hashOf(want.tx)); generated[name] != value {
+			t.Errorf("%s: expected %q, got %q", name, value, generated[name])
+		}
+	}
+	if len(generated) != 3 {
+		t.Errorf("wrong parameters %v", generated)
+	}
+	consensus := func() map[string]string {
+		sums := snapshot(t)
+		return map[string]string{"1_parameters": sums["1_parameters"], "rollback_tx": sums["rollback_tx"],
+			"1_delayed_contracts": sums["1_delayed_contracts"]}
+	}
+	want := consensus()
+
+	// the validator writes the same state
+	if err = sqldb.DBConn.Exec(`CREATE DATABASE ibax_replica`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err = sqldb.GormClose(); err != nil {
+		t.Fatal(err)
+	}
+	replicaDB := db
+	replicaDB.Name = "ibax_replica"
+	c.replica(replicaDB)
+	for i, data := range blocks {
+		if i == 3 {
+			addDelayed()
+		}
+		rb, err := block.ProcessBlockByBinData(data, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = rb.Check(); err != nil {
+			t.Fatalf("checking block on the validator: %v", err)
+		}
+		if err = rb.PlaySafe(); err != nil {
+			t.Fatalf("playing block on the validator: %v", err)
+		}
+	}
+	compareSnapshots(t, want, consensus())
+}
+
+func TestPlaySafeRollbackLimit(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	c.playBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract TestRollbacks {
+			data {
+				Prefix string
+				Count int
+			}
+			action {
+				var i int
+				while i < $Count {
+					CallContract("@1NewParameter", {"Name": Sprintf("%s_%d", $Prefix, i), "Value": "1", "Conditions": "true"})
+					i = i + 1
+				}
+			}
+		}`}, c.start+1))
+	rollbacksTx := func(prefix string, now int64) []byte {
+		return c.newContractTx("TestRollbacks", map[string]any{"Prefix": prefix, "Count": 3}, now)
+	}
+	setLimit := func(limit int64) {
+		t.Helper()
+		if err := sqldb.DBConn.Exec(`UPDATE "1_platform_parameters" SET value = ? WHERE name = ?`,
+			converter.Int64ToStr(limit), syspar.MaxTxRollbacks).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := syspar.SysUpdate(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := func(query string, args ...any) int64 {
+		t.Helper()
+		var n int64
+		if err := sqldb.DBConn.Raw(query, args...).Scan(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	first := rollbacksTx("rollbacks_first", c.start+2)
+	c.playBlock(first)
+	tx, err := transaction.UnmarshallTransaction(bytes.NewBuffer(first), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := count(`SELECT count(*) FROM rollback_tx WHERE tx_hash = ?`, tx.Hash())
+	if entries == 0 {
+		t.Fatal("no rollback entries of the transaction")
+	}
+
+	// the transaction which has the entries of the limit is played
+	setLimit(entries)
+	c.playBlock(rollbacksTx("rollbacks_under", c.start+3))
+
+	setLimit(entries - 1)
+	info := &sqldb.InfoBlock{}
+	if _, err = info.Get(); err != nil {
+		t.Fatal(err)
+	}
+	over := c.nextBlock(rollbacksTx("rollbacks_over", c.start+4))
+	if err = over.PlaySafe(); err == nil || !strings.Contains(err.Error(), smart.ErrRollbackLimit.Error()) {
+		t.Fatalf("expected %v, got %v", smart.ErrRollbackLimit, err)
+	}
+	if last := count(`SELECT max(id) FROM block_chain`); last != info.BlockID {
+		t.Errorf("the rejected block %d is committed", last)
+	}
+	if n := count(`SELECT count(*) FROM "1_parameters" WHERE name LIKE 'rollbacks_over_%'`); n != 0 {
+		t.Errorf("the writes of the rejected transaction are kept %d", n)
+	}
+	if n := count(`SELECT count(*) FROM "1_parameters" WHERE name LIKE 'rollbacks_under_%'`); n != 3 {
+		t.Errorf("expected 3 parameters under the limit, got %d", n)
+	}
+}
+
+// TestPlaySafeMerkleProof verifies the proofs of the transactions of the played block by the contract function
+// and reads the headers of the plain, the compressed and the data availability blocks
+func TestPlaySafeMerkleProof(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	play := func(txs ...[]byte) *block.Block {
+		t.Helper()
+		b := c.nextBlock(txs...)
+		if err := b.PlaySafe(); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	header := func(current, blockID int64) (*types.Map, error) {
+		sc := &smart.SmartContract{BlockHeader: &types.BlockHeader{BlockId: current}}
+		return smart.GetBlockHeader(sc, blockID)
+	}
+	checkHeader := func(b *block.Block) {
+		t.Helper()
+		h, err := header(b.Header.BlockId+1, b.Header.BlockId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key, want := range map[string]any{
+			"id":          b.Header.BlockId,
+			"hash":        hex.EncodeToString(b.Header.BlockHash),
+			"merkle_root": string(b.MerkleRoot),
+			"tx_count":    int64(len(b.TxFullData)),
+		} {
+			if got, _ := h.Get(key); got != want {
+				t.Errorf("block %d: expected %s %v, got %v", b.Header.BlockId, key, want, got)
+			}
+		}
+	}
+
+	txs := [][]byte{c.newParameterTx("merkle_a", c.start+2), c.newParameterTx("merkle_b", c.start+2),
+		c.newParameterTx("merkle_c", c.start+2)}
+	b2 := play(txs...)
+	stored := &sqldb.BlockChain{}
+	if found, err := stored.Get(2); err != nil || !found {
+		t.Fatalf("block 2 isn't found: %v", err)
+	}
+	data := &types.BlockData{}
+	if err := data.UnmarshallBlock(stored.Data); err != nil {
+		t.Fatal(err)
+	}
+	leaves := data.LeafHashes()
+	for i, tx := range txs {
+		var proof []any
+		for _, item := range types.MerkleTreeProof(leaves, i) {
+			side := "R:"
+			if item.Left {
+				side = "L:"
+			}
+			proof = append(proof, side+string(item.Hash))
+		}
+		leaf := string(converter.BinToHex(crypto.DoubleHash(tx)))
+		if ok, err := smart.VerifyMerkleProof(string(b2.MerkleRoot), leaf, proof); err != nil || !ok {
+			t.Errorf("proof of transaction %d isn't valid: %v", i, err)
+		}
+	}
+	checkHeader(b2)
+	// the played block isn't committed yet and the later blocks aren't known
+	for _, blockID := range []int64{0, 3, 4} {
+		if _, err := header(3, blockID); err == nil {
+			t.Errorf("header of block %d is returned", blockID)
+		}
+	}
+	if _, err := smart.GetBlockHeader(&smart.SmartContract{}, 100); err == nil {
+		t.Error("header of unknown block is returned")
+	}
+
+	if err := sqldb.DBConn.Exec(`UPDATE "1_platform_parameters" SET value = '1' WHERE name = ?`, syspar.BlockCompression).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := syspar.SysUpdate(nil); err != nil {
+		t.Fatal(err)
+	}
+	b3 := play(c.newParameterTx("merkle_compressed", c.start+3))
+	var raw []byte
+	if err := sqldb.DBConn.Raw(`SELECT data FROM block_chain WHERE id = 3`).Row().Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	if !types.IsCompressedBlock(raw) {
+		t.Fatal("block 3 isn't compressed")
+	}
+	checkHeader(b3)
+
+	store, err := dastore.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dastore.DA = store
+	defer func() { dastore.DA = nil }()
+	b4 := play(c.newParameterTx("merkle_da", c.start+4))
+	stored = &sqldb.BlockChain{}
+	if _, err = stored.Get(4); err != nil || len(stored.TxData) == 0 {
+		t.Fatalf("block 4 keeps the transactions on-chain: %v", err)
+	}
+	checkHeader(b4)
+}
+
+// TestPlaySafeOracle plays the commits, the reveals and the resolution of the oracle requests by three
+// honor nodes. The third node reveals the wrong value, the second request misses the reveal of the second
+// node and it's resolved as failed after the deadline
+func TestPlaySafeOracle(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	server := func(price string) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data":{"pairs":[{"name":"IBXC/USD","price":%s}]}}`, price)
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	good, wrong := server("12.5"), server("13")
+
+	// the honor nodes and their keys, the first node generates the blocks
+	keys := [][]byte{c.privateKey}
+	publicKey, err := crypto.PrivateToPublic(c.privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := []*syspar.HonorNode{{TCPAddress: "127.0.0.1:7078", APIAddress: "http://127.0.0.1:7079", PublicKey: publicKey}}
+	for i := 1; i < 3; i++ {
+		priv, _, err := crypto.GenHexKeys()
+		if err != nil {
+			t.Fatal(err)
+		}
+		privateKey, _ := hex.DecodeString(priv)
+		if publicKey, err = crypto.PrivateToPublic(privateKey); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, privateKey)
+		nodes = append(nodes, &syspar.HonorNode{TCPAddress: fmt.Sprintf("127.0.0.1:%d", 7078+i*10),
+			APIAddress: fmt.Sprintf("http://127.0.0.1:%d", 7079+i*10), PublicKey: publicKey})
+		keyID := crypto.Address(publicKey)
+		if err = sqldb.DBConn.Exec(`INSERT INTO "1_keys" (id, account, pub, amount) VALUES (?, ?, ?, 0)`,
+			keyID, converter.AddressToString(keyID), publicKey).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	honorNodes, err := json.Marshal(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = sqldb.DBConn.Exec(`UPDATE "1_platform_parameters" SET value = ? WHERE name = ?`, string(honorNodes), syspar.HonorNodes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err = syspar.SysUpdate(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	play := func(txs ...[]byte) {
+		t.Helper()
+		b := c.nextBlock(txs...)
+		if err := b.PlaySafe(); err != nil {
+			t.Fatalf("playing block %d: %v", b.Header.BlockId, err)
+		}
+		if len(b.TxFullData) != len(txs) {
+			t.Fatalf("block %d has %d transactions of %d", b.Header.BlockId, len(b.TxFullData), len(txs))
+		}
+	}
+	request := func(id int64) sqldb.OracleRequest {
+		t.Helper()
+		var r sqldb.OracleRequest
+		if err := sqldb.DBConn.Where("id = ?", id).First(&r).Error; err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	play(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+		"Value": `contract OracleTestCallback {
+			data {
+				RequestId int
+				Value string
+				Failed int
+			}
+			action {
+				EmitEvent("OracleResult", {"RequestId": $RequestId, "Value": $Value, "Failed": $Failed})
+			}
+		}`}, c.start+1))
+	const path = "data.pairs.0.price"
+	deadline := c.start + 5
+	play(
+		c.newContractTx("OracleRequest", map[string]any{"Url": good, "JsonPath": path, "Callback": "OracleTestCallback",
+			"Deadline": c.start + 100, "Threshold": 2}, c.start+2),
+		c.newContractTx("OracleRequest", map[string]any{"Url": good, "JsonPath": path, "Callback": "OracleTestCallback",
+			"Deadline": deadline, "Threshold": 2}, c.start+2),
+	)
+	var ids []int64
+	if err = sqldb.DBConn.Raw(`SELECT id FROM "1_oracle_requests" ORDER BY id`).Scan(&ids).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(ids))
+	}
+	agreed, missed := ids[0], ids[1]
+
+	// every node fetches the value and commits its hash
+	values := make([]string, len(keys))
+	for i, url := range []string{good, good, wrong} {
+		if values[i], err = oracle.Fetch(context.Background(), url, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	salt := func(requestID int64, node int) string {
+		return fmt.Sprintf("salt%d_%d", requestID, node)
+	}
+	commit := func(requestID int64, node int) []byte {
+		return c.newContractTxOf(keys[node], "OracleCommit", map[string]any{"RequestId": requestID,
+			"Hash": oracle.CommitHash(requestID, values[node], salt(requestID, node))}, c.start+3)
+	}
+	reveal := func(requestID int64, node int, now int64) []byte {
+		return c.newContractTxOf(keys[node], "OracleReveal", map[string]any{"RequestId": requestID,
+			"Value": values[node], "Salt": salt(requestID, node)}, now)
+	}
+	play(commit(agreed, 0), commit(agreed, 1), commit(agreed, 2), commit(missed, 0), commit(missed, 1))
+	answers, err := sqldb.GetOracleAnswers(agreed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 3 {
+		t.Fatalf("expected 3 commits, got %d", len(answers))
+	}
+
+	// the wrong value is revealed first, the values of the first two reveals don't reach the threshold
+	play(reveal(agreed, 2, c.start+4), reveal(agreed, 0, c.start+4), reveal(missed, 0, c.start+4))
+	if r := request(agreed); r.Status != sqldb.OracleStatusPending {
+		t.Fatalf("request is resolved by the different values: %+v", r)
+	}
+	play(reveal(agreed, 1, c.start+5),
+		c.newContractTxOf(keys[2], "OracleResolve", map[string]any{"RequestId": missed}, c.start+5))
+	if r := request(agreed); r.Status != sqldb.OracleStatusDone || r.Value != "12.5" {
+		t.Errorf("expected the agreed value 12.5, got %+v", r)
+	}
+	if r := request(missed); r.Status != sqldb.OracleStatusFailed || r.Value != "" {
+		t.Errorf("expected the failed request without the reveal, got %+v", r)
+	}
+	if answers, err = sqldb.GetOracleAnswers(missed); err != nil {
+		t.Fatal(err)
+	}
+	revealed := 0
+	for _, a := range answers {
+		revealed += int(a.Revealed)
+	}
+	if len(answers) != 2 || revealed != 1 {
+		t.Errorf("expected 2 commits and 1 reveal of the failed request, got %d and %d", len(answers), revealed)
+	}
+
+	var results []string
+	if err = sqldb.DBConn.Raw(`SELECT data::text FROM contract_events WHERE event_name = 'OracleResult' ORDER BY block_id, log_index`).
+		Scan(&results).Error; err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]any{
+		{"RequestId": float64(agreed), "Value": "12.5", "Failed": float64(0)},
+		{"RequestId": float64(missed), "Value": "", "Failed": float64(1)},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d callbacks, got %v", len(expected), results)
+	}
+	for i, data := range results {
+		got := make(map[string]any)
+		if err = json.Unmarshal([]byte(data), &got); err != nil {
+			t.Fatal(err)
+		}
+		for key, want := range expected[i] {
+			if got[key] != want {
+				t.Errorf("callback %d: expected %s %v, got %v", i, key, want, got[key])
+			}
+		}
+	}
+}
+
+func TestAuditLogTamper(t *testing.T) {
+	newTestChain(t, startPostgres(t))
+	entries := make([]*sqldb.AuditLog, 3)
+	for i := range entries {
+		entries[i] = sqldb.NewAuditLog(sqldb.AuditRollback, int64(i+1), int64(i+1), nil, []byte{byte(i)})
+		if err := entries[i].Create(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if broken, _, err := sqldb.VerifyAuditLog(); err != nil || broken != 0 {
+		t.Fatalf("valid log is broken at %d, error %v", broken, err)
+	}
+	if err := sqldb.DBConn.Exec(`UPDATE audit_log SET key_id = key_id + 1 WHERE id = ?`, entries[1].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	broken, valid, err := sqldb.VerifyAuditLog()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if broken != entries[1].ID || valid != entries[0].ID {
+		t.Errorf("tampered entry %d: got broken %d, valid %d", entries[1].ID, broken, valid)
+	}
+}
+
+// stopNetworkCert returns the self-signed certificate which is its own bundle
+func stopNetworkCert(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &stdx509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "stop network"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              stdx509.KeyUsageCertSign | stdx509.KeyUsageDigitalSignature,
+	}
+	der, err := stdx509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// TestPlaySafeStopNetworkAudit checks that the stop network transaction is audited even though its block is rolled back
+func TestPlaySafeStopNetworkAudit(t *testing.T) {
+	// the time of the stop network transaction is now, it isn't after the time of the block
+	c := newTestChainAt(t, startPostgres(t), time.Now().Unix())
+	defer node.PauseNodeActivity(node.NoPause)
+	cert := stopNetworkCert(t)
+	first, err := syspar.GetFirstBlockData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	withBundle := *first
+	withBundle.StopNetworkCertBundle = cert
+	syspar.SetFirstBlockData(&withBundle)
+	defer syspar.SetFirstBlockData(first)
+
+	stop, err := new(transaction.StopNetworkParser).BinMarshal(&types.StopNetwork{KeyID: c.keyID, Time: time.Now().Unix(), StopNetworkCert: cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := c.nextBlock(stop)
+	if err = b.PlaySafe(); !errors.Is(err, transaction.ErrNetworkStopping) {
+		t.Fatalf("expected %v, got %v", transaction.ErrNetworkStopping, err)
+	}
+	if node.NodePauseType() != node.PauseTypeStopingNetwork {
+		t.Error("node isn't paused")
+	}
+	info := &sqldb.InfoBlock{}
+	if _, err = info.Get(); err != nil {
+		t.Fatal(err)
+	}
+	if info.BlockID != b.Header.BlockId-1 {
+		t.Errorf("block %d isn't rolled back, the last block is %d", b.Header.BlockId, info.BlockID)
+	}
+	entries, total, err := sqldb.GetAuditLogs(sqldb.AuditStopNetwork, 0, 0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || entries[0].BlockID != b.Header.BlockId || entries[0].KeyID != c.keyID ||
+		!bytes.Equal(entries[0].TxHash, c.txHash(stop)) {
+		t.Fatalf("expected the stop network entry of block %d, got %d %+v", b.Header.BlockId, total, entries)
+	}
+	if broken, _, err := sqldb.VerifyAuditLog(); err != nil || broken != 0 {
+		t.Errorf("audit log is broken at %d, error %v", broken, err)
+	}
+}
+
+// TestPlaySafeIdentityRegistry registers and verifies the identities, the change of the identity by its key
+// resets the verification and the rollback removes the identities
+func TestPlaySafeIdentityRegistry(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	genesis := snapshot(t)
+	identity := func(keyID int64) *sqldb.Identity {
+		t.Helper()
+		i := &sqldb.Identity{}
+		if found, err := i.Get(1, keyID); err != nil || !found {
+			t.Fatalf("getting identity of %d: %v", keyID, err)
+		}
+		return i
+	}
+	events := func(keyID int64) (verifiers []int64) {
+		t.Helper()
+		if err := sqldb.DBConn.Raw(`SELECT verified_by_key_id FROM "1_identity_events"
+			WHERE key_id = ? AND ecosystem = 1 AND event = 'IdentityUpdatedEvent' ORDER BY id`, keyID).
+			Scan(&verifiers).Error; err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	rejected := func(data []byte) {
+		t.Helper()
+		if err := c.nextBlock(data).PlaySafe(); err == nil {
+			t.Fatal("invalid transaction is played")
+		}
+	}
+	register := func(name, emailHash, website string, now int64) []byte {
+		return c.newContractTx("IdentityRegistry", map[string]any{"DisplayName": name, "EmailHash": emailHash,
+			"Website": website}, now)
+	}
+	emailHash := strings.ToUpper(hex.EncodeToString(crypto.Hash([]byte("alice@example.com"))))
+
+	c.playBlock(register(" Alice ", emailHash, "https://alice.example.com", c.start+2))
+	got := identity(c.keyID)
+	if got.DisplayName != "Alice" || got.EmailHash != strings.ToLower(emailHash) ||
+		got.Website != "https://alice.example.com" || got.VerifiedByKeyID != 0 || got.BlockID != 2 {
+		t.Errorf("wrong registered identity %+v", got)
+	}
+	rejected(register("", "", "", c.start+3))
+	rejected(register("Alice", "abc", "", c.start+3))
+	rejected(register("Alice", "", "ftp://alice.example.com", c.start+3))
+	// the own identity can't be verified
+	rejected(c.newContractTx("IdentityVerification", map[string]any{"Account": converter.AddressToString(c.keyID)}, c.start+3))
+
+	// the identity of the other key is verified and revoked by the founder
+	const other = int64(777)
+	if err := sqldb.DBConn.Exec(`INSERT INTO "1_identities" (id, key_id, display_name, ecosystem)
+		VALUES (next_id('1_identities'), ?, 'bob', 1)`, other).Error; err != nil {
+		t.Fatal(err)
+	}
+	verify := func(revoke bool, now int64) []byte {
+		return c.newContractTx("IdentityVerification", map[string]any{"Account": converter.AddressToString(other),
+			"Revoke": revoke}, now)
+	}
+	c.playBlock(verify(false, c.start+3))
+	if got = identity(other); got.VerifiedByKeyID != c.keyID {
+		t.Errorf("identity isn't verified by %d: %+v", c.keyID, got)
+	}
+	c.playBlock(verify(true, c.start+4))
+	if got = identity(other); got.VerifiedByKeyID != 0 {
+		t.Errorf("verification isn't revoked: %+v", got)
+	}
+	if got := events(other); len(got) != 2 || got[0] != c.keyID || got[1] != 0 {
+		t.Errorf("wrong identity events of the verification %v", got)
+	}
+
+	// the verified identity is changed by its key and has to be verified again
+	if err := sqldb.DBConn.Exec(`UPDATE "1_identities" SET verified_by_key_id = ? WHERE key_id = ? AND ecosystem = 1`,
+		other, c.keyID).Error; err != nil {
+		t.Fatal(err)
+	}
+	c.playBlock(register("Alice Smith", "", "", c.start+5))
+	if got = identity(c.keyID); got.DisplayName != "Alice Smith" || got.VerifiedByKeyID != 0 {
+		t.Errorf("changed identity keeps the verification %+v", got)
+	}
+	if got := events(c.keyID); len(got) != 2 {
+		t.Errorf("expected two events of the registry, got %v", got)
+	}
+
+	c.rollbackTo(1)
+	if err := sqldb.DBConn.Exec(`DELETE FROM "1_identities" WHERE key_id = ?`, other).Error; err != nil {
+		t.Fatal(err)
+	}
+	compareSnapshots(t, genesis, snapshot(t))
+}
+
+// testArchive is the archive of the blocks of the other node
+type testArchive map[int64][]byte
+
+func (a testArchive) Block(_ context.Context, blockID int64) ([]byte, error) {
+	if data, ok := a[blockID]; ok {
+		return data, nil
+	}
+	return nil, recovery.ErrBlockNotArchived
+}
+
+// TestReplayOldBlocks replays the archived blocks of the day ago on the new node, their time is behind
+// the past bound of the new tip
+func TestReplayOldBlocks(t *testing.T) {
+	db := startPostgres(t)
+	c := newTestChainAt(t, db, time.Now().Add(-24*time.Hour).Unix())
+	for i := 0; i < 3; i++ {
+		c.playBlock(c.newParameterTx(fmt.Sprintf("replay_%d", i), c.start+int64(i)+2))
+	}
+	archive := make(testArchive)
+	for blockID := int64(1); blockID <= 4; blockID++ {
+		bc := &sqldb.BlockChain{}
+		if found, err := bc.Get(blockID); err != nil || !found {
+			t.Fatalf("getting block %d: %v", blockID, err)
+		}
+		data, err := bc.BlockData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive[blockID] = data
+	}
+	expected := snapshot(t)
+	// the old block is rejected when it's broadcast
+	b, err := block.ProcessBlockByBinData(archive[4], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Origin = block.OriginBroadcast
+	if err = b.Check(); !errors.Is(err, block.ErrBlockTimeInPast) {
+		t.Fatalf("expected %v, got %v", block.ErrBlockTimeInPast, err)
+	}
+
+	if err = sqldb.DBConn.Exec(`CREATE DATABASE ibax_replica`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err = sqldb.GormClose(); err != nil {
+		t.Fatal(err)
+	}
+	replicaDB := db
+	replicaDB.Name = "ibax_replica"
+	c.replica(replicaDB)
+	if err = recovery.ReplayFromArchive(context.Background(), archive, 1, 4, nil); err != nil {
+		t.Fatalf("replaying old blocks: %v", err)
+	}
+	compareSnapshots(t, expected, snapshot(t))
+}
+
+// TestPlaySafeDelayedOrigin records the transactions which create the delayed contracts and traces the
+// delayed contracts which are created by the transaction transitively
+func TestPlaySafeDelayedOrigin(t *testing.T) {
+	c := newTestChain(t, startPostgres(t))
+	for i, value := range []string{
+		// the forged origin of the contract is replaced
+		`contract NewDelayedContract {
+			data { Contract string }
+			action {
+				$result = DBInsert("@1delayed_contracts", {"contract": $Contract, "key_id": $key_id,
+					"block_id": $block + 1, "every_block": 1, "limit": 1, "conditions": "true", "origin_tx_hash": "00"})
+			}
+		}`,
+		`contract TestDelayedLeaf { action { $result = "leaf" } }`,
+		`contract TestDelayedChain { action { CallContract("@1NewDelayedContract", {"Contract": "@1TestDelayedLeaf"}) } }`,
+	} {
+		c.playBlock(c.newContractTx("NewContract", map[string]any{"ApplicationId": 1, "Conditions": "true",
+			"Value": value}, c.start+int64(i)+1))
+	}
+
+	create := c.newContractTx("NewDelayedContract", map[string]any{"Contract": "@1TestDelayedChain"}, c.start+4)
+	c.playBlock(create)
+	origin := c.txHash(create)
+	delays, err := sqldb.GetCausedDelays(nil, origin, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 1 || delays[0].Contract != "@1TestDelayedChain" || !bytes.Equal(delays[0].OriginTxHash, origin) ||
+		delays[0].OriginBlockID != 5 || delays[0].Depth != 1 {
+		t.Fatalf("wrong origin of the delayed contract %+v", delays)
+	}
+
+	// the delayed contract creates the other one, it's traced to the first transaction with the depth 2
+	run := c.delayedTx("TestDelayedChain", c.start+5)
+	c.playBlock(run)
+	if delays, err = sqldb.GetCausedDelays(nil, origin, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 2 || delays[1].Contract != "@1TestDelayedLeaf" || !bytes.Equal(delays[1].OriginTxHash, c.txHash(run)) ||
+		delays[1].OriginBlockID != 6 || delays[1].Depth != 2 {
+		t.Fatalf("wrong caused delays %+v", delays)
+	}
+	if delays, err = sqldb.GetCausedDelays(nil, origin, 1); err != nil || len(delays) != 1 {
+		t.Errorf("caused delays aren't limited %+v %v", delays, err)
+	}
+	if delays, err = sqldb.GetCausedDelays(nil, c.txHash(run), 10); err != nil || len(delays) != 1 || delays[0].Depth != 1 {
+		t.Errorf("wrong caused delays of the delayed transaction %+v %v", delays, err)
+	}
+}
+
+// TestReadTransactionSnapshot reads while the block transaction is open, the read sees the state of the last
+// committed block and doesn't wait for the block, with the read pool and with the main pool
+func TestReadTransactionSnapshot(t *testing.T) {
+	db := startPostgres(t)
+	db.ReadConns = 2
+	c := newTestChain(t, db)
+	c.playBlock(c.newParameterTx("snapshot_0", c.start+2))
+	if sqldb.ReadConn == nil {
+		t.Fatal("read pool isn't opened")
+	}
+	defer func(conn *gorm.DB) { sqldb.ReadConn = conn }(sqldb.ReadConn)
+
+	scan := func(readTx *sqldb.DbTransaction) (value string, err error) {
+		err = sqldb.GetDB(readTx).Raw(`SELECT value FROM "1_parameters" WHERE name = ?`, "snapshot_0").Row().Scan(&value)
+		return
+	}
+	read := func(readTx *sqldb.DbTransaction) string {
+		t.Helper()
+		value, err := scan(readTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	for _, pool := range []*gorm.DB{sqldb.ReadConn, nil} {
+		sqldb.ReadConn = pool
+		blockTx, err := sqldb.StartTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, query := range []string{
+			`UPDATE "1_parameters" SET value = 'changed' WHERE name = 'snapshot_0'`,
+			`UPDATE info_block SET block_id = block_id + 1`,
+		} {
+			if err = sqldb.GetDB(blockTx).Exec(query).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		done := make(chan struct{})
+		var (
+			readTx  *sqldb.DbTransaction
+			blockID int64
+			value   string
+		)
+		go func() {
+			defer close(done)
+			if readTx, blockID, err = sqldb.StartReadTransaction(); err == nil {
+				value, err = scan(readTx)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("read waits for the block transaction")
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blockID != 2 || value != "snapshot_0" {
+			t.Errorf("expected the committed block 2, got the block %d with %s", blockID, value)
+		}
+
+		// the read keeps its snapshot after the commit of the block, the next read sees the block
+		if err = blockTx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if value = read(readTx); value != "snapshot_0" {
+			t.Errorf("the snapshot of the read is changed to %s", value)
+		}
+		readTx.Rollback()
+		next, nextID, err := sqldb.StartReadTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value = read(next); nextID != 3 || value != "changed" {
+			t.Errorf("expected the committed block 3, got the block %d with %s", nextID, value)
+		}
+		next.Rollback()
+
+		// the state is restored for the next pool
+		for _, query := range []string{
+			`UPDATE "1_parameters" SET value = 'snapshot_0' WHERE name = 'snapshot_0'`,
+			`UPDATE info_block SET block_id = block_id - 1`,
+		} {
+			if err = sqldb.DBConn.Exec(query).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
+)
+
+// KVChange is the row which is changed by the committed block. OldValue is the json of the changed
+// columns before the block, it's nil for the inserted row. NewValue is the json of the row after
+// the block, it's nil for the removed row
+type KVChange struct {
+	Table    string
+	Key      []byte
+	OldValue []byte
+	NewValue []byte
+}
+
+// KVCommitHook receives the changes of every committed block, e.g. to update the external store of
+// the indexer. The hooks are called one after another after the commit, so they should be fast
+type KVCommitHook interface {
+	OnCommit(blockID int64, changes []KVChange) error
+}
+
+var (
+	kvCommitHooks = make(map[string]KVCommitHook)
+	kvHookMutex   sync.RWMutex
+
+	// readKVRow returns the json of the committed row or nil if the row doesn't exist
+	readKVRow = func(row sqldb.RowRef) ([]byte, error) {
+		return readRow(nil, row, nil)
+	}
+)
+
+// readRow returns the json of the row within the db transaction or nil if the row doesn't exist,
+// nil dbTx reads the committed row. The columns are read by the canonical expressions of their types,
+// columns caches the select lists of the tables if it isn't nil
+func readRow(dbTx *sqldb.DbTransaction, row sqldb.RowRef, columns map[string]string) ([]byte, error) {
+	list, ok := columns[row.Table]
+	if !ok {
+		cols, err := dbTx.GetAllColumnTypes(row.Table)
+		if err != nil {
+			return nil, err
+		}
+		exprs := make([]string, len(cols))
+		for i, col := range cols {
+			exprs[i] = canonicalColumn(col["column_name"], col["data_type"])
+		}
+		list = strings.Join(exprs, ",")
+		if columns != nil {
+			columns[row.Table] = list
+		}
+	}
+	values, err := dbTx.GetOneRow(`SELECT ` + list + ` FROM "` + row.Table + `"` + row.Where()).String()
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	return json.Marshal(values)
+}
+
+// canonicalColumn returns the select expression of the column whose text doesn't depend on the version
+// and the settings of the server: the floats are the hex of their binary form, the dates and the times
+// are the microseconds since the epoch
+func canonicalColumn(name, dataType string) string {
+	col := `"` + name + `"`
+	switch {
+	case dataType == "double precision" || dataType == "real":
+		col = `encode(float8send(` + col + `::float8), 'hex')`
+	case strings.HasPrefix(dataType, "timestamp") || strings.HasPrefix(dataType, "time ") ||
+		dataType == "date" || dataType == "interval":
+		col = `(extract(epoch from ` + col + `) * 1000000)::bigint`
+	case dataType == "money":
+		col = col + `::numeric`
+	default:
+		return col
+	}
+	return col + ` AS "` + name + `"`
+}
+
+// RegisterKVCommitHook registers the hook with the name, nil hook removes it
+func RegisterKVCommitHook(name string, hook KVCommitHook) {
+	kvHookMutex.Lock()
+	defer kvHookMutex.Unlock()
+	if hook == nil {
+		delete(kvCommitHooks, name)
+		return
+	}
+	kvCommitHooks[name] = hook
+}
+
+// kvHooks returns the registered hooks in the order of the names
+func kvHooks() (names []string, hooks []KVCommitHook) {
+	kvHookMutex.RLock()
+	defer kvHookMutex.RUnlock()
+	for name := range kvCommitHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hooks = append(hooks, kvCommitHooks[name])
+	}
+	return
+}
+
+// runKVCommitHooks passes the changes of the committed block to the hooks. The block is already
+// committed, so the errors are only logged
+func (b *Block) runKVCommitHooks() {
+	names, hooks := kvHooks()
+	if len(hooks) == 0 {
+		return
+	}
+	logger := b.GetLogger()
+	changes, err := b.kvChanges()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting kv changes of block")
+		return
+	}
+	for i, hook := range hooks {
+		if err := hook.OnCommit(b.Header.BlockId, changes); err != nil {
+			logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "hook": names[i]}).Error("calling kv commit hook")
+		}
+	}
+}
+
+// kvChanges returns the rows which are changed by the rollback records of the block in the order
+// of the first change. The old value is taken from the first change of the row in the block
+func (b *Block) kvChanges() ([]KVChange, error) {
+	if b.AfterTxs == nil {
+		return nil, nil
+	}
+	var (
+		changes []KVChange
+		rows    []sqldb.RowRef
+		seen    = make(map[[2]string]bool)
+	)
+	for _, rt := range b.AfterTxs.Rts {
+		if rt.NameTable == smart.SysName {
+			continue
+		}
+		row, err := (&sqldb.RollbackTx{NameTable: rt.NameTable, TableID: rt.TableId, Data: rt.Data}).Row()
+		if err != nil {
+			return nil, err
+		}
+		id := [2]string{row.Table, row.Key()}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		change := KVChange{Table: row.Table, Key: []byte(row.Key())}
+		if len(rt.Data) > 0 {
+			change.OldValue = []byte(rt.Data)
+		}
+		changes = append(changes, change)
+		rows = append(rows, row)
+	}
+	for i, row := range rows {
+		value, err := readKVRow(row)
+		if err != nil {
+			return nil, err
+		}
+		changes[i].NewValue = value
+	}
+	return changes, nil
+}
@@ -0,0 +1,107 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+type testKVHook struct {
+	name    string
+	calls   *[]string
+	changes []KVChange
+	err     error
+}
+
+func (h *testKVHook) OnCommit(blockID int64, changes []KVChange) error {
+	*h.calls = append(*h.calls, h.name)
+	h.changes = changes
+	return h.err
+}
+
+func TestKVCommitHooks(t *testing.T) {
+	defer func(f func(sqldb.RowRef) ([]byte, error)) { readKVRow = f }(readKVRow)
+	rows := map[string]string{
+		"1_keys 7,2":  `{"amount":"5","ecosystem":"2","id":"7"}`,
+		"1_orders 3":  `{"id":"3","price":"2"}`,
+		"1_pages 4,1": `{"id":"4","name":"home"}`,
+		"1_removed 9": "",
+	}
+	readKVRow = func(row sqldb.RowRef) ([]byte, error) {
+		if v := rows[row.Table+" "+row.Key()]; v != "" {
+			return []byte(v), nil
+		}
+		return nil, nil
+	}
+
+	b := mustBuild(t, newTestBuilder(10).WithAfterTxs(&types.AfterTxs{Rts: []*types.RollbackTx{
+		{NameTable: "1_keys", TableId: "7", Data: `{"amount":"1","ecosystem":"2"}`},
+		{NameTable: smart.SysName, TableId: "1", Data: `{"type":"NewTable"}`},
+		{NameTable: "1_orders", TableId: "3", Data: ""},
+		{NameTable: "1_keys", TableId: "7", Data: `{"amount":"3","ecosystem":"2"}`},
+		{NameTable: "1_pages", TableId: "4,1", Data: ""},
+		{NameTable: "1_removed", TableId: "9", Data: `{"name":"old"}`},
+	}}))
+	var calls []string
+	first := &testKVHook{name: "b", calls: &calls, err: errors.New("store is down")}
+	second := &testKVHook{name: "a", calls: &calls}
+	RegisterKVCommitHook("b", first)
+	RegisterKVCommitHook("a", second)
+	defer RegisterKVCommitHook("a", nil)
+	defer RegisterKVCommitHook("b", nil)
+
+	b.runKVCommitHooks()
+	if !reflect.DeepEqual(calls, []string{"a", "b"}) {
+		t.Errorf("hooks must be called in the order of names, got %v", calls)
+	}
+	expected := []KVChange{
+		{Table: "1_keys", Key: []byte("7,2"), OldValue: []byte(`{"amount":"1","ecosystem":"2"}`), NewValue: []byte(rows["1_keys 7,2"])},
+		{Table: "1_orders", Key: []byte("3"), NewValue: []byte(rows["1_orders 3"])},
+		{Table: "1_pages", Key: []byte("4,1"), NewValue: []byte(rows["1_pages 4,1"])},
+		{Table: "1_removed", Key: []byte("9"), OldValue: []byte(`{"name":"old"}`)},
+	}
+	if !reflect.DeepEqual(second.changes, expected) || !reflect.DeepEqual(first.changes, expected) {
+		t.Errorf("wrong changes %q", second.changes)
+	}
+
+	RegisterKVCommitHook("b", nil)
+	calls = nil
+	b.runKVCommitHooks()
+	if !reflect.DeepEqual(calls, []string{"a"}) {
+		t.Errorf("removed hook is called: %v", calls)
+	}
+}
+
+func TestCanonicalColumn(t *testing.T) {
+	for _, c := range []struct {
+		dataType string
+		expr     string
+	}{
+		{"bigint", `"amount"`},
+		{"numeric", `"amount"`},
+		{"character varying", `"amount"`},
+		{"jsonb", `"amount"`},
+		{"bytea", `"amount"`},
+		{"double precision", `encode(float8send("amount"::float8), 'hex') AS "amount"`},
+		{"real", `encode(float8send("amount"::float8), 'hex') AS "amount"`},
+		{"timestamp without time zone", `(extract(epoch from "amount") * 1000000)::bigint AS "amount"`},
+		{"timestamp with time zone", `(extract(epoch from "amount") * 1000000)::bigint AS "amount"`},
+		{"time without time zone", `(extract(epoch from "amount") * 1000000)::bigint AS "amount"`},
+		{"date", `(extract(epoch from "amount") * 1000000)::bigint AS "amount"`},
+		{"interval", `(extract(epoch from "amount") * 1000000)::bigint AS "amount"`},
+		{"money", `"amount"::numeric AS "amount"`},
+	} {
+		if expr := canonicalColumn("amount", c.dataType); expr != c.expr {
+			t.Errorf("wrong expression of %s: %s", c.dataType, expr)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// ErrSameMerkleRoot is returned by DiagnoseMerkleRoot if the transactions of the blocks have the same merkle root
+var ErrSameMerkleRoot = errors.New("merkle roots of the transactions are the same")
+
+// MerkleTxInfo describes the transaction at the divergent position of the block
+type MerkleTxInfo struct {
+	Hash     string `json:"hash"`
+	Type     int    `json:"type"`
+	KeyID    int64  `json:"key_id"`
+	Contract string `json:"contract,omitempty"`
+}
+
+// MerkleRootDiagnostic is the first transaction which makes the merkle roots of the blocks differ
+type MerkleRootDiagnostic struct {
+	BlockID    int64         `json:"block_id"`
+	LocalRoot  string        `json:"local_root"`
+	RemoteRoot string        `json:"remote_root"`
+	Index      int           `json:"index"`
+	Local      *MerkleTxInfo `json:"local"`  // nil if the local block has no transaction at Index
+	Remote     *MerkleTxInfo `json:"remote"` // nil if the remote block has no transaction at Index
+	Steps      int           `json:"steps"`  // merkle roots computed by the search
+}
+
+// DiagnoseMerkleRoot binary-searches for the first transaction which differs in the local and the remote
+// blocks. The merkle roots of the prefixes of the transactions are compared, the prefixes are equal
+// up to the divergent transaction and differ from it on
+func DiagnoseMerkleRoot(local *Block, remote *Block) (*MerkleRootDiagnostic, error) {
+	if local == nil || remote == nil || local.BlockData == nil || remote.BlockData == nil {
+		return nil, fmt.Errorf("block is empty")
+	}
+	if local.Header.BlockId != remote.Header.BlockId {
+		return nil, fmt.Errorf("blocks %d and %d can't be compared", local.Header.BlockId, remote.Header.BlockId)
+	}
+	localLeaves, remoteLeaves := merkleLeaves(local.TxFullData), merkleLeaves(remote.TxFullData)
+	n := len(localLeaves)
+	if len(remoteLeaves) > n {
+		n = len(remoteLeaves)
+	}
+	var steps int
+	differ := func(k int) bool {
+		steps += 2
+		return !bytes.Equal(prefixMerkleRoot(localLeaves, k), prefixMerkleRoot(remoteLeaves, k))
+	}
+	if n == 0 || !differ(n) {
+		return nil, ErrSameMerkleRoot
+	}
+	// the smallest prefix with the different roots ends with the divergent transaction
+	lo, hi := 1, n
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if differ(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	index := lo - 1
+	return &MerkleRootDiagnostic{
+		BlockID:    local.Header.BlockId,
+		LocalRoot:  string(local.MerkleRoot),
+		RemoteRoot: string(remote.MerkleRoot),
+		Index:      index,
+		Local:      merkleTxInfo(local, index),
+		Remote:     merkleTxInfo(remote, index),
+		Steps:      steps,
+	}, nil
+}
+
+// merkleLeaves returns the leaves of the merkle tree of the transactions, the merkle root of the block
+// is computed over the compressed transactions
+func merkleLeaves(txs [][]byte) [][]byte {
+	compressed := make([][]byte, len(txs))
+	for i, tx := range txs {
+		compressed[i] = types.DoZlibCompress(append([]byte(nil), tx...))
+	}
+	if len(compressed) == 0 {
+		return nil
+	}
+	return (&types.BlockData{TxFullData: compressed}).LeafHashes()
+}
+
+func prefixMerkleRoot(leaves [][]byte, k int) []byte {
+	if k > len(leaves) {
+		k = len(leaves)
+	}
+	if k == 0 {
+		return nil
+	}
+	return types.MerkleTreeRoot(leaves[:k])
+}
+
+func merkleTxInfo(b *Block, index int) *MerkleTxInfo {
+	if index >= len(b.TxFullData) {
+		return nil
+	}
+	var t *transaction.Transaction
+	if index < len(b.Transactions) {
+		t = b.Transactions[index]
+	} else {
+		var err error
+		if t, err = transaction.DecodeTransaction(b.TxFullData[index]); err != nil {
+			info := &MerkleTxInfo{Type: -1}
+			if len(b.TxFullData[index]) > 0 {
+				info.Type = int(b.TxFullData[index][0])
+			}
+			return info
+		}
+	}
+	info := &MerkleTxInfo{
+		Hash:  hex.EncodeToString(t.Hash()),
+		Type:  int(t.Type()),
+		KeyID: t.KeyID(),
+	}
+	_, info.Contract = txContract(t)
+	if info.Contract == "" && t.IsSmartContract() && t.Type() == types.SmartContractTxType {
+		info.Contract = fmt.Sprintf("#%d", t.SmartContract().TxSmart.ID)
+	}
+	return info
+}
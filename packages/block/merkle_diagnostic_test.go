@@ -0,0 +1,54 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+)
+
+func TestDiagnoseMerkleRoot(t *testing.T) {
+	txs := make([]*transaction.Transaction, 6)
+	for i := range txs {
+		txs[i] = newCustomTx(t, byte(20+i))
+	}
+	newBlock := func(txs ...*transaction.Transaction) *Block {
+		return mustBuild(t, newTestBuilder(10).AddTransaction(txs...))
+	}
+	local := newBlock(txs[:5]...)
+
+	if _, err := DiagnoseMerkleRoot(local, newBlock(txs[:5]...)); !errors.Is(err, ErrSameMerkleRoot) {
+		t.Fatalf("expected the same roots, got %v", err)
+	}
+
+	other := append(append(append([]*transaction.Transaction{}, txs[:3]...), txs[5]), txs[4])
+	d, err := DiagnoseMerkleRoot(local, newBlock(other...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Index != 3 || d.BlockID != 10 || d.LocalRoot != string(local.MerkleRoot) || d.LocalRoot == d.RemoteRoot {
+		t.Errorf("wrong diagnostic %+v", d)
+	}
+	if d.Local.Hash != hex.EncodeToString(txs[3].Hash()) || d.Remote.Hash != hex.EncodeToString(txs[5].Hash()) ||
+		d.Remote.Type != 25 || d.Remote.KeyID != 1 {
+		t.Errorf("wrong divergent txs %+v %+v", d.Local, d.Remote)
+	}
+
+	d, err = DiagnoseMerkleRoot(local, newBlock(txs[:4]...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Index != 4 || d.Local == nil || d.Remote != nil {
+		t.Errorf("missing tx isn't found %+v", d)
+	}
+
+	if _, err = DiagnoseMerkleRoot(local, mustBuild(t, newTestBuilder(11))); err == nil {
+		t.Error("blocks of the different ids must not be compared")
+	}
+}
@@ -0,0 +1,144 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// The names of the ordering policies of the node
+const (
+	OrderFIFO = "fifo" // by the time of the transactions
+	OrderFee  = "fee"  // by the expedite fee, then by the time
+	OrderFair = "fair" // one transaction of every key in turn, the transactions of the key go by the time
+)
+
+// OrderingPolicy orders the queued transactions which are collected by the generated block. The transactions
+// which don't fit the limits of the block stay in the queue
+type OrderingPolicy interface {
+	Order(txs []*sqldb.Transaction) []*sqldb.Transaction
+}
+
+// OrderingFunc is the function of OrderingPolicy
+type OrderingFunc func(txs []*sqldb.Transaction) []*sqldb.Transaction
+
+// Order implements OrderingPolicy
+func (f OrderingFunc) Order(txs []*sqldb.Transaction) []*sqldb.Transaction {
+	return f(txs)
+}
+
+var (
+	orderingPolicies = map[string]OrderingPolicy{
+		OrderFIFO: OrderingFunc(orderFIFO),
+		OrderFee:  OrderingFunc(orderFee),
+		OrderFair: OrderingFunc(orderFair),
+	}
+	orderingMutex sync.RWMutex
+)
+
+// RegisterOrderingPolicy registers the policy with the name, nil policy removes it
+func RegisterOrderingPolicy(name string, policy OrderingPolicy) {
+	orderingMutex.Lock()
+	defer orderingMutex.Unlock()
+	if policy == nil {
+		delete(orderingPolicies, name)
+		return
+	}
+	orderingPolicies[name] = policy
+}
+
+// GetOrderingPolicy returns the registered policy by the name
+func GetOrderingPolicy(name string) (OrderingPolicy, error) {
+	orderingMutex.RLock()
+	defer orderingMutex.RUnlock()
+	policy, ok := orderingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction ordering policy %q", name)
+	}
+	return policy, nil
+}
+
+// BlockBuilder collects the transactions of the generated block in the order of its policy. The transactions
+// of the higher rate, e.g. the stop of the network, keep their place after the other ones whatever the policy is
+type BlockBuilder struct {
+	policy OrderingPolicy
+}
+
+// NewBlockBuilder returns the builder with the policy, nil policy is OrderFee
+func NewBlockBuilder(policy OrderingPolicy) *BlockBuilder {
+	if policy == nil {
+		policy = OrderingFunc(orderFee)
+	}
+	return &BlockBuilder{policy: policy}
+}
+
+// Order returns the queued transactions in the order of the block, txs isn't changed
+func (bb *BlockBuilder) Order(txs []*sqldb.Transaction) []*sqldb.Transaction {
+	rates := make(map[int8][]*sqldb.Transaction)
+	var keys []int8
+	for _, tx := range txs {
+		rate := int8(tx.HighRate)
+		if _, ok := rates[rate]; !ok {
+			keys = append(keys, rate)
+		}
+		rates[rate] = append(rates[rate], tx)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	ordered := make([]*sqldb.Transaction, 0, len(txs))
+	for _, rate := range keys {
+		ordered = append(ordered, bb.policy.Order(rates[rate])...)
+	}
+	return ordered
+}
+
+func orderFIFO(txs []*sqldb.Transaction) []*sqldb.Transaction {
+	ordered := append([]*sqldb.Transaction(nil), txs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Time != ordered[j].Time {
+			return ordered[i].Time < ordered[j].Time
+		}
+		return bytes.Compare(ordered[i].Hash, ordered[j].Hash) < 0
+	})
+	return ordered
+}
+
+func orderFee(txs []*sqldb.Transaction) []*sqldb.Transaction {
+	ordered := orderFIFO(txs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Expedite.GreaterThan(ordered[j].Expedite)
+	})
+	return ordered
+}
+
+// orderFair takes the transactions of the keys in turn, the keys go in the order of their best fees
+func orderFair(txs []*sqldb.Transaction) []*sqldb.Transaction {
+	var keys []int64
+	seen := make(map[int64]bool)
+	for _, tx := range orderFee(txs) {
+		if !seen[tx.KeyID] {
+			seen[tx.KeyID] = true
+			keys = append(keys, tx.KeyID)
+		}
+	}
+	byKey := make(map[int64][]*sqldb.Transaction)
+	for _, tx := range orderFIFO(txs) {
+		byKey[tx.KeyID] = append(byKey[tx.KeyID], tx)
+	}
+	ordered := make([]*sqldb.Transaction, 0, len(txs))
+	for round := 0; len(ordered) < len(txs); round++ {
+		for _, key := range keys {
+			if round < len(byKey[key]) {
+				ordered = append(ordered, byKey[key][round])
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,47 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/shopspring/decimal"
+)
+
+func TestBlockBuilderOrder(t *testing.T) {
+	tx := func(hash string, key, time int64, fee int64) *sqldb.Transaction {
+		return &sqldb.Transaction{Hash: []byte(hash), KeyID: key, Time: time, Expedite: decimal.NewFromInt(fee)}
+	}
+	stop := tx("s", 1, 1, 0)
+	stop.HighRate = sqldb.TransactionRateStopNetwork
+	txs := []*sqldb.Transaction{stop, tx("a", 1, 5, 10), tx("b", 1, 3, 30), tx("c", 2, 4, 20), tx("d", 1, 2, 0), tx("e", 3, 1, 0)}
+	hashes := func(list []*sqldb.Transaction) string {
+		var s []string
+		for _, tx := range list {
+			s = append(s, string(tx.Hash))
+		}
+		return strings.Join(s, "")
+	}
+	for name, want := range map[string]string{
+		OrderFIFO: "edbcas",
+		OrderFee:  "bcaeds",
+		// the keys go by their best fees 1, 2, 3 and the transactions of the key go by the time
+		OrderFair: "dcebas",
+	} {
+		policy, err := GetOrderingPolicy(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hashes(NewBlockBuilder(policy).Order(txs)); got != want {
+			t.Errorf("%s: expected %s got %s", name, want, got)
+		}
+	}
+	if _, err := GetOrderingPolicy("random"); err == nil {
+		t.Error("unknown policy is found")
+	}
+}
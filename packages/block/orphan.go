@@ -0,0 +1,172 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// maxOrphans is the default capacity of the orphan pool
+const maxOrphans = 256
+
+var (
+	ErrOrphanHeader = errors.New("orphan block doesn't have the header of the previous block")
+	ErrOrphanFork   = errors.New("branch of the orphan pool doesn't meet the chain")
+	ErrOrphanDepth  = errors.New("fork of the branch is deeper than the rollback limit")
+)
+
+// Orphan is the block whose parent isn't the block of the chain
+type Orphan struct {
+	ID       int64
+	Hash     []byte
+	PrevHash []byte
+	Data     []byte
+}
+
+// NewOrphan returns the orphan of the binary block, the parent is the previous header of the block
+func NewOrphan(data []byte) (*Orphan, error) {
+	bd := &types.BlockData{}
+	if err := bd.UnmarshallBlock(data); err != nil {
+		return nil, err
+	}
+	if bd.Header == nil || bd.PrevHeader == nil || len(bd.PrevHeader.BlockHash) == 0 {
+		return nil, ErrOrphanHeader
+	}
+	return &Orphan{ID: bd.Header.BlockId, Hash: bd.Header.BlockHash, PrevHash: bd.PrevHeader.BlockHash, Data: data}, nil
+}
+
+// OrphanPool keeps the blocks whose parents are unknown until the branch of them meets the chain
+type OrphanPool struct {
+	mu     sync.Mutex
+	max    int
+	blocks map[string]*Orphan
+}
+
+// Orphans is the orphan pool of the node
+var Orphans = NewOrphanPool(maxOrphans)
+
+// NewOrphanPool returns the pool of max blocks at most
+func NewOrphanPool(max int) *OrphanPool {
+	return &OrphanPool{max: max, blocks: make(map[string]*Orphan)}
+}
+
+// Add adds the orphan, the block with the largest id is dropped if the pool is full, so the blocks
+// which are close to the chain are kept
+func (p *OrphanPool) Add(o *Orphan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.blocks[string(o.Hash)]; ok {
+		return
+	}
+	p.blocks[string(o.Hash)] = o
+	for len(p.blocks) > p.max {
+		var last *Orphan
+		for _, b := range p.blocks {
+			if last == nil || b.ID > last.ID || b.ID == last.ID && bytes.Compare(b.Hash, last.Hash) > 0 {
+				last = b
+			}
+		}
+		delete(p.blocks, string(last.Hash))
+	}
+}
+
+// Remove removes the blocks of the branch
+func (p *OrphanPool) Remove(branch []*Orphan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, o := range branch {
+		delete(p.blocks, string(o.Hash))
+	}
+}
+
+// Prune removes the blocks which are not above minID, their forks are too deep
+func (p *OrphanPool) Prune(minID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for hash, o := range p.blocks {
+		if o.ID <= minID {
+			delete(p.blocks, hash)
+		}
+	}
+}
+
+// Len returns the number of the orphans
+func (p *OrphanPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.blocks)
+}
+
+// Branch returns the blocks of the pool from the first one whose parent isn't in the pool to the block
+// tip, the ids of the blocks go one after another
+func (p *OrphanPool) Branch(tip []byte) []*Orphan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var branch []*Orphan
+	for o, ok := p.blocks[string(tip)]; ok; o, ok = p.blocks[string(o.PrevHash)] {
+		if len(branch) > 0 && branch[len(branch)-1].ID != o.ID+1 {
+			break
+		}
+		branch = append(branch, o)
+	}
+	sort.Slice(branch, func(i, j int) bool { return branch[i].ID < branch[j].ID })
+	return branch
+}
+
+// Tip returns the highest block of the pool which follows the block hash, its branch is the longest
+func (p *OrphanPool) Tip(hash []byte) *Orphan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	children := make(map[string][]*Orphan)
+	for _, o := range p.blocks {
+		children[string(o.PrevHash)] = append(children[string(o.PrevHash)], o)
+	}
+	var tip *Orphan
+	var walk func(parent []byte, id int64)
+	walk = func(parent []byte, id int64) {
+		for _, o := range children[string(parent)] {
+			if id > 0 && o.ID != id+1 {
+				continue
+			}
+			if tip == nil || o.ID > tip.ID || o.ID == tip.ID && bytes.Compare(o.Hash, tip.Hash) < 0 {
+				tip = o
+			}
+			walk(o.Hash, o.ID)
+		}
+	}
+	walk(hash, 0)
+	return tip
+}
+
+// ReorgPlan returns the id of the last common block of the chain and the branch, which is the parent of
+// the first block of the branch. hashAt returns the hash of the block of the chain, lastID is the last
+// block of the chain and the fork is maxDepth blocks deep at most. The branch is applied if it's longer
+// than the chain, ok is false if the chain is heavier
+func ReorgPlan(branch []*Orphan, lastID, maxDepth int64, hashAt func(id int64) ([]byte, error)) (forkID int64, ok bool, err error) {
+	if len(branch) == 0 {
+		return 0, false, nil
+	}
+	forkID = branch[0].ID - 1
+	if forkID < 1 || forkID > lastID {
+		return 0, false, ErrOrphanFork
+	}
+	hash, err := hashAt(forkID)
+	if err != nil {
+		return 0, false, err
+	}
+	if !bytes.Equal(hash, branch[0].PrevHash) {
+		return 0, false, ErrOrphanFork
+	}
+	if maxDepth > 0 && lastID-forkID > maxDepth {
+		return 0, false, ErrOrphanDepth
+	}
+	return forkID, branch[len(branch)-1].ID > lastID, nil
+}
@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrphanPool(t *testing.T) {
+	p := NewOrphanPool(4)
+	// the branch a <- b <- c forks after the block "chain5", d is the other child of b
+	a := &Orphan{ID: 6, Hash: []byte("a"), PrevHash: []byte("chain5")}
+	b := &Orphan{ID: 7, Hash: []byte("b"), PrevHash: []byte("a")}
+	c := &Orphan{ID: 8, Hash: []byte("c"), PrevHash: []byte("b")}
+	d := &Orphan{ID: 8, Hash: []byte("d"), PrevHash: []byte("b")}
+	for _, o := range []*Orphan{c, a, d, b} {
+		p.Add(o)
+	}
+	branch := p.Branch(c.Hash)
+	if len(branch) != 3 || branch[0] != a || branch[2] != c {
+		t.Fatalf("wrong branch %v", branch)
+	}
+	if tip := p.Tip(a.Hash); tip != c {
+		t.Errorf("expected tip c, got %v", tip)
+	}
+	// the full pool drops the highest block
+	p.Add(&Orphan{ID: 9, Hash: []byte("e"), PrevHash: []byte("c")})
+	if p.Len() != 4 || len(p.Branch([]byte("e"))) != 0 {
+		t.Errorf("highest block is kept, %d orphans", p.Len())
+	}
+	p.Prune(6)
+	if len(p.Branch(c.Hash)) != 2 {
+		t.Errorf("pruned block is kept")
+	}
+}
+
+func TestReorgPlan(t *testing.T) {
+	chain := map[int64][]byte{4: []byte("chain4"), 5: []byte("chain5"), 6: []byte("chain6"), 7: []byte("chain7")}
+	hashAt := func(id int64) ([]byte, error) { return chain[id], nil }
+	branch := []*Orphan{
+		{ID: 6, Hash: []byte("a"), PrevHash: []byte("chain5")},
+		{ID: 7, Hash: []byte("b"), PrevHash: []byte("a")},
+		{ID: 8, Hash: []byte("c"), PrevHash: []byte("b")},
+	}
+	forkID, ok, err := ReorgPlan(branch, 7, 10, hashAt)
+	if err != nil || !ok || forkID != 5 {
+		t.Errorf("expected reorg after 5, got %d %v %v", forkID, ok, err)
+	}
+	// the branch of the same length doesn't replace the chain
+	if _, ok, err = ReorgPlan(branch[:2], 7, 10, hashAt); err != nil || ok {
+		t.Errorf("shorter branch is applied %v %v", ok, err)
+	}
+	if _, _, err = ReorgPlan(branch, 7, 1, hashAt); !errors.Is(err, ErrOrphanDepth) {
+		t.Errorf("expected %v got %v", ErrOrphanDepth, err)
+	}
+	if _, _, err = ReorgPlan(branch[1:], 7, 10, hashAt); !errors.Is(err, ErrOrphanFork) {
+		t.Errorf("expected %v got %v", ErrOrphanFork, err)
+	}
+}
@@ -0,0 +1,113 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	testEco    = 1
+	testWallet = 100
+)
+
+func initialOutputs() map[sqldb.KeyUTXO][]sqldb.SpentInfo {
+	outputs := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+	sqldb.PutAllOutputsMap([]sqldb.SpentInfo{
+		{OutputTxHash: []byte("genesis"), OutputKeyId: testWallet, OutputValue: "100", Ecosystem: testEco, BlockId: 1},
+	}, outputs)
+	return outputs
+}
+
+// payUTXO collects the changes of utxo transfer like UtxoToken does. The failed contract
+// returns OutCtx without the inputs and the outputs
+func payUTXO(outputsMap map[sqldb.KeyUTXO][]sqldb.SpentInfo, toID int64, value int64, fail bool) *transaction.OutCtx {
+	txInputs := sqldb.GetUnusedOutputsMap(sqldb.KeyUTXO{Ecosystem: testEco, KeyId: testWallet}, outputsMap)
+	total := decimal.Zero
+	for _, input := range txInputs {
+		total = total.Add(decimal.RequireFromString(input.OutputValue))
+	}
+	pay := decimal.NewFromInt(value)
+	txInputsMap := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+	txOutputsMap := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+	sqldb.PutAllOutputsMap(txInputs, txInputsMap)
+	sqldb.PutAllOutputsMap([]sqldb.SpentInfo{
+		{OutputIndex: 0, OutputKeyId: toID, OutputValue: pay.String(), Ecosystem: testEco, BlockId: 2},
+		{OutputIndex: 1, OutputKeyId: testWallet, OutputValue: total.Sub(pay).String(), Ecosystem: testEco, BlockId: 2},
+	}, txOutputsMap)
+	if fail || total.LessThan(pay) {
+		return &transaction.OutCtx{}
+	}
+	return &transaction.OutCtx{TxInputsMap: txInputsMap, TxOutputsMap: txOutputsMap}
+}
+
+func unusedBalance(outputsMap map[sqldb.KeyUTXO][]sqldb.SpentInfo, keyID int64) int64 {
+	total := decimal.Zero
+	for _, output := range sqldb.GetUnusedOutputsMap(sqldb.KeyUTXO{Ecosystem: testEco, KeyId: keyID}, outputsMap) {
+		total = total.Add(decimal.RequireFromString(output.OutputValue))
+	}
+	return total.IntPart()
+}
+
+// playPayments plays tx1, the failing tx2 and tx3 from the same wallet
+func playPayments(t *testing.T) *Block {
+	b := mustBuild(t, newTestBuilder(2).WithOutputs(initialOutputs()))
+	b.applyTxOutputs([]byte("tx1"), payUTXO(b.OutputsMap, 101, 30, false))
+	// tx2 is rolled back after its inputs and outputs have been collected
+	_ = payUTXO(b.OutputsMap, 102, 50, true)
+	if balance := unusedBalance(b.OutputsMap, testWallet); balance != 70 {
+		t.Fatalf("balance after the failed tx: expected 70 got %d", balance)
+	}
+	b.applyTxOutputs([]byte("tx3"), payUTXO(b.OutputsMap, 103, 20, false))
+	return b
+}
+
+func TestOutputsAfterFailedTx(t *testing.T) {
+	b := playPayments(t)
+	for keyID, expected := range map[int64]int64{testWallet: 50, 101: 30, 102: 0, 103: 20} {
+		if balance := unusedBalance(b.OutputsMap, keyID); balance != expected {
+			t.Errorf("balance of %d: expected %d got %d", keyID, expected, balance)
+		}
+	}
+	// the second node plays the same block
+	if other := playPayments(t); !reflect.DeepEqual(b.OutputsMap, other.OutputsMap) {
+		t.Errorf("outputs differ on the second node")
+	}
+}
+
+func TestInputIndexOrder(t *testing.T) {
+	play := func() map[sqldb.KeyUTXO][]sqldb.SpentInfo {
+		outputs := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		for eco := int64(1); eco <= 8; eco++ {
+			sqldb.PutAllOutputsMap([]sqldb.SpentInfo{
+				{OutputKeyId: testWallet, OutputValue: "1", Ecosystem: eco},
+				{OutputKeyId: testWallet, OutputValue: "2", Ecosystem: eco},
+			}, outputs)
+		}
+		b := mustBuild(t, newTestBuilder(2).WithOutputs(outputs))
+		b.applyTxOutputs([]byte("tx"), &transaction.OutCtx{TxInputsMap: copyOutputs(outputs)})
+		return b.OutputsMap
+	}
+	expected := play()
+	for i := 0; i < 20; i++ {
+		if !reflect.DeepEqual(play(), expected) {
+			t.Fatalf("input indexes depend on the map order")
+		}
+	}
+}
+
+func copyOutputs(outputs map[sqldb.KeyUTXO][]sqldb.SpentInfo) map[sqldb.KeyUTXO][]sqldb.SpentInfo {
+	inputs := make(map[sqldb.KeyUTXO][]sqldb.SpentInfo, len(outputs))
+	for k, v := range outputs {
+		inputs[k] = append([]sqldb.SpentInfo(nil), v...)
+	}
+	return inputs
+}
@@ -6,79 +6,371 @@
 package block
 
 import (
-	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
+	"runtime/debug"
 	"strconv"
 	"sync"
+	"time"
 
+	logtools "github.com/IBAX-io/go-ibax/packages/common/log"
 	"github.com/IBAX-io/go-ibax/packages/common/random"
+	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/notificator"
 	"github.com/IBAX-io/go-ibax/packages/pbgo"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/statsd"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // PlaySafe is inserting block safely
-func (b *Block) PlaySafe() error {
+func (b *Block) PlaySafe() (err error) {
+	ctx, span := tracer.Start(context.Background(), "block.PlaySafe")
+	defer func() {
+		spanError(span, err)
+		span.End()
+	}()
+	if err = b.retrieveTxs(); err != nil {
+		return err
+	}
+	if span.IsRecording() {
+		span.SetAttributes(attribute.Int64("block.id", b.Header.BlockId),
+			attribute.Int("block.txs", len(b.Transactions)), attribute.Bool("block.gen", b.GenBlock))
+	}
+	return b.play(func(dbTx *sqldb.DbTransaction) error {
+		return b.processTxs(ctx, dbTx)
+	})
+}
+
+// play executes the transactions with process and inserts the block within one db transaction
+func (b *Block) play(process func(dbTx *sqldb.DbTransaction) error) (err error) {
+	b.beforeBlock()
+	defer func() { b.afterBlock(err) }()
 	logger := b.GetLogger()
 	dbTx, err := sqldb.StartTransaction()
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting db transaction")
-		return err
+		return dbError("starting db transaction", err)
 	}
+	dbTx.SetLogger(logger)
 
-	err = b.ProcessTxs(dbTx)
+	start := time.Now()
+	err = process(dbTx)
 	if err != nil {
 		dbTx.Rollback()
-		return err
+		b.writeStopAudit()
+		return wrapError("processing transactions", err, KindInvalidBlock)
 	}
 
-	if b.GenBlock && len(b.TxFullData) == 0 {
+	if b.GenBlock && len(b.TxFullData) == 0 && !b.isHeartbeat() {
 		dbTx.Commit()
 		return ErrEmptyBlock
 	}
 
 	if err := b.InsertIntoBlockchain(dbTx); err != nil {
 		dbTx.Rollback()
-		return err
+		return wrapError("inserting block", err, KindInvalidBlock)
 	}
 	err = dbTx.Commit()
 	if err != nil {
-		return err
+		return dbError("committing db transaction", err)
 	}
+	elapsed := time.Since(start)
 	for _, q := range b.Notifications {
 		q.Send()
 	}
+	b.writeBlockResources(elapsed)
+	b.writeAuditLogs()
+	b.liftBans()
+	b.writeFeeStats()
+	b.writeContractStats()
+	b.writeResourceUsage()
+	b.writeTrace()
+	b.runKVCommitHooks()
+	b.publishCommitted()
+	logger.WithFields(log.Fields{"txs": len(b.TxFullData)}).Debug("block played")
 	return nil
 }
 
+// writeFeeStats saves the gas prices of the committed block for the fee estimation
+func (b *Block) writeFeeStats() {
+	if len(b.FeeStats) == 0 {
+		return
+	}
+	if err := sqldb.SaveBlockFeeStats(nil, b.Header.BlockId, b.FeeStats); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing block fee stats")
+	}
+	b.FeeStats = nil
+}
+
+// writeBlockResources saves the resources of the committed block, elapsed is the time of the
+// execution and the insertion of the block
+func (b *Block) writeBlockResources(elapsed time.Duration) {
+	if conf.Config.BlockResourcesDays <= 0 {
+		return
+	}
+	txCounts := make(map[int]int64, len(b.ClassifyTxsMap))
+	for txType, txs := range b.ClassifyTxsMap {
+		if len(txs) > 0 {
+			txCounts[txType] = int64(len(txs))
+		}
+	}
+	var notifications int64
+	for _, q := range b.Notifications {
+		notifications += int64(q.Size())
+	}
+	r := sqldb.NewBlockResources(b.Header.BlockId, time.Unix(b.Header.Timestamp, 0), txCounts,
+		int64(len(b.BinData)), b.GasUsed, elapsed.Nanoseconds(), b.Savepoints, notifications)
+	if err := sqldb.SaveBlockResources(nil, r); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing block resources")
+	}
+}
+
+// writeContractStats adds the invocations of the committed block to the local contract stats
+// of the day of the block
+func (b *Block) writeContractStats() {
+	days := conf.Config.ContractStatsDays
+	if len(b.ContractStats) == 0 || days <= 0 {
+		return
+	}
+	t := time.Unix(b.Header.Timestamp, 0)
+	err := sqldb.SaveContractStats(nil, sqldb.StatsDay(t), sqldb.StatsDay(t.AddDate(0, 0, 1-days)), b.ContractStats)
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing contract stats")
+	}
+	b.ContractStats = nil
+}
+
+// writeResourceUsage saves the resources consumed by the contracts of the committed block
+func (b *Block) writeResourceUsage() {
+	days := conf.Config.ResourceUsageDays
+	if len(b.ResourceUsage) == 0 || days <= 0 {
+		return
+	}
+	if err := sqldb.SaveResourceUsage(nil, b.ResourceUsage, time.Now().AddDate(0, 0, -days)); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing resource usage")
+	}
+	b.ResourceUsage = nil
+}
+
+// addResourceUsage records the resources consumed by the successfully played contract, cpu is
+// the time of the play and written is the size of the sql statements of the contract
+func (b *Block) addResourceUsage(t *transaction.Transaction, cpu time.Duration, written int64) {
+	if !t.IsSmartContract() || t.IsDryRun() || conf.Config.ResourceUsageDays <= 0 {
+		return
+	}
+	eco, contract := txContract(t)
+	sc := t.SmartContract()
+	b.ResourceUsage = append(b.ResourceUsage, &sqldb.ResourceUsage{
+		BlockID:      b.Header.BlockId,
+		TxHash:       t.Hash(),
+		ContractName: contract,
+		EcosystemID:  eco,
+		CPUNs:        cpu.Nanoseconds(),
+		DBWriteBytes: written,
+		MemPeakBytes: sc.TxMemPeak,
+		GasUsed:      sc.TxFuel,
+		RecordedAt:   time.Now(),
+	})
+}
+
+// addContractStats counts the invocation of the played contract
+func (b *Block) addContractStats(t *transaction.Transaction, eco int64, contract string, code pbgo.TxInvokeStatusCode) {
+	if len(contract) == 0 || t.IsDryRun() || conf.Config.ContractStatsDays <= 0 {
+		return
+	}
+	key := sqldb.ContractStatsKey{Ecosystem: eco, Contract: contract}
+	stats, ok := b.ContractStats[key]
+	if !ok {
+		stats = &sqldb.ContractStats{}
+		b.ContractStats[key] = stats
+	}
+	stats.Add(code == pbgo.TxInvokeStatusCode_SUCCESS, t.SmartContract().TxFuel, len(t.RollBackTx))
+}
+
+// addReceipt records the execution receipt of the played transaction, the receipts are inserted with
+// log_transactions of the block
+func (b *Block) addReceipt(t *transaction.Transaction, eco int64, contract string, code pbgo.TxInvokeStatusCode) {
+	r := &sqldb.TxReceipt{
+		TxHash:       t.Hash(),
+		BlockID:      b.Header.BlockId,
+		EcosystemID:  eco,
+		ContractName: contract,
+		RowsTouched:  int64(len(t.RollBackTx)),
+		Status:       int64(code),
+	}
+	if t.IsSmartContract() {
+		r.Fuel = t.SmartContract().TxFuel
+		r.Events = int64(len(t.SmartContract().Events))
+	}
+	b.Receipts = append(b.Receipts, r)
+}
+
+// createAudit appends the entry to the audit log within the db transaction
+var createAudit = (*sqldb.AuditLog).Create
+
+// auditTx collects the privileged operations of the played transaction, they are written to the audit
+// log after the commit of the block. The changed parameters are reloaded after the commit too
+func (b *Block) auditTx(t *transaction.Transaction) {
+	if t.SysUpdate {
+		t.SysUpdate = false
+		b.SysUpdate = true
+		b.AuditLogs = append(b.AuditLogs, sqldb.NewAuditLog(sqldb.AuditSysUpdate, t.KeyID(), b.Header.BlockId, t.Hash(), t.Payload()))
+	}
+	switch t.Type() {
+	case types.KeyRotationTxType:
+		b.AuditLogs = append(b.AuditLogs, sqldb.NewAuditLog(sqldb.AuditKeyRotation, t.KeyID(), b.Header.BlockId, t.Hash(), t.Payload()))
+	case types.BanLiftTxType:
+		b.AuditLogs = append(b.AuditLogs, sqldb.NewAuditLog(sqldb.AuditKeyBanLift, t.KeyID(), b.Header.BlockId, t.Hash(), t.Payload()))
+		b.liftedBans = append(b.liftedBans, t.BanLift().BannedID)
+	}
+}
+
+// stopNetwork pauses the node by the stop network transaction. The block is rolled back after it,
+// so the audit entry is kept until writeStopAudit writes it out of the block transaction
+func (b *Block) stopNetwork(t *transaction.Transaction) {
+	node.PauseNodeActivity(node.PauseTypeStopingNetwork)
+	b.stopAudit = sqldb.NewAuditLog(sqldb.AuditStopNetwork, t.KeyID(), b.Header.BlockId, t.Hash(), t.Payload())
+}
+
+// writeStopAudit appends the stop network entry to the audit log after the rollback of the block
+func (b *Block) writeStopAudit() {
+	if b.stopAudit == nil {
+		return
+	}
+	if err := createAudit(b.stopAudit, nil); err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err, "action": b.stopAudit.Action}).Error("writing audit log")
+	}
+	b.stopAudit = nil
+}
+
+// writeAuditLogs appends the privileged operations of the committed block to the audit log
+func (b *Block) writeAuditLogs() {
+	for _, a := range b.AuditLogs {
+		if err := createAudit(a, nil); err != nil {
+			b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err, "action": a.Action}).Error("writing audit log")
+		}
+	}
+	b.AuditLogs = nil
+}
+
+// liftBans lifts the bans of the ban lift transactions of the committed block
+func (b *Block) liftBans() {
+	for _, keyID := range b.liftedBans {
+		transaction.LiftBan(keyID)
+	}
+	b.liftedBans = nil
+}
+
 type badTxStruct struct {
 	index int
 	hash  []byte
 	msg   string
 	keyID int64
+	// spent is set when the outputs of the transaction were spent by the other transaction of the key,
+	// the key isn't banned
+	spent bool
+}
+
+// ProcessTxs executes the classified transactions of the block
+func (b *Block) ProcessTxs(dbTx *sqldb.DbTransaction) error {
+	return b.processTxs(context.Background(), dbTx)
+}
+
+func (b *Block) processTxs(ctx context.Context, dbTx *sqldb.DbTransaction) error {
+	txs := b.orderedTxs(syspar.IsTxTypeOrderAt(b.Header.BlockId))
+	txTypes := make(map[*transaction.Transaction]int, len(txs))
+	for txType, list := range b.ClassifyTxsMap {
+		for _, t := range list {
+			txTypes[t] = txType
+		}
+	}
+	if workers := signWorkers(); workers > 0 {
+		b.preVerifySigns(dbTx, txs, workers)
+		defer utils.ResetSignCache()
+	}
+	txStream := make(chan *transaction.Transaction, len(txs))
+	for _, t := range txs {
+		txStream <- t
+	}
+	close(txStream)
+	return b.processStream(ctx, dbTx, txStream, b.Transactions, func(t *transaction.Transaction) int {
+		return txTypes[t]
+	})
+}
+
+// orderedTxs returns the transactions in the order of the execution. The stop network
+// transactions are executed alone, the key rotations and the ban lifts go first and the custom
+// types go after the delayed contracts. The contract transactions go before the utxo ones if
+// typeOrder is true, otherwise they keep the order of the block like the generators wrote them
+// before the activation of tx_type_order
+func (b *Block) orderedTxs(typeOrder bool) []*transaction.Transaction {
+	txsMap := b.ClassifyTxsMap
+	if len(txsMap[types.StopNetworkTxType]) > 0 {
+		return txsMap[types.StopNetworkTxType]
+	}
+	var txs []*transaction.Transaction
+	if b.IsGenesis() {
+		txs = append(txs, b.Transactions...)
+	}
+	order := append([]int{types.KeyRotationTxType, types.BanLiftTxType, types.DelayTxType}, customTxTypes(txsMap)...)
+	for _, txType := range append(order, types.TransferSelfTxType) {
+		txs = append(txs, txsMap[txType]...)
+	}
+	if typeOrder {
+		txs = append(txs, txsMap[types.SmartContractTxType]...)
+		return append(txs, txsMap[types.UtxoTxType]...)
+	}
+	contracts := make(map[*transaction.Transaction]bool)
+	for _, txType := range []int{types.SmartContractTxType, types.UtxoTxType} {
+		for _, t := range txsMap[txType] {
+			contracts[t] = true
+		}
+	}
+	for _, t := range b.Transactions {
+		if contracts[t] {
+			txs = append(txs, t)
+		}
+	}
+	return txs
 }
 
-func (b *Block) ProcessTxs(dbTx *sqldb.DbTransaction) (err error) {
+// processStream executes the transactions in the order they are received from txStream.
+// The outputs and the ecosystem parameters of preload are loaded at once, the others are
+// loaded as the transactions arrive. classify returns the type of the transaction in ClassifyTxsMap
+func (b *Block) processStream(ctx context.Context, dbTx *sqldb.DbTransaction, txStream <-chan *transaction.Transaction,
+	preload []*transaction.Transaction, classify func(t *transaction.Transaction) int) (err error) {
 	afters := &types.AfterTxs{
 		Rts: make([]*types.RollbackTx, 0),
 		Txs: make([]*types.AfterTx, 0),
 	}
-	txsMap := b.ClassifyTxsMap
 	processedTx := make([][]byte, 0, len(b.Transactions))
 
+	b.rejected = nil
+	badTxDone := make(chan struct{})
 	processBadTx := func() chan badTxStruct {
 		ch := make(chan badTxStruct)
 		go func() {
+			defer close(badTxDone)
 			for badTxItem := range ch {
-				transaction.BadTxForBan(badTxItem.keyID)
+				if b.replay {
+					// the replayed block doesn't change the state of the node
+					b.rejected = append(b.rejected, badTxItem)
+					continue
+				}
+				if !badTxItem.spent {
+					transaction.BadTxForBan(badTxItem.keyID, transaction.BanReasonBlock)
+				}
 				_ = transaction.MarkTransactionBad(badTxItem.hash, badTxItem.msg)
 			}
 		}()
@@ -88,6 +380,9 @@ func (b *Block) ProcessTxs(dbTx *sqldb.DbTransaction) (err error) {
 	txBadChan := processBadTx()
 	defer func() {
 		close(txBadChan)
+		if b.replay {
+			<-badTxDone
+		}
 		if b.IsGenesis() || b.GenBlock || b.AfterTxs != nil {
 			b.AfterTxs = afters
 		}
@@ -113,231 +408,355 @@ func (b *Block) ProcessTxs(dbTx *sqldb.DbTransaction) (err error) {
 	//	return nil
 	//}
 
-	var keyIds []int64
-	var keyIdsMap = make(map[int64]bool)
-	var ecosystemIds []int64
-	var ecosystemIdsMap = make(map[int64]bool)
-	for indexTx := 0; indexTx < len(b.Transactions); indexTx++ {
-		t := b.Transactions[indexTx]
-		if !keyIdsMap[t.KeyID()] {
-			keyIdsMap[t.KeyID()] = true
-			keyIds = append(keyIds, t.KeyID())
-		}
-		if t.IsSmartContract() && !ecosystemIdsMap[t.SmartContract().TxSmart.EcosystemID] {
-			ecosystemIdsMap[t.SmartContract().TxSmart.EcosystemID] = true
-			ecosystemIds = append(ecosystemIds, t.SmartContract().TxSmart.EcosystemID)
-		}
-	}
-	// query all keys utxo
-	outputs, err := sqldb.GetTxOutputs(dbTx, keyIds)
-	if err != nil {
-		return err
-	}
 	b.OutputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
-	sqldb.PutAllOutputsMap(outputs, b.OutputsMap)
-	// query all ecosystems combination percent
-	ecoParams, err := sqldb.GetEcoParam(dbTx, ecosystemIds)
-	if err != nil {
-		return err
-	}
-	b.EcoParams = ecoParams
+	b.EcoParams = nil
+	b.FeeStats = make(map[int][]int64)
+	b.ContractStats = make(map[sqldb.ContractStatsKey]*sqldb.ContractStats)
+	b.ResourceUsage = nil
+	b.Receipts = nil
+	b.BinLogSql = nil
+	b.GasUsed = 0
+	b.Savepoints = 0
+	b.batch = nil
+	b.execTrace = newBlockTrace(b.replay)
 	// UTXO multiple ecosystem fuelRate
 	b.PrevSysPar = syspar.GetSysParCache()
-	var wg sync.WaitGroup
 
-	// StopNetworkTxType
-	if len(txsMap[types.StopNetworkTxType]) > 0 {
-		transactions := txsMap[types.StopNetworkTxType]
-		err := b.serialExecuteTxs(dbTx, txBadChan, afters, &processedTx, transactions, lock)
-		delete(txsMap, types.StopNetworkTxType)
-		if err != nil {
-			return err
-		}
-		return nil
+	in := newIngest(ctx, b, dbTx, txBadChan, afters, &processedTx)
+	in.blockOrder = !syspar.IsTxTypeOrderAt(b.Header.BlockId)
+	defer in.endGroup()
+	if err = in.load(preload); err != nil {
+		return err
 	}
-
-	// FirstBlockTxType
-	if b.IsGenesis() {
-		transactions := make([]*transaction.Transaction, 0)
-		for _, tx := range b.Transactions {
-			t, err := transaction.UnmarshallTransaction(bytes.NewBuffer(tx.FullData), false)
-			if err != nil {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case t, ok := <-txStream:
+			if !ok {
+				return in.finish()
+			}
+			if err = in.push(t, classify(t)); err != nil {
 				return err
 			}
-			transactions = append(transactions, t)
-		}
-		err := b.serialExecuteTxs(dbTx, txBadChan, afters, &processedTx, transactions, lock)
-		transactions = make([]*transaction.Transaction, 0)
-		if err != nil {
-			return err
 		}
 	}
+}
 
-	// DelayTxType
-	if len(txsMap[types.DelayTxType]) > 0 {
-		transactions := txsMap[types.DelayTxType]
-		err := b.serialExecuteTxs(dbTx, txBadChan, afters, &processedTx, transactions, lock)
-		delete(txsMap, types.DelayTxType)
-		if err != nil {
-			return err
-		}
-	}
+// txGroup is the state of the transactions which are executed one after another
+type txGroup struct {
+	limits  *transaction.Limits
+	rand    *random.Rand
+	index   int
+	stopped bool // the block limits are reached, the next transactions of the group are skipped
+}
 
-	// TransferSelf
-	if len(txsMap[types.TransferSelfTxType]) > 0 {
-		transactions := txsMap[types.TransferSelfTxType]
+func (b *Block) newTxGroup() *txGroup {
+	return &txGroup{
+		limits: transaction.NewLimits(b.limitMode(), b.Header.BlockId),
+		rand:   blockRand(b.Header),
+	}
+}
 
-		walletAddress := make(map[int64]int64)
-		groupTransferSelfTxs(transactions, walletAddress)
-		for _, transactions := range transferSelfTxsGroupMap {
-			wg.Add(1)
-			go func(_dbTx *sqldb.DbTransaction, _txBadChan chan badTxStruct, _transactions []*transaction.Transaction, _afters *types.AfterTxs, _processedTx *[][]byte, _lock *sync.RWMutex) {
-				defer wg.Done()
-				err := b.serialExecuteTxs(_dbTx, _txBadChan, _afters, _processedTx, _transactions, _lock)
-				if err != nil {
-					return
-				}
-			}(dbTx, txBadChan, transactions, afters, &processedTx, lock)
+// serialExecuteTxs executes the transactions of the parallel group, limits is the own copy of the group
+func (b *Block) serialExecuteTxs(ctx context.Context, dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte, txs []*transaction.Transaction, limits *transaction.Limits, _lock *sync.RWMutex) error {
+	_lock.Lock()
+	defer _lock.Unlock()
+	g := b.newTxGroup()
+	g.limits = limits
+	for _, t := range txs {
+		if err := b.executeTx(ctx, dbTx, txBadChan, afters, processedTx, g, t); err != nil {
+			return err
 		}
-		wg.Wait()
-		transferSelfTxsGroupMap = make(map[string][]*transaction.Transaction, 0)
-		transferSelfGroupTxsList = make([]*transaction.Transaction, 0)
-		transferSelfGroupSerial = 1
-		delete(txsMap, types.TransferSelfTxType)
-	}
-
-	//Utxo && Smart contract
-	if len(txsMap[types.UtxoTxType]) > 0 || len(txsMap[types.SmartContractTxType]) > 0 {
-		transactions := txsMap[types.UtxoTxType]
-		// utxo group
-		walletAddress := make(map[int64]int64)
-		groupUtxoTxs(transactions, walletAddress)
-		if len(txsMap[types.SmartContractTxType]) > 0 {
-			utxoTxsGroupMap[strconv.Itoa(0)] = txsMap[types.SmartContractTxType]
-		}
-		for _, transactions := range utxoTxsGroupMap {
-			wg.Add(1)
-			go func(_dbTx *sqldb.DbTransaction, _txBadChan chan badTxStruct, _transactions []*transaction.Transaction, _afters *types.AfterTxs, _processedTx *[][]byte, _lock *sync.RWMutex) {
-				defer wg.Done()
-				err := b.serialExecuteTxs(_dbTx, _txBadChan, _afters, _processedTx, _transactions, _lock)
-				if err != nil {
-					return
-				}
-			}(dbTx, txBadChan, transactions, afters, &processedTx, lock)
+		if g.stopped {
+			break
 		}
-		wg.Wait()
-		utxoTxsGroupMap = make(map[string][]*transaction.Transaction, 0)
-		utxoGroupTxsList = make([]*transaction.Transaction, 0)
-		utxoGroupSerial = 1
-		delete(txsMap, types.UtxoTxType)
-		delete(txsMap, types.SmartContractTxType)
 	}
-
 	return nil
 }
 
-func (b *Block) serialExecuteTxs(dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte, txs []*transaction.Transaction, _lock *sync.RWMutex) error {
-	_lock.Lock()
-	defer _lock.Unlock()
-	limits := transaction.NewLimits(b.limitMode())
-	rand := random.NewRand(b.Header.Timestamp)
-	logger := b.GetLogger()
-	for curTx := 0; curTx < len(txs); curTx++ {
-		t := txs[curTx]
-		err := dbTx.Savepoint(consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash())))
-		if err != nil {
-			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "tx_hash": t.Hash()}).Error("using savepoint")
-			return err
+// playTx plays the next transaction of the group. The transaction has its own savepoint if own is true,
+// otherwise it's played after the savepoint of its batch and returns errBatchFailed if it fails. The panic
+// of the transaction is recovered as the error of the bad transaction
+func (b *Block) playTx(ctx context.Context, dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte, g *txGroup, t *transaction.Transaction, own bool) (err error) {
+	curTx := g.index
+	g.index++
+	// failure is the error of the transaction which is skipped or rejects the block
+	var failure error
+	b.beforeTx(t)
+	defer func() {
+		if err == errBatchFailed {
+			// the transaction is played again with the batch
+			return
+		}
+		e := &TxExecution{Tx: t, Err: failure}
+		if e.Err == nil {
+			e.Err = err
+		}
+		if e.Err == nil {
+			e.Rollbacks = t.RollBackTx
+		}
+		b.afterTx(e)
+	}()
+	if r, ok := b.preExecutedFailure(t); ok {
+		failure = errors.New(r.failure)
+		// the transaction has failed on the state of the last block while the node waited for its slot
+		txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: r.failure, keyID: t.KeyID(), spent: r.spent}
+		if !conf.Config.StrictBlock.Enabled {
+			return nil
 		}
-		err = t.WithOption(notificator.NewQueue(), b.GenBlock, b.Header, b.PrevHeader, dbTx, rand.BytesSeed(t.Hash()), limits,
-			consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash())), b.OutputsMap, b.PrevSysPar, b.EcoParams)
+		return b.badTxError(t, failure)
+	}
+	savepoint := false
+	defer func() {
+		if r := recover(); r != nil {
+			failure = fmt.Errorf("panic: %v", r)
+			if !own {
+				err = errBatchFailed
+				return
+			}
+			err = b.txPanic(dbTx, txBadChan, curTx, t, savepoint, r)
+		}
+	}()
+	_, span := tracer.Start(ctx, "block.tx")
+	defer span.End()
+	setTxAttributes(span, t)
+	txCtx, cancel := b.txContext(ctx)
+	defer cancel()
+	logger := b.txLogger(t)
+	// the contract without the savepoint fails instead of rolling back to it
+	var point string
+	if own {
+		point = consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash()))
+		err = timeSavepoint(statsd.DBSavepoint, func() error {
+			return dbTx.Savepoint(point)
+		})
 		if err != nil {
-			return err
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("using savepoint")
+			return dbError("using savepoint", err)
 		}
+		savepoint = true
+		b.Savepoints++
+		b.execTrace.add(t.Hash(), TraceSavepoint, "")
+	}
+	err = t.WithOption(notificator.NewQueueWithLogger(logger), b.GenBlock, b.Header, b.PrevHeader, dbTx, g.rand.BytesSeed(t.Hash()), g.limits,
+		point, b.OutputsMap, b.PrevSysPar, b.EcoParams, transaction.WithLogger(logger),
+		transaction.WithTraceCalls(b.execTrace != nil), transaction.WithContext(txCtx))
+	if err != nil {
+		return err
+	}
+	// the savepoints and the statements after the play aren't canceled
+	dbTx.SetContext(txCtx)
+	defer dbTx.SetContext(nil)
+	// the slow statements of the play are attributed to the transaction
+	_, txName := txContract(t)
+	dbTx.SetStatementSource(t.Hash(), txName)
+	started, written := time.Now(), dbTx.WrittenBytes()
+	if t.IsCustom() {
+		err = executeCustomTx(t, dbTx)
+	} else {
 		err = t.Play()
-		if err != nil {
-			if err == transaction.ErrNetworkStopping {
-				// Set the node in a pause state
-				node.PauseNodeActivity(node.PauseTypeStopingNetwork)
+	}
+	dbTx.SetContext(nil)
+	if err == nil {
+		err = b.checkBlockFuel(t)
+	}
+	if err != nil {
+		failure = err
+		spanError(span, err)
+		if !own {
+			return errBatchFailed
+		}
+		if err == transaction.ErrNetworkStopping {
+			// Set the node in a pause state
+			if b.replay {
 				return err
 			}
-			errRoll := t.DbTransaction.RollbackSavepoint(consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash())))
-			if errRoll != nil {
-				return fmt.Errorf("%v; %w", err, errRoll)
-			}
-			if b.GenBlock {
-				if errors.Cause(err) == transaction.ErrLimitStop {
-					if curTx == 0 {
-						txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: err.Error(), keyID: t.KeyID()}
-						return err
-					}
-					break
-				}
+			b.stopNetwork(t)
+			return err
+		}
+		errRoll := timeSavepoint(statsd.DBSavepointRollback, func() error {
+			return t.DbTransaction.RollbackSavepoint(point)
+		})
+		if errRoll != nil {
+			return dbError("rolling back savepoint", fmt.Errorf("%v; %w", err, errRoll))
+		}
+		b.execTrace.addCalls(t)
+		b.execTrace.add(t.Hash(), TraceRollback, "")
+		if errCanceled := canceledTx(ctx, txCtx, err); errCanceled != nil {
+			if IsRetryable(errCanceled) {
+				return errCanceled
 			}
-			txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: err.Error(), keyID: t.KeyID()}
-			if t.SysUpdate {
-				if err := syspar.SysUpdate(t.DbTransaction); err != nil {
-					return fmt.Errorf("updating syspar: %w", err)
+			err, failure = errCanceled, errCanceled
+		}
+		if IsRetryable(err) {
+			// the transaction isn't bad, the block is played again
+			return dbError("playing transaction", err)
+		}
+		if b.GenBlock {
+			if errors.Cause(err) == transaction.ErrLimitStop {
+				if curTx == 0 && b.GasUsed == 0 {
+					txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: err.Error(), keyID: t.KeyID()}
+					return b.badTxError(t, err)
 				}
-				t.SysUpdate = false
-			}
-			if b.GenBlock {
-				continue
+				g.stopped = true
+				return nil
 			}
-			return err
 		}
-
+		msg := err.Error()
+		if ce, ok := err.(*TxCanceledError); ok {
+			msg = ce.status()
+		}
+		txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: msg, keyID: t.KeyID(),
+			spent: errors.Is(err, smart.ErrOutputSpent)}
 		if t.SysUpdate {
-			b.SysUpdate = true
+			if err := syspar.SysUpdate(t.DbTransaction); err != nil {
+				return fmt.Errorf("updating syspar: %w", err)
+			}
 			t.SysUpdate = false
 		}
-
-		if t.Notifications.Size() > 0 {
-			b.Notifications = append(b.Notifications, t.Notifications)
+		if b.GenBlock && !conf.Config.StrictBlock.Enabled {
+			return nil
 		}
+		return b.badTxError(t, err)
+	}
 
-		var (
-			after    = &types.AfterTx{}
-			eco      = int64(1)
-			contract string
-			code     pbgo.TxInvokeStatusCode
-		)
-		if t.IsSmartContract() {
-			eco = t.SmartContract().TxSmart.EcosystemID
-			code = t.TxResult.Code
-			if t.SmartContract().TxContract != nil {
-				contract = t.SmartContract().TxContract.Name
-			}
+	b.addResourceUsage(t, time.Since(started), dbTx.WrittenBytes()-written)
+	if t.IsSmartContract() {
+		b.GasUsed += t.SmartContract().TxFuel
+	}
+
+	if t.SysUpdate && t.IsDryRun() {
+		t.SysUpdate = false
+		// the changed parameters are discarded by the dry run, so the cache is reloaded
+		if err := syspar.SysUpdate(t.DbTransaction); err != nil {
+			return fmt.Errorf("updating syspar: %w", err)
 		}
-		after.UsedTx = t.Hash()
-		after.Lts = &types.LogTransaction{
-			Block: t.BlockHeader.BlockId,
-			Hash:  t.Hash(),
-			//TxData:       t.FullData,
-			Timestamp:    t.Timestamp(),
-			Address:      t.KeyID(),
-			EcosystemId:  eco,
-			ContractName: contract,
-			InvokeStatus: code,
+	}
+	b.auditTx(t)
+
+	if t.IsSmartContract() {
+		if err = sqldb.SaveContractEvents(dbTx, t.SmartContract().Events); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing contract events")
+			return dbError("writing contract events", err)
 		}
-		after.UpdTxStatus = t.TxResult
-		afters.Txs = append(afters.Txs, after)
-		afters.Rts = append(afters.Rts, t.RollBackTx...)
-		//afters.TxBinLogSql = append(afters.TxBinLogSql, t.DbTransaction.BinLogSql...)
-		*processedTx = append(*processedTx, t.FullData)
+		b.AuditLogs = append(b.AuditLogs, t.SmartContract().AuditLogs...)
+		txType := int(t.Type())
+		b.FeeStats[txType] = append(b.FeeStats[txType], t.Expedite().Shift(consts.MoneyDigits).IntPart())
+	}
 
-		sqldb.UpdateTxInputs(t.Hash(), t.TxInputsMap, b.OutputsMap)
-		sqldb.InsertTxOutputs(t.Hash(), t.TxOutputsMap, b.OutputsMap)
+	if t.Notifications.Size() > 0 && !t.IsDryRun() {
+		b.Notifications = append(b.Notifications, t.Notifications)
 	}
 
+	var (
+		after    = &types.AfterTx{}
+		eco      = int64(1)
+		contract string
+		code     pbgo.TxInvokeStatusCode
+	)
+	if t.IsSmartContract() {
+		eco = t.SmartContract().TxSmart.EcosystemID
+		code = t.TxResult.Code
+		if t.SmartContract().TxContract != nil {
+			contract = t.SmartContract().TxContract.Name
+		}
+	}
+	b.addContractStats(t, eco, contract, code)
+	b.addReceipt(t, eco, contract, code)
+	after.UsedTx = t.Hash()
+	after.Lts = &types.LogTransaction{
+		Block: t.BlockHeader.BlockId,
+		Hash:  t.Hash(),
+		//TxData:       t.FullData,
+		Timestamp:    t.Timestamp(),
+		Address:      t.KeyID(),
+		EcosystemId:  eco,
+		ContractName: contract,
+		InvokeStatus: code,
+	}
+	after.UpdTxStatus = t.TxResult
+	afters.Txs = append(afters.Txs, after)
+	afters.Rts = append(afters.Rts, t.RollBackTx...)
+	b.BinLogSql = append(b.BinLogSql, t.DbTransaction.BinLogSql...)
+	*processedTx = append(*processedTx, t.FullData)
+
+	if !own {
+		b.batch.state.saveOutputs(b.OutputsMap, t.OutCtx)
+	}
+	b.applyTxOutputs(t.Hash(), t.OutCtx)
+	b.execTrace.addPlayed(t)
+	logger.Debug("transaction played")
 	return nil
 }
 
+// checkBlockFuel returns the error if the played transaction doesn't fit max_fuel_block of the whole block,
+// the limiters of the groups check their own copies of the limits only
+func (b *Block) checkBlockFuel(t *transaction.Transaction) error {
+	if !t.IsSmartContract() || b.IsGenesis() {
+		return nil
+	}
+	return blockFuelError(b.GasUsed, t.SmartContract().TxFuel, syspar.GetMaxBlockFuel(), b.GenBlock)
+}
+
+// blockFuelError returns ErrBlockFuel if the fuel of the transaction and the used fuel of the block are over
+// the limit. The generated block stops on the transaction unless it doesn't fit the empty block
+func blockFuelError(used, fuel, limit int64, genBlock bool) error {
+	if limit <= 0 || used+fuel <= limit {
+		return nil
+	}
+	err := fmt.Errorf("%w: %d > %d", ErrBlockFuel, used+fuel, limit)
+	if genBlock && used > 0 {
+		return errors.WithMessage(transaction.ErrLimitStop, err.Error())
+	}
+	return err
+}
+
+// txPanic rolls back the transaction which panicked and marks it bad. The generated block skips
+// the transaction, otherwise the block is rejected with PanicError
+func (b *Block) txPanic(dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, curTx int, t *transaction.Transaction, savepoint bool, r any) error {
+	err := &PanicError{Hash: t.Hash(), Value: r}
+	b.GetLogger().WithFields(log.Fields{logtools.FieldTxHash: hex.EncodeToString(t.Hash()), "type": consts.PanicRecoveredError, "error": r, "stack": string(debug.Stack())}).Error("transaction panicked")
+	if savepoint {
+		if errRoll := dbTx.RollbackSavepoint(consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash()))); errRoll != nil {
+			return dbError("rolling back savepoint", fmt.Errorf("%v; %w", err, errRoll))
+		}
+		b.execTrace.add(t.Hash(), TraceRollback, "")
+	}
+	txBadChan <- badTxStruct{index: curTx, hash: t.Hash(), msg: err.Error(), keyID: t.KeyID()}
+	if b.GenBlock && !conf.Config.StrictBlock.Enabled {
+		return nil
+	}
+	return b.badTxError(t, err)
+}
+
+// badTxError returns the error of the bad transaction. The generated block is aborted with
+// BadTxError in the strict mode, so the block is assembled again without the transaction
+func (b *Block) badTxError(t *transaction.Transaction, err error) error {
+	if b.GenBlock && conf.Config.StrictBlock.Enabled {
+		return &BadTxError{Hash: t.Hash(), Err: err}
+	}
+	return err
+}
+
+// applyTxOutputs registers the spent inputs and the new outputs of the played transaction in
+// OutputsMap. The contracts only read OutputsMap and collect the changes of the transaction in
+// TxInputsMap and TxOutputsMap of out, which are filled when the transaction succeeds. So it
+// must be called after the successful Play only, then the failed transaction leaves OutputsMap
+// untouched and its savepoint rollback doesn't need to undo anything in memory
+func (b *Block) applyTxOutputs(hash []byte, out *transaction.OutCtx) {
+	sqldb.UpdateTxInputs(hash, out.TxInputsMap, b.OutputsMap)
+	sqldb.InsertTxOutputs(hash, out.TxOutputsMap, b.OutputsMap)
+}
+
 var (
 	utxoTxsGroupMap         = make(map[string][]*transaction.Transaction)
 	utxoGroupTxsList        = make([]*transaction.Transaction, 0)
 	utxoGroupSerial  uint16 = 1
 	lock                    = &sync.RWMutex{}
+	// groupsLock guards the groups of the utxo and transfer self transactions from they are grouped
+	// until they are reset after the play
+	groupsLock = &sync.Mutex{}
 )
 
 func groupUtxoTxs(txs []*transaction.Transaction, walletAddress map[int64]int64) map[string][]*transaction.Transaction {
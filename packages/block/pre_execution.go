@@ -0,0 +1,182 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/common/random"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/notificator"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// preResult is the result of the pre-executed transaction, the empty failure is the success
+type preResult struct {
+	failure string
+	spent   bool // the transaction has failed on the spent outputs, its key isn't banned
+}
+
+// preExecution is the results of the transactions which are played on the state of the last block.
+// Until the chain has the state trie, the state is the hash of the last block
+type preExecution struct {
+	mu      sync.Mutex
+	state   []byte
+	results map[string]preResult
+}
+
+var preExecuted = &preExecution{results: make(map[string]preResult)}
+
+// switchState drops the results if they are played on the other state
+func (p *preExecution) switchState(state []byte) {
+	if !bytes.Equal(p.state, state) {
+		p.state = state
+		p.results = make(map[string]preResult)
+	}
+}
+
+func (p *preExecution) put(state, hash []byte, r preResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.switchState(state)
+	p.results[string(hash)] = r
+}
+
+func (p *preExecution) get(state, hash []byte) (preResult, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !bytes.Equal(p.state, state) {
+		return preResult{}, false
+	}
+	r, ok := p.results[string(hash)]
+	return r, ok
+}
+
+// PreExecute plays the contract transactions of the queue one by one on the state of the last block
+// prev while the node waits for its slot, header is the expected header of the next block. Every
+// transaction is rolled back and the transactions which have been played on this state are skipped.
+// The generated block drops the failed transactions without playing them. It returns the number
+// of the played transactions
+func PreExecute(ctx context.Context, header, prev *types.BlockHeader, txs []*transaction.Transaction) (int, error) {
+	var candidates []*transaction.Transaction
+	for _, t := range txs {
+		if _, ok := preExecuted.get(prev.BlockHash, t.Hash()); ok || !preExecutable(t) {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	dbTx, err := sqldb.StartTransaction()
+	if err != nil {
+		return 0, dbError("starting db transaction", err)
+	}
+	defer dbTx.Rollback()
+
+	b := &Block{BlockData: &types.BlockData{Header: header, PrevHeader: prev}, GenBlock: true,
+		OutputsMap: make(map[sqldb.KeyUTXO][]sqldb.SpentInfo), PrevSysPar: syspar.GetSysParCache()}
+	in := newIngest(ctx, b, dbTx, nil, nil, nil)
+	if err = in.load(candidates); err != nil {
+		return 0, err
+	}
+	rand := blockRand(header)
+	var played int
+	for _, t := range candidates {
+		select {
+		case <-ctx.Done():
+			return played, nil
+		default:
+		}
+		r, ok, err := b.preExecuteTx(dbTx, rand, t)
+		if err != nil {
+			return played, err
+		}
+		played++
+		if ok {
+			preExecuted.put(prev.BlockHash, t.Hash(), r)
+		}
+	}
+	return played, nil
+}
+
+// preExecutable returns true for the calls of the contracts, the transfers are cheap and the other
+// types depend on the block
+func preExecutable(t *transaction.Transaction) bool {
+	if t.Type() != types.SmartContractTxType || !t.IsSmartContract() {
+		return false
+	}
+	tx := t.SmartContract().TxSmart
+	return tx.TransferSelf == nil && tx.UTXO == nil && tx.ConfidentialUTXO == nil
+}
+
+// preExecuteTx plays the transaction and rolls it back. The result isn't kept if it depends on the
+// block: the limits of the block, the errors of the database and the panic
+func (b *Block) preExecuteTx(dbTx *sqldb.DbTransaction, rand *random.Rand, t *transaction.Transaction) (r preResult, ok bool, err error) {
+	mark := consts.SetSavePointMarkBlock(hex.EncodeToString(t.Hash()))
+	if err = dbTx.Savepoint(mark); err != nil {
+		return r, false, dbError("using savepoint", err)
+	}
+	logger := b.txLogger(t)
+	defer func() {
+		if rec := recover(); rec != nil {
+			flushVM(t)
+			logger.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": rec}).Debug("pre-executing transaction")
+			ok = false
+		}
+		if errRoll := dbTx.RollbackSavepoint(mark); errRoll != nil && err == nil {
+			err = dbError("rolling back savepoint", errRoll)
+		}
+		if t.SysUpdate {
+			t.SysUpdate = false
+			if errSys := syspar.SysUpdate(dbTx); errSys != nil && err == nil {
+				err = fmt.Errorf("updating syspar: %w", errSys)
+			}
+		}
+	}()
+	err = t.WithOption(notificator.NewQueueWithLogger(logger), true, b.Header, b.PrevHeader, dbTx, rand.BytesSeed(t.Hash()),
+		transaction.NewLimits(b.limitMode(), b.Header.BlockId), mark, b.OutputsMap, b.PrevSysPar, b.EcoParams, transaction.WithLogger(logger))
+	if err != nil {
+		return r, false, nil
+	}
+	errPlay := t.Play()
+	if errPlay == nil {
+		flushVM(t)
+		return r, true, nil
+	}
+	if errors.Cause(errPlay) == transaction.ErrLimitStop || IsRetryable(errPlay) {
+		return r, false, nil
+	}
+	return preResult{failure: errPlay.Error(), spent: errors.Is(errPlay, smart.ErrOutputSpent)}, true, nil
+}
+
+// flushVM restores the contracts which are changed by the transaction in the vm
+func flushVM(t *transaction.Transaction) {
+	if sc, ok := t.Inner.(*transaction.SmartTransactionParser); ok {
+		sc.FlushVM()
+	}
+}
+
+// preExecutedFailure returns the failure of the transaction which has been pre-executed on the state
+// of the previous block of the generated block
+func (b *Block) preExecutedFailure(t *transaction.Transaction) (preResult, bool) {
+	if !b.GenBlock || !conf.Config.PreExecution || b.PrevHeader == nil {
+		return preResult{}, false
+	}
+	r, ok := preExecuted.get(b.PrevHeader.BlockHash, t.Hash())
+	return r, ok && len(r.failure) > 0
+}
@@ -0,0 +1,29 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import "testing"
+
+func TestPreExecutionState(t *testing.T) {
+	p := &preExecution{results: make(map[string]preResult)}
+	first, second := []byte("first"), []byte("second")
+	p.put(first, []byte("a"), preResult{failure: "failed"})
+	p.put(first, []byte("b"), preResult{})
+	if r, ok := p.get(first, []byte("a")); !ok || r.failure != "failed" {
+		t.Fatalf("unexpected result %v %v", r, ok)
+	}
+	if _, ok := p.get(second, []byte("a")); ok {
+		t.Fatal("result of the other state is found")
+	}
+	// the results of the previous state are dropped
+	p.put(second, []byte("c"), preResult{})
+	if _, ok := p.get(first, []byte("b")); ok {
+		t.Fatal("result of the previous state is kept")
+	}
+	if _, ok := p.get(second, []byte("c")); !ok {
+		t.Fatal("result of the state isn't found")
+	}
+}
@@ -0,0 +1,85 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// PruneBatchBlocks is the number of the blocks which are pruned within one db transaction
+const PruneBatchBlocks = 100
+
+// PruneRange returns the blocks from..to which are pruned next, the last retention blocks are kept whole.
+// The first block is never pruned, it keeps the data of the network
+func PruneRange(maxID, prunedID, retention int64) (from, to int64, ok bool) {
+	from, to = prunedID+1, maxID-retention
+	if from < 2 {
+		from = 2
+	}
+	if to > from+PruneBatchBlocks-1 {
+		to = from + PruneBatchBlocks - 1
+	}
+	return from, to, to >= from
+}
+
+// prunedData returns the binary of the block without the transactions which is kept by the pruned block
+func prunedData(bc *sqldb.BlockChain) ([]byte, error) {
+	data := bc.Data
+	// the block which keeps the commitment has no transactions already
+	if len(bc.TxData) == 0 {
+		var err error
+		if data, err = types.StripTxs(bc.Data); err != nil {
+			return nil, err
+		}
+	}
+	if syspar.IsBlockCompression() {
+		data = types.CompressBlock(data)
+	}
+	return data, nil
+}
+
+// PruneBlocks deletes the transactions, the binlog and the rollback records of the blocks from..to, so
+// the blocks can't be rolled back or sent to the other nodes then
+func PruneBlocks(from, to int64) error {
+	logger := log.WithFields(log.Fields{"from_block": from, "to_block": to})
+	blocks, err := (&sqldb.BlockChain{}).GetBlocksFrom(from-1, "asc", int(to-from+1))
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting blocks")
+		return err
+	}
+	dbTx, err := sqldb.StartTransaction()
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return err
+	}
+	for i := range blocks {
+		bc := &blocks[i]
+		if bc.Pruned || bc.ID > to {
+			continue
+		}
+		data, err := prunedData(bc)
+		if err != nil {
+			dbTx.Rollback()
+			logger.WithFields(log.Fields{"type": consts.ParserError, "error": err, "block_id": bc.ID}).Error("stripping block transactions")
+			return err
+		}
+		if err = bc.Prune(dbTx, data); err != nil {
+			dbTx.Rollback()
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": bc.ID}).Error("pruning block")
+			return err
+		}
+	}
+	if err = sqldb.DeleteRollbackTxsTo(dbTx, to); err != nil {
+		dbTx.Rollback()
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting rollback records")
+		return err
+	}
+	return dbTx.Commit()
+}
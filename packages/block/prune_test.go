@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestPruneRange(t *testing.T) {
+	for _, c := range []struct {
+		maxID, pruned, retention int64
+		from, to                 int64
+		ok                       bool
+	}{
+		{maxID: 50, retention: 100},
+		{maxID: 150, retention: 100, from: 2, to: 50, ok: true},
+		{maxID: 1000, pruned: 50, retention: 100, from: 51, to: 50 + PruneBatchBlocks, ok: true},
+		{maxID: 1000, pruned: 900, retention: 100, from: 901, to: 900},
+	} {
+		from, to, ok := PruneRange(c.maxID, c.pruned, c.retention)
+		if ok != c.ok || (ok && (from != c.from || to != c.to)) {
+			t.Errorf("%+v: got %d..%d %v", c, from, to, ok)
+		}
+	}
+}
+
+func TestPrunedData(t *testing.T) {
+	data, err := proto.Marshal(&types.BlockData{Header: &types.BlockHeader{BlockId: 7, BlockHash: []byte("hash")},
+		TxFullData: [][]byte{types.DoZlibCompress([]byte("tx"))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := &sqldb.BlockChain{ID: 7, Data: data}
+	stripped, err := prunedData(bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &types.BlockData{}
+	if err = b.UnmarshallBlock(stripped); err != nil {
+		t.Fatal(err)
+	}
+	if b.Header.BlockId != 7 || len(b.TxFullData) != 0 {
+		t.Errorf("wrong pruned block %v", b)
+	}
+
+	bc.Data, bc.Pruned = stripped, true
+	if _, err = bc.BlockData(); !errors.Is(err, sqldb.ErrPruned) {
+		t.Errorf("expected pruned block, got %v", err)
+	}
+}
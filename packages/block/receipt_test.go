@@ -0,0 +1,54 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/pbgo"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestAddReceipt(t *testing.T) {
+	newTx := func(hash string, fuel int64, events, rows int) *transaction.Transaction {
+		return &transaction.Transaction{
+			OutCtx: &transaction.OutCtx{RollBackTx: make([]*types.RollbackTx, rows)},
+			Inner: &transaction.SmartTransactionParser{SmartContract: &smart.SmartContract{
+				Hash:   []byte(hash),
+				TxFuel: fuel,
+				Events: make([]*sqldb.ContractEvent, events),
+			}},
+		}
+	}
+	b := &Block{BlockData: &types.BlockData{Header: &types.BlockHeader{BlockId: 9}}}
+	b.addReceipt(newTx("success", 120, 2, 3), 2, "@2Transfer", pbgo.TxInvokeStatusCode_SUCCESS)
+	// the contract which has failed with the penalty is in the block with the fee rows only
+	b.addReceipt(newTx("penalty", 40, 0, 1), 1, "@1Fail", pbgo.TxInvokeStatusCode_PENALTY)
+	b.addReceipt(stopNetworkTx(nil), 1, "", pbgo.TxInvokeStatusCode_SUCCESS)
+
+	expected := []sqldb.TxReceipt{
+		{TxHash: []byte("success"), BlockID: 9, EcosystemID: 2, ContractName: "@2Transfer", Fuel: 120,
+			RowsTouched: 3, Events: 2, Status: int64(pbgo.TxInvokeStatusCode_SUCCESS)},
+		{TxHash: []byte("penalty"), BlockID: 9, EcosystemID: 1, ContractName: "@1Fail", Fuel: 40,
+			RowsTouched: 1, Status: int64(pbgo.TxInvokeStatusCode_PENALTY)},
+		{TxHash: []byte("hash"), BlockID: 9, EcosystemID: 1},
+	}
+	if len(b.Receipts) != len(expected) {
+		t.Fatalf("expected %d receipts, got %d", len(expected), len(b.Receipts))
+	}
+	for i, r := range b.Receipts {
+		e := expected[i]
+		if !bytes.Equal(r.TxHash, e.TxHash) || r.BlockID != e.BlockID || r.EcosystemID != e.EcosystemID ||
+			r.ContractName != e.ContractName || r.Fuel != e.Fuel || r.RowsTouched != e.RowsTouched ||
+			r.Events != e.Events || r.Status != e.Status {
+			t.Errorf("receipt %d: expected %+v, got %+v", i, e, *r)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"io"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// Replay plays the transactions of the block again within dbTx and writes the full trace to w: the contract
+// calls, the statements and the fuel of every transaction. dbTx must have the state of the previous block,
+// the block isn't inserted and dbTx isn't committed. The bad transactions aren't marked and their keys aren't
+// banned. The trace is written even if the block is rejected, it shows where the play has stopped
+func (b *Block) Replay(dbTx *sqldb.DbTransaction, w io.Writer) error {
+	if err := b.retrieveTxs(); err != nil {
+		return err
+	}
+	b.replay = true
+	defer func() { b.replay = false }()
+	err := b.processTxs(context.Background(), dbTx)
+	if b.execTrace == nil {
+		return err
+	}
+	tr := b.execTrace.collect(b.Header.BlockId, b.Header.BlockHash, b.Transactions)
+	b.execTrace = nil
+	if errW := WriteTrace(w, tr); errW != nil && err == nil {
+		err = errW
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// errBatchFailed is returned by the transaction which fails without its own savepoint
+var errBatchFailed = errors.New("transaction of savepoint batch has failed")
+
+// txBatch is the transactions of the group which are played after one savepoint. The failed transaction
+// of the batch can't be rolled back alone, so the batch is rolled back to its savepoint and its
+// transactions are played again with their own savepoints
+type txBatch struct {
+	g      *txGroup
+	size   int
+	point  string
+	played []*transaction.Transaction
+	state  batchState
+}
+
+// batchState is the state of the played block before the batch, it's restored when the batch fails
+type batchState struct {
+	index         int
+	limits        *transaction.Limits
+	afterTxs      int
+	afterRts      int
+	processed     int
+	gasUsed       int64
+	sysUpdate     bool
+	auditLogs     int
+	notifications int
+	liftedBans    int
+	resourceUsage int
+	receipts      int
+	binLogSql     int
+	feeStats      map[int]int
+	contractStats map[sqldb.ContractStatsKey]sqldb.ContractStats
+	// outputs are the entries of OutputsMap before the batch has changed them
+	outputs map[sqldb.KeyUTXO][]sqldb.SpentInfo
+}
+
+// executeTx executes the next transaction of the group. The transactions are played in the batches of
+// savepoint_batch with one savepoint for the batch, the dry run transactions and the transactions
+// which have failed on the pre-execution have their own savepoints
+func (b *Block) executeTx(ctx context.Context, dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte, g *txGroup, t *transaction.Transaction) error {
+	if b.batch != nil && b.batch.g != g {
+		b.batch = nil
+	}
+	size := syspar.GetSavepointBatch()
+	if _, failed := b.preExecutedFailure(t); size <= 1 || t.IsDryRun() || failed {
+		b.batch = nil
+		return b.playTx(ctx, dbTx, txBadChan, afters, processedTx, g, t, true)
+	}
+	if b.batch == nil {
+		if err := b.openBatch(dbTx, afters, processedTx, g, t, size); err != nil {
+			return err
+		}
+	}
+	batch := b.batch
+	err := b.playTx(ctx, dbTx, txBadChan, afters, processedTx, g, t, false)
+	if err == errBatchFailed {
+		return b.replayBatch(ctx, dbTx, txBadChan, afters, processedTx, t)
+	}
+	if err != nil {
+		return err
+	}
+	batch.played = append(batch.played, t)
+	if len(batch.played) >= batch.size {
+		b.batch = nil
+	}
+	return nil
+}
+
+// openBatch sets the savepoint of the batch which starts with the transaction
+func (b *Block) openBatch(dbTx *sqldb.DbTransaction, afters *types.AfterTxs, processedTx *[][]byte, g *txGroup, t *transaction.Transaction, size int) error {
+	point := consts.SetSavePointMarkBlock("batch-" + hex.EncodeToString(t.Hash()))
+	err := timeSavepoint(statsd.DBSavepoint, func() error {
+		return dbTx.Savepoint(point)
+	})
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("using savepoint of batch")
+		return dbError("using savepoint", err)
+	}
+	b.Savepoints++
+	b.execTrace.add(t.Hash(), TraceSavepoint, "")
+	b.batch = &txBatch{
+		g:     g,
+		size:  size,
+		point: point,
+		state: b.batchState(afters, processedTx, g),
+	}
+	return nil
+}
+
+func (b *Block) batchState(afters *types.AfterTxs, processedTx *[][]byte, g *txGroup) batchState {
+	s := batchState{
+		index:         g.index,
+		limits:        g.limits.Clone(),
+		afterTxs:      len(afters.Txs),
+		afterRts:      len(afters.Rts),
+		processed:     len(*processedTx),
+		gasUsed:       b.GasUsed,
+		sysUpdate:     b.SysUpdate,
+		auditLogs:     len(b.AuditLogs),
+		notifications: len(b.Notifications),
+		liftedBans:    len(b.liftedBans),
+		resourceUsage: len(b.ResourceUsage),
+		receipts:      len(b.Receipts),
+		binLogSql:     len(b.BinLogSql),
+		feeStats:      make(map[int]int, len(b.FeeStats)),
+		contractStats: make(map[sqldb.ContractStatsKey]sqldb.ContractStats, len(b.ContractStats)),
+		outputs:       make(map[sqldb.KeyUTXO][]sqldb.SpentInfo),
+	}
+	for txType, prices := range b.FeeStats {
+		s.feeStats[txType] = len(prices)
+	}
+	for key, stats := range b.ContractStats {
+		c := *stats
+		c.Buckets = append([]int64(nil), stats.Buckets...)
+		s.contractStats[key] = c
+	}
+	return s
+}
+
+// saveOutputs keeps the entries of OutputsMap which the transaction of the batch is going to change
+func (bs *batchState) saveOutputs(outputsMap map[sqldb.KeyUTXO][]sqldb.SpentInfo, out *transaction.OutCtx) {
+	for _, m := range []map[sqldb.KeyUTXO][]sqldb.SpentInfo{out.TxInputsMap, out.TxOutputsMap} {
+		for key := range m {
+			if _, ok := bs.outputs[key]; !ok {
+				bs.outputs[key] = append([]sqldb.SpentInfo{}, outputsMap[key]...)
+			}
+		}
+	}
+}
+
+// loadOutputs adds the outputs of the previous blocks which are loaded during the batch to the saved entry
+func (bs *batchState) loadOutputs(key sqldb.KeyUTXO, infos []sqldb.SpentInfo) {
+	if saved, ok := bs.outputs[key]; ok {
+		bs.outputs[key] = append(append([]sqldb.SpentInfo{}, infos...), saved...)
+	}
+}
+
+// restore returns the block and the group to the state before the batch
+func (bs *batchState) restore(b *Block, afters *types.AfterTxs, processedTx *[][]byte, g *txGroup) {
+	g.index = bs.index
+	g.limits.Reset(bs.limits)
+	afters.Txs, afters.Rts = afters.Txs[:bs.afterTxs], afters.Rts[:bs.afterRts]
+	*processedTx = (*processedTx)[:bs.processed]
+	b.GasUsed, b.SysUpdate = bs.gasUsed, bs.sysUpdate
+	b.AuditLogs = b.AuditLogs[:bs.auditLogs]
+	b.Notifications = b.Notifications[:bs.notifications]
+	b.liftedBans = b.liftedBans[:bs.liftedBans]
+	b.ResourceUsage = b.ResourceUsage[:bs.resourceUsage]
+	b.Receipts = b.Receipts[:bs.receipts]
+	b.BinLogSql = b.BinLogSql[:bs.binLogSql]
+	for txType, prices := range b.FeeStats {
+		b.FeeStats[txType] = prices[:bs.feeStats[txType]]
+	}
+	for key, stats := range b.ContractStats {
+		if c, ok := bs.contractStats[key]; ok {
+			*stats = c
+		} else {
+			delete(b.ContractStats, key)
+		}
+	}
+	for key, infos := range bs.outputs {
+		if len(infos) == 0 {
+			delete(b.OutputsMap, key)
+		} else {
+			b.OutputsMap[key] = infos
+		}
+	}
+}
+
+// replayBatch rolls back the batch whose transaction t has failed and plays the transactions of the batch
+// and t again with their own savepoints
+func (b *Block) replayBatch(ctx context.Context, dbTx *sqldb.DbTransaction, txBadChan chan badTxStruct, afters *types.AfterTxs, processedTx *[][]byte, t *transaction.Transaction) error {
+	batch := b.batch
+	b.batch = nil
+	err := timeSavepoint(statsd.DBSavepointRollback, func() error {
+		return dbTx.RollbackSavepoint(batch.point)
+	})
+	if err != nil {
+		return dbError("rolling back savepoint of batch", err)
+	}
+	for i := len(batch.played) - 1; i >= 0; i-- {
+		flushVM(batch.played[i])
+	}
+	if b.SysUpdate || t.SysUpdate {
+		// the parameters which are changed by the batch are discarded
+		t.SysUpdate = false
+		if err = syspar.SysUpdate(dbTx); err != nil {
+			return fmt.Errorf("updating syspar: %w", err)
+		}
+	}
+	batch.state.restore(b, afters, processedTx, batch.g)
+	for _, p := range batch.played {
+		b.execTrace.drop(p.Hash())
+	}
+	b.execTrace.drop(t.Hash())
+	b.GetLogger().WithFields(log.Fields{"txs": len(batch.played) + 1}).Debug("savepoint batch is played again")
+	for _, p := range append(batch.played, t) {
+		if err = b.playTx(ctx, dbTx, txBadChan, afters, processedTx, batch.g, p, true); err != nil {
+			return err
+		}
+		if batch.g.stopped {
+			break
+		}
+	}
+	return nil
+}
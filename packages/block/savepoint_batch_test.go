@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// TestBatchStateRestore checks that the failed batch leaves the block as it was before the batch
+func TestBatchStateRestore(t *testing.T) {
+	key := sqldb.ContractStatsKey{Ecosystem: testEco, Contract: "@1TokenTransfer"}
+	b := &Block{
+		BlockData:     &types.BlockData{},
+		OutputsMap:    initialOutputs(),
+		FeeStats:      map[int][]int64{types.SmartContractTxType: {1}},
+		ContractStats: map[sqldb.ContractStatsKey]*sqldb.ContractStats{key: {}},
+		GasUsed:       10,
+	}
+	g := &txGroup{limits: transaction.NewLimits(transaction.GetLetParsing(), 1), index: 3}
+	afters := &types.AfterTxs{Txs: []*types.AfterTx{{}}}
+	processedTx := [][]byte{[]byte("tx0")}
+	wantOutputs := initialOutputs()
+
+	state := b.batchState(afters, &processedTx, g)
+	for i, toID := range []int64{200, 300} {
+		out := payUTXO(b.OutputsMap, toID, 10, false)
+		state.saveOutputs(b.OutputsMap, out)
+		b.applyTxOutputs([]byte{byte(i)}, out)
+		g.index++
+		b.GasUsed += 5
+		b.FeeStats[types.SmartContractTxType] = append(b.FeeStats[types.SmartContractTxType], 2)
+		b.ContractStats[key].Add(true, 5, 1)
+		afters.Txs = append(afters.Txs, &types.AfterTx{})
+		processedTx = append(processedTx, []byte{byte(i)})
+	}
+	if unusedBalance(b.OutputsMap, 200) != 10 {
+		t.Fatal("payment isn't applied")
+	}
+	state.restore(b, afters, &processedTx, g)
+
+	if !reflect.DeepEqual(b.OutputsMap, wantOutputs) {
+		t.Errorf("outputs aren't restored: %v", b.OutputsMap)
+	}
+	if g.index != 3 || b.GasUsed != 10 || len(afters.Txs) != 1 || len(processedTx) != 1 {
+		t.Errorf("state isn't restored: index %d, gas %d, afters %d, processed %d", g.index, b.GasUsed, len(afters.Txs), len(processedTx))
+	}
+	if len(b.FeeStats[types.SmartContractTxType]) != 1 || b.ContractStats[key].Success != 0 {
+		t.Error("stats aren't restored")
+	}
+}
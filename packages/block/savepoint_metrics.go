@@ -0,0 +1,24 @@
+//go:build metrics
+
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+)
+
+// timeSavepoint sends the latency of the savepoint call to statsd split by its result
+func timeSavepoint(counterName string, call func() error) error {
+	start := time.Now()
+	err := call()
+	if statsd.Client != nil {
+		statsd.Client.TimingDuration(statsd.SuccessCounterName(counterName, err == nil)+statsd.Time, time.Since(start), 1.0)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+//go:build metrics
+
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+	statsdclient "github.com/cactus/go-statsd-client/v5/statsd"
+	"github.com/cactus/go-statsd-client/v5/statsd/statsdtest"
+)
+
+// TestTimeSavepoint checks that the latencies of the committed and the failed savepoints and of the rollbacks to
+// them are sent with the label of the result
+func TestTimeSavepoint(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsdclient.NewClientWithSender(sender, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(c statsdclient.Statter) { statsd.Client = c }(statsd.Client)
+	statsd.Client = client
+
+	errSavepoint := errors.New("savepoint")
+	for _, item := range []struct {
+		counter string
+		err     error
+		stat    string
+	}{
+		{statsd.DBSavepoint, nil, "db.savepoint.success.time"},
+		{statsd.DBSavepoint, errSavepoint, "db.savepoint.failure.time"},
+		{statsd.DBSavepointRollback, nil, "db.savepoint_rollback.success.time"},
+		{statsd.DBSavepointRollback, errSavepoint, "db.savepoint_rollback.failure.time"},
+	} {
+		sender.ClearSent()
+		if err := timeSavepoint(item.counter, func() error { return item.err }); err != item.err {
+			t.Errorf("%s: expected %v got %v", item.stat, item.err, err)
+		}
+		sent := sender.GetSent()
+		if len(sent) != 1 || sent[0].Stat != item.stat || sent[0].Tag != "ms" {
+			t.Errorf("expected the timing of %s, got %v", item.stat, sent)
+		}
+	}
+}
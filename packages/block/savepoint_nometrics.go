@@ -0,0 +1,13 @@
+//go:build !metrics
+
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+// timeSavepoint only makes the savepoint call, the latency is measured in the builds with the metrics tag
+func timeSavepoint(_ string, call func() error) error {
+	return call()
+}
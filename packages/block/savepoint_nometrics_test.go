@@ -0,0 +1,38 @@
+//go:build !metrics
+
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+	statsdclient "github.com/cactus/go-statsd-client/v5/statsd"
+	"github.com/cactus/go-statsd-client/v5/statsd/statsdtest"
+)
+
+// TestTimeSavepoint checks that the savepoints aren't timed without the metrics tag
+func TestTimeSavepoint(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsdclient.NewClientWithSender(sender, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(c statsdclient.Statter) { statsd.Client = c }(statsd.Client)
+	statsd.Client = client
+
+	errSavepoint := errors.New("savepoint")
+	for _, want := range []error{nil, errSavepoint} {
+		if err := timeSavepoint(statsd.DBSavepoint, func() error { return want }); err != want {
+			t.Errorf("expected %v got %v", want, err)
+		}
+	}
+	if sent := sender.GetSent(); len(sent) != 0 {
+		t.Errorf("savepoints are timed without the metrics tag %v", sent)
+	}
+}
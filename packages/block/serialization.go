@@ -7,6 +7,7 @@ package block
 
 import (
 	"bytes"
+	"context"
 
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/utils"
@@ -44,7 +45,67 @@ func MarshallBlock(opts ...types.BlockDataOption) ([]byte, error) {
 	if err := block.Apply(opts...); err != nil {
 		return nil, err
 	}
-	return block.MarshallBlock(syspar.GetNodePrivKey())
+	data, err := block.MarshallBlock(syspar.GetNodeSigner())
+	if err != nil {
+		return nil, err
+	}
+	return types.WithBlockFormat(blockFormatAt(block.Header.BlockId), data)
+}
+
+// MarshallProvedBlock marshals the block which is generated out of the slot of the node, it has the
+// proof of work of minBits. The search of the proof is stopped when ctx is done
+func MarshallProvedBlock(ctx context.Context, minBits int, opts ...types.BlockDataOption) ([]byte, error) {
+	block := &types.BlockData{}
+	if err := block.Apply(opts...); err != nil {
+		return nil, err
+	}
+	data, err := block.MarshallProvedBlock(ctx, syspar.GetNodeSigner(), minBits)
+	if err != nil {
+		return nil, err
+	}
+	return types.WithBlockFormat(blockFormatAt(block.Header.BlockId), data)
+}
+
+// delayedContractNames returns the contracts which are executed by the delayed transactions
+func delayedContractNames() ([]string, error) {
+	allDelayedContract, err := sqldb.GetAllDelayedContract()
+	if err != nil {
+		return nil, err
+	}
+	contractNames := make([]string, 0, len(allDelayedContract))
+	for _, contract := range allDelayedContract {
+		contractNames = append(contractNames, contract.Contract)
+	}
+	return contractNames, nil
+}
+
+// classifyTx returns the type of the transaction in ClassifyTxsMap. The transactions
+// of the contracts from contractNames are delayed
+func classifyTx(tx *transaction.Transaction, contractNames []string) (int, bool) {
+	if tx.Type() == types.StopNetworkTxType || tx.Type() == types.KeyRotationTxType || tx.Type() == types.BanLiftTxType {
+		return int(tx.Type()), true
+	}
+	if tx.IsCustom() {
+		return int(tx.Type()), true
+	}
+	if !tx.IsSmartContract() {
+		return 0, false
+	}
+	switch tx.Type() {
+	case types.TransferSelfTxType, types.UtxoTxType:
+		return int(tx.Type()), true
+	case types.AbstractAccountTxType:
+		// the delayed contracts are signed by the nodes
+		return types.SmartContractTxType, true
+	case types.ConfidentialUTXOTxType:
+		// the commitments are verified by the contract stage one by one
+		return types.SmartContractTxType, true
+	}
+	if utils.StringInSlice(contractNames, tx.SmartContract().TxContract.Name) {
+		tx.SmartContract().Delayed = true
+		return types.DelayTxType, true
+	}
+	return types.SmartContractTxType, true
 }
 
 func UnmarshallBlock(blockBuffer *bytes.Buffer, fill bool) (*Block, error) {
@@ -58,14 +119,10 @@ func UnmarshallBlock(blockBuffer *bytes.Buffer, fill bool) (*Block, error) {
 	}
 
 	if block.Header.BlockId != 1 {
-		allDelayedContract, err := sqldb.GetAllDelayedContract()
-		if err != nil {
+		var err error
+		if contractNames, err = delayedContractNames(); err != nil {
 			return nil, err
 		}
-
-		for _, contract := range allDelayedContract {
-			contractNames = append(contractNames, contract.Contract)
-		}
 	}
 
 	transactions := make([]*transaction.Transaction, 0)
@@ -74,28 +131,8 @@ func UnmarshallBlock(blockBuffer *bytes.Buffer, fill bool) (*Block, error) {
 		if err != nil {
 			return nil, err
 		}
-		if tx.Type() == types.StopNetworkTxType {
-			classifyTxsMap[types.StopNetworkTxType] = append(classifyTxsMap[types.StopNetworkTxType], tx)
-			transactions = append(transactions, tx)
-			continue
-		}
-		if tx.IsSmartContract() {
-			if tx.Type() == types.TransferSelfTxType {
-				classifyTxsMap[types.TransferSelfTxType] = append(classifyTxsMap[types.TransferSelfTxType], tx)
-				transactions = append(transactions, tx)
-				continue
-			}
-			if tx.Type() == types.UtxoTxType {
-				classifyTxsMap[types.UtxoTxType] = append(classifyTxsMap[types.UtxoTxType], tx)
-				transactions = append(transactions, tx)
-				continue
-			}
-			if utils.StringInSlice(contractNames, tx.SmartContract().TxContract.Name) {
-				classifyTxsMap[types.DelayTxType] = append(classifyTxsMap[types.DelayTxType], tx)
-				transactions = append(transactions, tx)
-				continue
-			}
-			classifyTxsMap[types.SmartContractTxType] = append(classifyTxsMap[types.SmartContractTxType], tx)
+		if txType, ok := classifyTx(tx, contractNames); ok {
+			classifyTxsMap[txType] = append(classifyTxsMap[txType], tx)
 		}
 		transactions = append(transactions, tx)
 	}
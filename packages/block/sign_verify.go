@@ -0,0 +1,82 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// signWorkers returns the number of the workers which verify the signatures before the play
+func signWorkers() int {
+	if conf.Config.SignWorkers != 0 {
+		return conf.Config.SignWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// preVerifySigns verifies the signatures of the contract transactions by the workers before the play. The
+// public key is taken like the contract takes it on the state before the block, the play verifies the
+// signature again if the key is changed by the previous transactions of the block
+func (b *Block) preVerifySigns(dbTx *sqldb.DbTransaction, txs []*transaction.Transaction, workers int) {
+	var (
+		smartTxs []*transaction.SmartTransactionParser
+		ids      []int64
+	)
+	for _, t := range txs {
+		if !t.IsSmartContract() {
+			continue
+		}
+		s := t.SmartContract()
+		if s.TxSmart == nil || s.TxSmart.AbstractAccount {
+			continue
+		}
+		smartTxs = append(smartTxs, s)
+		ids = append(ids, signedBy(s))
+	}
+	if len(smartTxs) == 0 {
+		return
+	}
+	start := time.Now()
+	keys, err := sqldb.GetPublicKeys(dbTx, ids)
+	if err != nil {
+		// the signatures are verified by the play
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting public keys of transactions")
+		return
+	}
+	checks := make([]utils.SignCheck, 0, len(smartTxs))
+	for i, s := range smartTxs {
+		var public []byte
+		if len(s.TxSmart.PublicKey) > 0 && string(s.TxSmart.PublicKey) != `null` {
+			public = s.TxSmart.PublicKey
+		}
+		if pub := keys[[2]int64{s.TxSmart.EcosystemID, ids[i]}]; len(pub) > 0 {
+			public = pub
+		}
+		if len(public) == 0 {
+			continue
+		}
+		checks = append(checks, utils.SignCheck{PublicKey: public, ForSign: s.Hash, Signs: s.TxSignature})
+	}
+	verified := utils.PreVerifySigns(checks, workers)
+	b.GetLogger().WithFields(log.Fields{"txs": len(smartTxs), "signs": verified, "workers": workers,
+		"duration": time.Since(start)}).Debug("signatures are pre-verified")
+}
+
+// signedBy returns the key which signs the transaction
+func signedBy(s *transaction.SmartTransactionParser) int64 {
+	if s.TxSmart.SignedBy != 0 {
+		return s.TxSmart.SignedBy
+	}
+	return s.TxSmart.KeyID
+}
@@ -0,0 +1,177 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+)
+
+// signedBlocksDepth is the number of the heights which the hashes of the signed blocks are kept for
+const signedBlocksDepth = 100
+
+// validatorKeys returns the public keys of the validators by their key ids which sign the block
+var validatorKeys = syspar.GetValidatorKeysAt
+
+var (
+	// signedBlocks is the hashes of the blocks which are signed by the node as the validator by their ids
+	signedBlocks   = make(map[int64][]byte)
+	signedBlocksMu sync.Mutex
+)
+
+// ValidatorKeyIDOf returns the key id of the validator of the block which signs with the public key
+func ValidatorKeyIDOf(blockID int64, publicKey []byte) (int64, error) {
+	for keyID, keys := range validatorKeys(blockID) {
+		for _, key := range keys {
+			if bytes.Equal(key, publicKey) {
+				return keyID, nil
+			}
+		}
+	}
+	return 0, ErrNotValidator
+}
+
+// AddSignature signs the header of the block by the validator, the previous signature of the validator
+// is replaced. The signatures aren't signed and hashed with the header, so the hash of the block is kept
+func (b *Block) AddSignature(signer keystore.Signer) error {
+	sign, err := b.validatorSign(signer)
+	if err != nil {
+		return err
+	}
+	return b.SetSignature(sign)
+}
+
+func (b *Block) validatorSign(signer keystore.Signer) (*types.BlockSignature, error) {
+	keyID, err := ValidatorKeyIDOf(b.Header.BlockId, signer.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	sign, err := keystore.SignData(signer, []byte(b.ForSign()))
+	if err != nil {
+		return nil, fmt.Errorf("signing block by validator: %w", err)
+	}
+	return &types.BlockSignature{ValidatorKeyId: keyID, Signature: sign}, nil
+}
+
+// SetSignature adds the signature of the validator to the header of the block, the previous signature
+// of the validator is replaced
+func (b *Block) SetSignature(sign *types.BlockSignature) error {
+	if sign == nil || len(sign.Signature) == 0 {
+		return ErrBlockSignature
+	}
+	for i, item := range b.Header.BlockSignatures {
+		if item.ValidatorKeyId == sign.ValidatorKeyId {
+			b.Header.BlockSignatures[i] = sign
+			return nil
+		}
+	}
+	b.Header.BlockSignatures = append(b.Header.BlockSignatures, sign)
+	return nil
+}
+
+// VerifyThreshold returns nil if at least threshold of the validators sign the header of the block.
+// The signatures of the keys which aren't in validators, the repeated and the incorrect signatures
+// reject the block. Zero threshold doesn't require the signatures
+func (b *Block) VerifyThreshold(validators []int64, threshold int) error {
+	if threshold <= 0 {
+		return nil
+	}
+	if threshold > len(validators) {
+		return fmt.Errorf("%w: threshold %d is greater than %d validators", ErrNotEnoughSignatures, threshold, len(validators))
+	}
+	allowed := make(map[int64]bool, len(validators))
+	for _, keyID := range validators {
+		allowed[keyID] = true
+	}
+	keys := validatorKeys(b.Header.BlockId)
+	forSign := []byte(b.ForSign())
+	signed := make(map[int64]bool)
+	for _, sign := range b.Header.BlockSignatures {
+		keyID := sign.ValidatorKeyId
+		if !allowed[keyID] {
+			return fmt.Errorf("%w: %d isn't the validator", ErrBlockSignature, keyID)
+		}
+		if signed[keyID] {
+			return fmt.Errorf("%w: %d signs twice", ErrBlockSignature, keyID)
+		}
+		if !checkValidatorSign(keys[keyID], forSign, sign.Signature) {
+			return fmt.Errorf("%w: %d", ErrBlockSignature, keyID)
+		}
+		signed[keyID] = true
+	}
+	if len(signed) < threshold {
+		return fmt.Errorf("%w: %d of %d", ErrNotEnoughSignatures, len(signed), threshold)
+	}
+	return nil
+}
+
+// CheckValidatorSignature returns nil if the signature is the signature of the block by the validator
+func (b *Block) CheckValidatorSignature(sign *types.BlockSignature) error {
+	if !checkValidatorSign(validatorKeys(b.Header.BlockId)[sign.ValidatorKeyId], []byte(b.ForSign()), sign.Signature) {
+		return fmt.Errorf("%w: %d", ErrBlockSignature, sign.ValidatorKeyId)
+	}
+	return nil
+}
+
+// CheckSignatures checks the signatures of the validators of the block by block_signature_threshold
+// and block_validators, the threshold is used in the honor node mode only
+func (b *Block) CheckSignatures() error {
+	if b.IsGenesis() || conf.Config.IsSubNode() || b.PrevHeader == nil || !syspar.IsHonorNodeMode() {
+		return nil
+	}
+	blockID := b.Header.BlockId
+	return b.VerifyThreshold(syspar.GetBlockValidatorsAt(blockID), int(syspar.GetBlockSignatureThresholdAt(blockID)))
+}
+
+// SignAsValidator returns the signature of the block of the binary data by the validator, the block is
+// generated by the other node. The hash of the block and the signature of its node are checked, and the
+// validator signs one block of the height only, so the blocks of the fork don't get the threshold
+func SignAsValidator(data []byte, signer keystore.Signer) (*types.BlockSignature, error) {
+	b, err := ProcessBlockByBinData(data, true)
+	if err != nil {
+		return nil, err
+	}
+	blockID := b.Header.BlockId
+	if !bytes.Equal(b.Header.BlockHash, b.Header.GenHash(b.PrevHeader, b.MerkleRoot)) {
+		return nil, fmt.Errorf("%w: hash of block %d doesn't match", ErrBlockSignature, blockID)
+	}
+	if err = b.CheckSign(); err != nil {
+		return nil, err
+	}
+
+	signedBlocksMu.Lock()
+	defer signedBlocksMu.Unlock()
+	if hash, ok := signedBlocks[blockID]; ok && !bytes.Equal(hash, b.Header.BlockHash) {
+		return nil, fmt.Errorf("%w: other block %d is signed", ErrBlockSignature, blockID)
+	}
+	sign, err := b.validatorSign(signer)
+	if err != nil {
+		return nil, err
+	}
+	signedBlocks[blockID] = b.Header.BlockHash
+	for id := range signedBlocks {
+		if id <= blockID-signedBlocksDepth {
+			delete(signedBlocks, id)
+		}
+	}
+	return sign, nil
+}
+
+func checkValidatorSign(keys [][]byte, forSign, sign []byte) bool {
+	for _, key := range keys {
+		if ok, err := utils.CheckSign([][]byte{key}, forSign, sign, true); ok && err == nil {
+			return true
+		}
+	}
+	return false
+}
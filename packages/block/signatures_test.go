@@ -0,0 +1,97 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestVerifyThreshold(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	signers := make([]keystore.Signer, 4)
+	keys := make(map[int64][][]byte)
+	var validators []int64
+	for i := range signers {
+		priv, pub, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signers[i], err = keystore.NewKeySigner(priv); err != nil {
+			t.Fatal(err)
+		}
+		keys[crypto.Address(pub)] = [][]byte{pub}
+		if i < 3 {
+			validators = append(validators, crypto.Address(pub))
+		}
+	}
+	defer func(f func(int64) map[int64][][]byte) { validatorKeys = f }(validatorKeys)
+	validatorKeys = func(int64) map[int64][][]byte { return keys }
+
+	b := mustBuild(t, newTestBuilder(10).WithSigner(signers[0]))
+	hash := string(b.Header.BlockHash)
+	if err := b.VerifyThreshold(validators, 0); err != nil {
+		t.Errorf("zero threshold must not require signatures: %v", err)
+	}
+	if err := b.VerifyThreshold(validators, 4); !errors.Is(err, ErrNotEnoughSignatures) {
+		t.Errorf("threshold greater than validators is accepted: %v", err)
+	}
+	for _, signer := range signers[:2] {
+		if err := b.AddSignature(signer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the repeated signing replaces the signature of the validator
+	if err := b.AddSignature(signers[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.VerifyThreshold(validators, 2); err != nil {
+		t.Errorf("2 of 3 signatures: %v", err)
+	}
+	if err := b.VerifyThreshold(validators, 3); !errors.Is(err, ErrNotEnoughSignatures) {
+		t.Errorf("expected not enough signatures, got %v", err)
+	}
+	if string(b.Header.BlockHash) != hash || len(b.Header.BlockSignatures) != 2 {
+		t.Errorf("wrong signatures %v", b.Header.BlockSignatures)
+	}
+
+	other := append([]*types.BlockSignature{}, b.Header.BlockSignatures...)
+	if err := b.AddSignature(signers[3]); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.VerifyThreshold(validators, 2); !errors.Is(err, ErrBlockSignature) {
+		t.Errorf("signature of the key out of the validators is accepted: %v", err)
+	}
+	b.Header.BlockSignatures = append(other, &types.BlockSignature{ValidatorKeyId: validators[2], Signature: other[0].Signature})
+	if err := b.VerifyThreshold(validators, 2); !errors.Is(err, ErrBlockSignature) {
+		t.Errorf("signature of the other validator is accepted: %v", err)
+	}
+	b.Header.BlockSignatures = []*types.BlockSignature{other[0], other[0]}
+	if err := b.VerifyThreshold(validators, 2); !errors.Is(err, ErrBlockSignature) {
+		t.Errorf("repeated signature is accepted: %v", err)
+	}
+
+	b.Header.BlockSignatures = other
+	data, err := types.WithBlockSignatures(b.BinData, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := &types.BlockData{}
+	if err = parsed.UnmarshallBlock(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsed.Header.BlockHash, b.Header.BlockHash) {
+		t.Error("signatures change the hash of the block")
+	}
+	if err = (&Block{BlockData: parsed}).VerifyThreshold(validators, 2); err != nil {
+		t.Errorf("signatures of the binary: %v", err)
+	}
+}
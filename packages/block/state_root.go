@@ -0,0 +1,215 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrStateRoot is returned if the state root of the block differs from the state of the node after the block
+var ErrStateRoot = errors.New("state root of the block doesn't match")
+
+// spentInfoTable is the table of the outputs in the leaves of the state root
+const spentInfoTable = "spent_info"
+
+// StateRow is the row which is changed by the block, Value is the canonical json of the row after the block
+type StateRow struct {
+	Table string
+	Key   string
+	Value []byte
+}
+
+// Leaf returns the leaf of the row in the state root
+func (r StateRow) Leaf() []byte {
+	return types.StateLeaf(r.Table, r.Key, r.Value)
+}
+
+// stateRows returns the rows which are changed by the rollback records and the outputs of the block
+// within dbTx, they are ordered by the table and the key. Only the changed rows are committed to the
+// state root, not the whole state
+func stateRows(dbTx *sqldb.DbTransaction, blockID int64) ([]StateRow, error) {
+	rollbacks, err := (&sqldb.RollbackTx{}).GetBlockRollbackTransactions(dbTx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rows    []StateRow
+		seen    = make(map[[2]string]bool)
+		columns = make(map[string]string)
+	)
+	for _, rt := range rollbacks {
+		if rt.NameTable == smart.SysName {
+			continue
+		}
+		ref, err := rt.Row()
+		if err != nil {
+			return nil, err
+		}
+		id := [2]string{ref.Table, ref.Key()}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		row := StateRow{Table: ref.Table, Key: ref.Key()}
+		// the rows of the table which is dropped by the block are removed
+		if dbTx.IsTable(ref.Table) {
+			if row.Value, err = readRow(dbTx, ref, columns); err != nil {
+				return nil, err
+			}
+		}
+		rows = append(rows, row)
+	}
+	outputs, err := sqldb.GetBlockOutputs(dbTx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range outputs {
+		value, err := json.Marshal(&outputs[i])
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%x,%d", outputs[i].OutputTxHash, outputs[i].OutputIndex)
+		rows = append(rows, StateRow{Table: spentInfoTable, Key: key, Value: value})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Table != rows[j].Table {
+			return rows[i].Table < rows[j].Table
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows, nil
+}
+
+// stateLeaves returns the leaves of the rows
+func stateLeaves(rows []StateRow) [][]byte {
+	leaves := make([][]byte, len(rows))
+	for i, row := range rows {
+		leaves[i] = row.Leaf()
+	}
+	return leaves
+}
+
+// commitStateRoot sets the state root of the generated block or checks the state root of the played block,
+// it returns the leaves which are stored with the block
+func (b *Block) commitStateRoot(dbTx *sqldb.DbTransaction) ([]byte, error) {
+	h := b.Header
+	if !syspar.IsStateRootAt(h.BlockId) {
+		if !b.GenBlock && len(h.StateRoot) > 0 {
+			return nil, fmt.Errorf("%w: state root is disabled", ErrStateRoot)
+		}
+		return nil, nil
+	}
+	rows, err := stateRows(dbTx, h.BlockId)
+	if err != nil {
+		b.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting state rows of block")
+		return nil, err
+	}
+	leaves := stateLeaves(rows)
+	root := types.StateRoot(leaves)
+	if b.GenBlock {
+		h.StateRoot = root
+	} else if !bytes.Equal(h.StateRoot, root) {
+		b.GetLogger().WithFields(log.Fields{"type": consts.BlockError, "block_id": h.BlockId, "rows": len(rows),
+			"local_root": fmt.Sprintf("%x", root), "remote_root": fmt.Sprintf("%x", h.StateRoot)}).Error("state diverged")
+		return nil, fmt.Errorf("%w: block %d, local %x, remote %x", ErrStateRoot, h.BlockId, root, h.StateRoot)
+	}
+	return bytes.Join(leaves, nil), nil
+}
+
+// StateProof is the merkle path from the row which is changed by the block to the state root of its header
+type StateProof struct {
+	BlockID   int64                   `json:"block_id"`
+	StateRoot []byte                  `json:"state_root"`
+	Table     string                  `json:"table"`
+	Key       string                  `json:"key"`
+	Value     json.RawMessage         `json:"value"` // null for the removed row
+	Path      []types.MerkleProofItem `json:"path"`
+}
+
+// Verify returns true if the path of the row leads to root
+func (p *StateProof) Verify(root []byte) bool {
+	var value []byte
+	if len(p.Value) > 0 && string(p.Value) != "null" {
+		value = p.Value
+	}
+	return bytes.Equal(p.StateRoot, root) && types.VerifyMerkleProof(root, types.StateLeaf(p.Table, p.Key, value), p.Path)
+}
+
+// ErrStateProof is returned if the row isn't in the state root of the block with its current value
+var ErrStateProof = errors.New("row isn't in the state root of the block")
+
+// GetStateProof returns the proof of the committed row of the table with the key for the state root of the block.
+// The proof is built from the current value of the row, so it fails with ErrStateProof as soon as a later block
+// changes the row
+func GetStateProof(blockID int64, table, key string) (*StateProof, error) {
+	bl := &sqldb.BlockChain{}
+	found, err := bl.Get(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: block %d isn't found", ErrStateProof, blockID)
+	}
+	header, err := types.ParseBlockHeader(bytes.NewBuffer(bl.Data), syspar.GetMaxBlockSize())
+	if err != nil {
+		return nil, err
+	}
+	if len(header.StateRoot) == 0 {
+		return nil, fmt.Errorf("%w: block %d has no state root", ErrStateProof, blockID)
+	}
+	row := StateRow{Table: table, Key: key}
+	if table == spentInfoTable {
+		return nil, fmt.Errorf("%w: proofs of outputs aren't supported", ErrStateProof)
+	}
+	ref := sqldb.RowRef{Table: table, ID: key}
+	if id, eco, ok := splitSharedKey(key); ok {
+		ref = sqldb.RowRef{Table: table, ID: id, Ecosystem: eco, Shared: true}
+	}
+	if _, err = strconv.ParseInt(ref.ID, 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: wrong key %s", ErrStateProof, key)
+	}
+	// the row of the dropped table is the removed one
+	if (*sqldb.DbTransaction)(nil).IsTable(table) {
+		if row.Value, err = readRow(nil, ref, nil); err != nil {
+			return nil, err
+		}
+	}
+	leaves := types.SplitStateLeaves(bl.StateLeaves)
+	leaf := row.Leaf()
+	for i, l := range leaves {
+		if bytes.Equal(l, leaf) {
+			return &StateProof{BlockID: blockID, StateRoot: header.StateRoot, Table: table, Key: key,
+				Value: row.Value, Path: types.MerkleTreeProof(leaves, i)}, nil
+		}
+	}
+	return nil, ErrStateProof
+}
+
+// splitSharedKey returns the id and the ecosystem of the key id,ecosystem of the row of the shared table
+func splitSharedKey(key string) (string, int64, bool) {
+	i := strings.LastIndexByte(key, ',')
+	if i <= 0 {
+		return "", 0, false
+	}
+	eco, err := strconv.ParseInt(key[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i], eco, true
+}
@@ -0,0 +1,52 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestStateProof(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	rows := []StateRow{
+		{Table: "1_keys", Key: "100,1", Value: []byte(`{"amount":"10"}`)},
+		{Table: "1_keys", Key: "200,1", Value: []byte(`{"amount":"20"}`)},
+		{Table: "2_notes", Key: "5"}, // removed row
+	}
+	leaves := stateLeaves(rows)
+	root := types.StateRoot(leaves)
+	joined := bytes.Join(leaves, nil)
+	for i, row := range rows {
+		value := row.Value
+		if value == nil {
+			value = []byte("null")
+		}
+		p := &StateProof{StateRoot: root, Table: row.Table, Key: row.Key, Value: value,
+			Path: types.MerkleTreeProof(types.SplitStateLeaves(joined), i)}
+		if !p.Verify(root) {
+			t.Fatalf("proof of %s %s isn't valid", row.Table, row.Key)
+		}
+		p.Value = []byte(fmt.Sprintf(`{"amount":"%d"}`, i+100))
+		if p.Verify(root) {
+			t.Fatalf("proof of %s %s with the other value is valid", row.Table, row.Key)
+		}
+	}
+	if bytes.Equal(types.StateRoot(nil), root) || len(types.StateRoot(nil)) == 0 {
+		t.Error("wrong state root of the block without changes")
+	}
+
+	if id, eco, ok := splitSharedKey("100,1"); !ok || id != "100" || eco != 1 {
+		t.Errorf("wrong shared key %s %d", id, eco)
+	}
+	if _, _, ok := splitSharedKey("100"); ok {
+		t.Error("key without ecosystem is split")
+	}
+}
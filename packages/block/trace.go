@@ -0,0 +1,44 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+)
+
+// tracer is no-op until the tracer provider is set by tracing.Init
+var tracer = otel.Tracer("github.com/IBAX-io/go-ibax/packages/block")
+
+// setTxAttributes describes the transaction in its span. The attributes are only
+// computed if the span is sampled
+func setTxAttributes(span trace.Span, t *transaction.Transaction) {
+	if !span.IsRecording() {
+		return
+	}
+	eco, contract := txContract(t)
+	span.SetAttributes(
+		attribute.String("tx.hash", hex.EncodeToString(t.Hash())),
+		attribute.Int("tx.type", int(t.Type())),
+		attribute.Int64("tx.key_id", t.KeyID()),
+		attribute.Int64("tx.ecosystem_id", eco),
+		attribute.String("tx.contract", contract),
+	)
+}
+
+// spanError marks the span as failed
+func spanError(span trace.Span, err error) {
+	if err != nil && span.IsRecording() {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/script"
+)
+
+// TxCanceledError is the transaction which is stopped by its context, Reason is recorded in its status
+type TxCanceledError struct {
+	Reason string
+	Err    error
+}
+
+func (e *TxCanceledError) Error() string {
+	return fmt.Sprintf("transaction is canceled (%s): %v", e.Reason, e.Err)
+}
+
+func (e *TxCanceledError) Unwrap() error { return e.Err }
+
+// status returns the error of the status of the transaction
+func (e *TxCanceledError) status() string {
+	return script.SetVMError(`canceled`, e.Reason).Error()
+}
+
+// txContext returns the context of the played transaction. The generator stops the transaction after the
+// time limit of the contracts, the validators are bound by the fuel, so they stop it by ctx only
+func (b *Block) txContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if !b.GenBlock {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(syspar.GetMaxBlockGenerationTime())*time.Millisecond)
+}
+
+// canceledTx returns the error of the transaction which is stopped by txCtx, the cancellation of the play
+// of the block isn't the fault of the transaction, so it's retryable. It returns nil if txCtx isn't done
+func canceledTx(ctx, txCtx context.Context, err error) error {
+	if txCtx.Err() == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return &PlayError{Kind: KindRetryable, Op: "playing transaction", Err: context.Cause(ctx)}
+	}
+	reason := context.Cause(txCtx).Error()
+	if errors.Is(txCtx.Err(), context.DeadlineExceeded) {
+		reason = fmt.Sprintf("deadline of %d ms exceeded", syspar.GetMaxBlockGenerationTime())
+	}
+	return &TxCanceledError{Reason: reason, Err: err}
+}
@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"sort"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+)
+
+// TxGroupsState is the state of the groups of the utxo or transfer self transactions
+type TxGroupsState struct {
+	Groups  int      `json:"groups"`
+	Keys    []string `json:"keys"`
+	ListLen int      `json:"list_len"`
+	Serial  uint16   `json:"serial"`
+	// Stuck is true if the groups aren't reset while no block is played
+	Stuck bool `json:"stuck"`
+}
+
+// TxGroupsSnapshot is the state of the package variables of the grouped transactions
+type TxGroupsSnapshot struct {
+	// Busy is true if the transactions of the block are grouped or played, the groups aren't read then
+	Busy         bool           `json:"busy"`
+	Utxo         *TxGroupsState `json:"utxo,omitempty"`
+	TransferSelf *TxGroupsState `json:"transfer_self,omitempty"`
+}
+
+// GetTxGroupsSnapshot returns the state of the groups of the transactions. The groups are empty after
+// each block, the groups which are left after the panic of the play are reported as stuck
+func GetTxGroupsSnapshot() *TxGroupsSnapshot {
+	if !groupsLock.TryLock() {
+		return &TxGroupsSnapshot{Busy: true}
+	}
+	defer groupsLock.Unlock()
+	return &TxGroupsSnapshot{
+		Utxo:         txGroupsState(utxoTxsGroupMap, utxoGroupTxsList, utxoGroupSerial),
+		TransferSelf: txGroupsState(transferSelfTxsGroupMap, transferSelfGroupTxsList, transferSelfGroupSerial),
+	}
+}
+
+func txGroupsState(groups map[string][]*transaction.Transaction, list []*transaction.Transaction, serial uint16) *TxGroupsState {
+	state := &TxGroupsState{
+		Groups:  len(groups),
+		Keys:    make([]string, 0, len(groups)),
+		ListLen: len(list),
+		Serial:  serial,
+	}
+	for key := range groups {
+		state.Keys = append(state.Keys, key)
+	}
+	sort.Strings(state.Keys)
+	state.Stuck = state.Groups > 0 || state.ListLen > 0 || serial != 1
+	return state
+}
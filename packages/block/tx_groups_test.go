@@ -0,0 +1,39 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package block
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+)
+
+func TestGetTxGroupsSnapshot(t *testing.T) {
+	s := GetTxGroupsSnapshot()
+	if s.Busy || s.Utxo.Stuck || s.TransferSelf.Stuck {
+		t.Fatalf("groups must be empty %+v", s)
+	}
+
+	defer func() {
+		utxoTxsGroupMap = make(map[string][]*transaction.Transaction)
+		utxoGroupTxsList = make([]*transaction.Transaction, 0)
+		utxoGroupSerial = 1
+	}()
+	tx := newCustomTx(t, 30)
+	utxoTxsGroupMap["2"], utxoTxsGroupMap["1"] = []*transaction.Transaction{tx}, []*transaction.Transaction{tx}
+	utxoGroupSerial = 2
+	s = GetTxGroupsSnapshot()
+	if !s.Utxo.Stuck || s.Utxo.Groups != 2 || s.Utxo.Keys[0] != "1" || s.Utxo.Serial != 2 || s.TransferSelf.Stuck {
+		t.Errorf("stuck groups aren't reported %+v %+v", s.Utxo, s.TransferSelf)
+	}
+
+	groupsLock.Lock()
+	s = GetTxGroupsSnapshot()
+	groupsLock.Unlock()
+	if !s.Busy || s.Utxo != nil {
+		t.Errorf("groups are read while the block is played %+v", s)
+	}
+}
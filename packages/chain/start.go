@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/IBAX-io/go-ibax/packages/api"
+	"github.com/IBAX-io/go-ibax/packages/block"
 	"github.com/IBAX-io/go-ibax/packages/chain/daemonsctl"
 	"github.com/IBAX-io/go-ibax/packages/chain/system"
 
@@ -28,10 +29,16 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/daemons"
 	"github.com/IBAX-io/go-ibax/packages/modes"
 	"github.com/IBAX-io/go-ibax/packages/network/httpserver"
+	"github.com/IBAX-io/go-ibax/packages/notificator"
 	"github.com/IBAX-io/go-ibax/packages/publisher"
+	"github.com/IBAX-io/go-ibax/packages/service/kvhook"
 	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/snapshot"
 	"github.com/IBAX-io/go-ibax/packages/statsd"
+	"github.com/IBAX-io/go-ibax/packages/storage/dastore"
+	"github.com/IBAX-io/go-ibax/packages/storage/mmapstore"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/tracing"
 	"github.com/IBAX-io/go-ibax/packages/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -54,7 +61,11 @@ func Start() {
 	exitErr := func(code int) {
 		system.RemovePidFile()
 		sqldb.GormClose()
+		if mmapstore.Store != nil {
+			mmapstore.Store.Close()
+		}
 		statsd.Close()
+		tracing.Close()
 		os.Exit(code)
 	}
 	f := utils.LockOrDie(conf.Config.DirPathConf.LockFilePath)
@@ -83,7 +94,10 @@ func Start() {
 	}
 
 	publisher.InitCentrifugo(conf.Config.Centrifugo)
+	notificator.StartDispatcher(context.Background(), conf.Config.NotifyWorkers, conf.Config.NotifyQueueSize)
 	initStatsd()
+	initTracing()
+	initKVHooks()
 
 	rand.Seed(time.Now().UTC().UnixNano())
 	smart.InitVM()
@@ -99,11 +113,28 @@ func Start() {
 		exitErr(1)
 	}
 
+	if conf.Config.MMAPBlockStorePath != "" {
+		if err = mmapstore.Init(conf.Config.MMAPBlockStorePath); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "path": conf.Config.MMAPBlockStorePath}).Error("can't open block store")
+			exitErr(1)
+		}
+	}
+	if err = dastore.Init(conf.Config.DA); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err, "da_type": conf.Config.DA.Type}).Error("can't init data availability layer")
+		exitErr(1)
+	}
+
 	if sqldb.DBConn != nil {
 		if err := sqldb.UpdateSchema(); err != nil {
 			log.WithError(err).Error("on running update migrations")
 			exitErr(1)
 		}
+		if len(conf.Config.SnapshotDir) > 0 {
+			if _, err := snapshot.Import(context.Background(), conf.Config.SnapshotDir); err != nil {
+				log.WithFields(log.Fields{"error": err, "dir": conf.Config.SnapshotDir}).Error("can't import snapshot")
+				exitErr(1)
+			}
+		}
 		candidateNodes, err := sqldb.GetCandidateNode(syspar.SysInt(syspar.NumberNodes))
 		if err == nil && len(candidateNodes) > 0 {
 			syspar.SetRunModel(consts.CandidateNodeMode)
@@ -136,10 +167,27 @@ func initStatsd() {
 	}
 }
 
+func initTracing() {
+	if err := tracing.Init(conf.Config.Tracing); err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Fatal("cannot initialize tracing")
+	}
+}
+
+func initKVHooks() {
+	if !conf.Config.KVHook.Redis {
+		return
+	}
+	client, err := kvhook.NewRedisClient(conf.Config.Redis, conf.Config.KVHook.RedisDb)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Fatal("cannot connect to redis of kv commit hook")
+	}
+	block.RegisterKVCommitHook("redis", kvhook.NewRedis(client, conf.Config.KVHook.Prefix))
+}
+
 func initLogs() error {
 	switch conf.Config.Log.LogFormat {
 	case "json":
-		log.SetFormatter(&log.JSONFormatter{})
+		log.SetFormatter(logtools.NewJSONFormatter())
 	default:
 		log.SetFormatter(&log.TextFormatter{})
 	}
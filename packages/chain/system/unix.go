@@ -24,3 +24,12 @@ func KillPid(pid string) error {
 	}
 	return nil
 }
+
+// DiskFree returns the free space in bytes of the file system of path which is available to the node
+func DiskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
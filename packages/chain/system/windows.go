@@ -17,6 +17,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows"
 )
 
 // KillPid kills the process with the specified pid
@@ -43,3 +44,16 @@ func KillPid(pid string) error {
 	}
 	return nil
 }
+
+// DiskFree returns the free space in bytes of the disk of path which is available to the node
+func DiskFree(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if err = windows.GetDiskFreeSpaceEx(p, &free, nil, nil); err != nil {
+		return 0, err
+	}
+	return free, nil
+}
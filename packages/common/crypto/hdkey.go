@@ -0,0 +1,153 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package crypto
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto/asymalgo"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+const (
+	// HardenedKeyStart is the first index of the hardened child keys, they are derived from the private key only
+	HardenedKeyStart uint32 = 0x80000000
+	// ChainCodeLength is the length of the chain code which follows the key in the extended key
+	ChainCodeLength = 32
+	// ExtendedPrivLength is the length of the extended private key
+	ExtendedPrivLength = consts.PrivkeyLength + ChainCodeLength
+	// ExtendedPubLength is the length of the extended public key
+	ExtendedPubLength = consts.PubkeySizeLength + ChainCodeLength
+)
+
+var (
+	// ErrExtendedKey is returned if the extended key is malformed
+	ErrExtendedKey = errors.New("wrong extended key")
+	// ErrHardenedPublic is returned if the hardened child is derived from the public key
+	ErrHardenedPublic = errors.New("hardened child key can't be derived from public key")
+	// ErrInvalidChild is returned for the index which gives the invalid key, BIP-32 goes on with the next index
+	ErrInvalidChild = errors.New("derived child key is invalid")
+)
+
+// masterSeedKey is the HMAC key of the master key of BIP-32
+var masterSeedKey = []byte("Bitcoin seed")
+
+// curve returns the elliptic curve of the asymmetric algorithm
+func curve() elliptic.Curve {
+	switch asymAlgo {
+	case AsymAlgo_ECC_Secp256k1:
+		return secp.S256()
+	case AsymAlgo_SM2:
+		return sm2.P256Sm2()
+	}
+	return elliptic.P256()
+}
+
+// NewMasterKey returns the extended private key of the seed as the master key of BIP-32
+func NewMasterKey(seed []byte) ([]byte, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("wrong seed length %d", len(seed))
+	}
+	key, chainCode := hmacSHA512(masterSeedKey, seed)
+	k := new(big.Int).SetBytes(key)
+	if k.Sign() == 0 || k.Cmp(curve().Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	return append(key, chainCode...), nil
+}
+
+// ExtendedPublicKey returns the extended public key of the extended private key, it has the same chain code
+func ExtendedPublicKey(extPriv []byte) ([]byte, error) {
+	if len(extPriv) != ExtendedPrivLength {
+		return nil, ErrExtendedKey
+	}
+	x, y := curve().ScalarBaseMult(extPriv[:consts.PrivkeyLength])
+	return append(marshalPoint(x, y), extPriv[consts.PrivkeyLength:]...), nil
+}
+
+// DeriveChildPrivKey derives the child with index of the extended private key as CKDpriv of BIP-32.
+// The extended private key is the 32 bytes of the key followed by the chain code, the child has the same format
+func DeriveChildPrivKey(masterPriv []byte, index uint32) ([]byte, error) {
+	if len(masterPriv) != ExtendedPrivLength {
+		return nil, ErrExtendedKey
+	}
+	c := curve()
+	key, chainCode := masterPriv[:consts.PrivkeyLength], masterPriv[consts.PrivkeyLength:]
+	var data []byte
+	if index >= HardenedKeyStart {
+		data = append([]byte{0}, key...)
+	} else {
+		data = compressPoint(c.ScalarBaseMult(key))
+	}
+	il, ir := hmacSHA512(chainCode, binary.BigEndian.AppendUint32(data, index))
+	n := c.Params().N
+	child := new(big.Int).SetBytes(il)
+	if child.Cmp(n) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	child.Add(child, new(big.Int).SetBytes(key)).Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	return append(asymalgo.FillLeft(child.Bytes()), ir...), nil
+}
+
+// DeriveChildKey derives the child with index of the extended public key as CKDpub of BIP-32. The extended
+// public key is the 64 bytes of the key as it's kept in the keys table followed by the chain code, the
+// child has the same format. It's the public key of DeriveChildPrivKey of the same non-hardened index
+func DeriveChildKey(masterPub []byte, index uint32) ([]byte, error) {
+	if len(masterPub) != ExtendedPubLength {
+		return nil, ErrExtendedKey
+	}
+	if index >= HardenedKeyStart {
+		return nil, ErrHardenedPublic
+	}
+	c := curve()
+	key, chainCode := masterPub[:consts.PubkeySizeLength], masterPub[consts.PubkeySizeLength:]
+	x := new(big.Int).SetBytes(key[:consts.PrivkeyLength])
+	y := new(big.Int).SetBytes(key[consts.PrivkeyLength:])
+	if !c.IsOnCurve(x, y) {
+		return nil, ErrExtendedKey
+	}
+	il, ir := hmacSHA512(chainCode, binary.BigEndian.AppendUint32(compressPoint(x, y), index))
+	if new(big.Int).SetBytes(il).Cmp(c.Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+	cx, cy := c.ScalarBaseMult(il)
+	cx, cy = c.Add(cx, cy, x, y)
+	if cx.Sign() == 0 && cy.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	return append(marshalPoint(cx, cy), ir...), nil
+}
+
+func hmacSHA512(key, data []byte) (il, ir []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32:32], sum[32:]
+}
+
+// compressPoint returns the compressed point as serP of BIP-32
+func compressPoint(x, y *big.Int) []byte {
+	prefix := byte(2)
+	if y.Bit(0) == 1 {
+		prefix = 3
+	}
+	return append([]byte{prefix}, asymalgo.FillLeft(x.Bytes())...)
+}
+
+// marshalPoint returns the point as the public key of the keys table
+func marshalPoint(x, y *big.Int) []byte {
+	return append(asymalgo.FillLeft(x.Bytes()), asymalgo.FillLeft(y.Bytes())...)
+}
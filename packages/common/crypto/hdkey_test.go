@@ -0,0 +1,74 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// TestDeriveChildKey checks the derivation by the test vector 1 of BIP-32
+func TestDeriveChildKey(t *testing.T) {
+	defer func(a AsymAlgo) { asymAlgo = a }(asymAlgo)
+	asymAlgo = AsymAlgo_ECC_Secp256k1
+
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []struct {
+		index     uint32
+		priv      string
+		chainCode string
+	}{
+		{HardenedKeyStart, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			"47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{1, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			"2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+	} {
+		parentPub, err := ExtendedPublicKey(master)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, err := DeriveChildPrivKey(master, step.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(child); got != step.priv+step.chainCode {
+			t.Errorf("%d: expected %s%s, got %s", step.index, step.priv, step.chainCode, got)
+		}
+		childPub, err := ExtendedPublicKey(child)
+		if err != nil {
+			t.Fatal(err)
+		}
+		derived, err := DeriveChildKey(parentPub, step.index)
+		if step.index >= HardenedKeyStart {
+			if !errors.Is(err, ErrHardenedPublic) {
+				t.Errorf("%d: expected %v, got %v", step.index, ErrHardenedPublic, err)
+			}
+		} else if err != nil {
+			t.Error(err)
+		} else if !bytes.Equal(derived, childPub) {
+			t.Errorf("%d: expected public %x, got %x", step.index, childPub, derived)
+		}
+		master = child
+	}
+	if got := hex.EncodeToString(compressPoint(curve().ScalarBaseMult(master[:32]))); got !=
+		"03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c" {
+		t.Errorf("wrong public key of m/0H/1 %s", got)
+	}
+
+	if _, err = DeriveChildKey(master, 1); !errors.Is(err, ErrExtendedKey) {
+		t.Errorf("expected %v, got %v", ErrExtendedKey, err)
+	}
+	pub, _ := ExtendedPublicKey(master)
+	pub[10] ^= 1
+	if _, err = DeriveChildKey(pub, 1); !errors.Is(err, ErrExtendedKey) {
+		t.Errorf("expected %v for the point out of the curve, got %v", ErrExtendedKey, err)
+	}
+}
@@ -0,0 +1,326 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package crypto
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto/asymalgo"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	// CommitmentLength is the length of the compressed point of the Pedersen commitment
+	CommitmentLength = 33
+	// RangeProofBits is the number of the bits of the amount proved by the range proof
+	RangeProofBits = 80
+	// bitProofLength is the commitment of the bit followed by the challenges and the responses of the OR proof
+	bitProofLength = CommitmentLength + 4*32
+	// RangeProofLength is the length of the range proof of the amount
+	RangeProofLength = RangeProofBits * bitProofLength
+)
+
+var (
+	// ErrCommitment is returned if the value or the blinding factor is out of range or the commitment is the infinity
+	ErrCommitment = errors.New("wrong pedersen commitment")
+	// ErrRangeProof is returned if the value doesn't fit RangeProofBits
+	ErrRangeProof = errors.New("value is out of the range proof")
+)
+
+// domains of the hashes of the generator H and the challenges of the range proof
+var (
+	generatorDomain = []byte("IBAX Pedersen H")
+	rangeDomain     = []byte("IBAX Pedersen range")
+)
+
+// point is the point of the curve, nil x is the infinity
+type point struct {
+	x, y *big.Int
+}
+
+var (
+	generators   = make(map[string]point)
+	generatorsMu sync.Mutex
+)
+
+// generatorH returns the second generator of the commitments. Its discrete logarithm to the base G is unknown,
+// the point is found by hashing the domain with the counter until the hash is the x coordinate of the point
+func generatorH(c elliptic.Curve) point {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	if h, ok := generators[c.Params().Name]; ok {
+		return h
+	}
+	var counter [4]byte
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		sum := sha256.Sum256(append(append([]byte{}, generatorDomain...), counter[:]...))
+		if h, err := decodePoint(c, append([]byte{2}, sum[:]...)); err == nil {
+			generators[c.Params().Name] = h
+			return h
+		}
+	}
+}
+
+// PedersenCommit returns the compressed point r*G + v*H of the curve of the asymmetric algorithm,
+// where v is the value and r is the blinding factor
+func PedersenCommit(value, blindingFactor *big.Int) ([]byte, error) {
+	c := curve()
+	n := c.Params().N
+	if value == nil || blindingFactor == nil || value.Sign() < 0 || value.Cmp(n) >= 0 ||
+		blindingFactor.Sign() < 0 || blindingFactor.Cmp(n) >= 0 {
+		return nil, ErrCommitment
+	}
+	p := commit(c, value, blindingFactor)
+	if p.x == nil {
+		return nil, ErrCommitment
+	}
+	return compressPoint(p.x, p.y), nil
+}
+
+// VerifyPedersenBalance returns true if the sum of the input commitments is equal to the sum of the output
+// and the fee commitments, so the values balance if the blinding factors balance
+func VerifyPedersenBalance(inputs, outputs, fee [][]byte) bool {
+	c := curve()
+	sum := func(list ...[][]byte) (point, bool) {
+		var acc point
+		for _, commitments := range list {
+			for _, data := range commitments {
+				p, err := decodePoint(c, data)
+				if err != nil {
+					return acc, false
+				}
+				acc = addPoints(c, acc, p)
+			}
+		}
+		return acc, true
+	}
+	in, ok := sum(inputs)
+	if !ok {
+		return false
+	}
+	out, ok := sum(outputs, fee)
+	if !ok {
+		return false
+	}
+	return equalPoints(in, out)
+}
+
+// ProveRange returns the proof that the commitment of the value and the blinding factor commits to the value
+// in [0, 2^RangeProofBits). Every bit is committed separately with the OR proof that it's 0 or 1, the blinding
+// factors of the bits are chosen so the weighted sum of the bit commitments is the commitment of the value
+func ProveRange(value, blindingFactor *big.Int) ([]byte, error) {
+	commitment, err := PedersenCommit(value, blindingFactor)
+	if err != nil {
+		return nil, err
+	}
+	if value.BitLen() > RangeProofBits {
+		return nil, ErrRangeProof
+	}
+	c := curve()
+	n := c.Params().N
+	h := generatorH(c)
+	factors := make([]*big.Int, RangeProofBits)
+	first := new(big.Int).Set(blindingFactor)
+	for i := 1; i < RangeProofBits; i++ {
+		if factors[i], err = randScalar(n); err != nil {
+			return nil, err
+		}
+		weighted := new(big.Int).Lsh(factors[i], uint(i))
+		first.Sub(first, weighted)
+	}
+	factors[0] = first.Mod(first, n)
+
+	proof := make([]byte, 0, RangeProofLength)
+	for i := 0; i < RangeProofBits; i++ {
+		bit := int(value.Bit(i))
+		ci := commit(c, big.NewInt(int64(bit)), factors[i])
+		if ci.x == nil {
+			return nil, ErrCommitment
+		}
+		// the bit commitment is r*G for 0 and r*G + H for 1, the prover knows the logarithm of one of them
+		keys := [2]point{ci, addPoints(c, ci, negPoint(c, h))}
+		var e, s [2]*big.Int
+		var r [2]point
+		nonce, err := randScalar(n)
+		if err != nil {
+			return nil, err
+		}
+		r[bit] = scalarBaseMult(c, nonce)
+		other := 1 - bit
+		if e[other], err = randScalar(n); err != nil {
+			return nil, err
+		}
+		if s[other], err = randScalar(n); err != nil {
+			return nil, err
+		}
+		r[other] = schnorrCommitment(c, s[other], e[other], keys[other])
+		challenge := bitChallenge(c, commitment, i, ci, r[0], r[1])
+		e[bit] = challenge.Sub(challenge, e[other])
+		e[bit].Mod(e[bit], n)
+		s[bit] = new(big.Int).Mul(e[bit], factors[i])
+		s[bit].Add(s[bit], nonce).Mod(s[bit], n)
+		proof = append(proof, compressPoint(ci.x, ci.y)...)
+		for _, v := range []*big.Int{e[0], e[1], s[0], s[1]} {
+			proof = append(proof, asymalgo.FillLeft(v.Bytes())...)
+		}
+	}
+	return proof, nil
+}
+
+// VerifyRange returns true if the proof of ProveRange is valid for the commitment
+func VerifyRange(commitment, proof []byte) bool {
+	if len(proof) != RangeProofLength {
+		return false
+	}
+	c := curve()
+	n := c.Params().N
+	target, err := decodePoint(c, commitment)
+	if err != nil {
+		return false
+	}
+	h := negPoint(c, generatorH(c))
+	var acc point
+	for i := 0; i < RangeProofBits; i++ {
+		data := proof[i*bitProofLength : (i+1)*bitProofLength]
+		ci, err := decodePoint(c, data[:CommitmentLength])
+		if err != nil {
+			return false
+		}
+		var scalars [4]*big.Int
+		for j := range scalars {
+			offset := CommitmentLength + j*32
+			scalars[j] = new(big.Int).SetBytes(data[offset : offset+32])
+			if scalars[j].Cmp(n) >= 0 {
+				return false
+			}
+		}
+		keys := [2]point{ci, addPoints(c, ci, h)}
+		r0 := schnorrCommitment(c, scalars[2], scalars[0], keys[0])
+		r1 := schnorrCommitment(c, scalars[3], scalars[1], keys[1])
+		sum := new(big.Int).Add(scalars[0], scalars[1])
+		if sum.Mod(sum, n).Cmp(bitChallenge(c, commitment, i, ci, r0, r1)) != 0 {
+			return false
+		}
+		acc = addPoints(c, acc, scalarMult(c, ci, new(big.Int).Lsh(big.NewInt(1), uint(i))))
+	}
+	return equalPoints(acc, target)
+}
+
+// commit returns r*G + v*H
+func commit(c elliptic.Curve, value, blindingFactor *big.Int) point {
+	return addPoints(c, scalarBaseMult(c, blindingFactor), scalarMult(c, generatorH(c), value))
+}
+
+// schnorrCommitment returns s*G - e*P
+func schnorrCommitment(c elliptic.Curve, s, e *big.Int, p point) point {
+	return addPoints(c, scalarBaseMult(c, s), negPoint(c, scalarMult(c, p, e)))
+}
+
+func bitChallenge(c elliptic.Curve, commitment []byte, index int, points ...point) *big.Int {
+	hash := sha256.New()
+	hash.Write(rangeDomain)
+	hash.Write(commitment)
+	hash.Write(binary.BigEndian.AppendUint32(nil, uint32(index)))
+	for _, p := range points {
+		if p.x == nil {
+			hash.Write(make([]byte, CommitmentLength))
+			continue
+		}
+		hash.Write(compressPoint(p.x, p.y))
+	}
+	e := new(big.Int).SetBytes(hash.Sum(nil))
+	return e.Mod(e, c.Params().N)
+}
+
+func randScalar(n *big.Int) (*big.Int, error) {
+	return rand.Int(rand.Reader, n)
+}
+
+func scalarBaseMult(c elliptic.Curve, k *big.Int) point {
+	k = new(big.Int).Mod(k, c.Params().N)
+	if k.Sign() == 0 {
+		return point{}
+	}
+	x, y := c.ScalarBaseMult(k.Bytes())
+	return point{x, y}
+}
+
+func scalarMult(c elliptic.Curve, p point, k *big.Int) point {
+	k = new(big.Int).Mod(k, c.Params().N)
+	if p.x == nil || k.Sign() == 0 {
+		return point{}
+	}
+	x, y := c.ScalarMult(p.x, p.y, k.Bytes())
+	return point{x, y}
+}
+
+// addPoints adds the points, the infinity and the doubling are handled here as the curves differ in them
+func addPoints(c elliptic.Curve, a, b point) point {
+	if a.x == nil {
+		return b
+	}
+	if b.x == nil {
+		return a
+	}
+	if a.x.Cmp(b.x) == 0 {
+		if a.y.Cmp(b.y) != 0 {
+			return point{}
+		}
+		x, y := c.Double(a.x, a.y)
+		return point{x, y}
+	}
+	x, y := c.Add(a.x, a.y, b.x, b.y)
+	return point{x, y}
+}
+
+func negPoint(c elliptic.Curve, p point) point {
+	if p.x == nil {
+		return p
+	}
+	return point{p.x, new(big.Int).Sub(c.Params().P, p.y)}
+}
+
+func equalPoints(a, b point) bool {
+	if a.x == nil || b.x == nil {
+		return a.x == nil && b.x == nil
+	}
+	return a.x.Cmp(b.x) == 0 && a.y.Cmp(b.y) == 0
+}
+
+// decodePoint decodes the compressed point of compressPoint
+func decodePoint(c elliptic.Curve, data []byte) (point, error) {
+	if len(data) != CommitmentLength || data[0] != 2 && data[0] != 3 {
+		return point{}, ErrCommitment
+	}
+	params := c.Params()
+	x := new(big.Int).SetBytes(data[1:])
+	if x.Cmp(params.P) >= 0 {
+		return point{}, ErrCommitment
+	}
+	// y^2 = x^3 + a*x + b, a is 0 for secp256k1 and -3 for P-256 and SM2
+	rhs := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	if params.Name != secp.S256().Params().Name {
+		rhs.Sub(rhs, new(big.Int).Mul(x, big.NewInt(3)))
+	}
+	rhs.Add(rhs, params.B).Mod(rhs, params.P)
+	y := new(big.Int).ModSqrt(rhs, params.P)
+	if y == nil {
+		return point{}, ErrCommitment
+	}
+	if y.Bit(0) != uint(data[0]&1) {
+		y.Sub(params.P, y)
+	}
+	if !c.IsOnCurve(x, y) {
+		return point{}, ErrCommitment
+	}
+	return point{x, y}, nil
+}
@@ -0,0 +1,55 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPedersenBalance(t *testing.T) {
+	for _, algo := range []string{"ECC_P256", "ECC_Secp256k1", "SM2"} {
+		InitAsymAlgo(algo)
+		commit := func(v, r int64) []byte {
+			c, err := PedersenCommit(big.NewInt(v), big.NewInt(r))
+			if err != nil {
+				t.Fatal(algo, err)
+			}
+			return c
+		}
+		inputs := [][]byte{commit(70, 11), commit(30, 22)}
+		outputs := [][]byte{commit(60, 20), commit(35, 13)}
+		if !VerifyPedersenBalance(inputs, outputs, [][]byte{commit(5, 0)}) {
+			t.Errorf("%s: balanced commitments aren't verified", algo)
+		}
+		if VerifyPedersenBalance(inputs, outputs, [][]byte{commit(6, 0)}) {
+			t.Errorf("%s: unbalanced commitments are verified", algo)
+		}
+		if _, err := PedersenCommit(big.NewInt(0), big.NewInt(0)); err == nil {
+			t.Errorf("%s: infinity is committed", algo)
+		}
+
+		proof, err := ProveRange(big.NewInt(60), big.NewInt(20))
+		if err != nil {
+			t.Fatal(algo, err)
+		}
+		if !VerifyRange(outputs[0], proof) {
+			t.Errorf("%s: range proof isn't verified", algo)
+		}
+		if VerifyRange(outputs[1], proof) {
+			t.Errorf("%s: range proof of the other commitment is verified", algo)
+		}
+		proof[CommitmentLength+1] ^= 1
+		if VerifyRange(outputs[0], proof) {
+			t.Errorf("%s: broken range proof is verified", algo)
+		}
+		// the negative value wraps around the order of the curve
+		negative := new(big.Int).Sub(curve().Params().N, big.NewInt(5))
+		if _, err = ProveRange(negative, big.NewInt(1)); err != ErrRangeProof {
+			t.Errorf("%s: negative value is proved, %v", algo, err)
+		}
+	}
+	InitAsymAlgo("ECC_P256")
+}
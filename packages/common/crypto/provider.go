@@ -1,5 +1,8 @@
 package crypto
 
+// PublicKey is the binary public key of the asymmetric algorithm
+type PublicKey = []byte
+
 type AsymProvider interface {
 	GenKeyPair() ([]byte, []byte, error)
 	Sign(privateKey, hash []byte) ([]byte, error)
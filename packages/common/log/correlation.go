@@ -0,0 +1,56 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package log
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The fields which correlate the entries of one block and its transactions. The names are
+// stable so the lifecycle of the block can be filtered with a single field query
+const (
+	FieldBlockID   = "block_id"
+	FieldBlockHash = "block_hash"
+	FieldBlockMode = "block_mode"
+	FieldTxHash    = "tx_hash"
+	FieldContract  = "contract"
+	FieldEco       = "eco"
+)
+
+// The values of FieldBlockMode
+const (
+	BlockModeGeneration = "generation"
+	BlockModeValidation = "validation"
+)
+
+// The keys of the entry in the json output
+const (
+	FieldTime  = "time"
+	FieldLevel = "level"
+	FieldMsg   = "msg"
+)
+
+// NewJSONFormatter returns the formatter of the json output with the stable names of the fields
+func NewJSONFormatter() *logrus.JSONFormatter {
+	return &logrus.JSONFormatter{
+		TimestampFormat: time.RFC3339Nano,
+		FieldMap: logrus.FieldMap{
+			logrus.FieldKeyTime:  FieldTime,
+			logrus.FieldKeyLevel: FieldLevel,
+			logrus.FieldKeyMsg:   FieldMsg,
+		},
+	}
+}
+
+// BlockMode returns the value of FieldBlockMode
+func BlockMode(genBlock bool) string {
+	if genBlock {
+		return BlockModeGeneration
+	}
+	return BlockModeValidation
+}
@@ -48,7 +48,10 @@ func (hook ContextHook) Fire(entry *logrus.Entry) error {
 				entry.Data["file"] = path.Base(file)
 				entry.Data["func"] = path.Base(name)
 				entry.Data["line"] = line
-				entry.Data["time"] = time.Now().Format(time.RFC3339)
+				if conf.Config.Log.LogFormat != "json" {
+					// the json formatter writes the time of the entry itself
+					entry.Data["time"] = time.Now().Format(time.RFC3339)
+				}
 				if conf.Config.Log.LogLevel != "DEBUG" {
 					break
 				}
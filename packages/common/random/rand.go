@@ -12,11 +12,13 @@ import (
 )
 
 type Rand struct {
-	src *rand.Rand
+	src    *rand.Rand
+	beacon []byte
 }
 
+// BytesSeed reseeds the random by b, the beacon of the block goes before b
 func (r *Rand) BytesSeed(b []byte) *rand.Rand {
-	seed := crypto.CalcChecksum(b)
+	seed := crypto.CalcChecksum(append(append([]byte{}, r.beacon...), b...))
 	r.src.Seed(int64(seed))
 	return r.src
 }
@@ -27,6 +29,14 @@ func NewRand(seed int64) *Rand {
 	}
 }
 
+// NewBeaconRand returns the random whose seeds are prefixed by the random beacon of the block
+func NewBeaconRand(beacon []byte) *Rand {
+	return &Rand{
+		src:    rand.New(rand.NewSource(0)),
+		beacon: beacon,
+	}
+}
+
 func RandInt(min, max int) int {
 	if min >= max || min == 0 || max == 0 {
 		return max
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
@@ -164,6 +165,14 @@ func FillRuntimeKey() error {
 	return nil
 }
 
+// GetMaxFutureBlockAge returns how far the block time could be ahead of the local time
+func (c *GlobalConfig) GetMaxFutureBlockAge() time.Duration {
+	if c.LocalConf.MaxFutureBlockAge <= 0 {
+		return consts.MaxFutureBlockAge * time.Second
+	}
+	return time.Duration(c.LocalConf.MaxFutureBlockAge) * time.Second
+}
+
 // GetNodesAddr returns address of nodes
 func GetNodesAddr() []string {
 	return Config.BootNodes.NodesAddr[:]
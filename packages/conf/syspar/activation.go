@@ -0,0 +1,263 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
+)
+
+const (
+	// ConsensusSchedule is the activation schedule of the consensus parameters
+	ConsensusSchedule = `consensus_schedule`
+	// ActivationDelay is the number of blocks after the block with the changing of the
+	// consensus parameter when its new value becomes effective
+	ActivationDelay int64 = 10
+)
+
+// consensusParams are the parameters which are used for the block validation. Their new values
+// become effective at the activation height, so all nodes agree on the value for every block
+var consensusParams = map[string]bool{
+	MaxBlockSize:            true,
+	MaxBlockWeight:          true,
+	MaxTxRollbacks:          true,
+	MinBaseGasPrice:         true,
+	MaxBaseGasPrice:         true,
+	GapsBetweenBlocks:       true,
+	BlockFormatVersion:      true,
+	BlockSignatureThreshold: true,
+	BlockValidators:         true,
+	RandomBeacon:            true,
+	StateRoot:               true,
+	EventsBloom:             true,
+	StrictBlockTime:         true,
+	TxTypeOrder:             true,
+}
+
+var schedule = Schedule{}
+
+// Activation is the value of the consensus parameter which is effective since Height
+type Activation struct {
+	Height int64  `json:"height"`
+	Value  string `json:"value"`
+}
+
+// Schedule is the activations of the consensus parameters ordered by height
+type Schedule map[string][]Activation
+
+// IsConsensusParam returns true if the changing of the parameter is activated at the height
+func IsConsensusParam(name string) bool {
+	return consensusParams[name]
+}
+
+// ParseSchedule parses the value of consensus_schedule parameter
+func ParseSchedule(data string) (Schedule, error) {
+	s := Schedule{}
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Marshal returns the value of consensus_schedule parameter
+func (s Schedule) Marshal() (string, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// Activate schedules value of name which is changed in blockID to be effective since
+// blockID + ActivationDelay. prev is the value before the changing, it is used if name
+// hasn't been scheduled yet. The activations which have been superseded before blockID
+// are dropped, the pending activations at the same or greater height are replaced
+func (s Schedule) Activate(name, prev, value string, blockID int64) {
+	list := s[name]
+	if len(list) == 0 {
+		list = []Activation{{Height: 1, Value: prev}}
+	}
+	var start int
+	for i, a := range list {
+		if a.Height <= blockID {
+			start = i
+		}
+	}
+	height := blockID + ActivationDelay
+	updated := make([]Activation, 0, len(list)-start+1)
+	for _, a := range list[start:] {
+		if a.Height < height {
+			updated = append(updated, a)
+		}
+	}
+	if updated[len(updated)-1].Value == value {
+		s[name] = updated
+		return
+	}
+	s[name] = append(updated, Activation{Height: height, Value: value})
+}
+
+// ValueAt returns the value of name which is effective for blockID. It returns false if the
+// value isn't scheduled for blockID
+func (s Schedule) ValueAt(name string, blockID int64) (string, bool) {
+	a, ok := s.activationAt(name, blockID)
+	return a.Value, ok
+}
+
+// HeightAt returns the activation height of the value of name which is effective for blockID
+func (s Schedule) HeightAt(name string, blockID int64) int64 {
+	a, _ := s.activationAt(name, blockID)
+	return a.Height
+}
+
+func (s Schedule) activationAt(name string, blockID int64) (a Activation, ok bool) {
+	for _, item := range s[name] {
+		if item.Height > blockID {
+			break
+		}
+		a, ok = item, true
+	}
+	return
+}
+
+// updateSchedule reloads the schedule from the cache, the mutex must be locked
+func updateSchedule() error {
+	s, err := ParseSchedule(cache[ConsensusSchedule])
+	if err != nil {
+		return err
+	}
+	schedule = s
+	return nil
+}
+
+// GetSchedule returns the copy of the activation schedule
+func GetSchedule() Schedule {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	s := make(Schedule, len(schedule))
+	for name, list := range schedule {
+		s[name] = append([]Activation(nil), list...)
+	}
+	return s
+}
+
+// sysStringAt returns the value of the consensus parameter which is effective for blockID
+func sysStringAt(name string, blockID int64) string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if value, ok := schedule.ValueAt(name, blockID); ok {
+		return value
+	}
+	return cache[name]
+}
+
+// GetMaxBlockSizeAt returns max block size which is effective for the block
+func GetMaxBlockSizeAt(blockID int64) int64 {
+	return converter.StrToInt64(sysStringAt(MaxBlockSize, blockID))
+}
+
+// GetMaxBlockWeightAt returns max block weight which is effective for the block
+func GetMaxBlockWeightAt(blockID int64) int64 {
+	if weight := converter.StrToInt64(sysStringAt(MaxBlockWeight, blockID)); weight > 0 {
+		return weight
+	}
+	return DefaultMaxBlockWeight
+}
+
+// GetMaxTxRollbacksAt returns max count of the rollback entries of the transaction which is effective for the block
+func GetMaxTxRollbacksAt(blockID int64) int64 {
+	if limit := converter.StrToInt64(sysStringAt(MaxTxRollbacks, blockID)); limit > 0 {
+		return limit
+	}
+	return DefaultMaxTxRollbacks
+}
+
+// GetBlockFormatAt returns the format version of the binary of the block
+func GetBlockFormatAt(blockID int64) int64 {
+	return converter.StrToInt64(sysStringAt(BlockFormatVersion, blockID))
+}
+
+// GetBaseGasPriceRangeAt returns the range of the base gas price which is effective for the block,
+// max is zero if the fee market is disabled
+func GetBaseGasPriceRangeAt(blockID int64) (min, max int64) {
+	max = converter.StrToInt64(sysStringAt(MaxBaseGasPrice, blockID))
+	if max <= 0 {
+		return 0, 0
+	}
+	min = converter.StrToInt64(sysStringAt(MinBaseGasPrice, blockID))
+	if min <= 0 {
+		min = 1
+	}
+	if min > max {
+		min = max
+	}
+	return min, max
+}
+
+// GetMaxBlockSizeLimit returns the greatest max block size of the schedule and the current value.
+// It is used to check the size of the block before its id is known
+func GetMaxBlockSizeLimit() int64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	limit := converter.StrToInt64(cache[MaxBlockSize])
+	for _, a := range schedule[MaxBlockSize] {
+		if size := converter.StrToInt64(a.Value); size > limit {
+			limit = size
+		}
+	}
+	return limit
+}
+
+// IsRandomBeaconAt returns true if the block has the random beacon
+func IsRandomBeaconAt(blockID int64) bool {
+	par := sysStringAt(RandomBeacon, blockID)
+	return par == `1` || par == `true`
+}
+
+// IsStateRootAt returns true if the block has the state root
+func IsStateRootAt(blockID int64) bool {
+	par := sysStringAt(StateRoot, blockID)
+	return par == `1` || par == `true`
+}
+
+// IsEventsBloomAt returns true if the block has the bloom of the events
+func IsEventsBloomAt(blockID int64) bool {
+	par := sysStringAt(EventsBloom, blockID)
+	return par == `1` || par == `true`
+}
+
+// IsStrictBlockTimeAt returns true if the time of the block must be after the previous block and in the slot of its node
+func IsStrictBlockTimeAt(blockID int64) bool {
+	par := sysStringAt(StrictBlockTime, blockID)
+	return par == `1` || par == `true`
+}
+
+// IsTxTypeOrderAt returns true if the contract transactions of the block are executed before its utxo
+// transactions, otherwise they are executed in the order of the block
+func IsTxTypeOrderAt(blockID int64) bool {
+	par := sysStringAt(TxTypeOrder, blockID)
+	return par == `1` || par == `true`
+}
+
+// GetGapsBetweenBlocksAt returns gaps between blocks which are effective for the block
+func GetGapsBetweenBlocksAt(blockID int64) int64 {
+	return converter.StrToInt64(sysStringAt(GapsBetweenBlocks, blockID))
+}
+
+// GetGapsActivationHeight returns the activation height of gaps between blocks which are
+// effective for the block. It is zero if gaps between blocks haven't been changed
+func GetGapsActivationHeight(blockID int64) int64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return schedule.HeightAt(GapsBetweenBlocks, blockID)
+}
+
+// GetMaxBlockTimeDurationAt returns max block time duration which is effective for the block
+func GetMaxBlockTimeDurationAt(blockID int64) time.Duration {
+	return time.Millisecond*time.Duration(GetMaxBlockGenerationTime()) + time.Second*time.Duration(GetGapsBetweenBlocksAt(blockID))
+}
@@ -0,0 +1,190 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testNode emulates the platform parameters of the node, the rows are changed by the blocks
+type testNode struct {
+	rows map[string]string
+}
+
+func newTestNode() *testNode {
+	return &testNode{rows: map[string]string{MaxBlockSize: "100", ConsensusSchedule: "{}"}}
+}
+
+// updateParam plays the block with the changing of the consensus parameter like UpdatePlatformParam does
+func (n *testNode) updateParam(t *testing.T, name, value string, blockID int64) {
+	t.Helper()
+	s, err := ParseSchedule(n.rows[ConsensusSchedule])
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Activate(name, n.rows[name], value, blockID)
+	if n.rows[ConsensusSchedule], err = s.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	n.rows[name] = value
+}
+
+// sysUpdate loads the rows into the cache like SysUpdate does
+func (n *testNode) sysUpdate(t *testing.T) {
+	t.Helper()
+	mutex.Lock()
+	defer mutex.Unlock()
+	for name, value := range n.rows {
+		cache[name] = value
+	}
+	if err := updateSchedule(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (n *testNode) snapshot() map[string]string {
+	rows := make(map[string]string, len(n.rows))
+	for k, v := range n.rows {
+		rows[k] = v
+	}
+	return rows
+}
+
+// validSize returns true if the block of size is valid at blockID
+func (n *testNode) validSize(t *testing.T, blockID, size int64) bool {
+	n.sysUpdate(t)
+	return size <= GetMaxBlockSizeAt(blockID)
+}
+
+func TestActivationBoundary(t *testing.T) {
+	const (
+		updateBlock = 20
+		boundary    = updateBlock + ActivationDelay
+	)
+	t.Cleanup(func() {
+		mutex.Lock()
+		cache, schedule = map[string]string{}, Schedule{}
+		mutex.Unlock()
+	})
+
+	// the nodes play the update block at the different time, the second one validates the
+	// blocks after the boundary as soon as it has played the update block
+	first, second := newTestNode(), newTestNode()
+	beforeUpdate := first.snapshot()
+	first.updateParam(t, MaxBlockSize, "50", updateBlock)
+	if first.validSize(t, boundary-1, 80) != !first.validSize(t, boundary, 80) {
+		t.Fatalf("first node doesn't switch the value at the boundary")
+	}
+	second.updateParam(t, MaxBlockSize, "50", updateBlock)
+
+	for _, node := range []*testNode{first, second} {
+		for blockID := int64(updateBlock); blockID < boundary; blockID++ {
+			if !node.validSize(t, blockID, 80) {
+				t.Errorf("block %d before the boundary is rejected", blockID)
+			}
+		}
+		for blockID := int64(boundary); blockID < boundary+3; blockID++ {
+			if node.validSize(t, blockID, 80) || !node.validSize(t, blockID, 50) {
+				t.Errorf("block %d after the boundary uses the old value", blockID)
+			}
+		}
+	}
+	if !reflect.DeepEqual(first.rows, second.rows) {
+		t.Fatalf("nodes disagree on the schedule: %v %v", first.rows, second.rows)
+	}
+
+	// rollback of the blocks after the boundary keeps the activation
+	if first.validSize(t, boundary, 80) {
+		t.Errorf("the boundary is lost after the rollback to %d", boundary)
+	}
+	// rollback of the update block restores the rows so the new value is never activated
+	first.rows = beforeUpdate
+	if !first.validSize(t, boundary, 80) || !first.validSize(t, boundary+10, 100) {
+		t.Errorf("the old value isn't restored after the rollback across the boundary")
+	}
+	// the fork plays the update in the later block and moves the boundary
+	first.updateParam(t, MaxBlockSize, "50", updateBlock+5)
+	if !first.validSize(t, boundary, 80) || first.validSize(t, boundary+5, 80) {
+		t.Errorf("the boundary of the fork is wrong: %v", GetSchedule())
+	}
+}
+
+func TestScheduleActivate(t *testing.T) {
+	s := Schedule{}
+	s.Activate(MaxBlockSize, "100", "50", 20)
+	s.Activate(MaxBlockSize, "50", "70", 25)
+	expected := []Activation{{1, "100"}, {30, "50"}, {35, "70"}}
+	if !reflect.DeepEqual(s[MaxBlockSize], expected) {
+		t.Fatalf("pending activations: %v", s[MaxBlockSize])
+	}
+	for blockID, value := range map[int64]string{1: "100", 29: "100", 30: "50", 34: "50", 35: "70", 100: "70"} {
+		if got, _ := s.ValueAt(MaxBlockSize, blockID); got != value {
+			t.Errorf("value at %d: expected %s got %s", blockID, value, got)
+		}
+	}
+
+	// the superseded activations are dropped
+	s.Activate(MaxBlockSize, "70", "90", 40)
+	// the second changing in the same block replaces the pending activation
+	s.Activate(MaxBlockSize, "90", "60", 40)
+	expected = []Activation{{35, "70"}, {50, "60"}}
+	if !reflect.DeepEqual(s[MaxBlockSize], expected) {
+		t.Fatalf("after pruning: %v", s[MaxBlockSize])
+	}
+	if height := s.HeightAt(MaxBlockSize, 49); height != 35 {
+		t.Errorf("activation height at 49: %d", height)
+	}
+	// the changing in the next block is activated after the pending one
+	s.Activate(MaxBlockSize, "60", "70", 41)
+	s.Activate(MaxBlockSize, "70", "70", 42)
+	expected = []Activation{{35, "70"}, {50, "60"}, {51, "70"}}
+	if !reflect.DeepEqual(s[MaxBlockSize], expected) {
+		t.Errorf("next activation: %v", s[MaxBlockSize])
+	}
+	if _, ok := s.ValueAt(GapsBetweenBlocks, 10); ok {
+		t.Errorf("unscheduled parameter has the value")
+	}
+}
+
+func TestBaseGasPriceRange(t *testing.T) {
+	t.Cleanup(func() {
+		mutex.Lock()
+		cache, schedule = map[string]string{}, Schedule{}
+		mutex.Unlock()
+	})
+	node := newTestNode()
+	node.rows[MinBaseGasPrice], node.rows[MaxBaseGasPrice] = "100", "0"
+	node.updateParam(t, MaxBaseGasPrice, "5000", 10)
+	node.sysUpdate(t)
+	if min, max := GetBaseGasPriceRangeAt(10 + ActivationDelay - 1); min != 0 || max != 0 {
+		t.Errorf("the fee market is enabled before the boundary: %d-%d", min, max)
+	}
+	if min, max := GetBaseGasPriceRangeAt(10 + ActivationDelay); min != 100 || max != 5000 {
+		t.Errorf("wrong range after the boundary: %d-%d", min, max)
+	}
+	node.rows[MinBaseGasPrice] = "9000"
+	node.sysUpdate(t)
+	if min, max := GetBaseGasPriceRangeAt(10 + ActivationDelay); min != 5000 || max != 5000 {
+		t.Errorf("the minimum must be clamped by the maximum: %d-%d", min, max)
+	}
+}
+
+func TestStrictBlockTimeActivation(t *testing.T) {
+	const updateBlock = 40
+	t.Cleanup(func() {
+		mutex.Lock()
+		cache, schedule = map[string]string{}, Schedule{}
+		mutex.Unlock()
+	})
+	n := newTestNode()
+	n.rows[StrictBlockTime] = "0"
+	n.updateParam(t, StrictBlockTime, "1", updateBlock)
+	n.sysUpdate(t)
+	if IsStrictBlockTimeAt(updateBlock+ActivationDelay-1) || !IsStrictBlockTimeAt(updateBlock+ActivationDelay) {
+		t.Errorf("strict block time isn't activated at %d: %v", updateBlock+ActivationDelay, GetSchedule())
+	}
+}
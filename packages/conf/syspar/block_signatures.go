@@ -0,0 +1,62 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+)
+
+// GetBlockSignatureThresholdAt returns the number of the validator signatures which the block requires,
+// zero if the block is accepted with the signature of its node only
+func GetBlockSignatureThresholdAt(blockID int64) int64 {
+	if threshold := converter.StrToInt64(sysStringAt(BlockSignatureThreshold, blockID)); threshold > 0 {
+		return threshold
+	}
+	return 0
+}
+
+// GetBlockValidatorsAt returns the key ids of the validators which sign the block. All honor nodes are
+// the validators if block_validators is empty
+func GetBlockValidatorsAt(blockID int64) []int64 {
+	validators := make([]int64, 0)
+	seen := make(map[int64]bool)
+	for _, item := range strings.Split(sysStringAt(BlockValidators, blockID), ",") {
+		if keyID := converter.StrToInt64(strings.TrimSpace(item)); keyID != 0 && !seen[keyID] {
+			seen[keyID] = true
+			validators = append(validators, keyID)
+		}
+	}
+	if len(validators) > 0 {
+		return validators
+	}
+	for _, node := range GetNodes() {
+		validators = append(validators, ValidatorKeyID(node))
+	}
+	return validators
+}
+
+// ValidatorKeyID returns the key id of the honor node which identifies its signatures of the blocks,
+// it's the address of the current public key of the node
+func ValidatorKeyID(node HonorNode) int64 {
+	return crypto.Address(node.PublicKey)
+}
+
+// GetValidatorKeysAt returns the public keys of the honor nodes by their validator key ids which sign
+// the block blockID. The previous key of the rotated key is accepted up to its PreviousKeyUntil
+func GetValidatorKeysAt(blockID int64) map[int64][][]byte {
+	keys := make(map[int64][][]byte)
+	for _, node := range GetNodes() {
+		list := [][]byte{node.PublicKey}
+		if len(node.PreviousPublicKey) > 0 && blockID <= node.PreviousKeyUntil {
+			list = append(list, node.PreviousPublicKey)
+		}
+		keys[ValidatorKeyID(node)] = list
+	}
+	return keys
+}
@@ -35,6 +35,9 @@ type honorNodeJSON struct {
 	PublicKey  string      `json:"public_key"`
 	UnbanTime  json.Number `json:"unban_time,er"`
 	Stopped    bool        `json:"stopped"`
+	// the key before the rotation which signs the blocks up to PreviousKeyUntil
+	PreviousPublicKey string `json:"previous_public_key,omitempty"`
+	PreviousKeyUntil  int64  `json:"previous_key_until,omitempty"`
 }
 
 // HonorNode is storing honor node data
@@ -44,6 +47,9 @@ type HonorNode struct {
 	PublicKey  []byte
 	UnbanTime  time.Time
 	Stopped    bool
+
+	PreviousPublicKey []byte
+	PreviousKeyUntil  int64
 }
 
 // UnmarshalJSON is custom json unmarshaller
@@ -62,6 +68,13 @@ func (fn *HonorNode) UnmarshalJSON(b []byte) (err error) {
 		return err
 	}
 	fn.UnbanTime = time.Unix(converter.StrToInt64(data.UnbanTime.String()), 0)
+	if len(data.PreviousPublicKey) > 0 {
+		if fn.PreviousPublicKey, err = crypto.HexToPub(data.PreviousPublicKey); err != nil {
+			log.WithFields(log.Fields{"type": consts.ConversionError, "error": err, "value": data.PreviousPublicKey}).Error("converting honor nodes previous public key from hex")
+			return err
+		}
+		fn.PreviousKeyUntil = data.PreviousKeyUntil
+	}
 
 	if err = fn.Validate(); err != nil {
 		return err
@@ -77,6 +90,10 @@ func (fn *HonorNode) MarshalJSON() ([]byte, error) {
 		PublicKey:  crypto.PubToHex(fn.PublicKey),
 		UnbanTime:  json.Number(strconv.FormatInt(fn.UnbanTime.Unix(), 10)),
 	}
+	if len(fn.PreviousPublicKey) > 0 {
+		jfn.PreviousPublicKey = crypto.PubToHex(fn.PreviousPublicKey)
+		jfn.PreviousKeyUntil = fn.PreviousKeyUntil
+	}
 
 	data, err := json.Marshal(jfn)
 	if err != nil {
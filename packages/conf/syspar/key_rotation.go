@@ -0,0 +1,94 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+// ErrHonorNodeKey is returned if the rotated key isn't the key of the honor node
+var ErrHonorNodeKey = errors.New("key isn't the public key of honor node")
+
+// GetKeyRotationBlocks returns the number of the blocks after the key rotation which are accepted
+// with the signature of the previous key
+func GetKeyRotationBlocks() int64 {
+	if v := SysInt64(KeyRotationBlocks); v > 0 {
+		return v
+	}
+	return DefaultKeyRotationBlocks
+}
+
+// IsHonorNodeKey returns true if the public key is the key of the honor node, stopped or not
+func IsHonorNodeKey(publicKey []byte) bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	_, ok := nodes[hex.EncodeToString(publicKey)]
+	return ok
+}
+
+// GetNodePublicKeysAt returns the public keys of the node at position which sign the block blockID.
+// The previous key of the rotated key is accepted up to its PreviousKeyUntil
+func GetNodePublicKeysAt(position, blockID int64) ([][]byte, error) {
+	publicKey, err := GetNodePublicKeyByPosition(position)
+	if err != nil {
+		return nil, err
+	}
+	keys := [][]byte{publicKey}
+	if IsCandidateNodeMode() {
+		return keys, nil
+	}
+	node, err := GetNodeByPosition(position)
+	if err != nil {
+		return nil, err
+	}
+	if len(node.PreviousPublicKey) > 0 && blockID <= node.PreviousKeyUntil {
+		keys = append(keys, node.PreviousPublicKey)
+	}
+	return keys, nil
+}
+
+// RotateHonorNodeKey returns the value of honor_nodes where the public key oldKey is replaced with
+// newKey, oldKey is kept as the previous key up to the block until. The other fields are kept as is
+func RotateHonorNodeKey(value string, oldKey, newKey []byte, until int64) (string, error) {
+	var items []map[string]any
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&items); err != nil {
+		return "", fmt.Errorf("decoding honor nodes: %w", err)
+	}
+	found := false
+	for _, item := range items {
+		hexKey, _ := item["public_key"].(string)
+		publicKey, err := crypto.HexToPub(hexKey)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(publicKey, newKey) {
+			return "", fmt.Errorf("%w: new key is in use", ErrHonorNodeKey)
+		}
+		if bytes.Equal(publicKey, oldKey) {
+			item["public_key"] = crypto.PubToHex(newKey)
+			item["previous_public_key"] = crypto.PubToHex(oldKey)
+			item["previous_key_until"] = until
+			found = true
+		}
+	}
+	if !found {
+		return "", ErrHonorNodeKey
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
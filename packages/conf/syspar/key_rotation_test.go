@@ -0,0 +1,48 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+func TestRotateHonorNodeKey(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, publicKeyLength), bytes.Repeat([]byte{2}, publicKeyLength)
+	other := bytes.Repeat([]byte{3}, publicKeyLength)
+	value := `[{"tcp_address":"127.0.0.1:7078","api_address":"http://127.0.0.1:7079","public_key":"` + crypto.PubToHex(oldKey) +
+		`","unban_time":1700000000,"stopped":false},{"tcp_address":"127.0.0.2:7078","api_address":"http://127.0.0.2:7079","public_key":"` +
+		crypto.PubToHex(other) + `","unban_time":0,"stopped":true}]`
+	rotated, err := RotateHonorNodeKey(value, oldKey, newKey, 110)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []*HonorNode
+	if err = json.Unmarshal([]byte(rotated), &items); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(items[0].PublicKey, newKey) || !bytes.Equal(items[0].PreviousPublicKey, oldKey) || items[0].PreviousKeyUntil != 110 {
+		t.Errorf("wrong rotated node %+v", items[0])
+	}
+	if items[0].UnbanTime.Unix() != 1700000000 || !items[1].Stopped || len(items[1].PreviousPublicKey) != 0 {
+		t.Errorf("other fields are changed: %s", rotated)
+	}
+	if !strings.Contains(rotated, `"stopped":true`) {
+		t.Errorf("stopped is lost: %s", rotated)
+	}
+
+	if _, err = RotateHonorNodeKey(value, newKey, oldKey, 110); !errors.Is(err, ErrHonorNodeKey) {
+		t.Errorf("unknown key: got %v", err)
+	}
+	if _, err = RotateHonorNodeKey(value, oldKey, other, 110); !errors.Is(err, ErrHonorNodeKey) {
+		t.Errorf("key in use: got %v", err)
+	}
+}
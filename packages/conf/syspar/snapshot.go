@@ -0,0 +1,145 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrSnapshotSignature is returned by ImportSnapshot for the snapshot which isn't signed by the key
+	ErrSnapshotSignature = errors.New("wrong signature of syspar snapshot")
+	// ErrSnapshotOutdated is returned by ImportSnapshot for the snapshot before the last local block
+	ErrSnapshotOutdated = errors.New("syspar snapshot is older than the local chain")
+)
+
+// SnapshotParam is the platform parameter of the snapshot
+type SnapshotParam struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+}
+
+// Snapshot is the platform parameters at the block signed by the node
+type Snapshot struct {
+	BlockID   int64           `json:"block_id"`
+	Params    []SnapshotParam `json:"params"`
+	NodeKeyID int64           `json:"node_key_id"`
+	Timestamp int64           `json:"timestamp"`
+	Signature []byte          `json:"signature"`
+}
+
+// Digest returns SHA256(block_id || params || timestamp), the integers are 8 bytes big-endian and every
+// parameter is its id followed by its name, value and conditions prefixed by their lengths
+func (s *Snapshot) Digest() []byte {
+	buf := binary.BigEndian.AppendUint64(nil, uint64(s.BlockID))
+	for _, p := range s.Params {
+		buf = binary.BigEndian.AppendUint64(buf, uint64(p.ID))
+		for _, v := range []string{p.Name, p.Value, p.Conditions} {
+			buf = binary.BigEndian.AppendUint64(buf, uint64(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(s.Timestamp))
+	digest := sha256.Sum256(buf)
+	return digest[:]
+}
+
+// Verify checks that the snapshot is signed with publicKey of the node NodeKeyID
+func (s *Snapshot) Verify(publicKey []byte) error {
+	if crypto.Address(publicKey) != s.NodeKeyID {
+		return ErrSnapshotSignature
+	}
+	if ok, err := crypto.Verify(publicKey, s.Digest(), s.Signature); err != nil || !ok {
+		return ErrSnapshotSignature
+	}
+	return nil
+}
+
+// NewSnapshot signs the snapshot of the platform parameters at the block with signer
+func NewSnapshot(blockID int64, params []sqldb.PlatformParameter, signer keystore.Signer, timestamp int64) (*Snapshot, error) {
+	s := &Snapshot{
+		BlockID:   blockID,
+		Params:    make([]SnapshotParam, 0, len(params)),
+		NodeKeyID: crypto.Address(signer.PublicKey()),
+		Timestamp: timestamp,
+	}
+	for _, p := range params {
+		s.Params = append(s.Params, SnapshotParam{ID: p.ID, Name: p.Name, Value: p.Value, Conditions: p.Conditions})
+	}
+	var err error
+	if s.Signature, err = keystore.SignData(signer, s.Digest()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SignSnapshot returns the snapshot of the platform parameters at the last block of the local chain
+func SignSnapshot(signer keystore.Signer) (*Snapshot, error) {
+	blockID, params, err := sqldb.GetPlatformParametersSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return NewSnapshot(blockID, params, signer, time.Now().Unix())
+}
+
+// ImportSnapshot checks that the json of the snapshot is signed by the public key signedBy and replaces
+// the local platform parameters with its values. The block of the snapshot is recorded as the start of
+// the replay of the changes of the parameters, the snapshot before the last local block is rejected.
+// The caller decides whether signedBy is trusted
+func ImportSnapshot(data []byte, signedBy []byte) error {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling syspar snapshot")
+		return err
+	}
+	if err := s.Verify(signedBy); err != nil {
+		log.WithFields(log.Fields{"type": consts.CryptoError, "node_key_id": s.NodeKeyID}).Error("checking syspar snapshot signature")
+		return err
+	}
+	info := &sqldb.InfoBlock{}
+	found, err := info.Get()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return err
+	}
+	if found && info.BlockID > s.BlockID {
+		return fmt.Errorf("%w: block %d before %d", ErrSnapshotOutdated, s.BlockID, info.BlockID)
+	}
+	params := make([]sqldb.PlatformParameter, 0, len(s.Params))
+	for _, p := range s.Params {
+		params = append(params, sqldb.PlatformParameter{ID: p.ID, Name: p.Name, Value: p.Value, Conditions: p.Conditions})
+	}
+	snapshot := &sqldb.SysparSnapshot{BlockID: s.BlockID, NodeKeyID: s.NodeKeyID, ImportedAt: time.Now()}
+	if err = sqldb.ImportPlatformParameters(snapshot, params); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": s.BlockID}).Error("importing syspar snapshot")
+		return err
+	}
+	return SysUpdate(nil)
+}
+
+// GetSnapshotBlockID returns the block of the last imported snapshot, the changes of the platform
+// parameters are replayed since it. It's zero if no snapshot has been imported
+func GetSnapshotBlockID() (int64, error) {
+	s, found, err := sqldb.GetLastSysparSnapshot()
+	if err != nil || !found {
+		return 0, err
+	}
+	return s.BlockID, nil
+}
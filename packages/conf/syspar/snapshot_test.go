@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+func TestSnapshot(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, pub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keystore.NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := []sqldb.PlatformParameter{
+		{ID: 1, Name: MaxBlockSize, Value: "67108864", Conditions: `ContractAccess("@1UpdatePlatformParam")`},
+		{ID: 2, Name: MaxTxRollbacks, Value: "100000", Conditions: `ContractAccess("@1UpdatePlatformParam")`},
+	}
+	s, err := NewSnapshot(10, params, signer, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := &Snapshot{}
+	if err = json.Unmarshal(data, received); err != nil {
+		t.Fatal(err)
+	}
+	if err = received.Verify(pub); err != nil {
+		t.Fatalf("valid snapshot is rejected: %v", err)
+	}
+	if err = received.Verify(otherPub); !errors.Is(err, ErrSnapshotSignature) {
+		t.Errorf("snapshot is accepted with the wrong public key: %v", err)
+	}
+	received.Params[1].Value = "1"
+	if err = received.Verify(pub); !errors.Is(err, ErrSnapshotSignature) {
+		t.Errorf("snapshot with the changed value is accepted: %v", err)
+	}
+	received.Params[1].Value, received.Params[0].Name = "100000", MaxBlockSize+"x"
+	if err = received.Verify(pub); !errors.Is(err, ErrSnapshotSignature) {
+		t.Errorf("snapshot with the changed name is accepted: %v", err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/types"
 
@@ -39,14 +40,56 @@ const (
 	MaxBlockSize = `max_block_size`
 	// MaxTxSize is the maximum size of the transaction
 	MaxTxSize = `max_tx_size`
+	// MaxTxParams is the maximum count of the contract parameters in the transaction
+	MaxTxParams = `max_tx_params`
+	// MaxTxParamSize is the maximum size of the contract parameter in the transaction
+	MaxTxParamSize = `max_tx_param_size`
 	// MaxForsignSize is the maximum size of the forsign of transaction
 	MaxForsignSize = `max_forsign_size`
 	// MaxBlockFuel is the maximum fuel of the block
 	MaxBlockFuel = `max_fuel_block`
+	// MaxBlockWeight is the maximum weight of the transactions in the block
+	MaxBlockWeight = `max_block_weight`
+	// MinBaseGasPrice is the minimum base gas price of the block
+	MinBaseGasPrice = `min_base_gas_price`
+	// MaxBaseGasPrice is the maximum base gas price of the block, the fee market is disabled if it's zero
+	MaxBaseGasPrice = `max_base_gas_price`
 	// MaxTxFuel is the maximum fuel of the transaction
 	MaxTxFuel = `max_fuel_tx`
 	// MaxTxCount is the maximum count of the transactions
 	MaxTxCount = `max_tx_block`
+	// KeyRotationBlocks is the number of the blocks which the previous key of the rotated node key still signs
+	KeyRotationBlocks = `key_rotation_blocks`
+	// MaxTxRollbacks is the maximum count of the rollback entries of the transaction
+	MaxTxRollbacks = `max_tx_rollbacks`
+	// BlockFormatVersion is the format of the block binaries which are marshalled by the nodes
+	BlockFormatVersion = `block_format_version`
+	// BlockSignatureThreshold is the number of the validator signatures the block requires, zero disables it
+	BlockSignatureThreshold = `block_signature_threshold`
+	// BlockValidators is the comma separated key ids of the validators which sign the blocks
+	BlockValidators = `block_validators`
+	// HeartbeatBlocks is the number of the block intervals without blocks after which the empty block is generated
+	HeartbeatBlocks = `heartbeat_blocks`
+	// SavepointBatch is the number of the transactions of the block which are played after one savepoint
+	SavepointBatch = `savepoint_batch`
+	// CheckpointInterval is the number of the blocks between the checkpoints of the honor nodes, zero disables them
+	CheckpointInterval = `checkpoint_interval`
+	// RandomBeacon enables the commit-reveal random beacon of the blocks which seeds the random of the contracts
+	RandomBeacon = `random_beacon`
+	// BlockCompression enables the compression of the blocks which are stored and sent to the nodes
+	BlockCompression = `block_compression`
+	// StateRoot enables the merkle root of the rows which are changed by the block in the block header
+	StateRoot = `state_root`
+	// EventsBloom enables the bloom filter of the events of the block in the block header
+	EventsBloom = `events_bloom`
+	// MaxPastBlockAge is the time in seconds the new block time could be behind the local time of the node
+	MaxPastBlockAge = `max_past_block_age`
+	// StrictBlockTime enables the rejection of the block whose time isn't after the previous block or is out of the slot of its node
+	StrictBlockTime = `strict_block_time`
+	// TxTypeOrder enables the execution of the contract transactions of the block before its utxo transactions
+	TxTypeOrder = `tx_type_order`
+	// MinPoWBits is the leading zero bits of the proof of work of the block generated out of the slot of its node
+	MinPoWBits = `min_pow_bits`
 	// MaxBlockGenerationTime is the time limit for block generation (in ms)
 	MaxBlockGenerationTime = `max_block_generation_time`
 	// MaxColumns is the maximum columns in tables
@@ -79,9 +122,19 @@ const (
 	Test = `test`
 	// PrivateBlockchain is value defining blockchain mode
 	PrivateBlockchain = `private_blockchain`
+	// DeletedObjectsRetention is the seconds the soft-deleted application objects are kept before the purge
+	DeletedObjectsRetention = `deleted_objects_retention`
 
 	// CostDefault is the default maximum cost of F
 	CostDefault = int64(20000000)
+	// DefaultMaxBlockWeight is the maximum weight of the block if max_block_weight isn't set
+	DefaultMaxBlockWeight = int64(1000000)
+	// DefaultMaxTxRollbacks is the maximum count of the rollback entries of the transaction if max_tx_rollbacks isn't set
+	DefaultMaxTxRollbacks = int64(100000)
+	// DefaultKeyRotationBlocks is the transition period of the rotated node key if key_rotation_blocks isn't set
+	DefaultKeyRotationBlocks = int64(100)
+	// BaseGasPriceUnit is the base gas price which keeps the fuel rate of the ecosystems
+	BaseGasPriceUnit = int64(1000000)
 
 	PriceExec       = "price_exec_"
 	AccessExec      = "access_exec_"
@@ -102,32 +155,45 @@ var (
 	errNodeDisabled     = errors.New("node is disabled")
 	nodePubKey          []byte
 	nodePrivKey         []byte
+	nodeSigner          keystore.Signer
 	cacheTableColType   = make([]map[string]string, 0)
 	runModel            uint8
 )
 
+// ReadNodeKeys opens the keystore of the node private key from the configuration
 func ReadNodeKeys() (err error) {
-	var (
-		nprivkey []byte
-	)
-	nprivkey, err = os.ReadFile(filepath.Join(conf.Config.DirPathConf.KeysDir, consts.NodePrivateKeyFilename))
+	nodeSigner, err = newNodeSigner(conf.Config.Keystore)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("reading node private key from file")
+		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err, "keystore": conf.Config.Keystore.Type}).Error("opening node keystore")
 		return
 	}
-	nodePrivKey, err = hex.DecodeString(string(nprivkey))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.ConversionError, "error": err}).Error("decoding node private key from hex")
-		return
-	}
-	nodePubKey, err = crypto.PrivateToPublic(nodePrivKey)
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.CryptoError, "error": err}).Error("converting node private key to public")
-		return
+	nodePubKey = nodeSigner.PublicKey()
+	nodePrivKey = nil
+	if exporter, ok := nodeSigner.(keystore.KeyExporter); ok {
+		nodePrivKey = exporter.PrivateKey()
 	}
 	return
 }
 
+func newNodeSigner(c conf.KeystoreConfig) (keystore.Signer, error) {
+	switch c.Type {
+	case "", keystore.TypeFile:
+		return keystore.NewFileKeystore(filepath.Join(conf.Config.DirPathConf.KeysDir, consts.NodePrivateKeyFilename),
+			os.Getenv(c.PassphraseEnv))
+	case keystore.TypeEnv:
+		return keystore.NewEnvKeystore(c.EnvVar)
+	case keystore.TypeVault:
+		return keystore.NewVaultKeystore(keystore.VaultConfig{
+			Address: c.Vault.Address,
+			Token:   c.Vault.Token,
+			Mount:   c.Vault.Mount,
+			Key:     c.Vault.Key,
+			Timeout: time.Duration(c.Vault.Timeout) * time.Second,
+		})
+	}
+	return nil, fmt.Errorf("unknown keystore type %s", c.Type)
+}
+
 func GetSysParCache() map[string]string {
 	var cp = make(map[string]string, len(cache))
 	for k, v := range cache {
@@ -140,10 +206,16 @@ func GetNodePubKey() []byte {
 	return nodePubKey
 }
 
+// GetNodePrivKey returns the node private key. It is empty if the keystore doesn't export the key
 func GetNodePrivKey() []byte {
 	return nodePrivKey
 }
 
+// GetNodeSigner returns the signer of the node keystore
+func GetNodeSigner() keystore.Signer {
+	return nodeSigner
+}
+
 // SysUpdate reloads/updates values of platform parameters
 func SysUpdate(dbTx *sqldb.DbTransaction) error {
 	var err error
@@ -157,6 +229,14 @@ func SysUpdate(dbTx *sqldb.DbTransaction) error {
 	for _, param := range platformParameters {
 		cache[param.Name] = param.Value
 	}
+	if err = updateSchedule(); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling consensus schedule from json")
+		return err
+	}
+	if err = updateTxTypes(); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling registered tx types from json")
+		return err
+	}
 	if len(cache[HonorNodes]) > 0 {
 		if err = updateNodes(); err != nil {
 			return err
@@ -482,6 +562,27 @@ func GetMaxTxSize() int64 {
 	return converter.StrToInt64(SysString(MaxTxSize))
 }
 
+// GetMaxTxParams is returns max count of the contract parameters in tx
+func GetMaxTxParams() int64 {
+	if v := SysInt64(MaxTxParams); v > 0 {
+		return v
+	}
+	return consts.MaxTxParams
+}
+
+// GetMaxTxParamSize is returns max size of the contract parameter in tx, it is limited by max tx size by default
+func GetMaxTxParamSize() int64 {
+	if v := SysInt64(MaxTxParamSize); v > 0 {
+		return v
+	}
+	return GetMaxTxSize()
+}
+
+// GetDeletedObjectsRetention is returns the retention of the soft-deleted objects in seconds, 0 disables the purge
+func GetDeletedObjectsRetention() int64 {
+	return SysInt64(DeletedObjectsRetention)
+}
+
 // GetMaxTxTextSize is returns max tx text size
 func GetMaxForsignSize() int64 {
 	return converter.StrToInt64(SysString(MaxForsignSize))
@@ -511,6 +612,47 @@ func IsTestMode() bool {
 	return SysString(Test) == `true` || SysString(Test) == `1`
 }
 
+// GetHeartbeatBlocks returns the number of the block intervals without blocks after which the node generates
+// the empty block, zero disables the empty blocks
+func GetHeartbeatBlocks() int64 {
+	return converter.StrToInt64(SysString(HeartbeatBlocks))
+}
+
+// GetSavepointBatch returns the number of the transactions which are played after one savepoint, the batch
+// is played again with the savepoint of every transaction if one of them fails. 0 or 1 disables the batches
+func GetSavepointBatch() int {
+	return converter.StrToInt(SysString(SavepointBatch))
+}
+
+// GetMaxPastBlockAge returns how far the time of the new block could be behind the local time, the default
+// is consts.MaxPastBlockAge
+func GetMaxPastBlockAge() time.Duration {
+	age := converter.StrToInt64(SysString(MaxPastBlockAge))
+	if age <= 0 {
+		age = consts.MaxPastBlockAge
+	}
+	return time.Duration(age) * time.Second
+}
+
+// GetMinPoWBits returns the leading zero bits of the proof of work of the block which is generated out of
+// the slot of its node when the scheduled node has missed the slot, zero disables such blocks
+func GetMinPoWBits() int {
+	return converter.StrToInt(SysString(MinPoWBits))
+}
+
+// IsBlockCompression returns true if the node compresses the blocks of block_chain and the blocks which
+// it sends to the nodes
+func IsBlockCompression() bool {
+	par := SysString(BlockCompression)
+	return par == `1` || par == `true`
+}
+
+// GetCheckpointInterval returns the number of the blocks between the checkpoints which are signed by the
+// honor nodes, zero disables the checkpoints
+func GetCheckpointInterval() int64 {
+	return converter.StrToInt64(SysString(CheckpointInterval))
+}
+
 func GetIncorrectBlocksPerDay() int {
 	return converter.StrToInt(SysString(IncorrectBlocksPerDay))
 }
@@ -0,0 +1,59 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// RegisteredTxTypes is the JSON map of the custom transaction types which are accepted by
+// the network, {type_id: handler_description}
+const RegisteredTxTypes = `registered_tx_types`
+
+var txTypes = map[int]string{}
+
+// ParseTxTypes parses the value of registered_tx_types parameter
+func ParseTxTypes(data string) (map[int]string, error) {
+	list := make(map[int]string)
+	if len(data) == 0 {
+		return list, nil
+	}
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return nil, err
+	}
+	for key, desc := range raw {
+		id, err := strconv.Atoi(key)
+		if err != nil || !types.IsCustomTxType(id) {
+			return nil, fmt.Errorf("invalid custom transaction type %s, expected %d-%d",
+				key, types.CustomTxTypeMin, types.CustomTxTypeMax)
+		}
+		list[id] = desc
+	}
+	return list, nil
+}
+
+// updateTxTypes reloads the registered transaction types from the cache, the mutex must be locked
+func updateTxTypes() error {
+	list, err := ParseTxTypes(cache[RegisteredTxTypes])
+	if err != nil {
+		return err
+	}
+	txTypes = list
+	return nil
+}
+
+// GetRegisteredTxType returns the handler description of the custom transaction type
+func GetRegisteredTxType(id int) (string, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	desc, ok := txTypes[id]
+	return desc, ok
+}
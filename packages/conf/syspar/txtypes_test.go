@@ -0,0 +1,26 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package syspar
+
+import "testing"
+
+func TestParseTxTypes(t *testing.T) {
+	list, err := ParseTxTypes(`{"16": "escrow", "127": "oracle"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[16] != "escrow" || list[127] != "oracle" {
+		t.Errorf("wrong types %v", list)
+	}
+	if list, err = ParseTxTypes(""); err != nil || len(list) != 0 {
+		t.Errorf("empty value: %v %v", list, err)
+	}
+	for _, value := range []string{`{"3": "smart"}`, `{"128": "reset"}`, `{"x": ""}`, `[16]`} {
+		if _, err = ParseTxTypes(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
@@ -9,15 +9,20 @@ type (
 
 	// DBConfig database connection parameters
 	DBConfig struct {
-		Name            string
-		Host            string
-		Port            int
-		User            string
-		Password        string
-		LockTimeout     int // lock_timeout in milliseconds
-		IdleInTxTimeout int // postgres parameter idle_in_transaction_session_timeout
-		MaxIdleConns    int // sets the maximum number of connections in the idle connection pool
-		MaxOpenConns    int // sets the maximum number of open connections to the database
+		Name                   string
+		Host                   string
+		Port                   int
+		User                   string
+		Password               string
+		LockTimeout            int // lock_timeout in milliseconds
+		IdleInTxTimeout        int // postgres parameter idle_in_transaction_session_timeout
+		MaxIdleConns           int // sets the maximum number of connections in the idle connection pool
+		MaxOpenConns           int // sets the maximum number of open connections to the database
+		SlowStatementThreshold int // statements of the block transactions over it in milliseconds are logged, 0 disables
+		// MaxBinLogStatementBytes limits the statement of the binlog, the larger one is split or fails, 0 disables
+		MaxBinLogStatementBytes int
+		// ReadConns is the size of the pool of the read only connections of the API, 0 reads by the main pool
+		ReadConns int
 	}
 
 	//RedisConfig get redis information from config.yml
@@ -36,6 +41,13 @@ type (
 		Name string
 	}
 
+	// TracingConfig is the OTLP exporter of the block processing spans
+	TracingConfig struct {
+		Endpoint    string  // host:port of the OTLP/HTTP collector, the tracing is disabled if it's empty
+		Insecure    bool    // plain http instead of https
+		SampleRatio float64 // share of the traced blocks, all blocks are traced if it's zero
+	}
+
 	// CentrifugoConfig connection params
 	CentrifugoConfig struct {
 		Secret string
@@ -73,6 +85,9 @@ type (
 		BadTime int // control time period in minutes
 		BanTime int // ban time in minutes
 		BadTx   int // maximum bad tx during badTime minutes
+		MaxKeys int // maximum keys which are tracked, the keys without the ban are evicted first
+		// ReasonBadTx is the maximum bad tx of the reason during badTime minutes, it's lower than BadTx
+		ReasonBadTx map[string]int
 	}
 
 	TLSConfig struct {
@@ -99,12 +114,63 @@ type (
 		Hasher   string
 	}
 
+	// KeystoreConfig is the store of the node private key
+	KeystoreConfig struct {
+		Type          string // file|env|vault, the key is read from KeysDir/NodePrivateKey for file
+		EnvVar        string // environment variable with the hex node private key for env
+		PassphraseEnv string // environment variable with the passphrase of the encrypted key file for file
+		Vault         VaultConfig
+	}
+
+	// VaultConfig is the transit secrets engine of HashiCorp Vault which keeps the node key
+	VaultConfig struct {
+		Address string // e.g. https://127.0.0.1:8200
+		Token   string // VAULT_TOKEN environment variable is used if it's empty
+		Mount   string // path of the transit secrets engine
+		Key     string // name of ecdsa-p256 key
+		Timeout int    // request timeout in seconds
+	}
+
+	// HealthConfig is the thresholds of the node health check
+	HealthConfig struct {
+		MaxBlockAge       int64 // seconds, the last block is stale after it. Zero is 10 expected block gaps
+		MinPeers          int   // the node is degraded with less available peers
+		MaxQueueSize      int64 // the node is degraded with more pending transactions, zero disables the check
+		MaxReplicationLag int64 // seconds of the replication lag of the database replica
+		MinDiskFree       int64 // megabytes of the free space in the data directory
+	}
+
+	// KVHookConfig is the redis which receives the rows changed by the committed blocks
+	KVHookConfig struct {
+		Redis   bool
+		RedisDb int    // the database in the redis of the node, 0 and 1 are used by the node
+		Prefix  string // the prefix of the keys
+	}
+
+	// StrictBlockConfig is the generation of the blocks which never skips the bad transactions. The
+	// block with a bad transaction is aborted and assembled again without it
+	StrictBlockConfig struct {
+		Enabled bool
+		Retries int // the assemblies of the block after the first one, the slot is skipped after them
+	}
+
+	// DAConfig is the data availability layer which keeps the transactions of the blocks off-chain,
+	// block_chain keeps their commitments. It's disabled if Type is empty
+	DAConfig struct {
+		Type        string // file or blob
+		Path        string // the directory of the files
+		BlobRelayer string // the URL of the relayer which sends the blob transactions
+		BeaconNode  string // the URL of the beacon node which serves the blobs
+		Timeout     int    // the timeout of the requests of the blobs in seconds
+	}
+
 	//LocalConfig TODO: uncategorized
 	LocalConfig struct {
 		RunNodeMode           string
 		HTTPServerMaxBodySize int64
 		NetworkID             int64
 		MaxPageGenerationTime int64 // in milliseconds
+		MaxFutureBlockAge     int64 // in seconds
 	}
 	BlockSyncMethod struct {
 		Method string
@@ -115,6 +181,7 @@ type (
 		ConfigPath   string `toml:"-"`
 		TestRollBack bool   `toml:"-"`
 		FuncBench    bool   `toml:"-"`
+		SnapshotDir  string `toml:"-"` // the directory of the state snapshot which is imported on start
 		LocalConf    LocalConfig
 		DirPathConf  DirectoryConfig
 		BootNodes    BootstrapNodeConfig
@@ -128,11 +195,65 @@ type (
 		DB              DBConfig
 		Redis           RedisConfig
 		StatsD          StatsDConfig
+		Tracing         TracingConfig
 		Centrifugo      CentrifugoConfig
 		Log             LogConfig
 		TokenMovement   TokenMovementConfig
 		BanKey          BanKeyConfig
 		CryptoSettings  CryptoSettings
+		Keystore        KeystoreConfig
+		Health          HealthConfig
+		KVHook          KVHookConfig
+		StrictBlock     StrictBlockConfig
 		BlockSyncMethod BlockSyncMethod
+		DA              DAConfig
+		// RewardAddress is the cold account of the block rewards. It's checked against the on-chain
+		// registration made by the node key, the rewards aren't redirected by the config alone
+		RewardAddress string
+		// MMAPBlockStorePath is the directory of the memory-mapped copy of the blocks, it's disabled if empty
+		MMAPBlockStorePath string
+		// ContractStatsDays is the number of the days of the local contract statistics, zero disables them
+		ContractStatsDays int
+		// ResourceUsageDays is the number of the days of the resources consumed by the contracts, zero disables them
+		ResourceUsageDays int
+		// BlockResourcesDays is the number of the days of the resources of every played block, the older
+		// blocks are down-sampled to the hours, zero disables the resources of the blocks
+		BlockResourcesDays int
+		// BlockResourcesHourDays is the number of the days of the hourly resources of the blocks, the older
+		// hours are down-sampled to the days
+		BlockResourcesHourDays int
+		// PriorityInversionThresholdBlocks is the number of the blocks which the high-fee transaction may wait
+		// behind the low-fee transaction of its key before the warning, zero disables the check
+		PriorityInversionThresholdBlocks int
+		// MaxTxWatchSeconds is the time which the websocket of the status of the transaction waits for the change
+		MaxTxWatchSeconds int
+		// BlockChunkSize is the size of the chunks of the blocks which are downloaded by chunks, the smaller
+		// blocks are downloaded whole
+		BlockChunkSize int64
+		// BlockTracePath is the directory of the execution traces of the played blocks, it's disabled if empty
+		BlockTracePath string
+		// TxGroupWorkers is the maximum of the groups of the transfer self and utxo transactions which
+		// are played at once, zero is the number of the CPUs
+		TxGroupWorkers int
+		// SignWorkers is the number of the workers which verify the signatures of the contract transactions
+		// of the block before its play, zero is the number of the CPUs and the negative value disables them
+		SignWorkers int
+		// PreExecution plays the queued contract transactions while the node waits for its slot, the
+		// generated block drops the transactions which have failed on the state of the last block
+		PreExecution bool
+		// TxOrdering is the policy of the order of the queued transactions in the generated block: fifo, fee
+		// or fair, the other policies are registered by block.RegisterOrderingPolicy
+		TxOrdering string
+		// NotifyWorkers is the number of the workers which send the notifications of the committed blocks,
+		// zero sends them within the play of the block
+		NotifyWorkers int
+		// NotifyQueueSize is the number of the blocks and the clients which wait for the sending at most
+		NotifyQueueSize int
+		// Pruned deletes the transactions, the binlog and the rollback records of the blocks which are
+		// older than PruneRetentionBlocks, the headers of the blocks and the state are kept
+		Pruned bool
+		// PruneRetentionBlocks is the number of the last blocks which are kept whole by the pruned node,
+		// it's rollback_blocks_1 at least, so the blocks which may be rolled back aren't pruned
+		PruneRetentionBlocks int64
 	}
 )
@@ -41,6 +41,21 @@ const MaxTxForw = 600
 // MaxTxBack transaction may wander in the net for a day and then get into a block
 const MaxTxBack = 86400
 
+// MaxTxDepth is the maximum nesting depth of arrays and maps in the transaction data
+const MaxTxDepth = 16
+
+// MaxTxParams is the default maximum count of the contract parameters in the transaction
+const MaxTxParams = 256
+
+// MaxFutureBlockAge is the default value in seconds how far the block time could be in the future
+const MaxFutureBlockAge = 15
+
+// MaxPastBlockAge is the default value in seconds how far the block time could be in the past
+const MaxPastBlockAge = 30 * 60
+
+// BlockChunkSize is the default size of the chunks of the large blocks which are served over tcp
+const BlockChunkSize = 1 << 20
+
 // RoundFix is rounding constant
 const RoundFix = 0.00000000001
 
@@ -105,6 +120,18 @@ const (
 	// TxRequestExpire is expiration time for request of transaction
 	TxRequestExpire = 1 * time.Minute
 
+	// TxInventoryInterval is the interval of the batched announcements of transaction hashes
+	TxInventoryInterval = 200 * time.Millisecond
+
+	// TxInventoryMaxHashes is max count of hashes in one announcement
+	TxInventoryMaxHashes = 10000
+
+	// TxSeenTTL is how long the hashes of relayed transactions are remembered
+	TxSeenTTL = 10 * time.Minute
+
+	// TxSeenSize is max count of remembered hashes of relayed transactions
+	TxSeenSize = 100000
+
 	// DefaultCLB always is 1
 	DefaultCLB = 1
 
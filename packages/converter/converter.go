@@ -49,6 +49,10 @@ var FirstEcosystemTables = map[string]bool{
 	`buffer_data`:        true,
 	`app_params`:         true,
 	`views`:              true,
+	`identities`:         true,
+	`identity_events`:    true,
+	`time_locks`:         true,
+	`proposal_snapshots`: true,
 }
 
 func EncodeLenInt64(data *[]byte, x int64) *[]byte {
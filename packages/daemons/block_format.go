@@ -0,0 +1,50 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"sync"
+	"time"
+)
+
+// unknownFormatTimeout is the time when the host which has sent the block of the unknown format isn't
+// chosen to download the blocks, the blocks are downloaded from the nodes of the same version
+const unknownFormatTimeout = 10 * time.Minute
+
+// unknownFormatHosts are the hosts which have sent the blocks of the unknown format
+var unknownFormatHosts = struct {
+	sync.Mutex
+	until map[string]time.Time
+}{until: make(map[string]time.Time)}
+
+// skipUnknownFormatHost excludes the host from the choice of the host for unknownFormatTimeout
+func skipUnknownFormatHost(host string) {
+	unknownFormatHosts.Lock()
+	defer unknownFormatHosts.Unlock()
+	unknownFormatHosts.until[host] = time.Now().Add(unknownFormatTimeout)
+}
+
+// filterUnknownFormatHosts returns the hosts without the skipped ones. The hosts are returned as is
+// if all of them are skipped
+func filterUnknownFormatHosts(hosts []string) []string {
+	unknownFormatHosts.Lock()
+	defer unknownFormatHosts.Unlock()
+	now := time.Now()
+	filtered := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if until, ok := unknownFormatHosts.until[host]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(unknownFormatHosts.until, host)
+		}
+		filtered = append(filtered, host)
+	}
+	if len(filtered) == 0 {
+		return hosts
+	}
+	return filtered
+}
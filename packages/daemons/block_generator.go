@@ -8,6 +8,7 @@ package daemons
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"sync/atomic"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/protocols"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/statsd"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
@@ -62,8 +64,25 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	btc := protocols.NewBlockTimeCounter()
-	st := time.Now()
+	prevBlock := &sqldb.InfoBlock{}
+	_, err = prevBlock.Get()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
+		return err
+	}
+
+	btc, err := protocols.NewBlockTimeCounter(prevBlock.BlockID + 1)
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("calculating block time")
+		return err
+	}
+	// the time of the block is after the previous block even if the local clock has regressed
+	now := time.Now()
+	st := block.NextBlockTime(now, prevBlock.Time)
+	if st.Sub(now) > conf.Config.GetMaxFutureBlockAge() {
+		d.logger.WithFields(log.Fields{"type": consts.BlockError, "prev_time": prevBlock.Time}).Warn("local clock is behind the previous block")
+		return nil
+	}
 	if exists, err := btc.BlockForTimeExists(st, int(nodePosition)); exists || err != nil {
 		return nil
 	}
@@ -74,9 +93,23 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return err
 	}
 
+	// the block out of the slot of the node is generated with the proof of work when the scheduled
+	// node has missed the slot, the search is cancelled when the slot ends
+	var powCtx context.Context
+	powBits := syspar.GetMinPoWBits()
 	if !timeToGenerate {
-		d.logger.WithFields(log.Fields{"type": consts.JustWaiting}).Debug("not my generation time")
-		return nil
+		start, end, err := btc.RangeByTime(st)
+		if err != nil || !slotMissed(start, end, st, prevBlock.Time) || powBits <= 0 {
+			d.logger.WithFields(log.Fields{"type": consts.JustWaiting}).Debug("not my generation time")
+			if conf.Config.PreExecution {
+				preExecuteQueue(ctx, d.logger, prevBlock, st, nodePosition)
+			}
+			return nil
+		}
+		var cancel context.CancelFunc
+		powCtx, cancel = context.WithDeadline(ctx, end)
+		defer cancel()
+		d.logger.WithFields(log.Fields{"type": consts.SyncProcess, "block_id": prevBlock.BlockID + 1}).Info("generating block with proof of work, slot is missed")
 	}
 	//if !NtpDriftFlag {
 	//	d.logger.WithFields(log.Fields{"type": consts.Ntpdate}).Error("ntp time not ntpdate")
@@ -94,17 +127,12 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 	//	d.logger.WithFields(log.Fields{"type": consts.JustWaiting}).Debug("not my confirmation time")
 	//	return nil
 	//}
-	prevBlock := &sqldb.InfoBlock{}
-	_, err = prevBlock.Get()
-	if err != nil {
-		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
-		return err
-	}
+	checkRewardAddress(d.logger, prevBlock.BlockID+1)
 
 	NodePrivateKey, NodePublicKey := utils.GetNodeKeys()
-	if len(NodePrivateKey) < 1 {
-		d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		return errors.New(`node private key is empty`)
+	if syspar.GetNodeSigner() == nil {
+		d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node keystore is empty")
+		return errors.New(`node keystore is empty`)
 	}
 
 	dtx := DelayedTx{
@@ -119,16 +147,25 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	trs, classifyTxsMap, err := processTransactionsNew(d.logger, txs, st)
-	if err != nil {
-		return err
-	}
+	return assembleBlock(d.logger, txs, st, prevBlock.BlockID+1, prevBlock.Time, func(trs [][]byte, classifyTxsMap map[int][]*transaction.Transaction) error {
+		header, err := nextBlockHeader(prevBlock, st, nodePosition)
+		if err != nil {
+			return err
+		}
+		prev := prevBlockHeader(prevBlock)
+		if powCtx != nil {
+			return generateProvedBlock(powCtx, powBits, header, prev, trs, classifyTxsMap)
+		}
+		return generateProcessBlockNew(header, prev, trs, classifyTxsMap)
+	})
+}
 
-	// Block generation will be started only if we have transactions
-	if len(trs) == 0 {
-		return nil
+// nextBlockHeader returns the header of the block which the node generates after prevBlock at st
+func nextBlockHeader(prevBlock *sqldb.InfoBlock, st time.Time, nodePosition int64) (*types.BlockHeader, error) {
+	baseGasPrice, err := block.BaseGasPriceOf(prevBlock.BlockID + 1)
+	if err != nil {
+		return nil, err
 	}
-
 	header := &types.BlockHeader{
 		BlockId:       prevBlock.BlockID + 1,
 		Timestamp:     st.Unix(),
@@ -138,20 +175,55 @@ func BlockGenerator(ctx context.Context, d *daemon) error {
 		NodePosition:  nodePosition,
 		Version:       consts.BlockVersion,
 		ConsensusMode: consts.HonorNodeMode,
+		BaseGasPrice:  baseGasPrice,
+	}
+	if err = block.SetRandomBeacon(header); err != nil {
+		return nil, err
 	}
+	return header, nil
+}
 
-	prev := &types.BlockHeader{
+func prevBlockHeader(prevBlock *sqldb.InfoBlock) *types.BlockHeader {
+	return &types.BlockHeader{
 		BlockId:       prevBlock.BlockID,
 		BlockHash:     prevBlock.Hash,
 		RollbacksHash: prevBlock.RollbacksHash,
 	}
+}
 
-	err = generateProcessBlockNew(header, prev, trs, classifyTxsMap)
-	if err != nil {
-		return err
+// slotMissed returns true if there is no block in the slot from start to end after the half of the slot
+func slotMissed(start, end, st time.Time, prevTime int64) bool {
+	return prevTime < start.Unix() && !st.Before(start.Add(end.Sub(start)/2))
+}
+
+// assembleBlock selects the transactions of the block and generates it, the generation is skipped
+// without transactions unless the heartbeat block is due after prevTime. In the strict mode the block with a bad transaction is aborted, the
+// transaction is evicted and the block is assembled again up to StrictBlock.Retries times
+func assembleBlock(logger *log.Entry, txs []*sqldb.Transaction, st time.Time, blockID, prevTime int64,
+	generate func(trs [][]byte, classifyTxsMap map[int][]*transaction.Transaction) error) error {
+	evicted := make(map[string]bool)
+	for attempt := 0; ; attempt++ {
+		trs, classifyTxsMap, err := processTransactionsNew(logger, txs, st, blockID, evicted, nil)
+		if err != nil {
+			return err
+		}
+		// Block generation will be started only if we have transactions or the heartbeat block is due
+		if len(trs) == 0 && !block.HeartbeatDue(prevTime, st.Unix()) {
+			return nil
+		}
+		err = generate(trs, classifyTxsMap)
+		var badTx *block.BadTxError
+		if !errors.As(err, &badTx) {
+			return err
+		}
+		evicted[string(badTx.Hash)] = true
+		fields := log.Fields{"type": consts.BlockError, "block_id": blockID, "tx_hash": fmt.Sprintf("%x", badTx.Hash), "error": badTx.Err}
+		if attempt >= conf.Config.StrictBlock.Retries {
+			logger.WithFields(fields).Warn("block isn't generated, too many bad transactions")
+			return nil
+		}
+		logger.WithFields(fields).Info("assembling block again without bad transaction")
 	}
-	//go notificator.CheckTokenMovementLimits(nil, conf.Config.TokenMovement, header.BlockId)
-	return nil
 }
 
 func generateNextBlock(blockHeader, prevBlock *types.BlockHeader, trs [][]byte) ([]byte, error) {
@@ -161,11 +233,18 @@ func generateNextBlock(blockHeader, prevBlock *types.BlockHeader, trs [][]byte)
 		types.WithTxFullData(trs))
 }
 
-func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time.Time) ([][]byte, map[int][]*transaction.Transaction, error) {
+// processTransactionsNew returns the transactions of the block except for the evicted ones. The bad transactions
+// are marked and their keys are banned, they are passed to reject instead if it isn't nil
+func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time.Time, blockID int64, evicted map[string]bool,
+	reject func(tr *transaction.Transaction, err error)) ([][]byte, map[int][]*transaction.Transaction, error) {
 	classifyTxsMap := make(map[int][]*transaction.Transaction)
 	var done = make(<-chan time.Time, 1)
 	if syspar.IsHonorNodeMode() {
-		btc := protocols.NewBlockTimeCounter()
+		btc, err := protocols.NewBlockTimeCounter(blockID)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.TimeCalcError, "error": err}).Error("creating block time counter")
+			return nil, nil, err
+		}
 		_, endTime, err := btc.RangeByTime(st)
 		if err != nil {
 			log.WithFields(log.Fields{"type": consts.TimeCalcError, "error": err}).Error("on getting end time of generation")
@@ -179,7 +258,17 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 		return nil, nil, err
 	}
 
-	limits := transaction.NewLimits(transaction.GetLetPreprocess())
+	limits := transaction.NewLimits(transaction.GetLetPreprocess(), blockID)
+	// the transactions skipped by the limits and the spends of the reserved outputs stay in the queue for the next blocks
+	skipped := make(map[int64]int)
+	spends := make(transaction.Spends)
+	// the dry run of the block doesn't send the metrics
+	dryRun := reject != nil
+	defer func() {
+		if !dryRun {
+			keyTxsMetrics(classifyTxsMap, skipped)
+		}
+	}()
 
 	type badTxStruct struct {
 		hash  []byte
@@ -192,7 +281,7 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 
 		go func() {
 			for badTxItem := range ch {
-				transaction.BadTxForBan(badTxItem.keyID)
+				transaction.BadTxForBan(badTxItem.keyID, transaction.BanReasonQueue)
 				_ = transaction.MarkTransactionBad(badTxItem.hash, badTxItem.msg)
 			}
 		}()
@@ -200,15 +289,19 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 		return ch
 	}
 
-	txBadChan := processBadTx(nil)
-
-	defer func() {
-		close(txBadChan)
-	}()
+	if reject == nil {
+		txBadChan := processBadTx(nil)
+		defer func() {
+			close(txBadChan)
+		}()
+		reject = func(tr *transaction.Transaction, err error) {
+			txBadChan <- badTxStruct{hash: tr.Hash(), msg: err.Error(), keyID: tr.KeyID()}
+		}
+	}
 
 	// Checks preprocessing count limits
 	txList := make([][]byte, 0, len(trs))
-	txs = append(txs, trs...)
+	txs = append(txs, queueBuilder(logger).Order(trs)...)
 
 	allDelayedContract, err := sqldb.GetAllDelayedContract()
 	if err != nil {
@@ -231,13 +324,16 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 		tr, err := transaction.UnmarshallTransaction(bufTransaction, true)
 		if err != nil {
 			if tr != nil {
-				txBadChan <- badTxStruct{hash: tr.Hash(), msg: err.Error(), keyID: tr.KeyID()}
+				reject(tr, err)
 			}
 			continue
 		}
+		if evicted[string(tr.Hash())] {
+			continue
+		}
 
 		if err := tr.Check(st.Unix()); err != nil {
-			txBadChan <- badTxStruct{hash: tr.Hash(), msg: err.Error(), keyID: tr.KeyID()}
+			reject(tr, err)
 			continue
 		}
 		if txItem.GetTransactionRateStopNetwork() {
@@ -245,16 +341,31 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 			txList = append(txList[:0], txs[i].Data)
 			break
 		}
+		if tr.IsCustom() || tr.Type() == types.KeyRotationTxType || tr.Type() == types.BanLiftTxType {
+			classifyTxsMap[int(tr.Type())] = append(classifyTxsMap[int(tr.Type())], tr)
+			txList = append(txList, txs[i].Data)
+			continue
+		}
 		if tr.IsSmartContract() {
+			// the delayed transactions weigh 1 in the limits
+			tr.SmartContract().Delayed = tr.Type() == types.SmartContractTxType &&
+				utils.StringInSlice(contractNames, tr.SmartContract().TxContract.Name)
+			if spends.Reserved(tr.SmartContract()) {
+				skipped[tr.KeyID()]++
+				continue
+			}
 			err = limits.CheckLimit(tr.Inner)
 			if errors.Cause(err) == transaction.ErrLimitStop && i > 0 {
 				break
 			} else if err != nil {
 				if err != transaction.ErrLimitSkip {
-					txBadChan <- badTxStruct{hash: tr.Hash(), msg: err.Error(), keyID: tr.KeyID()}
+					reject(tr, err)
+				} else {
+					skipped[tr.KeyID()]++
 				}
 				continue
 			}
+			spends.Reserve(tr.SmartContract())
 			if tr.Type() == types.TransferSelfTxType {
 				classifyTxsMap[types.TransferSelfTxType] = append(classifyTxsMap[types.TransferSelfTxType], tr)
 				txList = append(txList, txs[i].Data)
@@ -266,7 +377,7 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 				continue
 			}
 
-			if utils.StringInSlice(contractNames, tr.SmartContract().TxContract.Name) {
+			if tr.SmartContract().Delayed {
 				classifyTxsMap[types.DelayTxType] = append(classifyTxsMap[types.DelayTxType], tr)
 				txList = append(txList, txs[i].Data)
 				continue
@@ -277,3 +388,44 @@ func processTransactionsNew(logger *log.Entry, txs []*sqldb.Transaction, st time
 	}
 	return txList, classifyTxsMap, nil
 }
+
+// queueBuilder returns the builder of the ordering policy of the node, the unknown policy is logged and the
+// transactions go by the fee
+func queueBuilder(logger *log.Entry) *block.BlockBuilder {
+	if len(conf.Config.TxOrdering) == 0 {
+		return block.NewBlockBuilder(nil)
+	}
+	policy, err := block.GetOrderingPolicy(conf.Config.TxOrdering)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.ConfigError, "error": err}).Error("getting transaction ordering policy")
+	}
+	return block.NewBlockBuilder(policy)
+}
+
+// keyTxsMetrics sends the max count of the transactions of one key in the block. The keys which reach
+// max_tx_block_per_user are sent with their counts and the skipped transactions, so the spam is detected
+func keyTxsMetrics(classified map[int][]*transaction.Transaction, skipped map[int64]int) {
+	if statsd.Client == nil {
+		return
+	}
+	var maxCount int
+	counts := make(map[int64]int)
+	for _, list := range classified {
+		for _, tr := range list {
+			counts[tr.KeyID()]++
+			if counts[tr.KeyID()] > maxCount {
+				maxCount = counts[tr.KeyID()]
+			}
+		}
+	}
+	statsd.Client.Gauge(statsd.KeyTxsMax, int64(maxCount), 1.0)
+	limit := syspar.GetMaxBlockUserTx()
+	for keyID, count := range counts {
+		if count >= limit {
+			statsd.Client.Gauge(statsd.KeyTxsCounterName(keyID), int64(count), 1.0)
+		}
+	}
+	for keyID, count := range skipped {
+		statsd.Client.Inc(statsd.SkippedKeyTxsCounterName(keyID), int64(count), 1.0)
+	}
+}
@@ -16,12 +16,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/IBAX-io/go-ibax/packages/block"
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
 	"github.com/IBAX-io/go-ibax/packages/types"
 	"github.com/IBAX-io/go-ibax/packages/utils"
 	log "github.com/sirupsen/logrus"
@@ -41,9 +43,9 @@ func BlockGeneratorCandidate(ctx context.Context, d *daemon) error {
 		return err
 	}
 	NodePrivateKey, NodePublicKey := utils.GetNodeKeys()
-	if len(NodePrivateKey) < 1 {
-		d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node private key is empty")
-		return errors.New(`node private key is empty`)
+	if syspar.GetNodeSigner() == nil {
+		d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node keystore is empty")
+		return errors.New(`node keystore is empty`)
 	}
 	if len(NodePublicKey) < 1 {
 		d.logger.WithFields(log.Fields{"type": consts.EmptyObject}).Error("node public key is empty")
@@ -73,54 +75,51 @@ func BlockGeneratorCandidate(ctx context.Context, d *daemon) error {
 		return err
 	}
 
-	trs, classifyTxsMap, err := processTransactionsNew(d.logger, txs, st)
-	if err != nil {
-		return err
-	}
-	// Block generation will be started only if we have transactions
-	if len(trs) == 0 {
-		return nil
-	}
-	lastBlockInterval := time.Unix(prevBlock.Time, 0)
-	timeDifference := st.Sub(lastBlockInterval)
-	if timeDifference <= syspar.GetMaxBlockTimeDuration() {
-		time.Sleep(syspar.GetMaxBlockTimeDuration() - timeDifference)
-		st = time.Now()
-	}
-
-	nodes := make([]types.BlockCandidateNode, len(candidateNodes))
-	for i, candidateNode := range candidateNodes {
-		bcn := types.BlockCandidateNode{
-			ID:         candidateNode.ID,
-			ReplyCount: candidateNode.ReplyCount,
+	return assembleBlock(d.logger, txs, st, prevBlock.BlockID+1, prevBlock.Time, func(trs [][]byte, classifyTxsMap map[int][]*transaction.Transaction) error {
+		lastBlockInterval := time.Unix(prevBlock.Time, 0)
+		timeDifference := st.Sub(lastBlockInterval)
+		if blockDuration := syspar.GetMaxBlockTimeDurationAt(prevBlock.BlockID + 1); timeDifference <= blockDuration {
+			time.Sleep(blockDuration - timeDifference)
+			st = time.Now()
 		}
-		nodes[i] = bcn
-	}
-	candidateNodesByte, _ := json.Marshal(nodes)
 
-	header := &types.BlockHeader{
-		BlockId:        prevBlock.BlockID + 1,
-		Timestamp:      st.Unix(),
-		EcosystemId:    0,
-		KeyId:          conf.Config.KeyID,
-		NetworkId:      conf.Config.LocalConf.NetworkID,
-		NodePosition:   currentCandidateNode.ID,
-		Version:        consts.BlockVersion,
-		ConsensusMode:  consts.CandidateNodeMode,
-		CandidateNodes: candidateNodesByte,
-	}
-	prev := &types.BlockHeader{
-		BlockId:       prevBlock.BlockID,
-		BlockHash:     prevBlock.Hash,
-		RollbacksHash: prevBlock.RollbacksHash,
-	}
+		nodes := make([]types.BlockCandidateNode, len(candidateNodes))
+		for i, candidateNode := range candidateNodes {
+			bcn := types.BlockCandidateNode{
+				ID:         candidateNode.ID,
+				ReplyCount: candidateNode.ReplyCount,
+			}
+			nodes[i] = bcn
+		}
+		candidateNodesByte, _ := json.Marshal(nodes)
 
-	err = generateProcessBlockNew(header, prev, trs, classifyTxsMap)
-	if err != nil {
-		return err
-	}
+		baseGasPrice, err := block.BaseGasPriceOf(prevBlock.BlockID + 1)
+		if err != nil {
+			return err
+		}
+		header := &types.BlockHeader{
+			BlockId:        prevBlock.BlockID + 1,
+			Timestamp:      st.Unix(),
+			EcosystemId:    0,
+			KeyId:          conf.Config.KeyID,
+			NetworkId:      conf.Config.LocalConf.NetworkID,
+			NodePosition:   currentCandidateNode.ID,
+			Version:        consts.BlockVersion,
+			ConsensusMode:  consts.CandidateNodeMode,
+			CandidateNodes: candidateNodesByte,
+			BaseGasPrice:   baseGasPrice,
+		}
+		if err = block.SetRandomBeacon(header); err != nil {
+			return err
+		}
+		prev := &types.BlockHeader{
+			BlockId:       prevBlock.BlockID,
+			BlockHash:     prevBlock.Hash,
+			RollbacksHash: prevBlock.RollbacksHash,
+		}
 
-	return nil
+		return generateProcessBlockNew(header, prev, trs, classifyTxsMap)
+	})
 }
 
 func GetThisNodePosition(candidateNodes sqldb.CandidateNodes, prevBlock *sqldb.InfoBlock) (sqldb.CandidateNode, bool) {
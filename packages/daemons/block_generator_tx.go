@@ -41,6 +41,11 @@ func (dtx *DelayedTx) RunForDelayBlockID(blockID int64) ([]*sqldb.Transaction, e
 		dtx.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting delayed contracts for block")
 		return nil, err
 	}
+	if len(contracts) > 0 && len(dtx.privateKey) == 0 {
+		// the delayed transactions are signed by the node key which isn't exported by the keystore
+		dtx.logger.WithFields(log.Fields{"type": consts.CryptoError, "block_id": blockID}).Warning("node private key is not available, delayed contracts are skipped")
+		return nil, nil
+	}
 	txList := make([]*sqldb.Transaction, 0, len(contracts))
 	for _, c := range contracts {
 		tx, err := dtx.createDelayTxByItem(c.Contract, c.KeyID, c.HighRate)
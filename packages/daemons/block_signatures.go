@@ -0,0 +1,106 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/network/tcpclient"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+// signaturesTimeout is the time of the collection of the signatures of the validators
+const signaturesTimeout = 3 * time.Second
+
+// sendSignBlock requests the signature of the block from the validator
+var sendSignBlock = tcpclient.SendSignBlock
+
+// collectBlockSignatures returns the binary of the generated block with the signatures of the validators.
+// The binary is kept if the block doesn't require the signatures. The node signs the block if it's the
+// validator, the other validators are requested in parallel until the threshold is reached
+func collectBlockSignatures(blockBin []byte) ([]byte, error) {
+	if !syspar.IsHonorNodeMode() {
+		return blockBin, nil
+	}
+	data := &types.BlockData{}
+	if err := data.UnmarshallBlock(blockBin); err != nil {
+		return nil, err
+	}
+	blockID := data.Header.BlockId
+	threshold := int(syspar.GetBlockSignatureThresholdAt(blockID))
+	if threshold <= 0 {
+		return blockBin, nil
+	}
+	b := &block.Block{BlockData: data}
+	logger := b.GetLogger()
+	signer := syspar.GetNodeSigner()
+	selfID, err := block.ValidatorKeyIDOf(blockID, signer.PublicKey())
+	if err == nil {
+		if err = b.AddSignature(signer); err != nil {
+			return nil, err
+		}
+	}
+
+	validators := syspar.GetBlockValidatorsAt(blockID)
+	allowed := make(map[int64]bool, len(validators))
+	for _, keyID := range validators {
+		allowed[keyID] = true
+	}
+	type result struct {
+		keyID int64
+		res   *network.BlockSignResponse
+		err   error
+	}
+	results := make(chan result, len(validators))
+	var requests int
+	for _, node := range syspar.GetNodes() {
+		keyID := syspar.ValidatorKeyID(node)
+		if !allowed[keyID] || node.Stopped || keyID == selfID {
+			continue
+		}
+		requests++
+		go func(addr string, keyID int64) {
+			res, err := sendSignBlock(addr, blockBin)
+			results <- result{keyID: keyID, res: res, err: err}
+		}(node.TCPAddress, keyID)
+	}
+
+	timeout := time.After(signaturesTimeout)
+collect:
+	for ; requests > 0 && len(b.Header.BlockSignatures) < threshold; requests-- {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				logger.WithFields(log.Fields{"type": consts.NetworkError, "error": r.err, "validator": r.keyID}).Warn("requesting block signature")
+				continue
+			}
+			sign := &types.BlockSignature{ValidatorKeyId: r.res.KeyID, Signature: r.res.Signature}
+			if sign.ValidatorKeyId != r.keyID {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject, "validator": r.keyID, "key_id": r.res.KeyID}).Warn("checking block signature")
+				continue
+			}
+			if err := b.CheckValidatorSignature(sign); err != nil {
+				logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err, "validator": r.keyID}).Warn("checking block signature")
+				continue
+			}
+			if err := b.SetSignature(sign); err != nil {
+				return nil, err
+			}
+		case <-timeout:
+			break collect
+		}
+	}
+	if err := b.VerifyThreshold(validators, threshold); err != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("collecting block signatures")
+		return nil, err
+	}
+	return types.WithBlockSignatures(blockBin, b.Header.BlockSignatures)
+}
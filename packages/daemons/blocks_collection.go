@@ -29,6 +29,12 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// playAttempts is the number of the attempts to play the block with the transient database failures
+	playAttempts   = 5
+	playRetryDelay = 200 * time.Millisecond
+)
+
 // BlocksCollection collects and parses blocks
 func BlocksCollection(ctx context.Context, d *daemon) error {
 	if ctx.Err() != nil {
@@ -79,11 +85,18 @@ func blocksCollection(ctx context.Context, d *daemon) (err error) {
 	}()
 
 	// update our chain till maxBlockID from the host
-	return UpdateChain(ctx, d, host, maxBlockID)
+	err = UpdateChain(ctx, d, host, maxBlockID, 0)
+	if errors.Is(err, block.ErrUnknownBlockVersion) {
+		// the host runs the newer version, the blocks are downloaded from the other host next time
+		d.logger.WithFields(log.Fields{"type": consts.BlockError, "host": host}).Warn("host sends blocks of unknown format")
+		skipUnknownFormatHost(host)
+	}
+	return err
 }
 
-// UpdateChain load from host all blocks from our last block to maxBlockID
-func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64) error {
+// UpdateChain load from host all blocks from our last block to maxBlockID. The block broadcastID is the one
+// which is announced to the node, only its time is checked against the past bound, 0 is no announced block
+func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID, broadcastID int64) error {
 	// get current block id from our blockchain
 	curBlock := &sqldb.InfoBlock{}
 	if _, err := curBlock.Get(); err != nil {
@@ -101,22 +114,43 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 		var err error
 		var bl *block.Block
 		defer func(err2 *error) {
-			if err2 != nil {
+			if err2 != nil && !block.IsRetryable(*err2) {
 				banNodePause(host, lastBlockID, lastBlockTime, *err2)
 			}
 		}(&err)
-		bl, err = block.ProcessBlockByBinData(rb, true)
-		if err != nil {
-			d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("processing block")
-			return err
-		}
-
 		curBlock := &sqldb.InfoBlock{}
 		if _, err = curBlock.Get(); err != nil {
 			d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("Getting info block")
 			return err
 		}
 
+		// the block of the other branch waits in the orphan pool until its branch is longer than the chain
+		var (
+			orphan *block.Orphan
+			known  bool
+		)
+		if orphan, known, err = orphanBlock(rb); err != nil || known {
+			return err
+		}
+		if orphan != nil {
+			block.Orphans.Add(orphan)
+			lastBlockID = orphan.ID
+			var applied bool
+			if applied, err = reorganize(ctx, d.logger, orphan); err != nil || applied {
+				return err
+			}
+			if orphan.ID > curBlock.BlockID+1 {
+				d.logger.WithFields(log.Fields{"block_id": orphan.ID, "orphans": block.Orphans.Len()}).Debug("block is kept in orphan pool")
+				return nil
+			}
+		}
+
+		bl, err = block.ProcessBlockByBinData(rb, true)
+		if err != nil {
+			d.logger.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Error("processing block")
+			return err
+		}
+
 		if curBlock.BlockID != bl.PrevHeader.BlockId {
 			d.logger.WithFields(log.Fields{"type": consts.BlockError}).Error("info block compare with previous block")
 			return fmt.Errorf("info block compare with previous block err curBlock: %d, PrevBlock: %d", curBlock.BlockID, bl.PrevHeader.BlockId)
@@ -124,8 +158,16 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 
 		lastBlockID = bl.Header.BlockId
 		lastBlockTime = bl.Header.Timestamp
+		if lastBlockID == broadcastID {
+			bl.Origin = block.OriginBroadcast
+		}
 
 		if err = bl.Check(); err != nil {
+			// the stale announcement isn't a fork, the block is downloaded by the sync later
+			if errors.Is(err, block.ErrBlockTimeInPast) {
+				d.logger.WithFields(log.Fields{"error": err, "from_host": host, "block_id": lastBlockID, "type": consts.BlockError}).Warn("skipping stale block")
+				return err
+			}
 			var replaceCount int64 = 1
 			if err == block.ErrIncorrectRollbackHash {
 				replaceCount++
@@ -142,7 +184,19 @@ func UpdateChain(ctx context.Context, d *daemon, host string, maxBlockID int64)
 			}
 			return err
 		}
-		return bl.PlaySafe()
+		// the failed block is decoded again, the played one has the state of the transactions
+		played := false
+		err = block.PlayRetry(ctx, playAttempts, playRetryDelay, func() error {
+			if played {
+				if bl, err = block.ProcessBlockByBinData(rb, true); err != nil {
+					return err
+				}
+				d.logger.WithFields(log.Fields{"block_id": lastBlockID, "type": consts.BlockError}).Warn("retrying block")
+			}
+			played = true
+			return bl.PlaySafe()
+		})
+		return err
 	}
 
 	var count int
@@ -214,10 +268,10 @@ func getHostWithMaxID(ctx context.Context, logger *log.Entry) (host string, maxB
 		logger.WithError(err).Error("on filtering banned hosts")
 	}
 
-	host, maxBlockID, err = tcpclient.HostWithMaxBlock(ctx, hosts)
+	host, maxBlockID, err = tcpclient.HostWithMaxBlock(ctx, filterUnknownFormatHosts(hosts))
 	if len(hosts) == 0 || err == tcpclient.ErrNodesUnavailable {
 		hosts = conf.GetNodesAddr()
-		return tcpclient.HostWithMaxBlock(ctx, hosts)
+		return tcpclient.HostWithMaxBlock(ctx, filterUnknownFormatHosts(hosts))
 	}
 
 	return
@@ -230,6 +284,15 @@ func ReplaceBlocksFromHost(ctx context.Context, host string, blockID, replaceCou
 	if err != nil {
 		return err
 	}
+
+	// get starting blockID from slice of blocks
+	if len(blocks) > 0 {
+		blockID = blocks[len(blocks)-1].Header.BlockId
+	}
+	if err = block.CheckFinalized(blockID - 1); err != nil {
+		log.WithFields(log.Fields{"type": consts.BlockError, "error": err, "host": host}).Error("replacing blocks from host")
+		return err
+	}
 	transaction.CleanCache()
 
 	// mark all transaction as unverified
@@ -242,11 +305,6 @@ func ReplaceBlocksFromHost(ctx context.Context, host string, blockID, replaceCou
 		return utils.ErrInfo(err)
 	}
 
-	// get starting blockID from slice of blocks
-	if len(blocks) > 0 {
-		blockID = blocks[len(blocks)-1].Header.BlockId
-	}
-
 	// we have the slice of blocks for applying
 	// first of all we should rollback old blocks
 	b := &sqldb.BlockChain{}
@@ -256,10 +314,13 @@ func ReplaceBlocksFromHost(ctx context.Context, host string, blockID, replaceCou
 		return utils.ErrInfo(err)
 	}
 	for _, b := range myRollbackBlocks {
-		err := rollback.RollbackBlock(b.Data)
+		data, err := b.BlockData()
 		if err != nil {
 			return utils.ErrInfo(err)
 		}
+		if err = rollback.RollbackBlock(data); err != nil {
+			return utils.ErrInfo(err)
+		}
 	}
 
 	script.SavepointSmartVMObjects()
@@ -0,0 +1,139 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/network/tcpclient"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
+)
+
+// checkpointsInterval is the pause between the runs of Checkpoints
+const checkpointsInterval = 10 * time.Second
+
+// sendSignCheckpoint requests the attestation of the checkpoint from the honor node
+var sendSignCheckpoint = tcpclient.SendSignCheckpoint
+
+// Checkpoints finalizes the last block at the multiple of checkpoint_interval, the checkpoint is saved when
+// more than two thirds of the honor nodes attest the block of the local chain. The chain isn't rolled back
+// below the last checkpoint
+func Checkpoints(ctx context.Context, d *daemon) error {
+	d.sleepTime = checkpointsInterval
+	interval := syspar.GetCheckpointInterval()
+	if interval <= 0 {
+		return nil
+	}
+	last := &sqldb.BlockChain{}
+	if _, err := last.GetMaxBlock(); err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return err
+	}
+	blockID := last.ID / interval * interval
+	if blockID == 0 {
+		return nil
+	}
+	cp, found, err := sqldb.GetLastCheckpoint()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting last checkpoint")
+		return err
+	}
+	if found && cp.BlockID >= blockID {
+		return nil
+	}
+	c, err := collectCheckpoint(ctx, blockID)
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": blockID}).Debug("collecting checkpoint")
+		return err
+	}
+	m, err := c.Model()
+	if err != nil {
+		return err
+	}
+	if err = m.Create(); err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("saving checkpoint")
+		return err
+	}
+	d.logger.WithFields(log.Fields{"block_id": blockID, "attestations": len(c.Attestations)}).Info("checkpoint finalized")
+	return nil
+}
+
+// collectCheckpoint returns the checkpoint of the block of the local chain with the attestations of the
+// honor nodes which have the same block, the honor node attests the block itself
+func collectCheckpoint(ctx context.Context, blockID int64) (*node.Checkpoint, error) {
+	bc := &sqldb.BlockChain{}
+	found, err := bc.Get(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, node.ErrBlockNotFound
+	}
+	c := &node.Checkpoint{BlockID: blockID, BlockHash: bc.Hash}
+	var selfID int64
+	if syspar.IsHonorNodeMode() {
+		signer := syspar.GetNodeSigner()
+		a, err := node.NewBlockAttestation(blockID, bc.Hash, signer, time.Now().Unix())
+		if err != nil {
+			return nil, err
+		}
+		selfID = a.NodeKeyID
+		c.Attestations = append(c.Attestations, a)
+	}
+
+	type result struct {
+		res *network.CheckpointSignResponse
+		err error
+	}
+	nodes := syspar.GetNodes()
+	results := make(chan result, len(nodes))
+	var requests int
+	for _, hn := range nodes {
+		if hn.Stopped || crypto.Address(hn.PublicKey) == selfID {
+			continue
+		}
+		requests++
+		go func(addr string) {
+			res, err := sendSignCheckpoint(addr, blockID)
+			results <- result{res: res, err: err}
+		}(hn.TCPAddress)
+	}
+
+	timeout := time.After(signaturesTimeout)
+collect:
+	for ; requests > 0; requests-- {
+		select {
+		case r := <-results:
+			if r.err != nil || !bytes.Equal(r.res.BlockHash, bc.Hash) {
+				continue
+			}
+			c.Attestations = append(c.Attestations, &node.BlockAttestation{
+				BlockID:   blockID,
+				BlockHash: r.res.BlockHash,
+				NodeKeyID: r.res.NodeKeyID,
+				Timestamp: r.res.Timestamp,
+				Signature: r.res.Signature,
+			})
+		case <-timeout:
+			break collect
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	keys := node.HonorNodeKeys()
+	if err = c.Verify(keys, node.CheckpointThreshold(len(keys))); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,48 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// cleanupInterval is the pause between the runs of Cleanup
+const cleanupInterval = 10 * time.Minute
+
+// Cleanup down-samples the local statistics of the node which are older than their retention
+func Cleanup(ctx context.Context, d *daemon) error {
+	d.sleepTime = cleanupInterval
+	return downsampleBlockResources(time.Now(), d.logger)
+}
+
+// downsampleBlockResources replaces the resources of the blocks older than BlockResourcesDays with
+// the hours and the hours older than BlockResourcesHourDays with the days
+func downsampleBlockResources(now time.Time, logger *log.Entry) error {
+	days := conf.Config.BlockResourcesDays
+	if days <= 0 {
+		return nil
+	}
+	if err := sqldb.DownsampleBlockResources(nil, now.AddDate(0, 0, -days),
+		sqldb.ResolutionBlock, sqldb.ResolutionHour); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("down-sampling block resources to hours")
+		return err
+	}
+	if hours := conf.Config.BlockResourcesHourDays; hours > 0 {
+		if err := sqldb.DownsampleBlockResources(nil, now.AddDate(0, 0, -days-hours),
+			sqldb.ResolutionHour, sqldb.ResolutionDay); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("down-sampling block resources to days")
+			return err
+		}
+	}
+	return nil
+}
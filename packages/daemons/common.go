@@ -51,6 +51,11 @@ var daemonsList = map[string]func(context.Context, *daemon) error{
 	"Confirmations":       Confirmations,
 	"Scheduler":           Scheduler,
 	"CandidateNodeVoting": CandidateNodeVoting,
+	"Oracle":              Oracle,
+	"Cleanup":             Cleanup,
+	"PriorityInversion":   PriorityInversion,
+	"Checkpoints":         Checkpoints,
+	"Pruning":             Pruning,
 	//"ExternalNetwork":   ExternalNetwork,
 }
 
@@ -185,6 +190,27 @@ func generateProcessBlockNew(blockHeader, prevBlock *types.BlockHeader, trs [][]
 	if err != nil {
 		return err
 	}
+	return insertGeneratedBlock(blockBin, blockHeader, classifyTxsMap)
+}
+
+// generateProvedBlock generates the block out of the slot of the node with the proof of work of minBits
+func generateProvedBlock(ctx context.Context, minBits int, blockHeader, prevBlock *types.BlockHeader, trs [][]byte, classifyTxsMap map[int][]*transaction.Transaction) error {
+	blockBin, err := block.MarshallProvedBlock(ctx, minBits,
+		types.WithCurHeader(blockHeader),
+		types.WithPrevHeader(prevBlock),
+		types.WithTxFullData(trs))
+	if err != nil {
+		return err
+	}
+	return insertGeneratedBlock(blockBin, blockHeader, classifyTxsMap)
+}
+
+func insertGeneratedBlock(blockBin []byte, blockHeader *types.BlockHeader, classifyTxsMap map[int][]*transaction.Transaction) error {
+	blockBin, err := collectBlockSignatures(blockBin)
+	if err != nil {
+		log.WithError(err).Error("on collecting block signatures")
+		return err
+	}
 	//err = block.InsertBlockWOForks(blockBin, true, false)
 	err = block.InsertBlockWOForksNew(blockBin, classifyTxsMap, true, false)
 	if err != nil {
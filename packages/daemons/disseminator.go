@@ -7,6 +7,7 @@ package daemons
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	"github.com/IBAX-io/go-ibax/packages/network/tcpclient"
@@ -19,6 +20,11 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	txAnnouncer     = tcpclient.NewTxAnnouncer(consts.TxInventoryInterval)
+	txAnnouncerOnce sync.Once
+)
+
 // Disseminator is send to all nodes from nodes_connections the following data
 // if we are honor node: sends blocks and transactions hashes
 // else send the full transactions
@@ -28,6 +34,9 @@ func Disseminator(ctx context.Context, d *daemon) error {
 	} else {
 		return nil
 	}
+	txAnnouncerOnce.Do(func() {
+		go txAnnouncer.Run(ctx)
+	})
 	DBLock()
 	defer DBUnlock()
 
@@ -79,10 +88,8 @@ func sendTransactions(ctx context.Context, logger *log.Entry) error {
 		}
 	}
 
-	if err := tcpclient.SendTransacitionsToAll(ctx, hosts, *trs); err != nil {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending transactions")
-		return err
-	}
+	// the hashes are announced in the batches, the hosts request the unknown transactions
+	txAnnouncer.Add(hosts, *trs)
 
 	if len(hosts) > 0 {
 		// set all transactions as sent
@@ -0,0 +1,87 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	log "github.com/sirupsen/logrus"
+)
+
+// BlockAssembler runs the dry run of the block of the node
+type BlockAssembler struct{}
+
+// DryRunBlock implements api.BlockAssembler
+func (BlockAssembler) DryRunBlock(ctx context.Context) (*block.DryRunReport, error) {
+	return DryRunBlock(ctx)
+}
+
+// DryRunBlock assembles the block which the node would generate now from the queue and plays it like
+// the generated block, the block isn't inserted and the queue isn't changed. The delayed contracts aren't
+// included, they are signed by the node at its slot
+func DryRunBlock(ctx context.Context) (*block.DryRunReport, error) {
+	DBLock()
+	defer DBUnlock()
+	logger := log.WithFields(log.Fields{"type": consts.BlockError, "dry_run": true})
+
+	prevBlock := &sqldb.InfoBlock{}
+	if _, err := prevBlock.Get(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting previous block")
+		return nil, err
+	}
+	// the node which isn't the honor node assembles the block at the first position
+	nodePosition, err := syspar.GetThisNodePosition()
+	if err != nil {
+		nodePosition = 0
+	}
+	st := block.NextBlockTime(time.Now(), prevBlock.Time)
+	header, err := nextBlockHeader(prevBlock, st, nodePosition)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := sqldb.GetAllUnusedTransactions(nil, syspar.GetMaxTxCount())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all unused transactions")
+		return nil, err
+	}
+
+	report := block.NewDryRunReport(header.BlockId)
+	report.Queued = len(queue)
+	trs, classifyTxsMap, err := processTransactionsNew(logger, nil, st, header.BlockId, nil, func(tr *transaction.Transaction, err error) {
+		report.Reject(tr.Hash(), tr.KeyID(), err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[string]bool, len(trs))
+	for _, data := range trs {
+		selected[string(data)] = true
+	}
+	for _, item := range queue {
+		if !selected[string(item.Data)] && !report.IsRejected(item.Hash) {
+			report.Skip(item.Hash, item.KeyID)
+		}
+	}
+
+	err = block.DryRun(ctx, header, prevBlockHeader(prevBlock), classifyTxsMap, report)
+	var badTx *block.BadTxError
+	if errors.As(err, &badTx) {
+		// the strict block is rejected by the bad transaction, the generator assembles it again without it
+		report.Error = err.Error()
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
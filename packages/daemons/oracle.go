@@ -0,0 +1,30 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/service/oracle"
+)
+
+// Oracle sends the answers of this honor node to the pending oracle requests
+func Oracle(ctx context.Context, d *daemon) error {
+	if atomic.CompareAndSwapUint32(&d.atomic, 0, 1) {
+		defer atomic.StoreUint32(&d.atomic, 0)
+	} else {
+		return nil
+	}
+	d.sleepTime = 2 * time.Second
+	if node.IsNodePaused() || !syspar.IsHonorNodeMode() {
+		return nil
+	}
+	return oracle.GetService().Process(ctx)
+}
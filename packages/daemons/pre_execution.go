@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	log "github.com/sirupsen/logrus"
+)
+
+// preExecuteQueue plays the queued transactions on the state of the last block while the node waits for
+// its slot, it takes the max generation time of the block at most. The failures are logged only, the
+// generated block handles the transactions
+func preExecuteQueue(ctx context.Context, logger *log.Entry, prevBlock *sqldb.InfoBlock, st time.Time, nodePosition int64) {
+	header, err := nextBlockHeader(prevBlock, st, nodePosition)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("getting header of pre-execution")
+		return
+	}
+	queue, err := sqldb.GetAllUnusedTransactions(nil, syspar.GetMaxTxCount())
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting all unused transactions")
+		return
+	}
+	txs := make([]*transaction.Transaction, 0, len(queue))
+	for _, item := range queue {
+		tr, err := transaction.UnmarshallTransaction(bytes.NewBuffer(item.Data), true)
+		if err != nil || tr.Check(st.Unix()) != nil {
+			continue
+		}
+		txs = append(txs, tr)
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*time.Duration(syspar.GetMaxBlockGenerationTime()))
+	defer cancel()
+	played, err := block.PreExecute(ctx, header, prevBlockHeader(prevBlock), txs)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error("pre-executing transactions")
+		return
+	}
+	if played > 0 {
+		logger.WithFields(log.Fields{"block_id": header.BlockId, "txs": played}).Debug("transactions are pre-executed")
+	}
+}
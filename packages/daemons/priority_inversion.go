@@ -0,0 +1,128 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"context"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// priorityInversionPercentile is the percentile of the fees of the queue over which the transaction is high-fee
+const priorityInversionPercentile = 0.9
+
+// inversionMonitor keeps the blocks where the transactions of the queue have been seen first
+type inversionMonitor struct {
+	blockID  int64
+	seen     map[string]int64
+	reported map[string]bool
+}
+
+var priorityInversions = &inversionMonitor{seen: make(map[string]int64), reported: make(map[string]bool)}
+
+// PriorityInversion logs the high-fee transactions of the queue which wait behind the low-fee
+// transactions of the same key for more than PriorityInversionThresholdBlocks blocks
+func PriorityInversion(ctx context.Context, d *daemon) error {
+	d.sleepTime = time.Second
+	threshold := int64(conf.Config.PriorityInversionThresholdBlocks)
+	if threshold <= 0 {
+		d.sleepTime = time.Minute
+		return nil
+	}
+	info := &sqldb.InfoBlock{}
+	found, err := info.Get()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block")
+		return err
+	}
+	if !found || info.BlockID == priorityInversions.blockID {
+		return nil
+	}
+	txs, err := sqldb.GetAllUnusedTransactions(nil, 0)
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting unused transactions")
+		return err
+	}
+	for _, inv := range priorityInversions.check(info.BlockID, txs, threshold) {
+		d.logger.WithFields(log.Fields{
+			"type":         consts.BlockError,
+			"tx_hash":      hex.EncodeToString(inv.tx.Hash),
+			"fee":          inv.tx.Expedite.String(),
+			"key_id":       inv.tx.KeyID,
+			"waited":       inv.waited,
+			"blocking_tx":  hex.EncodeToString(inv.blocking.Hash),
+			"blocking_fee": inv.blocking.Expedite.String(),
+		}).Warning("high-fee transaction waits behind low-fee transaction")
+		if statsd.Client != nil {
+			statsd.Client.Inc(statsd.PriorityInversions, 1, 1.0)
+		}
+	}
+	return nil
+}
+
+type priorityInversion struct {
+	tx, blocking *sqldb.Transaction
+	waited       int64
+}
+
+// check returns the new inversions of the queue at the block. The high-fee transaction is over the
+// percentile of the fees of the queue, it's blocked by the earliest transaction of its key with the lower fee
+func (m *inversionMonitor) check(blockID int64, txs []*sqldb.Transaction, threshold int64) []priorityInversion {
+	m.blockID = blockID
+	queued := make(map[string]bool, len(txs))
+	byKey := make(map[int64][]*sqldb.Transaction)
+	fees := make([]decimal.Decimal, 0, len(txs))
+	for _, tx := range txs {
+		hash := string(tx.Hash)
+		queued[hash] = true
+		if _, ok := m.seen[hash]; !ok {
+			m.seen[hash] = blockID
+		}
+		byKey[tx.KeyID] = append(byKey[tx.KeyID], tx)
+		fees = append(fees, tx.Expedite)
+	}
+	for hash := range m.seen {
+		if !queued[hash] {
+			delete(m.seen, hash)
+			delete(m.reported, hash)
+		}
+	}
+	if len(fees) == 0 {
+		return nil
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i].LessThan(fees[j]) })
+	percentile := fees[int(float64(len(fees)-1)*priorityInversionPercentile)]
+
+	var list []priorityInversion
+	for _, tx := range txs {
+		hash := string(tx.Hash)
+		waited := blockID - m.seen[hash]
+		if m.reported[hash] || waited <= threshold || !tx.Expedite.GreaterThan(percentile) {
+			continue
+		}
+		var blocking *sqldb.Transaction
+		for _, other := range byKey[tx.KeyID] {
+			if other.Expedite.LessThan(tx.Expedite) && other.Time <= tx.Time &&
+				(blocking == nil || other.Time < blocking.Time) {
+				blocking = other
+			}
+		}
+		if blocking != nil {
+			m.reported[hash] = true
+			list = append(list, priorityInversion{tx: tx, blocking: blocking, waited: waited})
+		}
+	}
+	return list
+}
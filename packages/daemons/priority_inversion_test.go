@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/shopspring/decimal"
+)
+
+func queuedTx(hash string, keyID, fee, time int64) *sqldb.Transaction {
+	return &sqldb.Transaction{Hash: []byte(hash), KeyID: keyID, Expedite: decimal.NewFromInt(fee), Time: time}
+}
+
+// TestInversionPercentile checks that only the transactions over the 90th percentile of the fees of the
+// queue are high-fee, the queue of ten fees has the percentile of the ninth one
+func TestInversionPercentile(t *testing.T) {
+	txs := []*sqldb.Transaction{
+		queuedTx("low", 1, 1, 1),
+		queuedTx("ninth", 1, 9, 2),
+		queuedTx("high", 1, 10, 3),
+	}
+	for i := int64(2); i <= 8; i++ {
+		txs = append(txs, queuedTx(string(rune('a'+i)), 100+i, i, 1))
+	}
+	m := &inversionMonitor{seen: make(map[string]int64), reported: make(map[string]bool)}
+	m.check(10, txs, 0)
+	list := m.check(11, txs, 0)
+	if len(list) != 1 || string(list[0].tx.Hash) != "high" || string(list[0].blocking.Hash) != "low" {
+		t.Fatalf("expected the inversion of the high-fee transaction, got %+v", list)
+	}
+
+	// the transaction of the key which has the lower fee and is the later one doesn't block
+	m = &inversionMonitor{seen: make(map[string]int64), reported: make(map[string]bool)}
+	txs[0].Time = 5
+	if list = m.check(20, txs, 0); len(list) != 0 {
+		t.Errorf("later transaction blocks the high-fee one %+v", list)
+	}
+}
+
+// TestInversionThreshold checks that the inversion is reported once after the threshold blocks
+func TestInversionThreshold(t *testing.T) {
+	const threshold = 3
+	txs := []*sqldb.Transaction{queuedTx("low", 1, 1, 1), queuedTx("high", 1, 100, 2)}
+	m := &inversionMonitor{seen: make(map[string]int64), reported: make(map[string]bool)}
+	for blockID := int64(10); blockID <= 10+threshold; blockID++ {
+		if list := m.check(blockID, txs, threshold); len(list) != 0 {
+			t.Fatalf("inversion is reported at block %d before the threshold", blockID)
+		}
+	}
+	list := m.check(10+threshold+1, txs, threshold)
+	if len(list) != 1 || list[0].waited != threshold+1 {
+		t.Fatalf("expected the inversion after %d blocks, got %+v", threshold, list)
+	}
+	if list = m.check(10+threshold+2, txs, threshold); len(list) != 0 {
+		t.Errorf("inversion is reported again %+v", list)
+	}
+
+	// the transactions which have left the queue are forgotten, the returned one waits again
+	m.check(20, txs[:1], threshold)
+	if _, ok := m.seen["high"]; ok || m.reported["high"] {
+		t.Fatal("transaction which has left the queue is kept")
+	}
+	if list = m.check(21, txs, threshold); len(list) != 0 {
+		t.Errorf("returned transaction is reported before the threshold %+v", list)
+	}
+}
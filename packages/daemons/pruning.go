@@ -0,0 +1,61 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// pruningInterval is the pause between the runs of Pruning
+	pruningInterval = time.Minute
+	// pruningCatchUp is the pause while the old blocks of the node are pruned
+	pruningCatchUp = time.Second
+)
+
+// Pruning deletes the payload of the blocks beyond the retention window of the pruned node, the blocks
+// are pruned by batches from the oldest one
+func Pruning(ctx context.Context, d *daemon) error {
+	d.sleepTime = pruningInterval
+	if !conf.Config.Pruned {
+		return nil
+	}
+	retention := conf.Config.PruneRetentionBlocks
+	if rb := syspar.GetRbBlocks1(); retention < rb {
+		retention = rb
+	}
+	last := &sqldb.BlockChain{}
+	if _, err := last.GetMaxBlock(); err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return err
+	}
+	pruned, err := sqldb.GetPrunedHeight()
+	if err != nil {
+		d.logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pruned height")
+		return err
+	}
+	from, to, ok := block.PruneRange(last.ID, pruned, retention)
+	if !ok {
+		return nil
+	}
+	if err = block.PruneBlocks(from, to); err != nil {
+		return err
+	}
+	d.logger.WithFields(log.Fields{"from_block": from, "to_block": to}).Debug("blocks pruned")
+	if to < last.ID-retention {
+		d.sleepTime = pruningCatchUp
+	}
+	return nil
+}
@@ -82,5 +82,5 @@ func QueueParserBlocks(ctx context.Context, d *daemon) error {
 
 	host := utils.GetHostPort(nodeHost)
 	// update our chain till maxBlockID from the host
-	return UpdateChain(ctx, d, host, blockID)
+	return UpdateChain(ctx, d, host, blockID, blockID)
 }
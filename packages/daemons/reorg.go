@@ -0,0 +1,177 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/rollback"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// orphanBlock returns the orphan of the raw block if its parent isn't the block of the chain, known is true if
+// the block is in the chain already. The block which isn't decoded is checked by the usual way
+func orphanBlock(rb []byte) (o *block.Orphan, known bool, err error) {
+	if o, err = block.NewOrphan(rb); err != nil {
+		return nil, false, nil
+	}
+	hash, err := chainHash(o.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if bytes.Equal(hash, o.Hash) {
+		return nil, true, nil
+	}
+	if hash, err = chainHash(o.ID - 1); err != nil {
+		return nil, false, err
+	}
+	if bytes.Equal(hash, o.PrevHash) {
+		return nil, false, nil
+	}
+	return o, false, nil
+}
+
+// chainHash returns the hash of the block of the chain, nil if there is no block
+func chainHash(id int64) ([]byte, error) {
+	bc := &sqldb.BlockChain{}
+	found, err := bc.Get(id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return bc.Hash, nil
+}
+
+// reorganize applies the longest branch of the orphan pool which goes through the orphan if the branch meets
+// the chain at most rollback_blocks_1 blocks ago and it's longer than the chain. The blocks of the chain after
+// the fork are rolled back, they are played again if the branch fails. It returns true if the branch is applied
+func reorganize(ctx context.Context, logger *log.Entry, o *block.Orphan) (bool, error) {
+	last := &sqldb.BlockChain{}
+	if _, err := last.GetMaxBlock(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting max block")
+		return false, err
+	}
+	maxDepth := syspar.GetRbBlocks1()
+	block.Orphans.Prune(last.ID - maxDepth)
+	tip := block.Orphans.Tip(o.Hash)
+	if tip == nil {
+		tip = o
+	}
+	branch := block.Orphans.Branch(tip.Hash)
+	forkID, heavier, err := block.ReorgPlan(branch, last.ID, maxDepth, chainHash)
+	if errors.Is(err, block.ErrOrphanFork) {
+		// the parent of the branch is still unknown
+		return false, nil
+	}
+	if errors.Is(err, block.ErrOrphanDepth) {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "block_id": branch[0].ID, "max_block": last.ID}).Warn("dropping orphan branch")
+		block.Orphans.Remove(branch)
+		return false, nil
+	}
+	if err != nil || !heavier {
+		return false, err
+	}
+	if err = block.CheckFinalized(forkID); err != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "tip_block": tip.ID}).Warn("dropping orphan branch")
+		block.Orphans.Remove(branch)
+		if errors.Is(err, block.ErrFinalized) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	logger.WithFields(log.Fields{"fork_block": forkID, "max_block": last.ID, "tip_block": tip.ID}).Warn("reorganizing chain")
+	blocks := &sqldb.BlockChain{}
+	chain, err := blocks.GetBlocksFrom(forkID, "desc", 0)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting rollback blocks from blockID")
+		return false, err
+	}
+	transaction.CleanCache()
+	if _, err = sqldb.MarkVerifiedAndNotUsedTransactionsUnverified(); err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("marking verified and not used transactions unverified")
+		return false, utils.ErrInfo(err)
+	}
+	// the blocks of the chain are kept to be played again if the branch fails
+	restore := make([][]byte, len(chain))
+	for i, bc := range chain {
+		data, err := bc.BlockData()
+		if err != nil {
+			return false, utils.ErrInfo(err)
+		}
+		if err = rollback.RollbackBlock(data); err != nil {
+			if _, errRestore := playBlocksData(ctx, restore[len(chain)-i:]); errRestore != nil {
+				logger.WithFields(log.Fields{"type": consts.BlockError, "error": errRestore, "fork_block": forkID}).Error("restoring chain")
+			}
+			return false, utils.ErrInfo(err)
+		}
+		restore[len(chain)-1-i] = data
+	}
+
+	script.SavepointSmartVMObjects()
+	data := make([][]byte, len(branch))
+	for i, b := range branch {
+		data[i] = b.Data
+	}
+	played, err := playBlocksData(ctx, data)
+	block.Orphans.Remove(branch)
+	if err == nil {
+		script.ReleaseSmartVMObjects()
+		return true, nil
+	}
+	logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "fork_block": forkID}).Error("playing orphan branch")
+	if errRestore := restoreChain(ctx, data[:played], restore); errRestore != nil {
+		logger.WithFields(log.Fields{"type": consts.BlockError, "error": errRestore, "fork_block": forkID}).Error("restoring chain")
+	}
+	return false, err
+}
+
+// restoreChain rolls back the played blocks of the branch and plays the blocks of the chain again
+func restoreChain(ctx context.Context, played, chain [][]byte) error {
+	for i := len(played) - 1; i >= 0; i-- {
+		if err := rollback.RollbackBlock(played[i]); err != nil {
+			return err
+		}
+	}
+	script.RollbackSmartVMObjects()
+	_, err := playBlocksData(ctx, chain)
+	return err
+}
+
+// playBlocksData checks and plays the binary blocks one after another, it returns the number of the played blocks
+func playBlocksData(ctx context.Context, data [][]byte) (int, error) {
+	for i, rb := range data {
+		bl, err := block.ProcessBlockByBinData(rb, true)
+		if err != nil {
+			return i, err
+		}
+		if err = bl.Check(); err != nil {
+			return i, err
+		}
+		played := false
+		err = block.PlayRetry(ctx, playAttempts, playRetryDelay, func() error {
+			if played {
+				if bl, err = block.ProcessBlockByBinData(rb, true); err != nil {
+					return err
+				}
+			}
+			played = true
+			return bl.PlaySafe()
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(data), nil
+}
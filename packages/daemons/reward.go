@@ -0,0 +1,43 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package daemons
+
+import (
+	"sync/atomic"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// rewardAddressWarned is set while the configured reward address differs from the registered one
+var rewardAddressWarned atomic.Bool
+
+// checkRewardAddress compares the reward address of the config with the on-chain registration
+// of the node key. The rewards are settled by the registration only, the config can't redirect
+// them, so the node operator is warned until @1SetRewardDestination is sent with the hot key
+func checkRewardAddress(logger *log.Entry, blockID int64) {
+	if len(conf.Config.RewardAddress) == 0 {
+		return
+	}
+	destination, found, err := sqldb.GetRewardDestination(nil, conf.Config.KeyID, blockID)
+	if err != nil {
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting reward destination")
+		return
+	}
+	want := converter.AddressToID(conf.Config.RewardAddress)
+	if want != 0 && found && destination == want {
+		rewardAddressWarned.Store(false)
+		return
+	}
+	if !rewardAddressWarned.Swap(true) {
+		logger.WithFields(log.Fields{"type": consts.ConfigError, "reward_address": conf.Config.RewardAddress,
+			"registered": found}).Warn("reward address isn't registered on-chain, it's ignored")
+	}
+}
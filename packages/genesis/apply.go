@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package genesis
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// defaultConditions are the conditions of the contract of the spec without the conditions
+const defaultConditions = `ContractConditions("@1DeveloperCondition")`
+
+// Apply writes the state of the spec by the first block transaction with txHash. The output 0 of
+// the transaction is the founder's, the balances of the accounts are the next outputs. The contracts
+// are loaded into the vm by the caller
+func (s *Spec) Apply(dbTx *sqldb.DbTransaction, txHash []byte) error {
+	db := sqldb.GetDB(dbTx)
+	for i, a := range s.Accounts {
+		pub, _ := crypto.HexToPub(a.PublicKey)
+		keyID := crypto.Address(pub)
+		err := db.Exec(`insert into "1_keys" (id,account,pub,amount) values(?,?,?,0) on conflict (ecosystem,id) do nothing`,
+			keyID, converter.AddressToString(keyID), pub).Error
+		if err != nil {
+			return fmt.Errorf("inserting key of account %d: %w", i, err)
+		}
+		if len(a.Balance) == 0 {
+			continue
+		}
+		amount, _ := AccountAmount(a.Balance)
+		err = db.Exec(`insert into "spent_info" (output_index,output_tx_hash,output_key_id,output_value,ecosystem,block_id,type) values(?,?,?,?,?,?,?)`,
+			i+1, txHash, keyID, amount.String(), 1, 1, consts.UTXO_Type_First_Block).Error
+		if err != nil {
+			return fmt.Errorf("inserting balance of account %d: %w", i, err)
+		}
+	}
+
+	params := make(map[string]string, len(s.Parameters)+1)
+	for name, value := range s.Parameters {
+		params[name] = value
+	}
+	if len(s.HonorNodes) > 0 {
+		params[syspar.HonorNodes], _ = s.honorNodesValue()
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res := db.Exec(`update "1_platform_parameters" set value = ? where name = ?`, params[name], name)
+		if res.Error != nil {
+			return fmt.Errorf("updating parameter %s: %w", name, res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return specError("unknown parameter %q", name)
+		}
+	}
+
+	for _, c := range s.Contracts {
+		conditions := c.Conditions
+		if len(conditions) == 0 {
+			conditions = defaultConditions
+		}
+		id, err := dbTx.GetNextID("1_contracts")
+		if err != nil {
+			return err
+		}
+		err = db.Exec(`insert into "1_contracts" (id,name,value,token_id,conditions,app_id,ecosystem) values(?,?,?,1,?,1,1)`,
+			id, c.Name, c.Source, conditions).Error
+		if err != nil {
+			return fmt.Errorf("inserting contract %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
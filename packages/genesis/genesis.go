@@ -0,0 +1,212 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package genesis parses the declarative spec of the initial state of the network. The spec is
+// embedded in the first block, so every node validates and applies the same spec when it plays
+// the first block
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// Version is the version of the format of the spec, the binary first block without the spec is the version 1
+	Version = 2
+	// publicKeyLength is the length of the public key without the prefix
+	publicKeyLength = 64
+)
+
+var (
+	// ErrSpec is returned if the spec is invalid
+	ErrSpec = errors.New("invalid genesis spec")
+
+	contractName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	// reservedParams are set by the fields of the spec or by the first block itself
+	reservedParams = map[string]bool{
+		syspar.HonorNodes:        true,
+		syspar.TaxesWallet:       true,
+		syspar.Test:              true,
+		syspar.PrivateBlockchain: true,
+	}
+)
+
+// Account is the key which exists since the first block, Balance is the amount of the tokens of the first
+// ecosystem which the key gets as the utxo output
+type Account struct {
+	PublicKey string `json:"public_key" yaml:"public_key"`
+	Balance   string `json:"balance,omitempty" yaml:"balance"`
+}
+
+// HonorNode is the initial honor node of the network
+type HonorNode struct {
+	TCPAddress string `json:"tcp_address" yaml:"tcp_address"`
+	APIAddress string `json:"api_address" yaml:"api_address"`
+	PublicKey  string `json:"public_key" yaml:"public_key"`
+}
+
+// Contract is the contract of the first ecosystem which is loaded with the first block
+type Contract struct {
+	Name       string `json:"name" yaml:"name"`
+	Source     string `json:"source" yaml:"source"`
+	Conditions string `json:"conditions,omitempty" yaml:"conditions"`
+}
+
+// Spec is the initial state of the network. The parameters are the platform parameters which exist
+// in the first ecosystem, honor_nodes is set by HonorNodes
+type Spec struct {
+	Version    int               `json:"version" yaml:"version"`
+	Timestamp  int64             `json:"timestamp,omitempty" yaml:"timestamp"`
+	Test       bool              `json:"test,omitempty" yaml:"test"`
+	Private    bool              `json:"private,omitempty" yaml:"private"`
+	Accounts   []Account         `json:"accounts,omitempty" yaml:"accounts"`
+	HonorNodes []HonorNode       `json:"honor_nodes,omitempty" yaml:"honor_nodes"`
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters"`
+	Contracts  []Contract        `json:"contracts,omitempty" yaml:"contracts"`
+}
+
+// Load reads the spec from the JSON or the YAML file, the format is chosen by the extension of the file
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(spec)
+	default:
+		err = strictJSON(data, spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSpec, err)
+	}
+	if err = spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Parse returns the valid spec which is embedded in the first block
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := strictJSON(data, spec); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSpec, err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func strictJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// Marshal returns the JSON of the spec which is embedded in the first block
+func (s *Spec) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func specError(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrSpec, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the spec without the state, so all nodes get the same result
+func (s *Spec) Validate() error {
+	if s.Version != Version {
+		return specError("version %d isn't supported", s.Version)
+	}
+	if s.Timestamp < 0 {
+		return specError("negative timestamp")
+	}
+	keys := make(map[int64]bool, len(s.Accounts))
+	for i, a := range s.Accounts {
+		pub, err := crypto.HexToPub(a.PublicKey)
+		if err != nil || len(pub) != publicKeyLength {
+			return specError("account %d: invalid public key", i)
+		}
+		keyID := crypto.Address(pub)
+		if keys[keyID] {
+			return specError("account %d: duplicate key", i)
+		}
+		keys[keyID] = true
+		if len(a.Balance) > 0 {
+			if _, err = AccountAmount(a.Balance); err != nil {
+				return specError("account %d: %v", i, err)
+			}
+		}
+	}
+	if len(s.HonorNodes) > 0 {
+		if _, err := s.honorNodesValue(); err != nil {
+			return specError("honor nodes: %v", err)
+		}
+	}
+	for name := range s.Parameters {
+		if len(name) == 0 || reservedParams[name] {
+			return specError("parameter %q can't be set", name)
+		}
+	}
+	names := make(map[string]bool, len(s.Contracts))
+	for i, c := range s.Contracts {
+		if !contractName.MatchString(c.Name) {
+			return specError("contract %d: invalid name %q", i, c.Name)
+		}
+		if names[c.Name] {
+			return specError("contract %d: duplicate name %q", i, c.Name)
+		}
+		names[c.Name] = true
+		if !regexp.MustCompile(`\bcontract\s+` + c.Name + `\b`).MatchString(c.Source) {
+			return specError("contract %d: source doesn't declare %q", i, c.Name)
+		}
+	}
+	return nil
+}
+
+// AccountAmount returns the amount of the utxo output of the balance in the tokens
+func AccountAmount(balance string) (decimal.Decimal, error) {
+	value, err := decimal.NewFromString(balance)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("balance %q: %v", balance, err)
+	}
+	amount := value.Shift(int32(consts.MoneyDigits))
+	if amount.Sign() <= 0 || !amount.Equal(amount.Truncate(0)) {
+		return decimal.Zero, fmt.Errorf("balance %q must be positive with %d digits at most", balance, consts.MoneyDigits)
+	}
+	return amount, nil
+}
+
+// honorNodesValue returns the value of honor_nodes parameter, the nodes are checked as the parameter
+func (s *Spec) honorNodesValue() (string, error) {
+	data, err := json.Marshal(s.HonorNodes)
+	if err != nil {
+		return "", err
+	}
+	var nodes []*syspar.HonorNode
+	if err = json.Unmarshal(data, &nodes); err != nil {
+		return "", err
+	}
+	if err = syspar.DuplicateHonorNode(nodes); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,91 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package genesis
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testPub = "0498b18e551493a269b6f419d7784d26c8e3555638e80897c69997ef9f211e21d5d0b8adeeaab0e0e750e720ddf3048ec55d613ba5dee3fdfd4e7c17d346731e9b"
+
+const testYAML = `version: 2
+timestamp: 1700000000
+test: true
+accounts:
+  - public_key: ` + testPub + `
+    balance: "1000.5"
+honor_nodes:
+  - tcp_address: 127.0.0.1:7078
+    api_address: http://127.0.0.1:7079
+    public_key: ` + testPub + `
+parameters:
+  max_tx_block: "500"
+contracts:
+  - name: Hello
+    source: contract Hello { action { } }
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "genesis.yaml")
+	if err := os.WriteFile(path, []byte(testYAML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Timestamp != 1700000000 || !spec.Test || len(spec.Accounts) != 1 || spec.Parameters["max_tx_block"] != "500" {
+		t.Fatalf("unexpected spec %+v", spec)
+	}
+	amount, err := AccountAmount(spec.Accounts[0].Balance)
+	if err != nil || amount.String() != "1000500000000000" {
+		t.Errorf("amount %s, error %v", amount, err)
+	}
+
+	// the spec of the first block is the same as the file
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, spec) {
+		t.Errorf("parsed spec %+v differs from %+v", parsed, spec)
+	}
+	jsonPath := filepath.Join(dir, "genesis.json")
+	if err = os.WriteFile(jsonPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := Load(jsonPath); err != nil || !reflect.DeepEqual(loaded, spec) {
+		t.Errorf("json spec %+v, error %v", loaded, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for name, spec := range map[string]Spec{
+		"version":   {Version: 1},
+		"key":       {Version: 2, Accounts: []Account{{PublicKey: "04abcd"}}},
+		"duplicate": {Version: 2, Accounts: []Account{{PublicKey: testPub}, {PublicKey: testPub}}},
+		"balance":   {Version: 2, Accounts: []Account{{PublicKey: testPub, Balance: "0.0000000000000000001"}}},
+		"node":      {Version: 2, HonorNodes: []HonorNode{{TCPAddress: "127.0.0.1:7078", APIAddress: "bad", PublicKey: testPub}}},
+		"reserved":  {Version: 2, Parameters: map[string]string{"honor_nodes": "[]"}},
+		"contract":  {Version: 2, Contracts: []Contract{{Name: "Hello", Source: "contract Other {}"}}},
+	} {
+		if err := spec.Validate(); !errors.Is(err, ErrSpec) {
+			t.Errorf("%s: expected invalid spec, got %v", name, err)
+		}
+	}
+	if _, err := Parse([]byte(`{"version":2,"unknown":1}`)); !errors.Is(err, ErrSpec) {
+		t.Errorf("unknown field: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// DefaultPassphraseEnvVar is the environment variable with the passphrase of the encrypted key file by default
+const DefaultPassphraseEnvVar = "IBAX_NODE_KEY_PASSPHRASE"
+
+// The parameters of scrypt of the new key files, the file keeps its parameters
+const (
+	scryptN      = 1 << 16
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLength   = 32
+)
+
+var (
+	// ErrPassphrase is returned if the passphrase doesn't decrypt the key file
+	ErrPassphrase = errors.New("wrong passphrase of private key")
+	// ErrEmptyPassphrase is returned if the private key is encrypted with the empty passphrase
+	ErrEmptyPassphrase = errors.New("passphrase is empty")
+)
+
+// encryptedKey is the key file with the private key encrypted by AES-GCM with the key of scrypt
+type encryptedKey struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// IsEncryptedKey returns true if the data of the key file is the encrypted private key
+func IsEncryptedKey(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// EncryptKey returns the key file with the private key encrypted with the passphrase
+func EncryptKey(privateKey []byte, passphrase string) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+	key := &encryptedKey{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := key.cipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key.Salt, key.Nonce = hex.EncodeToString(salt), hex.EncodeToString(nonce)
+	key.Ciphertext = hex.EncodeToString(aead.Seal(nil, nonce, privateKey, nil))
+	return json.MarshalIndent(key, "", "  ")
+}
+
+// DecryptKey returns the private key of the key file which is encrypted with the passphrase
+func DecryptKey(data []byte, passphrase string) ([]byte, error) {
+	key := &encryptedKey{}
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, fmt.Errorf("decoding encrypted key: %w", err)
+	}
+	if key.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key derivation %q", key.KDF)
+	}
+	salt, err := hex.DecodeString(key.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decoding salt: %w", err)
+	}
+	nonce, err := hex.DecodeString(key.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decoding nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(key.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decoding ciphertext: %w", err)
+	}
+	aead, err := key.cipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("wrong nonce length %d", len(nonce))
+	}
+	privateKey, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	return privateKey, nil
+}
+
+func (key *encryptedKey) cipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, key.N, key.R, key.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
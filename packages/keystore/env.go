@@ -0,0 +1,36 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package keystore
+
+import (
+	"fmt"
+	"os"
+)
+
+// DefaultEnvVar is the environment variable of EnvKeystore by default
+const DefaultEnvVar = "IBAX_NODE_PRIVATE_KEY"
+
+// EnvKeystore reads the hex encoded private key from the environment variable
+type EnvKeystore struct {
+	*KeySigner
+	Name string
+}
+
+// NewEnvKeystore reads the private key from the environment variable name
+func NewEnvKeystore(name string) (*EnvKeystore, error) {
+	if name == "" {
+		name = DefaultEnvVar
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	signer, err := newHexKeySigner(value)
+	if err != nil {
+		return nil, fmt.Errorf("private key of %s: %w", name, err)
+	}
+	return &EnvKeystore{KeySigner: signer, Name: name}, nil
+}
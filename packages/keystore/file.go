@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package keystore
+
+import (
+	"fmt"
+	"os"
+)
+
+// FileKeystore reads the private key from the file, the key is hex encoded or encrypted by EncryptKey
+type FileKeystore struct {
+	*KeySigner
+	Path string
+}
+
+// NewFileKeystore reads the private key from the file at path, passphrase decrypts the encrypted key
+func NewFileKeystore(path, passphrase string) (*FileKeystore, error) {
+	privateKey, err := ReadKeyFile(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := NewKeySigner(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("private key of %s: %w", path, err)
+	}
+	return &FileKeystore{KeySigner: signer, Path: path}, nil
+}
+
+// ReadKeyFile returns the binary private key of the file at path
+func ReadKeyFile(path, passphrase string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key from file: %w", err)
+	}
+	if !IsEncryptedKey(data) {
+		signer, err := newHexKeySigner(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("private key of %s: %w", path, err)
+		}
+		return signer.privateKey, nil
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("private key of %s is encrypted: %w", path, ErrEmptyPassphrase)
+	}
+	privateKey, err := DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("private key of %s: %w", path, err)
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,86 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package keystore provides the signers of the node which keep the private key
+// in the local file, in the environment or in the external vault
+package keystore
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+// Types of the keystores in the configuration
+const (
+	TypeFile  = "file"
+	TypeEnv   = "env"
+	TypeVault = "vault"
+)
+
+// ErrEmptyKey is returned when the keystore doesn't contain the private key
+var ErrEmptyKey = errors.New("private key is empty")
+
+// Signer signs the digests with the private key which may be not accessible to the node
+type Signer interface {
+	// Sign returns the signature of the digest which is hashed by crypto.Hash
+	Sign(digest []byte) ([]byte, error)
+	// PublicKey returns the public key of the signer
+	PublicKey() crypto.PublicKey
+}
+
+// KeyExporter is implemented by the signers which keep the private key in the node memory
+type KeyExporter interface {
+	PrivateKey() []byte
+}
+
+// SignData signs the hash of data like crypto.Sign does
+func SignData(s Signer, data []byte) ([]byte, error) {
+	return s.Sign(crypto.Hash(data))
+}
+
+// KeySigner signs with the private key in memory
+type KeySigner struct {
+	privateKey []byte
+	publicKey  crypto.PublicKey
+}
+
+// NewKeySigner returns the signer of the binary private key
+func NewKeySigner(privateKey []byte) (*KeySigner, error) {
+	if len(privateKey) == 0 {
+		return nil, ErrEmptyKey
+	}
+	publicKey, err := crypto.PrivateToPublic(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySigner{privateKey: privateKey, publicKey: publicKey}, nil
+}
+
+// newHexKeySigner returns the signer of the hex encoded private key
+func newHexKeySigner(hexKey string) (*KeySigner, error) {
+	privateKey, err := hex.DecodeString(strings.TrimSpace(hexKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(privateKey)
+}
+
+// Sign implements Signer
+func (s *KeySigner) Sign(digest []byte) ([]byte, error) {
+	return crypto.GetAsymProvider().Sign(s.privateKey, digest)
+}
+
+// PublicKey implements Signer
+func (s *KeySigner) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
+
+// PrivateKey implements KeyExporter
+func (s *KeySigner) PrivateKey() []byte {
+	return s.privateKey
+}
@@ -0,0 +1,189 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto/asymalgo"
+)
+
+func checkSigner(t *testing.T, s Signer, publicKey []byte) {
+	t.Helper()
+	if hex.EncodeToString(s.PublicKey()) != hex.EncodeToString(publicKey) {
+		t.Fatalf("wrong public key %x", s.PublicKey())
+	}
+	data := []byte("0,10,block")
+	signature, err := SignData(s, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := crypto.Verify(publicKey, data, signature); !ok || err != nil {
+		t.Errorf("signature is rejected: %v", err)
+	}
+}
+
+func TestLocalKeystores(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, pub, err := crypto.GenHexKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, _ := crypto.HexToPub(pub)
+
+	path := filepath.Join(t.TempDir(), "NodePrivateKey")
+	if err = os.WriteFile(path, []byte(priv+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileKeystore, err := NewFileKeystore(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, fileKeystore, publicKey)
+	if _, err = NewFileKeystore(path+"1", ""); err == nil {
+		t.Error("missing file is accepted")
+	}
+
+	t.Setenv(DefaultEnvVar, priv)
+	envKeystore, err := NewEnvKeystore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, envKeystore, publicKey)
+	if hex.EncodeToString(envKeystore.PrivateKey()) != priv {
+		t.Error("wrong exported private key")
+	}
+	t.Setenv(DefaultEnvVar, "")
+	if _, err = NewEnvKeystore(""); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("empty key: got %v", err)
+	}
+}
+
+func TestEncryptedFileKeystore(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, pub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := EncryptKey(priv, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncryptedKey(data) || bytes.Contains(data, []byte(hex.EncodeToString(priv))) {
+		t.Fatal("private key isn't encrypted")
+	}
+	path := filepath.Join(t.TempDir(), "NodePrivateKey")
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileKeystore, err := NewFileKeystore(path, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSigner(t, fileKeystore, pub)
+	if _, err = NewFileKeystore(path, "wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong passphrase: got %v", err)
+	}
+	if _, err = NewFileKeystore(path, ""); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("empty passphrase: got %v", err)
+	}
+	if _, err = EncryptKey(priv, ""); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("encrypting with empty passphrase: got %v", err)
+	}
+}
+
+// newTransitServer emulates the transit secrets engine with the ecdsa-p256 key
+func newTransitServer(t *testing.T, key *ecdsa.PrivateKey) *httptest.Server {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	reply := func(w http.ResponseWriter, data any) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/transit/keys/node", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]any{
+			"type":           "ecdsa-p256",
+			"latest_version": 2,
+			"keys":           map[string]any{"2": map[string]string{"public_key": string(publicPEM)}},
+		})
+	})
+	mux.HandleFunc("/v1/transit/sign/node", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input      string `json:"input"`
+			Prehashed  bool   `json:"prehashed"`
+			Marshaling string `json:"marshaling_algorithm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.Prehashed || req.Marshaling != "jws" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{"invalid request"}})
+			return
+		}
+		digest, _ := base64.StdEncoding.DecodeString(req.Input)
+		r1, s1, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			t.Error(err)
+		}
+		signature := append(asymalgo.FillLeft(r1.Bytes()), asymalgo.FillLeft(s1.Bytes())...)
+		reply(w, map[string]string{"signature": "vault:v2:" + base64.RawURLEncoding.EncodeToString(signature)})
+	})
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+func TestVaultKeystore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTransitServer(t, key)
+	defer server.Close()
+	conf := VaultConfig{Address: server.URL, Token: "secret", Key: "node"}
+
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	if _, err = NewVaultKeystore(conf); err == nil {
+		t.Error("vault keystore is accepted for secp256k1")
+	}
+
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_P256.String())
+	vault, err := NewVaultKeystore(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := append(asymalgo.FillLeft(key.X.Bytes()), asymalgo.FillLeft(key.Y.Bytes())...)
+	checkSigner(t, vault, publicKey)
+	if _, ok := Signer(vault).(KeyExporter); ok {
+		t.Error("vault keystore exports the private key")
+	}
+
+	conf.Token = "wrong"
+	if _, err = NewVaultKeystore(conf); err == nil {
+		t.Error("wrong token is accepted")
+	}
+}
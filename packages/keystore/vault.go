@@ -0,0 +1,188 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package keystore
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/common/crypto/asymalgo"
+)
+
+const (
+	// DefaultVaultMount is the path of the transit secrets engine by default
+	DefaultVaultMount = "transit"
+	// DefaultVaultTimeout is the timeout of the requests to Vault by default
+	DefaultVaultTimeout = 10 * time.Second
+
+	vaultKeyType       = "ecdsa-p256"
+	vaultSignaturePref = "vault:v"
+	vaultTokenEnv      = "VAULT_TOKEN"
+)
+
+// VaultConfig is the settings of HashiCorp Vault with the transit secrets engine
+type VaultConfig struct {
+	Address string // address of the server, e.g. https://127.0.0.1:8200
+	Token   string // VAULT_TOKEN environment variable is used if it's empty
+	Mount   string // path of the transit secrets engine
+	Key     string // name of ecdsa-p256 key
+	Timeout time.Duration
+}
+
+// VaultKeystore signs the digests with the key of the Vault transit secrets engine.
+// The private key never leaves Vault so only ECC_P256 algorithm is supported
+type VaultKeystore struct {
+	conf      VaultConfig
+	client    *http.Client
+	publicKey crypto.PublicKey
+}
+
+// NewVaultKeystore checks the key of the transit secrets engine and reads its public key
+func NewVaultKeystore(conf VaultConfig) (*VaultKeystore, error) {
+	if _, ok := crypto.GetAsymProvider().(*asymalgo.P256); !ok {
+		return nil, fmt.Errorf("vault keystore supports %s only", crypto.AsymAlgo_ECC_P256)
+	}
+	if conf.Address == "" || conf.Key == "" {
+		return nil, fmt.Errorf("vault address and key name must be specified")
+	}
+	if conf.Token == "" {
+		conf.Token = os.Getenv(vaultTokenEnv)
+	}
+	if conf.Mount == "" {
+		conf.Mount = DefaultVaultMount
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = DefaultVaultTimeout
+	}
+	v := &VaultKeystore{
+		conf:   conf,
+		client: &http.Client{Timeout: conf.Timeout},
+	}
+	var err error
+	if v.publicKey, err = v.readPublicKey(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Sign implements Signer
+func (v *VaultKeystore) Sign(digest []byte) ([]byte, error) {
+	var result struct {
+		Signature string `json:"signature"`
+	}
+	err := v.request(http.MethodPost, "sign/"+url.PathEscape(v.conf.Key), map[string]any{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"hash_algorithm":       "sha2-256",
+		"marshaling_algorithm": "jws",
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	// the signature is vault:v<version>:<base64url of r || s>
+	parts := strings.SplitN(result.Signature, ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(result.Signature, vaultSignaturePref) {
+		return nil, fmt.Errorf("vault: wrong signature format")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return nil, fmt.Errorf("vault: decoding signature: %w", err)
+	}
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("vault: wrong signature length %d", len(signature))
+	}
+	return signature, nil
+}
+
+// PublicKey implements Signer
+func (v *VaultKeystore) PublicKey() crypto.PublicKey {
+	return v.publicKey
+}
+
+func (v *VaultKeystore) readPublicKey() (crypto.PublicKey, error) {
+	var result struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	}
+	if err := v.request(http.MethodGet, "keys/"+url.PathEscape(v.conf.Key), nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Type != vaultKeyType {
+		return nil, fmt.Errorf("vault: key %s has %s type, %s is required", v.conf.Key, result.Type, vaultKeyType)
+	}
+	key, ok := result.Keys[strconv.Itoa(result.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("vault: key %s has no version %d", v.conf.Key, result.LatestVersion)
+	}
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("vault: wrong public key format")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("vault: parsing public key: %w", err)
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok || ecdsaPub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("vault: public key is not %s", vaultKeyType)
+	}
+	return append(asymalgo.FillLeft(ecdsaPub.X.Bytes()), asymalgo.FillLeft(ecdsaPub.Y.Bytes())...), nil
+}
+
+// request calls the API of the transit secrets engine and decodes data of the response into result
+func (v *VaultKeystore) request(method, path string, body, result any) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	u := strings.TrimRight(v.conf.Address, "/") + "/v1/" + strings.Trim(v.conf.Mount, "/") + "/" + path
+	req, err := http.NewRequest(method, u, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.conf.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&out); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("vault: decoding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault: %s %s: %d %s", method, path, resp.StatusCode, strings.Join(out.Errors, "; "))
+	}
+	if err = json.Unmarshal(out.Data, result); err != nil {
+		return fmt.Errorf("vault: decoding response data: %w", err)
+	}
+	return nil
+}
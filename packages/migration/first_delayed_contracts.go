@@ -8,5 +8,6 @@ package migration
 var firstDelayedContractsDataSQL = `INSERT INTO "1_delayed_contracts"
 		("id", "contract", "key_id", "block_id", "every_block", "high_rate", "conditions")
 	VALUES
-		(next_id('1_delayed_contracts'), '@1CheckNodesBan', '{{.Wallet}}', '10', '10', '4','ContractConditions("@1MainCondition")');
+		(next_id('1_delayed_contracts'), '@1CheckNodesBan', '{{.Wallet}}', '10', '10', '4','ContractConditions("@1MainCondition")'),
+		(next_id('1_delayed_contracts'), '@1PurgeDeletedObjects', '{{.Wallet}}', '100', '100', '4','ContractConditions("@1MainCondition")');
 `
@@ -78,6 +78,28 @@ VALUES
         CallContract("@1VotingTemplateRun",temp)
     }
 }
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'AccountFreeze', 'contract AccountFreeze {
+    data {
+        Account string
+        Unfreeze bool "optional"
+    }
+
+    conditions {
+        EvalCondition("parameters", "freezing_accounts", "value")
+        $key_id = AddressToId($Account)
+        if $key_id == 0 {
+            warning Sprintf("AccountFreeze: wrong account %s", $Account)
+        }
+        if !DBFind("@1keys").Where({"id": $key_id, "ecosystem": $ecosystem_id}).One("id") {
+            warning Sprintf("AccountFreeze: account %s has not been found in ecosystem %d", $Account, $ecosystem_id)
+        }
+    }
+
+    action {
+        SetAccountFrozen($key_id, !$Unfreeze)
+    }
+}
 ', '1', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'BindWallet', 'contract BindWallet {
 	data {
@@ -181,6 +203,24 @@ VALUES
         UpdateNodesBan($block_time)
     }
 }
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'DeleteObject', 'contract DeleteObject {
+    data {
+        Type string
+        Id int
+    }
+
+    conditions {
+        if $Type != "contracts" && $Type != "pages" && $Type != "snippets" && $Type != "menu" {
+            warning Sprintf("DeleteObject: wrong type %s", $Type)
+        }
+        RowConditions($Type, $Id, false)
+    }
+
+    action {
+        SetObjectDeleted($Type, $Id, true)
+    }
+}
 ', '1', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'EditAppParam', 'contract EditAppParam {
     data {
@@ -531,6 +571,79 @@ VALUES
 		warning "HonorNodeCondition: Sorry, you do not have access to this action"
 	}
 }
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'IdentityRegistry', 'contract IdentityRegistry {
+    data {
+        DisplayName string
+        EmailHash string "optional"
+        Website string "optional"
+    }
+
+    conditions {
+        $DisplayName = TrimSpace($DisplayName)
+        if Size($DisplayName) == 0 || Size($DisplayName) > 255 {
+            warning "IdentityRegistry: display name must be between 1 and 255 characters"
+        }
+        $EmailHash = ToLower($EmailHash)
+        if Size($EmailHash) > 0 && !RegexpMatch($EmailHash, ` + "`" + `^[0-9a-f]{64}$` + "`" + `) {
+            warning "IdentityRegistry: email hash must be 64 hex characters"
+        }
+        if Size($Website) > 255 {
+            warning "IdentityRegistry: website is too long"
+        }
+        if Size($Website) > 0 && !HasPrefix($Website, "http://") && !HasPrefix($Website, "https://") {
+            warning "IdentityRegistry: website must begin with http:// or https://"
+        }
+        $Id = Int(DBFind("@1identities").Where({"key_id": $key_id, "ecosystem": $ecosystem_id}).One("id"))
+    }
+
+    action {
+        // the changed identity has to be verified again
+        var pars map
+        pars = {"display_name": $DisplayName, "email_hash": $EmailHash, "website": $Website,
+            "verified_by_key_id": 0, "block_id": $block}
+        if $Id {
+            DBUpdate("@1identities", $Id, pars)
+        } else {
+            pars["key_id"] = $key_id
+            $Id = DBInsert("@1identities", pars)
+        }
+        DBInsert("@1identity_events", {"event": "IdentityUpdatedEvent", "key_id": $key_id, "block_id": $block})
+        $result = $Id
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'IdentityVerification', 'contract IdentityVerification {
+    data {
+        Account string
+        Revoke bool "optional"
+    }
+
+    conditions {
+        EvalCondition("parameters", "identity_verifiers", "value")
+        $identity_key = AddressToId($Account)
+        if $identity_key == 0 {
+            warning Sprintf("IdentityVerification: wrong account %s", $Account)
+        }
+        if $identity_key == $key_id {
+            warning "IdentityVerification: the own identity can''t be verified"
+        }
+        $Id = Int(DBFind("@1identities").Where({"key_id": $identity_key, "ecosystem": $ecosystem_id}).One("id"))
+        if !$Id {
+            warning Sprintf("IdentityVerification: identity of %s has not been found in ecosystem %d", $Account, $ecosystem_id)
+        }
+    }
+
+    action {
+        var verifier int
+        if !$Revoke {
+            verifier = $key_id
+        }
+        DBUpdate("@1identities", $Id, {"verified_by_key_id": verifier, "block_id": $block})
+        DBInsert("@1identity_events", {"event": "IdentityUpdatedEvent", "key_id": $identity_key,
+            "verified_by_key_id": verifier, "block_id": $block})
+    }
+}
 ', '1', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'Import', 'contract Import {
     data {
@@ -619,6 +732,38 @@ VALUES
         // Println(Sprintf("> time: %v", $time))
     }
 }
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'ImportBundle', 'contract ImportBundle {
+    data {
+        Data string
+        Overwrite bool "optional"
+    }
+    conditions {
+        $calls = BundlePlan($Data, $Overwrite)
+    }
+    action {
+        var i int
+        while i < Len($calls) {
+            var call params map
+            call = $calls[i]
+            params = call["Params"]
+            if call["NewApp"] {
+                params["ApplicationId"] = Int(DBFind("@1applications").Columns("id").Where({"name": call["NewApp"], "ecosystem": $ecosystem_id}).One("id"))
+            }
+            if call["Kind"] == "seeds" {
+                var rows array j int
+                rows = params["Rows"]
+                while j < Len(rows) {
+                    DBInsert(call["Name"], rows[j])
+                    j = j + 1
+                }
+            } else {
+                CallContract(call["Contract"], params)
+            }
+            i = i + 1
+        }
+    }
+}
 ', '1', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'ImportUpload', 'contract ImportUpload {
     data {
@@ -872,6 +1017,7 @@ VALUES
     conditions {
         ValidateCondition($Conditions,$ecosystem_id)
 
+        CheckTombstone("menu", $Name)
         if DBFind("menu").Columns("id").Where({name: $Name}).One("id") {
             warning Sprintf( "Menu %s already exists", $Name)
         }
@@ -917,6 +1063,7 @@ VALUES
             warning "Application id cannot equal 0"
         }
 
+        CheckTombstone("pages", $Name)
         if DBFind("pages").Columns("id").Where({name: $Name}).One("id") {
             warning Sprintf( "Page %s already exists", $Name)
         }
@@ -982,6 +1129,7 @@ VALUES
             warning "Application id cannot equal 0"
         }
 
+        CheckTombstone("snippets", $Name)
         if DBFind("snippets").Columns("id").Where({name:$Name}).One("id") {
             warning Sprintf( "Block %s already exists", $Name)
         }
@@ -1119,6 +1267,296 @@ VALUES
         }
 	}
 }
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'OracleCommit', 'contract OracleCommit {
+    data {
+        RequestId int
+        Hash string
+    }
+
+    conditions {
+        HonorNodeCondition()
+        $request = DBFind("@1oracle_requests").Where({"id": $RequestId}).Row()
+        if !$request {
+            warning Sprintf("OracleCommit: request %d has not been found", $RequestId)
+        }
+        if Int($request["status"]) != 0 || $block_time >= Int($request["deadline"]) {
+            warning Sprintf("OracleCommit: request %d is closed", $RequestId)
+        }
+        if Size($Hash) != 64 {
+            warning "OracleCommit: invalid hash"
+        }
+        $node_id = AddressToId($account_id)
+        if DBCount("@1oracle_answers", {"request_id": $RequestId, "node_id": $node_id}) > 0 {
+            warning "OracleCommit: answer has already been committed"
+        }
+        if DBCount("@1oracle_answers", {"request_id": $RequestId, "revealed": 1}) > 0 {
+            warning "OracleCommit: reveal phase has already begun"
+        }
+    }
+
+    action {
+        DBInsert("@1oracle_answers", {"request_id": $RequestId, "node_id": $node_id,
+            "commit_hash": ToLower($Hash), "block_id": $block})
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'OracleRequest', 'contract OracleRequest {
+    data {
+        Url string
+        JsonPath string "optional"
+        Callback string
+        Deadline int
+        Threshold int "optional"
+    }
+
+    conditions {
+        if !HasPrefix($Url, "http://") && !HasPrefix($Url, "https://") {
+            warning "OracleRequest: url must begin with http:// or https://"
+        }
+        if Size($Url) > 1024 || Size($JsonPath) > 255 {
+            warning "OracleRequest: url or json path is too long"
+        }
+        if $Deadline <= $block_time {
+            warning "OracleRequest: deadline must be greater than block time"
+        }
+        if !HasPrefix($Callback, "@") {
+            $Callback = "@" + Str($ecosystem_id) + $Callback
+        }
+        if !GetContractByName($Callback) {
+            warning Sprintf("OracleRequest: contract %s has not been found", $Callback)
+        }
+        var nodes int
+        nodes = HonorNodesCount()
+        if $Threshold == 0 {
+            $Threshold = nodes / 2 + 1
+        }
+        if $Threshold < 1 || $Threshold > nodes {
+            warning Sprintf("OracleRequest: threshold must be between 1 and %d", nodes)
+        }
+    }
+
+    action {
+        $result = DBInsert("@1oracle_requests", {"url": $Url, "json_path": $JsonPath, "callback": $Callback,
+            "threshold": $Threshold, "deadline": $Deadline, "key_id": $key_id, "ecosystem": $ecosystem_id,
+            "block_id": $block})
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'OracleResolve', 'contract OracleResolve {
+    data {
+        RequestId int
+    }
+
+    conditions {
+        HonorNodeCondition()
+        $request = DBFind("@1oracle_requests").Where({"id": $RequestId}).Row()
+        if !$request {
+            warning Sprintf("OracleResolve: request %d has not been found", $RequestId)
+        }
+        if Int($request["status"]) != 0 {
+            warning Sprintf("OracleResolve: request %d has already been resolved", $RequestId)
+        }
+        if $block_time < Int($request["deadline"]) {
+            warning Sprintf("OracleResolve: deadline of request %d has not been reached", $RequestId)
+        }
+    }
+
+    action {
+        DBUpdate("@1oracle_requests", $RequestId, {"status": 2})
+        CallContract($request["callback"], {"RequestId": $RequestId, "Value": "", "Failed": 1})
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'OracleReveal', 'contract OracleReveal {
+    data {
+        RequestId int
+        Value string
+        Salt string
+    }
+
+    conditions {
+        HonorNodeCondition()
+        $request = DBFind("@1oracle_requests").Where({"id": $RequestId}).Row()
+        if !$request {
+            warning Sprintf("OracleReveal: request %d has not been found", $RequestId)
+        }
+        if Int($request["status"]) != 0 || $block_time >= Int($request["deadline"]) {
+            warning Sprintf("OracleReveal: request %d is closed", $RequestId)
+        }
+        $node_id = AddressToId($account_id)
+        $answer = DBFind("@1oracle_answers").Where({"request_id": $RequestId, "node_id": $node_id}).Row()
+        if !$answer {
+            warning "OracleReveal: answer has not been committed"
+        }
+        if Int($answer["revealed"]) != 0 {
+            warning "OracleReveal: answer has already been revealed"
+        }
+        if DBCount("@1oracle_answers", {"request_id": $RequestId}) < Int($request["threshold"]) {
+            warning "OracleReveal: not enough commits"
+        }
+        if Hash(Sprintf("%d:%s:%s", $RequestId, $Value, $Salt)) != $answer["commit_hash"] {
+            warning "OracleReveal: value does not match the commit"
+        }
+    }
+
+    action {
+        DBUpdate("@1oracle_answers", Int($answer["id"]), {"value": $Value, "revealed": 1})
+        if DBCount("@1oracle_answers", {"request_id": $RequestId, "revealed": 1, "value": $Value}) >= Int($request["threshold"]) {
+            DBUpdate("@1oracle_requests", $RequestId, {"status": 1, "value": $Value})
+            CallContract($request["callback"], {"RequestId": $RequestId, "Value": $Value, "Failed": 0})
+        }
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'PurgeDeletedObjects', 'contract PurgeDeletedObjects {
+    func getPermission() {
+        var array_permissions array result i int prevContract string
+        array_permissions = ["@1PurgeDeletedObjects"]
+
+        prevContract = $stack[0]
+        if Len($stack) > 2 {
+            prevContract = $stack[Len($stack) - 2]
+        }
+        while i < Len(array_permissions) {
+            var contract_name string
+            contract_name = array_permissions[i]
+            if contract_name == prevContract {
+                result = 1
+            }
+            i = i + 1
+        }
+
+        if result == 0 {
+            warning LangRes("@1contract_chain_distorted")
+        }
+    }
+    conditions {
+        getPermission()
+        HonorNodeCondition()
+        var rows array
+        rows = DBFind("@1delayed_contracts").Where({"contract": "@1PurgeDeletedObjects", "deleted": 0})
+        if !Len(rows) {
+            warning Sprintf(LangRes("@1template_delayed_contract_not_exist"), $Id)
+        }
+        $cur = rows[0]
+        $counter = Int($cur["counter"]) + 1
+        $Id = Int($cur["id"])
+    }
+    action {
+        DBUpdateExt("@1delayed_contracts", {"id":$Id}, {"counter": $counter})
+
+        PurgeDeletedObjects()
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'RestoreObject', 'contract RestoreObject {
+    data {
+        Type string
+        Id int
+    }
+
+    conditions {
+        if $Type != "contracts" && $Type != "pages" && $Type != "snippets" && $Type != "menu" {
+            warning Sprintf("RestoreObject: wrong type %s", $Type)
+        }
+        RowConditions($Type, $Id, false)
+    }
+
+    action {
+        SetObjectDeleted($Type, $Id, false)
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'SetAuthContract', 'contract SetAuthContract {
+    data {
+        Contract string "optional"
+    }
+
+    conditions {
+        if Size($Contract) > 0 && !GetContractByName($Contract) {
+            warning Sprintf("SetAuthContract: contract %s has not been found", $Contract)
+        }
+    }
+
+    action {
+        SetKeyAuthContract($Contract)
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'SetKeyParent', 'contract SetKeyParent {
+    data {
+        ParentKey string
+        Index int
+    }
+
+    conditions {
+        if $Index < 0 {
+            warning Sprintf("SetKeyParent: wrong index %d", $Index)
+        }
+    }
+
+    action {
+        SetKeyParent($ParentKey, $Index)
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'SetRewardDestination', 'contract SetRewardDestination {
+    data {
+        Account string
+    }
+
+    conditions {
+        $destination = AddressToId($Account)
+        if $destination == 0 {
+            warning Sprintf("SetRewardDestination: wrong account %s", $Account)
+        }
+    }
+
+    action {
+        SetRewardDestination($destination)
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'TimeLockRelease', 'contract TimeLockRelease {
+    data {
+        Id int
+    }
+
+    conditions {
+        if !DBFind("@1time_locks").Where({"id": $Id, "ecosystem": $ecosystem_id}).One("id") {
+            warning Sprintf("TimeLockRelease: time lock %d has not been found in ecosystem %d", $Id, $ecosystem_id)
+        }
+    }
+
+    action {
+        $result = ReleaseTimeLock($Id)
+    }
+}
+', '1', 'ContractConditions("MainCondition")', '1', '1'),
+	(next_id('1_contracts'), 'TimeLockTransfer', 'contract TimeLockTransfer {
+    data {
+        Recipient string
+        Amount money
+        UnlockTime int "optional"
+        UnlockBlock int "optional"
+        VestingEnd int "optional"
+    }
+
+    conditions {
+        $recipient_id = AddressToId($Recipient)
+        if $recipient_id == 0 {
+            warning Sprintf("TimeLockTransfer: wrong recipient %s", $Recipient)
+        }
+        if $Amount <= 0 {
+            warning "TimeLockTransfer: amount must be greater than zero"
+        }
+    }
+
+    action {
+        $result = SetTimeLock($recipient_id, $Amount, $UnlockTime, $UnlockBlock, $VestingEnd)
+    }
+}
 ', '1', 'ContractConditions("MainCondition")', '1', '1'),
 	(next_id('1_contracts'), 'UnbindWallet', 'contract UnbindWallet {
 	data {
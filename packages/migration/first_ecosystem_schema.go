@@ -40,7 +40,24 @@ var sqlFirstEcosystemSchema = `
 		t.Column("limit", "bigint", {"default": "0"})
 		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("conditions", "text", {"default": ""})
-	{{footer "primary" "index(block_id)"}}
+		t.Column("callback", "string", {"default": "", "size":255})
+		t.Column("origin_tx_hash", "bytea", {"default": ""})
+		t.Column("origin_block_id", "bigint", {"default": "0"})
+	{{footer "primary" "index(block_id)" "index(origin_tx_hash)"}}
+
+	{{head "1_delayed_results"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("delayed_id", "bigint", {"default": "0"})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("contract", "string", {"default": "", "size":255})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("success", "bigint", {"default": "0"})
+		t.Column("result", "text", {"default": ""})
+		t.Column("fuel", "bigint", {"default": "0"})
+		t.Column("callback", "string", {"default": "", "size":255})
+		t.Column("callback_error", "text", {"default": ""})
+	{{footer "primary" "index(delayed_id)"}}
 
 	{{head "1_bad_blocks"}}
 		t.Column("id", "bigint", {"default": "0"})
@@ -59,6 +76,30 @@ var sqlFirstEcosystemSchema = `
 		t.Column("ban_time", "bigint", {"default": "0"})
 		t.Column("reason", "text", {"default": ""})
 	{{footer "primary" }}
+
+	{{head "1_oracle_requests"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("url", "text", {"default": ""})
+		t.Column("json_path", "varchar(255)", {"default": ""})
+		t.Column("callback", "varchar(255)", {"default": ""})
+		t.Column("threshold", "bigint", {"default": "0"})
+		t.Column("deadline", "bigint", {"default": "0"})
+		t.Column("status", "bigint", {"default": "0"})
+		t.Column("value", "text", {"default": ""})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("ecosystem", "bigint", {"default": "1"})
+		t.Column("block_id", "bigint", {"default": "0"})
+	{{footer "primary" "index(status, deadline)"}}
+
+	{{head "1_oracle_answers"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("request_id", "bigint", {"default": "0"})
+		t.Column("node_id", "bigint", {"default": "0"})
+		t.Column("commit_hash", "varchar(64)", {"default": ""})
+		t.Column("value", "text", {"default": ""})
+		t.Column("revealed", "bigint", {"default": "0"})
+		t.Column("block_id", "bigint", {"default": "0"})
+	{{footer "primary" "index(request_id, node_id)"}}
 `
 
 var sqlFirstEcosystemCommon = `
@@ -70,10 +111,14 @@ var sqlFirstEcosystemCommon = `
 		t.Column("multi", "bigint", {"default": "0"})
 		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("blocked", "bigint", {"default": "0"})
+		t.Column("frozen", "bigint", {"default": "0"})
+		t.Column("auth_contract", "string", {"default": "", "size":255})
+		t.Column("parent_key_id", "bigint", {"default": "0"})
+		t.Column("derivation_index", "bigint", {"default": "0"})
 		t.Column("ecosystem", "bigint", {"default": "1"})
 		t.Column("account", "char(24)", {})
 		t.PrimaryKey("ecosystem", "id")
-	{{footer "index(account)" "unique(ecosystem, account)"}}
+	{{footer "index(account)" "unique(ecosystem, account)" "index(ecosystem, parent_key_id)"}}
 
 	{{head "1_menu"}}
 		t.Column("id", "bigint", {"default": "0"})
@@ -82,6 +127,7 @@ var sqlFirstEcosystemCommon = `
 		t.Column("value", "text", {"default": ""})
 		t.Column("conditions", "text", {"default": ""})
 		t.Column("permissions", "jsonb", {"null": true})
+		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("ecosystem", "bigint", {"default": "1"})
 	{{footer "primary" "unique(ecosystem, name)" "index(ecosystem, name)"}}
 
@@ -95,6 +141,7 @@ var sqlFirstEcosystemCommon = `
 		t.Column("permissions", "jsonb", {"null": true})
 		t.Column("app_id", "bigint", {"default": "1"})
 		t.Column("validate_mode", "character(1)", {"default": "0"})
+		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("ecosystem", "bigint", {"default": "1"})
 	{{footer "primary" "unique(ecosystem, name)" "index(ecosystem, name)"}}
 
@@ -105,6 +152,7 @@ var sqlFirstEcosystemCommon = `
 		t.Column("conditions", "text", {"default": ""})
 		t.Column("permissions", "jsonb", {"null": true})
 		t.Column("app_id", "bigint", {"default": "1"})
+		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("ecosystem", "bigint", {"default": "1"})
 	{{footer "primary" "unique(ecosystem, name)" "index(ecosystem, name)"}}
 
@@ -126,6 +174,7 @@ var sqlFirstEcosystemCommon = `
 		t.Column("conditions", "text", {"default": ""})
 		t.Column("permissions", "jsonb", {"null": true})
 		t.Column("app_id", "bigint", {"default": "1"})
+		t.Column("deleted", "bigint", {"default": "0"})
 		t.Column("ecosystem", "bigint", {"default": "1"})
 	{{footer "primary" "unique(ecosystem, name)" "index(ecosystem)"}}
 
@@ -198,6 +247,48 @@ var sqlFirstEcosystemCommon = `
 	{{footer "primary" "index(ecosystem)"}}
 	add_index("1_members", ["account", "ecosystem"], {"unique": true})
 
+	{{head "1_identities"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("display_name", "string", {"default": "", "size": 255})
+		t.Column("email_hash", "varchar(64)", {"default": ""})
+		t.Column("website", "string", {"default": "", "size": 255})
+		t.Column("verified_by_key_id", "bigint", {"default": "0"})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("ecosystem", "bigint", {"default": "1"})
+	{{footer "primary" "unique(ecosystem, key_id)"}}
+
+	{{head "1_identity_events"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("event", "varchar(64)", {"default": ""})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("verified_by_key_id", "bigint", {"default": "0"})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("ecosystem", "bigint", {"default": "1"})
+	{{footer "primary" "index(ecosystem, key_id)"}}
+
+	{{head "1_time_locks"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("sender_id", "bigint", {"default": "0"})
+		t.Column("recipient_id", "bigint", {"default": "0"})
+		t.Column("amount", "decimal(30)", {"default_raw": "'0' CHECK (amount > 0)"})
+		t.Column("released", "decimal(30)", {"default_raw": "'0' CHECK (released >= 0 AND released <= amount)"})
+		t.Column("created_at", "bigint", {"default": "0"})
+		t.Column("unlock_time", "bigint", {"default": "0"})
+		t.Column("unlock_block", "bigint", {"default": "0"})
+		t.Column("vesting_end", "bigint", {"default": "0"})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("ecosystem", "bigint", {"default": "1"})
+	{{footer "primary" "index(ecosystem, recipient_id)"}}
+
+	{{head "1_proposal_snapshots"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("amount", "decimal(30)", {"default": "0"})
+		t.Column("snapshot_block_id", "bigint", {"default": "0"})
+		t.Column("ecosystem", "bigint", {"default": "1"})
+	{{footer "primary" "unique(ecosystem, snapshot_block_id, key_id)"}}
+
 	{{head "1_roles"}}
 		t.Column("id", "bigint", {"default": "0"})
 		t.Column("default_page", "string", {"default": "", "size": 255})
@@ -22,7 +22,30 @@ INSERT INTO "1_tables" ("id", "name", "permissions","columns", "conditions") VAL
             "high_rate": "ContractAccess(\"@1EditDelayedContract\")",
             "limit": "ContractAccess(\"@1EditDelayedContract\")",
             "deleted": "ContractAccess(\"@1EditDelayedContract\")",
-            "conditions": "ContractAccess(\"@1EditDelayedContract\")"
+            "conditions": "ContractAccess(\"@1EditDelayedContract\")",
+            "callback": "ContractAccess(\"@1EditDelayedContract\")",
+            "origin_tx_hash": "false",
+            "origin_block_id": "false"
+        }',
+        'ContractConditions("@1MainCondition")'
+    ),
+    (next_id('1_tables'), 'delayed_results',
+        '{
+            "insert": "false",
+            "update": "false",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "delayed_id": "false",
+            "key_id": "false",
+            "contract": "false",
+            "block_id": "false",
+            "tx_hash": "false",
+            "success": "false",
+            "result": "false",
+            "fuel": "false",
+            "callback": "false",
+            "callback_error": "false"
         }',
         'ContractConditions("@1MainCondition")'
     ),
@@ -90,6 +113,42 @@ INSERT INTO "1_tables" ("id", "name", "permissions","columns", "conditions") VAL
         }',
         'ContractConditions("@1MainCondition")'
     ),
+    (next_id('1_tables'), 'oracle_requests',
+        '{
+            "insert": "ContractAccess(\"@1OracleRequest\")",
+            "update": "ContractAccess(\"@1OracleReveal\", \"@1OracleResolve\")",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "url": "false",
+            "json_path": "false",
+            "callback": "false",
+            "threshold": "false",
+            "deadline": "false",
+            "status": "ContractAccess(\"@1OracleReveal\", \"@1OracleResolve\")",
+            "value": "ContractAccess(\"@1OracleReveal\")",
+            "key_id": "false",
+            "ecosystem": "false",
+            "block_id": "false"
+        }',
+        'ContractConditions("@1MainCondition")'
+    ),
+    (next_id('1_tables'), 'oracle_answers',
+        '{
+            "insert": "ContractAccess(\"@1OracleCommit\")",
+            "update": "ContractAccess(\"@1OracleReveal\")",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "request_id": "false",
+            "node_id": "false",
+            "commit_hash": "false",
+            "value": "ContractAccess(\"@1OracleReveal\")",
+            "revealed": "ContractAccess(\"@1OracleReveal\")",
+            "block_id": "false"
+        }',
+        'ContractConditions("@1MainCondition")'
+    ),
     (next_id('1_tables'), 'time_zones',
         '{
             "insert": "false",
@@ -32,6 +32,51 @@ var updateMigrations = []*migration{
 	{"0.0.3", updates.MigrationUpdatePriceExec, false},
 	{"0.0.4", updates.MigrationUpdateAccessExec, false},
 	{"0.0.5", updates.MigrationUpdatePriceCreateExec, false},
+	{"0.0.6", updates.MigrationUpdateAuditLog, true},
+	{"0.0.7", updates.MigrationUpdatePriceExecMerkle, false},
+	{"0.0.8", updates.MigrationUpdateTxParamsLimits, false},
+	{"0.0.9", updates.MigrationUpdateAccountFreeze, false},
+	{"0.0.10", updates.MigrationUpdateBlockFeeStats, true},
+	{"0.0.11", updates.MigrationUpdateConsensusSchedule, false},
+	{"0.0.12", updates.MigrationUpdateRegisteredTxTypes, false},
+	{"0.0.13", updates.MigrationUpdateMaxBlockWeight, false},
+	{"0.0.14", updates.MigrationUpdateRewardDestinations, true},
+	{"0.0.15", updates.MigrationUpdateRewardDestinationAccess, false},
+	{"0.0.16", updates.MigrationUpdateContractStats, true},
+	{"0.0.17", updates.MigrationUpdateResourceUsage, true},
+	{"0.0.18", updates.MigrationUpdateServiceKeys, true},
+	{"0.0.19", updates.MigrationUpdateStateDiffs, true},
+	{"0.0.20", updates.MigrationUpdateSoftDelete, false},
+	{"0.0.21", updates.MigrationUpdateTimeLocks, false},
+	{"0.0.22", updates.MigrationUpdateProposalSnapshots, false},
+	{"0.0.23", updates.MigrationUpdateAuthContracts, false},
+	{"0.0.24", updates.MigrationUpdateDelayedResults, true},
+	{"0.0.25", updates.MigrationUpdateBlockTxData, false},
+	{"0.0.26", updates.MigrationUpdateBaseGasPrice, false},
+	{"0.0.27", updates.MigrationUpdateBlockResources, true},
+	{"0.0.28", updates.MigrationUpdateDerivedKeys, false},
+	{"0.0.29", updates.MigrationUpdateMaxTxRollbacks, false},
+	{"0.0.30", updates.MigrationUpdateSysparSnapshots, true},
+	{"0.0.31", updates.MigrationUpdateKeyRotationBlocks, false},
+	{"0.0.32", updates.MigrationUpdateContractEvents, true},
+	{"0.0.33", updates.MigrationUpdateBlockFormatVersion, false},
+	{"0.0.34", updates.MigrationUpdateConfidentialUTXO, false},
+	{"0.0.35", updates.MigrationUpdateBlockSignatures, false},
+	{"0.0.36", updates.MigrationUpdateDelayedOrigin, false},
+	{"0.0.37", updates.MigrationUpdateTxReceipts, true},
+	{"0.0.38", updates.MigrationUpdateHeartbeatBlocks, false},
+	{"0.0.39", updates.MigrationUpdateCheckpoints, true},
+	{"0.0.40", updates.MigrationUpdateCheckpointInterval, false},
+	{"0.0.41", updates.MigrationUpdateRandomBeacon, false},
+	{"0.0.42", updates.MigrationUpdateBlockCompression, false},
+	{"0.0.43", updates.MigrationUpdateStateRoot, false},
+	{"0.0.44", updates.MigrationUpdateEventsBloom, false},
+	{"0.0.45", updates.MigrationUpdateSavepointBatch, false},
+	{"0.0.46", updates.MigrationUpdateBlockPruning, false},
+	{"0.0.47", updates.MigrationUpdateMaxPastBlockAge, false},
+	{"0.0.48", updates.MigrationUpdateMinPoWBits, false},
+	{"0.0.49", updates.MigrationUpdateStrictBlockTime, false},
+	{"0.0.50", updates.MigrationUpdateTxTypeOrder, false},
 }
 
 type migration struct {
@@ -17,6 +17,8 @@ INSERT INTO "1_parameters" ("id","name", "value", "conditions", "ecosystem") VAL
 	(next_id('1_parameters'),'changing_parameters', 'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
 	(next_id('1_parameters'),'changing_app_params', 'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
 	(next_id('1_parameters'),'changing_snippets', 'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
+	(next_id('1_parameters'),'freezing_accounts', 'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
+	(next_id('1_parameters'),'identity_verifiers', 'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
 	(next_id('1_parameters'),'max_sum', '1000000', 'ContractConditions("DeveloperCondition")', '{{.Ecosystem}}'),
 	(next_id('1_parameters'),'print_stylesheet', 'body {
 		  /* You can define your custom styles here or create custom CSS rules */
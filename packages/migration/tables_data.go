@@ -20,6 +20,7 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "conditions": "ContractAccess(\"@1EditContract\")",
             "permissions": "ContractConditions(\"@1MainCondition\")",
             "app_id": "ContractAccess(\"@1ItemChangeAppId\")",
+            "deleted": "false",
             "ecosystem": "false"
         }',
         'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
@@ -36,6 +37,10 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "maxpay": "ContractConditions(\"@1MainCondition\")",
             "deleted": "ContractAccess(\"@1DeleteMember\")",
             "blocked": "ContractAccess(\"@1TokensLockoutMember\")",
+            "frozen": "false",
+            "auth_contract": "false",
+            "parent_key_id": "false",
+            "derivation_index": "false",
             "account": "false",
             "ecosystem": "false",
             "multi": "ContractConditions(\"@1MainCondition\")"
@@ -92,6 +97,7 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "title": "ContractAccess(\"@1EditMenu\")",
             "conditions": "ContractAccess(\"@1EditMenu\")",
             "permissions": "ContractConditions(\"@1MainCondition\")",
+            "deleted": "false",
             "ecosystem": "false"
         }',
         'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
@@ -111,6 +117,7 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "app_id": "ContractAccess(\"@1ItemChangeAppId\")",
             "conditions": "ContractAccess(\"@1EditPage\")",
             "permissions": "ContractConditions(\"@1MainCondition\")",
+            "deleted": "false",
             "ecosystem": "false"
         }',
         'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
@@ -127,6 +134,7 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "conditions": "ContractAccess(\"@1EditSnippet\")",
             "permissions": "ContractConditions(\"@1MainCondition\")",
             "app_id": "ContractAccess(\"@1ItemChangeAppId\")",
+            "deleted": "false",
             "ecosystem": "false"
         }',
         'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
@@ -297,6 +305,72 @@ var tablesDataSQL = `INSERT INTO "1_tables" ("id", "name", "permissions","column
             "ecosystem": "false"
         }',
         'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
+    ),
+    (next_id('1_tables'), 'identities',
+        '{
+            "insert": "ContractAccess(\"@1IdentityRegistry\")",
+            "update": "ContractAccess(\"@1IdentityRegistry\", \"@1IdentityVerification\")",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "key_id": "false",
+            "display_name": "ContractAccess(\"@1IdentityRegistry\")",
+            "email_hash": "ContractAccess(\"@1IdentityRegistry\")",
+            "website": "ContractAccess(\"@1IdentityRegistry\")",
+            "verified_by_key_id": "ContractAccess(\"@1IdentityRegistry\", \"@1IdentityVerification\")",
+            "block_id": "ContractAccess(\"@1IdentityRegistry\", \"@1IdentityVerification\")",
+            "ecosystem": "false"
+        }',
+        'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
+    ),
+    (next_id('1_tables'), 'identity_events',
+        '{
+            "insert": "ContractAccess(\"@1IdentityRegistry\", \"@1IdentityVerification\")",
+            "update": "false",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "event": "false",
+            "key_id": "false",
+            "verified_by_key_id": "false",
+            "block_id": "false",
+            "ecosystem": "false"
+        }',
+        'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
+    ),
+    (next_id('1_tables'), 'time_locks',
+        '{
+            "insert": "ContractAccess(\"@1TimeLockTransfer\")",
+            "update": "ContractAccess(\"@1TimeLockRelease\")",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "sender_id": "false",
+            "recipient_id": "false",
+            "amount": "false",
+            "released": "ContractAccess(\"@1TimeLockRelease\")",
+            "created_at": "false",
+            "unlock_time": "false",
+            "unlock_block": "false",
+            "vesting_end": "false",
+            "block_id": "false",
+            "ecosystem": "false"
+        }',
+        'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
+    ),
+    (next_id('1_tables'), 'proposal_snapshots',
+        '{
+            "insert": "false",
+            "update": "false",
+            "new_column": "ContractConditions(\"@1MainCondition\")"
+        }',
+        '{
+            "key_id": "false",
+            "amount": "false",
+            "snapshot_block_id": "false",
+            "ecosystem": "false"
+        }',
+        'ContractConditions("@1MainCondition")', '{{.Ecosystem}}'
     ),
 	(next_id('1_tables'), 'views',
         '{
@@ -0,0 +1,19 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateAuditLog = `
+	{{head "audit_log"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("time", "bigint", {"default": "0"})
+		t.Column("action", "string", {"default": "", "size":64})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("payload_hash", "bytea", {"default": ""})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("prev_hash", "bytea", {"default": ""})
+		t.Column("hash", "bytea", {"default": ""})
+	{{footer "primary" "index(action, time)"}}
+`
@@ -0,0 +1,28 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateBlockResources = `
+	{{head "block_resources"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("last_block_id", "bigint", {"default": "0"})
+		t.Column("period_start", "timestamptz", {"default_raw": "now()"})
+		t.Column("resolution", "string", {"default": "block", "size":16})
+		t.Column("blocks", "bigint", {"default": "0"})
+		t.Column("tx_types", "text", {"default": "{}"})
+		t.Column("tx_count", "bigint", {"default": "0"})
+		t.Column("tx_count_max", "bigint", {"default": "0"})
+		t.Column("size", "bigint", {"default": "0"})
+		t.Column("size_max", "bigint", {"default": "0"})
+		t.Column("fuel", "bigint", {"default": "0"})
+		t.Column("fuel_max", "bigint", {"default": "0"})
+		t.Column("exec_ns", "bigint", {"default": "0"})
+		t.Column("exec_ns_max", "bigint", {"default": "0"})
+		t.Column("savepoints", "bigint", {"default": "0"})
+		t.Column("savepoints_max", "bigint", {"default": "0"})
+		t.Column("notifications", "bigint", {"default": "0"})
+		t.Column("notifications_max", "bigint", {"default": "0"})
+	{{footer "primary(block_id)" "index(resolution, period_start)" "index(period_start)"}}
+`
@@ -0,0 +1,14 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateCheckpoints = `
+	{{head "checkpoints"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("hash", "bytea", {"default": ""})
+		t.Column("attestations", "bytea", {"default": ""})
+		t.Column("created_at", "timestamptz", {"default_raw": "now()"})
+	{{footer "primary(block_id)"}}
+`
@@ -0,0 +1,17 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateContractEvents = `
+	{{head "contract_events"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("log_index", "integer", {"default": "0"})
+		t.Column("ecosystem_id", "bigint", {"default": "0"})
+		t.Column("contract_name", "string", {"default": "", "size":255})
+		t.Column("event_name", "string", {"default": "", "size":255})
+		t.Column("data", "jsonb", {"default": "{}"})
+	{{footer "primary(tx_hash,log_index)" "index(block_id)" "index(ecosystem_id, contract_name, event_name, block_id)"}}
+`
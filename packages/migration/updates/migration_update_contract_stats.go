@@ -0,0 +1,19 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateContractStats = `
+	{{head "contract_stats"}}
+		t.Column("day", "string", {"default": "", "size":10})
+		t.Column("ecosystem", "bigint", {"default": "0"})
+		t.Column("contract", "string", {"default": "", "size":255})
+		t.Column("success", "bigint", {"default": "0"})
+		t.Column("failed", "bigint", {"default": "0"})
+		t.Column("fuel_total", "bigint", {"default": "0"})
+		t.Column("fuel_max", "bigint", {"default": "0"})
+		t.Column("fuel_buckets", "text", {"default": ""})
+		t.Column("rows_written", "bigint", {"default": "0"})
+	{{footer "primary(day,ecosystem,contract)" "index(ecosystem, contract, day)"}}
+`
@@ -157,3 +157,222 @@ INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
 	(next_id('1_platform_parameters'), 'external_blockchain', '', 'ContractAccess("@1UpdatePlatformParam")'),
 	(next_id('1_platform_parameters'), 'price_exec_send_external_transaction', '50', 'ContractAccess("@1UpdatePlatformParam")');
 `
+
+var MigrationUpdatePriceExecMerkle = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'price_exec_verify_merkle_proof', '50', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'price_exec_get_block_header', '50', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateTxParamsLimits = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'max_tx_params', '256', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'max_tx_param_size', '33554432', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateAccountFreeze = `
+ALTER TABLE "1_keys" ADD COLUMN IF NOT EXISTS "frozen" bigint NOT NULL DEFAULT '0';
+UPDATE "1_tables" SET columns = columns || '{"frozen": "false"}'::jsonb WHERE name = 'keys';
+INSERT INTO "1_parameters" (id, name, value, conditions, ecosystem)
+	SELECT (SELECT COALESCE(max(id), 0) FROM "1_parameters") + row_number() OVER (ORDER BY e.id), 'freezing_accounts',
+		'ContractConditions("DeveloperCondition")', 'ContractConditions("DeveloperCondition")', e.id
+	FROM "1_ecosystems" AS e
+	WHERE NOT EXISTS (SELECT 1 FROM "1_parameters" AS p WHERE p.ecosystem = e.id AND p.name = 'freezing_accounts');
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_set_account_frozen', 'ContractAccess("@1AccountFreeze")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateConsensusSchedule = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'consensus_schedule', '{}', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateRegisteredTxTypes = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'registered_tx_types', '{}', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateMaxBlockWeight = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'max_block_weight', '1000000', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateRewardDestinationAccess = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_set_reward_destination', 'ContractAccess("@1SetRewardDestination")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateSoftDelete = `
+ALTER TABLE "1_contracts" ADD COLUMN IF NOT EXISTS "deleted" bigint NOT NULL DEFAULT '0';
+ALTER TABLE "1_pages" ADD COLUMN IF NOT EXISTS "deleted" bigint NOT NULL DEFAULT '0';
+ALTER TABLE "1_snippets" ADD COLUMN IF NOT EXISTS "deleted" bigint NOT NULL DEFAULT '0';
+ALTER TABLE "1_menu" ADD COLUMN IF NOT EXISTS "deleted" bigint NOT NULL DEFAULT '0';
+UPDATE "1_tables" SET columns = columns || '{"deleted": "false"}'::jsonb WHERE name IN ('contracts', 'pages', 'snippets', 'menu');
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'deleted_objects_retention', '2592000', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'access_exec_set_object_deleted', 'ContractAccess("@1DeleteObject","@1RestoreObject")', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'access_exec_purge_deleted_objects', 'ContractAccess("@1PurgeDeletedObjects")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateTimeLocks = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_set_time_lock', 'ContractAccess("@1TimeLockTransfer")', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'access_exec_release_time_lock', 'ContractAccess("@1TimeLockRelease")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateProposalSnapshots = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_snapshot_balances', 'ContractAccess("@1VotingTemplateRun")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateAuthContracts = `
+ALTER TABLE "1_keys" ADD COLUMN IF NOT EXISTS "auth_contract" varchar(255) NOT NULL DEFAULT '';
+UPDATE "1_tables" SET columns = columns || '{"auth_contract": "false"}'::jsonb WHERE name = 'keys';
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_set_key_auth_contract', 'ContractAccess("@1SetAuthContract")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateDelayedResults = `
+ALTER TABLE "1_delayed_contracts" ADD COLUMN IF NOT EXISTS "callback" varchar(255) NOT NULL DEFAULT '';
+UPDATE "1_tables" SET columns = columns || '{"callback": "ContractAccess(\"@1EditDelayedContract\")"}'::jsonb WHERE name = 'delayed_contracts';
+
+	{{head "1_delayed_results"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("delayed_id", "bigint", {"default": "0"})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("contract", "string", {"default": "", "size":255})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("success", "bigint", {"default": "0"})
+		t.Column("result", "text", {"default": ""})
+		t.Column("fuel", "bigint", {"default": "0"})
+		t.Column("callback", "string", {"default": "", "size":255})
+		t.Column("callback_error", "text", {"default": ""})
+	{{footer "primary" "index(delayed_id)"}}
+
+INSERT INTO "1_tables" ("id", "name", "permissions", "columns", "conditions") VALUES
+	(next_id('1_tables'), 'delayed_results',
+		'{"insert": "false", "update": "false", "new_column": "ContractConditions(\"@1MainCondition\")"}',
+		'{"delayed_id": "false", "key_id": "false", "contract": "false", "block_id": "false", "tx_hash": "false",
+		"success": "false", "result": "false", "fuel": "false", "callback": "false", "callback_error": "false"}',
+		'ContractConditions("@1MainCondition")');
+`
+
+var MigrationUpdateBlockTxData = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "tx_data" bytea;
+`
+
+var MigrationUpdateBaseGasPrice = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "base_gas_price" bigint NOT NULL DEFAULT '0';
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "gas_used" bigint NOT NULL DEFAULT '0';
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'min_base_gas_price', '100000', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'max_base_gas_price', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateDerivedKeys = `
+ALTER TABLE "1_keys" ADD COLUMN IF NOT EXISTS "parent_key_id" bigint NOT NULL DEFAULT '0';
+ALTER TABLE "1_keys" ADD COLUMN IF NOT EXISTS "derivation_index" bigint NOT NULL DEFAULT '0';
+CREATE INDEX IF NOT EXISTS "1_keys_ecosystem_parent_key_id_idx" ON "1_keys" (ecosystem, parent_key_id);
+UPDATE "1_tables" SET columns = columns || '{"parent_key_id": "false", "derivation_index": "false"}'::jsonb WHERE name = 'keys';
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'access_exec_set_key_parent', 'ContractAccess("@1SetKeyParent")', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateMaxTxRollbacks = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'max_tx_rollbacks', '100000', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateKeyRotationBlocks = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'key_rotation_blocks', '100', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateBlockFormatVersion = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'block_format_version', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateConfidentialUTXO = `
+ALTER TABLE "spent_info" ADD COLUMN IF NOT EXISTS "utxo_commitments" bytea;
+`
+
+var MigrationUpdateBlockSignatures = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'block_signature_threshold', '0', 'ContractAccess("@1UpdatePlatformParam")'),
+	(next_id('1_platform_parameters'), 'block_validators', '', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateDelayedOrigin = `
+ALTER TABLE "1_delayed_contracts" ADD COLUMN IF NOT EXISTS "origin_tx_hash" bytea NOT NULL DEFAULT '';
+ALTER TABLE "1_delayed_contracts" ADD COLUMN IF NOT EXISTS "origin_block_id" bigint NOT NULL DEFAULT '0';
+CREATE INDEX IF NOT EXISTS "1_delayed_contracts_origin_tx_hash_idx" ON "1_delayed_contracts" (origin_tx_hash);
+UPDATE "1_tables" SET columns = columns || '{"origin_tx_hash": "false", "origin_block_id": "false"}'::jsonb WHERE name = 'delayed_contracts';
+`
+
+var MigrationUpdateHeartbeatBlocks = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'heartbeat_blocks', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateCheckpointInterval = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'checkpoint_interval', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateRandomBeacon = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "random_beacon" bytea;
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "random_commit" bytea;
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'random_beacon', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateBlockCompression = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'block_compression', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateStateRoot = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "state_leaves" bytea;
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'state_root', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateEventsBloom = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "events_bloom" bytea;
+ALTER TABLE "contract_events" ADD COLUMN IF NOT EXISTS "key_id" bigint NOT NULL DEFAULT '0';
+CREATE INDEX IF NOT EXISTS "contract_events_index_key_id" ON "contract_events" (key_id, block_id);
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'events_bloom', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateSavepointBatch = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'savepoint_batch', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateBlockPruning = `
+ALTER TABLE "block_chain" ADD COLUMN IF NOT EXISTS "pruned" boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS "block_chain_pruned_idx" ON "block_chain" (id) WHERE pruned;
+`
+
+var MigrationUpdateMaxPastBlockAge = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'max_past_block_age', '1800', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateMinPoWBits = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'min_pow_bits', '20', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateStrictBlockTime = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'strict_block_time', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
+
+var MigrationUpdateTxTypeOrder = `
+INSERT INTO "1_platform_parameters" (id, name, value, conditions) VALUES
+	(next_id('1_platform_parameters'), 'tx_type_order', '0', 'ContractAccess("@1UpdatePlatformParam")');
+`
@@ -0,0 +1,14 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateBlockFeeStats = `
+	{{head "block_fee_stats"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_type", "integer", {"default": "0"})
+		t.Column("tx_count", "integer", {"default": "0"})
+		t.Column("gas_prices", "text", {"default": ""})
+	{{footer "primary(block_id,tx_type)"}}
+`
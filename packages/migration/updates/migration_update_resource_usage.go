@@ -0,0 +1,19 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateResourceUsage = `
+	{{head "resource_usage"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("contract_name", "string", {"default": "", "size":255})
+		t.Column("ecosystem_id", "bigint", {"default": "0"})
+		t.Column("cpu_ns", "bigint", {"default": "0"})
+		t.Column("db_write_bytes", "bigint", {"default": "0"})
+		t.Column("mem_peak_bytes", "bigint", {"default": "0"})
+		t.Column("gas_used", "bigint", {"default": "0"})
+		t.Column("recorded_at", "timestamptz", {"default_raw": "now()"})
+	{{footer "primary(block_id,tx_hash)" "index(ecosystem_id, contract_name, recorded_at)" "index(recorded_at)"}}
+`
@@ -0,0 +1,14 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateRewardDestinations = `
+	{{head "1_reward_destinations"}}
+		t.Column("id", "bigint", {"default": "0"})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("destination", "bigint", {"default": "0"})
+		t.Column("block_id", "bigint", {"default": "0"})
+	{{footer "primary" "index(key_id, block_id)"}}
+`
@@ -0,0 +1,19 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateServiceKeys = `
+	{{head "service_keys"}}
+		t.Column("name", "string", {"default": "", "size":255})
+		t.Column("key_hash", "bytea", {"default": ""})
+		t.Column("permission", "string", {"default": "", "size":32})
+		t.Column("allowed_ips", "text", {"default": ""})
+		t.Column("key_id", "bigint", {"default": "0"})
+		t.Column("ecosystem_id", "bigint", {"default": "1"})
+		t.Column("created_at", "timestamptz", {"default_raw": "now()"})
+		t.Column("last_used_at", "timestamptz", {"null": true})
+		t.Column("revoked_at", "timestamptz", {"null": true})
+	{{footer "primary(name)" "unique(key_hash)"}}
+`
@@ -0,0 +1,14 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateStateDiffs = `
+	add_column("block_chain", "bin_log_sql", "bytea", {"null": true})
+	{{head "block_state_diffs"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("block_hash", "bytea", {"default": ""})
+		t.Column("diffs", "jsonb", {"default": "[]"})
+	{{footer "primary(block_id)"}}
+`
@@ -0,0 +1,13 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateSysparSnapshots = `
+	{{head "syspar_snapshots"}}
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("node_key_id", "bigint", {"default": "0"})
+		t.Column("imported_at", "timestamptz", {"default_raw": "now()"})
+	{{footer "primary(block_id)"}}
+`
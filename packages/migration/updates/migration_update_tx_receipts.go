@@ -0,0 +1,18 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package updates
+
+var MigrationUpdateTxReceipts = `
+	{{head "tx_receipts"}}
+		t.Column("tx_hash", "bytea", {"default": ""})
+		t.Column("block_id", "bigint", {"default": "0"})
+		t.Column("ecosystem_id", "bigint", {"default": "0"})
+		t.Column("contract_name", "string", {"default": "", "size":255})
+		t.Column("fuel", "bigint", {"default": "0"})
+		t.Column("rows_touched", "bigint", {"default": "0"})
+		t.Column("events", "bigint", {"default": "0"})
+		t.Column("status", "bigint", {"default": "0"})
+	{{footer "primary(tx_hash)" "index(block_id)"}}
+`
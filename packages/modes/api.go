@@ -10,6 +10,7 @@ import (
 
 	"github.com/IBAX-io/go-ibax/packages/api"
 	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/daemons"
 )
 
 func RegisterRoutes() http.Handler {
@@ -17,6 +18,7 @@ func RegisterRoutes() http.Handler {
 		EcosystemGetter:   GetEcosystemGetter(),
 		ContractRunner:    GetSmartContractRunner(),
 		ClientTxProcessor: GetClientTxPreprocessor(),
+		BlockAssembler:    daemons.BlockAssembler{},
 	}
 
 	r := api.NewRouter(m)
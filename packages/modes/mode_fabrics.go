@@ -105,6 +105,11 @@ func (BCDaemonFactory) GetDaemonsList() []string {
 		"Confirmations",
 		"Scheduler",
 		"CandidateNodeVoting",
+		"Oracle",
+		"Cleanup",
+		"PriorityInversion",
+		"Checkpoints",
+		"Pruning",
 		//"ExternalNetwork",
 	}
 }
@@ -0,0 +1,205 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package network
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrChunkOffset is returned when the chunk isn't at the offset of the received part of the block
+var ErrChunkOffset = errors.New("wrong offset of the block chunk")
+
+// GetChunkedBodiesRequest is GetBodiesRequest of the client which downloads the blocks larger than
+// ChunkSize by chunks, such blocks are sent as BlockRef instead of the body
+type GetChunkedBodiesRequest struct {
+	GetBodiesRequest
+	ChunkSize int64
+}
+
+func (req *GetChunkedBodiesRequest) Read(r io.Reader) error {
+	if err := req.GetBodiesRequest.Read(r); err != nil {
+		return err
+	}
+	return binary.Read(r, binary.LittleEndian, &req.ChunkSize)
+}
+
+func (req *GetChunkedBodiesRequest) Write(w io.Writer) error {
+	if err := req.GetBodiesRequest.Write(w); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, req.ChunkSize)
+}
+
+// ChunkedBody is the item of the response to GetChunkedBodiesRequest, it's either the body
+// of the block or the reference to download it by chunks
+type ChunkedBody struct {
+	Data []byte
+	Ref  *BlockRef
+}
+
+func (b *ChunkedBody) Read(r io.Reader) error {
+	isRef, err := readBool(r)
+	if err != nil {
+		return err
+	}
+	if isRef {
+		b.Data, b.Ref = nil, &BlockRef{}
+		return b.Ref.Read(r)
+	}
+	b.Ref = nil
+	b.Data, err = ReadSlice(r)
+	return err
+}
+
+func (b *ChunkedBody) Write(w io.Writer) error {
+	if err := writeBool(w, b.Ref != nil); err != nil {
+		return err
+	}
+	if b.Ref != nil {
+		return b.Ref.Write(w)
+	}
+	return writeSlice(w, b.Data)
+}
+
+// BlockRef is the hash and the size of the block which is downloaded by chunks
+type BlockRef struct {
+	Hash []byte
+	Size int64
+}
+
+func (ref *BlockRef) Read(r io.Reader) error {
+	hash, err := ReadSliceWithMaxSize(r, consts.HashSize)
+	if err != nil {
+		return err
+	}
+	ref.Hash = hash
+	return binary.Read(r, binary.LittleEndian, &ref.Size)
+}
+
+func (ref *BlockRef) Write(w io.Writer) error {
+	if err := writeSlice(w, ref.Hash); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, ref.Size)
+}
+
+// GetBlockChunksRequest requests the chunks of the block with the hash starting from Offset
+type GetBlockChunksRequest struct {
+	Hash      []byte
+	Offset    int64
+	ChunkSize int64
+}
+
+func (req *GetBlockChunksRequest) Read(r io.Reader) error {
+	hash, err := ReadSliceWithMaxSize(r, consts.HashSize)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading GetBlockChunksRequest hash")
+		return err
+	}
+	req.Hash = hash
+	if err = binary.Read(r, binary.LittleEndian, &req.Offset); err != nil {
+		return err
+	}
+	return binary.Read(r, binary.LittleEndian, &req.ChunkSize)
+}
+
+func (req *GetBlockChunksRequest) Write(w io.Writer) error {
+	if err := writeSlice(w, req.Hash); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending GetBlockChunksRequest hash")
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, req.Offset); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, req.ChunkSize)
+}
+
+// BlockChunksHeader precedes the chunks, Checksum is sha256 of the whole block. Size is zero
+// if the node doesn't have the block
+type BlockChunksHeader struct {
+	Size     int64
+	Checksum []byte
+}
+
+func (h *BlockChunksHeader) Read(r io.Reader) error {
+	if err := binary.Read(r, binary.LittleEndian, &h.Size); err != nil {
+		return err
+	}
+	h.Checksum = make([]byte, sha256.Size)
+	_, err := io.ReadFull(r, h.Checksum)
+	return err
+}
+
+func (h *BlockChunksHeader) Write(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, h.Size); err != nil {
+		return err
+	}
+	checksum := make([]byte, sha256.Size)
+	copy(checksum, h.Checksum)
+	_, err := w.Write(checksum)
+	return err
+}
+
+// BlockChunk is the part of the block at Offset, the empty chunk ends the block
+type BlockChunk struct {
+	Offset int64
+	Data   []byte
+}
+
+func (c *BlockChunk) Read(r io.Reader) error {
+	return c.ReadWithMaxSize(r, syspar.GetMaxBlockSizeLimit())
+}
+
+// ReadWithMaxSize reads the chunk which isn't greater than maxSize
+func (c *BlockChunk) ReadWithMaxSize(r io.Reader, maxSize int64) error {
+	if err := binary.Read(r, binary.LittleEndian, &c.Offset); err != nil {
+		return err
+	}
+	data, err := ReadSliceWithMaxSize(r, uint64(maxSize))
+	c.Data = data
+	return err
+}
+
+func (c *BlockChunk) Write(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, c.Offset); err != nil {
+		return err
+	}
+	return writeSlice(w, c.Data)
+}
+
+// WriteBlockChunks writes the header and the chunks of data starting from offset
+func WriteBlockChunks(w io.Writer, data []byte, offset, chunkSize int64) error {
+	size := int64(len(data))
+	if offset < 0 || offset > size {
+		return fmt.Errorf("%w: %d of %d bytes", ErrChunkOffset, offset, size)
+	}
+	if chunkSize <= 0 {
+		chunkSize = consts.BlockChunkSize
+	}
+	checksum := sha256.Sum256(data)
+	if err := (&BlockChunksHeader{Size: size, Checksum: checksum[:]}).Write(w); err != nil {
+		return err
+	}
+	for offset < size {
+		end := offset + chunkSize
+		if end > size {
+			end = size
+		}
+		if err := (&BlockChunk{Offset: offset, Data: data[offset:end]}).Write(w); err != nil {
+			return err
+		}
+		offset = end
+	}
+	return (&BlockChunk{Offset: size}).Write(w)
+}
@@ -0,0 +1,134 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package network
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrWrongHashes = errors.New("wrong transactions hashes size")
+
+// KnownTxs contains the hashes of transactions which mustn't be requested from the peers,
+// they are received, requested, included in the blocks or marked as bad
+var KnownTxs = NewSeenSet(consts.TxSeenTTL, consts.TxSeenSize)
+
+type seenItem struct {
+	key    string
+	expire time.Time
+}
+
+// SeenSet is the rolling set of hashes, the hash is forgotten after ttl or
+// if the set is larger than size
+type SeenSet struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	size  int
+	items map[string]*list.Element
+	order *list.List
+	now   func() time.Time
+}
+
+// NewSeenSet returns the empty set
+func NewSeenSet(ttl time.Duration, size int) *SeenSet {
+	return &SeenSet{
+		ttl:   ttl,
+		size:  size,
+		items: make(map[string]*list.Element),
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+// prune removes the expired and the oldest hashes, the items are ordered by expiration
+func (s *SeenSet) prune(now time.Time) {
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		item := e.Value.(*seenItem)
+		if len(s.items) <= s.size && now.Before(item.expire) {
+			return
+		}
+		s.order.Remove(e)
+		delete(s.items, item.key)
+	}
+}
+
+// Add adds the hash and returns false if the set already contains it
+func (s *SeenSet) Add(hash []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.prune(now)
+	if _, ok := s.items[string(hash)]; ok {
+		return false
+	}
+	item := &seenItem{key: string(hash), expire: now.Add(s.ttl)}
+	s.items[item.key] = s.order.PushBack(item)
+	s.prune(now)
+	return true
+}
+
+// Has returns true if the set contains the hash
+func (s *SeenSet) Has(hash []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(s.now())
+	_, ok := s.items[string(hash)]
+	return ok
+}
+
+// Remove forgets the hash, e.g. if the requested transaction hasn't been received
+func (s *SeenSet) Remove(hash []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[string(hash)]; ok {
+		s.order.Remove(e)
+		delete(s.items, string(hash))
+	}
+}
+
+// Len returns the count of hashes
+func (s *SeenSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(s.now())
+	return len(s.items)
+}
+
+// TxInventory contains the hashes of transactions, it is used both for the announcement
+// and for the request of unknown transactions
+type TxInventory struct {
+	Hashes [][]byte
+}
+
+func (inv *TxInventory) Read(r io.Reader) error {
+	slice, err := ReadSliceWithMaxSize(r, uint64(consts.TxInventoryMaxHashes*consts.HashSize))
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading tx inventory")
+		return err
+	}
+	if len(slice)%consts.HashSize != 0 {
+		log.WithFields(log.Fields{"type": consts.ProtocolError, "size": len(slice)}).Error("incorrect hashes length")
+		return ErrWrongHashes
+	}
+
+	inv.Hashes = make([][]byte, 0, len(slice)/consts.HashSize)
+	for len(slice) > 0 {
+		inv.Hashes = append(inv.Hashes, slice[:consts.HashSize])
+		slice = slice[consts.HashSize:]
+	}
+	return nil
+}
+
+func (inv *TxInventory) Write(w io.Writer) error {
+	return writeSlice(w, bytes.Join(inv.Hashes, nil))
+}
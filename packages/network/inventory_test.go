@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package network
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeenSet(t *testing.T) {
+	now := time.Now()
+	s := NewSeenSet(time.Minute, 3)
+	s.now = func() time.Time { return now }
+
+	require.True(t, s.Add([]byte("a")))
+	require.False(t, s.Add([]byte("a")))
+	require.True(t, s.Add([]byte("b")))
+
+	// the hashes expire after ttl
+	now = now.Add(30 * time.Second)
+	require.True(t, s.Add([]byte("c")))
+	now = now.Add(40 * time.Second)
+	require.False(t, s.Has([]byte("a")))
+	require.True(t, s.Has([]byte("c")))
+	require.True(t, s.Add([]byte("a")))
+
+	// the oldest hashes are dropped if the set is full
+	require.True(t, s.Add([]byte("d")))
+	require.True(t, s.Add([]byte("e")))
+	require.Equal(t, 3, s.Len())
+	require.False(t, s.Has([]byte("c")))
+
+	s.Remove([]byte("e"))
+	require.False(t, s.Has([]byte("e")))
+	require.Equal(t, 2, s.Len())
+}
+
+func TestTxInventory(t *testing.T) {
+	inv := TxInventory{Hashes: [][]byte{
+		bytes.Repeat([]byte{1}, consts.HashSize),
+		bytes.Repeat([]byte{2}, consts.HashSize),
+	}}
+	b := &bytes.Buffer{}
+	require.NoError(t, inv.Write(b))
+	result := TxInventory{}
+	require.NoError(t, result.Read(b))
+	require.Equal(t, inv, result)
+
+	require.NoError(t, writeSlice(b, []byte("short")))
+	require.Equal(t, ErrWrongHashes, result.Read(b))
+}
@@ -0,0 +1,87 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Peers contains the last known state of the peers, it's updated by their messages
+var Peers = NewPeerSet()
+
+// PeerState is the last block of the peer which is known from its messages
+type PeerState struct {
+	PeerID        string
+	BlockID       int64
+	BlockHash     []byte
+	LastMessageAt time.Time
+}
+
+// PeerSet is the state of the peers by their tcp address
+type PeerSet struct {
+	mu    sync.Mutex
+	peers map[string]*PeerState
+	now   func() time.Time
+}
+
+// NewPeerSet returns the empty set
+func NewPeerSet() *PeerSet {
+	return &PeerSet{
+		peers: make(map[string]*PeerState),
+		now:   time.Now,
+	}
+}
+
+func (s *PeerSet) get(peer string) *PeerState {
+	state, ok := s.peers[peer]
+	if !ok {
+		state = &PeerState{PeerID: peer}
+		s.peers[peer] = state
+	}
+	state.LastMessageAt = s.now()
+	return state
+}
+
+// SetBlock records the block announced by the peer
+func (s *PeerSet) SetBlock(peer string, blockID int64, hash []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.get(peer)
+	if blockID < state.BlockID {
+		return
+	}
+	state.BlockID, state.BlockHash = blockID, append([]byte(nil), hash...)
+}
+
+// SetBlockID records the max block of the peer, the hash is kept if the block isn't changed
+func (s *PeerSet) SetBlockID(peer string, blockID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.get(peer)
+	if blockID != state.BlockID {
+		state.BlockID, state.BlockHash = blockID, nil
+	}
+}
+
+// Seen records the message of the peer without the block
+func (s *PeerSet) Seen(peer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(peer)
+}
+
+// List returns the states of the peers ordered by their id
+func (s *PeerSet) List() []PeerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]PeerState, 0, len(s.peers))
+	for _, state := range s.peers {
+		list = append(list, *state)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PeerID < list[j].PeerID })
+	return list
+}
@@ -0,0 +1,43 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package network
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestPeerSet(t *testing.T) {
+	s := NewPeerSet()
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	s.SetBlock("b:7078", 10, []byte{10})
+	s.SetBlockID("a:7078", 5)
+	now = now.Add(time.Second)
+	// the late announcement of the previous block doesn't move the peer back
+	s.SetBlock("b:7078", 9, []byte{9})
+	// the max block request keeps the hash of the announced block
+	s.SetBlockID("b:7078", 10)
+
+	list := s.List()
+	if len(list) != 2 || list[0].PeerID != "a:7078" || list[1].PeerID != "b:7078" {
+		t.Fatalf("wrong peers %+v", list)
+	}
+	if b := list[1]; b.BlockID != 10 || !bytes.Equal(b.BlockHash, []byte{10}) || !b.LastMessageAt.Equal(now) {
+		t.Errorf("wrong peer state %+v", b)
+	}
+
+	s.SetBlockID("b:7078", 11)
+	s.Seen("a:7078")
+	list = s.List()
+	if list[1].BlockID != 11 || list[1].BlockHash != nil {
+		t.Errorf("wrong peer state %+v", list[1])
+	}
+	if list[0].BlockID != 5 || !list[0].LastMessageAt.Equal(now) {
+		t.Errorf("wrong peer state %+v", list[0])
+	}
+}
@@ -29,6 +29,12 @@ const (
 	RequestTypeMaxBlock
 	RequestTypeVoting
 	RequestSyncMatchineState
+	RequestTypeTxInventory
+	RequestTypeBlockCollectionChunked
+	RequestTypeBlockChunks
+	RequestTypeKeyRotation
+	RequestTypeSignBlock
+	RequestTypeSignCheckpoint
 
 	// BlocksPerRequest contains count of blocks per request
 	BlocksPerRequest int = 10
@@ -245,6 +251,137 @@ func (resp *StopNetworkResponse) Write(w io.Writer) error {
 	return writeSlice(w, resp.Hash)
 }
 
+// KeyRotationRequest is the transaction of the rotation of the node key
+type KeyRotationRequest struct {
+	Data []byte
+}
+
+func (req *KeyRotationRequest) Read(r io.Reader) error {
+	slice, err := ReadSlice(r)
+	if err != nil {
+		return err
+	}
+
+	req.Data = slice
+	return nil
+}
+
+func (req *KeyRotationRequest) Write(w io.Writer) error {
+	return writeSlice(w, req.Data)
+}
+
+// KeyRotationResponse is the hash of the accepted key rotation transaction
+type KeyRotationResponse struct {
+	Hash []byte
+}
+
+func (resp *KeyRotationResponse) Read(r io.Reader) error {
+	slice, err := ReadSlice(r)
+	if err != nil {
+		return err
+	}
+
+	resp.Hash = slice
+	return nil
+}
+
+func (resp *KeyRotationResponse) Write(w io.Writer) error {
+	return writeSlice(w, resp.Hash)
+}
+
+// BlockSignRequest is the generated block which is signed by the validator
+type BlockSignRequest struct {
+	Data []byte
+}
+
+func (req *BlockSignRequest) Read(r io.Reader) error {
+	slice, err := ReadSliceWithMaxSize(r, uint64(syspar.GetMaxBlockSizeLimit()))
+	if err != nil {
+		return err
+	}
+
+	req.Data = slice
+	return nil
+}
+
+func (req *BlockSignRequest) Write(w io.Writer) error {
+	return writeSlice(w, req.Data)
+}
+
+// BlockSignResponse is the signature of the block by the validator
+type BlockSignResponse struct {
+	KeyID     int64
+	Signature []byte
+}
+
+func (resp *BlockSignResponse) Read(r io.Reader) error {
+	if err := binary.Read(r, binary.LittleEndian, &resp.KeyID); err != nil {
+		return err
+	}
+	slice, err := ReadSlice(r)
+	if err != nil {
+		return err
+	}
+
+	resp.Signature = slice
+	return nil
+}
+
+func (resp *BlockSignResponse) Write(w io.Writer) error {
+	if err := binary.Write(w, binary.LittleEndian, resp.KeyID); err != nil {
+		return err
+	}
+	return writeSlice(w, resp.Signature)
+}
+
+// CheckpointSignRequest is the block of the checkpoint which is attested by the honor node
+type CheckpointSignRequest struct {
+	BlockID int64
+}
+
+func (req *CheckpointSignRequest) Read(r io.Reader) error {
+	return binary.Read(r, binary.LittleEndian, &req.BlockID)
+}
+
+func (req *CheckpointSignRequest) Write(w io.Writer) error {
+	return binary.Write(w, binary.LittleEndian, req.BlockID)
+}
+
+// CheckpointSignResponse is the attestation of the block of the checkpoint by the honor node
+type CheckpointSignResponse struct {
+	BlockHash []byte
+	NodeKeyID int64
+	Timestamp int64
+	Signature []byte
+}
+
+func (resp *CheckpointSignResponse) Read(r io.Reader) (err error) {
+	if resp.BlockHash, err = ReadSlice(r); err != nil {
+		return err
+	}
+	if err = binary.Read(r, binary.LittleEndian, &resp.NodeKeyID); err != nil {
+		return err
+	}
+	if err = binary.Read(r, binary.LittleEndian, &resp.Timestamp); err != nil {
+		return err
+	}
+	resp.Signature, err = ReadSlice(r)
+	return err
+}
+
+func (resp *CheckpointSignResponse) Write(w io.Writer) error {
+	if err := writeSlice(w, resp.BlockHash); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, resp.NodeKeyID); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, resp.Timestamp); err != nil {
+		return err
+	}
+	return writeSlice(w, resp.Signature)
+}
+
 func readBool(r io.Reader) (bool, error) {
 	var val uint8
 	if err := binary.Read(r, binary.LittleEndian, &val); err != nil {
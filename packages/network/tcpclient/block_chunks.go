@@ -0,0 +1,112 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// blockChunksAttempts is the number of the connections to every host to download the block
+const blockChunksAttempts = 3
+
+var (
+	// ErrBlockChecksum is returned when the downloaded block differs from the checksum of the host
+	ErrBlockChecksum  = errors.New("checksum of the block is wrong")
+	errBlockNotExists = errors.New("host doesn't have the block")
+)
+
+// blockDownload is the received part of the block, it's kept between the connections
+type blockDownload struct {
+	ref       *network.BlockRef
+	chunkSize int64
+	checksum  []byte
+	data      []byte
+}
+
+// DownloadBlock downloads the block by chunks from the hosts holding the block with the hash. After
+// the broken connection the download is resumed from the received offset with the fresh connection to
+// the same host or the next one, the received chunks are never requested again unless the whole block
+// doesn't match the checksum
+func DownloadBlock(ctx context.Context, hosts []string, ref *network.BlockRef, chunkSize int64) ([]byte, error) {
+	if len(hosts) == 0 {
+		return nil, wrongAddressError
+	}
+	d := &blockDownload{ref: ref, chunkSize: chunkSize, data: make([]byte, 0, ref.Size)}
+	var err error
+	for i := 0; i < len(hosts)*blockChunksAttempts; i++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		host := hosts[i%len(hosts)]
+		if err = d.resume(host); err == nil {
+			return d.data, nil
+		}
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "host": host, "offset": len(d.data),
+			"size": ref.Size}).Warn("resuming block download")
+	}
+	return nil, err
+}
+
+// resume requests the chunks starting from the end of the received data
+func (d *blockDownload) resume(host string) error {
+	conn, err := newConnection(host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rt := &network.RequestType{Type: network.RequestTypeBlockChunks}
+	if err = rt.Write(conn); err != nil {
+		return err
+	}
+	req := &network.GetBlockChunksRequest{Hash: d.ref.Hash, Offset: int64(len(d.data)), ChunkSize: d.chunkSize}
+	if err = req.Write(conn); err != nil {
+		return err
+	}
+
+	header := &network.BlockChunksHeader{}
+	if err = header.Read(conn); err != nil {
+		return err
+	}
+	if header.Size == 0 {
+		return errBlockNotExists
+	}
+	if header.Size != d.ref.Size || (d.checksum != nil && !bytes.Equal(d.checksum, header.Checksum)) {
+		// the host has the other payload with the hash, the received part isn't used with it
+		return fmt.Errorf("%w: host has the other block of %d bytes", ErrBlockChecksum, header.Size)
+	}
+	d.checksum = header.Checksum
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(consts.ReadTimeout * time.Second))
+		chunk := &network.BlockChunk{}
+		if err = chunk.ReadWithMaxSize(conn, d.ref.Size-int64(len(d.data))); err != nil {
+			return err
+		}
+		if chunk.Offset != int64(len(d.data)) {
+			return fmt.Errorf("%w: %d instead of %d", network.ErrChunkOffset, chunk.Offset, len(d.data))
+		}
+		if len(chunk.Data) == 0 {
+			break
+		}
+		d.data = append(d.data, chunk.Data...)
+	}
+
+	if sum := sha256.Sum256(d.data); int64(len(d.data)) != d.ref.Size || !bytes.Equal(sum[:], d.checksum) {
+		d.data, d.checksum = d.data[:0], nil
+		return ErrBlockChecksum
+	}
+	return nil
+}
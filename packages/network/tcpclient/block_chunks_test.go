@@ -0,0 +1,145 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/network"
+)
+
+// chunksServer serves the block by chunks, the first broken connections are closed
+// after the header and the limited count of the chunks
+type chunksServer struct {
+	listener  net.Listener
+	data      []byte
+	chunkSize int64
+	broken    int
+
+	mu      sync.Mutex
+	offsets []int64
+	served  int64
+}
+
+func newChunksServer(t *testing.T, data []byte, chunkSize int64, broken int) *chunksServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &chunksServer{listener: l, data: data, chunkSize: chunkSize, broken: broken}
+	t.Cleanup(func() { l.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *chunksServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.handle(conn)
+	}
+}
+
+func (s *chunksServer) handle(conn net.Conn) {
+	defer conn.Close()
+	rt := &network.RequestType{}
+	req := &network.GetBlockChunksRequest{}
+	if rt.Read(conn) != nil || rt.Type != network.RequestTypeBlockChunks || req.Read(conn) != nil {
+		return
+	}
+	data := s.data[req.Offset:]
+	s.mu.Lock()
+	s.offsets = append(s.offsets, req.Offset)
+	broken := len(s.offsets) <= s.broken
+	if broken && 2*s.chunkSize < int64(len(data)) {
+		// the connection is killed after two chunks
+		data = data[:2*s.chunkSize]
+	}
+	s.served += int64(len(data))
+	s.mu.Unlock()
+
+	if broken {
+		checksum := sha256.Sum256(s.data)
+		(&network.BlockChunksHeader{Size: int64(len(s.data)), Checksum: checksum[:]}).Write(conn)
+		for i := int64(0); i < int64(len(data)); i += s.chunkSize {
+			end := i + s.chunkSize
+			if end > int64(len(data)) {
+				end = int64(len(data))
+			}
+			(&network.BlockChunk{Offset: req.Offset + i, Data: data[i:end]}).Write(conn)
+		}
+	} else {
+		network.WriteBlockChunks(conn, s.data, req.Offset, s.chunkSize)
+	}
+}
+
+func (s *chunksServer) stats() ([]int64, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int64{}, s.offsets...), s.served
+}
+
+func TestDownloadBlockResume(t *testing.T) {
+	const chunkSize = 64
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	sum := sha256.Sum256(data)
+	ref := &network.BlockRef{Hash: sum[:], Size: int64(len(data))}
+
+	first := newChunksServer(t, data, chunkSize, 1)
+	second := newChunksServer(t, data, chunkSize, 0)
+	got, err := DownloadBlock(context.Background(), []string{first.listener.Addr().String(),
+		second.listener.Addr().String()}, ref, chunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded block differs")
+	}
+	// the first host breaks the connection, the download is resumed from the second host after two chunks
+	firstOffsets, firstServed := first.stats()
+	secondOffsets, secondServed := second.stats()
+	if len(firstOffsets) != 1 || firstOffsets[0] != 0 {
+		t.Errorf("wrong offsets of the first host %v", firstOffsets)
+	}
+	if len(secondOffsets) != 1 || secondOffsets[0] != 2*chunkSize {
+		t.Errorf("wrong offsets of the second host %v", secondOffsets)
+	}
+	if served := firstServed + secondServed; served != int64(len(data)) {
+		t.Errorf("expected %d bytes to be served, got %d", len(data), served)
+	}
+}
+
+func TestDownloadBlockOtherChecksum(t *testing.T) {
+	const chunkSize = 64
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	other := bytes.Repeat([]byte("9876543210"), 100)
+	sum := sha256.Sum256(data)
+	ref := &network.BlockRef{Hash: sum[:], Size: int64(len(data))}
+
+	// the first host always breaks the connection
+	first := newChunksServer(t, data, chunkSize, 100)
+	second := newChunksServer(t, other, chunkSize, 0)
+	_, err := DownloadBlock(context.Background(), []string{first.listener.Addr().String(),
+		second.listener.Addr().String()}, ref, chunkSize)
+	if !errors.Is(err, ErrBlockChecksum) {
+		t.Errorf("expected %v, got %v", ErrBlockChecksum, err)
+	}
+	// the download isn't restarted with the other block, it goes on from the first host
+	offsets, _ := first.stats()
+	for i, offset := range offsets {
+		if offset != int64(i)*2*chunkSize {
+			t.Errorf("wrong offsets of the first host %v", offsets)
+			break
+		}
+	}
+}
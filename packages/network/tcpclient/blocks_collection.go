@@ -11,8 +11,11 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/types"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -25,7 +28,8 @@ const hasntVal = "has not value"
 
 const sizeBytesLength = 4
 
-// GetBlocksBodies send GetBodiesRequest returns channel of binary blocks data
+// GetBlocksBodies send GetBodiesRequest returns channel of binary blocks data. The blocks larger than
+// the chunk size are downloaded by chunks from the host or the other nodes
 func GetBlocksBodies(ctx context.Context, host string, blockID int64, reverseOrder bool) (<-chan []byte, error) {
 	conn, err := newConnection(host)
 	if err != nil {
@@ -33,15 +37,22 @@ func GetBlocksBodies(ctx context.Context, host string, blockID int64, reverseOrd
 	}
 
 	// send the type of data
-	rt := &network.RequestType{Type: network.RequestTypeBlockCollection}
+	rt := &network.RequestType{Type: network.RequestTypeBlockCollectionChunked}
 	if err = rt.Write(conn); err != nil {
 		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("writing data type block body to connection")
 		return nil, err
 	}
 
-	req := &network.GetBodiesRequest{
-		BlockID:      uint32(blockID),
-		ReverseOrder: reverseOrder,
+	chunkSize := conf.Config.BlockChunkSize
+	if chunkSize <= 0 {
+		chunkSize = consts.BlockChunkSize
+	}
+	req := &network.GetChunkedBodiesRequest{
+		GetBodiesRequest: network.GetBodiesRequest{
+			BlockID:      uint32(blockID),
+			ReverseOrder: reverseOrder,
+		},
+		ChunkSize: chunkSize,
 	}
 
 	if err = req.Write(conn); err != nil {
@@ -59,7 +70,13 @@ func GetBlocksBodies(ctx context.Context, host string, blockID int64, reverseOrd
 		return nil, fmt.Errorf("host: %s does'nt contains blocks", host)
 	}
 
-	blocksChan, errChan := GetBlockBodiesChan(ctx, conn, blocksCount)
+	hosts := []string{host}
+	for _, h := range conf.GetNodesAddr() {
+		if h != host {
+			hosts = append(hosts, h)
+		}
+	}
+	blocksChan, errChan := GetChunkedBodiesChan(ctx, conn, blocksCount, hosts, chunkSize)
 	go func() {
 		for err := range errChan {
 			if err != nil {
@@ -71,6 +88,47 @@ func GetBlocksBodies(ctx context.Context, host string, blockID int64, reverseOrd
 	return blocksChan, nil
 }
 
+// GetChunkedBodiesChan reads the response to GetChunkedBodiesRequest, the referenced blocks are
+// downloaded by chunks from the hosts
+func GetChunkedBodiesChan(ctx context.Context, src io.ReadCloser, blocksCount int64, hosts []string, chunkSize int64) (<-chan []byte, <-chan error) {
+	rawBlocksCh := make(chan []byte, blocksCount)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			close(rawBlocksCh)
+			close(errChan)
+			src.Close()
+		}()
+
+		for i := 0; i < int(blocksCount); i++ {
+			body := &network.ChunkedBody{}
+			if err := body.Read(src); err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on reading block body")
+				errChan <- err
+				return
+			}
+			data := body.Data
+			var err error
+			if body.Ref != nil {
+				if data, err = DownloadBlock(ctx, hosts, body.Ref, chunkSize); err != nil {
+					errChan <- err
+					return
+				}
+			}
+			if data, err = types.DecompressBlock(data, syspar.GetMaxBlockSize()); err != nil {
+				log.WithFields(log.Fields{"type": consts.ParserError, "error": err}).Error("on decompressing block body")
+				errChan <- err
+				return
+			}
+			rawBlocksCh <- data
+			errChan <- nil
+		}
+	}()
+
+	return rawBlocksCh, errChan
+}
+
 func GetBlockBodiesChan(ctx context.Context, src io.ReadCloser, blocksCount int64) (<-chan []byte, <-chan error) {
 	rawBlocksCh := make(chan []byte, blocksCount)
 	errChan := make(chan error, 1)
@@ -125,6 +183,11 @@ func GetBlockBodiesChan(ctx context.Context, src io.ReadCloser, blocksCount int6
 			}
 
 			bodyStartIndx = bodyEndIndx
+			if body, err = types.DecompressBlock(body, syspar.GetMaxBlockSize()); err != nil {
+				log.WithFields(log.Fields{"type": consts.ParserError, "error": err}).Error("on decompressing block body")
+				errChan <- err
+				return
+			}
 			rawBlocksCh <- body
 			errChan <- nil
 		}
@@ -125,24 +125,7 @@ func SendFullBlockToAll(ctx context.Context, hosts []string, block *sqldb.InfoBl
 				return
 			}
 
-			var buf bytes.Buffer
-			requestedHashes := parseTxHashesFromResponse(response)
-			for _, txhash := range requestedHashes {
-				if data, ok := txDataMap[string(txhash)]; ok && len(data) > 0 {
-					log.WithFields(log.Fields{"len_of_tx": len(data)}).Debug("on prepare full tx package")
-					if _, err := buf.Write(converter.EncodeLengthPlusData(data)); err != nil {
-						log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Warn("on write tx hash to response buffer")
-					}
-				}
-			}
-
-			if _, err = con.Write(converter.DecToBin(buf.Len(), 4)); err != nil {
-				increaseErrCount()
-				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": h}).Error("on writing requested transactions buf length")
-				return
-			}
-
-			if _, err = con.Write(buf.Bytes()); err != nil {
+			if err = writeRequestedTxs(con, parseTxHashesFromResponse(response), txDataMap); err != nil {
 				increaseErrCount()
 				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": h}).Error("on writing requested transactions")
 				return
@@ -160,6 +143,25 @@ func SendFullBlockToAll(ctx context.Context, hosts []string, block *sqldb.InfoBl
 	return nil
 }
 
+// writeRequestedTxs writes the bodies of the requested transactions with the total length
+func writeRequestedTxs(w io.Writer, requestedHashes [][]byte, txDataMap map[string][]byte) error {
+	var buf bytes.Buffer
+	for _, txhash := range requestedHashes {
+		if data, ok := txDataMap[string(txhash)]; ok && len(data) > 0 {
+			log.WithFields(log.Fields{"len_of_tx": len(data)}).Debug("on prepare full tx package")
+			if _, err := buf.Write(converter.EncodeLengthPlusData(data)); err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Warn("on write tx hash to response buffer")
+			}
+		}
+	}
+
+	if _, err := w.Write(converter.DecToBin(buf.Len(), 4)); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
 func sendFullBlockRequest(con net.Conn, data []byte) (response []byte, err error) {
 
 	if err := sendDisseminatorRequest(con, network.RequestTypeHonorNode, data); err != nil {
@@ -0,0 +1,170 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TxAnnouncer collects the new transactions and announces their hashes to the hosts in batches,
+// the hosts request the bodies of unknown transactions only
+type TxAnnouncer struct {
+	interval  time.Duration
+	announced *network.SeenSet
+
+	mu      sync.Mutex
+	pending map[string][]sqldb.Transaction
+}
+
+// NewTxAnnouncer returns the announcer which sends the batches every interval
+func NewTxAnnouncer(interval time.Duration) *TxAnnouncer {
+	return &TxAnnouncer{
+		interval:  interval,
+		announced: network.NewSeenSet(consts.TxSeenTTL, consts.TxSeenSize),
+		pending:   make(map[string][]sqldb.Transaction),
+	}
+}
+
+// Add queues the transactions for the hosts and returns the count of queued ones,
+// the transactions which have been announced already are skipped
+func (a *TxAnnouncer) Add(hosts []string, txes []sqldb.Transaction) int {
+	if len(hosts) == 0 {
+		return 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var count int
+	for _, tx := range txes {
+		if !a.announced.Add(tx.Hash) {
+			continue
+		}
+		count++
+		for _, h := range hosts {
+			a.pending[h] = append(a.pending[h], tx)
+		}
+	}
+	return count
+}
+
+// Flush announces the queued transactions to the hosts
+func (a *TxAnnouncer) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	pending := a.pending
+	a.pending = make(map[string][]sqldb.Transaction)
+	a.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var errCount int32
+	for h, txes := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func(host string, txes []sqldb.Transaction) {
+			defer wg.Done()
+
+			for len(txes) > 0 {
+				n := len(txes)
+				if n > consts.TxInventoryMaxHashes {
+					n = consts.TxInventoryMaxHashes
+				}
+				if err := announceTxsToHost(host, txes[:n]); err != nil {
+					atomic.AddInt32(&errCount, 1)
+					return
+				}
+				txes = txes[n:]
+			}
+		}(h, txes)
+	}
+
+	wg.Wait()
+
+	if int(errCount) == len(pending) {
+		return ErrNodesUnavailable
+	}
+	return nil
+}
+
+// Run flushes the announcements every interval until ctx is done
+func (a *TxAnnouncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Flush(ctx); err != nil {
+				log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on announcing transactions")
+			}
+		}
+	}
+}
+
+func announceTxsToHost(host string, txes []sqldb.Transaction) error {
+	con, err := newConnection(host)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "host": host}).Error("on creating tcp connection")
+		return err
+	}
+
+	defer con.Close()
+
+	inv := &network.TxInventory{Hashes: make([][]byte, 0, len(txes))}
+	txDataMap := make(map[string][]byte, len(txes))
+	for _, tx := range txes {
+		inv.Hashes = append(inv.Hashes, tx.Hash)
+		txDataMap[string(tx.Hash)] = tx.Data
+	}
+
+	rt := network.RequestType{Type: network.RequestTypeTxInventory}
+	if err = rt.Write(con); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("writing request type to host")
+		return err
+	}
+	if err = inv.Write(con); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("on sending tx inventory")
+		return err
+	}
+
+	need := &network.TxInventory{}
+	if err = need.Read(con); err != nil {
+		if err == io.EOF {
+			// the host doesn't support the inventory, the transactions are pushed as before
+			log.WithFields(log.Fields{"host": host}).Debug("tx inventory isn't supported, sending full transactions")
+			packet, err := MarshalTxPacket(txes)
+			if err != nil {
+				return err
+			}
+			return sendRawTransacitionsToHost(host, packet)
+		}
+		return err
+	}
+	if len(need.Hashes) == 0 {
+		return nil
+	}
+
+	if err = writeRequestedTxs(con, need.Hashes, txDataMap); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "host": host}).Error("on writing requested transactions")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+)
+
+// SendKeyRotation sends the key rotation transaction to the node, the node adds it to the queue
+func SendKeyRotation(addr string, req *network.KeyRotationRequest) ([]byte, error) {
+	conn, err := newConnection(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rt := &network.RequestType{
+		Type: network.RequestTypeKeyRotation,
+	}
+
+	if err = rt.Write(conn); err != nil {
+		return nil, err
+	}
+
+	if err = req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	res := &network.KeyRotationResponse{}
+	if err = res.Read(conn); err != nil {
+		return nil, err
+	}
+
+	if len(res.Hash) != consts.HashSize {
+		return nil, network.ErrNotAccepted
+	}
+
+	return res.Hash, nil
+}
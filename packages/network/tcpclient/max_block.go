@@ -54,6 +54,7 @@ func getMaxBlock(host string) (blockID int64, err error) {
 		log.WithFields(log.Fields{"error": err, "type": consts.ConnectionError, "host": host}).Error("reading max block id from host")
 		return -1, err
 	}
+	network.Peers.SetBlockID(host, resp.BlockID)
 
 	return resp.BlockID, nil
 }
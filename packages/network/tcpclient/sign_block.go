@@ -0,0 +1,42 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/network"
+)
+
+// SendSignBlock sends the generated block to the validator, the validator returns its signature of the block
+func SendSignBlock(addr string, data []byte) (*network.BlockSignResponse, error) {
+	conn, err := newConnection(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rt := &network.RequestType{
+		Type: network.RequestTypeSignBlock,
+	}
+
+	if err = rt.Write(conn); err != nil {
+		return nil, err
+	}
+
+	req := &network.BlockSignRequest{Data: data}
+	if err = req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	res := &network.BlockSignResponse{}
+	if err = res.Read(conn); err != nil {
+		return nil, err
+	}
+
+	if len(res.Signature) == 0 {
+		return nil, network.ErrNotAccepted
+	}
+
+	return res, nil
+}
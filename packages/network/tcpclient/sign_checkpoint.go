@@ -0,0 +1,42 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpclient
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/network"
+)
+
+// SendSignCheckpoint requests the attestation of the block of the checkpoint from the honor node
+func SendSignCheckpoint(addr string, blockID int64) (*network.CheckpointSignResponse, error) {
+	conn, err := newConnection(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	rt := &network.RequestType{
+		Type: network.RequestTypeSignCheckpoint,
+	}
+
+	if err = rt.Write(conn); err != nil {
+		return nil, err
+	}
+
+	req := &network.CheckpointSignRequest{BlockID: blockID}
+	if err = req.Write(conn); err != nil {
+		return nil, err
+	}
+
+	res := &network.CheckpointSignResponse{}
+	if err = res.Read(conn); err != nil {
+		return nil, err
+	}
+
+	if len(res.Signature) == 0 {
+		return nil, network.ErrNotAccepted
+	}
+
+	return res, nil
+}
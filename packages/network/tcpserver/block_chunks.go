@@ -0,0 +1,64 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"net"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BlockCollectionChunked writes the bodies of the specified blocks like BlockCollection, the blocks
+// larger than the chunk size of the request are written as the references to download them by chunks
+func BlockCollectionChunked(request *network.GetChunkedBodiesRequest, w net.Conn) error {
+	blocks, err := writeCollectedBlocks(&request.GetBodiesRequest, w)
+	if err != nil {
+		return err
+	}
+	chunkSize := request.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = consts.BlockChunkSize
+	}
+	for _, b := range blocks {
+		body := &network.ChunkedBody{Data: b.Data}
+		if int64(len(b.Data)) > chunkSize {
+			body = &network.ChunkedBody{Ref: &network.BlockRef{Hash: b.Hash, Size: int64(len(b.Data))}}
+		}
+		if err := body.Write(w); err != nil {
+			log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "block_id": b.ID}).Error("on sending block body")
+			return err
+		}
+	}
+	return nil
+}
+
+// BlockChunks writes the chunks of the block with the hash starting from the offset of the request,
+// so the client resumes the download after the broken connection
+func BlockChunks(request *network.GetBlockChunksRequest, w net.Conn) error {
+	block := &sqldb.BlockChain{}
+	found, err := block.GetByHash(request.Hash)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting block by hash")
+		return err
+	}
+	if !found {
+		// the zero size means the node doesn't have the block
+		return (&network.BlockChunksHeader{}).Write(w)
+	}
+	data, err := block.BlockData()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err, "block_id": block.ID}).Error("retrieving block data")
+		return err
+	}
+	if err = network.WriteBlockChunks(w, wireBlockData(data), request.Offset, request.ChunkSize); err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err, "block_id": block.ID, "offset": request.Offset}).Error("on sending block chunks")
+		return err
+	}
+	return nil
+}
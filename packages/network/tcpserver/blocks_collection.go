@@ -7,9 +7,11 @@ package tcpserver
 import (
 	"net"
 
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/network"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -17,6 +19,28 @@ import (
 // BlockCollection writes the body of the specified block
 // blocksCollection and queue_parser_blocks daemons send the request through p.GetBlocks()
 func BlockCollection(request *network.GetBodiesRequest, w net.Conn) error {
+	blocks, err := writeCollectedBlocks(request, w)
+	if err != nil {
+		return err
+	}
+
+	if err := network.WriteInt(lenOfBlockData(blocks), w); err != nil {
+		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending requested blocks data length")
+		return err
+	}
+
+	for _, b := range blocks {
+		br := &network.GetBodyResponse{Data: b.Data}
+		if err := br.Write(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCollectedBlocks gets the requested blocks and writes their count
+func writeCollectedBlocks(request *network.GetBodiesRequest, w net.Conn) ([]sqldb.BlockChain, error) {
 	block := &sqldb.BlockChain{}
 
 	var blocks []sqldb.BlockChain
@@ -32,27 +56,34 @@ func BlockCollection(request *network.GetBodiesRequest, w net.Conn) error {
 		if err := network.WriteInt(0, w); err != nil {
 			log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending 0 requested blocks")
 		}
-		return err
+		return nil, err
+	}
+	// the peers get the blocks with the transactions which are kept off-chain
+	for i := range blocks {
+		if blocks[i].Data, err = blocks[i].BlockData(); err != nil {
+			log.WithFields(log.Fields{"type": consts.IOError, "error": err, "block_id": blocks[i].ID}).Error("retrieving block data")
+			if err := network.WriteInt(0, w); err != nil {
+				log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending 0 requested blocks")
+			}
+			return nil, err
+		}
+		blocks[i].Data, blocks[i].TxData = wireBlockData(blocks[i].Data), nil
 	}
 
 	if err := network.WriteInt(int64(len(blocks)), w); err != nil {
 		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending requested blocks count")
-		return err
-	}
-
-	if err := network.WriteInt(lenOfBlockData(blocks), w); err != nil {
-		log.WithFields(log.Fields{"type": consts.NetworkError, "error": err}).Error("on sending requested blocks data length")
-		return err
+		return nil, err
 	}
+	return blocks, nil
+}
 
-	for _, b := range blocks {
-		br := &network.GetBodyResponse{Data: b.Data}
-		if err := br.Write(w); err != nil {
-			return err
-		}
+// wireBlockData returns the binary of the block which is sent to the nodes, it's compressed if the compression
+// is enabled. The nodes decode the compressed and the legacy blocks
+func wireBlockData(data []byte) []byte {
+	if syspar.IsBlockCompression() {
+		return types.CompressBlock(data)
 	}
-
-	return nil
+	return data
 }
 
 func lenOfBlockData(blocks []sqldb.BlockChain) int64 {
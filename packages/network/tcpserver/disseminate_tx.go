@@ -39,16 +39,16 @@ func DisseminateTxs(rw io.ReadWriter) error {
 	}
 	var rtxs []*sqldb.RawTx
 	for _, tran := range txs {
-		if int64(len(tran)) > syspar.GetMaxTxSize() {
-			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_tx_size": syspar.GetMaxTxSize(), "current_size": len(tran)}).Error("transaction size exceeds max size")
-			return utils.ErrInfo("len(txBinData) > max_tx_size")
-		}
-
 		if tran == nil {
 			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "mx_tx_size": syspar.GetMaxTxSize(), "info": "tran nil", "current_size": len(tran)}).Error("transaction size nil")
 			continue
 		}
 
+		if err = transaction.CheckIngress(tran); err != nil {
+			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err, "current_size": len(tran)}).Error("transaction is rejected")
+			return utils.ErrInfo(err)
+		}
+
 		rtx := &transaction.Transaction{}
 		if err = rtx.Unmarshall(bytes.NewBuffer(tran), true); err != nil {
 			return err
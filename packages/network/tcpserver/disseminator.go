@@ -58,6 +58,11 @@ func Disseminator(rw io.ReadWriter) error {
 		log.WithError(err).Error("on getting node by position")
 		return err
 	}
+	var peer string
+	if n != nil {
+		peer = n.TCPAddress
+		network.Peers.Seen(peer)
+	}
 
 	// get data type (0 - block and transactions, 1 - only transactions)
 	newDataType := converter.BinToDec(buf.Next(1))
@@ -69,7 +74,7 @@ func Disseminator(rw io.ReadWriter) error {
 			buf.Next(3)
 			buf.Next(consts.HashSize)
 		} else {
-			err := processBlock(buf, honorNodeID)
+			err := processBlock(buf, honorNodeID, peer)
 			if err != nil {
 				log.WithError(err).Error("on process block")
 				return err
@@ -123,7 +128,7 @@ func resieveTxBodies(con io.Reader) ([]byte, error) {
 	return txBodies, nil
 }
 
-func processBlock(buf *bytes.Buffer, honorNodeID int64) error {
+func processBlock(buf *bytes.Buffer, honorNodeID int64, peer string) error {
 	infoBlock := &sqldb.InfoBlock{}
 	found, err := infoBlock.Get()
 	if err != nil {
@@ -142,6 +147,9 @@ func processBlock(buf *bytes.Buffer, honorNodeID int64) error {
 	// get block hash
 	blockHash := buf.Next(consts.HashSize)
 	log.Debugf("blockHash %x", blockHash)
+	if len(peer) > 0 {
+		network.Peers.SetBlock(peer, newBlockID, blockHash)
+	}
 
 	qb := &sqldb.QueueBlock{}
 	found, err = qb.GetQueueBlockByHash(blockHash)
@@ -246,9 +254,9 @@ func saveNewTransactions(binaryTxs []byte) error {
 			return utils.ErrInfo(errors.New("len(txBinData) == 0"))
 		}
 
-		if int64(len(txBinData)) > syspar.GetMaxTxSize() {
-			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "len": len(txBinData), "size": syspar.GetMaxTxSize()}).Error("len of tx data exceeds max size")
-			return utils.ErrInfo("len(txBinData) > max_tx_size")
+		if err = transaction.CheckIngress(txBinData); err != nil {
+			log.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err, "len": len(txBinData)}).Error("transaction is rejected")
+			return utils.ErrInfo(err)
 		}
 
 		rtx := transaction.Transaction{}
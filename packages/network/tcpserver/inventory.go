@@ -0,0 +1,123 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"errors"
+	"io"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TxPool is the storage of transactions which are received from the peers
+type TxPool interface {
+	// Has returns true if the transaction is in the queue, in the pool or in the blocks
+	Has(hash []byte) (bool, error)
+	// Save stores the bodies of the transactions
+	Save(txs [][]byte) error
+}
+
+// dbTxPool keeps the transactions in queue_tx
+type dbTxPool struct{}
+
+func (dbTxPool) Has(hash []byte) (bool, error) {
+	for _, count := range []func([]byte) (int64, error){
+		sqldb.GetLogTransactionsCount,
+		sqldb.GetTransactionsCount,
+		sqldb.GetQueuedTransactionsCount,
+	} {
+		exists, err := count(hash)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err, "txHash": hash}).Error("Getting tx count")
+			return false, utils.ErrInfo(err)
+		}
+		if exists > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (dbTxPool) Save(txs [][]byte) error {
+	var buf []byte
+	for _, tx := range txs {
+		buf = append(buf, converter.EncodeLengthPlusData(tx)...)
+	}
+	return saveNewTransactions(buf)
+}
+
+// TxInventory serves the announcement of transaction hashes. It requests only the hashes
+// which aren't known and receives their bodies on the same connection
+func TxInventory(rw io.ReadWriter, pool TxPool, known *network.SeenSet) error {
+	inv := &network.TxInventory{}
+	if err := inv.Read(rw); err != nil {
+		return err
+	}
+
+	need := &network.TxInventory{}
+	for _, hash := range inv.Hashes {
+		if known.Has(hash) {
+			continue
+		}
+		exists, err := pool.Has(hash)
+		if err != nil {
+			return err
+		}
+		// the concurrent announcements of the same hash request it only once
+		if known.Add(hash) && !exists {
+			need.Hashes = append(need.Hashes, hash)
+		}
+	}
+
+	if err := need.Write(rw); err != nil {
+		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Error("on sending requested tx hashes")
+		return err
+	}
+	if len(need.Hashes) == 0 {
+		return nil
+	}
+
+	txs, err := receiveRequestedTxs(rw)
+	if err == nil {
+		err = pool.Save(txs)
+	}
+	if err != nil || len(txs) < len(need.Hashes) {
+		// the missed transactions can be requested from another peer
+		for _, hash := range need.Hashes {
+			if ok, _ := pool.Has(hash); !ok {
+				known.Remove(hash)
+			}
+		}
+	}
+	return err
+}
+
+func receiveRequestedTxs(r io.Reader) ([][]byte, error) {
+	data, err := resieveTxBodies(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs [][]byte
+	for len(data) > 0 {
+		size, err := converter.DecodeLength(&data)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ProtocolError, "error": err}).Error("decoding binary txs length")
+			return nil, err
+		}
+		if size <= 0 || int64(len(data)) < size {
+			log.WithFields(log.Fields{"type": consts.ProtocolError, "size": size, "len": len(data)}).Error("incorrect binary txs len")
+			return nil, errors.New("bad transactions packet")
+		}
+		txs = append(txs, converter.BytesShift(&data, size))
+	}
+	return txs, nil
+}
@@ -0,0 +1,243 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/network/tcpclient"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+const (
+	testTxCount = 20
+	testTxSize  = 2048
+)
+
+// countingConn counts the bytes which are read and written by the server, that is all bytes
+// of the connection
+type countingConn struct {
+	net.Conn
+	counter *int64
+}
+
+func (c countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddInt64(c.counter, int64(n))
+	return n, err
+}
+
+func (c countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddInt64(c.counter, int64(n))
+	return n, err
+}
+
+// testNode keeps the transactions in memory and relays the new ones to the peers either with
+// the inventory or by pushing the full transactions
+type testNode struct {
+	ctx       context.Context
+	addr      string
+	peers     []string
+	inventory bool
+	known     *network.SeenSet
+	announcer *tcpclient.TxAnnouncer
+	received  *int64
+
+	mu  sync.Mutex
+	txs map[string][]byte
+}
+
+func (n *testNode) Has(hash []byte) (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.txs[string(hash)]
+	return ok, nil
+}
+
+func (n *testNode) Save(txs [][]byte) error {
+	n.mu.Lock()
+	var added []sqldb.Transaction
+	for _, data := range txs {
+		hash := sha256.Sum256(data)
+		if _, ok := n.txs[string(hash[:])]; !ok {
+			n.txs[string(hash[:])] = data
+			added = append(added, sqldb.Transaction{Hash: hash[:], Data: data})
+		}
+	}
+	n.mu.Unlock()
+	n.relay(added)
+	return nil
+}
+
+func (n *testNode) relay(txs []sqldb.Transaction) {
+	if len(txs) == 0 {
+		return
+	}
+	if n.inventory {
+		n.announcer.Add(n.peers, txs)
+		return
+	}
+	go tcpclient.SendTransacitionsToAll(n.ctx, n.peers, txs)
+}
+
+func (n *testNode) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.txs)
+}
+
+func (n *testNode) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			rw := countingConn{Conn: conn, counter: n.received}
+			rt := &network.RequestType{}
+			if err := rt.Read(rw); err != nil {
+				return
+			}
+			switch rt.Type {
+			case network.RequestTypeTxInventory:
+				TxInventory(rw, n, n.known)
+			case network.RequestTypeNotHonorNode:
+				r := &network.DisRequest{}
+				if err := r.Read(rw); err != nil {
+					return
+				}
+				if txs, err := UnmarshalTxPacket(r.Data); err == nil {
+					n.Save(txs)
+				}
+			}
+		}(conn)
+	}
+}
+
+func listen(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+// deadAddr returns the address where nobody listens
+func deadAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+// newTestNetwork starts three nodes, the submitter, the relay and the producer. The direct link
+// between the submitter and the producer is down if linkDown is true
+func newTestNetwork(t *testing.T, inventory, linkDown bool) (nodes []*testNode, wire *int64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	wire = new(int64)
+	listeners := make([]net.Listener, 3)
+	for i := range listeners {
+		listeners[i] = listen(t)
+		nodes = append(nodes, &testNode{
+			ctx:       ctx,
+			addr:      listeners[i].Addr().String(),
+			inventory: inventory,
+			known:     network.NewSeenSet(time.Minute, 1000),
+			announcer: tcpclient.NewTxAnnouncer(10 * time.Millisecond),
+			received:  wire,
+			txs:       make(map[string][]byte),
+		})
+	}
+	for i, n := range nodes {
+		for j, peer := range nodes {
+			if i == j {
+				continue
+			}
+			addr := peer.addr
+			if linkDown && i+j == 2 && i != 1 {
+				addr = deadAddr(t)
+			}
+			n.peers = append(n.peers, addr)
+		}
+		go n.serve(listeners[i])
+		go n.announcer.Run(ctx)
+	}
+	return
+}
+
+// submit adds the new transactions to the submitter and waits until all nodes receive them
+func submit(t *testing.T, nodes []*testNode) {
+	t.Helper()
+	txs := make([][]byte, testTxCount)
+	for i := range txs {
+		txs[i] = bytes.Repeat([]byte(fmt.Sprintf("%02d", i)), testTxSize/2)
+	}
+	if err := nodes[0].Save(txs); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for _, n := range nodes {
+		for n.count() < testTxCount {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %s has received %d of %d transactions", n.addr, n.count(), testTxCount)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestTxInventoryLinkDown(t *testing.T) {
+	nodes, _ := newTestNetwork(t, true, true)
+	submit(t, nodes)
+	producer := nodes[2]
+	for _, n := range nodes[:2] {
+		n.mu.Lock()
+		for hash, data := range n.txs {
+			if !bytes.Equal(producer.txs[hash], data) {
+				t.Errorf("producer has the wrong transaction %x", hash)
+			}
+		}
+		n.mu.Unlock()
+	}
+}
+
+func TestTxInventoryBandwidth(t *testing.T) {
+	pushNodes, push := newTestNetwork(t, false, false)
+	submit(t, pushNodes)
+	invNodes, inventory := newTestNetwork(t, true, false)
+	submit(t, invNodes)
+
+	// let the late announcements and pushes finish
+	time.Sleep(100 * time.Millisecond)
+	pushBytes, invBytes := atomic.LoadInt64(push), atomic.LoadInt64(inventory)
+	t.Logf("bytes on the wire: push %d, inventory %d", pushBytes, invBytes)
+
+	// every node receives each body once with the inventory, the push sends it over every link
+	bodies := int64(testTxCount * testTxSize * (len(invNodes) - 1))
+	if invBytes > bodies*5/4 {
+		t.Errorf("inventory sends %d bytes, the bodies are %d bytes", invBytes, bodies)
+	}
+	if invBytes*2 > pushBytes {
+		t.Errorf("inventory %d bytes isn't much less than push %d bytes", invBytes, pushBytes)
+	}
+}
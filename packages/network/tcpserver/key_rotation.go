@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"errors"
+	"net"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+var errNotKeyRotation = errors.New("transaction isn't key rotation")
+
+// KeyRotation adds the key rotation transaction to the queue
+func KeyRotation(req *network.KeyRotationRequest, w net.Conn) error {
+	hash, err := processKeyRotation(req.Data)
+	if err != nil {
+		return err
+	}
+
+	res := &network.KeyRotationResponse{Hash: hash}
+	if err = res.Write(w); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.NetworkError}).Error("sending response")
+		return err
+	}
+
+	return nil
+}
+
+func processKeyRotation(data []byte) ([]byte, error) {
+	if err := transaction.CheckIngress(data); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.ParameterExceeded}).Error("checking key rotation")
+		return nil, err
+	}
+	tx, err := transaction.DecodeTransaction(data)
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.ParseError}).Error("parsing key rotation")
+		return nil, err
+	}
+	krp, ok := tx.Inner.(*transaction.KeyRotationParser)
+	if !ok {
+		log.WithFields(log.Fields{"error": errNotKeyRotation, "type": consts.InvalidObject, "tx_type": tx.Type()}).Error("checking tx type")
+		return nil, errNotKeyRotation
+	}
+	if err = krp.Validate(); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.InvalidObject}).Error("validating key rotation")
+		return nil, err
+	}
+
+	t := &sqldb.Transaction{
+		Hash:     tx.Hash(),
+		Data:     data,
+		Type:     types.KeyRotationTxType,
+		KeyID:    tx.KeyID(),
+		HighRate: sqldb.TransactionRateApiContract,
+		Time:     tx.Timestamp(),
+	}
+	if err = t.Create(nil); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("inserting tx to database")
+		return nil, err
+	}
+
+	return tx.Hash(), nil
+}
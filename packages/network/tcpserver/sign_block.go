@@ -0,0 +1,32 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"net"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	log "github.com/sirupsen/logrus"
+)
+
+// SignBlock returns the signature of the generated block by the node as the validator
+func SignBlock(req *network.BlockSignRequest, w net.Conn) error {
+	sign, err := block.SignAsValidator(req.Data, syspar.GetNodeSigner())
+	if err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.BlockError}).Warn("signing block as validator")
+		return err
+	}
+
+	res := &network.BlockSignResponse{KeyID: sign.ValidatorKeyId, Signature: sign.Signature}
+	if err = res.Write(w); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.NetworkError}).Error("sending response")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package tcpserver
+
+import (
+	"net"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	log "github.com/sirupsen/logrus"
+)
+
+// SignCheckpoint returns the attestation of the block of the checkpoint by the node as the honor node
+func SignCheckpoint(req *network.CheckpointSignRequest, w net.Conn) error {
+	res := &network.CheckpointSignResponse{}
+	if syspar.IsHonorNodeMode() {
+		a, err := node.SignBlockAttestation(req.BlockID, syspar.GetNodeSigner())
+		if err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.BlockError, "block_id": req.BlockID}).Warn("signing checkpoint")
+		} else {
+			res = &network.CheckpointSignResponse{BlockHash: a.BlockHash, NodeKeyID: a.NodeKeyID, Timestamp: a.Timestamp, Signature: a.Signature}
+		}
+	}
+
+	if err := res.Write(w); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.NetworkError}).Error("sending response")
+		return err
+	}
+
+	return nil
+}
@@ -41,12 +41,42 @@ func HandleTCPRequest(rw net.Conn) {
 		}
 		err = DisseminateTxs(rw)
 
+	case network.RequestTypeTxInventory:
+		if node.IsNodePaused() {
+			return
+		}
+		err = TxInventory(rw, dbTxPool{}, network.KnownTxs)
+
 	case network.RequestTypeStopNetwork:
 		req := &network.StopNetworkRequest{}
 		if err = req.Read(rw); err == nil {
 			err = StopNetwork(req, rw)
 		}
 
+	case network.RequestTypeKeyRotation:
+		req := &network.KeyRotationRequest{}
+		if err = req.Read(rw); err == nil {
+			err = KeyRotation(req, rw)
+		}
+
+	case network.RequestTypeSignBlock:
+		if node.IsNodePaused() {
+			return
+		}
+		req := &network.BlockSignRequest{}
+		if err = req.Read(rw); err == nil {
+			err = SignBlock(req, rw)
+		}
+
+	case network.RequestTypeSignCheckpoint:
+		if node.IsNodePaused() {
+			return
+		}
+		req := &network.CheckpointSignRequest{}
+		if err = req.Read(rw); err == nil {
+			err = SignCheckpoint(req, rw)
+		}
+
 	case network.RequestTypeConfirmation:
 		//if node.IsNodePaused() {
 		//	return
@@ -63,6 +93,18 @@ func HandleTCPRequest(rw net.Conn) {
 			err = BlockCollection(req, rw)
 		}
 
+	case network.RequestTypeBlockCollectionChunked:
+		req := &network.GetChunkedBodiesRequest{}
+		if err = req.Read(rw); err == nil {
+			err = BlockCollectionChunked(req, rw)
+		}
+
+	case network.RequestTypeBlockChunks:
+		req := &network.GetBlockChunksRequest{}
+		if err = req.Read(rw); err == nil {
+			err = BlockChunks(req, rw)
+		}
+
 	case network.RequestTypeMaxBlock:
 		response, err = MaxBlock()
 
@@ -0,0 +1,184 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package notificator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/publisher"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sendAttempts   = 3
+	sendRetryDelay = 500 * time.Millisecond
+)
+
+// DispatcherStats is the state of the queues of the dispatcher
+type DispatcherStats struct {
+	Queues    int   `json:"queues"`    // the queues of the blocks which wait for the counting of the notifications
+	Clients   int   `json:"clients"`   // the clients which wait for the sending
+	Dropped   int64 `json:"dropped"`   // the queues which are dropped because the dispatcher is full
+	Coalesced int64 `json:"coalesced"` // the messages which are replaced by the newer ones of the same client
+	Failed    int64 `json:"failed"`    // the messages which aren't sent after all attempts
+	Sent      int64 `json:"sent"`
+}
+
+// Dispatcher sends the notifications of the committed blocks by the workers, so the play of the blocks
+// doesn't wait for the centrifugo. The client has one message waiting at most, the newer statistics
+// replace the message which isn't sent yet
+type Dispatcher struct {
+	queues  chan *Queue
+	clients chan string
+	write   func(account, data string) error
+
+	mu      sync.Mutex
+	pending map[string]string
+	stats   DispatcherStats
+}
+
+var dispatcher *Dispatcher
+
+// StartDispatcher starts the dispatcher of the node with the workers and the queue of size queues, the
+// notifications are sent by the caller if workers is zero
+func StartDispatcher(ctx context.Context, workers, size int) {
+	if workers <= 0 {
+		return
+	}
+	d := NewDispatcher(size, publisher.Write)
+	d.Run(ctx, workers)
+	dispatcher = d
+}
+
+// GetDispatcherStats returns the state of the dispatcher of the node, ok is false if it isn't started
+func GetDispatcherStats() (stats DispatcherStats, ok bool) {
+	if dispatcher == nil {
+		return stats, false
+	}
+	return dispatcher.Stats(), true
+}
+
+// NewDispatcher returns the dispatcher which keeps size queues and clients at most, write sends the message
+func NewDispatcher(size int, write func(account, data string) error) *Dispatcher {
+	return &Dispatcher{
+		queues:  make(chan *Queue, size),
+		clients: make(chan string, size),
+		write:   write,
+		pending: make(map[string]string),
+	}
+}
+
+// Run starts the workers which count the notifications of the queues and the workers which send them
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go d.countWorker(ctx)
+		go d.sendWorker(ctx)
+	}
+}
+
+// Stats returns the state of the queues
+func (d *Dispatcher) Stats() DispatcherStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stats := d.stats
+	stats.Queues = len(d.queues)
+	stats.Clients = len(d.pending)
+	return stats
+}
+
+// dispatch adds the queue of the block, it's dropped if the dispatcher is full
+func (d *Dispatcher) dispatch(q *Queue) {
+	select {
+	case d.queues <- q:
+	default:
+		d.mu.Lock()
+		d.stats.Dropped++
+		d.mu.Unlock()
+		q.getLogger().WithFields(log.Fields{"type": consts.IOError, "queue_size": cap(d.queues)}).Warn("notification dispatcher is full")
+	}
+}
+
+// push adds the message of the client, it replaces the message of the client which is waiting
+func (d *Dispatcher) push(ctx context.Context, account, data string) {
+	d.mu.Lock()
+	if _, ok := d.pending[account]; ok {
+		d.pending[account] = data
+		d.stats.Coalesced++
+		d.mu.Unlock()
+		return
+	}
+	d.pending[account] = data
+	d.mu.Unlock()
+	select {
+	case d.clients <- account:
+	case <-ctx.Done():
+	}
+}
+
+// pop returns the waiting message of the client
+func (d *Dispatcher) pop(account string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data, ok := d.pending[account]
+	delete(d.pending, account)
+	return data, ok
+}
+
+func (d *Dispatcher) countWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case q := <-d.queues:
+			q.send(func(account, data string) { d.push(ctx, account, data) })
+		}
+	}
+}
+
+func (d *Dispatcher) sendWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case account := <-d.clients:
+			data, ok := d.pop(account)
+			if !ok {
+				continue
+			}
+			err := d.send(ctx, account, data)
+			d.mu.Lock()
+			if err != nil {
+				d.stats.Failed++
+			} else {
+				d.stats.Sent++
+			}
+			d.mu.Unlock()
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.IOError, "error": err, "account": account}).Debug("writing to centrifugo")
+			}
+		}
+	}
+}
+
+// send writes the message, it's written again after the failure
+func (d *Dispatcher) send(ctx context.Context, account, data string) (err error) {
+	for i := 0; i < sendAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(sendRetryDelay):
+			}
+		}
+		if err = d.write(account, data); err == nil {
+			return nil
+		}
+	}
+	return err
+}
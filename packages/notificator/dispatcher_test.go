@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package notificator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcher(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		written = make(map[string]string)
+		fails   = 1
+	)
+	d := NewDispatcher(4, func(account, data string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if fails > 0 {
+			fails--
+			return errors.New("centrifugo is unavailable")
+		}
+		written[account] = data
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// the newer message replaces the waiting one of the same client
+	d.push(ctx, "1", "old")
+	d.push(ctx, "1", "new")
+	d.push(ctx, "2", "data")
+	if stats := d.Stats(); stats.Clients != 2 || stats.Coalesced != 1 {
+		t.Fatalf("wrong stats %+v", stats)
+	}
+	d.Run(ctx, 1)
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Stats().Sent < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if written["1"] != "new" || written["2"] != "data" {
+		t.Errorf("wrong messages %v", written)
+	}
+	if stats := d.Stats(); stats.Failed != 0 || stats.Clients != 0 {
+		t.Errorf("wrong stats %+v", stats)
+	}
+}
@@ -23,26 +23,45 @@ type notificationRecord struct {
 
 // UpdateNotifications send stats about unreaded messages to centrifugo for ecosystem
 func UpdateNotifications(ecosystemID int64, accounts []string) {
-	notificationsStats, err := getEcosystemNotificationStats(ecosystemID, accounts)
+	logger := log.NewEntry(log.StandardLogger())
+	updateNotifications(logger, ecosystemID, accounts, writer(logger))
+}
+
+// UpdateRolesNotifications send stats about unreaded messages to centrifugo for ecosystem
+func UpdateRolesNotifications(ecosystemID int64, roles []int64) {
+	logger := log.NewEntry(log.StandardLogger())
+	updateRolesNotifications(logger, ecosystemID, roles, writer(logger))
+}
+
+// writer returns the function which writes the message to centrifugo at once
+func writer(logger *log.Entry) func(account, data string) {
+	return func(account, data string) {
+		if err := publisher.Write(account, data); err != nil {
+			logger.WithFields(log.Fields{"type": consts.IOError, "error": err}).Debug("writing to centrifugo")
+		}
+	}
+}
+
+func updateNotifications(logger *log.Entry, ecosystemID int64, accounts []string, publish func(account, data string)) {
+	notificationsStats, err := getEcosystemNotificationStats(logger, ecosystemID, accounts)
 	if err != nil {
 		return
 	}
 
 	for account, n := range notificationsStats {
-		sendUserStats(account, *n)
+		sendUserStats(logger, account, *n, publish)
 	}
 }
 
-// UpdateRolesNotifications send stats about unreaded messages to centrifugo for ecosystem
-func UpdateRolesNotifications(ecosystemID int64, roles []int64) {
+func updateRolesNotifications(logger *log.Entry, ecosystemID int64, roles []int64, publish func(account, data string)) {
 	members, _ := sqldb.GetRoleMembers(nil, ecosystemID, roles)
-	UpdateNotifications(ecosystemID, members)
+	updateNotifications(logger, ecosystemID, members, publish)
 }
 
-func getEcosystemNotificationStats(ecosystemID int64, users []string) (map[string]*[]notificationRecord, error) {
+func getEcosystemNotificationStats(logger *log.Entry, ecosystemID int64, users []string) (map[string]*[]notificationRecord, error) {
 	result, err := sqldb.GetNotificationsCount(ecosystemID, users)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting notification count")
+		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting notification count")
 		return nil, err
 	}
 
@@ -79,14 +98,11 @@ func parseRecipientNotification(rows []sqldb.NotificationsCount, systemID int64)
 	return recipientNotifications
 }
 
-func sendUserStats(account string, stats []notificationRecord) {
+func sendUserStats(logger *log.Entry, account string, stats []notificationRecord, publish func(account, data string)) {
 	rawStats, err := json.Marshal(stats)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("notification statistic")
+		logger.WithFields(log.Fields{"type": consts.JSONMarshallError, "error": err}).Error("notification statistic")
 	}
 
-	err = publisher.Write(account, string(rawStats))
-	if err != nil {
-		log.WithFields(log.Fields{"type": consts.IOError, "error": err}).Debug("writing to centrifugo")
-	}
+	publish(account, string(rawStats))
 }
@@ -6,11 +6,14 @@ package notificator
 
 import (
 	"github.com/IBAX-io/go-ibax/packages/types"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type Queue struct {
 	Accounts []*Accounts
 	Roles    []*Roles
+	logger   *log.Entry
 }
 
 type Accounts struct {
@@ -41,19 +44,41 @@ func (q *Queue) AddRoles(ecosystem int64, list ...int64) {
 	})
 }
 
+// Send passes the queue to the dispatcher of the node, the notifications are sent at once if it isn't started
 func (q *Queue) Send() {
+	if dispatcher != nil {
+		dispatcher.dispatch(q)
+		return
+	}
+	q.send(writer(q.getLogger()))
+}
+
+func (q *Queue) send(publish func(account, data string)) {
 	for _, a := range q.Accounts {
-		UpdateNotifications(a.Ecosystem, a.List)
+		updateNotifications(q.getLogger(), a.Ecosystem, a.List, publish)
 	}
 
 	for _, r := range q.Roles {
-		UpdateRolesNotifications(r.Ecosystem, r.List)
+		updateRolesNotifications(q.getLogger(), r.Ecosystem, r.List, publish)
 	}
 }
 
+func (q *Queue) getLogger() *log.Entry {
+	if q.logger == nil {
+		return log.NewEntry(log.StandardLogger())
+	}
+	return q.logger
+}
+
 func NewQueue() types.Notifications {
+	return NewQueueWithLogger(nil)
+}
+
+// NewQueueWithLogger returns the queue which logs the sending with the logger of the block
+func NewQueueWithLogger(logger *log.Entry) types.Notifications {
 	return &Queue{
 		Accounts: make([]*Accounts, 0),
 		Roles:    make([]*Roles, 0),
+		logger:   logger,
 	}
 }
@@ -59,6 +59,7 @@ const (
 	TxInvokeStatusCode_PENALTY TxInvokeStatusCode = 1
 	TxInvokeStatusCode_FAILED  TxInvokeStatusCode = 2
 	TxInvokeStatusCode_PENDING TxInvokeStatusCode = 3
+	TxInvokeStatusCode_DRYRUN  TxInvokeStatusCode = 4
 )
 
 var TxInvokeStatusCode_name = map[int32]string{
@@ -66,6 +67,7 @@ var TxInvokeStatusCode_name = map[int32]string{
 	1: "PENALTY",
 	2: "FAILED",
 	3: "PENDING",
+	4: "DRYRUN",
 }
 
 var TxInvokeStatusCode_value = map[string]int32{
@@ -73,6 +75,7 @@ var TxInvokeStatusCode_value = map[string]int32{
 	"PENALTY": 1,
 	"FAILED":  2,
 	"PENDING": 3,
+	"DRYRUN":  4,
 }
 
 func (x TxInvokeStatusCode) String() string {
@@ -322,40 +325,40 @@ func init() {
 func init() { proto.RegisterFile("tx.proto", fileDescriptor_0fd2153dc07d3b5c) }
 
 var fileDescriptor_0fd2153dc07d3b5c = []byte{
-	// 516 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0xcd, 0x8e, 0xda, 0x3c,
-	0x14, 0x25, 0xfc, 0x73, 0x99, 0x19, 0x82, 0xf5, 0xcd, 0xa7, 0x54, 0x6a, 0x23, 0xc4, 0xa2, 0x42,
-	0xa8, 0x80, 0xd4, 0x2e, 0xba, 0x26, 0x19, 0x18, 0x45, 0x50, 0x40, 0x8e, 0xab, 0xfe, 0x6c, 0xa2,
-	0xfc, 0x58, 0x10, 0x05, 0xe2, 0xc8, 0x31, 0x53, 0xd8, 0xf7, 0x01, 0xba, 0xe9, 0x3b, 0x75, 0x39,
-	0xcb, 0x2e, 0x2b, 0x78, 0x91, 0x2a, 0x06, 0xcd, 0x54, 0x9d, 0x5d, 0x77, 0xf7, 0xdc, 0x73, 0x7c,
-	0xef, 0xf1, 0xb1, 0xa1, 0x2a, 0x76, 0xfd, 0x84, 0x33, 0xc1, 0x50, 0x31, 0xf1, 0x96, 0xac, 0xfd,
-	0x35, 0x0f, 0x30, 0x0e, 0x79, 0x2a, 0x8c, 0x35, 0xf3, 0x23, 0x74, 0x0d, 0xe5, 0x88, 0xee, 0x9d,
-	0x30, 0xd0, 0x94, 0x96, 0xd2, 0x29, 0xe0, 0x52, 0x44, 0xf7, 0x56, 0x80, 0x9e, 0x43, 0x4d, 0x84,
-	0x1b, 0x9a, 0x0a, 0x77, 0x93, 0x68, 0x79, 0xc9, 0x3c, 0x36, 0xd0, 0x0b, 0x80, 0x64, 0xeb, 0xad,
-	0x43, 0xdf, 0x89, 0xe8, 0x5e, 0x2b, 0xb4, 0x94, 0xce, 0x05, 0xae, 0x9d, 0x3a, 0x13, 0xba, 0x47,
-	0x2f, 0xa1, 0x11, 0xb3, 0x80, 0x3a, 0x7f, 0x68, 0x8a, 0x52, 0x73, 0x99, 0xb5, 0x17, 0x0f, 0xba,
-	0xb7, 0xa0, 0xa5, 0x82, 0x25, 0x4e, 0x4c, 0xc5, 0x17, 0xc6, 0x23, 0xc7, 0xa7, 0x5c, 0x38, 0xde,
-	0x36, 0x0e, 0xd6, 0x54, 0x2b, 0xc9, 0x03, 0xd7, 0x19, 0x3f, 0x3b, 0xd1, 0x26, 0xe5, 0xc2, 0x90,
-	0x24, 0x42, 0x50, 0x14, 0x34, 0x15, 0x5a, 0x59, 0x1a, 0x93, 0x35, 0xea, 0x01, 0x4a, 0x78, 0x78,
-	0xe7, 0x0a, 0xea, 0x78, 0xd9, 0xcd, 0xfc, 0x95, 0x1b, 0xc6, 0x5a, 0xa5, 0xa5, 0x74, 0x8a, 0xb8,
-	0x79, 0x66, 0x8c, 0x07, 0xa2, 0x1d, 0x43, 0xdd, 0x7e, 0x9c, 0xfd, 0x6f, 0x31, 0x74, 0xa1, 0xf9,
-	0xc4, 0xff, 0x39, 0x8d, 0xc6, 0x5f, 0xc6, 0xdb, 0xdf, 0x15, 0xa8, 0x92, 0x1d, 0xa6, 0xe9, 0x76,
-	0x2d, 0x32, 0xff, 0x2b, 0x37, 0x5d, 0xc9, 0x5d, 0x17, 0x58, 0xd6, 0xe8, 0x19, 0x54, 0xa5, 0xef,
-	0xcc, 0xc3, 0x69, 0x53, 0x45, 0x62, 0x2b, 0x40, 0xaf, 0xa0, 0xe8, 0xb3, 0x80, 0xca, 0xd1, 0x57,
-	0xaf, 0xb5, 0x7e, 0xf6, 0x8e, 0x7d, 0xb2, 0xb3, 0xe2, 0x3b, 0x16, 0x51, 0x5b, 0xb8, 0x62, 0x9b,
-	0x9a, 0x2c, 0xa0, 0x58, 0xaa, 0xd0, 0xff, 0x50, 0xe6, 0x72, 0x8d, 0x0c, 0xbd, 0x86, 0xcf, 0x08,
-	0xfd, 0x07, 0x25, 0xca, 0x39, 0xe3, 0x32, 0xda, 0x1a, 0x3e, 0x81, 0xee, 0x18, 0x54, 0xc2, 0xdd,
-	0x38, 0x75, 0x7d, 0x11, 0xb2, 0x98, 0xec, 0x13, 0x9a, 0xa2, 0x26, 0x5c, 0xda, 0xef, 0x86, 0x98,
-	0x98, 0xf3, 0x19, 0xc1, 0x43, 0x93, 0xa8, 0x39, 0x74, 0x05, 0x30, 0xb6, 0xb0, 0x4d, 0x8c, 0xe9,
-	0xdc, 0x9c, 0xa8, 0x0a, 0x6a, 0x40, 0xdd, 0x26, 0xf3, 0xc5, 0x6c, 0x44, 0x3e, 0xcc, 0xf1, 0x44,
-	0xcd, 0x77, 0x6f, 0x01, 0x3d, 0x75, 0x84, 0xea, 0x50, 0xb1, 0xdf, 0x9b, 0xe6, 0xc8, 0xb6, 0xd5,
-	0x5c, 0x06, 0x16, 0xa3, 0xd9, 0x70, 0x4a, 0x3e, 0xa9, 0x0a, 0x02, 0x28, 0x8f, 0x87, 0xd6, 0x74,
-	0x74, 0xa3, 0xe6, 0xcf, 0xc4, 0x8d, 0x35, 0xbb, 0x55, 0x0b, 0x86, 0xf1, 0xe3, 0xa0, 0x2b, 0xf7,
-	0x07, 0x5d, 0xf9, 0x75, 0xd0, 0x95, 0x6f, 0x47, 0x3d, 0x77, 0x7f, 0xd4, 0x73, 0x3f, 0x8f, 0x7a,
-	0xee, 0x73, 0x67, 0x19, 0x8a, 0xd5, 0xd6, 0xeb, 0xfb, 0x6c, 0x33, 0xb0, 0x8c, 0xe1, 0xc7, 0x5e,
-	0xc8, 0x06, 0x4b, 0xd6, 0x0b, 0x3d, 0x77, 0x37, 0x48, 0x5c, 0x3f, 0x72, 0x97, 0x34, 0x1d, 0x64,
-	0xd9, 0x78, 0x65, 0xf9, 0xe1, 0xdf, 0xfc, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x00, 0xe8, 0x53, 0x93,
-	0xfc, 0x02, 0x00, 0x00,
+	// 522 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x4f, 0x8f, 0xd2, 0x40,
+	0x18, 0xc6, 0x29, 0xdb, 0xe5, 0xcf, 0xcb, 0xee, 0x52, 0x26, 0xae, 0xa9, 0x89, 0x36, 0x84, 0x83,
+	0x21, 0x44, 0x20, 0xd1, 0x83, 0x67, 0x5a, 0xc0, 0x34, 0x60, 0x21, 0xd3, 0x6e, 0x74, 0xbd, 0x34,
+	0xfd, 0x33, 0x81, 0xa6, 0xd0, 0x69, 0xa6, 0xc3, 0x0a, 0x77, 0x3f, 0x80, 0x17, 0xbf, 0x93, 0xc7,
+	0x3d, 0x7a, 0x34, 0xf0, 0x45, 0x4c, 0x07, 0xb2, 0x6b, 0xdc, 0x9b, 0xb7, 0x79, 0xde, 0xdf, 0xd3,
+	0x79, 0x9f, 0x3e, 0x2d, 0x54, 0xf8, 0xb6, 0x97, 0x32, 0xca, 0x29, 0x92, 0x53, 0x7f, 0x41, 0x5b,
+	0xdf, 0x8a, 0x00, 0xe3, 0x88, 0x65, 0x5c, 0x5f, 0xd1, 0x20, 0x46, 0xd7, 0x50, 0x8a, 0xc9, 0xce,
+	0x8d, 0x42, 0x55, 0x6a, 0x4a, 0xed, 0x33, 0x7c, 0x1e, 0x93, 0x9d, 0x19, 0xa2, 0x97, 0x50, 0xe5,
+	0xd1, 0x9a, 0x64, 0xdc, 0x5b, 0xa7, 0x6a, 0x51, 0x90, 0xc7, 0x01, 0x7a, 0x05, 0x90, 0x6e, 0xfc,
+	0x55, 0x14, 0xb8, 0x31, 0xd9, 0xa9, 0x67, 0x4d, 0xa9, 0x7d, 0x81, 0xab, 0xc7, 0xc9, 0x84, 0xec,
+	0xd0, 0x6b, 0xa8, 0x27, 0x34, 0x24, 0xee, 0x5f, 0x1e, 0x59, 0x78, 0x2e, 0xf3, 0xf1, 0xfc, 0xc1,
+	0xf7, 0x1e, 0xd4, 0x8c, 0xd3, 0xd4, 0x4d, 0x08, 0xff, 0x4a, 0x59, 0xec, 0x06, 0x84, 0x71, 0xd7,
+	0xdf, 0x24, 0xe1, 0x8a, 0xa8, 0xe7, 0xe2, 0x81, 0xeb, 0x9c, 0x5b, 0x47, 0x6c, 0x10, 0xc6, 0x75,
+	0x01, 0x11, 0x02, 0x99, 0x93, 0x8c, 0xab, 0x25, 0x11, 0x4c, 0x9c, 0x51, 0x17, 0x50, 0xca, 0xa2,
+	0x3b, 0x8f, 0x13, 0xd7, 0xcf, 0xdf, 0x2c, 0x58, 0x7a, 0x51, 0xa2, 0x96, 0x9b, 0x52, 0x5b, 0xc6,
+	0x8d, 0x13, 0xd1, 0x1f, 0x40, 0x2b, 0x81, 0x9a, 0xfd, 0x78, 0xf7, 0xff, 0xd5, 0xd0, 0x81, 0xc6,
+	0x93, 0xfc, 0xa7, 0x36, 0xea, 0xff, 0x04, 0x6f, 0xfd, 0x90, 0xa0, 0xe2, 0x6c, 0x31, 0xc9, 0x36,
+	0x2b, 0x9e, 0xe7, 0x5f, 0x7a, 0xd9, 0x52, 0xec, 0xba, 0xc0, 0xe2, 0x8c, 0x5e, 0x40, 0x45, 0xe4,
+	0xce, 0x33, 0x1c, 0x37, 0x95, 0x85, 0x36, 0x43, 0xf4, 0x06, 0xe4, 0x80, 0x86, 0x44, 0x5c, 0x7d,
+	0xf5, 0x56, 0xed, 0xe5, 0xdf, 0xb1, 0xe7, 0x6c, 0xcd, 0xe4, 0x8e, 0xc6, 0xc4, 0xe6, 0x1e, 0xdf,
+	0x64, 0x06, 0x0d, 0x09, 0x16, 0x2e, 0xf4, 0x1c, 0x4a, 0x4c, 0xac, 0x11, 0xa5, 0x57, 0xf1, 0x49,
+	0xa1, 0x67, 0x70, 0x4e, 0x18, 0xa3, 0x4c, 0x54, 0x5b, 0xc5, 0x47, 0xd1, 0x19, 0x83, 0xe2, 0x30,
+	0x2f, 0xc9, 0xbc, 0x80, 0x47, 0x34, 0x71, 0x76, 0x29, 0xc9, 0x50, 0x03, 0x2e, 0xed, 0x8f, 0x03,
+	0xec, 0x18, 0x33, 0xcb, 0xc1, 0x03, 0xc3, 0x51, 0x0a, 0xe8, 0x0a, 0x60, 0x6c, 0x62, 0xdb, 0xd1,
+	0xa7, 0x33, 0x63, 0xa2, 0x48, 0xa8, 0x0e, 0x35, 0xdb, 0x99, 0xcd, 0xad, 0x91, 0xf3, 0x69, 0x86,
+	0x27, 0x4a, 0xb1, 0x63, 0x03, 0x7a, 0x9a, 0x08, 0xd5, 0xa0, 0x6c, 0xdf, 0x18, 0xc6, 0xc8, 0xb6,
+	0x95, 0x42, 0x2e, 0xe6, 0x23, 0x6b, 0x30, 0x75, 0x6e, 0x15, 0x09, 0x01, 0x94, 0xc6, 0x03, 0x73,
+	0x3a, 0x1a, 0x2a, 0xc5, 0x13, 0x18, 0x9a, 0xd6, 0x07, 0xe5, 0x2c, 0x07, 0x43, 0x7c, 0x8b, 0x6f,
+	0x2c, 0x45, 0xd6, 0xf5, 0x9f, 0x7b, 0x4d, 0xba, 0xdf, 0x6b, 0xd2, 0xef, 0xbd, 0x26, 0x7d, 0x3f,
+	0x68, 0x85, 0xfb, 0x83, 0x56, 0xf8, 0x75, 0xd0, 0x0a, 0x5f, 0xda, 0x8b, 0x88, 0x2f, 0x37, 0x7e,
+	0x2f, 0xa0, 0xeb, 0xbe, 0xa9, 0x0f, 0x3e, 0x77, 0x23, 0xda, 0x5f, 0xd0, 0x6e, 0xe4, 0x7b, 0xdb,
+	0x7e, 0xea, 0x05, 0xb1, 0xb7, 0x20, 0x59, 0x3f, 0xef, 0xc9, 0x2f, 0x89, 0x9f, 0xff, 0xdd, 0x9f,
+	0x01, 0x00, 0x4f, 0xaa, 0x08, 0x4e, 0x08, 0x03, 0x00, 0x00,
 }
 
 func (m *FirstBlock) Marshal() (dAtA []byte, err error) {
@@ -6,6 +6,7 @@ package protocols
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
@@ -130,12 +131,26 @@ func (btc *BlockTimeCounter) TimeToGenerate(at time.Time, nodePosition int) (boo
 	return position == nodePosition, err
 }
 
-// NewBlockTimeCounter return initialized BlockTimeCounter
-func NewBlockTimeCounter() *BlockTimeCounter {
+// NewBlockTimeCounter return initialized BlockTimeCounter for the block with blockID.
+// The generation queue starts at the first block. If gaps between blocks have been changed
+// it starts at the block which precedes the activation height of the effective value
+func NewBlockTimeCounter(blockID int64) (*BlockTimeCounter, error) {
+	start := syspar.GetFirstBlockTimestamp()
+	if height := syspar.GetGapsActivationHeight(blockID); height > 1 {
+		prev := &sqldb.BlockChain{}
+		found, err := prev.Get(height - 1)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, fmt.Errorf("block %d preceding the activation of %s has not been found", height-1, syspar.GapsBetweenBlocks)
+		}
+		start = prev.Time
+	}
 	btc := BlockTimeCounter{
-		start:       time.Unix(syspar.GetFirstBlockTimestamp(), 0),
-		duration:    syspar.GetMaxBlockTimeDuration(),
+		start:       time.Unix(start, 0),
+		duration:    syspar.GetMaxBlockTimeDurationAt(blockID),
 		numberNodes: int(syspar.GetCountOfActiveNodes()),
 	}
-	return &btc
+	return &btc, nil
 }
@@ -0,0 +1,123 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// progressBlocks is the number of the blocks between the progress records of the log
+	progressBlocks = 1000
+	// playAttempts is the number of the attempts to play the block with the transient database failures
+	playAttempts   = 5
+	playRetryDelay = 200 * time.Millisecond
+)
+
+var (
+	// ErrBlockNotArchived is returned by BlockArchiver if the archive has no block
+	ErrBlockNotArchived = errors.New("block isn't in the archive")
+	// ErrDivergentBlock is returned if the committed block differs from the archived one
+	ErrDivergentBlock = errors.New("committed block differs from the archived one")
+)
+
+// BlockArchiver returns the binaries of the blocks from the external archive
+type BlockArchiver interface {
+	// Block returns the binary of the block blockID like it's sent between the nodes
+	Block(ctx context.Context, blockID int64) ([]byte, error)
+}
+
+var (
+	// committedBlockHash returns the hash of the block of block_chain, false if the block isn't committed
+	committedBlockHash = func(db *sqldb.DbTransaction, blockID int64) ([]byte, bool, error) {
+		bc := &sqldb.BlockChain{}
+		found, err := bc.GetTransaction(db, blockID)
+		return bc.Hash, found, err
+	}
+	// applyBlock checks and plays the archived block like the downloaded one, the time and the slot of
+	// the block aren't checked against the local clock and the current honor nodes
+	applyBlock = func(ctx context.Context, data []byte, blockID int64) error {
+		return block.PlayRetry(ctx, playAttempts, playRetryDelay, func() error {
+			bl, err := block.ProcessBlockByBinData(data, blockID != 1)
+			if err != nil {
+				return err
+			}
+			bl.Origin = block.OriginArchive
+			if err = bl.Check(); err != nil {
+				return err
+			}
+			return bl.PlaySafe()
+		})
+	}
+)
+
+// ReplayFromArchive rebuilds the state of the node by playing the blocks fromBlock...toBlock of the archive
+// in order. The blocks which are in block_chain of db are skipped, so the replay is restarted from the same
+// fromBlock after the failure and continues from the first block which isn't committed. db is nil for the
+// connection of the node
+func ReplayFromArchive(ctx context.Context, archiver BlockArchiver, fromBlock, toBlock int64, db *sqldb.DbTransaction) error {
+	if fromBlock < 1 || toBlock < fromBlock {
+		return fmt.Errorf("wrong range of blocks %d-%d", fromBlock, toBlock)
+	}
+	logger := log.WithFields(log.Fields{"from_block": fromBlock, "to_block": toBlock})
+	logger.Info("replaying blocks from archive")
+	var skipped, played int64
+	start := time.Now()
+	for blockID := fromBlock; blockID <= toBlock; blockID++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := archiver.Block(ctx, blockID)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.IOError, "error": err, "block_id": blockID}).Error("reading archived block")
+			return fmt.Errorf("reading block %d: %w", blockID, err)
+		}
+		header, err := types.ParseBlockHeader(bytes.NewBuffer(data), int64(len(data)))
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParseError, "error": err, "block_id": blockID}).Error("parsing archived block")
+			return fmt.Errorf("parsing block %d: %w", blockID, err)
+		}
+		if header.BlockId != blockID {
+			return fmt.Errorf("archive returns block %d instead of %d", header.BlockId, blockID)
+		}
+		hash, found, err := committedBlockHash(db, blockID)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "block_id": blockID}).Error("getting committed block")
+			return err
+		}
+		if found {
+			if !bytes.Equal(hash, header.BlockHash) {
+				logger.WithFields(log.Fields{"type": consts.BlockError, "block_id": blockID, "hash": fmt.Sprintf("%x", hash),
+					"archived_hash": fmt.Sprintf("%x", header.BlockHash)}).Error("checking committed block")
+				return fmt.Errorf("%w: block %d", ErrDivergentBlock, blockID)
+			}
+			skipped++
+		} else {
+			if err = applyBlock(ctx, data, blockID); err != nil {
+				logger.WithFields(log.Fields{"type": consts.BlockError, "error": err, "block_id": blockID}).Error("playing archived block")
+				return fmt.Errorf("playing block %d: %w", blockID, err)
+			}
+			played++
+		}
+		if done := blockID - fromBlock + 1; done%progressBlocks == 0 {
+			logger.WithFields(log.Fields{"block_id": blockID, "played": played, "skipped": skipped,
+				"left": toBlock - blockID, "duration": time.Since(start).String()}).Info("replaying blocks from archive")
+		}
+	}
+	logger.WithFields(log.Fields{"played": played, "skipped": skipped, "duration": time.Since(start).String()}).
+		Info("blocks are replayed from archive")
+	return nil
+}
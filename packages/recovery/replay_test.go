@@ -0,0 +1,82 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package recovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+type testArchiver map[int64][]byte
+
+func (a testArchiver) Block(_ context.Context, blockID int64) ([]byte, error) {
+	if data, ok := a[blockID]; ok {
+		return data, nil
+	}
+	return nil, ErrBlockNotArchived
+}
+
+func TestReplayFromArchive(t *testing.T) {
+	archive := make(testArchiver)
+	for id := int64(1); id <= 10; id++ {
+		data, err := proto.Marshal(&types.BlockData{Header: &types.BlockHeader{BlockId: id, BlockHash: []byte(fmt.Sprint("hash", id))}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		archive[id] = data
+	}
+
+	chain := make(map[int64][]byte)
+	crashAt := int64(6)
+	defer func(c func(*sqldb.DbTransaction, int64) ([]byte, bool, error), a func(context.Context, []byte, int64) error) {
+		committedBlockHash, applyBlock = c, a
+	}(committedBlockHash, applyBlock)
+	committedBlockHash = func(_ *sqldb.DbTransaction, blockID int64) ([]byte, bool, error) {
+		hash, ok := chain[blockID]
+		return hash, ok, nil
+	}
+	var applied []int64
+	applyBlock = func(_ context.Context, data []byte, blockID int64) error {
+		if blockID == crashAt {
+			return errors.New("crash")
+		}
+		if _, ok := chain[blockID-1]; blockID > 1 && !ok {
+			t.Fatalf("block %d is played before the previous one", blockID)
+		}
+		chain[blockID] = []byte(fmt.Sprint("hash", blockID))
+		applied = append(applied, blockID)
+		return nil
+	}
+
+	if err := ReplayFromArchive(context.Background(), archive, 1, 10, nil); err == nil {
+		t.Fatal("crash isn't returned")
+	}
+	if len(applied) != 5 {
+		t.Fatalf("wrong played blocks %v", applied)
+	}
+
+	crashAt, applied = 0, nil
+	if err := ReplayFromArchive(context.Background(), archive, 1, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 5 || applied[0] != 6 || applied[4] != 10 {
+		t.Errorf("replay isn't continued from the crash %v", applied)
+	}
+
+	chain[3] = []byte("fork")
+	if err := ReplayFromArchive(context.Background(), archive, 1, 10, nil); !errors.Is(err, ErrDivergentBlock) {
+		t.Errorf("expected divergent block, got %v", err)
+	}
+	if err := ReplayFromArchive(context.Background(), archive, 11, 12, nil); !errors.Is(err, ErrBlockNotArchived) {
+		t.Errorf("expected missing block, got %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
@@ -44,27 +45,59 @@ func RollbackBlock(data []byte) error {
 		return err
 	}
 
-	err = rollbackBlock(dbTx, bl)
-	if err != nil {
+	if err = deleteBlock(dbTx, bl); err != nil {
 		dbTx.Rollback()
 		return err
 	}
+	if err = dbTx.Commit(); err != nil {
+		return err
+	}
+	// the rolled back parameters are reloaded, e.g. the key of the rotation
+	if bl.SysUpdate {
+		return syspar.SysUpdate(nil)
+	}
+	return nil
+}
+
+// deleteBlock rolls back the last block bl within dbTx and sets the info block to the previous block
+func deleteBlock(dbTx *sqldb.DbTransaction, bl *block.Block) error {
+	err := rollbackBlock(dbTx, bl)
+	if err != nil {
+		return err
+	}
 
+	b := &sqldb.BlockChain{}
 	if err = b.DeleteById(dbTx, bl.Header.BlockId); err != nil {
 		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block by id")
-		dbTx.Rollback()
+		return err
+	}
+	if err = sqldb.DeleteBlockFeeStats(dbTx, bl.Header.BlockId); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block fee stats")
+		return err
+	}
+	if err = sqldb.DeleteResourceUsage(dbTx, bl.Header.BlockId); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block resource usage")
+		return err
+	}
+	if err = sqldb.DeleteBlockResources(dbTx, bl.Header.BlockId); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting block resources")
+		return err
+	}
+	if err = sqldb.DeleteContractEvents(dbTx, bl.Header.BlockId); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting contract events")
+		return err
+	}
+	if err = sqldb.DeleteTxReceipts(dbTx, bl.Header.BlockId); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("deleting tx receipts")
 		return err
 	}
 
-	b = &sqldb.BlockChain{}
 	if _, err = b.Get(bl.Header.BlockId - 1); err != nil {
-		dbTx.Rollback()
 		return err
 	}
 
-	bl, err = block.UnmarshallBlock(bytes.NewBuffer(b.Data), false)
+	prev, err := block.UnmarshallBlock(bytes.NewBuffer(b.Data), false)
 	if err != nil {
-		dbTx.Rollback()
 		return err
 	}
 
@@ -75,17 +108,11 @@ func RollbackBlock(data []byte) error {
 		NodePosition:   strconv.Itoa(int(b.NodePosition)),
 		KeyID:          b.KeyID,
 		Time:           b.Time,
-		CurrentVersion: strconv.Itoa(int(bl.Header.Version)),
+		CurrentVersion: strconv.Itoa(int(prev.Header.Version)),
 		ConsensusMode:  b.ConsensusMode,
 		CandidateNodes: b.CandidateNodes,
 	}
-	err = ib.Update(dbTx)
-	if err != nil {
-		dbTx.Rollback()
-		return err
-	}
-
-	return dbTx.Commit()
+	return ib.Update(dbTx)
 }
 
 func rollbackBlock(dbTx *sqldb.DbTransaction, block *block.Block) error {
@@ -121,6 +148,10 @@ func rollbackBlock(dbTx *sqldb.DbTransaction, block *block.Block) error {
 		}
 
 		switch t.Inner.(type) {
+		case *transaction.KeyRotationParser:
+			if err = rollbackTransaction(t.Hash(), t.DbTransaction, logger); err != nil {
+				return err
+			}
 		case *transaction.SmartTransactionParser:
 			t.Inner.(*transaction.SmartTransactionParser).DbTransaction = t.DbTransaction
 			if err = rollbackTransaction(t.Hash(), t.DbTransaction, logger); err != nil {
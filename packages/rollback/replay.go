@@ -0,0 +1,79 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package rollback
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Replay plays the existing block blockID again and writes its full trace to w. The blocks from the last one
+// down to blockID are rolled back within the db transaction which is discarded after the play, so the state
+// of the database isn't changed. The contracts of the vm are rolled back too, so the process must not play
+// the blocks after the replay. The delayed contracts of the block are classified by the current state
+func Replay(blockID int64, w io.Writer) error {
+	last := &sqldb.BlockChain{}
+	found, err := last.GetMaxBlock()
+	if err != nil {
+		return err
+	}
+	if !found || blockID < 2 || blockID > last.ID {
+		return fmt.Errorf("block %d isn't played again, the blocks are 2..%d", blockID, last.ID)
+	}
+	replayed := &sqldb.BlockChain{}
+	if _, err = replayed.Get(blockID); err != nil {
+		return err
+	}
+	data, err := replayed.BlockData()
+	if err != nil {
+		return errors.WithMessagef(err, "block_id: %d", blockID)
+	}
+
+	dbTx, err := sqldb.StartTransaction()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("starting transaction")
+		return err
+	}
+	defer func() {
+		dbTx.Rollback()
+		if errSys := syspar.SysUpdate(nil); errSys != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": errSys}).Error("updating syspar")
+		}
+	}()
+	for id := last.ID; id >= blockID; id-- {
+		bc := &sqldb.BlockChain{}
+		if _, err = bc.Get(id); err != nil {
+			return err
+		}
+		blockData, err := bc.BlockData()
+		if err != nil {
+			return errors.WithMessagef(err, "block_id: %d", id)
+		}
+		bl, err := block.UnmarshallBlock(bytes.NewBuffer(blockData), true)
+		if err != nil {
+			return errors.WithMessagef(err, "block_id: %d", id)
+		}
+		if err = deleteBlock(dbTx, bl); err != nil {
+			return errors.WithMessagef(err, "block_id: %d", id)
+		}
+	}
+	// the parameters of the previous block
+	if err = syspar.SysUpdate(dbTx); err != nil {
+		return err
+	}
+	bl, err := block.ProcessBlockByBinData(data, false)
+	if err != nil {
+		return err
+	}
+	return bl.Replay(dbTx, w)
+}
@@ -40,7 +40,11 @@ func ToBlockID(blockID int64, dbTx *sqldb.DbTransaction, logger *log.Entry) erro
 		}
 		for _, block := range blocks {
 			// roll back our blocks to the block blockID
-			err = RollbackBlock(block.Data)
+			data, err := block.BlockData()
+			if err != nil {
+				return errors.WithMessagef(err, "block_id: %d", block.ID)
+			}
+			err = RollbackBlock(data)
 			if err != nil {
 				return errors.WithMessagef(err, "block_id: %d", block.ID)
 			}
@@ -6,13 +6,11 @@ package rollback
 
 import (
 	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 
 	"github.com/IBAX-io/go-ibax/packages/consts"
-	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/smart"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 
@@ -88,56 +86,28 @@ func rollbackTransaction(txHash []byte, dbTx *sqldb.DbTransaction, logger *log.E
 				err = smart.SysRollbackDeleteColumn(dbTx, sysData)
 			case "DeleteTable":
 				err = smart.SysRollbackDeleteTable(dbTx, sysData)
+			case "DeleteContract":
+				err = smart.SysRollbackDeleteContract(sysData)
+			case "RestoreContract":
+				err = smart.SysRollbackRestoreContract(sysData)
+			case "PurgeObject":
+				err = smart.SysRollbackPurgeObject(dbTx, sysData)
+			case "ProposalSnapshot":
+				err = smart.SysRollbackProposalSnapshot(dbTx, sysData)
 			}
 			if err != nil {
 				return err
 			}
 			continue
 		}
-		table := tx[`table_name`]
-		var (
-			rollbackInfo   map[string]string
-			ecoID, keyName string
-			isFirstTable   bool
-		)
-		if len(tx["data"]) > 0 {
-			if err := json.Unmarshal([]byte(tx["data"]), &rollbackInfo); err != nil {
-				logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollback.Data from json")
-				return err
-			}
-			if len(rollbackInfo) > 0 {
-				if v, ok := rollbackInfo["ecosystem"]; ok {
-					ecoID = v
-				}
-			}
-		}
-		if under := strings.IndexByte(table, '_'); under > 0 {
-			keyName = table[under+1:]
-			if v, ok := converter.FirstEcosystemTables[keyName]; ok && v {
-				isFirstTable = true
-			}
-		}
-		where := ` WHERE "id"='`
-
-		if len(tx["data"]) <= 0 {
-			if isFirstTable {
-				var a []string
-				if strings.Contains(tx["table_id"], ",") {
-					a = strings.Split(tx["table_id"], ",")
-					ecoID = a[1]
-					where += a[0] + `'`
-				}
-			}
-		}
-		if len(tx["data"]) > 0 && isFirstTable {
-			where += tx["table_id"] + `'`
-		}
-		if isFirstTable {
-			where += fmt.Sprintf(` AND "ecosystem"='%d'`, converter.StrToInt64(ecoID))
-			tx[`table_name`] = `1_` + keyName
-		} else {
-			where += tx["table_id"] + `'`
+		rt := &sqldb.RollbackTx{NameTable: tx["table_name"], TableID: tx["table_id"], Data: tx["data"]}
+		row, err := rt.Row()
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling rollback.Data from json")
+			return err
 		}
+		where := row.Where()
+		tx[`table_name`] = row.Table
 		if len(tx["data"]) > 0 {
 			if err := rollbackUpdatedRow(tx, where, dbTx, logger); err != nil {
 				return err
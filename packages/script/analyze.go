@@ -0,0 +1,205 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package script
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The severities of the findings of Analyze
+const (
+	SeverityError   = msgError
+	SeverityWarning = msgWarning
+	SeverityInfo    = msgInfo
+)
+
+// AnalysisFinding is the possible vulnerability of the source of the contract
+type AnalysisFinding struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+}
+
+// Analyze performs the static checks of the source of the contracts. It finds the arithmetic on money
+// which can overflow, the loops bounded by the external data, the reads of all columns of the table and
+// the cross-contract calls whose results are dropped. The source which can't be compiled has the error
+// finding, the checks are heuristic and go by the names, so the other findings don't prevent the compilation
+func Analyze(source string) []AnalysisFinding {
+	lexemes, err := lexParser([]rune(source))
+	if err != nil {
+		return []AnalysisFinding{{Severity: SeverityError, Message: err.Error()}}
+	}
+	a := &analyzer{lexemes: lexemes, money: moneyNames(lexemes)}
+	for i, lex := range lexemes {
+		switch {
+		case lex.Type == lexOper && lex.Value == uint32(isAsterisk):
+			a.checkMoneyProduct(i)
+		case lex.Type == lexKeyword|keyWhile<<8:
+			a.checkLoopBound(i)
+		case lex.Type == lexIdent:
+			switch name := lex.Value.(string); {
+			case name == `Int`:
+				a.checkMoneyToInt(i)
+			case name == `DBFind`:
+				a.checkColumns(i)
+			case name == `CallContract`, strings.HasPrefix(name, `@`):
+				a.checkCallResult(i)
+			}
+		}
+	}
+	sort.SliceStable(a.findings, func(i, j int) bool {
+		if a.findings[i].Line != a.findings[j].Line {
+			return a.findings[i].Line < a.findings[j].Line
+		}
+		return a.findings[i].Column < a.findings[j].Column
+	})
+	return a.findings
+}
+
+type analyzer struct {
+	lexemes  Lexemes
+	money    map[string]bool
+	findings []AnalysisFinding
+}
+
+func (a *analyzer) add(severity string, lex *Lexeme, format string, args ...any) {
+	a.findings = append(a.findings, AnalysisFinding{Severity: severity, Line: int(lex.Line),
+		Column: int(lex.Column), Message: fmt.Sprintf(format, args...)})
+}
+
+// moneyNames returns the names of the variables, the parameters and the data fields of money type.
+// The scopes aren't taken into account
+func moneyNames(lexemes Lexemes) map[string]bool {
+	names := make(map[string]bool)
+	for i, lex := range lexemes {
+		if lex.Type != lexType || lex.Ext != DtMoney {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if lexemes[j].Type == lexIdent {
+				names[lexemes[j].Value.(string)] = true
+			} else if lexemes[j].Type != isComma {
+				break
+			}
+		}
+	}
+	return names
+}
+
+// isMoney returns true if the lexeme is the variable or the data field of money type
+func (a *analyzer) isMoney(i int) bool {
+	if i < 0 || i >= len(a.lexemes) {
+		return false
+	}
+	lex := a.lexemes[i]
+	name, ok := lex.Value.(string)
+	return ok && (lex.Type == lexIdent || lex.Type == lexExtend) && a.money[name]
+}
+
+func (a *analyzer) checkMoneyProduct(i int) {
+	if a.isMoney(i-1) && a.isMoney(i+1) {
+		a.add(SeverityWarning, a.lexemes[i], "product of money values %s and %s can overflow the money columns",
+			a.lexemes[i-1].Value, a.lexemes[i+1].Value)
+	}
+}
+
+func (a *analyzer) checkMoneyToInt(i int) {
+	if a.is(i+1, isLPar) && a.isMoney(i+2) && a.is(i+3, isRPar) {
+		a.add(SeverityWarning, a.lexemes[i], "conversion of money value %s to int overflows over 9223372036854775807",
+			a.lexemes[i+2].Value)
+	}
+}
+
+// checkLoopBound warns about the condition of while which depends on the data of the transaction
+// or the extended variables
+func (a *analyzer) checkLoopBound(i int) {
+	for j := i + 1; j < len(a.lexemes) && !a.is(j, isLCurly); j++ {
+		if a.lexemes[j].Type == lexExtend {
+			a.add(SeverityWarning, a.lexemes[i], "loop is bounded by external data $%s, limit the number of iterations",
+				a.lexemes[j].Value)
+			return
+		}
+	}
+}
+
+// checkColumns warns about DBFind which reads all columns of the table
+func (a *analyzer) checkColumns(i int) {
+	if !a.is(i+1, isLPar) {
+		return
+	}
+	var columns bool
+	for j := a.closing(i + 1); j >= 0 && a.is(j+1, isDot) && a.is(j+3, isLPar); {
+		end := a.closing(j + 3)
+		if end < 0 {
+			break
+		}
+		if a.lexemes[j+2].Value == `Columns` {
+			columns = true
+			for k := j + 4; k < end; k++ {
+				if s, ok := a.lexemes[k].Value.(string); ok && a.lexemes[k].Type == lexString && hasStarColumn(s) {
+					a.add(SeverityWarning, a.lexemes[j+2], "DBFind fetches all columns, list the columns which are used")
+					break
+				}
+			}
+		}
+		j = end
+	}
+	if !columns {
+		a.add(SeverityInfo, a.lexemes[i], "DBFind without Columns fetches all columns")
+	}
+}
+
+func hasStarColumn(columns string) bool {
+	for _, c := range strings.Split(columns, `,`) {
+		if strings.TrimSpace(c) == `*` {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCallResult warns about the call of the contract which is the whole statement
+func (a *analyzer) checkCallResult(i int) {
+	if !a.is(i+1, isLPar) || (i > 0 && !a.isStatementEdge(i-1)) {
+		return
+	}
+	end := a.closing(i + 1)
+	if end < 0 || (end+1 < len(a.lexemes) && !a.isStatementEdge(end+1)) {
+		return
+	}
+	name := a.lexemes[i].Value.(string)
+	if name == `CallContract` && a.lexemes[i+2].Type == lexString {
+		name = a.lexemes[i+2].Value.(string)
+	}
+	a.add(SeverityWarning, a.lexemes[i], "result of the call of contract %s isn't checked", name)
+}
+
+func (a *analyzer) isStatementEdge(i int) bool {
+	t := a.lexemes[i].Type
+	return t == lexNewLine || t == isLCurly || t == isRCurly
+}
+
+func (a *analyzer) is(i int, t uint32) bool {
+	return i >= 0 && i < len(a.lexemes) && a.lexemes[i].Type == t
+}
+
+// closing returns the index of the closing bracket of the bracket at i, -1 if it isn't closed
+func (a *analyzer) closing(i int) int {
+	var depth int
+	for ; i < len(a.lexemes); i++ {
+		switch a.lexemes[i].Type {
+		case isLPar:
+			depth++
+		case isRPar:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
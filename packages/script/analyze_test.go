@@ -0,0 +1,62 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	source := `contract Pay {
+	data {
+		Amount money
+		Count int
+	}
+	action {
+		var rate, fee money
+		var i int
+		var r map
+		fee = $Amount * rate
+		i = Int(fee) + 10
+		while i < $Count {
+			i = i + 1
+		}
+		r = DBFind("keys").Columns("*").Where({"id": 1}).Row()
+		r = DBFind("keys").Columns("id, amount").Row()
+		r = DBFind("keys").Row()
+		CallContract("Transfer", r)
+		@1Other("x", 1)
+		if CallContract("Check", r) {
+			r = @1Other("x", 2)
+		}
+	}
+}`
+	want := []AnalysisFinding{
+		{SeverityWarning, 10, 0, "product of money values Amount and rate"},
+		{SeverityWarning, 11, 0, "conversion of money value fee to int"},
+		{SeverityWarning, 12, 0, "loop is bounded by external data $Count"},
+		{SeverityWarning, 15, 0, "DBFind fetches all columns"},
+		{SeverityInfo, 17, 0, "DBFind without Columns"},
+		{SeverityWarning, 18, 0, "result of the call of contract Transfer isn't checked"},
+		{SeverityWarning, 19, 0, "result of the call of contract @1Other isn't checked"},
+	}
+	findings := Analyze(source)
+	if len(findings) != len(want) {
+		t.Fatalf("findings %v, want %d", findings, len(want))
+	}
+	for i, f := range findings {
+		if f.Severity != want[i].Severity || f.Line != want[i].Line || !strings.HasPrefix(f.Message, want[i].Message) {
+			t.Errorf("finding %d is %+v, want %+v", i, f, want[i])
+		}
+	}
+
+	if findings = Analyze("contract A { action { var a int \n a = 1 ? 2 } }"); len(findings) != 1 || findings[0].Severity != SeverityError {
+		t.Errorf("wrong findings of the invalid source %v", findings)
+	}
+	if findings = Analyze("contract A { action { var a int \n a = 2 * 3 } }"); len(findings) != 0 {
+		t.Errorf("findings of the safe source %v", findings)
+	}
+}
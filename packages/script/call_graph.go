@@ -0,0 +1,203 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package script
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
+)
+
+// CallGraph is the graph of the calls between the contracts of the ecosystem. The contracts of the
+// other ecosystems are the nodes with @ecosystem prefix, they are External and their calls aren't known
+type CallGraph struct {
+	Nodes  []CallNode `json:"nodes"`
+	Edges  []CallEdge `json:"edges"`
+	Cycles [][]string `json:"cycles"`
+}
+
+// CallNode is the contract of the call graph
+type CallNode struct {
+	Name     string `json:"name"`
+	External bool   `json:"external,omitempty"`
+}
+
+// CallEdge is the call of the contract Callee by the contract Caller
+type CallEdge struct {
+	Caller string `json:"caller"`
+	Callee string `json:"callee"`
+}
+
+// BuildCallGraph returns the graph of the calls between the contracts of the ecosystem. The calls are
+// found in the sources of the contracts: CallContract with the name of the contract, the call by the
+// name of the contract of the ecosystem and the call of @ecosystem contract. The names of CallContract
+// which aren't constants are unknown. Cycles are the groups of the contracts which call each other
+func BuildCallGraph(ecoID int64, db *sqldb.DbTransaction) (*CallGraph, error) {
+	contracts, err := new(sqldb.Contract).GetFromEcosystem(db, ecoID)
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "ecosystem": ecoID}).Error("getting contracts of ecosystem")
+		return nil, err
+	}
+	sources := make(map[string]string, len(contracts))
+	for _, c := range contracts {
+		if c.Deleted == 0 {
+			sources[c.Name] = c.Value
+		}
+	}
+	return callGraphOf(ecoID, sources), nil
+}
+
+// callGraphOf returns the call graph of the sources of the contracts by their names
+func callGraphOf(ecoID int64, sources map[string]string) *CallGraph {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &CallGraph{Nodes: make([]CallNode, 0, len(names)), Edges: make([]CallEdge, 0), Cycles: make([][]string, 0)}
+	external := make(map[string]bool)
+	calls := make(map[string][]string)
+	for _, name := range names {
+		g.Nodes = append(g.Nodes, CallNode{Name: name})
+		lexemes, err := lexParser([]rune(sources[name]))
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.ParseError, "error": err, "contract": name}).Warn("parsing contract of call graph")
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, callee := range contractCalls(lexemes) {
+			if callee = localName(ecoID, callee); seen[callee] {
+				continue
+			}
+			if _, ok := sources[callee]; !ok {
+				if !strings.HasPrefix(callee, `@`) {
+					continue
+				}
+				external[callee] = true
+			}
+			seen[callee] = true
+			calls[name] = append(calls[name], callee)
+			g.Edges = append(g.Edges, CallEdge{Caller: name, Callee: callee})
+		}
+	}
+	ext := make([]string, 0, len(external))
+	for name := range external {
+		ext = append(ext, name)
+	}
+	sort.Strings(ext)
+	for _, name := range ext {
+		g.Nodes = append(g.Nodes, CallNode{Name: name, External: true})
+	}
+	g.Cycles = callCycles(names, calls)
+	return g
+}
+
+// contractCalls returns the names of the called contracts, the names of the idents are the possible
+// contracts, they are filtered by the caller
+func contractCalls(lexemes Lexemes) []string {
+	var calls []string
+	is := func(i int, t uint32) bool {
+		return i >= 0 && i < len(lexemes) && lexemes[i].Type == t
+	}
+	for i, lex := range lexemes {
+		if lex.Type != lexIdent || !is(i+1, isLPar) || is(i-1, isDot) || is(i-1, lexKeyword|keyFunc<<8) {
+			continue
+		}
+		name := lex.Value.(string)
+		if name == `CallContract` {
+			if is(i+2, lexString) {
+				calls = append(calls, lexemes[i+2].Value.(string))
+			}
+			continue
+		}
+		calls = append(calls, name)
+	}
+	return calls
+}
+
+// localName returns the name of the contract of the ecosystem without @ecosystem prefix
+func localName(ecoID int64, name string) string {
+	if !strings.HasPrefix(name, `@`) {
+		return name
+	}
+	i := 1
+	for i < len(name) && name[i] >= '0' && name[i] <= '9' {
+		i++
+	}
+	if eco, err := strconv.ParseInt(name[1:i], 10, 64); err == nil && eco == ecoID && i < len(name) {
+		return name[i:]
+	}
+	return name
+}
+
+// callCycles returns the strongly connected components of the calls which have the cycles, the contract
+// which calls itself is the cycle too. The components are found by Tarjan's algorithm
+func callCycles(names []string, calls map[string][]string) [][]string {
+	var (
+		index   int
+		indexes = make(map[string]int)
+		low     = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		cycles  = make([][]string, 0)
+	)
+	var connect func(name string)
+	connect = func(name string) {
+		indexes[name], low[name] = index, index
+		index++
+		stack = append(stack, name)
+		onStack[name] = true
+		for _, callee := range calls[name] {
+			if _, ok := indexes[callee]; !ok {
+				connect(callee)
+				if low[callee] < low[name] {
+					low[name] = low[callee]
+				}
+			} else if onStack[callee] {
+				if indexes[callee] < low[name] {
+					low[name] = indexes[callee]
+				}
+			}
+		}
+		if low[name] != indexes[name] {
+			return
+		}
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+			if top == name {
+				break
+			}
+		}
+		if len(component) > 1 || hasCall(calls[name], name) {
+			sort.Strings(component)
+			cycles = append(cycles, component)
+		}
+	}
+	for _, name := range names {
+		if _, ok := indexes[name]; !ok {
+			connect(name)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+func hasCall(calls []string, name string) bool {
+	for _, callee := range calls {
+		if callee == name {
+			return true
+		}
+	}
+	return false
+}
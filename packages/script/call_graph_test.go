@@ -0,0 +1,34 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package script
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCallGraph(t *testing.T) {
+	g := callGraphOf(2, map[string]string{
+		`A`: `contract A { action { B() 
+			CallContract("C", {}) } }`,
+		`B`: `contract B { func C() int { return 1 } action { var r map 
+			r = DBFind("keys").Row() 
+			@2A() 
+			@1Transfer({}) } }`,
+		`C`: `contract C { action { C() } }`,
+		`D`: `contract D { action { var name string 
+			CallContract(name, {}) } }`,
+	})
+	want := []CallEdge{{"A", "B"}, {"A", "C"}, {"B", "A"}, {"B", "@1Transfer"}, {"C", "C"}}
+	if !reflect.DeepEqual(g.Edges, want) {
+		t.Errorf("wrong edges %v", g.Edges)
+	}
+	if len(g.Nodes) != 5 || g.Nodes[4] != (CallNode{Name: "@1Transfer", External: true}) {
+		t.Errorf("wrong nodes %v", g.Nodes)
+	}
+	if !reflect.DeepEqual(g.Cycles, [][]string{{"A", "B"}, {"C"}}) {
+		t.Errorf("wrong cycles %v", g.Cycles)
+	}
+}
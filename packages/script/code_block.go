@@ -120,6 +120,7 @@ type OwnerInfo struct {
 	TableID  int64  `json:"tableid"`
 	WalletID int64  `json:"walletid"`
 	TokenID  int64  `json:"tokenid"`
+	Deleted  bool   `json:"deleted"` // the contract is soft-deleted, it can't be called until it's restored
 }
 
 // ObjInfo is the common object type
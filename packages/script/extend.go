@@ -27,6 +27,14 @@ const (
 	Extend_pre_block_data_hash = `pre_block_data_hash`
 	Extend_gen_block           = `gen_block`
 	Extend_time_limit          = `time_limit`
+	// the block context which is the same on all the nodes, unlike gen_block
+	Extend_tx_type  = `tx_type`
+	Extend_block_id = `block_id`
+	Extend_tx_hash  = `tx_hash`
+
+	// Extend_ctx is the context of the transaction which cancels the contract, the name isn't
+	// an identifier, so the contracts can't read or change it
+	Extend_ctx = `@ctx`
 
 	Extend_rt_state = `rt_state`
 	Extend_rt       = `rt`
@@ -62,4 +70,8 @@ const (
 	sysVars_gen_block           = `gen_block`
 	sysVars_time_limit          = `time_limit`
 	sysVars_pre_block_data_hash = `pre_block_data_hash`
+	sysVars_tx_type             = `tx_type`
+	sysVars_block_id            = `block_id`
+	sysVars_tx_hash             = `tx_hash`
+	sysVars_ctx                 = Extend_ctx
 )
@@ -38,6 +38,9 @@ func NewExtendData() *ExtendData {
 // params are the values of parameters
 func ExecContract(rt *RunTime, name, txs string, params ...any) (any, error) {
 	contract, ok := rt.vm.Objects[name]
+	if ok && contract.Type == ObjectType_Contract && contract.GetCodeBlock().GetContractInfo().IsDeleted() {
+		ok = false
+	}
 	if !ok {
 		log.WithFields(log.Fields{"contract_name": name, "type": consts.ContractError}).Error("unknown contract")
 		return nil, fmt.Errorf(eUnknownContract, name)
@@ -110,6 +113,7 @@ func ExecContract(rt *RunTime, name, txs string, params ...any) (any, error) {
 		}
 	}
 
+	cost := rt.cost
 	if err := rt.SubCost(CostContract); err != nil {
 		return nil, err
 	}
@@ -146,6 +150,9 @@ func ExecContract(rt *RunTime, name, txs string, params ...any) (any, error) {
 	if stack != nil {
 		stack.PopStack(name)
 	}
+	if recorder, ok := rt.extend[Extend_sc].(CallRecorder); ok {
+		recorder.RecordCall(name, cost-rt.cost, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -169,6 +176,9 @@ func ExecContract(rt *RunTime, name, txs string, params ...any) (any, error) {
 func ExContract(rt *RunTime, state uint32, name string, params *types.Map) (any, error) {
 	name = StateName(state, name)
 	contract, ok := rt.vm.Objects[name]
+	if ok && contract.Type == ObjectType_Contract && contract.GetCodeBlock().GetContractInfo().IsDeleted() {
+		ok = false
+	}
 	if !ok {
 		log.WithFields(log.Fields{"contract_name": name, "type": consts.ContractError}).Error("unknown contract")
 		return nil, fmt.Errorf(eUnknownContract, name)
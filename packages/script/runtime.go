@@ -5,11 +5,13 @@
 package script
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
@@ -70,14 +72,47 @@ var sysVars = map[string]struct{}{
 	sysVars_gen_block:           {},
 	sysVars_time_limit:          {},
 	sysVars_pre_block_data_hash: {},
+	sysVars_tx_type:             {},
+	sysVars_block_id:            {},
+	sysVars_tx_hash:             {},
+	sysVars_ctx:                 {},
 }
 
 var (
 	ErrMemoryLimit = errors.New("Memory limit exceeded")
 	//ErrVMTimeLimit returns when the time limit exceeded
 	ErrVMTimeLimit = errors.New(`time limit exceeded`)
+	// ErrVMCanceled returns when the context of the contract is canceled before its deadline
+	ErrVMCanceled = errors.New(`execution canceled`)
 )
 
+// IsCanceled returns true if the contract is stopped by the time limit or by the cancellation of its context
+func IsCanceled(err error) bool {
+	return errors.Is(err, ErrVMTimeLimit) || errors.Is(err, ErrVMCanceled)
+}
+
+// canceledError is the error of the stopped contract with its position in the code, it keeps the cause
+// for errors.Is
+type canceledError struct {
+	msg string
+	err error
+}
+
+func (e *canceledError) Error() string { return e.msg }
+func (e *canceledError) Unwrap() error { return e.err }
+
+// cancelError returns the error of the contract which is stopped by the context
+func cancelError(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if cause == nil || errors.Is(cause, context.DeadlineExceeded) {
+		return ErrVMTimeLimit
+	}
+	if IsCanceled(cause) {
+		return cause
+	}
+	return fmt.Errorf("%w: %v", ErrVMCanceled, cause)
+}
+
 // VMError represents error of VM
 type VMError struct {
 	Type  string `json:"type"`
@@ -105,9 +140,11 @@ type RunTime struct {
 	cost      int64 //cost remaining
 	err       error
 	unwrap    bool
-	timeLimit bool
+	timeLimit atomic.Bool // the context of Run is done
+	stopErr   error       // the error of the done context, it's set before timeLimit
 	callDepth uint16
 	mem       int64
+	memPeak   int64 // the maximum of mem
 	memVars   map[any]int64
 	errInfo   ErrInfo
 }
@@ -594,6 +631,12 @@ func (rt *RunTime) RunCode(block *CodeBlock) (status int, err error) {
 			err = errors.Errorf(`runtime run code crashed: %v`, r)
 		}
 		if err != nil && !strings.HasPrefix(err.Error(), `{`) {
+			cause := err
+			defer func() {
+				if IsCanceled(cause) {
+					err = &canceledError{msg: err.Error(), err: cause}
+				}
+			}()
 			var curContract, line string
 			if block.isParentContract() {
 				stack := block.Parent.GetContractInfo()
@@ -685,11 +728,14 @@ main:
 		if err = rt.SubCost(1); err != nil {
 			break
 		}
-		if rt.timeLimit {
-			err = ErrVMTimeLimit
+		if rt.timeLimit.Load() {
+			err = rt.stopErr
 			break
 		}
 
+		if rt.mem > rt.memPeak {
+			rt.memPeak = rt.mem
+		}
 		if rt.mem > memoryLimit {
 			rt.vm.logger.WithFields(log.Fields{"type": consts.VMError}).Warn(ErrMemoryLimit)
 			err = ErrMemoryLimit
@@ -1398,18 +1444,30 @@ func (rt *RunTime) Run(block *CodeBlock, params []any, extend map[string]any) (r
 	}()
 	info := block.GetFuncInfo()
 	rt.extend = extend
-	var (
-		genBlock bool
-		timer    *time.Timer
-	)
-	if gen, ok := extend[Extend_gen_block]; ok {
-		genBlock = gen.(bool)
+	// the contract is stopped by the cancellation of the context of the transaction and by the time limit
+	// of the generator, the validators are bound by the fuel only
+	ctx, ok := extend[Extend_ctx].(context.Context)
+	if !ok || ctx == nil {
+		ctx = context.Background()
 	}
-	timeOver := func() {
-		rt.timeLimit = true
+	if genBlock, _ := extend[Extend_gen_block].(bool); genBlock {
+		if limit, ok := extend[Extend_time_limit].(int64); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Millisecond*time.Duration(limit))
+			defer cancel()
+		}
 	}
-	if genBlock {
-		timer = time.AfterFunc(time.Millisecond*time.Duration(extend[Extend_time_limit].(int64)), timeOver)
+	if done := ctx.Done(); done != nil {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-done:
+				rt.stopErr = cancelError(ctx)
+				rt.timeLimit.Store(true)
+			case <-finished:
+			}
+		}()
 	}
 	if _, err = rt.RunCode(block); err == nil {
 		if rt.len() < len(info.Results) {
@@ -1424,8 +1482,5 @@ func (rt *RunTime) Run(block *CodeBlock, params []any, extend map[string]any) (r
 			ret = append(ret, rt.stack[off+i])
 		}
 	}
-	if genBlock {
-		timer.Stop()
-	}
 	return
 }
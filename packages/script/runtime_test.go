@@ -5,7 +5,10 @@
 package script
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -29,3 +32,61 @@ func TestCalcMem(t *testing.T) {
 		assert.Equal(t, v.mem, calcMem(v.v))
 	}
 }
+
+type testMemRecorder struct {
+	peaks []int64
+}
+
+func (r *testMemRecorder) RecordMem(peak int64) {
+	r.peaks = append(r.peaks, peak)
+}
+
+func TestRecordMem(t *testing.T) {
+	vm := NewVM()
+	err := vm.Compile([]rune(`func mem_test() string {
+		var s string
+		s = "0123456789"
+		s = s + s
+		s = "0"
+		return s
+	}`), &OwnerInfo{StateID: 1, Active: true, TableID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &testMemRecorder{}
+	_, err = vm.Call("mem_test", nil, map[string]any{Extend_rt_state: uint32(1), Extend_txcost: int64(1000), Extend_sc: recorder})
+	assert.NoError(t, err)
+	// the peak is the doubled string even though the variable is short at the end
+	assert.Equal(t, []int64{20}, recorder.peaks)
+}
+
+func TestRunCanceled(t *testing.T) {
+	vm := NewVM()
+	vm.Extern = true
+	if err := vm.Compile([]rune(`func loop() int {
+		var i int
+		while true {
+			i = i + 1
+		}
+		return i
+	}`), &OwnerInfo{StateID: 1, Active: true, TableID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	shutdown := errors.New("shutdown")
+	canceled, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(10*time.Millisecond, func() { cancel(shutdown) })
+	expired, cancelExpired := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelExpired()
+	for _, c := range []struct {
+		ctx context.Context
+		err error
+	}{{canceled, ErrVMCanceled}, {expired, ErrVMTimeLimit}} {
+		_, err := vm.Call(`loop`, nil, map[string]any{`rt_state`: uint32(1), Extend_txcost: int64(1 << 60), Extend_ctx: c.ctx})
+		if !errors.Is(err, c.err) || !IsCanceled(err) {
+			t.Errorf("expected %v, got %v", c.err, err)
+		}
+	}
+	if _, err := vm.Call(`loop`, nil, map[string]any{`rt_state`: uint32(1), Extend_txcost: int64(1000)}); IsCanceled(err) {
+		t.Errorf("contract without context is canceled: %v", err)
+	}
+}
@@ -130,6 +130,9 @@ func VMGetContractByID(vm *VM, id int32) *ContractInfo {
 	if tableID > 0 && vm.Children[idcont].GetContractInfo().Owner.TableID != tableID {
 		return nil
 	}
+	if vm.Children[idcont].GetContractInfo().IsDeleted() {
+		return nil
+	}
 	return vm.Children[idcont].GetContractInfo()
 }
 
@@ -147,7 +150,7 @@ func RunContractByName(vm *VM, name string, methods []string, extend map[string]
 		return fmt.Errorf(`unknown object '%s'`, name)
 	}
 
-	if obj.Type != ObjectType_Contract {
+	if obj.Type != ObjectType_Contract || obj.GetCodeBlock().GetContractInfo().IsDeleted() {
 		return fmt.Errorf(eUnknownContract, name)
 	}
 	contract := obj.GetCodeBlock()
@@ -187,6 +190,7 @@ func VMRun(vm *VM, block *CodeBlock, params []any, extend map[string]any, hash [
 	rt := NewRunTime(vm, cost)
 	ret, err = rt.Run(block, params, extend)
 	extend[Extend_txcost] = rt.Cost()
+	recordMem(rt, extend)
 	if err != nil {
 		vm.logger.WithFields(log.Fields{"type": consts.VMError, "tx_hash": fmt.Sprintf("%x", hash), "error": err, "original_contract": extend[Extend_original_contract], "this_contract": extend[Extend_this_contract], "ecosystem_id": extend[Extend_ecosystem_id]}).Error("running block in smart vm")
 		return nil, err
@@ -64,6 +64,11 @@ type ContractInfo struct {
 	CanWrite bool // If the function can update DB
 }
 
+// IsDeleted returns true if the contract is soft-deleted
+func (c *ContractInfo) IsDeleted() bool {
+	return c != nil && c.Owner != nil && c.Owner.Deleted
+}
+
 func (c *ContractInfo) TxMap() map[string]*FieldInfo {
 	if c == nil {
 		return nil
@@ -129,6 +134,32 @@ type Stacker interface {
 	PopStack(fn string)
 }
 
+// MemRecorder receives the peak memory of the finished run time of the contract
+type MemRecorder interface {
+	RecordMem(peak int64)
+}
+
+// ContractCall is the call of the contract by the other contract, Fuel is used by the call and its nested
+// calls, the top contract is at Depth 1
+type ContractCall struct {
+	Name  string
+	Depth int
+	Fuel  int64
+	Err   error
+}
+
+// CallRecorder receives the finished calls of the contracts
+type CallRecorder interface {
+	RecordCall(name string, fuel int64, err error)
+}
+
+// recordMem passes the peak memory of rt to the recorder of extend
+func recordMem(rt *RunTime, extend map[string]any) {
+	if recorder, ok := extend[Extend_sc].(MemRecorder); ok {
+		recorder.RecordMem(rt.memPeak)
+	}
+}
+
 // NewVM creates a new virtual machine
 func NewVM() *VM {
 	vm := &VM{
@@ -174,6 +205,7 @@ func (vm *VM) Call(name string, params []any, extend map[string]any) (ret []any,
 		rt := NewRunTime(vm, cost)
 		ret, err = rt.Run(obj.GetCodeBlock(), params, extend)
 		extend[Extend_txcost] = rt.Cost()
+		recordMem(rt, extend)
 	case ObjectType_ExtFunc:
 		finfo := obj.GetExtFuncInfo()
 		foo := reflect.ValueOf(finfo.Func)
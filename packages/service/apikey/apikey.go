@@ -0,0 +1,263 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package apikey authenticates the service accounts of the machine-to-machine use of the REST and
+// JSON-RPC api. The key is presented as "Authorization: ApiKey <secret>", it's checked against the
+// database on every request, so the revoked key is rejected at once
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
+)
+
+// Permission is the set of the requests which are allowed for the key
+type Permission string
+
+const (
+	PermRead   Permission = "read"      // reading the chain data
+	PermSubmit Permission = "submit-tx" // reading and sending the signed transactions
+	PermAdmin  Permission = "admin"     // all the requests including the node administration
+)
+
+// Scheme is the prefix of the authorization header with the api key
+const Scheme = "ApiKey "
+
+const (
+	secretPrefix = "ibax_"
+	secretSize   = 32
+	// touchInterval is the precision of the last use, so the key isn't updated on every request
+	touchInterval = time.Minute
+)
+
+var (
+	ErrInvalidKey   = errors.New("api key is invalid or revoked")
+	ErrIPNotAllowed = errors.New("ip address isn't allowed for api key")
+	ErrName         = errors.New("name of api key is empty")
+	ErrPermission   = errors.New("unknown permission of api key")
+)
+
+var levels = map[Permission]int{PermRead: 1, PermSubmit: 2, PermAdmin: 3}
+
+// Valid returns true if the permission is known
+func (p Permission) Valid() bool {
+	return levels[p] > 0
+}
+
+// Allows returns true if the key with p can make the request which needs the permission
+func (p Permission) Allows(need Permission) bool {
+	return p.Valid() && levels[p] >= levels[need]
+}
+
+// Store keeps the service keys
+type Store interface {
+	// GetByHash returns the active key by the hash of the secret or nil
+	GetByHash(hash []byte) (*sqldb.ServiceKey, error)
+	Touch(name string, t time.Time) error
+}
+
+// dbStore is the service_keys table of the node
+type dbStore struct{}
+
+// NodeStore returns the store of the running node
+func NodeStore() Store {
+	return dbStore{}
+}
+
+func (dbStore) GetByHash(hash []byte) (*sqldb.ServiceKey, error) {
+	return sqldb.GetServiceKeyByHash(hash)
+}
+
+func (dbStore) Touch(name string, t time.Time) error {
+	return sqldb.TouchServiceKey(name, t)
+}
+
+// HasScheme returns true if the authorization header contains the api key
+func HasScheme(header string) bool {
+	return strings.HasPrefix(header, Scheme)
+}
+
+// Hash returns the stored hash of the secret. The secrets are random, so they aren't salted
+func Hash(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
+
+// NewSecret returns the random secret of the key
+func NewSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// ParseIPs parses the comma separated ips and cidrs of the allow-list
+func ParseIPs(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip address %s", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// IPAllowed returns true if the remote address of the request matches the allow-list. The empty
+// list allows all the addresses, the forwarded headers aren't trusted
+func IPAllowed(list, remoteAddr string) bool {
+	nets, err := ParseIPs(list)
+	if err != nil {
+		return false
+	}
+	if len(nets) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticate returns the key of the authorization header of the request from remoteAddr
+func Authenticate(store Store, header, remoteAddr string, now time.Time) (*sqldb.ServiceKey, error) {
+	secret := strings.TrimSpace(strings.TrimPrefix(header, Scheme))
+	if len(secret) == 0 {
+		return nil, ErrInvalidKey
+	}
+	key, err := store.GetByHash(Hash(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !Permission(key.Permission).Valid() {
+		return nil, ErrInvalidKey
+	}
+	if !IPAllowed(key.AllowedIPs, remoteAddr) {
+		return nil, ErrIPNotAllowed
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err = store.Touch(key.Name, now); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err, "name": key.Name}).Error("updating last use of api key")
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+type contextKey struct{}
+
+// FromRequest returns the key of the authenticated request or nil
+func FromRequest(r *http.Request) *sqldb.ServiceKey {
+	key, _ := r.Context().Value(contextKey{}).(*sqldb.ServiceKey)
+	return key
+}
+
+// Middleware authenticates the requests with the api key and passes the other ones as is. The
+// failed requests are answered by deny
+func Middleware(store Store, deny func(w http.ResponseWriter, r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !HasScheme(header) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, err := Authenticate(store, header, r.RemoteAddr, time.Now())
+			if err != nil {
+				log.WithFields(log.Fields{"type": consts.AccessDenied, "error": err, "remote": r.RemoteAddr}).Warning("authenticating api key")
+				deny(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, key)))
+		})
+	}
+}
+
+// Create stores the new key and returns its secret, the secret can't be restored later
+func Create(name string, perm Permission, allowedIPs string, keyID, ecosystemID int64) (string, *sqldb.ServiceKey, error) {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", nil, ErrName
+	}
+	if !perm.Valid() {
+		return "", nil, ErrPermission
+	}
+	if _, err := ParseIPs(allowedIPs); err != nil {
+		return "", nil, err
+	}
+	secret, err := NewSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	key := &sqldb.ServiceKey{
+		Name:        name,
+		KeyHash:     Hash(secret),
+		Permission:  string(perm),
+		AllowedIPs:  allowedIPs,
+		KeyID:       keyID,
+		EcosystemID: ecosystemID,
+		CreatedAt:   time.Now(),
+	}
+	if err = key.Create(); err != nil {
+		return "", nil, err
+	}
+	audit(keyID, "create,"+name+","+key.Permission)
+	return secret, key, nil
+}
+
+// Revoke revokes the key with the name, it returns false if there is no such active key
+func Revoke(name string, keyID int64) (bool, error) {
+	ok, err := sqldb.RevokeServiceKey(name, time.Now())
+	if ok {
+		audit(keyID, "revoke,"+name)
+	}
+	return ok, err
+}
+
+func audit(keyID int64, payload string) {
+	if err := sqldb.NewAuditLog(sqldb.AuditServiceKey, keyID, 0, nil, []byte(payload)).Create(nil); err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("writing audit log of api key")
+	}
+}
@@ -0,0 +1,148 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// memStore emulates service_keys, the revoked keys are removed
+type memStore struct {
+	keys    map[string]*sqldb.ServiceKey
+	touched int
+}
+
+func (s *memStore) GetByHash(hash []byte) (*sqldb.ServiceKey, error) {
+	for _, key := range s.keys {
+		if string(key.KeyHash) == string(hash) {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memStore) Touch(name string, t time.Time) error {
+	s.touched++
+	s.keys[name].LastUsedAt = &t
+	return nil
+}
+
+func TestPermission(t *testing.T) {
+	for _, c := range []struct {
+		have, need Permission
+		allowed    bool
+	}{
+		{PermRead, PermRead, true},
+		{PermRead, PermSubmit, false},
+		{PermRead, PermAdmin, false},
+		{PermSubmit, PermRead, true},
+		{PermSubmit, PermSubmit, true},
+		{PermSubmit, PermAdmin, false},
+		{PermAdmin, PermSubmit, true},
+		{PermAdmin, PermAdmin, true},
+		{"", PermRead, false},
+		{"write", PermRead, false},
+	} {
+		if c.have.Allows(c.need) != c.allowed {
+			t.Errorf("%q needs %q: expected %v", c.have, c.need, c.allowed)
+		}
+	}
+}
+
+func TestIPAllowed(t *testing.T) {
+	for _, c := range []struct {
+		list, remote string
+		allowed      bool
+	}{
+		{"", "203.0.113.5:4000", true},
+		{"10.0.0.0/8, 192.168.1.7", "10.2.3.4:4000", true},
+		{"10.0.0.0/8, 192.168.1.7", "192.168.1.7:4000", true},
+		{"10.0.0.0/8, 192.168.1.7", "192.168.1.8:4000", false},
+		{"::1", "[::1]:4000", true},
+		{"10.0.0.0/8", "garbage", false},
+		{"10.0.0.300", "10.0.0.1:4000", false},
+	} {
+		if IPAllowed(c.list, c.remote) != c.allowed {
+			t.Errorf("%q from %q: expected %v", c.list, c.remote, c.allowed)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &memStore{keys: map[string]*sqldb.ServiceKey{
+		"indexer": {Name: "indexer", KeyHash: Hash(secret), Permission: string(PermRead), AllowedIPs: "10.0.0.0/8", KeyID: 7},
+	}}
+	now := time.Now()
+
+	key, err := Authenticate(store, Scheme+secret, "10.1.1.1:4000", now)
+	if err != nil || key.Name != "indexer" {
+		t.Fatalf("expected indexer key, got %v, %v", key, err)
+	}
+	// the last use is updated once a minute
+	if _, err = Authenticate(store, Scheme+secret, "10.1.1.1:4000", now.Add(time.Second)); err != nil || store.touched != 1 {
+		t.Errorf("expected one update of last use, got %d, %v", store.touched, err)
+	}
+	if _, err = Authenticate(store, Scheme+secret, "10.1.1.1:4000", now.Add(time.Minute)); err != nil || store.touched != 2 {
+		t.Errorf("expected two updates of last use, got %d, %v", store.touched, err)
+	}
+
+	if _, err = Authenticate(store, Scheme+secret, "172.16.0.1:4000", now); !errors.Is(err, ErrIPNotAllowed) {
+		t.Errorf("expected ip error, got %v", err)
+	}
+	if _, err = Authenticate(store, Scheme+secret+"x", "10.1.1.1:4000", now); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected invalid key, got %v", err)
+	}
+	delete(store.keys, "indexer")
+	if _, err = Authenticate(store, Scheme+secret, "10.1.1.1:4000", now); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("revoked key is accepted: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	secret, _ := NewSecret()
+	store := &memStore{keys: map[string]*sqldb.ServiceKey{
+		"bot": {Name: "bot", KeyHash: Hash(secret), Permission: string(PermSubmit)},
+	}}
+	var denied error
+	handler := Middleware(store, func(w http.ResponseWriter, r *http.Request, err error) {
+		denied = err
+		w.WriteHeader(http.StatusUnauthorized)
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := FromRequest(r); key != nil {
+			w.Write([]byte(key.Name))
+		}
+	}))
+
+	for _, c := range []struct {
+		header, body string
+		code         int
+	}{
+		{"", "", http.StatusOK},
+		{"Bearer token", "", http.StatusOK},
+		{Scheme + secret, "bot", http.StatusOK},
+		{Scheme + "ibax_unknown", "", http.StatusUnauthorized},
+	} {
+		denied = nil
+		r := httptest.NewRequest(http.MethodGet, "/api/v2/blocks", nil)
+		r.Header.Set("Authorization", c.header)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != c.code || w.Body.String() != c.body {
+			t.Errorf("%q: got %d %q, %v", c.header, w.Code, w.Body.String(), denied)
+		}
+	}
+}
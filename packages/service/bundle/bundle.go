@@ -0,0 +1,389 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package bundle exports the applications of the ecosystem into the signed JSON bundle and
+// plans the import of the bundle into the target ecosystem.
+//
+// The bundle contains the objects of the applications and the parameters and menus of the
+// ecosystem, the data rows aren't exported except the seed rows of the requested tables. The
+// objects are sorted by name, so the same state is always exported into the same bytes and the
+// signature covers the whole bundle except the signature itself
+package bundle
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+)
+
+// Format is the version of the bundle format
+const Format = 1
+
+var (
+	ErrNotSigned    = errors.New("bundle is not signed")
+	ErrBadSignature = errors.New("bundle signature is invalid")
+	ErrFormat       = errors.New("unsupported bundle format")
+)
+
+// the parameters which are owned by the ecosystem and never exported
+var excludedParameters = map[string]bool{
+	"founder_account": true,
+}
+
+// Bundle is the exported state of the ecosystem
+type Bundle struct {
+	Format       int           `json:"format"`
+	Ecosystem    int64         `json:"ecosystem"`
+	Applications []Application `json:"applications"`
+	Parameters   []Parameter   `json:"parameters"`
+	Menus        []Menu        `json:"menus"`
+	Signature    *Signature    `json:"signature,omitempty"`
+}
+
+// Signature is the signature of the bundle by the exporting node
+type Signature struct {
+	PublicKey string `json:"public_key"`
+	Sign      string `json:"sign"`
+}
+
+// Application is the application with its objects
+type Application struct {
+	Name       string     `json:"name"`
+	Conditions string     `json:"conditions"`
+	Contracts  []Contract `json:"contracts"`
+	Tables     []Table    `json:"tables"`
+	Pages      []Page     `json:"pages"`
+	Snippets   []Snippet  `json:"snippets"`
+	AppParams  []AppParam `json:"app_params"`
+	Seeds      []Seed     `json:"seeds,omitempty"`
+}
+
+// Contract is the source of the contract, Version is the hash of the source
+type Contract struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+	Version    string `json:"version"`
+}
+
+// Column is the column of the table
+type Column struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Conditions string `json:"conditions"`
+}
+
+// Permissions are the permissions of the table
+type Permissions struct {
+	Insert    string `json:"insert"`
+	Update    string `json:"update"`
+	NewColumn string `json:"new_column"`
+	Read      string `json:"read,omitempty"`
+}
+
+// Table is the schema of the table
+type Table struct {
+	Name        string      `json:"name"`
+	Columns     []Column    `json:"columns"`
+	Permissions Permissions `json:"permissions"`
+}
+
+// Page is the page of the application
+type Page struct {
+	Name          string `json:"name"`
+	Value         string `json:"value"`
+	Menu          string `json:"menu"`
+	Conditions    string `json:"conditions"`
+	ValidateCount int64  `json:"validate_count"`
+	ValidateMode  string `json:"validate_mode"`
+}
+
+// Snippet is the snippet of the application
+type Snippet struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+}
+
+// AppParam is the parameter of the application
+type AppParam struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+}
+
+// Parameter is the parameter of the ecosystem
+type Parameter struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+}
+
+// Menu is the menu of the ecosystem
+type Menu struct {
+	Name       string `json:"name"`
+	Title      string `json:"title"`
+	Value      string `json:"value"`
+	Conditions string `json:"conditions"`
+}
+
+// Seed is the rows of the table which are inserted if the table is empty
+type Seed struct {
+	Table string              `json:"table"`
+	Rows  []map[string]string `json:"rows"`
+}
+
+// Source provides the state of the ecosystems
+type Source interface {
+	// Rows returns the rows of the ecosystem in the system table such as contracts or pages
+	Rows(table string, ecosystem int64) ([]map[string]string, error)
+	// ColumnTypes returns the types of the columns of the ecosystem table
+	ColumnTypes(ecosystem int64, table string) (map[string]string, error)
+	// Data returns the rows of the ecosystem table ordered by id
+	Data(ecosystem int64, table string) ([]map[string]string, error)
+	// HasData returns whether the ecosystem table has the rows
+	HasData(ecosystem int64, table string) (bool, error)
+}
+
+// Version returns the version of the contract source
+func Version(value string) string {
+	return hex.EncodeToString(crypto.Hash([]byte(value)))
+}
+
+// state is the objects of the ecosystem loaded from the source
+type state struct {
+	apps     []map[string]string
+	appNames map[string]string // app_id to name
+	byApp    map[string]map[string][]map[string]string
+	params   []map[string]string
+	menus    []map[string]string
+}
+
+var appTables = []string{"contracts", "tables", "pages", "snippets", "app_params"}
+
+func load(src Source, ecosystem int64) (*state, error) {
+	s := &state{appNames: make(map[string]string), byApp: make(map[string]map[string][]map[string]string)}
+	apps, err := src.Rows("applications", ecosystem)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		if app["deleted"] == "1" {
+			continue
+		}
+		s.apps = append(s.apps, app)
+		s.appNames[app["id"]] = app["name"]
+		s.byApp[app["name"]] = make(map[string][]map[string]string)
+	}
+	for _, table := range appTables {
+		rows, err := src.Rows(table, ecosystem)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if name, ok := s.appNames[row["app_id"]]; ok {
+				s.byApp[name][table] = append(s.byApp[name][table], row)
+			}
+		}
+	}
+	if s.params, err = src.Rows("parameters", ecosystem); err != nil {
+		return nil, err
+	}
+	if s.menus, err = src.Rows("menu", ecosystem); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func sortByName[T any](items []T, name func(T) string) {
+	sort.Slice(items, func(i, j int) bool { return name(items[i]) < name(items[j]) })
+}
+
+func decodeTable(src Source, ecosystem int64, row map[string]string) (Table, error) {
+	t := Table{Name: row["name"], Columns: []Column{}}
+	if err := json.Unmarshal([]byte(row["permissions"]), &t.Permissions); err != nil {
+		return t, fmt.Errorf("permissions of table %s: %w", t.Name, err)
+	}
+	conditions := make(map[string]string)
+	if err := json.Unmarshal([]byte(row["columns"]), &conditions); err != nil {
+		return t, fmt.Errorf("columns of table %s: %w", t.Name, err)
+	}
+	types, err := src.ColumnTypes(ecosystem, t.Name)
+	if err != nil {
+		return t, err
+	}
+	for name, cond := range conditions {
+		t.Columns = append(t.Columns, Column{Name: name, Type: types[name], Conditions: cond})
+	}
+	sortByName(t.Columns, func(c Column) string { return c.Name })
+	return t, nil
+}
+
+// Export returns the unsigned bundle of the ecosystem with the seed rows of the seeds tables
+func Export(src Source, ecosystem int64, seeds []string) (*Bundle, error) {
+	s, err := load(src, ecosystem)
+	if err != nil {
+		return nil, err
+	}
+	seedTables := make(map[string]bool)
+	for _, name := range seeds {
+		seedTables[name] = true
+	}
+
+	b := &Bundle{Format: Format, Ecosystem: ecosystem, Applications: []Application{},
+		Parameters: []Parameter{}, Menus: []Menu{}}
+	for _, row := range s.apps {
+		objects := s.byApp[row["name"]]
+		app := Application{Name: row["name"], Conditions: row["conditions"], Contracts: []Contract{},
+			Tables: []Table{}, Pages: []Page{}, Snippets: []Snippet{}, AppParams: []AppParam{}}
+		for _, item := range objects["contracts"] {
+			app.Contracts = append(app.Contracts, Contract{Name: item["name"], Value: item["value"],
+				Conditions: item["conditions"], Version: Version(item["value"])})
+		}
+		for _, item := range objects["tables"] {
+			t, err := decodeTable(src, ecosystem, item)
+			if err != nil {
+				return nil, err
+			}
+			app.Tables = append(app.Tables, t)
+			if !seedTables[t.Name] {
+				continue
+			}
+			delete(seedTables, t.Name)
+			rows, err := src.Data(ecosystem, t.Name)
+			if err != nil {
+				return nil, err
+			}
+			seed := Seed{Table: t.Name, Rows: make([]map[string]string, 0, len(rows))}
+			for _, r := range rows {
+				values := make(map[string]string, len(r))
+				for key, val := range r {
+					if key != "id" {
+						values[key] = val
+					}
+				}
+				seed.Rows = append(seed.Rows, values)
+			}
+			app.Seeds = append(app.Seeds, seed)
+		}
+		for _, item := range objects["pages"] {
+			app.Pages = append(app.Pages, Page{Name: item["name"], Value: item["value"], Menu: item["menu"],
+				Conditions: item["conditions"], ValidateCount: converter.StrToInt64(item["validate_count"]),
+				ValidateMode: item["validate_mode"]})
+		}
+		for _, item := range objects["snippets"] {
+			app.Snippets = append(app.Snippets, Snippet{Name: item["name"], Value: item["value"],
+				Conditions: item["conditions"]})
+		}
+		for _, item := range objects["app_params"] {
+			app.AppParams = append(app.AppParams, AppParam{Name: item["name"], Value: item["value"],
+				Conditions: item["conditions"]})
+		}
+		sortByName(app.Contracts, func(c Contract) string { return c.Name })
+		sortByName(app.Tables, func(t Table) string { return t.Name })
+		sortByName(app.Pages, func(p Page) string { return p.Name })
+		sortByName(app.Snippets, func(s Snippet) string { return s.Name })
+		sortByName(app.AppParams, func(p AppParam) string { return p.Name })
+		sortByName(app.Seeds, func(s Seed) string { return s.Table })
+		b.Applications = append(b.Applications, app)
+	}
+	for name := range seedTables {
+		return nil, fmt.Errorf("seed table %s doesn't belong to any application", name)
+	}
+
+	for _, item := range s.params {
+		if excludedParameters[item["name"]] {
+			continue
+		}
+		b.Parameters = append(b.Parameters, Parameter{Name: item["name"], Value: item["value"],
+			Conditions: item["conditions"]})
+	}
+	for _, item := range s.menus {
+		b.Menus = append(b.Menus, Menu{Name: item["name"], Title: item["title"], Value: item["value"],
+			Conditions: item["conditions"]})
+	}
+	sortByName(b.Applications, func(a Application) string { return a.Name })
+	sortByName(b.Parameters, func(p Parameter) string { return p.Name })
+	sortByName(b.Menus, func(m Menu) string { return m.Name })
+	return b, nil
+}
+
+// Canonical returns the signed bytes of the bundle
+func (b *Bundle) Canonical() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// Sign signs the bundle by the signer
+func (b *Bundle) Sign(signer keystore.Signer) error {
+	if signer == nil {
+		return keystore.ErrEmptyKey
+	}
+	data, err := b.Canonical()
+	if err != nil {
+		return err
+	}
+	sign, err := keystore.SignData(signer, data)
+	if err != nil {
+		return err
+	}
+	b.Signature = &Signature{
+		PublicKey: hex.EncodeToString(signer.PublicKey()),
+		Sign:      hex.EncodeToString(sign),
+	}
+	return nil
+}
+
+// Verify checks the format and the signature of the bundle and the versions of its contracts
+func (b *Bundle) Verify() error {
+	if b.Format != Format {
+		return fmt.Errorf("%w %d", ErrFormat, b.Format)
+	}
+	if b.Signature == nil {
+		return ErrNotSigned
+	}
+	pub, err := hex.DecodeString(b.Signature.PublicKey)
+	if err != nil {
+		return ErrBadSignature
+	}
+	sign, err := hex.DecodeString(b.Signature.Sign)
+	if err != nil {
+		return ErrBadSignature
+	}
+	data, err := b.Canonical()
+	if err != nil {
+		return err
+	}
+	if ok, err := crypto.Verify(pub, data, sign); err != nil || !ok {
+		return ErrBadSignature
+	}
+	for _, app := range b.Applications {
+		for _, c := range app.Contracts {
+			if c.Version != Version(c.Value) {
+				return fmt.Errorf("wrong version of contract %s", c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Parse decodes and verifies the bundle
+func Parse(data []byte) (*Bundle, error) {
+	b := &Bundle{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("decoding bundle: %w", err)
+	}
+	if err := b.Verify(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,300 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+// memSource keeps the ecosystems in memory and applies the plans like the contracts do
+type memSource struct {
+	lastID int64
+	rows   map[string][]map[string]string // system table to rows
+	types  map[string]map[string]string   // ecosystem table to column types
+	data   map[string][]map[string]string // ecosystem table to rows
+}
+
+func newMemSource() *memSource {
+	return &memSource{rows: make(map[string][]map[string]string),
+		types: make(map[string]map[string]string), data: make(map[string][]map[string]string)}
+}
+
+func tableKey(ecosystem int64, table string) string {
+	return fmt.Sprintf("%d_%s", ecosystem, table)
+}
+
+func (s *memSource) Rows(table string, ecosystem int64) ([]map[string]string, error) {
+	var list []map[string]string
+	for _, row := range s.rows[table] {
+		if row["ecosystem"] == converter.Int64ToStr(ecosystem) {
+			list = append(list, row)
+		}
+	}
+	return list, nil
+}
+
+func (s *memSource) ColumnTypes(ecosystem int64, table string) (map[string]string, error) {
+	return s.types[tableKey(ecosystem, table)], nil
+}
+
+func (s *memSource) Data(ecosystem int64, table string) ([]map[string]string, error) {
+	return s.data[tableKey(ecosystem, table)], nil
+}
+
+func (s *memSource) HasData(ecosystem int64, table string) (bool, error) {
+	return len(s.data[tableKey(ecosystem, table)]) > 0, nil
+}
+
+func (s *memSource) insert(table string, ecosystem int64, row map[string]string) string {
+	s.lastID++
+	row["id"] = converter.Int64ToStr(s.lastID)
+	row["ecosystem"] = converter.Int64ToStr(ecosystem)
+	s.rows[table] = append(s.rows[table], row)
+	return row["id"]
+}
+
+func (s *memSource) find(ecosystem int64, table, key, value string) map[string]string {
+	for _, row := range s.rows[table] {
+		if row["ecosystem"] == converter.Int64ToStr(ecosystem) && row[key] == value {
+			return row
+		}
+	}
+	return nil
+}
+
+func (s *memSource) createTable(ecosystem int64, appID, name, columns, permissions string) {
+	var cols []Column
+	if err := json.Unmarshal([]byte(columns), &cols); err != nil {
+		panic(err)
+	}
+	types := map[string]string{"id": "number"}
+	conditions := make(map[string]string)
+	for _, col := range cols {
+		types[col.Name] = col.Type
+		conditions[col.Name] = col.Conditions
+	}
+	out, _ := json.Marshal(conditions)
+	s.types[tableKey(ecosystem, name)] = types
+	s.insert("tables", ecosystem, map[string]string{"name": name, "columns": string(out),
+		"permissions": permissions, "app_id": appID})
+}
+
+var contractName = regexp.MustCompile(`contract\s+(\w+)`)
+
+// apply makes the changes of the plan like the ImportBundle contract does
+func (s *memSource) apply(ecosystem int64, plan *Plan) {
+	str := func(v any) string { return fmt.Sprint(v) }
+	for _, c := range plan.Pending() {
+		p := c.Params
+		if c.NewApp != "" {
+			p["ApplicationId"] = converter.StrToInt64(s.find(ecosystem, "applications", "name", c.NewApp)["id"])
+		}
+		appID, id := str(p["ApplicationId"]), str(p["Id"])
+		switch c.Contract {
+		case "NewApplication":
+			s.insert("applications", ecosystem, map[string]string{"name": str(p["Name"]),
+				"conditions": str(p["Conditions"]), "deleted": "0"})
+		case "EditApplication":
+			s.find(ecosystem, "applications", "id", appID)["conditions"] = str(p["Conditions"])
+		case "NewParameter":
+			s.insert("parameters", ecosystem, map[string]string{"name": str(p["Name"]),
+				"value": str(p["Value"]), "conditions": str(p["Conditions"])})
+		case "NewMenu":
+			s.insert("menu", ecosystem, map[string]string{"name": str(p["Name"]), "title": str(p["Title"]),
+				"value": str(p["Value"]), "conditions": str(p["Conditions"])})
+		case "NewContract":
+			name := contractName.FindStringSubmatch(str(p["Value"]))[1]
+			s.insert("contracts", ecosystem, map[string]string{"name": name, "value": str(p["Value"]),
+				"conditions": str(p["Conditions"]), "app_id": appID})
+		case "NewSnippet", "NewAppParam":
+			table := map[string]string{"NewSnippet": "snippets", "NewAppParam": "app_params"}[c.Contract]
+			s.insert(table, ecosystem, map[string]string{"name": str(p["Name"]), "value": str(p["Value"]),
+				"conditions": str(p["Conditions"]), "app_id": appID})
+		case "NewPage":
+			s.insert("pages", ecosystem, map[string]string{"name": str(p["Name"]), "value": str(p["Value"]),
+				"menu": str(p["Menu"]), "conditions": str(p["Conditions"]), "app_id": appID,
+				"validate_count": str(p["ValidateCount"]), "validate_mode": str(p["ValidateMode"])})
+		case "NewTable":
+			s.createTable(ecosystem, appID, str(p["Name"]), str(p["Columns"]), str(p["Permissions"]))
+		case "EditParameter", "EditMenu", "EditContract", "EditSnippet", "EditPage", "EditAppParam":
+			for _, table := range []string{"parameters", "menu", "contracts", "snippets", "pages", "app_params"} {
+				if row := s.find(ecosystem, table, "id", id); row != nil {
+					for key, val := range p {
+						if key != "Id" {
+							row[map[string]string{"Value": "value", "Conditions": "conditions", "Title": "title",
+								"Menu": "menu", "ValidateCount": "validate_count", "ValidateMode": "validate_mode"}[key]] = str(val)
+						}
+					}
+				}
+			}
+		case "EditTable":
+			perm, _ := json.Marshal(Permissions{Insert: str(p["InsertPerm"]), Update: str(p["UpdatePerm"]),
+				NewColumn: str(p["NewColumnPerm"]), Read: str(p["ReadPerm"])})
+			s.find(ecosystem, "tables", "name", str(p["Name"]))["permissions"] = string(perm)
+		case "EditColumn":
+			row := s.find(ecosystem, "tables", "name", str(p["TableName"]))
+			conditions := make(map[string]string)
+			json.Unmarshal([]byte(row["columns"]), &conditions)
+			conditions[str(p["Name"])] = str(p["Permissions"])
+			out, _ := json.Marshal(conditions)
+			row["columns"] = string(out)
+		case "":
+			key := tableKey(ecosystem, c.Name)
+			for _, item := range p["Rows"].([]any) {
+				row := map[string]string{"id": converter.IntToStr(len(s.data[key]) + 1)}
+				for k, v := range item.(map[string]any) {
+					row[k] = str(v)
+				}
+				s.data[key] = append(s.data[key], row)
+			}
+		default:
+			panic("unknown contract " + c.Contract)
+		}
+	}
+}
+
+// fillApp creates the application with the objects of all kinds in the ecosystem
+func fillApp(s *memSource, ecosystem int64) {
+	appID := s.insert("applications", ecosystem, map[string]string{"name": "Shop", "conditions": "true", "deleted": "0"})
+	s.insert("applications", ecosystem, map[string]string{"name": "Removed", "conditions": "true", "deleted": "1"})
+	// the contract calls the contract which goes after it by name
+	s.insert("contracts", ecosystem, map[string]string{"name": "AddItem", "app_id": appID, "conditions": "true",
+		"value": `contract AddItem { action { CheckItem("Name", $Name) DBInsert("items", {name: $Name}) } }`})
+	s.insert("contracts", ecosystem, map[string]string{"name": "CheckItem", "app_id": appID, "conditions": "true",
+		"value": `contract CheckItem { data { Name string } }`})
+	s.createTable(ecosystem, appID, "items", `[{"name":"name","type":"varchar","conditions":"true"},`+
+		`{"name":"price","type":"money","conditions":"{\"update\":\"false\",\"read\":\"true\"}"}]`,
+		`{"insert":"true","update":"true","new_column":"false"}`)
+	s.data[tableKey(ecosystem, "items")] = []map[string]string{
+		{"id": "1", "name": "apple", "price": "10"},
+		{"id": "2", "name": "pear", "price": "12"},
+	}
+	s.insert("pages", ecosystem, map[string]string{"name": "shop_items", "app_id": appID, "menu": "shop_menu",
+		"value": `Div(){Items}`, "conditions": "true", "validate_count": "1", "validate_mode": "0"})
+	s.insert("snippets", ecosystem, map[string]string{"name": "item_row", "app_id": appID,
+		"value": `Span(#name#)`, "conditions": "true"})
+	s.insert("app_params", ecosystem, map[string]string{"name": "currency", "app_id": appID,
+		"value": "IBXC", "conditions": "true"})
+	s.insert("menu", ecosystem, map[string]string{"name": "shop_menu", "title": "Shop",
+		"value": `MenuItem(Items, shop_items)`, "conditions": "true"})
+	s.insert("parameters", ecosystem, map[string]string{"name": "founder_account", "value": "123", "conditions": "true"})
+	s.insert("parameters", ecosystem, map[string]string{"name": "shop_owner", "value": "456", "conditions": "true"})
+}
+
+func testSigner(t *testing.T) keystore.Signer {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, _, err := crypto.GenKeyPair()
+	require.NoError(t, err)
+	signer, err := keystore.NewKeySigner(priv)
+	require.NoError(t, err)
+	return signer
+}
+
+func actions(plan *Plan) map[Action]int {
+	list := make(map[Action]int)
+	for _, c := range plan.Changes {
+		list[c.Action]++
+	}
+	return list
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	signer := testSigner(t)
+
+	src := newMemSource()
+	fillApp(src, 1)
+	b, err := Export(src, 1, []string{"items"})
+	require.NoError(t, err)
+	require.NoError(t, b.Sign(signer))
+	data, err := json.Marshal(b)
+	require.NoError(t, err)
+
+	b, err = Parse(data)
+	require.NoError(t, err)
+	require.Len(t, b.Applications, 1)
+	require.Equal(t, []string{"shop_owner"}, []string{b.Parameters[0].Name})
+
+	plan, err := MakePlan(src, 2, b, false)
+	require.NoError(t, err)
+	require.Empty(t, plan.Conflicts())
+	require.Equal(t, map[Action]int{Create: 10}, actions(plan))
+	var contracts []string
+	for _, c := range plan.Changes {
+		if c.Kind == "contracts" {
+			contracts = append(contracts, c.Name)
+		}
+	}
+	require.Equal(t, []string{"CheckItem", "AddItem"}, contracts)
+	src.apply(2, plan)
+
+	// the repeated import changes nothing
+	plan, err = MakePlan(src, 2, b, false)
+	require.NoError(t, err)
+	require.Empty(t, plan.Pending())
+	require.Equal(t, map[Action]int{Unchanged: 12}, actions(plan))
+
+	// the imported ecosystem is exported into the same bundle
+	exported, err := Export(src, 2, []string{"items"})
+	require.NoError(t, err)
+	exported.Ecosystem = b.Ecosystem
+	canonical, err := b.Canonical()
+	require.NoError(t, err)
+	reexported, err := exported.Canonical()
+	require.NoError(t, err)
+	require.Equal(t, string(canonical), string(reexported))
+}
+
+func TestBundleConflicts(t *testing.T) {
+	signer := testSigner(t)
+
+	src := newMemSource()
+	fillApp(src, 1)
+	b, err := Export(src, 1, nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Sign(signer))
+
+	plan, err := MakePlan(src, 2, b, false)
+	require.NoError(t, err)
+	src.apply(2, plan)
+	src.find(2, "app_params", "name", "currency")["value"] = "USD"
+	src.find(2, "contracts", "name", "CheckItem")["value"] = `contract CheckItem { data { Name string "optional" } }`
+
+	plan, err = MakePlan(src, 2, b, false)
+	require.NoError(t, err)
+	require.Len(t, plan.Conflicts(), 2)
+	require.Error(t, plan.ConflictError())
+	require.Empty(t, plan.Pending())
+
+	plan, err = MakePlan(src, 2, b, true)
+	require.NoError(t, err)
+	require.Empty(t, plan.Conflicts())
+	require.Len(t, plan.Pending(), 2)
+	src.apply(2, plan)
+	plan, err = MakePlan(src, 2, b, false)
+	require.NoError(t, err)
+	require.Empty(t, plan.Pending())
+	require.Empty(t, plan.Conflicts())
+
+	// the table schema isn't overwritten
+	src.types[tableKey(2, "items")]["price"] = "number"
+	plan, err = MakePlan(src, 2, b, true)
+	require.NoError(t, err)
+	require.Len(t, plan.Conflicts(), 1)
+	require.Equal(t, []string{"columns.price"}, plan.Conflicts()[0].Fields)
+
+	b.Menus[0].Title = "Store"
+	require.Equal(t, ErrBadSignature, b.Verify())
+	b.Signature = nil
+	require.Equal(t, ErrNotSigned, b.Verify())
+}
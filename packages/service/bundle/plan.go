@@ -0,0 +1,472 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// Action is what the import does with the object
+type Action string
+
+const (
+	Create    Action = "create"
+	Update    Action = "update"
+	Unchanged Action = "unchanged"
+	Conflict  Action = "conflict"
+)
+
+// Change is the planned change of the object in the target ecosystem. Contract is called with
+// Params to apply the change. If the object belongs to the application which is created by
+// the same import, NewApp is the name of the application and its id is set to ApplicationId
+// when the application has been created
+type Change struct {
+	Kind     string         `json:"kind"`
+	Name     string         `json:"name"`
+	Action   Action         `json:"action"`
+	Fields   []string       `json:"fields,omitempty"` // the fields which differ from the target
+	Contract string         `json:"contract,omitempty"`
+	Params   map[string]any `json:"params,omitempty"`
+	NewApp   string         `json:"new_app,omitempty"`
+}
+
+// Plan is the ordered list of the changes
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Conflicts returns the changes which can't be applied
+func (p *Plan) Conflicts() []Change {
+	var list []Change
+	for _, c := range p.Changes {
+		if c.Action == Conflict {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Pending returns the changes which modify the target ecosystem
+func (p *Plan) Pending() []Change {
+	var list []Change
+	for _, c := range p.Changes {
+		if c.Action == Create || c.Action == Update {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// Calls returns the pending changes as the values of the contract VM
+func (p *Plan) Calls() []any {
+	calls := make([]any, 0)
+	for _, c := range p.Pending() {
+		params := make(map[string]any, len(c.Params))
+		for key, val := range c.Params {
+			params[key] = val
+		}
+		call := map[string]any{"Kind": c.Kind, "Name": c.Name, "Contract": c.Contract, "Params": params}
+		if c.NewApp != "" {
+			call["NewApp"] = c.NewApp
+		}
+		calls = append(calls, types.ConvertMap(call))
+	}
+	return calls
+}
+
+// ConflictError returns the error which lists the conflicts of the plan or nil
+func (p *Plan) ConflictError() error {
+	conflicts := p.Conflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	list := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		list = append(list, fmt.Sprintf("%s %s (%s)", c.Kind, c.Name, strings.Join(c.Fields, ", ")))
+	}
+	return fmt.Errorf("bundle conflicts with ecosystem: %s", strings.Join(list, "; "))
+}
+
+type planner struct {
+	src       Source
+	ecosystem int64
+	overwrite bool
+	apps      map[string]map[string]string
+	rows      map[string]map[string]map[string]string
+	plan      *Plan
+}
+
+func (p *planner) loadRows(table string, key func(map[string]string) string) error {
+	rows, err := p.src.Rows(table, p.ecosystem)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]map[string]string, len(rows))
+	for _, row := range rows {
+		index[key(row)] = row
+	}
+	p.rows[table] = index
+	return nil
+}
+
+func byName(row map[string]string) string {
+	return row["name"]
+}
+
+// appID returns id of the existing application or the name of the created application
+func (p *planner) appID(app string) (id int64, newApp string) {
+	if row, ok := p.apps[app]; ok {
+		return converter.StrToInt64(row["id"]), ""
+	}
+	return 0, app
+}
+
+func (p *planner) appParams(app string, params map[string]any) (map[string]any, string) {
+	id, newApp := p.appID(app)
+	if newApp == "" {
+		params["ApplicationId"] = id
+	}
+	return params, newApp
+}
+
+// diff returns the names of the fields whose values differ, the values go in name, new, old order
+func diff(values ...string) []string {
+	var fields []string
+	for i := 0; i+2 < len(values); i += 3 {
+		if values[i+1] != values[i+2] {
+			fields = append(fields, values[i])
+		}
+	}
+	return fields
+}
+
+type objectChange struct {
+	kind, name string
+	row        map[string]string
+	fields     []string
+	create     string
+	edit       string // empty if the object can't be updated
+	params     map[string]any
+	newApp     string
+}
+
+// add plans the object which is created if row is nil or updated by edit contract with the id
+// of row if the overwrite is allowed
+func (p *planner) add(o objectChange) {
+	c := Change{Kind: o.kind, Name: o.name}
+	switch {
+	case o.row == nil:
+		c.Action, c.Contract, c.Params, c.NewApp = Create, o.create, o.params, o.newApp
+	case len(o.fields) == 0:
+		c.Action = Unchanged
+	case p.overwrite && o.edit != "":
+		c.Action, c.Contract, c.Params, c.Fields = Update, o.edit, o.params, o.fields
+	default:
+		c.Action, c.Fields = Conflict, o.fields
+	}
+	p.plan.Changes = append(p.plan.Changes, c)
+}
+
+// MakePlan compares the bundle with the target ecosystem. The conflicts become the updates
+// if overwrite is true, except the changes of the table schemas
+func MakePlan(src Source, ecosystem int64, b *Bundle, overwrite bool) (*Plan, error) {
+	p := &planner{src: src, ecosystem: ecosystem, overwrite: overwrite, plan: &Plan{Changes: []Change{}},
+		rows: make(map[string]map[string]map[string]string)}
+	for _, table := range []string{"applications", "contracts", "tables", "pages", "snippets", "menu", "parameters"} {
+		if err := p.loadRows(table, byName); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.loadRows("app_params", func(row map[string]string) string {
+		return row["app_id"] + "/" + row["name"]
+	}); err != nil {
+		return nil, err
+	}
+	p.apps = p.rows["applications"]
+
+	for _, app := range b.Applications {
+		row := p.apps[app.Name]
+		o := objectChange{kind: "applications", name: app.Name, row: row, create: "NewApplication",
+			edit: "EditApplication", params: map[string]any{"Name": app.Name, "Conditions": app.Conditions}}
+		if row != nil {
+			o.fields = diff("conditions", app.Conditions, row["conditions"])
+			o.params = map[string]any{"ApplicationId": converter.StrToInt64(row["id"]), "Conditions": app.Conditions}
+			if row["deleted"] == "1" {
+				o.fields, o.edit = append(o.fields, "deleted"), ""
+			}
+		}
+		p.add(o)
+	}
+	for _, item := range b.Parameters {
+		row := p.rows["parameters"][item.Name]
+		o := objectChange{kind: "parameters", name: item.Name, row: row, create: "NewParameter",
+			edit: "EditParameter", params: map[string]any{"Name": item.Name, "Value": item.Value,
+				"Conditions": item.Conditions}}
+		if row != nil {
+			o.fields = diff("value", item.Value, row["value"], "conditions", item.Conditions, row["conditions"])
+			o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+				"Conditions": item.Conditions}
+		}
+		p.add(o)
+	}
+	for _, item := range b.Menus {
+		row := p.rows["menu"][item.Name]
+		o := objectChange{kind: "menu", name: item.Name, row: row, create: "NewMenu", edit: "EditMenu",
+			params: map[string]any{"Name": item.Name, "Value": item.Value, "Title": item.Title,
+				"Conditions": item.Conditions}}
+		if row != nil {
+			o.fields = diff("value", item.Value, row["value"], "title", item.Title, row["title"],
+				"conditions", item.Conditions, row["conditions"])
+			o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+				"Title": item.Title, "Conditions": item.Conditions}
+		}
+		p.add(o)
+	}
+
+	var contracts []appContract
+	for _, app := range b.Applications {
+		for _, t := range app.Tables {
+			if err := p.addTable(app.Name, t); err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range app.Contracts {
+			contracts = append(contracts, appContract{app: app.Name, Contract: c})
+		}
+	}
+	// the contracts are created after the contracts which they call
+	for _, c := range orderContracts(contracts) {
+		p.addContract(c.app, c.Contract)
+	}
+	for _, app := range b.Applications {
+		for _, item := range app.Snippets {
+			row := p.rows["snippets"][item.Name]
+			params, newApp := p.appParams(app.Name, map[string]any{"Name": item.Name, "Value": item.Value,
+				"Conditions": item.Conditions})
+			o := objectChange{kind: "snippets", name: item.Name, row: row, create: "NewSnippet",
+				edit: "EditSnippet", params: params, newApp: newApp}
+			if row != nil {
+				o.fields = diff("value", item.Value, row["value"], "conditions", item.Conditions, row["conditions"])
+				o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+					"Conditions": item.Conditions}
+			}
+			p.add(o)
+		}
+		for _, item := range app.Pages {
+			row := p.rows["pages"][item.Name]
+			params, newApp := p.appParams(app.Name, map[string]any{"Name": item.Name, "Value": item.Value,
+				"Menu": item.Menu, "Conditions": item.Conditions, "ValidateCount": item.ValidateCount,
+				"ValidateMode": item.ValidateMode})
+			o := objectChange{kind: "pages", name: item.Name, row: row, create: "NewPage", edit: "EditPage",
+				params: params, newApp: newApp}
+			if row != nil {
+				o.fields = diff("value", item.Value, row["value"], "menu", item.Menu, row["menu"],
+					"conditions", item.Conditions, row["conditions"],
+					"validate_count", converter.Int64ToStr(item.ValidateCount), row["validate_count"],
+					"validate_mode", item.ValidateMode, row["validate_mode"])
+				o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+					"Menu": item.Menu, "Conditions": item.Conditions, "ValidateCount": item.ValidateCount,
+					"ValidateMode": item.ValidateMode}
+			}
+			p.add(o)
+		}
+		for _, item := range app.AppParams {
+			id, _ := p.appID(app.Name)
+			row := p.rows["app_params"][converter.Int64ToStr(id)+"/"+item.Name]
+			params, newApp := p.appParams(app.Name, map[string]any{"Name": item.Name, "Value": item.Value,
+				"Conditions": item.Conditions})
+			o := objectChange{kind: "app_params", name: app.Name + "." + item.Name, row: row,
+				create: "NewAppParam", edit: "EditAppParam", params: params, newApp: newApp}
+			if row != nil {
+				o.fields = diff("value", item.Value, row["value"], "conditions", item.Conditions, row["conditions"])
+				o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+					"Conditions": item.Conditions}
+			}
+			p.add(o)
+		}
+	}
+	for _, app := range b.Applications {
+		for _, seed := range app.Seeds {
+			if err := p.addSeed(seed); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.plan, nil
+}
+
+func (p *planner) addContract(app string, item Contract) {
+	row := p.rows["contracts"][item.Name]
+	params, newApp := p.appParams(app, map[string]any{"Value": item.Value, "Conditions": item.Conditions})
+	o := objectChange{kind: "contracts", name: item.Name, row: row, create: "NewContract",
+		edit: "EditContract", params: params, newApp: newApp}
+	if row != nil {
+		o.fields = diff("version", item.Version, Version(row["value"]), "conditions", item.Conditions, row["conditions"])
+		o.params = map[string]any{"Id": converter.StrToInt64(row["id"]), "Value": item.Value,
+			"Conditions": item.Conditions}
+		// the contract with false conditions can't be changed
+		if row["conditions"] == "false" {
+			o.edit = ""
+		}
+	}
+	p.add(o)
+}
+
+func (p *planner) addTable(app string, t Table) error {
+	row := p.rows["tables"][t.Name]
+	if row == nil {
+		columns, err := json.Marshal(t.Columns)
+		if err != nil {
+			return err
+		}
+		permissions, err := json.Marshal(t.Permissions)
+		if err != nil {
+			return err
+		}
+		params, newApp := p.appParams(app, map[string]any{"Name": t.Name, "Columns": string(columns),
+			"Permissions": string(permissions)})
+		p.add(objectChange{kind: "tables", name: t.Name, create: "NewTable", params: params, newApp: newApp})
+		return nil
+	}
+
+	cur, err := decodeTable(p.src, p.ecosystem, row)
+	if err != nil {
+		return err
+	}
+	// the schema isn't changed by the import, the different columns are the conflict
+	var schema []string
+	curColumns := make(map[string]Column, len(cur.Columns))
+	for _, col := range cur.Columns {
+		curColumns[col.Name] = col
+	}
+	for _, col := range t.Columns {
+		if c, ok := curColumns[col.Name]; !ok || c.Type != col.Type {
+			schema = append(schema, "columns."+col.Name)
+		}
+	}
+	if len(cur.Columns) != len(t.Columns) {
+		bundled := make(map[string]bool, len(t.Columns))
+		for _, col := range t.Columns {
+			bundled[col.Name] = true
+		}
+		for _, col := range cur.Columns {
+			if !bundled[col.Name] {
+				schema = append(schema, "columns."+col.Name)
+			}
+		}
+	}
+	if len(schema) > 0 {
+		sort.Strings(schema)
+		p.add(objectChange{kind: "tables", name: t.Name, row: row, fields: schema})
+		return nil
+	}
+
+	p.add(objectChange{kind: "tables", name: t.Name, row: row, edit: "EditTable",
+		fields: diff("insert", t.Permissions.Insert, cur.Permissions.Insert,
+			"update", t.Permissions.Update, cur.Permissions.Update,
+			"new_column", t.Permissions.NewColumn, cur.Permissions.NewColumn,
+			"read", t.Permissions.Read, cur.Permissions.Read),
+		params: map[string]any{"Name": t.Name, "InsertPerm": t.Permissions.Insert,
+			"UpdatePerm": t.Permissions.Update, "NewColumnPerm": t.Permissions.NewColumn,
+			"ReadPerm": t.Permissions.Read}})
+	for _, col := range t.Columns {
+		p.add(objectChange{kind: "columns", name: t.Name + "." + col.Name, row: row, edit: "EditColumn",
+			fields: diff("conditions", col.Conditions, curColumns[col.Name].Conditions),
+			params: map[string]any{"TableName": t.Name, "Name": col.Name, "Permissions": col.Conditions}})
+	}
+	return nil
+}
+
+// addSeed plans the insert of the seed rows if the table is created or empty
+func (p *planner) addSeed(seed Seed) error {
+	action := Create
+	if p.rows["tables"][seed.Table] != nil {
+		exists, err := p.src.HasData(p.ecosystem, seed.Table)
+		if err != nil {
+			return err
+		}
+		if exists {
+			action = Unchanged
+		}
+	}
+	c := Change{Kind: "seeds", Name: seed.Table, Action: action}
+	if action == Create {
+		rows := make([]any, 0, len(seed.Rows))
+		for _, row := range seed.Rows {
+			values := make(map[string]any, len(row))
+			for key, val := range row {
+				values[key] = val
+			}
+			rows = append(rows, values)
+		}
+		c.Params = map[string]any{"Rows": rows}
+	}
+	p.plan.Changes = append(p.plan.Changes, c)
+	return nil
+}
+
+type appContract struct {
+	app string
+	Contract
+}
+
+var identRegexp = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// orderContracts sorts the contracts so that the called contracts of the bundle go first, the
+// contracts of the cycles keep the order of the bundle
+func orderContracts(contracts []appContract) []appContract {
+	names := make(map[string]bool, len(contracts))
+	for _, c := range contracts {
+		names[c.Name] = true
+	}
+	deps := make(map[string][]string, len(contracts))
+	for _, c := range contracts {
+		seen := make(map[string]bool)
+		for _, ident := range identRegexp.FindAllString(c.Value, -1) {
+			if ident != c.Name && names[ident] && !seen[ident] {
+				seen[ident] = true
+				deps[c.Name] = append(deps[c.Name], ident)
+			}
+		}
+	}
+
+	ordered := make([]appContract, 0, len(contracts))
+	done := make(map[string]bool, len(contracts))
+	for len(ordered) < len(contracts) {
+		progress := false
+		for _, c := range contracts {
+			if done[c.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range deps[c.Name] {
+				ready = ready && done[dep]
+			}
+			if ready {
+				done[c.Name], progress = true, true
+				ordered = append(ordered, c)
+			}
+		}
+		if !progress {
+			// the cycle, the first pending contract goes next
+			for _, c := range contracts {
+				if !done[c.Name] {
+					done[c.Name] = true
+					ordered = append(ordered, c)
+					break
+				}
+			}
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,51 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	qb "github.com/IBAX-io/go-ibax/packages/storage/sqldb/queryBuilder"
+)
+
+// dbSource reads the ecosystems within the transaction, nil transaction reads the committed state
+type dbSource struct {
+	dbTx *sqldb.DbTransaction
+}
+
+// NewDBSource returns the source of the database
+func NewDBSource(dbTx *sqldb.DbTransaction) Source {
+	return &dbSource{dbTx: dbTx}
+}
+
+func (s *dbSource) Rows(table string, ecosystem int64) ([]map[string]string, error) {
+	return s.dbTx.GetAllTransaction(fmt.Sprintf(`SELECT * FROM "1_%s" WHERE ecosystem = ? ORDER BY id`, table),
+		-1, ecosystem)
+}
+
+func (s *dbSource) ColumnTypes(ecosystem int64, table string) (map[string]string, error) {
+	columns, err := s.dbTx.GetAllColumnTypes(qb.GetTableName(ecosystem, table))
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		types[col["column_name"]] = sqldb.DataTypeToColumnType(col["data_type"])
+	}
+	return types, nil
+}
+
+func (s *dbSource) Data(ecosystem int64, table string) ([]map[string]string, error) {
+	return s.dbTx.GetAllTransaction(fmt.Sprintf(`SELECT * FROM "%s" ORDER BY id`,
+		qb.GetTableName(ecosystem, table)), -1)
+}
+
+func (s *dbSource) HasData(ecosystem int64, table string) (bool, error) {
+	rows, err := s.dbTx.GetAllTransaction(fmt.Sprintf(`SELECT id FROM "%s"`,
+		qb.GetTableName(ecosystem, table)), 1)
+	return len(rows) > 0, err
+}
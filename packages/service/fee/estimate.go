@@ -0,0 +1,106 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package fee estimates the gas price which is enough to include the transaction into the
+// blocks with the requested priority. The gas price of the transaction is its expedite in
+// the minimal units, the transactions with the higher expedite are included first
+package fee
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Priority is the requested speed of the inclusion into the block
+type Priority string
+
+const (
+	PriorityFast     Priority = "fast"
+	PriorityStandard Priority = "standard"
+	PrioritySlow     Priority = "slow"
+)
+
+// ErrPriority is returned for the unknown priority
+var ErrPriority = errors.New("unknown priority")
+
+var percentiles = map[Priority]float64{
+	PriorityFast:     90,
+	PriorityStandard: 50,
+	PrioritySlow:     25,
+}
+
+// FeeEstimate is the estimated gas price for the priority
+type FeeEstimate struct {
+	GasPrice                 int64   `json:"gas_price"`
+	EstimatedInclusionBlocks int     `json:"estimated_inclusion_blocks"`
+	Confidence               float64 `json:"confidence"`
+}
+
+// Percentile returns the target percentile of the gas prices for the priority
+func (p Priority) Percentile() (float64, error) {
+	percentile, ok := percentiles[p]
+	if !ok {
+		return 0, ErrPriority
+	}
+	return percentile, nil
+}
+
+// Estimate returns the percentile of the gas prices of the window for the priority. Every item
+// of blocks contains the gas prices of the transactions of one block. Confidence is the share
+// of the blocks with the transactions where the cheapest included transaction didn't pay more
+// than the estimated price. The empty window gives the zero price and confidence
+func Estimate(blocks [][]int64, priority Priority) (FeeEstimate, error) {
+	percentile, err := priority.Percentile()
+	if err != nil {
+		return FeeEstimate{}, err
+	}
+	var (
+		prices []int64
+		mins   []int64
+	)
+	for _, block := range blocks {
+		if len(block) == 0 {
+			continue
+		}
+		min := block[0]
+		for _, price := range block {
+			prices = append(prices, price)
+			if price < min {
+				min = price
+			}
+		}
+		mins = append(mins, min)
+	}
+	estimate := FeeEstimate{EstimatedInclusionBlocks: 1}
+	if len(prices) == 0 {
+		return estimate, nil
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+	// nearest-rank percentile
+	rank := int(math.Ceil(percentile / 100 * float64(len(prices))))
+	if rank < 1 {
+		rank = 1
+	}
+	estimate.GasPrice = prices[rank-1]
+
+	var covered int
+	for _, min := range mins {
+		if min <= estimate.GasPrice {
+			covered++
+		}
+	}
+	estimate.Confidence = float64(covered) / float64(len(mins))
+	return estimate, nil
+}
+
+// InclusionBlocks returns the number of blocks which are generated before the transaction
+// is included if there are pending transactions with the same or the higher price
+func InclusionBlocks(pending int64, maxTxCount int) int {
+	if maxTxCount <= 0 || pending <= 0 {
+		return 1
+	}
+	return int(pending/int64(maxTxCount)) + 1
+}
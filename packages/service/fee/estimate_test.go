@@ -0,0 +1,59 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package fee
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	blocks := [][]int64{
+		{10, 20, 30, 40},
+		{},
+		{50, 60, 70},
+		{80, 90, 100},
+	}
+	cases := []struct {
+		priority   Priority
+		price      int64
+		confidence float64
+	}{
+		{PriorityFast, 90, 1},
+		{PriorityStandard, 50, 2.0 / 3},
+		{PrioritySlow, 30, 1.0 / 3},
+	}
+	for _, c := range cases {
+		estimate, err := Estimate(blocks, c.priority)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate.GasPrice != c.price || estimate.Confidence != c.confidence {
+			t.Errorf("%s: got %+v", c.priority, estimate)
+		}
+	}
+
+	if _, err := Estimate(blocks, "instant"); !errors.Is(err, ErrPriority) {
+		t.Errorf("unknown priority: got %v", err)
+	}
+	estimate, err := Estimate(nil, PriorityFast)
+	if err != nil || estimate.GasPrice != 0 || estimate.Confidence != 0 {
+		t.Errorf("empty window: got %+v %v", estimate, err)
+	}
+}
+
+func TestInclusionBlocks(t *testing.T) {
+	for _, c := range []struct {
+		pending, blocks int64
+		maxTxCount      int
+	}{
+		{0, 1, 1000}, {999, 1, 1000}, {1000, 2, 1000}, {2500, 3, 1000}, {10, 1, 0},
+	} {
+		if got := InclusionBlocks(c.pending, c.maxTxCount); int64(got) != c.blocks {
+			t.Errorf("%d pending: got %d", c.pending, got)
+		}
+	}
+}
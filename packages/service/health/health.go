@@ -0,0 +1,241 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package health checks the dependencies of the node for the readiness probes of the load
+// balancers. The node is ready if all checks pass, degraded if it can serve the requests with
+// the warnings, and unhealthy if it must not receive the requests
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+)
+
+// Verdict is the result of the check
+type Verdict string
+
+const (
+	Ready     Verdict = "ready"
+	Degraded  Verdict = "degraded"
+	Unhealthy Verdict = "unhealthy"
+)
+
+// DefaultBlockAgeGaps is max age of the last block in the expected block gaps if it isn't configured
+const DefaultBlockAgeGaps = 10
+
+var errNoDatabase = errors.New("database is unavailable")
+
+var severity = map[Verdict]int{Ready: 0, Degraded: 1, Unhealthy: 2}
+
+func worst(verdicts ...Verdict) Verdict {
+	v := Ready
+	for _, item := range verdicts {
+		if severity[item] > severity[v] {
+			v = item
+		}
+	}
+	return v
+}
+
+// StatusCode returns HTTP status of the verdict, the degraded node keeps receiving the requests
+func (v Verdict) StatusCode() int {
+	if v == Unhealthy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Thresholds are the limits of the checks
+type Thresholds struct {
+	MaxBlockAge       time.Duration // zero is DefaultBlockAgeGaps of the expected block gap
+	MinPeers          int
+	MaxQueueSize      int64 // zero disables the check
+	MaxReplicationLag time.Duration
+	MinDiskFree       uint64 // bytes
+}
+
+// FromConfig returns the thresholds of the node configuration
+func FromConfig(c conf.HealthConfig) Thresholds {
+	return Thresholds{
+		MaxBlockAge:       time.Duration(c.MaxBlockAge) * time.Second,
+		MinPeers:          c.MinPeers,
+		MaxQueueSize:      c.MaxQueueSize,
+		MaxReplicationLag: time.Duration(c.MaxReplicationLag) * time.Second,
+		MinDiskFree:       uint64(c.MinDiskFree) << 20,
+	}
+}
+
+// Source provides the state of the node dependencies
+type Source interface {
+	PingDB(ctx context.Context) error
+	ReplicationLag(ctx context.Context) (replica bool, lag time.Duration, err error)
+	LastBlock() (height int64, timestamp time.Time, err error)
+	// BlockGap returns the expected time between the blocks before blockID
+	BlockGap(blockID int64) time.Duration
+	PendingTxs(ctx context.Context) (int64, error)
+	Peers() int
+	PauseType() node.PauseType
+	DiskFree() (path string, free uint64, err error)
+}
+
+type DatabaseCheck struct {
+	Status         Verdict `json:"status"`
+	Error          string  `json:"error,omitempty"`
+	Replica        bool    `json:"replica"`
+	ReplicationLag float64 `json:"replication_lag"` // seconds
+}
+
+type BlockCheck struct {
+	Status      Verdict `json:"status"`
+	Error       string  `json:"error,omitempty"`
+	Height      int64   `json:"height"`
+	Age         float64 `json:"age"`          // seconds
+	ExpectedGap float64 `json:"expected_gap"` // seconds
+	MaxAge      float64 `json:"max_age"`      // seconds
+}
+
+type QueueCheck struct {
+	Status  Verdict `json:"status"`
+	Error   string  `json:"error,omitempty"`
+	Pending int64   `json:"pending"`
+}
+
+type PeersCheck struct {
+	Status Verdict `json:"status"`
+	Count  int     `json:"count"`
+	Min    int     `json:"min"`
+}
+
+type PauseCheck struct {
+	Status Verdict `json:"status"`
+	Paused bool    `json:"paused"`
+	Reason string  `json:"reason"`
+}
+
+type DiskCheck struct {
+	Status Verdict `json:"status"`
+	Error  string  `json:"error,omitempty"`
+	Path   string  `json:"path"`
+	Free   uint64  `json:"free"` // bytes
+}
+
+// Report is the result of all checks, Status is the worst verdict of them
+type Report struct {
+	Status   Verdict       `json:"status"`
+	Database DatabaseCheck `json:"database"`
+	Block    BlockCheck    `json:"block"`
+	Queue    QueueCheck    `json:"queue"`
+	Peers    PeersCheck    `json:"peers"`
+	Pause    PauseCheck    `json:"pause"`
+	Disk     DiskCheck     `json:"disk"`
+}
+
+// Check runs the checks of the node dependencies at now
+func Check(ctx context.Context, src Source, th Thresholds, now time.Time) *Report {
+	r := &Report{}
+	r.Database = checkDatabase(ctx, src, th)
+	dbReady := r.Database.Status != Unhealthy
+	r.Queue = checkQueue(ctx, src, th, dbReady)
+	r.Block = checkBlock(src, th, now, dbReady, r.Queue.Pending)
+	r.Peers = PeersCheck{Status: Ready, Count: src.Peers(), Min: th.MinPeers}
+	if r.Peers.Count < th.MinPeers {
+		r.Peers.Status = Degraded
+	}
+	pt := src.PauseType()
+	r.Pause = PauseCheck{Status: Ready, Paused: pt != node.NoPause, Reason: pt.String()}
+	if r.Pause.Paused {
+		// the api rejects the requests while the node is paused
+		r.Pause.Status = Unhealthy
+	}
+	r.Disk = checkDisk(src, th)
+	r.Status = worst(r.Database.Status, r.Block.Status, r.Queue.Status, r.Peers.Status, r.Pause.Status, r.Disk.Status)
+	return r
+}
+
+func checkDatabase(ctx context.Context, src Source, th Thresholds) (c DatabaseCheck) {
+	c.Status = Ready
+	if err := src.PingDB(ctx); err != nil {
+		c.Status, c.Error = Unhealthy, err.Error()
+		return
+	}
+	replica, lag, err := src.ReplicationLag(ctx)
+	if err != nil {
+		c.Status, c.Error = Degraded, err.Error()
+		return
+	}
+	c.Replica, c.ReplicationLag = replica, lag.Seconds()
+	if replica && lag > th.MaxReplicationLag {
+		c.Status = Degraded
+	}
+	return
+}
+
+func checkQueue(ctx context.Context, src Source, th Thresholds, dbReady bool) (c QueueCheck) {
+	if !dbReady {
+		return QueueCheck{Status: Unhealthy, Error: errNoDatabase.Error()}
+	}
+	c.Status = Ready
+	pending, err := src.PendingTxs(ctx)
+	if err != nil {
+		c.Status, c.Error = Degraded, err.Error()
+		return
+	}
+	c.Pending = pending
+	if th.MaxQueueSize > 0 && pending > th.MaxQueueSize {
+		c.Status = Degraded
+	}
+	return
+}
+
+// checkBlock compares the age of the last block with max age. The blocks are generated only
+// if there are transactions, so the old block means the stalled chain if the transactions
+// are pending, otherwise the node is degraded
+func checkBlock(src Source, th Thresholds, now time.Time, dbReady bool, pending int64) (c BlockCheck) {
+	if !dbReady {
+		return BlockCheck{Status: Unhealthy, Error: errNoDatabase.Error()}
+	}
+	height, timestamp, err := src.LastBlock()
+	if err != nil {
+		return BlockCheck{Status: Unhealthy, Error: err.Error()}
+	}
+	gap := src.BlockGap(height + 1)
+	maxAge := th.MaxBlockAge
+	if maxAge <= 0 {
+		maxAge = DefaultBlockAgeGaps * gap
+	}
+	age := now.Sub(timestamp)
+	c = BlockCheck{
+		Status:      Ready,
+		Height:      height,
+		Age:         age.Seconds(),
+		ExpectedGap: gap.Seconds(),
+		MaxAge:      maxAge.Seconds(),
+	}
+	if age > maxAge {
+		c.Status = Degraded
+		if pending > 0 {
+			c.Status = Unhealthy
+		}
+	}
+	return
+}
+
+func checkDisk(src Source, th Thresholds) (c DiskCheck) {
+	path, free, err := src.DiskFree()
+	c = DiskCheck{Status: Ready, Path: path, Free: free}
+	if err != nil {
+		c.Status, c.Error = Degraded, err.Error()
+		return
+	}
+	if free < th.MinDiskFree {
+		c.Status = Degraded
+	}
+	return
+}
@@ -0,0 +1,110 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+)
+
+var errLost = errors.New("connection refused")
+
+// testSource emulates the node, the fields are changed by the test
+type testSource struct {
+	dbErr     error
+	replica   bool
+	lag       time.Duration
+	height    int64
+	blockTime time.Time
+	pending   int64
+	peers     int
+	pause     node.PauseType
+	free      uint64
+}
+
+func (s *testSource) PingDB(context.Context) error { return s.dbErr }
+
+func (s *testSource) ReplicationLag(context.Context) (bool, time.Duration, error) {
+	return s.replica, s.lag, s.dbErr
+}
+
+func (s *testSource) LastBlock() (int64, time.Time, error) {
+	return s.height, s.blockTime, s.dbErr
+}
+
+func (s *testSource) BlockGap(int64) time.Duration { return 4 * time.Second }
+
+func (s *testSource) PendingTxs(context.Context) (int64, error) { return s.pending, s.dbErr }
+
+func (s *testSource) Peers() int { return s.peers }
+
+func (s *testSource) PauseType() node.PauseType { return s.pause }
+
+func (s *testSource) DiskFree() (string, uint64, error) { return "/data", s.free, nil }
+
+func TestVerdictTransitions(t *testing.T) {
+	now := time.Now()
+	th := Thresholds{MinPeers: 1, MaxQueueSize: 100, MaxReplicationLag: 30 * time.Second, MinDiskFree: 1 << 30}
+	src := &testSource{height: 10, blockTime: now.Add(-5 * time.Second), peers: 2, free: 10 << 30}
+
+	check := func(step string, expected Verdict, code int) *Report {
+		t.Helper()
+		r := Check(context.Background(), src, th, now)
+		if r.Status != expected || r.Status.StatusCode() != code {
+			t.Fatalf("%s: expected %s %d got %s %d: %+v", step, expected, code, r.Status, r.Status.StatusCode(), r)
+		}
+		return r
+	}
+
+	check("healthy node", Ready, http.StatusOK)
+
+	// the chain stalls, the idle chain with no pending transactions is only degraded
+	now = now.Add(time.Minute)
+	if r := check("idle chain", Degraded, http.StatusOK); r.Block.MaxAge != 40 {
+		t.Errorf("default max block age is %v", r.Block.MaxAge)
+	}
+	src.pending = 5
+	if r := check("stalled chain", Unhealthy, http.StatusServiceUnavailable); r.Block.Status != Unhealthy {
+		t.Errorf("block check is %s", r.Block.Status)
+	}
+	th.MaxBlockAge = 2 * time.Minute
+	check("configured max block age", Ready, http.StatusOK)
+
+	// the new block recovers the node
+	th.MaxBlockAge = 0
+	src.height, src.blockTime, src.pending = 11, now, 0
+	check("new block", Ready, http.StatusOK)
+
+	// the database connection is lost
+	src.dbErr = errLost
+	r := check("lost database", Unhealthy, http.StatusServiceUnavailable)
+	if r.Database.Error != errLost.Error() || r.Block.Status != Unhealthy || r.Queue.Status != Unhealthy {
+		t.Errorf("checks which depend on the database: %+v", r)
+	}
+	src.dbErr = nil
+	check("restored database", Ready, http.StatusOK)
+
+	src.replica, src.lag = true, time.Minute
+	check("lagging replica", Degraded, http.StatusOK)
+	src.lag = time.Second
+	src.peers, src.free = 0, 1<<20
+	if r = check("no peers and disk", Degraded, http.StatusOK); r.Peers.Status != Degraded || r.Disk.Status != Degraded {
+		t.Errorf("peers or disk aren't degraded: %+v", r)
+	}
+	src.peers, src.free = 1, 10<<30
+
+	src.pause = node.PauseTypeUpdatingBlockchain
+	if r = check("paused node", Unhealthy, http.StatusServiceUnavailable); !r.Pause.Paused {
+		t.Errorf("pause isn't reported")
+	}
+	src.pause = node.NoPause
+	check("resumed node", Ready, http.StatusOK)
+}
@@ -0,0 +1,80 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/chain/system"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/service/node"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+var errNoBlocks = errors.New("blockchain is empty")
+
+// nodeSource is the state of the running node
+type nodeSource struct{}
+
+// NodeSource returns the source of the running node
+func NodeSource() Source {
+	return nodeSource{}
+}
+
+func (nodeSource) PingDB(ctx context.Context) error {
+	return sqldb.PingDB(ctx)
+}
+
+func (nodeSource) ReplicationLag(ctx context.Context) (bool, time.Duration, error) {
+	return sqldb.GetReplicationLag(ctx)
+}
+
+func (nodeSource) LastBlock() (int64, time.Time, error) {
+	ib := &sqldb.InfoBlock{}
+	found, err := ib.Get()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if !found {
+		return 0, time.Time{}, errNoBlocks
+	}
+	return ib.BlockID, time.Unix(ib.Time, 0), nil
+}
+
+func (nodeSource) BlockGap(blockID int64) time.Duration {
+	return syspar.GetMaxBlockTimeDurationAt(blockID)
+}
+
+func (nodeSource) PendingTxs(ctx context.Context) (int64, error) {
+	return sqldb.GetPendingTransactionsCount(ctx)
+}
+
+// Peers returns the number of the remote honor nodes which aren't banned
+func (nodeSource) Peers() int {
+	hosts := syspar.GetRemoteHosts()
+	if nbs := node.GetNodesBanService(); nbs != nil {
+		if good, err := nbs.FilterBannedHosts(hosts); err == nil {
+			hosts = good
+		}
+	}
+	return len(hosts)
+}
+
+func (nodeSource) PauseType() node.PauseType {
+	return node.NodePauseType()
+}
+
+func (nodeSource) DiskFree() (string, uint64, error) {
+	path := conf.Config.DirPathConf.DataDir
+	if path == "" {
+		path = "."
+	}
+	free, err := system.DiskFree(path)
+	return path, free, err
+}
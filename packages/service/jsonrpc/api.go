@@ -8,6 +8,7 @@ package jsonrpc
 import (
 	"encoding/hex"
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
 	"github.com/IBAX-io/go-ibax/packages/types"
 	"net/http"
 	"strings"
@@ -30,6 +31,7 @@ type UserClient struct {
 	EcosystemID   int64
 	EcosystemName string
 	RoleID        int64
+	Permission    apikey.Permission // the permission of the service account, empty for the wallet session
 }
 
 func (c *UserClient) Prefix() string {
@@ -14,12 +14,14 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/publisher"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
 	"github.com/IBAX-io/go-ibax/packages/smart"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/transaction"
 	log "github.com/sirupsen/logrus"
 	"math/rand"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/IBAX-io/go-ibax/packages/converter"
@@ -48,6 +50,35 @@ func authRequire(r *http.Request) *Error {
 	return UnauthorizedError()
 }
 
+// methodPermissions are the permissions of the service accounts for the methods and the whole
+// namespaces, the other methods need the read permission
+var methodPermissions = map[string]apikey.Permission{
+	GetNamespace(NamespaceAdmin):                                apikey.PermAdmin,
+	GetNamespace(NamespaceIBAX) + namespaceSeparator + "sendTx": apikey.PermSubmit,
+}
+
+// permissionRequire checks the permission of the service account for the method, the wallet
+// sessions aren't checked
+func permissionRequire(r *http.Request, method string) *Error {
+	client := getClient(r)
+	if client == nil || client.Permission == "" {
+		return nil
+	}
+	need, ok := methodPermissions[method]
+	if !ok {
+		if need, ok = methodPermissions[strings.SplitN(method, namespaceSeparator, 2)[0]]; !ok {
+			need = apikey.PermRead
+		}
+	}
+	if client.Permission.Allows(need) {
+		return nil
+	}
+
+	logger := getLogger(r)
+	logger.WithFields(log.Fields{"type": consts.AccessDenied, "method": method, "permission": client.Permission}).Warning("api key isn't allowed to call method")
+	return ForbiddenError(fmt.Sprintf("api key isn't allowed to call %s", method))
+}
+
 type authApi struct {
 	mode Mode
 }
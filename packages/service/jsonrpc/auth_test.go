@@ -0,0 +1,38 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package jsonrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestPermissionRequire(t *testing.T) {
+	for _, c := range []struct {
+		perm    apikey.Permission
+		method  string
+		allowed bool
+	}{
+		{"", "admin.stopJsonRpc", true},
+		{apikey.PermRead, "ibax.getBlockInfo", true},
+		{apikey.PermRead, "ibax.sendTx", false},
+		{apikey.PermRead, "admin.stopJsonRpc", false},
+		{apikey.PermSubmit, "ibax.sendTx", true},
+		{apikey.PermSubmit, "net.getNetwork", true},
+		{apikey.PermSubmit, "admin.startJsonRpc", false},
+		{apikey.PermAdmin, "admin.stopJsonRpc", true},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r = setLogger(r, log.NewEntry(log.StandardLogger()))
+		r = setClient(r, &UserClient{KeyID: 7, EcosystemID: 1, Permission: c.perm})
+		if err := permissionRequire(r, c.method); (err == nil) != c.allowed {
+			t.Errorf("%q calls %s: expected %v, got %v", c.perm, c.method, c.allowed, err)
+		}
+	}
+}
@@ -5,7 +5,6 @@
 package jsonrpc
 
 import (
-	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -60,6 +59,8 @@ type BlockInfoResult struct {
 	RollbacksHash string `json:"rollbacks_hash"`
 	NodePosition  int64  `json:"node_position"`
 	ConsensusMode int32  `json:"consensus_mode"`
+	BaseGasPrice  int64  `json:"base_gas_price"`
+	GasUsed       int64  `json:"gas_used"`
 }
 
 func (b *blockChainApi) GetBlockInfo(blockID int64) (*BlockInfoResult, *Error) {
@@ -81,6 +82,8 @@ func (b *blockChainApi) GetBlockInfo(blockID int64) (*BlockInfoResult, *Error) {
 		RollbacksHash: hex.EncodeToString(bk.RollbacksHash),
 		NodePosition:  bk.NodePosition,
 		ConsensusMode: bk.ConsensusMode,
+		BaseGasPrice:  bk.BaseGasPrice,
+		GasUsed:       bk.GasUsed,
 	}
 
 	return result, nil
@@ -302,7 +305,7 @@ func (b *blockChainApi) GetBlocksTxInfo(ctx RequestContext, blockId, count int64
 
 	result := map[int64][]TxInfo{}
 	for _, blockModel := range blocks {
-		blck, err := block.UnmarshallBlock(bytes.NewBuffer(blockModel.Data), false)
+		blck, err := block.UnmarshallBlockChain(&blockModel, false)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "bolck_id": blockModel.ID}).Error("on unmarshalling block")
 			return nil, DefaultError(err.Error())
@@ -357,6 +360,7 @@ type BlockHeaderInfo struct {
 	Sign         []byte `json:"-"`
 	Hash         string `json:"-"`
 	Version      int    `json:"version"`
+	BaseGasPrice int64  `json:"base_gas_price"`
 }
 
 type BlockDetailedInfo struct {
@@ -402,7 +406,7 @@ func (b *blockChainApi) DetailedBlocks(ctx RequestContext, blockId, count int64)
 
 	result := map[int64]BlockDetailedInfo{}
 	for _, blockModel := range blocks {
-		blck, err := block.UnmarshallBlock(bytes.NewBuffer(blockModel.Data), false)
+		blck, err := block.UnmarshallBlockChain(&blockModel, false)
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "block_id": blockModel.ID}).Error("on unmarshalling block")
 			return nil, DefaultError(err.Error())
@@ -450,6 +454,7 @@ func (b *blockChainApi) DetailedBlocks(ctx RequestContext, blockId, count int64)
 			Sign:         blck.Header.Sign,
 			Hash:         hex.EncodeToString(blck.Header.BlockHash),
 			Version:      int(blck.Header.Version),
+			BaseGasPrice: blck.Header.BaseGasPrice,
 		}
 
 		bdi := BlockDetailedInfo{
@@ -571,7 +576,7 @@ func (b *blockChainApi) DetailedBlock(ctx RequestContext, bh *BlockIdOrHash) (*B
 		return nil, NotFoundError()
 	}
 
-	blck, err := block.UnmarshallBlock(bytes.NewBuffer(bk.Data), false)
+	blck, err := block.UnmarshallBlockChain(bk, false)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.UnmarshallingError, "error": err, "block_id": bk.ID}).Error("on unmarshalling block")
 		return nil, DefaultError(err.Error())
@@ -380,7 +380,7 @@ func (c *commonApi) GetList(ctx RequestContext, auth Auth, form *ListWhereForm)
 		return nil, DefaultError(err.Error())
 	}
 	var q *gorm.DB
-	q = sqldb.GetTableListQuery(form.Name, client.EcosystemID)
+	q = sqldb.GetTableListQuery(nil, form.Name, client.EcosystemID)
 
 	if len(form.Columns) > 0 {
 		q = q.Select("id," + form.Columns)
@@ -831,7 +831,7 @@ func (c *commonApi) GetTableCount(ctx RequestContext, auth Auth, offset, limit *
 		List:  make([]tableInfo, len(list)),
 	}
 	for i, item := range list {
-		err = sqldb.GetTableQuery(item["name"], client.EcosystemID).Count(&count).Error
+		err = sqldb.GetTableQuery(nil, item["name"], client.EcosystemID).Count(&count).Error
 		if err != nil {
 			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("selecting count from table")
 			return nil, DefaultError(err.Error())
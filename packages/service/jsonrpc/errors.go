@@ -30,6 +30,7 @@ const (
 	ErrCodeUnknownUID          = -32013
 	ErrCodeUnauthorized        = -32014
 	ErrCodeParamsInvalid       = -32015
+	ErrCodeForbidden           = -32016
 )
 
 const (
@@ -118,6 +119,10 @@ func UnauthorizedError() *Error {
 	return NewError(ErrCodeUnauthorized, "Unauthorized")
 }
 
+func ForbiddenError(message string) *Error {
+	return NewError(ErrCodeForbidden, message)
+}
+
 func UnUnknownUIDError() *Error {
 	return NewError(ErrCodeUnknownUID, "Unknown uid")
 }
@@ -51,6 +51,7 @@ func NewMiddlewares(srv http.Handler, m Mode) http.Handler {
 	handler := newGzipHandler(srv)
 	handler = clientMiddleware(handler, m)
 	handler = tokenMiddleware(handler)
+	handler = apiKeyMiddleware(handler)
 	handler = nodeStateMiddleware(handler)
 	//handler = statsdMiddleware(handler)
 	handler = recoverMiddleware(handler)
@@ -5,7 +5,10 @@
 package jsonrpc
 
 import (
+	"errors"
 	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/service/apikey"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
 	"github.com/IBAX-io/go-ibax/packages/statsd"
 	"github.com/didip/tollbooth"
@@ -20,7 +23,20 @@ func clientMiddleware(next http.Handler, m Mode) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := getToken(r)
 		var client *UserClient
-		if token != nil { // get client from token
+		if key := apikey.FromRequest(r); key != nil {
+			name, err := m.EcosystemGetter.GetEcosystemName(key.EcosystemID)
+			if err != nil {
+				WriteResponse(w, nil, nil, DefaultError(err.Error()))
+				return
+			}
+			client = &UserClient{
+				KeyID:         key.KeyID,
+				AccountID:     converter.AddressToString(key.KeyID),
+				EcosystemID:   key.EcosystemID,
+				EcosystemName: name,
+				Permission:    apikey.Permission(key.Permission),
+			}
+		} else if token != nil { // get client from token
 			var err error
 			if client, err = getClientFromToken(token, m.EcosystemGetter); err != nil {
 				WriteResponse(w, nil, nil, DefaultError(err.Error()))
@@ -86,10 +102,33 @@ func nodeStateMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// apiKeyStore keeps the keys of the service accounts
+var apiKeyStore = apikey.NodeStore()
+
+// apiKeyMiddleware authenticates the service accounts by the api keys
+func apiKeyMiddleware(next http.Handler) http.Handler {
+	return apikey.Middleware(apiKeyStore, denyAPIKey)(next)
+}
+
+func denyAPIKey(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, apikey.ErrInvalidKey):
+		WriteResponse(w, nil, nil, UnauthorizedError())
+	case errors.Is(err, apikey.ErrIPNotAllowed):
+		WriteResponse(w, nil, nil, ForbiddenError(err.Error()))
+	default:
+		WriteResponse(w, nil, nil, DefaultError(err.Error()))
+	}
+}
+
 func tokenMiddleware(next http.Handler) http.Handler {
 	const authHeader = "AUTHORIZATION"
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apikey.HasScheme(r.Header.Get(authHeader)) {
+			next.ServeHTTP(w, r)
+			return
+		}
 		//token, err := RefreshToken(r.Header.Get(authHeader))
 		token, err := parseJWTToken(r.Header.Get(authHeader))
 		if err != nil {
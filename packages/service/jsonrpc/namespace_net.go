@@ -7,8 +7,11 @@ package jsonrpc
 import (
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/service/node"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type NetApi struct {
@@ -53,3 +56,13 @@ func (c *networkApi) GetNetwork() (*NetworkResult, *Error) {
 		HonorNodes:    getNodesJSON(),
 	}, nil
 }
+
+// GetSyncStatus returns the last local block and the last known blocks of the peers
+func (n *networkApi) GetSyncStatus(ctx RequestContext) (*node.SyncStatus, *Error) {
+	status, err := node.GetSyncStatus()
+	if err != nil {
+		getLogger(ctx.HTTPRequest()).WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting sync status")
+		return nil, DefaultError(err.Error())
+	}
+	return status, nil
+}
@@ -115,6 +115,9 @@ func (s *serviceRegistry) run(ctx RequestContext, isBatch bool, reqs []*Request)
 	if cb == nil {
 		return nil, MethodNotFound(req.Method)
 	}
+	if err := permissionRequire(ctx.HTTPRequest(), req.Method); err != nil {
+		return nil, err
+	}
 	if cb.hasAuth {
 		r := ctx.HTTPRequest()
 		if err := authRequire(r); err != nil {
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
@@ -35,13 +34,13 @@ func txHandlerBatches(r *http.Request, m Mode, mtx map[string][]byte) ([]string,
 	logger := getLogger(r)
 	var txData [][]byte
 	for _, datum := range mtx {
+		if err := transaction.CheckIngress(datum); err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "error": err, "size": len(datum)}).Error("transaction is rejected")
+			transaction.BadTxForBan(client.KeyID, transaction.BanReasonIngress)
+			return nil, err
+		}
 		txData = append(txData, datum)
 	}
-	if int64(len(txData)) > syspar.GetMaxTxSize() {
-		logger.WithFields(log.Fields{"type": consts.ParameterExceeded, "max_size": syspar.GetMaxTxSize(), "size": len(txData)}).Error("transaction size exceeds max size")
-		transaction.BadTxForBan(client.KeyID)
-		return nil, fmt.Errorf("the size of tx is too big (%d)", len(txData))
-	}
 
 	hash, err := m.ClientTxProcessor.ProcessClientTxBatches(txData, client.KeyID, logger)
 	if err != nil {
@@ -5,7 +5,6 @@
 package jsonrpc
 
 import (
-	"bytes"
 	"encoding/hex"
 	"errors"
 	"github.com/IBAX-io/go-ibax/packages/block"
@@ -81,7 +80,7 @@ func transactionData(blockId int64, txHash string) (*smart.TxInfo, error) {
 		return nil, errors.New("not found")
 	}
 
-	blck, err := block.UnmarshallBlock(bytes.NewBuffer(bk.Data), false)
+	blck, err := block.UnmarshallBlockChain(bk, false)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,68 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package kvhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+)
+
+// KafkaProducer sends the message to the topic, it's implemented over the kafka client
+// which is chosen by the node operator
+type KafkaProducer interface {
+	Produce(topic string, key, value []byte) error
+}
+
+// KafkaMessage is the value of the message of one changed row
+type KafkaMessage struct {
+	BlockID  int64           `json:"block_id"`
+	Table    string          `json:"table"`
+	Key      string          `json:"key"`
+	OldValue json.RawMessage `json:"old_value"`
+	NewValue json.RawMessage `json:"new_value"`
+}
+
+// Kafka sends one message per changed row, the key of the message is <table>:<key>
+// so the changes of the row keep their order within the partition
+type Kafka struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafka returns the hook of the producer
+func NewKafka(producer KafkaProducer, topic string) *Kafka {
+	return &Kafka{producer: producer, topic: topic}
+}
+
+func (k *Kafka) OnCommit(blockID int64, changes []block.KVChange) error {
+	for _, change := range changes {
+		value, err := json.Marshal(KafkaMessage{
+			BlockID:  blockID,
+			Table:    change.Table,
+			Key:      string(change.Key),
+			OldValue: rawJSON(change.OldValue),
+			NewValue: rawJSON(change.NewValue),
+		})
+		if err != nil {
+			return err
+		}
+		key := change.Table + ":" + string(change.Key)
+		if err = k.producer.Produce(k.topic, []byte(key), value); err != nil {
+			return fmt.Errorf("producing %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// rawJSON returns null for the missing value
+func rawJSON(value []byte) json.RawMessage {
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package kvhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+
+	"github.com/go-redis/redis"
+)
+
+var testChanges = []block.KVChange{
+	{Table: "1_keys", Key: []byte("7,1"), OldValue: []byte(`{"amount":"1"}`), NewValue: []byte(`{"amount":"2","id":"7"}`)},
+	{Table: "1_orders", Key: []byte("3"), NewValue: []byte(`{"id":"3"}`)},
+	{Table: "1_orders", Key: []byte("2"), OldValue: []byte(`{"price":"5"}`)},
+}
+
+// testPipe records the commands, the other methods of redis.Pipeliner aren't used by the hook
+type testPipe struct {
+	redis.Pipeliner
+	cmds []string
+}
+
+func (p *testPipe) Set(key string, value any, _ time.Duration) *redis.StatusCmd {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	p.cmds = append(p.cmds, fmt.Sprintf("SET %s %v", key, value))
+	return redis.NewStatusCmd()
+}
+
+func (p *testPipe) Del(keys ...string) *redis.IntCmd {
+	p.cmds = append(p.cmds, fmt.Sprintf("DEL %v", keys))
+	return redis.NewIntCmd()
+}
+
+type testClient struct {
+	txs [][]string
+}
+
+func (c *testClient) TxPipelined(fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	pipe := &testPipe{}
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+	c.txs = append(c.txs, pipe.cmds)
+	return nil, nil
+}
+
+func TestRedis(t *testing.T) {
+	client := &testClient{}
+	if err := NewRedis(client, "ibax:").OnCommit(10, testChanges); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{
+		`SET ibax:1_keys:7,1 {"amount":"2","id":"7"}`,
+		`SET ibax:1_orders:3 {"id":"3"}`,
+		`DEL [ibax:1_orders:2]`,
+		`SET ibax:block_id 10`,
+	}}
+	if !reflect.DeepEqual(client.txs, expected) {
+		t.Errorf("wrong commands %q", client.txs)
+	}
+}
+
+type testProducer struct {
+	keys   []string
+	values []KafkaMessage
+	err    error
+}
+
+func (p *testProducer) Produce(topic string, key, value []byte) error {
+	if topic != "changes" {
+		return fmt.Errorf("wrong topic %s", topic)
+	}
+	var msg KafkaMessage
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return err
+	}
+	p.keys = append(p.keys, string(key))
+	p.values = append(p.values, msg)
+	return p.err
+}
+
+func TestKafka(t *testing.T) {
+	producer := &testProducer{}
+	if err := NewKafka(producer, "changes").OnCommit(10, testChanges); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(producer.keys, []string{"1_keys:7,1", "1_orders:3", "1_orders:2"}) {
+		t.Errorf("wrong keys %v", producer.keys)
+	}
+	if msg := producer.values[0]; msg.BlockID != 10 || msg.Table != "1_keys" || msg.Key != "7,1" ||
+		string(msg.OldValue) != `{"amount":"1"}` || string(msg.NewValue) != `{"amount":"2","id":"7"}` {
+		t.Errorf("wrong message %+v", msg)
+	}
+	if string(producer.values[1].OldValue) != "null" || string(producer.values[2].NewValue) != "null" {
+		t.Errorf("missing values must be null %+v", producer.values)
+	}
+
+	errProduce := errors.New("broker is down")
+	producer = &testProducer{err: errProduce}
+	if err := NewKafka(producer, "changes").OnCommit(10, testChanges); !errors.Is(err, errProduce) || len(producer.keys) != 1 {
+		t.Errorf("expected the first message to fail, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package kvhook copies the rows changed by the committed blocks to the external key-value
+// stores, so the indexers get the updates without polling the database
+package kvhook
+
+import (
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/block"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+
+	"github.com/go-redis/redis"
+)
+
+// BlockIDKey is the key of the id of the last copied block, the prefix is prepended to it
+const BlockIDKey = "block_id"
+
+// TxPipeliner executes the commands within MULTI/EXEC, it's implemented by *redis.Client
+type TxPipeliner interface {
+	TxPipelined(fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+}
+
+// Redis writes the rows of the block to the keys <prefix><table>:<key> within one MULTI/EXEC.
+// The removed rows are deleted, the id of the block is written to <prefix>block_id
+type Redis struct {
+	client TxPipeliner
+	prefix string
+}
+
+// NewRedis returns the hook of the redis client
+func NewRedis(client TxPipeliner, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// NewRedisClient connects to the redis of the node config with the database db
+func NewRedisClient(cfg conf.RedisConfig, db int) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       db,
+	})
+	if err := client.Ping().Err(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Key returns the redis key of the row
+func (r *Redis) Key(change block.KVChange) string {
+	return r.prefix + change.Table + ":" + string(change.Key)
+}
+
+func (r *Redis) OnCommit(blockID int64, changes []block.KVChange) error {
+	_, err := r.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		for _, change := range changes {
+			if change.NewValue == nil {
+				pipe.Del(r.Key(change))
+				continue
+			}
+			pipe.Set(r.Key(change), change.NewValue, 0)
+		}
+		pipe.Set(r.prefix+BlockIDKey, blockID, 0)
+		return nil
+	})
+	return err
+}
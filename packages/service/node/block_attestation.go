@@ -0,0 +1,74 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// ErrBlockNotFound is returned when the block is not in the local chain
+var ErrBlockNotFound = errors.New("block has not been found")
+
+// BlockAttestation is the statement signed by the node that the block hash is canonical at the height
+type BlockAttestation struct {
+	BlockID   int64  `json:"block_id"`
+	BlockHash []byte `json:"block_hash"`
+	NodeKeyID int64  `json:"node_key_id"`
+	Timestamp int64  `json:"timestamp"`
+	Signature []byte `json:"signature"`
+}
+
+// Digest returns SHA256(block_id || block_hash || timestamp), the integers are 8 bytes big-endian
+func (a *BlockAttestation) Digest() []byte {
+	buf := make([]byte, 0, 16+len(a.BlockHash))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.BlockID))
+	buf = append(buf, a.BlockHash...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.Timestamp))
+	digest := sha256.Sum256(buf)
+	return digest[:]
+}
+
+// Verify checks that the attestation is signed with publicKey of the node NodeKeyID
+func (a *BlockAttestation) Verify(publicKey []byte) (bool, error) {
+	if crypto.Address(publicKey) != a.NodeKeyID {
+		return false, nil
+	}
+	return crypto.Verify(publicKey, a.Digest(), a.Signature)
+}
+
+// NewBlockAttestation signs the attestation of the block hash with signer
+func NewBlockAttestation(blockID int64, blockHash []byte, signer keystore.Signer, timestamp int64) (*BlockAttestation, error) {
+	a := &BlockAttestation{
+		BlockID:   blockID,
+		BlockHash: blockHash,
+		NodeKeyID: crypto.Address(signer.PublicKey()),
+		Timestamp: timestamp,
+	}
+	var err error
+	if a.Signature, err = keystore.SignData(signer, a.Digest()); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// SignBlockAttestation returns the attestation of the block from the local chain
+func SignBlockAttestation(blockID int64, signer keystore.Signer) (*BlockAttestation, error) {
+	block := &sqldb.BlockChain{}
+	found, err := block.Get(blockID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrBlockNotFound
+	}
+	return NewBlockAttestation(block.ID, block.Hash, signer, time.Now().Unix())
+}
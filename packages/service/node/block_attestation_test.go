@@ -0,0 +1,43 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+)
+
+func TestBlockAttestation(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, pub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keystore.NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewBlockAttestation(10, crypto.Hash([]byte("block")), signer, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := a.Verify(pub); !ok || err != nil {
+		t.Fatalf("valid attestation is rejected: %v", err)
+	}
+	if ok, _ := a.Verify(otherPub); ok {
+		t.Error("attestation is accepted with the wrong public key")
+	}
+	a.BlockID = 11
+	if ok, _ := a.Verify(pub); ok {
+		t.Error("attestation of the changed block id is accepted")
+	}
+}
@@ -0,0 +1,84 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// ErrCheckpointThreshold is returned when the checkpoint doesn't have enough attestations of the honor nodes
+var ErrCheckpointThreshold = errors.New("checkpoint doesn't have enough attestations of the honor nodes")
+
+// Checkpoint is the block hash at the height which is attested by more than two thirds of the honor nodes.
+// The client which knows the public keys of the honor nodes trusts the block without the blocks before it
+type Checkpoint struct {
+	BlockID      int64               `json:"block_id"`
+	BlockHash    []byte              `json:"block_hash"`
+	Attestations []*BlockAttestation `json:"attestations"`
+}
+
+// CheckpointThreshold returns the number of the attestations which finalize the checkpoint of nodes honor nodes
+func CheckpointThreshold(nodes int) int {
+	return nodes*2/3 + 1
+}
+
+// HonorNodeKeys returns the public keys of the honor nodes which attest the checkpoints
+func HonorNodeKeys() [][]byte {
+	nodes := syspar.GetNodes()
+	keys := make([][]byte, 0, len(nodes))
+	for _, node := range nodes {
+		keys = append(keys, node.PublicKey)
+	}
+	return keys
+}
+
+// Verify checks that the checkpoint is attested by threshold different keys of publicKeys, the attestations
+// of the other blocks and keys aren't counted
+func (c *Checkpoint) Verify(publicKeys [][]byte, threshold int) error {
+	keys := make(map[int64][]byte, len(publicKeys))
+	for _, key := range publicKeys {
+		keys[crypto.Address(key)] = key
+	}
+	attested := make(map[int64]bool)
+	for _, a := range c.Attestations {
+		key, ok := keys[a.NodeKeyID]
+		if !ok || attested[a.NodeKeyID] || a.BlockID != c.BlockID || !bytes.Equal(a.BlockHash, c.BlockHash) {
+			continue
+		}
+		if ok, err := a.Verify(key); err != nil || !ok {
+			continue
+		}
+		attested[a.NodeKeyID] = true
+	}
+	if len(attested) < threshold {
+		return fmt.Errorf("%w: %d of %d", ErrCheckpointThreshold, len(attested), threshold)
+	}
+	return nil
+}
+
+// Model returns the model of the checkpoint
+func (c *Checkpoint) Model() (*sqldb.Checkpoint, error) {
+	data, err := json.Marshal(c.Attestations)
+	if err != nil {
+		return nil, err
+	}
+	return &sqldb.Checkpoint{BlockID: c.BlockID, Hash: c.BlockHash, Attestations: data}, nil
+}
+
+// CheckpointFromModel returns the checkpoint of the model
+func CheckpointFromModel(m *sqldb.Checkpoint) (*Checkpoint, error) {
+	c := &Checkpoint{BlockID: m.BlockID, BlockHash: m.Hash}
+	if err := json.Unmarshal(m.Attestations, &c.Attestations); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
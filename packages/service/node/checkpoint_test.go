@@ -0,0 +1,54 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+)
+
+func TestCheckpointVerify(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	hash := crypto.Hash([]byte("block"))
+	c := &Checkpoint{BlockID: 100, BlockHash: hash}
+	var keys [][]byte
+	for i := 0; i < 4; i++ {
+		priv, pub, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, pub)
+		signer, err := keystore.NewKeySigner(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blockHash := hash
+		if i == 3 {
+			// the node of the other fork
+			blockHash = crypto.Hash([]byte("fork"))
+		}
+		a, err := NewBlockAttestation(100, blockHash, signer, 1700000000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Attestations = append(c.Attestations, a)
+	}
+	// the repeated attestation is counted once
+	c.Attestations = append(c.Attestations, c.Attestations[0])
+	threshold := CheckpointThreshold(len(keys))
+	if threshold != 3 {
+		t.Fatalf("expected threshold 3, got %d", threshold)
+	}
+	if err := c.Verify(keys, threshold); err != nil {
+		t.Errorf("valid checkpoint is rejected: %v", err)
+	}
+	if err := c.Verify(keys[1:], threshold); !errors.Is(err, ErrCheckpointThreshold) {
+		t.Errorf("expected %v, got %v", ErrCheckpointThreshold, err)
+	}
+}
@@ -0,0 +1,139 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+var (
+	// ErrNotMember is returned when the key isn't the member of the ecosystem
+	ErrNotMember = errors.New("key isn't member of the ecosystem")
+	// ErrMembershipProof is returned by VerifyMembershipProof for the proof which doesn't hold
+	ErrMembershipProof = errors.New("wrong membership proof")
+)
+
+// MembershipProof is the Merkle path from the membership record of the key to the state root of the
+// ecosystem signed by the node. Until the chain has the state trie, the state root is the Merkle root
+// of MembershipLeaf of the keys which are neither deleted nor blocked ordered by id. It's calculated
+// by the node at BlockID and isn't the part of the consensus, so the verifier must get the root from
+// the source it trusts
+type MembershipProof struct {
+	EcosystemID int64                   `json:"ecosystem_id"`
+	KeyID       int64                   `json:"key_id"`
+	BlockID     int64                   `json:"block_id"`
+	StateRoot   []byte                  `json:"state_root"`
+	Path        []types.MerkleProofItem `json:"path"`
+	NodeKeyID   int64                   `json:"node_key_id"`
+	Timestamp   int64                   `json:"timestamp"`
+	Signature   []byte                  `json:"signature"`
+}
+
+// MembershipLeaf returns the membership record of the key, it's ecosystem || key_id as 8 bytes big-endian
+func MembershipLeaf(ecosystem, keyID int64) []byte {
+	buf := make([]byte, 0, 16)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(ecosystem))
+	return binary.BigEndian.AppendUint64(buf, uint64(keyID))
+}
+
+// MembershipRoot returns the state root of the ecosystem for the ids of its members
+func MembershipRoot(ecosystem int64, members []int64) []byte {
+	return types.MerkleTreeRoot(membershipLeaves(ecosystem, members))
+}
+
+func membershipLeaves(ecosystem int64, members []int64) [][]byte {
+	leaves := make([][]byte, len(members))
+	for i, id := range members {
+		leaves[i] = MembershipLeaf(ecosystem, id)
+	}
+	return leaves
+}
+
+// Digest returns SHA256(ecosystem_id || key_id || block_id || state_root || path || timestamp), the
+// integers are 8 bytes big-endian and every item of the path is its side byte followed by its hash
+func (p *MembershipProof) Digest() []byte {
+	buf := make([]byte, 0, 32+len(p.StateRoot))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(p.EcosystemID))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(p.KeyID))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(p.BlockID))
+	buf = append(buf, p.StateRoot...)
+	for _, item := range p.Path {
+		side := byte(0)
+		if item.Left {
+			side = 1
+		}
+		buf = append(append(buf, side), item.Hash...)
+	}
+	buf = binary.BigEndian.AppendUint64(buf, uint64(p.Timestamp))
+	digest := sha256.Sum256(buf)
+	return digest[:]
+}
+
+// NewMembershipProof signs the proof of the key for the ids of the members of the ecosystem at the block,
+// the ids are ordered
+func NewMembershipProof(ecosystem, keyID, blockID int64, members []int64, signer keystore.Signer, timestamp int64) (*MembershipProof, error) {
+	index := sort.Search(len(members), func(i int) bool { return members[i] >= keyID })
+	if index == len(members) || members[index] != keyID {
+		return nil, ErrNotMember
+	}
+	leaves := membershipLeaves(ecosystem, members)
+	p := &MembershipProof{
+		EcosystemID: ecosystem,
+		KeyID:       keyID,
+		BlockID:     blockID,
+		StateRoot:   types.MerkleTreeRoot(leaves),
+		Path:        types.MerkleTreeProof(leaves, index),
+		NodeKeyID:   crypto.Address(signer.PublicKey()),
+		Timestamp:   timestamp,
+	}
+	var err error
+	if p.Signature, err = keystore.SignData(signer, p.Digest()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SignMembershipProof returns the proof of the key for the members of the ecosystem at the last block
+func SignMembershipProof(ecosystem, keyID int64, signer keystore.Signer) (*MembershipProof, error) {
+	blockID, members, err := sqldb.GetEcosystemMembers(ecosystem)
+	if err != nil {
+		return nil, err
+	}
+	return NewMembershipProof(ecosystem, keyID, blockID, members, signer, time.Now().Unix())
+}
+
+// VerifyMembershipProof checks the json of MembershipProof with the state root of the ecosystem and
+// the public key of the node. It returns nil if the key of the proof is the member of the ecosystem
+func VerifyMembershipProof(proof []byte, ecoStateRoot []byte, nodePublicKey []byte) error {
+	p := &MembershipProof{}
+	if err := json.Unmarshal(proof, p); err != nil {
+		return fmt.Errorf("%w: %v", ErrMembershipProof, err)
+	}
+	if !bytes.Equal(p.StateRoot, ecoStateRoot) {
+		return fmt.Errorf("%w: state root doesn't match", ErrMembershipProof)
+	}
+	if crypto.Address(nodePublicKey) != p.NodeKeyID {
+		return fmt.Errorf("%w: proof isn't signed by the node", ErrMembershipProof)
+	}
+	if ok, err := crypto.Verify(nodePublicKey, p.Digest(), p.Signature); err != nil || !ok {
+		return fmt.Errorf("%w: wrong signature", ErrMembershipProof)
+	}
+	if !types.VerifyMerkleProof(p.StateRoot, MembershipLeaf(p.EcosystemID, p.KeyID), p.Path) {
+		return fmt.Errorf("%w: path doesn't lead to the state root", ErrMembershipProof)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+)
+
+func TestMembershipProof(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, pub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPub, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := keystore.NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members := []int64{-70, 3, 15, 42, 108}
+	root := MembershipRoot(2, members)
+	if _, err = NewMembershipProof(2, 16, 10, members, signer, 1700000000); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("proof of the key which isn't member: %v", err)
+	}
+	for _, members := range [][]int64{{42}, members} {
+		p, err := NewMembershipProof(2, 42, 10, members, signer, 1700000000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = VerifyMembershipProof(data, MembershipRoot(2, members), pub); err != nil {
+			t.Fatalf("valid proof of %v is rejected: %v", members, err)
+		}
+	}
+
+	p, err := NewMembershipProof(2, 42, 10, members, signer, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify := func(p *MembershipProof, root, pub []byte) error {
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return VerifyMembershipProof(data, root, pub)
+	}
+	if err = verify(p, root, otherPub); !errors.Is(err, ErrMembershipProof) {
+		t.Errorf("proof is accepted with the wrong public key: %v", err)
+	}
+	if err = verify(p, MembershipRoot(2, members[1:]), pub); !errors.Is(err, ErrMembershipProof) {
+		t.Errorf("proof is accepted with the wrong state root: %v", err)
+	}
+	changed := *p
+	changed.KeyID = 15
+	if err = verify(&changed, root, pub); !errors.Is(err, ErrMembershipProof) {
+		t.Errorf("proof of the changed key id is accepted: %v", err)
+	}
+	if err = VerifyMembershipProof([]byte("{"), root, pub); !errors.Is(err, ErrMembershipProof) {
+		t.Errorf("malformed proof is accepted: %v", err)
+	}
+}
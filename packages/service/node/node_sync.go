@@ -0,0 +1,58 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"encoding/hex"
+
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// SyncStatus is the last local block and the last known blocks of the peers
+type SyncStatus struct {
+	LocalBlockID   int64        `json:"local_block_id"`
+	LocalBlockHash string       `json:"local_block_hash"`
+	LocalTimestamp int64        `json:"local_timestamp"`
+	Peers          []PeerStatus `json:"peers"`
+}
+
+// PeerStatus is the last known block of the peer, PeerLag is the local block id minus the block id of the peer
+type PeerStatus struct {
+	PeerID                string `json:"peer_id"`
+	PeerBlockID           int64  `json:"peer_block_id"`
+	PeerBlockHash         string `json:"peer_block_hash"`
+	LastMessageReceivedAt int64  `json:"last_message_received_at"`
+	PeerLag               int64  `json:"peer_lag"`
+}
+
+// GetSyncStatus returns the sync status of the node, the state of the peers is taken from their
+// last messages without the network requests
+func GetSyncStatus() (*SyncStatus, error) {
+	infoBlock := &sqldb.InfoBlock{}
+	if _, err := infoBlock.Get(); err != nil {
+		return nil, err
+	}
+	return newSyncStatus(infoBlock, network.Peers.List()), nil
+}
+
+func newSyncStatus(infoBlock *sqldb.InfoBlock, peers []network.PeerState) *SyncStatus {
+	status := &SyncStatus{
+		LocalBlockID:   infoBlock.BlockID,
+		LocalBlockHash: hex.EncodeToString(infoBlock.Hash),
+		LocalTimestamp: infoBlock.Time,
+		Peers:          make([]PeerStatus, 0, len(peers)),
+	}
+	for _, peer := range peers {
+		status.Peers = append(status.Peers, PeerStatus{
+			PeerID:                peer.PeerID,
+			PeerBlockID:           peer.BlockID,
+			PeerBlockHash:         hex.EncodeToString(peer.BlockHash),
+			LastMessageReceivedAt: peer.LastMessageAt.Unix(),
+			PeerLag:               infoBlock.BlockID - peer.BlockID,
+		})
+	}
+	return status
+}
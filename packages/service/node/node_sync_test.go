@@ -0,0 +1,32 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/network"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+func TestSyncStatusLag(t *testing.T) {
+	status := newSyncStatus(&sqldb.InfoBlock{BlockID: 100, Hash: []byte{1}, Time: 50}, []network.PeerState{
+		{PeerID: "a:7078", BlockID: 104, BlockHash: []byte{2}, LastMessageAt: time.Unix(60, 0)},
+		{PeerID: "b:7078", BlockID: 98},
+	})
+	if status.LocalBlockID != 100 || status.LocalBlockHash != "01" || status.LocalTimestamp != 50 {
+		t.Errorf("wrong local block %+v", status)
+	}
+	if len(status.Peers) != 2 {
+		t.Fatalf("expected 2 peers, got %d", len(status.Peers))
+	}
+	if p := status.Peers[0]; p.PeerLag != -4 || p.PeerBlockHash != "02" || p.LastMessageReceivedAt != 60 {
+		t.Errorf("wrong peer %+v", p)
+	}
+	if p := status.Peers[1]; p.PeerLag != 2 {
+		t.Errorf("wrong peer %+v", p)
+	}
+}
@@ -0,0 +1,109 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+const (
+	fetchTimeout = 10 * time.Second
+	maxBodySize  = 1 << 20
+)
+
+var (
+	ErrPathNotFound = errors.New("json path has not been found")
+	ErrWrongScheme  = errors.New("url must begin with http:// or https://")
+)
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// Fetch requests url and returns the value of json path from the response.
+// The path is the list of object keys and array indexes separated by dots,
+// the empty path returns the whole response
+func Fetch(ctx context.Context, url, path string) (string, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", ErrWrongScheme
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: wrong status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return "", err
+	}
+	return JSONValue(body, path)
+}
+
+// JSONValue returns the value of path in data as string
+func JSONValue(data []byte, path string) (string, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+	if len(path) > 0 {
+		for _, key := range strings.Split(path, ".") {
+			switch item := v.(type) {
+			case map[string]any:
+				val, ok := item[key]
+				if !ok {
+					return "", fmt.Errorf("%w: %s", ErrPathNotFound, path)
+				}
+				v = val
+			case []any:
+				i, err := strconv.Atoi(key)
+				if err != nil || i < 0 || i >= len(item) {
+					return "", fmt.Errorf("%w: %s", ErrPathNotFound, path)
+				}
+				v = item[i]
+			default:
+				return "", fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+		}
+	}
+	switch item := v.(type) {
+	case string:
+		return item, nil
+	case json.Number:
+		return item.String(), nil
+	case nil:
+		return "", nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// CommitHash returns the hash which is committed before revealing value.
+// It must match Hash(Sprintf("%d:%s:%s", RequestId, Value, Salt)) of @1OracleReveal contract
+func CommitHash(requestID int64, value, salt string) string {
+	return hex.EncodeToString(crypto.Hash([]byte(fmt.Sprintf("%d:%s:%s", requestID, value, salt))))
+}
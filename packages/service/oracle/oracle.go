@@ -0,0 +1,186 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package oracle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/transaction"
+	"github.com/IBAX-io/go-ibax/packages/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// resendTimeout is the time of waiting the transaction in the block before sending it again
+const resendTimeout = time.Minute
+
+type answer struct {
+	value    string
+	salt     string
+	commitAt time.Time
+	revealAt time.Time
+}
+
+// Service fetches the values of oracle requests and sends the commits and the reveals
+// of this honor node. The values with salts are kept in memory until the request is
+// resolved, so the node which has been restarted after the commit can't reveal its answer
+type Service struct {
+	mu        sync.Mutex
+	answers   map[int64]*answer
+	resolveAt map[int64]time.Time
+}
+
+var service = &Service{
+	answers:   make(map[int64]*answer),
+	resolveAt: make(map[int64]time.Time),
+}
+
+// GetService returns the oracle service of the node
+func GetService() *Service {
+	return service
+}
+
+// Process handles the pending oracle requests
+func (s *Service) Process(ctx context.Context) error {
+	position, err := syspar.GetThisNodePosition()
+	if err != nil {
+		return nil
+	}
+	requests, err := sqldb.GetPendingOracleRequests()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting pending oracle requests")
+		return err
+	}
+	nodeID := crypto.Address(syspar.GetNodePubKey())
+	nodes := syspar.GetNumberOfNodes()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make(map[int64]bool)
+	for _, req := range requests {
+		pending[req.ID] = true
+		if time.Now().Unix() >= req.Deadline {
+			// only one node sends the resolving transaction
+			if nodes > 0 && req.ID%nodes == position && time.Since(s.resolveAt[req.ID]) > resendTimeout {
+				s.resolveAt[req.ID] = time.Now()
+				if err := sendTx(smart.OracleResolveContract, map[string]any{"RequestId": req.ID}); err != nil {
+					log.WithFields(log.Fields{"type": consts.ContractError, "error": err, "request_id": req.ID}).Error("sending oracle resolve")
+				}
+			}
+			continue
+		}
+		answers, err := sqldb.GetOracleAnswers(req.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting oracle answers")
+			return err
+		}
+		if err := s.processRequest(ctx, nodeID, &req, answers); err != nil {
+			log.WithFields(log.Fields{"type": consts.ContractError, "error": err, "request_id": req.ID}).Error("processing oracle request")
+		}
+	}
+	for id := range s.answers {
+		if !pending[id] {
+			delete(s.answers, id)
+		}
+	}
+	for id := range s.resolveAt {
+		if !pending[id] {
+			delete(s.resolveAt, id)
+		}
+	}
+	return nil
+}
+
+func (s *Service) processRequest(ctx context.Context, nodeID int64, req *sqldb.OracleRequest, answers []sqldb.OracleAnswer) error {
+	var (
+		mine     *sqldb.OracleAnswer
+		revealed bool
+	)
+	for i := range answers {
+		if answers[i].NodeID == nodeID {
+			mine = &answers[i]
+		}
+		if answers[i].Revealed != 0 {
+			revealed = true
+		}
+	}
+	a := s.answers[req.ID]
+	if mine == nil {
+		// the commits are not accepted after the first reveal
+		if revealed || (a != nil && time.Since(a.commitAt) < resendTimeout) {
+			return nil
+		}
+		if a == nil {
+			value, err := Fetch(ctx, req.Url, req.JsonPath)
+			if err != nil {
+				return err
+			}
+			salt := make([]byte, 16)
+			if _, err = rand.Read(salt); err != nil {
+				return err
+			}
+			a = &answer{value: value, salt: hex.EncodeToString(salt)}
+			s.answers[req.ID] = a
+		}
+		a.commitAt = time.Now()
+		return sendTx(smart.OracleCommitContract, map[string]any{
+			"RequestId": req.ID,
+			"Hash":      CommitHash(req.ID, a.value, a.salt),
+		})
+	}
+	if mine.Revealed != 0 || int64(len(answers)) < req.Threshold {
+		return nil
+	}
+	if a == nil {
+		return fmt.Errorf("value of the committed answer has been lost")
+	}
+	if time.Since(a.revealAt) < resendTimeout {
+		return nil
+	}
+	a.revealAt = time.Now()
+	return sendTx(smart.OracleRevealContract, map[string]any{
+		"RequestId": req.ID,
+		"Value":     a.value,
+		"Salt":      a.salt,
+	})
+}
+
+func sendTx(name string, params map[string]any) error {
+	contract := smart.GetContract(name, 1)
+	if contract == nil {
+		return fmt.Errorf("contract %s has not been found", name)
+	}
+	sc := types.SmartTransaction{
+		Header: &types.Header{
+			ID:          int(contract.Info().ID),
+			EcosystemID: 1,
+			Time:        time.Now().Unix(),
+			KeyID:       conf.Config.KeyID,
+			NetworkID:   conf.Config.LocalConf.NetworkID,
+		},
+		Params: params,
+	}
+	stp := &transaction.SmartTransactionParser{
+		SmartContract: &smart.SmartContract{TxSmart: new(types.SmartTransaction)},
+	}
+	txData, err := stp.BinMarshalWithPrivate(&sc, syspar.GetNodePrivKey(), true)
+	if err != nil {
+		return err
+	}
+	return transaction.CreateTransaction(txData, stp.Hash, conf.Config.KeyID, stp.Timestamp)
+}
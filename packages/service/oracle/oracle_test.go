@@ -0,0 +1,36 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package oracle
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJSONValue(t *testing.T) {
+	data := []byte(`{"a":{"b":[1,{"c":"text"}],"big":12345678901234567890,"obj":{"x":true}}}`)
+	cases := map[string]string{
+		"a.b.0":   "1",
+		"a.b.1.c": "text",
+		"a.big":   "12345678901234567890",
+		"a.obj":   `{"x":true}`,
+	}
+	for path, want := range cases {
+		got, err := JSONValue(data, path)
+		if err != nil || got != want {
+			t.Errorf("%s: got %q, %v want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"a.c", "a.b.2", "a.b.-1", "a.b.1.c.d"} {
+		if _, err := JSONValue(data, path); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("%s: expected path error, got %v", path, err)
+		}
+	}
+	if _, err := Fetch(context.Background(), "ftp://localhost/data", ""); !errors.Is(err, ErrWrongScheme) {
+		t.Errorf("expected scheme error, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// authTimeLimit is the time limit of the auth contract in milliseconds
+const authTimeLimit int64 = 5
+
+// ErrAuthContract is returned when the abstract account transaction isn't authorized by the auth contract
+var ErrAuthContract = errors.New("transaction isn't authorized by the auth contract")
+
+// SetKeyAuthContract sets the auth contract of the transaction key in the ecosystem of the transaction,
+// the empty name removes it. The abstract account transactions of the key are authorized by the contract
+func SetKeyAuthContract(sc *SmartContract, name string) error {
+	if err := validateAccess(sc, "SetKeyAuthContract"); err != nil {
+		return err
+	}
+	ecosystem, keyID := sc.TxSmart.EcosystemID, sc.TxSmart.KeyID
+	if len(name) > 0 {
+		contract := VMGetContract(sc.VM, name, uint32(ecosystem))
+		if contract == nil {
+			return logErrorf(eUnknownContract, name, consts.NotFound, "looking for auth contract")
+		}
+		name = contract.Name
+	}
+	key := &sqldb.Key{}
+	found, err := key.SetTablePrefix(ecosystem).Get(sc.DbTransaction, keyID)
+	if err != nil {
+		return logErrorDB(err, "getting key")
+	}
+	if !found {
+		return logError(fmt.Errorf(eEcoKeyNotFound, converter.AddressToString(keyID), ecosystem), consts.NotFound, "looking for keyid in ecosystem")
+	}
+	if key.AuthContract == name {
+		return nil
+	}
+	_, _, err = sc.updateWhere([]string{"auth_contract"}, []any{name}, "1_keys",
+		types.LoadMap(map[string]any{"id": keyID, "ecosystem": ecosystem}))
+	return err
+}
+
+// checkAuthContract authorizes the abstract account transaction by the auth contract of the key. The
+// contract gets the hash of the transaction in $Hash and the attached data in $AuthData and sets
+// $result to true. The generator stops the contract after authTimeLimit, the validators are bound by the fuel
+func (sc *SmartContract) checkAuthContract() error {
+	if len(sc.Key.AuthContract) == 0 {
+		return fmt.Errorf("%w: key %s has no auth contract", ErrAuthContract, converter.AddressToString(sc.Key.ID))
+	}
+	contract := VMGetContract(sc.VM, sc.Key.AuthContract, uint32(sc.TxSmart.EcosystemID))
+	if contract == nil {
+		return fmt.Errorf("%w: "+eUnknownContract, ErrAuthContract, sc.Key.AuthContract)
+	}
+	extend := sc.getExtend()
+	_, name := converter.ParseName(contract.Name)
+	extend[script.Extend_original_contract] = name
+	extend[script.Extend_this_contract] = name
+	extend[script.Extend_contract] = contract
+	extend[script.Extend_time_limit] = authTimeLimit
+	extend["Hash"] = sc.Hash
+	extend["AuthData"] = sc.TxSignature
+	before := extend[script.Extend_txcost].(int64)
+	err := script.RunContractByName(sc.VM, contract.Name, []string{`conditions`, `action`}, extend, sc.Hash)
+	sc.authFuel = before - extend[script.Extend_txcost].(int64)
+	if err != nil {
+		return logError(fmt.Errorf("%w: %s", ErrAuthContract, err), consts.ContractError, "running auth contract")
+	}
+	if ok, _ := extend[script.Extend_result].(bool); !ok {
+		return logError(fmt.Errorf("%w: %s returns %v", ErrAuthContract, contract.Name, extend[script.Extend_result]),
+			consts.AccessDenied, "checking auth contract")
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+func TestCheckAuthContract(t *testing.T) {
+	for _, name := range []string{"", "@1UnknownAuth"} {
+		sc := newFreezeContract(1, 5)
+		sc.VM = script.GetVM()
+		sc.Key = &sqldb.Key{ID: 5, AuthContract: name}
+		if err := sc.checkAuthContract(); !errors.Is(err, ErrAuthContract) {
+			t.Errorf("%q: expected %v, got %v", name, ErrAuthContract, err)
+		}
+	}
+}
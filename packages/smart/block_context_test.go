@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package smart
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestBlockContext(t *testing.T) {
+	InitVM()
+	owner := &script.OwnerInfo{StateID: 1, TableID: 1}
+	if err := script.GetVM().Compile([]rune(`contract TestBlockContext {
+		action {
+			$got_type = $tx_type
+			$got_block = $block_id
+			$got_hash = $tx_hash
+		}
+	}`), owner); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tx_type", "block_id", "tx_hash"} {
+		if err := script.GetVM().Compile([]rune(fmt.Sprintf(`contract TestSet_%s { action { $%s = 1 } }`, name, name)), owner); err == nil {
+			t.Errorf("$%s is changed", name)
+		}
+	}
+
+	hash := []byte{1, 2, 3}
+	for txType, want := range map[int]int{
+		0:                         types.SmartContractTxType,
+		types.SmartContractTxType: types.SmartContractTxType,
+		types.DelayTxType:         types.DelayTxType,
+	} {
+		sc := &SmartContract{
+			TxSmart:     &types.SmartTransaction{Header: &types.Header{EcosystemID: 1}, MaxSum: "100000"},
+			BlockHeader: &types.BlockHeader{BlockId: 7},
+			Key:         &sqldb.Key{},
+			Hash:        hash,
+			TxType:      txType,
+		}
+		extend := sc.getExtend()
+		action := GetContract("TestBlockContext", 1).GetFunc("action")
+		if _, err := script.VMRun(script.GetVM(), action, nil, extend, hash); err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(extend["got_type"]) != fmt.Sprint(want) || extend["got_block"] != int64(7) || extend["got_hash"] != hex.EncodeToString(hash) {
+			t.Errorf("%d: wrong block context %v %v %v", txType, extend["got_type"], extend["got_block"], extend["got_hash"])
+		}
+	}
+}
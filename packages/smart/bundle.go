@@ -0,0 +1,31 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/service/bundle"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BundlePlan verifies the bundle and returns the calls of the contracts which import it into
+// the ecosystem of the transaction. The conflicts fail the import unless overwrite is set
+func BundlePlan(sc *SmartContract, data string, overwrite bool) ([]any, error) {
+	b, err := bundle.Parse([]byte(data))
+	if err != nil {
+		sc.GetLogger().WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("parsing bundle")
+		return nil, err
+	}
+	plan, err := bundle.MakePlan(bundle.NewDBSource(sc.DbTransaction), sc.TxSmart.EcosystemID, b, overwrite)
+	if err != nil {
+		return nil, logErrorDB(err, "planning bundle import")
+	}
+	if err = plan.ConflictError(); err != nil {
+		return nil, err
+	}
+	return plan.Calls(), nil
+}
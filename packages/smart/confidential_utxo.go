@@ -0,0 +1,208 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrConfidentialOutputs is returned if the plain UTXO transfer spends the confidential outputs,
+	// their amounts are spent by the confidential transfer only
+	ErrConfidentialOutputs = errors.New(`confidential outputs are spent by the confidential transfer only`)
+	// ErrConfidentialBalance is returned if the commitments of the inputs and the outputs don't balance
+	ErrConfidentialBalance = errors.New(`confidential inputs and outputs don't balance`)
+)
+
+// checkPlainOutputs returns ErrConfidentialOutputs if any of the inputs is confidential
+func checkPlainOutputs(inputs []sqldb.SpentInfo) error {
+	for i := range inputs {
+		if inputs[i].Confidential() {
+			return ErrConfidentialOutputs
+		}
+	}
+	return nil
+}
+
+// ConfidentialUtxoToken spends all the unused outputs of the sender like UtxoToken. The amounts of the
+// confidential inputs and outputs are known by their commitments only, the plain amount v is the commitment
+// v*H without the blinding factor. The range proofs must show the outputs aren't negative and the sum of the
+// input commitments must be the sum of the output commitments and fee*H. The public fee goes to the reward
+// account and the taxes wallet
+func ConfidentialUtxoToken(sc *SmartContract, c *types.ConfidentialUTXO) error {
+	fromID := sc.TxSmart.KeyID
+	ecosystem := sc.TxSmart.EcosystemID
+	if ecosystem != consts.DefaultTokenEcosystem {
+		return fmt.Errorf("confidential transfer isn't supported in ecosystem %d", ecosystem)
+	}
+	if err := sc.checkFrozen(ecosystem, fromID); err != nil {
+		return err
+	}
+	txInputs := sqldb.GetUnusedOutputsMap(sqldb.KeyUTXO{Ecosystem: ecosystem, KeyId: fromID}, sc.OutputsMap)
+	if len(txInputs) == 0 {
+		return errOutputSpent(fromID, ecosystem)
+	}
+	inputs := make([][]byte, 0, len(txInputs))
+	for _, input := range txInputs {
+		if input.Confidential() {
+			inputs = append(inputs, input.UtxoCommitments)
+			continue
+		}
+		value, ok := new(big.Int).SetString(input.OutputValue, 10)
+		if !ok {
+			return fmt.Errorf("wrong value %s of output", input.OutputValue)
+		}
+		if value.Sign() == 0 {
+			continue
+		}
+		commitment, err := crypto.PedersenCommit(value, big.NewInt(0))
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, commitment)
+	}
+
+	fee, err := decimal.NewFromString(c.Fee)
+	if err != nil {
+		return err
+	}
+	minFee, err := confidentialFee(sc, len(txInputs))
+	if err != nil {
+		return err
+	}
+	if fee.LessThan(minFee) {
+		return fmt.Errorf("confidential transfer fee %s is less than %s", fee, minFee)
+	}
+
+	blockID := sc.BlockHeader.BlockId
+	var (
+		outputIndex int32
+		txOutputs   []sqldb.SpentInfo
+		outputs     [][]byte
+	)
+	for _, out := range c.Outputs {
+		info := sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: out.ToID, BlockId: blockID,
+			Ecosystem: ecosystem, Type: consts.UTXO_Type_Transfer}
+		outputIndex++
+		if len(out.Commitment) == 0 {
+			value, _ := new(big.Int).SetString(out.Value, 10)
+			commitment, err := crypto.PedersenCommit(value, big.NewInt(0))
+			if err != nil {
+				return err
+			}
+			info.OutputValue = out.Value
+			outputs = append(outputs, commitment)
+		} else {
+			if !crypto.VerifyRange(out.Commitment, out.RangeProof) {
+				return fmt.Errorf("wrong range proof of output %d", info.OutputIndex)
+			}
+			info.OutputValue, info.UtxoCommitments = "0", out.Commitment
+			outputs = append(outputs, out.Commitment)
+		}
+		txOutputs = append(txOutputs, info)
+	}
+
+	var fees [][]byte
+	if fee.GreaterThan(decimal.Zero) {
+		commitment, err := crypto.PedersenCommit(fee.BigInt(), big.NewInt(0))
+		if err != nil {
+			return err
+		}
+		fees = append(fees, commitment)
+		feeOutputs, err := confidentialFeeOutputs(sc, fee, outputIndex)
+		if err != nil {
+			return err
+		}
+		txOutputs = append(txOutputs, feeOutputs...)
+	}
+	if !crypto.VerifyPedersenBalance(inputs, outputs, fees) {
+		return ErrConfidentialBalance
+	}
+	sqldb.PutAllOutputsMap(txInputs, sc.TxInputsMap)
+	sqldb.PutAllOutputsMap(txOutputs, sc.TxOutputsMap)
+	return nil
+}
+
+// confidentialFee returns the least fee of the confidential transfer, it's computed like the fee of UtxoToken
+func confidentialFee(sc *SmartContract, inputs int) (decimal.Decimal, error) {
+	expediteFee, err := expediteFeeBy(sc.TxSmart.Expedite, consts.MoneyDigits)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	fuels, err := utxoParams(sc, syspar.FuelRate)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	ret, ok := fuels[consts.DefaultTokenEcosystem]
+	if !ok {
+		return expediteFee, nil
+	}
+	fuelRate, err := decimal.NewFromString(ret)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	//	ecosystem fuelRate /10 *( bit + len(input))
+	money := fuelRate.Div(decimal.NewFromInt(10)).Mul(decimal.NewFromInt(sc.TxSize).Add(decimal.NewFromInt(int64(inputs))))
+	return money.Add(expediteFee).Ceil(), nil
+}
+
+// confidentialFeeOutputs returns the outputs of the fee of the reward account and the taxes wallet
+func confidentialFeeOutputs(sc *SmartContract, fee decimal.Decimal, outputIndex int32) ([]sqldb.SpentInfo, error) {
+	rewardID, err := sc.rewardKeyID()
+	if err != nil {
+		return nil, err
+	}
+	wallets, err := utxoParams(sc, syspar.TaxesWallet)
+	if err != nil {
+		return nil, err
+	}
+	ecosystem := int64(consts.DefaultTokenEcosystem)
+	blockID := sc.BlockHeader.BlockId
+	taxes := fee.Mul(decimal.NewFromInt(syspar.SysInt64(syspar.TaxesSize))).Div(decimal.New(100, 0)).Floor()
+	taxesWallet, ok := wallets[ecosystem]
+	if !ok || taxes.LessThanOrEqual(decimal.Zero) {
+		return []sqldb.SpentInfo{{OutputIndex: outputIndex, OutputKeyId: rewardID, OutputValue: fee.String(),
+			BlockId: blockID, Ecosystem: ecosystem, Type: consts.UTXO_Type_Packaging}}, nil
+	}
+	return []sqldb.SpentInfo{
+		{OutputIndex: outputIndex, OutputKeyId: rewardID, OutputValue: fee.Sub(taxes).String(),
+			BlockId: blockID, Ecosystem: ecosystem, Type: consts.UTXO_Type_Packaging},
+		{OutputIndex: outputIndex + 1, OutputKeyId: converter.StrToInt64(taxesWallet), OutputValue: taxes.String(),
+			BlockId: blockID, Ecosystem: ecosystem, Type: consts.UTXO_Type_Taxes},
+	}, nil
+}
+
+// utxoParams returns the values of the platform parameter of the ecosystems
+func utxoParams(sc *SmartContract, name string) (map[int64]string, error) {
+	res := make(map[int64]string)
+	if len(sc.PrevSysPar[name]) == 0 {
+		return res, nil
+	}
+	items := make([][]string, 0)
+	if err := json.Unmarshal([]byte(sc.PrevSysPar[name]), &items); err != nil {
+		log.WithFields(log.Fields{"type": consts.JSONUnmarshallError, "error": err}).Error("unmarshalling params from json")
+		return res, err
+	}
+	for _, item := range items {
+		if len(item) < 2 {
+			continue
+		}
+		res[converter.StrToInt64(item[0])] = item[1]
+	}
+	return res, nil
+}
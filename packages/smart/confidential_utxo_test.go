@@ -0,0 +1,91 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestConfidentialUtxoToken(t *testing.T) {
+	const (
+		sender    = 5
+		recipient = 6
+		producer  = 7
+	)
+	blockKeys{}.install(t)
+	prev := rewardDestination
+	rewardDestination = func(sc *SmartContract, keyID, blockID int64) (int64, bool, error) { return 0, false, nil }
+	t.Cleanup(func() { rewardDestination = prev })
+
+	blinding := big.NewInt(12345)
+	hidden, err := crypto.PedersenCommit(big.NewInt(50), blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := sqldb.KeyUTXO{Ecosystem: consts.DefaultTokenEcosystem, KeyId: sender}
+	newContract := func() *SmartContract {
+		sc := newFreezeContract(consts.DefaultTokenEcosystem, sender)
+		sc.BlockHeader.KeyId = producer
+		sc.OutputsMap = map[sqldb.KeyUTXO][]sqldb.SpentInfo{key: {
+			{OutputKeyId: sender, OutputValue: "100", Ecosystem: consts.DefaultTokenEcosystem},
+			{OutputKeyId: sender, OutputValue: "0", Ecosystem: consts.DefaultTokenEcosystem, UtxoCommitments: hidden},
+		}}
+		sc.TxInputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		sc.TxOutputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		return sc
+	}
+	// 100 + 50 = 120 + 20 + 10, the blinding factor moves to the output
+	commitment, err := crypto.PedersenCommit(big.NewInt(120), blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := crypto.ProveRange(big.NewInt(120), blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer := func(fee string) *types.ConfidentialUTXO {
+		return &types.ConfidentialUTXO{Fee: fee, Outputs: []types.ConfidentialOutput{
+			{ToID: recipient, Commitment: commitment, RangeProof: proof},
+			{ToID: sender, Value: "20"},
+		}}
+	}
+
+	sc := newContract()
+	if err = ConfidentialUtxoToken(sc, transfer("10")); err != nil {
+		t.Fatal(err)
+	}
+	out := sc.TxOutputsMap[sqldb.KeyUTXO{Ecosystem: consts.DefaultTokenEcosystem, KeyId: recipient}]
+	if len(out) != 1 || out[0].OutputValue != "0" || !out[0].Confidential() {
+		t.Errorf("wrong confidential output %+v", out)
+	}
+	fee := sc.TxOutputsMap[sqldb.KeyUTXO{Ecosystem: consts.DefaultTokenEcosystem, KeyId: producer}]
+	if len(fee) != 1 || fee[0].OutputValue != "10" {
+		t.Errorf("wrong fee output %+v", fee)
+	}
+	if len(sc.TxInputsMap[key]) != 2 {
+		t.Errorf("inputs aren't spent %+v", sc.TxInputsMap)
+	}
+
+	if err = ConfidentialUtxoToken(newContract(), transfer("11")); !errors.Is(err, ErrConfidentialBalance) {
+		t.Errorf("expected unbalanced transfer, got %v", err)
+	}
+	broken := transfer("10")
+	broken.Outputs[0].RangeProof = append([]byte{}, proof...)
+	broken.Outputs[0].RangeProof[crypto.CommitmentLength+1] ^= 1
+	if err = ConfidentialUtxoToken(newContract(), broken); err == nil {
+		t.Error("wrong range proof is accepted")
+	}
+	if err = checkPlainOutputs(sqldb.GetUnusedOutputsMap(key, newContract().OutputsMap)); !errors.Is(err, ErrConfidentialOutputs) {
+		t.Errorf("confidential outputs are spent by the plain transfer, %v", err)
+	}
+}
@@ -71,7 +71,7 @@ func VMGetContract(vm *script.VM, name string, state uint32) *Contract {
 	name = script.StateName(state, name)
 	obj, ok := vm.Objects[name]
 
-	if ok && obj.Type == script.ObjectType_Contract {
+	if ok && obj.Type == script.ObjectType_Contract && !obj.GetCodeBlock().GetContractInfo().IsDeleted() {
 		return &Contract{Name: name, Block: obj.GetCodeBlock()}
 	}
 	return nil
@@ -93,6 +93,9 @@ func VMGetContractByID(vm *script.VM, id int32) *Contract {
 	if tableID > 0 && vm.Children[idcont].GetContractInfo().Owner.TableID != tableID {
 		return nil
 	}
+	if vm.Children[idcont].GetContractInfo().IsDeleted() {
+		return nil
+	}
 	return &Contract{Name: vm.Children[idcont].GetContractInfo().Name,
 		Block: vm.Children[idcont]}
 }
@@ -137,6 +140,7 @@ func loadContractList(list []sqldb.Contract) error {
 			TableID:  item.ID,
 			WalletID: item.WalletID,
 			TokenID:  item.TokenID,
+			Deleted:  item.Deleted != 0,
 		}
 		if err = script.GetVM().Compile([]rune(item.Value), &owner); err != nil {
 			logErrorValue(err, consts.EvalError, "Load Contract", strings.Join(clist, `,`))
@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+const (
+	// maxTxEvents is the max count of the events emitted by the transaction
+	maxTxEvents = 256
+	// maxEventName is the max length of the name of the event
+	maxEventName = 255
+)
+
+var errEventName = errors.New("wrong event name")
+
+// EmitEvent adds the event with data to the transaction. The events of the played transaction are
+// written to contract_events with the name of the emitting contract and they are discarded if the
+// transaction fails
+func EmitEvent(sc *SmartContract, name string, data *types.Map) error {
+	if len(name) == 0 || len(name) > maxEventName {
+		return logError(errEventName, consts.InvalidObject, "checking event name")
+	}
+	if len(sc.Events) >= maxTxEvents {
+		return logErrorf("too many events, limit is %d", maxTxEvents, consts.ParameterExceeded, "emitting event")
+	}
+	value := "{}"
+	if data != nil {
+		var err error
+		if value, err = JSONEncode(data); err != nil {
+			return err
+		}
+	}
+	sc.Events = append(sc.Events, &sqldb.ContractEvent{
+		BlockID:      sc.BlockHeader.BlockId,
+		TxHash:       sc.Hash,
+		LogIndex:     int64(len(sc.Events)),
+		EcosystemID:  sc.TxSmart.EcosystemID,
+		KeyID:        sc.TxSmart.KeyID,
+		ContractName: sc.eventContract(),
+		EventName:    name,
+		Data:         value,
+	})
+	return nil
+}
+
+// eventContract returns the name of the contract which is executed now
+func (sc *SmartContract) eventContract() string {
+	if stack := sc.TxContract.StackCont; len(stack) > 0 {
+		if name, ok := stack[len(stack)-1].(string); ok {
+			return name
+		}
+	}
+	return sc.TxContract.Name
+}
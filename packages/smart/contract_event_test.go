@@ -0,0 +1,43 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestEmitEvent(t *testing.T) {
+	sc := &SmartContract{
+		TxSmart:     &types.SmartTransaction{Header: &types.Header{EcosystemID: 2}},
+		BlockHeader: &types.BlockHeader{BlockId: 10},
+		TxContract:  &Contract{Name: "@2Outer", StackCont: []any{"@2Outer", "@2Inner"}},
+		Hash:        []byte{1, 2},
+	}
+	data := types.NewMap()
+	data.Set("amount", 5)
+	if err := EmitEvent(sc, "Transfer", data); err != nil {
+		t.Fatal(err)
+	}
+	if err := EmitEvent(sc, "Done", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := EmitEvent(sc, "", nil); err == nil {
+		t.Error("empty event name must be rejected")
+	}
+	if len(sc.Events) != 2 {
+		t.Fatalf("wrong events %v", sc.Events)
+	}
+	e := sc.Events[0]
+	if e.ContractName != "@2Inner" || e.EcosystemID != 2 || e.BlockID != 10 || e.EventName != "Transfer" ||
+		e.Data != `{"amount":5}` || e.LogIndex != 0 {
+		t.Errorf("wrong event %+v", e)
+	}
+	if sc.Events[1].Data != "{}" || sc.Events[1].LogIndex != 1 {
+		t.Errorf("wrong event %+v", sc.Events[1])
+	}
+}
@@ -0,0 +1,32 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestWithDelayedOrigin(t *testing.T) {
+	sc := &SmartContract{Hash: []byte{0xab, 0xcd}, BlockHeader: &types.BlockHeader{BlockId: 7}}
+	// the origin of the contract is replaced by the current transaction
+	params, val := sc.withDelayedOrigin([]string{"contract", "origin_tx_hash", "key_id", "origin_block_id"},
+		[]any{"@1Test", "forged", int64(5), int64(1)})
+	if !reflect.DeepEqual(params, []string{"contract", "key_id", "origin_tx_hash", "origin_block_id"}) ||
+		!reflect.DeepEqual(val, []any{"@1Test", int64(5), "abcd", int64(7)}) {
+		t.Errorf("wrong origin %v %v", params, val)
+	}
+
+	// the contract created outside of the block has no origin block
+	sc.BlockHeader = nil
+	params, val = sc.withDelayedOrigin([]string{"contract"}, []any{"@1Test"})
+	if !reflect.DeepEqual(params, []string{"contract", "origin_tx_hash", "origin_block_id"}) ||
+		!reflect.DeepEqual(val, []any{"@1Test", "abcd", int64(0)}) {
+		t.Errorf("wrong origin without block %v %v", params, val)
+	}
+}
@@ -0,0 +1,166 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runningDelayed returns the delayed contract which is run by the transaction. The delayed transaction
+// runs the contract itself and CallDelayedContract runs it by $Id. It's nil for the other contracts and
+// if the delayed contract can't be run at the block, then the transaction fails as before
+func (sc *SmartContract) runningDelayed() (*sqldb.DelayedContract, error) {
+	if sc.BlockHeader == nil {
+		return nil, nil
+	}
+	var (
+		delayed = &sqldb.DelayedContract{}
+		found   bool
+		err     error
+	)
+	switch {
+	case sc.Delayed:
+		found, err = delayed.GetByContractKey(sc.DbTransaction, sc.TxContract.Name, sc.TxSmart.KeyID)
+	case sc.TxContract.Name == CallDelayedContract:
+		id, errID := converter.ValueToInt(sc.TxData["Id"])
+		if errID != nil {
+			return nil, nil
+		}
+		found, err = delayed.GetAvailable(sc.DbTransaction, id)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, logErrorDB(err, "getting delayed contract")
+	}
+	if !found || sc.BlockHeader.BlockId < delayed.BlockID || (delayed.Limit > 0 && delayed.Counter >= delayed.Limit) {
+		return nil, nil
+	}
+	return delayed, nil
+}
+
+// completeDelayed records the outcome of the delayed contract for the key which has scheduled it,
+// notifies the key and calls the callback contract of the delayed contract. failure is the error of
+// the delayed contract, its changes have been discarded, so the schedule is advanced here as
+// CallDelayedContract does and the transaction isn't bad
+func (sc *SmartContract) completeDelayed(delayed *sqldb.DelayedContract, result string, failure error) error {
+	success := failure == nil
+	if !success {
+		result = failure.Error()
+		for i := len(sc.FlushRollback) - 1; i >= 0; i-- {
+			sc.FlushRollback[i].FlushVM()
+		}
+		sc.FlushRollback = nil
+		sc.TxInputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		sc.TxOutputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
+		counter, blockID := delayed.Counter+1, sc.BlockHeader.BlockId
+		if delayed.Limit == 0 || delayed.Limit > counter {
+			blockID += delayed.EveryBlock
+		}
+		if _, _, err := sc.updateWhere([]string{"counter", "block_id"}, []any{counter, blockID},
+			"1_delayed_contracts", types.LoadMap(map[string]any{"id": delayed.ID})); err != nil {
+			return err
+		}
+	}
+	callbackErr, err := sc.callDelayedCallback(delayed, success, result)
+	if err != nil {
+		return err
+	}
+	var status int64
+	if success {
+		status = 1
+	}
+	if _, _, err = sc.insert([]string{"delayed_id", "key_id", "contract", "block_id", "tx_hash", "success",
+		"result", "fuel", "callback", "callback_error"},
+		[]any{delayed.ID, delayed.KeyID, delayed.Contract, sc.BlockHeader.BlockId, sc.Hash, status,
+			result, sc.TxFuel, delayed.Callback, callbackErr}, "1_delayed_results"); err != nil {
+		return err
+	}
+	return sc.notifyDelayed(delayed, success, result)
+}
+
+// callDelayedCallback calls the callback contract with the outcome of the delayed contract. The callback gets
+// $DelayedId, $Contract, $Success, $Result and $Fuel, its failure is returned as callbackErr and its changes
+// are discarded without failing the delayed contract
+func (sc *SmartContract) callDelayedCallback(delayed *sqldb.DelayedContract, success bool, result string) (callbackErr string, err error) {
+	if len(delayed.Callback) == 0 {
+		return "", nil
+	}
+	contract := VMGetContract(sc.VM, delayed.Callback, uint32(consts.DefaultTokenEcosystem))
+	if contract == nil {
+		return fmt.Sprintf(eUnknownContract, delayed.Callback), nil
+	}
+	point := consts.SetSavePointMarkBlock(hex.EncodeToString(sc.Hash) + "-callback")
+	if err = sc.DbTransaction.Savepoint(point); err != nil {
+		return "", logErrorDB(err, "setting savepoint of delayed callback")
+	}
+	rollbacks, binLogs, audits, flushes := len(sc.RollBackTx), len(sc.DbTransaction.BinLogSql), len(sc.AuditLogs), len(sc.FlushRollback)
+	events := len(sc.Events)
+
+	extend := sc.getExtend()
+	_, name := converter.ParseName(contract.Name)
+	extend[script.Extend_original_contract] = name
+	extend[script.Extend_this_contract] = name
+	extend[script.Extend_contract] = contract
+	extend[script.Extend_txcost] = extend[script.Extend_txcost].(int64) - sc.TxFuel
+	extend["DelayedId"] = delayed.ID
+	extend["Contract"] = delayed.Contract
+	extend["Success"] = success
+	extend["Result"] = result
+	extend["Fuel"] = sc.TxFuel
+	before := extend[script.Extend_txcost].(int64)
+	errRun := script.RunContractByName(sc.VM, contract.Name, []string{`conditions`, `action`}, extend, sc.Hash)
+	if used := before - extend[script.Extend_txcost].(int64); used > 0 {
+		sc.TxFuel += used
+	}
+	if errRun == nil {
+		return "", nil
+	}
+	if err = sc.DbTransaction.RollbackSavepoint(point); err != nil {
+		return "", logErrorDB(err, "rolling back savepoint of delayed callback")
+	}
+	for i := len(sc.FlushRollback) - 1; i >= flushes; i-- {
+		sc.FlushRollback[i].FlushVM()
+	}
+	sc.RollBackTx, sc.AuditLogs, sc.FlushRollback = sc.RollBackTx[:rollbacks], sc.AuditLogs[:audits], sc.FlushRollback[:flushes]
+	sc.DbTransaction.BinLogSql = sc.DbTransaction.BinLogSql[:binLogs]
+	sc.Events = sc.Events[:events]
+	sc.GetLogger().WithFields(log.Fields{"type": consts.ContractError, "error": errRun, "contract": contract.Name}).Warning("delayed callback failed")
+	return errRun.Error(), nil
+}
+
+// notifyDelayed adds the notification of the outcome of the delayed contract for the key which has scheduled it
+func (sc *SmartContract) notifyDelayed(delayed *sqldb.DelayedContract, success bool, result string) error {
+	account := converter.AddressToString(delayed.KeyID)
+	header := fmt.Sprintf("Delayed contract %s has been completed", delayed.Contract)
+	if !success {
+		header = fmt.Sprintf("Delayed contract %s has failed", delayed.Contract)
+	}
+	recipient, _ := json.Marshal(map[string]string{"member_id": converter.Int64ToStr(delayed.KeyID), "account": account})
+	sender, _ := json.Marshal(map[string]string{"member_id": converter.Int64ToStr(sc.TxSmart.KeyID),
+		"account": converter.AddressToString(sc.TxSmart.KeyID)})
+	notification, _ := json.Marshal(map[string]any{"type": sqldb.NotificationTypeSingle, "header": header, "body": result})
+	if _, _, err := sc.insert([]string{"recipient", "sender", "notification", "page_params", "processing_info",
+		"date_created", "ecosystem"},
+		[]any{string(recipient), string(sender), string(notification), "{}", "{}", sc.BlockHeader.Timestamp,
+			consts.DefaultTokenEcosystem}, "1_notifications"); err != nil {
+		return err
+	}
+	if sc.Notifications != nil {
+		sc.Notifications.AddAccounts(consts.DefaultTokenEcosystem, account)
+	}
+	return nil
+}
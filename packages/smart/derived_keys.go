@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// ErrNotDerivedKey is returned when the key isn't the child of the extended public key
+var ErrNotDerivedKey = errors.New("key isn't derived from the parent key")
+
+// SetKeyParent registers the transaction key as the child with index of the extended public key parent,
+// which is the hex of the public key of the parent followed by its chain code. The child is checked by
+// crypto.DeriveChildKey, so only the non-hardened children are registered, and the parent must be a key
+// of the ecosystem of the transaction
+func SetKeyParent(sc *SmartContract, parent string, index int64) error {
+	if err := validateAccess(sc, "SetKeyParent"); err != nil {
+		return err
+	}
+	if index < 0 || index >= int64(crypto.HardenedKeyStart) {
+		return logErrorValue(crypto.ErrHardenedPublic, consts.InvalidObject, "checking derivation index", converter.Int64ToStr(index))
+	}
+	extended, err := hex.DecodeString(parent)
+	if err != nil {
+		return logError(err, consts.ConversionError, "decoding extended public key")
+	}
+	child, err := crypto.DeriveChildKey(extended, uint32(index))
+	if err != nil {
+		return logError(err, consts.CryptoError, "deriving child key")
+	}
+	if len(sc.PublicKeys) == 0 || !bytes.Equal(crypto.CutPub(sc.PublicKeys[0]), child[:consts.PubkeySizeLength]) {
+		return logError(ErrNotDerivedKey, consts.InvalidObject, "checking derived key")
+	}
+	ecosystem, keyID := sc.TxSmart.EcosystemID, sc.TxSmart.KeyID
+	parentID := crypto.Address(extended[:consts.PubkeySizeLength])
+	if parentID == keyID {
+		return logError(ErrNotDerivedKey, consts.InvalidObject, "checking parent key")
+	}
+	for _, id := range []int64{parentID, keyID} {
+		found, err := (&sqldb.Key{}).SetTablePrefix(ecosystem).Get(sc.DbTransaction, id)
+		if err != nil {
+			return logErrorDB(err, "getting key")
+		}
+		if !found {
+			return logError(fmt.Errorf(eEcoKeyNotFound, converter.AddressToString(id), ecosystem), consts.NotFound, "looking for keyid in ecosystem")
+		}
+	}
+	_, _, err = sc.updateWhere([]string{"parent_key_id", "derivation_index"}, []any{parentID, index}, "1_keys",
+		types.LoadMap(map[string]any{"id": keyID, "ecosystem": ecosystem}))
+	return err
+}
@@ -7,6 +7,8 @@ package smart
 import (
 	"errors"
 	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
 )
 
 const (
@@ -21,6 +23,8 @@ const (
 	eEmptyCond             = `%v condition is empty`
 	eIncorrectSignature    = `incorrect signature %s`
 	eItemNotFound          = `item %d has not been found`
+	eBlockNotFound         = `block %d has not been found`
+	eMerkleProofLen        = `merkle proof is too long. Limit is %d`
 	eManyColumns           = `Too many columns. Limit is %d`
 	eNotCondition          = `There is not %s in parameters`
 	eParamNotFound         = `Parameter %s has not been found`
@@ -85,3 +89,15 @@ var (
 
 	errMaxPrice = fmt.Errorf(`price value is more than %d`, MaxPrice)
 )
+
+// ErrOutputSpent is returned when the unused outputs of the key don't cover the UTXO transfer,
+// they have been spent by the previous transactions of the key
+var ErrOutputSpent = errors.New(`output already spent`)
+
+// ErrRollbackLimit is returned when the rollback entries of the transaction exceed max_tx_rollbacks
+var ErrRollbackLimit = errors.New(`rollback limit exceeded`)
+
+// errOutputSpent returns ErrOutputSpent about the outputs of the key in the ecosystem
+func errOutputSpent(keyID, ecosystem int64) error {
+	return fmt.Errorf("%w: "+eEcoCurrentBalance, ErrOutputSpent, converter.IDToAddress(keyID), ecosystem)
+}
@@ -0,0 +1,118 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrAccountFrozen is returned when the transaction spends the tokens of the frozen account
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// keyFrozen returns whether the key is frozen in the ecosystem. It reads the key within
+// the transaction of the block, so the freezing by the previous transactions of the block is seen
+var keyFrozen = func(sc *SmartContract, ecosystem, keyID int64) (bool, error) {
+	key := &sqldb.Key{}
+	found, err := key.SetTablePrefix(ecosystem).Get(sc.DbTransaction, keyID)
+	if err != nil {
+		return false, logErrorDB(err, "getting key")
+	}
+	return found && key.Frozen != 0, nil
+}
+
+// checkFrozen returns ErrAccountFrozen if the tokens of keyID can't be spent in the ecosystem
+func (sc *SmartContract) checkFrozen(ecosystem, keyID int64) error {
+	frozen, err := keyFrozen(sc, ecosystem, keyID)
+	if err != nil {
+		return err
+	}
+	if frozen {
+		err = fmt.Errorf("%w: %s in ecosystem %d", ErrAccountFrozen, converter.AddressToString(keyID), ecosystem)
+		sc.GetLogger().WithFields(log.Fields{"type": consts.AccessDenied, "error": err}).Error("spending from frozen account")
+		return err
+	}
+	return nil
+}
+
+// isDebit returns whether the update of the keys row decreases its amount
+func isDebit(fields []string, values []any, row map[string]string) bool {
+	for i, field := range fields {
+		switch field {
+		case "-amount":
+			return true
+		case "amount":
+			cur, err := decimal.NewFromString(row["amount"])
+			if err != nil {
+				return true
+			}
+			val, err := decimal.NewFromString(fmt.Sprint(values[i]))
+			if err != nil || val.LessThan(cur) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkFrozenDebit checks the rows of 1_keys before they are updated. The amount of the frozen
+// account can't be decreased while the deposits are allowed
+func (sc *SmartContract) checkFrozenDebit(fields []string, values []any, where *types.Map, rows []map[string]string) error {
+	ecosystem := sc.TxSmart.EcosystemID
+	if v, ok := where.Get("ecosystem"); ok {
+		ecosystem = converter.StrToInt64(fmt.Sprint(v))
+	}
+	for _, row := range rows {
+		if !isDebit(fields, values, row) {
+			continue
+		}
+		if err := sc.checkFrozen(ecosystem, converter.StrToInt64(row["id"])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetAccountFrozen freezes or unfreezes the account in the ecosystem of the transaction
+func SetAccountFrozen(sc *SmartContract, keyID int64, frozen bool) error {
+	if err := validateAccess(sc, "SetAccountFrozen"); err != nil {
+		return err
+	}
+	ecosystem := sc.TxSmart.EcosystemID
+	key := &sqldb.Key{}
+	found, err := key.SetTablePrefix(ecosystem).Get(sc.DbTransaction, keyID)
+	if err != nil {
+		return logErrorDB(err, "getting key")
+	}
+	if !found {
+		return logError(fmt.Errorf(eEcoKeyNotFound, converter.AddressToString(keyID), ecosystem), consts.NotFound, "looking for keyid in ecosystem")
+	}
+	var (
+		value  int64
+		action = sqldb.AuditAccountUnfreeze
+	)
+	if frozen {
+		value = 1
+		action = sqldb.AuditAccountFreeze
+	}
+	if key.Frozen == value {
+		return nil
+	}
+	if _, _, err = sc.updateWhere([]string{"frozen"}, []any{value}, "1_keys",
+		types.LoadMap(map[string]any{"id": keyID, "ecosystem": ecosystem})); err != nil {
+		return err
+	}
+	sc.AuditLogs = append(sc.AuditLogs, sqldb.NewAuditLog(action, sc.TxSmart.KeyID, sc.BlockHeader.BlockId,
+		sc.Hash, []byte(fmt.Sprintf("%d,%d", ecosystem, keyID))))
+	return nil
+}
@@ -0,0 +1,139 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// blockKeys emulates the keys of the block state which are read by the transactions in order
+type blockKeys map[[2]int64]bool
+
+func (k blockKeys) install(t *testing.T) {
+	prev := keyFrozen
+	keyFrozen = func(sc *SmartContract, ecosystem, keyID int64) (bool, error) {
+		return k[[2]int64{ecosystem, keyID}], nil
+	}
+	t.Cleanup(func() { keyFrozen = prev })
+}
+
+func newFreezeContract(ecosystem, keyID int64) *SmartContract {
+	return &SmartContract{
+		TxSmart: &types.SmartTransaction{
+			Header: &types.Header{EcosystemID: ecosystem, KeyID: keyID},
+		},
+		BlockHeader: &types.BlockHeader{BlockId: 10},
+	}
+}
+
+func TestIsDebit(t *testing.T) {
+	row := map[string]string{"id": "5", "amount": "100"}
+	cases := []struct {
+		fields []string
+		values []any
+		debit  bool
+	}{
+		{[]string{"-amount"}, []any{"1"}, true},
+		{[]string{"+amount"}, []any{"1"}, false},
+		{[]string{"amount"}, []any{"99"}, true},
+		{[]string{"amount"}, []any{"100"}, false},
+		{[]string{"amount"}, []any{"101"}, false},
+		{[]string{"amount"}, []any{"wrong"}, true},
+		{[]string{"pub", "maxpay"}, []any{"", "10"}, false},
+	}
+	for _, c := range cases {
+		if got := isDebit(c.fields, c.values, row); got != c.debit {
+			t.Errorf("%v %v: got %v", c.fields, c.values, got)
+		}
+	}
+}
+
+func TestFrozenAccountOrder(t *testing.T) {
+	const (
+		eco    = 2
+		owner  = 5
+		target = 6
+	)
+	rows := []map[string]string{{"id": "5", "amount": "100"}}
+	where := types.LoadMap(map[string]any{"id": owner, "ecosystem": eco})
+	spend := func(sc *SmartContract) error {
+		return sc.checkFrozenDebit([]string{"-amount"}, []any{"10"}, where, rows)
+	}
+
+	// the freezing is before the spending in the block
+	keys := blockKeys{}
+	keys.install(t)
+	keys[[2]int64{eco, owner}] = true
+	if err := spend(newFreezeContract(eco, owner)); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("spend after freeze: got %v", err)
+	}
+	deposit := newFreezeContract(eco, target).checkFrozenDebit([]string{"+amount"}, []any{"10"}, where, rows)
+	if deposit != nil {
+		t.Errorf("deposit to frozen account: %v", deposit)
+	}
+	// the account is frozen only in the ecosystem
+	other := types.LoadMap(map[string]any{"id": owner, "ecosystem": 1})
+	if err := newFreezeContract(1, owner).checkFrozenDebit([]string{"-amount"}, []any{"10"}, other, rows); err != nil {
+		t.Errorf("spend in other ecosystem: %v", err)
+	}
+
+	// the spending is before the freezing in the block
+	keys = blockKeys{}
+	keys.install(t)
+	if err := spend(newFreezeContract(eco, owner)); err != nil {
+		t.Errorf("spend before freeze: %v", err)
+	}
+	keys[[2]int64{eco, owner}] = true
+	if err := spend(newFreezeContract(eco, owner)); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("second spend after freeze: got %v", err)
+	}
+
+	// unfreezing
+	keys[[2]int64{eco, owner}] = false
+	if err := spend(newFreezeContract(eco, owner)); err != nil {
+		t.Errorf("spend after unfreeze: %v", err)
+	}
+}
+
+func TestFrozenAccountUTXO(t *testing.T) {
+	const (
+		eco   = 2
+		owner = 5
+	)
+	keys := blockKeys{}
+	keys.install(t)
+
+	// there are no inputs so the spending fails on the balance if the account isn't frozen
+	if _, err := UtxoToken(newFreezeContract(eco, owner), 6, "10"); err == nil || errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("utxo before freeze: got %v", err)
+	}
+	keys[[2]int64{eco, owner}] = true
+	if _, err := UtxoToken(newFreezeContract(eco, owner), 6, "10"); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("utxo after freeze: got %v", err)
+	}
+	for _, source := range []string{"UTXO", "Account"} {
+		target := map[string]string{"UTXO": "Account", "Account": "UTXO"}[source]
+		if _, err := TransferSelf(newFreezeContract(eco, owner), "10", source, target); !errors.Is(err, ErrAccountFrozen) {
+			t.Errorf("transfer self from %s: got %v", source, err)
+		}
+	}
+
+	// the fee of utxo transaction is paid in the default ecosystem
+	keys = blockKeys{{1, owner}: true}
+	keys.install(t)
+	if _, err := UtxoToken(newFreezeContract(eco, owner), 6, "10"); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("utxo with frozen fee account: got %v", err)
+	}
+
+	// the fee payment of the contract
+	pay := &PaymentInfo{TokenEco: 1, FromID: owner}
+	if err := pay.checkVerify(newFreezeContract(eco, owner)); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("fee payment: got %v", err)
+	}
+}
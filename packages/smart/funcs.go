@@ -147,6 +147,13 @@ var (
 		"DeleteCLB":             {},
 		"DelColumn":             {},
 		"DelTable":              {},
+		"SetObjectDeleted":      {},
+		"PurgeDeletedObjects":   {},
+		"SetTimeLock":           {},
+		"ReleaseTimeLock":       {},
+		"SnapshotBalances":      {},
+		"SetKeyAuthContract":    {},
+		"SetKeyParent":          {},
 	}
 	// map for table name to parameter with conditions
 	tableParamConditions = map[string]string{
@@ -286,6 +293,7 @@ func EmbedFuncs(vt script.VMType) map[string]any {
 		"Floor":                        Floor,
 		"CheckCondition":               CheckCondition,
 		"IsHonorNodeKey":               IsHonorNodeKey,
+		"HonorNodesCount":              HonorNodesCount,
 		"CheckSign":                    CheckSign,
 		"CheckNumberChars":             CheckNumberChars,
 		"DateFormat":                   Date,
@@ -300,6 +308,8 @@ func EmbedFuncs(vt script.VMType) map[string]any {
 		"GreaterThanOrEqual":           GreaterThanOrEqual,
 		"LessThan":                     LessThan,
 		"LessThanOrEqual":              LessThanOrEqual,
+		"VerifyMerkleProof":            VerifyMerkleProof,
+		"BundlePlan":                   BundlePlan,
 	}
 	switch vt {
 	case script.VMType_CLB:
@@ -321,6 +331,19 @@ func EmbedFuncs(vt script.VMType) map[string]any {
 		f["GetCLBList"] = GetCLBList
 	case script.VMType_Smart:
 		f["GetBlock"] = GetBlock
+		f["GetBlockHeader"] = GetBlockHeader
+		f["SetAccountFrozen"] = SetAccountFrozen
+		f["SetRewardDestination"] = SetRewardDestination
+		f["SetObjectDeleted"] = SetObjectDeleted
+		f["PurgeDeletedObjects"] = PurgeDeletedObjects
+		f["CheckTombstone"] = CheckTombstone
+		f["SetTimeLock"] = SetTimeLock
+		f["ReleaseTimeLock"] = ReleaseTimeLock
+		f["SnapshotBalances"] = SnapshotBalances
+		f["VoteWeight"] = VoteWeight
+		f["SetKeyAuthContract"] = SetKeyAuthContract
+		f["SetKeyParent"] = SetKeyParent
+		f["EmitEvent"] = EmitEvent
 	}
 	return f
 }
@@ -341,7 +364,22 @@ func CompileContract(sc *SmartContract, code string, state, id, token int64) (an
 	if err := validateAccess(sc, "CompileContract"); err != nil {
 		return nil, err
 	}
-	return sc.VM.CompileBlock([]rune(code), &script.OwnerInfo{StateID: uint32(state), WalletID: id, TokenID: token})
+	for _, f := range script.Analyze(code) {
+		if f.Severity == script.SeverityError {
+			// the compilation fails with the same error
+			continue
+		}
+		sc.GetLogger().WithFields(log.Fields{"type": consts.ContractError, "ecosystem": state, "severity": f.Severity,
+			"line": f.Line, "column": f.Column}).Warning(f.Message)
+	}
+	root, err := sc.VM.CompileBlock([]rune(code), &script.OwnerInfo{StateID: uint32(state), WalletID: id, TokenID: token})
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range LintContract(code) {
+		sc.GetLogger().WithFields(log.Fields{"type": consts.ContractError, "ecosystem": state}).Warning(warning)
+	}
+	return root, nil
 }
 
 // ContractAccess checks whether the name of the executable contract matches one of the names listed in the parameters.
@@ -478,6 +516,9 @@ func UpdateContract(sc *SmartContract, id int64, value, conditions string, recip
 	}
 	pars := make(map[string]any)
 	ecosystemID := sc.TxSmart.EcosystemID
+	if err := sc.checkDeletedContract(id, ecosystemID); err != nil {
+		return err
+	}
 	var root any
 	if len(value) > 0 {
 		var err error
@@ -521,6 +562,9 @@ func CreateContract(sc *SmartContract, name, value, conditions string, tokenEcos
 			"tableId": isExists}).Error("create existing contract")
 		return 0, fmt.Errorf(eContractExist, script.StateName(uint32(sc.TxSmart.EcosystemID), name))
 	}
+	if err = sc.checkTombstone("contracts", name, sc.TxSmart.EcosystemID); err != nil {
+		return 0, err
+	}
 	root, err := CompileContract(sc, value, sc.TxSmart.EcosystemID, 0, tokenEcosystem)
 	if err != nil {
 		return 0, err
@@ -911,6 +955,9 @@ func DBInsert(sc *SmartContract, tblname string, values *types.Map) (qcost int64
 	if reflect.TypeOf(val[0]) == reflect.TypeOf([]any{}) {
 		val = val[0].([]any)
 	}
+	if tblname == "1_delayed_contracts" {
+		params, val = sc.withDelayedOrigin(params, val)
+	}
 	qcost, lastID, err = sc.insert(params, val, tblname)
 	if ind > 0 {
 		qcost *= int64(ind)
@@ -921,6 +968,32 @@ func DBInsert(sc *SmartContract, tblname string, values *types.Map) (qcost int64
 	return
 }
 
+// withDelayedOrigin sets the hash and the block of the current transaction as the origin of the delayed
+// contract, the values of the contract are replaced so the origin can't be forged
+func (sc *SmartContract) withDelayedOrigin(params []string, val []any) ([]string, []any) {
+	origin := map[string]any{
+		"origin_tx_hash":  hex.EncodeToString(sc.Hash),
+		"origin_block_id": int64(0),
+	}
+	if sc.BlockHeader != nil {
+		origin["origin_block_id"] = sc.BlockHeader.BlockId
+	}
+	retParams := make([]string, 0, len(params)+len(origin))
+	retVal := make([]any, 0, len(val)+len(origin))
+	for i, name := range params {
+		if _, ok := origin[name]; ok || i >= len(val) {
+			continue
+		}
+		retParams = append(retParams, name)
+		retVal = append(retVal, val[i])
+	}
+	for _, name := range []string{"origin_tx_hash", "origin_block_id"} {
+		retParams = append(retParams, name)
+		retVal = append(retVal, origin[name])
+	}
+	return retParams, retVal
+}
+
 // PrepareColumns replaces jsonb fields -> in the list of columns for db selecting
 // For example, name,doc->title => name,doc::jsonb->>'title' as "doc.title"
 func PrepareColumns(columns []string) string {
@@ -942,6 +1015,28 @@ func PrepareColumns(columns []string) string {
 	return strings.Join(colList, `,`)
 }
 
+// checkOrderColumns returns the error if the column of the order doesn't exist in the table
+func (sc *SmartContract) checkOrderColumns(tblname string, inOrder any) error {
+	columns := qb.OrderColumns(inOrder)
+	if len(columns) == 0 {
+		return nil
+	}
+	list, err := sc.DbTransaction.GetAllColumnTypes(tblname)
+	if err != nil {
+		return logErrorDB(err, "getting table columns")
+	}
+	exist := make(map[string]bool, len(list))
+	for _, item := range list {
+		exist[item["column_name"]] = true
+	}
+	for _, column := range columns {
+		if !exist[column] {
+			return fmt.Errorf(eColumnNotExist, column)
+		}
+	}
+	return nil
+}
+
 // DBSelect returns an array of values of the specified columns when there is selection of data 'offset', 'limit', 'where'
 func DBSelect(sc *SmartContract, tblname string, inColumns any, id int64, inOrder any,
 	offset, limit int64, inWhere *types.Map, query any, group string, all bool) (int64, []any, error) {
@@ -979,6 +1074,12 @@ func DBSelect(sc *SmartContract, tblname string, inColumns any, id int64, inOrde
 	if err = sc.AccessColumns(tblname, &columns, false); err != nil {
 		return 0, nil, err
 	}
+	// the order of the grouped query can use the aliases of the aggregates
+	if len(group) == 0 && query == "" {
+		if err = sc.checkOrderColumns(tblname, inOrder); err != nil {
+			return 0, nil, err
+		}
+	}
 	q := sqldb.GetDB(sc.DbTransaction).Table(tblname).Select(PrepareColumns(columns)).Where(where)
 
 	//group + order => false
@@ -2449,6 +2550,11 @@ func SendExternalTransaction(sc *SmartContract, uid, url, externalContract strin
 	return sqldb.GetDB(sc.DbTransaction).Exec(insertQuery).Error
 }
 
+// HonorNodesCount returns the number of nodes which produce blocks
+func HonorNodesCount(sc *SmartContract) int64 {
+	return syspar.GetNumberOfNodesFromDB(sc.DbTransaction)
+}
+
 func IsHonorNodeKey(id int64) bool {
 	if syspar.IsCandidateNodeMode() {
 		return true
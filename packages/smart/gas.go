@@ -206,6 +206,9 @@ func (pay *PaymentInfo) DetailCombustion() any {
 
 func (f *PaymentInfo) checkVerify(sc *SmartContract) error {
 	eco := f.TokenEco
+	if err := sc.checkFrozen(eco, f.FromID); err != nil {
+		return err
+	}
 	if err := sc.hasExistKeyID(eco, f.FromID); err != nil {
 		return errors.Wrapf(err, fmt.Sprintf("From ID %d does not exist", f.ToID))
 	}
@@ -482,7 +485,6 @@ func (sc *SmartContract) getChangeAddress(eco int64) ([]*PaymentInfo, error) {
 		feeMode     *sqldb.FeeModeInfo
 		curPay      = &PaymentInfo{
 			TokenEco:       eco,
-			ToID:           sc.BlockHeader.KeyId,
 			PayWallet:      new(sqldb.Key),
 			Ecosystem:      new(sqldb.Ecosystem),
 			Combustion:     new(Combustion),
@@ -490,6 +492,9 @@ func (sc *SmartContract) getChangeAddress(eco int64) ([]*PaymentInfo, error) {
 			TaxesSize:      syspar.SysInt64(syspar.TaxesSize),
 		}
 	)
+	if curPay.ToID, err = sc.rewardKeyID(); err != nil {
+		return nil, err
+	}
 	if _, err = curPay.Ecosystem.Get(sc.DbTransaction, curPay.TokenEco); err != nil {
 		return nil, err
 	}
@@ -508,6 +513,7 @@ func (sc *SmartContract) getChangeAddress(eco int64) ([]*PaymentInfo, error) {
 	if curPay.FuelRate, err = sc.fuelRate(curPay.TokenEco, f2); err != nil {
 		return nil, err
 	}
+	curPay.FuelRate = sc.baseFuelRate(curPay.FuelRate)
 
 	if curPay.TaxesID, err = sc.taxesWallet(curPay.TokenEco); err != nil {
 		return nil, err
@@ -740,6 +746,15 @@ func (sc *SmartContract) fuelRate(eco int64, followFuel float64) (decimal.Decima
 	return fuelRate, nil
 }
 
+// baseFuelRate returns the fuel rate of the base gas price of the block, BaseGasPriceUnit keeps the
+// fuel rate of the ecosystem
+func (sc *SmartContract) baseFuelRate(fuelRate decimal.Decimal) decimal.Decimal {
+	if sc.BlockHeader == nil || sc.BlockHeader.BaseGasPrice == 0 {
+		return fuelRate
+	}
+	return fuelRate.Mul(decimal.NewFromInt(sc.BlockHeader.BaseGasPrice)).Div(decimal.NewFromInt(syspar.BaseGasPriceUnit))
+}
+
 func (sc *SmartContract) taxesWallet(eco int64) (taxesID int64, err error) {
 	if _, ok := syspar.HasTaxesWallet(eco); !ok {
 		var taxesPub []byte
@@ -0,0 +1,98 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const dbFindCall = "DBFind("
+
+// LintContract returns the warnings of the source of the contract. It warns about the table reads with
+// Limit and without Order, the rows of such reads are taken in the order of the primary key
+func LintContract(code string) []string {
+	var warnings []string
+	for off := 0; ; {
+		i := strings.Index(code[off:], dbFindCall)
+		if i < 0 {
+			break
+		}
+		start := off + i
+		off = start + len(dbFindCall)
+		if start > 0 && isIdentRune(rune(code[start-1])) {
+			continue
+		}
+		tails, end := readTails(code, off-1)
+		off = end
+		if tails["Limit"] && !tails["Order"] {
+			warnings = append(warnings, fmt.Sprintf("line %d: DBFind is used with Limit without Order, the rows are ordered by id",
+				strings.Count(code[:start], "\n")+1))
+		}
+	}
+	return warnings
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// readTails returns the names of the tail functions of the call, the call starts with the bracket at off
+func readTails(code string, off int) (map[string]bool, int) {
+	tails := make(map[string]bool)
+	for {
+		if off = skipBrackets(code, off); off < 0 {
+			return tails, len(code)
+		}
+		next := off
+		for next < len(code) && unicode.IsSpace(rune(code[next])) {
+			next++
+		}
+		if next >= len(code) || code[next] != '.' {
+			return tails, off
+		}
+		next++
+		ident := next
+		for next < len(code) && isIdentRune(rune(code[next])) {
+			next++
+		}
+		if next >= len(code) || code[next] != '(' {
+			return tails, next
+		}
+		tails[code[ident:next]] = true
+		off = next
+	}
+}
+
+// skipBrackets returns the position after the closing bracket of the bracket at off,
+// the brackets inside of the strings are skipped
+func skipBrackets(code string, off int) int {
+	var (
+		depth int
+		quote byte
+	)
+	for i := off; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case quote != 0:
+			if ch == '\\' && quote == '"' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth--; depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
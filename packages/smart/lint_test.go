@@ -0,0 +1,28 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintContract(t *testing.T) {
+	warnings := LintContract(`contract Lint {
+	action {
+		var first, ordered, all array
+		first = DBFind("@1keys").Where({"amount": {"$gt": "0"}}).Limit(1)
+		ordered = DBFind("@1keys").Columns("id").
+			Limit(1).Order({"amount": -1})
+		all = DBFind("@1keys").Where({"pub": ")"})
+		MyDBFind("x").Limit(1)
+		$result = DBFind("@1pages").Columns("name").Limit(5).One("name") + "Limit("
+	}
+}`)
+	if len(warnings) != 2 || !strings.HasPrefix(warnings[0], "line 4:") || !strings.HasPrefix(warnings[1], "line 9:") {
+		t.Errorf("wrong warnings %v", warnings)
+	}
+}
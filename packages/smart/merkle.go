@@ -0,0 +1,102 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package smart
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+const (
+	// maxMerkleProofLen is enough for the tree with 2^64 leaves
+	maxMerkleProofLen = 64
+
+	merkleProofLeft  = "L:"
+	merkleProofRight = "R:"
+)
+
+// parseMerkleHash checks that value is a hex string of hash
+func parseMerkleHash(value string) ([]byte, error) {
+	if len(value) != consts.HashSize*2 {
+		return nil, errInvalidValue
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return nil, errInvalidValue
+	}
+	return []byte(strings.ToLower(value)), nil
+}
+
+// VerifyMerkleProof checks the proof that leaf is included in the tree with root.
+// The leaf is the hex of double hash of transaction data and every item of the proof
+// is the sibling hash prefixed with "L:" or "R:" depending on its side
+func VerifyMerkleProof(root, leaf string, proof []any) (bool, error) {
+	rootHash, err := parseMerkleHash(root)
+	if err != nil {
+		return false, logErrorValue(err, consts.InvalidObject, "merkle root", root)
+	}
+	leafHash, err := parseMerkleHash(leaf)
+	if err != nil {
+		return false, logErrorValue(err, consts.InvalidObject, "merkle leaf", leaf)
+	}
+	if len(proof) > maxMerkleProofLen {
+		return false, logErrorf(eMerkleProofLen, maxMerkleProofLen, consts.ParameterExceeded, "merkle proof")
+	}
+	items := make([]types.MerkleProofItem, 0, len(proof))
+	for _, v := range proof {
+		s, ok := v.(string)
+		if !ok {
+			return false, logErrorfShort(eUnsupportedType, v, consts.TypeError)
+		}
+		var item types.MerkleProofItem
+		switch {
+		case strings.HasPrefix(s, merkleProofLeft):
+			item.Left = true
+		case strings.HasPrefix(s, merkleProofRight):
+		default:
+			return false, logErrorValue(errInvalidValue, consts.InvalidObject, "merkle proof item", s)
+		}
+		if item.Hash, err = parseMerkleHash(s[len(merkleProofLeft):]); err != nil {
+			return false, logErrorValue(err, consts.InvalidObject, "merkle proof item", s)
+		}
+		items = append(items, item)
+	}
+	return types.VerifyMerkleProof(rootHash, leafHash, items), nil
+}
+
+// GetBlockHeader returns the header fields of the committed block
+func GetBlockHeader(sc *SmartContract, blockID int64) (*types.Map, error) {
+	if blockID < 1 || (sc.BlockHeader != nil && blockID >= sc.BlockHeader.BlockId) {
+		return nil, logErrorfShort(eBlockNotFound, blockID, consts.NotFound)
+	}
+	block := sqldb.BlockChain{}
+	found, err := block.Get(blockID)
+	if err != nil {
+		return nil, logErrorDB(err, "getting block")
+	}
+	if !found {
+		return nil, logErrorfShort(eBlockNotFound, blockID, consts.NotFound)
+	}
+	data := &types.BlockData{}
+	if err = data.UnmarshallBlock(block.Data); err != nil {
+		return nil, logError(err, consts.UnmarshallingError, "unmarshalling block")
+	}
+
+	return types.LoadMap(map[string]any{
+		"id":            block.ID,
+		"hash":          hex.EncodeToString(block.Hash),
+		"prev_hash":     hex.EncodeToString(data.GetPrevHeader().GetBlockHash()),
+		"merkle_root":   string(data.MerkleRoot),
+		"timestamp":     block.Time,
+		"key_id":        block.KeyID,
+		"producer":      converter.AddressToString(block.KeyID),
+		"node_position": block.NodePosition,
+		"tx_count":      int64(block.Tx),
+	}), nil
+}
@@ -0,0 +1,100 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// contractProof returns the proof in the form of the argument of VerifyMerkleProof
+func contractProof(items []types.MerkleProofItem) []any {
+	proof := make([]any, 0, len(items))
+	for _, item := range items {
+		side := merkleProofRight
+		if item.Left {
+			side = merkleProofLeft
+		}
+		proof = append(proof, side+string(item.Hash))
+	}
+	return proof
+}
+
+func TestVerifyMerkleProof(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	block := &types.BlockData{}
+	for i := 0; i < 5; i++ {
+		block.TxFullData = append(block.TxFullData, []byte(fmt.Sprintf("tx %d", i)))
+	}
+	leaves := block.LeafHashes()
+	root := string(block.GenMerkleRoot())
+	for i, leaf := range leaves {
+		proof := contractProof(types.MerkleTreeProof(leaves, i))
+		if ok, err := VerifyMerkleProof(root, string(leaf), proof); err != nil || !ok {
+			t.Fatalf("proof of %d isn't valid: %v", i, err)
+		}
+		// the hashes are compared in lower case
+		if ok, err := VerifyMerkleProof(strings.ToUpper(root), strings.ToUpper(string(leaf)), proof); err != nil || !ok {
+			t.Errorf("proof of %d in upper case isn't valid: %v", i, err)
+		}
+		if ok, err := VerifyMerkleProof(root, string(leaves[(i+1)%len(leaves)]), proof); err != nil || ok {
+			t.Errorf("proof of %d is valid for the other leaf: %v", i, err)
+		}
+	}
+
+	// the sides of the siblings are taken from the prefixes
+	proof := contractProof(types.MerkleTreeProof(leaves, 1))
+	swapped := make([]any, len(proof))
+	for i, item := range proof {
+		s := item.(string)
+		side := merkleProofLeft
+		if strings.HasPrefix(s, merkleProofLeft) {
+			side = merkleProofRight
+		}
+		swapped[i] = side + s[len(side):]
+	}
+	if ok, err := VerifyMerkleProof(root, string(leaves[1]), swapped); err != nil || ok {
+		t.Errorf("proof with the swapped sides is valid: %v", err)
+	}
+
+	hash := string(leaves[0])
+	for _, item := range []any{
+		hash,
+		"l:" + hash,
+		"X:" + hash,
+		"L:",
+		"R:" + hash[1:],
+		"R:" + hash[:len(hash)-1] + "z",
+		"R:" + hash + "00",
+		int64(1),
+	} {
+		if _, err := VerifyMerkleProof(root, hash, []any{item}); err == nil {
+			t.Errorf("proof item %v is accepted", item)
+		}
+	}
+	if _, err := VerifyMerkleProof(root[1:], hash, nil); err == nil {
+		t.Error("short root is accepted")
+	}
+	if _, err := VerifyMerkleProof(root, "leaf", nil); err == nil {
+		t.Error("wrong leaf is accepted")
+	}
+
+	long := make([]any, maxMerkleProofLen)
+	for i := range long {
+		long[i] = merkleProofRight + hash
+	}
+	if ok, err := VerifyMerkleProof(root, hash, long); err != nil || ok {
+		t.Errorf("proof of %d items: %v %v", maxMerkleProofLen, ok, err)
+	}
+	if _, err := VerifyMerkleProof(root, hash, append(long, merkleProofRight+hash)); err == nil ||
+		!strings.Contains(err.Error(), fmt.Sprintf(eMerkleProofLen, maxMerkleProofLen)) {
+		t.Errorf("proof over the limit: %v", err)
+	}
+}
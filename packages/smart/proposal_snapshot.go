@@ -0,0 +1,61 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/shopspring/decimal"
+)
+
+// SnapshotBalances records the balances of the keys of the ecosystem at the current block, the
+// proposal keeps the returned block id and the votes are weighted by VoteWeight. The tokens acquired
+// after the proposal has been created don't change the weight of the vote
+func SnapshotBalances(sc *SmartContract) (int64, error) {
+	if err := validateAccess(sc, "SnapshotBalances"); err != nil {
+		return 0, err
+	}
+	ecosystem, blockID := sc.TxSmart.EcosystemID, sc.BlockHeader.BlockId
+	found, err := sqldb.HasProposalSnapshot(sc.DbTransaction, ecosystem, blockID)
+	if err != nil {
+		return 0, logErrorDB(err, "getting proposal snapshot")
+	}
+	// the proposals of the same block share the snapshot
+	if found {
+		return blockID, nil
+	}
+	if !sc.CLB {
+		if err = SysRollback(sc, SysRollData{Type: "ProposalSnapshot", EcosystemID: ecosystem, ID: blockID}); err != nil {
+			return 0, err
+		}
+	}
+	if err = sqldb.CreateProposalSnapshot(sc.DbTransaction, ecosystem, blockID); err != nil {
+		return 0, logErrorDB(err, "creating proposal snapshot")
+	}
+	return blockID, nil
+}
+
+// VoteWeight returns the balance of the key at the snapshot of the proposal, the key which
+// hasn't had the tokens at the snapshot has zero weight
+func VoteWeight(sc *SmartContract, snapshotBlockID, keyID int64) (decimal.Decimal, error) {
+	ecosystem := sc.TxSmart.EcosystemID
+	snapshot := &sqldb.ProposalSnapshot{}
+	found, err := snapshot.Get(sc.DbTransaction, ecosystem, snapshotBlockID, keyID)
+	if err != nil {
+		return decimal.Zero, logErrorDB(err, "getting proposal snapshot")
+	}
+	if !found {
+		return decimal.Zero, nil
+	}
+	return snapshot.Amount, nil
+}
+
+// SysRollbackProposalSnapshot removes the balances recorded by the rolled back block
+func SysRollbackProposalSnapshot(dbTx *sqldb.DbTransaction, sysData SysRollData) error {
+	if err := sqldb.DeleteProposalSnapshot(dbTx, sysData.EcosystemID, sysData.ID); err != nil {
+		return logErrorDB(err, "deleting proposal snapshot")
+	}
+	return nil
+}
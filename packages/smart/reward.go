@@ -0,0 +1,61 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// errRewardDestination is returned when the destination of the rewards is the wrong account
+var errRewardDestination = errors.New("wrong reward destination")
+
+// rewardDestination returns the registered destination of the rewards of the producer key for the block.
+// It reads the registrations within the transaction of the block, so the registration applies
+// to the following transactions of its own block
+var rewardDestination = func(sc *SmartContract, keyID, blockID int64) (int64, bool, error) {
+	destination, found, err := sqldb.GetRewardDestination(sc.DbTransaction, keyID, blockID)
+	if err != nil {
+		return 0, false, logErrorDB(err, "getting reward destination")
+	}
+	return destination, found, nil
+}
+
+// rewardKeyID returns the account which receives the fees of the block. It's the key which has
+// signed the block unless the key has registered the other destination on-chain. The destination
+// in the node config isn't used here, the blocks are played in the same way by all nodes
+func (sc *SmartContract) rewardKeyID() (int64, error) {
+	producer := sc.BlockHeader.KeyId
+	destination, found, err := rewardDestination(sc, producer, sc.BlockHeader.BlockId)
+	if err != nil {
+		return 0, err
+	}
+	if !found || destination == 0 {
+		return producer, nil
+	}
+	return destination, nil
+}
+
+// SetRewardDestination registers the account which receives the rewards of the blocks signed by the key
+// of the transaction. It applies starting from the block of the transaction
+func SetRewardDestination(sc *SmartContract, destination int64) error {
+	if err := validateAccess(sc, "SetRewardDestination"); err != nil {
+		return err
+	}
+	if destination == 0 {
+		return logError(errRewardDestination, consts.InvalidObject, "checking reward destination")
+	}
+	if _, _, err := sc.insert([]string{"key_id", "destination", "block_id"},
+		[]any{sc.TxSmart.KeyID, destination, sc.BlockHeader.BlockId}, "1_reward_destinations"); err != nil {
+		return err
+	}
+	sc.AuditLogs = append(sc.AuditLogs, sqldb.NewAuditLog(sqldb.AuditRewardDestination, sc.TxSmart.KeyID, sc.BlockHeader.BlockId,
+		sc.Hash, []byte(fmt.Sprintf("%d,%d", sc.TxSmart.KeyID, destination))))
+	return nil
+}
@@ -0,0 +1,103 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// rewardRegistrations emulates 1_reward_destinations, the rows are appended in the order of the blocks
+type rewardRegistrations []struct{ keyID, destination, blockID int64 }
+
+func (r *rewardRegistrations) install(t *testing.T) {
+	prev := rewardDestination
+	rewardDestination = func(sc *SmartContract, keyID, blockID int64) (destination int64, found bool, err error) {
+		for _, row := range *r {
+			if row.keyID == keyID && row.blockID <= blockID {
+				destination, found = row.destination, true
+			}
+		}
+		return
+	}
+	t.Cleanup(func() { rewardDestination = prev })
+}
+
+func (r *rewardRegistrations) register(keyID, destination, blockID int64) {
+	*r = append(*r, struct{ keyID, destination, blockID int64 }{keyID, destination, blockID})
+}
+
+func newRewardContract(producer, blockID int64) *SmartContract {
+	return &SmartContract{
+		TxSmart:     &types.SmartTransaction{Header: &types.Header{EcosystemID: 1}},
+		BlockHeader: &types.BlockHeader{BlockId: blockID, KeyId: producer},
+	}
+}
+
+func TestRewardDestination(t *testing.T) {
+	const (
+		producer = 100
+		cold     = 200
+		other    = 300
+		fee      = 10
+	)
+	regs := &rewardRegistrations{}
+	regs.install(t)
+
+	// the destination in the config alone doesn't redirect the rewards
+	prevAddress := conf.Config.RewardAddress
+	conf.Config.RewardAddress = converter.AddressToString(other)
+	t.Cleanup(func() { conf.Config.RewardAddress = prevAddress })
+
+	rewards := map[int64]int64{}
+	produce := func(blockID int64) {
+		t.Helper()
+		to, err := newRewardContract(producer, blockID).rewardKeyID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rewards[to] += fee
+	}
+
+	for block := int64(1); block <= 3; block++ {
+		produce(block)
+	}
+	if rewards[producer] != 3*fee || rewards[other] != 0 {
+		t.Fatalf("before registration: %v", rewards)
+	}
+
+	// the registration applies from its block
+	regs.register(producer, cold, 4)
+	for block := int64(4); block <= 6; block++ {
+		produce(block)
+	}
+	if rewards[producer] != 3*fee || rewards[cold] != 3*fee || rewards[other] != 0 {
+		t.Fatalf("after registration: %v", rewards)
+	}
+
+	// the earlier blocks are settled with the old destination when they are played again
+	if to, _ := newRewardContract(producer, 3).rewardKeyID(); to != producer {
+		t.Errorf("block 3 is settled to %d", to)
+	}
+
+	// the destination is changed again
+	regs.register(producer, producer, 8)
+	for block := int64(7); block <= 8; block++ {
+		produce(block)
+	}
+	if rewards[producer] != 4*fee || rewards[cold] != 4*fee {
+		t.Fatalf("after change back: %v", rewards)
+	}
+
+	// the registration by the other key doesn't apply to the producer
+	regs.register(other, other, 9)
+	if to, _ := newRewardContract(producer, 9).rewardKeyID(); to != producer {
+		t.Errorf("producer rewards are redirected by the other key to %d", to)
+	}
+}
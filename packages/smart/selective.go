@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb/querycost"
@@ -31,6 +32,20 @@ func addRollback(sc *SmartContract, table, tableID, rollbackInfoStr, rollDataHas
 	sc.RollBackTx = append(sc.RollBackTx, rollbackTx)
 }
 
+// checkRollbackLimit returns ErrRollbackLimit if the count more rollback entries exceed max_tx_rollbacks,
+// it's checked before the statement, so the transaction fails before the writes
+func (sc *SmartContract) checkRollbackLimit(count int) error {
+	var blockID int64
+	if sc.BlockHeader != nil {
+		blockID = sc.BlockHeader.BlockId
+	}
+	limit := syspar.GetMaxTxRollbacksAt(blockID)
+	if total := int64(len(sc.RollBackTx) + count); total > limit {
+		return fmt.Errorf("%w: %d entries over %d, split the writes into several transactions", ErrRollbackLimit, total, limit)
+	}
+	return nil
+}
+
 func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []any,
 	table string, inWhere *types.Map, generalRollback bool, exists bool) (int64, string, error) {
 
@@ -86,6 +101,11 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []any,
 				"error": errWhereUpdate}).Error("update without where")
 			return 0, "", errWhereUpdate
 		}
+		if sqlBuilder.Table == "1_keys" {
+			if err = sc.checkFrozenDebit(fields, ivalues, sqlBuilder.Where, rows.List); err != nil {
+				return 0, "", err
+			}
+		}
 	}
 	var rollDataHashStr string
 
@@ -110,6 +130,11 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []any,
 			}
 			cost += updateCost
 		}
+		if generalRollback {
+			if err = sc.checkRollbackLimit(len(rows.List)); err != nil {
+				return 0, "", err
+			}
+		}
 		rollDataHashStr = `UPDATE "` + strings.Trim(sqlBuilder.Table, `"`) + `" SET ` + updateExpr + " " + whereExpr
 		err = sc.DbTransaction.Update(sqlBuilder.Table, updateExpr, whereExpr)
 		if err != nil {
@@ -141,6 +166,9 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []any,
 		return cost, sqlBuilder.TableID(), nil
 	}
 
+	if err := sc.checkTombstoneInsert(strings.Trim(table, `"`), fields, ivalues); err != nil {
+		return 0, "", err
+	}
 	insertQuery, err := sqlBuilder.GetSQLInsertQuery(sqldb.NextIDGetter{Tx: sc.DbTransaction})
 	if err != nil {
 		logger.WithError(err).Error("on build insert query")
@@ -154,6 +182,11 @@ func (sc *SmartContract) selectiveLoggingAndUpd(fields []string, ivalues []any,
 		}
 		rollDataHashStr = insertQuery
 	cost += insertCost
+	if generalRollback {
+		if err = sc.checkRollbackLimit(1); err != nil {
+			return 0, "", err
+		}
+	}
 	err = sc.DbTransaction.ExecSql(insertQuery)
 	if err != nil {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err, "query": insertQuery}).Error("executing insert query")
@@ -6,6 +6,7 @@
 package smart
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -40,6 +41,10 @@ const (
 	CallDelayedContract = "@1CallDelayedContract"
 	NewUserContract     = "@1NewUser"
 	NewBadBlockContract = "@1NewBadBlock"
+
+	OracleCommitContract  = "@1OracleCommit"
+	OracleRevealContract  = "@1OracleReveal"
+	OracleResolveContract = "@1OracleResolve"
 )
 
 var (
@@ -47,6 +52,10 @@ var (
 		CallDelayedContract: true,
 		NewUserContract:     true,
 		NewBadBlockContract: true,
+
+		OracleCommitContract:  true,
+		OracleRevealContract:  true,
+		OracleResolveContract: true,
 	}
 )
 
@@ -63,6 +72,7 @@ type SmartContract struct {
 	TxFuel          int64           // The fuel of executing contract
 	TxCost          int64           // Maximum cost of executing contract
 	TxUsedCost      decimal.Decimal // Used cost of CPU resources
+	TxMemPeak       int64           // The peak memory of the run times of the contract
 	TXBlockFuel     decimal.Decimal
 	BlockHeader     *types.BlockHeader
 	PreBlockHeader  *types.BlockHeader
@@ -91,6 +101,15 @@ type SmartContract struct {
 	TxOutputsMap    map[sqldb.KeyUTXO][]sqldb.SpentInfo
 	PrevSysPar      map[string]string
 	EcoParams       []sqldb.EcoParam
+	AuditLogs       []*sqldb.AuditLog
+	Events          []*sqldb.ContractEvent
+	Calls           []script.ContractCall
+	Logger          *log.Entry      // the logger of the block, nil outside of the block
+	Delayed         bool            // the contract is executed by the delayed transaction
+	TxType          int             // the type of the transaction in ClassifyTxsMap of the block, zero outside of the block
+	TraceCalls      bool            // the nested calls of the contracts are recorded in Calls
+	Ctx             context.Context // cancels the contract, nil outside of the block
+	authFuel        int64           // the fuel of the auth contract of the abstract account transaction
 }
 
 // AppendStack adds an element to the stack of contract call or removes the top element when name is empty
@@ -118,6 +137,25 @@ func (sc *SmartContract) PopStack(fn string) {
 	}
 }
 
+// RecordMem keeps the largest peak memory of the run times of the contract
+func (sc *SmartContract) RecordMem(peak int64) {
+	if peak > sc.TxMemPeak {
+		sc.TxMemPeak = peak
+	}
+}
+
+// RecordCall records the nested call of the contract if TraceCalls is set
+func (sc *SmartContract) RecordCall(name string, fuel int64, err error) {
+	if !sc.TraceCalls {
+		return
+	}
+	depth := 1
+	if sc.TxContract != nil {
+		depth += len(sc.TxContract.StackCont)
+	}
+	sc.Calls = append(sc.Calls, script.ContractCall{Name: name, Depth: depth, Fuel: fuel, Err: err})
+}
+
 func (sc *SmartContract) isAllowStack(fn string) bool {
 	// Stack contains only contracts
 	c := VMGetContract(sc.VM, fn, uint32(sc.TxSmart.EcosystemID))
@@ -151,7 +189,11 @@ func (sc *SmartContract) GetLogger() *log.Entry {
 	if sc.TxContract != nil {
 		name = sc.TxContract.Name
 	}
-	return log.WithFields(log.Fields{"tx": fmt.Sprintf("%x", sc.Hash), "clb": sc.CLB, "name": name, "tx_eco": sc.TxSmart.EcosystemID})
+	fields := log.Fields{"tx": fmt.Sprintf("%x", sc.Hash), "clb": sc.CLB, "name": name, "tx_eco": sc.TxSmart.EcosystemID}
+	if sc.Logger != nil {
+		return sc.Logger.WithFields(fields)
+	}
+	return log.WithFields(fields)
 }
 
 func GetAllContracts() (string, error) {
@@ -234,6 +276,10 @@ func (sc *SmartContract) getExtend() map[string]any {
 		script.Extend_pre_block_data_hash: perBlockHash,
 		script.Extend_gen_block:           sc.GenBlock,
 		script.Extend_time_limit:          sc.TimeLimit,
+		script.Extend_tx_type:             sc.classifiedTxType(),
+		script.Extend_block_id:            block,
+		script.Extend_tx_hash:             hex.EncodeToString(sc.Hash),
+		script.Extend_ctx:                 sc.Ctx,
 	}
 	for key, val := range sc.TxData {
 		extend[key] = val
@@ -242,6 +288,15 @@ func (sc *SmartContract) getExtend() map[string]any {
 	return extend
 }
 
+// classifiedTxType returns the type of the transaction in ClassifyTxsMap. The contract which is
+// called outside of the block is classified as the contract transaction
+func (sc *SmartContract) classifiedTxType() int {
+	if sc.TxType == 0 {
+		return types.SmartContractTxType
+	}
+	return sc.TxType
+}
+
 func PrefixName(table string) (prefix, name string) {
 	name = table
 	if off := strings.IndexByte(table, '_'); off > 0 && table[0] >= '0' && table[0] <= '9' {
@@ -343,7 +398,7 @@ func (sc *SmartContract) AccessColumns(table string, columns *[]string, update b
 			if sc.TxSmart.KeyID == converter.StrToInt64(EcosysParam(sc, `founder_account`)) {
 				return nil
 			}
-			log.WithFields(log.Fields{"txSmart.KeyId": sc.TxSmart.KeyID}).Error("ACCESS DENIED")
+			sc.GetLogger().WithFields(log.Fields{"txSmart.KeyId": sc.TxSmart.KeyID}).Error("ACCESS DENIED")
 			return errAccessDenied
 		}
 		return nil
@@ -546,6 +601,12 @@ func (sc *SmartContract) GetSignedBy(public []byte) (int64, error) {
 	return signedBy, nil
 }
 
+// canceled returns true if the contract is stopped by the time limit or by the context of the transaction,
+// err of the nested contracts isn't always wrapped, so the context is checked too
+func (sc *SmartContract) canceled(err error) bool {
+	return script.IsCanceled(err) || sc.Ctx != nil && sc.Ctx.Err() != nil
+}
+
 // CallContract calls the contract functions according to the specified flags
 func (sc *SmartContract) CallContract(point string) (string, error) {
 	var (
@@ -556,7 +617,7 @@ func (sc *SmartContract) CallContract(point string) (string, error) {
 
 	retError := func(err error) (string, error) {
 		eText := err.Error()
-		if !strings.HasPrefix(eText, `{`) && err != script.ErrVMTimeLimit {
+		if !strings.HasPrefix(eText, `{`) && !sc.canceled(err) {
 			err = script.SetVMError(`panic`, eText)
 		}
 		return ``, err
@@ -565,6 +626,10 @@ func (sc *SmartContract) CallContract(point string) (string, error) {
 	if err = sc.checkTxSign(); err != nil {
 		return ``, err
 	}
+	delayed, err := sc.runningDelayed()
+	if err != nil {
+		return ``, err
+	}
 
 	needPayment := sc.needPayment()
 	if needPayment {
@@ -585,7 +650,7 @@ func (sc *SmartContract) CallContract(point string) (string, error) {
 	ctrctExtend := sc.TxContract.Extend
 	before := ctrctExtend[script.Extend_txcost].(int64)
 	txSizeFuel := syspar.GetSizeFuel() * sc.TxSize / 1024
-	ctrctExtend[script.Extend_txcost] = ctrctExtend[script.Extend_txcost].(int64) - txSizeFuel
+	ctrctExtend[script.Extend_txcost] = ctrctExtend[script.Extend_txcost].(int64) - txSizeFuel - sc.authFuel
 
 	_, nameContract := converter.ParseName(sc.TxContract.Name)
 	ctrctExtend[script.Extend_original_contract] = nameContract
@@ -610,14 +675,35 @@ func (sc *SmartContract) CallContract(point string) (string, error) {
 				result = result[:255] + `...`
 			}
 		}
+		if err == nil && delayed != nil {
+			err = sc.completeDelayed(delayed, result, nil)
+			sc.TxUsedCost = decimal.New(sc.TxFuel, 0)
+		}
 	}
 lp:
 	if err != nil {
+		if len(point) == 0 {
+			// the transaction of the savepoint batch is played again with its own savepoint
+			return retError(err)
+		}
 		sc.RollBackTx = nil
+		sc.AuditLogs = nil
+		sc.Events = nil
 		sc.DbTransaction.BinLogSql = nil
 		if errReset := sc.DbTransaction.ResetSavepoint(point); errReset != nil {
 			return retError(errors.Wrap(err, errReset.Error()))
 		}
+		if delayed != nil && !sc.canceled(err) {
+			// the failure of the delayed contract is recorded, so the transaction isn't bad. The time limit
+			// applies to the generator only, such transaction stays bad and is excluded from the block
+			if errDelayed := sc.completeDelayed(delayed, "", err); errDelayed != nil {
+				return retError(errors.Wrap(err, errDelayed.Error()))
+			}
+			sc.TxUsedCost = decimal.New(sc.TxFuel, 0)
+			if !needPayment {
+				return err.Error(), nil
+			}
+		}
 		if needPayment {
 			if errPay := sc.payContract(true); errPay != nil {
 				sc.RollBackTx = nil
@@ -632,6 +718,16 @@ lp:
 		return retError(err)
 	}
 
+	if sc.TxSmart.DryRun {
+		// the state changes of the dry run are discarded, the fee is charged for the used fuel
+		sc.RollBackTx = nil
+		sc.AuditLogs = nil
+		sc.Events = nil
+		sc.DbTransaction.BinLogSql = nil
+		if errReset := sc.DbTransaction.ResetSavepoint(point); errReset != nil {
+			return retError(errReset)
+		}
+	}
 	if needPayment {
 		if errPay := sc.payContract(false); errPay != nil {
 			err = errPay
@@ -643,7 +739,7 @@ lp:
 
 func (sc *SmartContract) checkTxSign() error {
 	var public []byte
-	if len(sc.TxSmart.PublicKey) > 0 && string(sc.TxSmart.PublicKey) != `null` {
+	if len(sc.TxSmart.PublicKey) > 0 && string(sc.TxSmart.PublicKey) != `null` && !sc.TxSmart.AbstractAccount {
 		public = sc.TxSmart.PublicKey
 	}
 	signedBy, err := sc.GetSignedBy(public)
@@ -667,6 +763,9 @@ func (sc *SmartContract) checkTxSign() error {
 		sc.GetLogger().WithFields(log.Fields{"type": consts.ContractError, "error": err}).Error("disable keyid")
 		return err
 	}
+	if sc.TxSmart.AbstractAccount {
+		return sc.checkAuthContract()
+	}
 	if len(sc.Key.PublicKey) > 0 {
 		public = sc.Key.PublicKey
 	}
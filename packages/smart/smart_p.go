@@ -118,7 +118,8 @@ func UpdatePlatformParam(sc *SmartContract, name, value, conditions string) (int
 		case syspar.TaxesSize,
 			syspar.PriceCreateRate,
 			syspar.PriceTxSize,
-			syspar.BlockReward:
+			syspar.BlockReward,
+			syspar.MaxBaseGasPrice:
 			ok = ival >= 0
 		case syspar.MaxBlockSize,
 			syspar.MaxTxSize,
@@ -128,7 +129,11 @@ func UpdatePlatformParam(sc *SmartContract, name, value, conditions string) (int
 			syspar.MaxBlockUserTx,
 			syspar.MaxTxFuel,
 			syspar.MaxBlockFuel,
-			syspar.MaxForsignSize:
+			syspar.MaxBlockWeight,
+			syspar.MinBaseGasPrice,
+			syspar.MaxForsignSize,
+			syspar.MaxTxParams,
+			syspar.MaxTxParamSize:
 			ok = ival > 0
 		case syspar.FuelRate,
 			syspar.TaxesWallet:
@@ -154,6 +159,14 @@ func UpdatePlatformParam(sc *SmartContract, name, value, conditions string) (int
 				}
 			}
 			checked = len(fnodes) > 0
+		case syspar.ConsensusSchedule:
+			// the schedule is changed only with the consensus parameters
+			break check
+		case syspar.RegisteredTxTypes:
+			if _, err := syspar.ParseTxTypes(value); err != nil {
+				return 0, logErrorValue(err, consts.InvalidObject, err.Error(), value)
+			}
+			checked = true
 		default:
 			if strings.HasPrefix(name, `extend_cost_`) || strings.HasSuffix(name, `_price`) {
 				ok = ival >= 0
@@ -165,6 +178,11 @@ func UpdatePlatformParam(sc *SmartContract, name, value, conditions string) (int
 			return 0, logErrorValue(errInvalidValue, consts.InvalidObject, errInvalidValue.Error(),
 				value)
 		}
+		if syspar.IsConsensusParam(name) && value != par.Value {
+			if err = sc.scheduleActivation(name, par.Value, value); err != nil {
+				return 0, err
+			}
+		}
 		fields = append(fields, "value")
 		values = append(values, value)
 	}
@@ -190,6 +208,30 @@ func UpdatePlatformParam(sc *SmartContract, name, value, conditions string) (int
 	return 0, nil
 }
 
+// scheduleActivation records in the schedule that the new value of the consensus parameter
+// becomes effective syspar.ActivationDelay blocks after the current block
+func (sc *SmartContract) scheduleActivation(name, prev, value string) error {
+	par := &sqldb.PlatformParameter{}
+	found, err := par.Get(sc.DbTransaction, syspar.ConsensusSchedule)
+	if err != nil {
+		return logErrorDB(err, "system parameter get")
+	}
+	if !found {
+		return logErrorf(eParamNotFound, syspar.ConsensusSchedule, consts.NotFound, "system parameter get")
+	}
+	schedule, err := syspar.ParseSchedule(par.Value)
+	if err != nil {
+		return logErrorValue(err, consts.JSONUnmarshallError, "unmarshalling consensus schedule", par.Value)
+	}
+	schedule.Activate(name, prev, value, sc.BlockHeader.BlockId)
+	data, err := schedule.Marshal()
+	if err != nil {
+		return logError(err, consts.JSONMarshallError, "marshalling consensus schedule")
+	}
+	_, _, err = sc.update([]string{"value"}, []any{data}, "1_platform_parameters", "id", par.ID)
+	return err
+}
+
 // SysParamString returns the value of the system parameter
 func SysParamString(name string) string {
 	return syspar.SysString(name)
@@ -717,6 +759,9 @@ func TransferSelf(sc *SmartContract, value string, source string, target string)
 	blockId := sc.BlockHeader.BlockId
 	//dbTx := sc.DbTransaction
 	keyUTXO := sqldb.KeyUTXO{Ecosystem: ecosystem, KeyId: fromID}
+	if err = sc.checkFrozen(ecosystem, fromID); err != nil {
+		return false, err
+	}
 	//sum, _ := decimal.NewFromString(value)
 	payValue, _ := decimal.NewFromString(value)
 	status := pbgo.TxInvokeStatusCode_SUCCESS
@@ -727,7 +772,10 @@ func TransferSelf(sc *SmartContract, value string, source string, target string)
 		txInputs := sqldb.GetUnusedOutputsMap(keyUTXO, outputsMap)
 
 		if len(txInputs) == 0 {
-			return false, fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(fromID), ecosystem)
+			return false, errOutputSpent(fromID, ecosystem)
+		}
+		if err = checkPlainOutputs(txInputs); err != nil {
+			return false, err
 		}
 
 		totalAmount := decimal.Zero
@@ -747,7 +795,7 @@ func TransferSelf(sc *SmartContract, value string, source string, target string)
 				return false, err
 			}
 		} else {
-			return false, fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(fromID), ecosystem)
+			return false, errOutputSpent(fromID, ecosystem)
 		}
 
 		// The change
@@ -883,9 +931,27 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 	//dbTx := sc.DbTransaction
 	keyUTXO := sqldb.KeyUTXO{Ecosystem: ecosystem, KeyId: fromID}
 
+	if err = sc.checkFrozen(ecosystem, fromID); err != nil {
+		return false, err
+	}
+	// the fee is paid by the inputs of the default ecosystem
+	if ecosystem != consts.DefaultTokenEcosystem {
+		if err = sc.checkFrozen(consts.DefaultTokenEcosystem, fromID); err != nil {
+			return false, err
+		}
+	}
+
 	txInputs := sqldb.GetUnusedOutputsMap(keyUTXO, outputsMap)
 	if len(txInputs) == 0 {
-		return false, fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(fromID), ecosystem)
+		return false, errOutputSpent(fromID, ecosystem)
+	}
+	if err = checkPlainOutputs(txInputs); err != nil {
+		return false, err
+	}
+
+	rewardID, err := sc.rewardKeyID()
+	if err != nil {
+		return false, err
 	}
 
 	if expediteFee, err = expediteFeeBy(sc.TxSmart.Expedite, consts.MoneyDigits); err != nil {
@@ -914,7 +980,10 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 			keyUTXO1 := sqldb.KeyUTXO{Ecosystem: ecosystem1, KeyId: fromID}
 			txInputs1 := sqldb.GetUnusedOutputsMap(keyUTXO1, outputsMap)
 			if len(txInputs1) == 0 {
-				return false, fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(fromID), ecosystem1)
+				return false, errOutputSpent(fromID, ecosystem1)
+			}
+			if err = checkPlainOutputs(txInputs1); err != nil {
+				return false, err
 			}
 			totalAmount1 := decimal.Zero
 
@@ -948,7 +1017,7 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 
 					flag = true
 					// 97%
-					txOutputs1 = append(txOutputs1, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: sc.BlockHeader.KeyId, OutputValue: money1.Sub(taxes1).String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Packaging})
+					txOutputs1 = append(txOutputs1, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: rewardID, OutputValue: money1.Sub(taxes1).String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Packaging})
 					outputIndex++
 					// 3%
 					txOutputs1 = append(txOutputs1, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: taxesID, OutputValue: taxes1.String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Taxes})
@@ -1006,7 +1075,7 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 
 					flag = true
 					// 97%
-					txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: sc.BlockHeader.KeyId, OutputValue: money2.Sub(taxes2).String(), BlockId: blockId, Ecosystem: ecosystem2, Type: consts.UTXO_Type_Packaging})
+					txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: rewardID, OutputValue: money2.Sub(taxes2).String(), BlockId: blockId, Ecosystem: ecosystem2, Type: consts.UTXO_Type_Packaging})
 					outputIndex++
 					// 3%
 					txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: taxesID, OutputValue: taxes2.String(), BlockId: blockId, Ecosystem: ecosystem2, Type: consts.UTXO_Type_Taxes})
@@ -1047,7 +1116,7 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 
 				flag = true
 				// 97%
-				txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: sc.BlockHeader.KeyId, OutputValue: money1.Sub(taxes1).String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Packaging})
+				txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: rewardID, OutputValue: money1.Sub(taxes1).String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Packaging})
 				outputIndex++
 				// 3%
 				txOutputs = append(txOutputs, sqldb.SpentInfo{OutputIndex: outputIndex, OutputKeyId: taxesID, OutputValue: taxes1.String(), BlockId: blockId, Ecosystem: ecosystem1, Type: consts.UTXO_Type_Taxes})
@@ -1067,7 +1136,7 @@ func UtxoToken(sc *SmartContract, toID int64, value string) (flag bool, err erro
 		totalAmount = totalAmount.Sub(payValue)
 	} else {
 		flag = false
-		err = fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(fromID), ecosystem)
+		err = errOutputSpent(fromID, ecosystem)
 	}
 
 	// The change
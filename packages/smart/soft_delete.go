@@ -0,0 +1,254 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+// purgedContract is the deleted column of the purged contract. The row of the contract isn't removed
+// because the ids of the contracts in the VM follow the rows of 1_contracts
+const purgedContract = -1
+
+var (
+	// ErrObjectDeleted is returned when the name of the soft-deleted object is reused
+	ErrObjectDeleted = errors.New("object is deleted")
+
+	// softDeleteTables are the application objects which can be soft-deleted
+	softDeleteTables = map[string]string{
+		"contracts": "contract",
+		"pages":     "page",
+		"snippets":  "snippet",
+		"menu":      "menu",
+	}
+	// undeletableContracts are needed to restore the objects
+	undeletableContracts = map[string]bool{"DeleteObject": true, "RestoreObject": true}
+)
+
+func softDeleteKind(table string) (string, error) {
+	kind, ok := softDeleteTables[strings.TrimPrefix(strings.ToLower(table), "@1")]
+	if !ok {
+		return "", fmt.Errorf("table %s doesn't support soft delete", table)
+	}
+	return kind, nil
+}
+
+// tombstoneError returns ErrObjectDeleted with the soft-deleted row
+func tombstoneError(table, name string, ecosystem, id, deleted int64) error {
+	kind := softDeleteTables[table]
+	if deleted == purgedContract {
+		return fmt.Errorf("%w: %s %s of ecosystem %d has been purged, its name can't be reused", ErrObjectDeleted, kind, name, ecosystem)
+	}
+	return fmt.Errorf("%w: %s %s of ecosystem %d is soft-deleted (tombstone %s id %d), restore it with @1RestoreObject",
+		ErrObjectDeleted, kind, name, ecosystem, table, id)
+}
+
+// tombstone returns the id and the deleted column of the soft-deleted object with the name,
+// the id is 0 if there isn't such object
+var tombstone = func(sc *SmartContract, table, name string, ecosystem int64) (id, deleted int64, err error) {
+	row, err := sc.DbTransaction.GetOneRowTransaction(
+		fmt.Sprintf(`SELECT id, deleted FROM "1_%s" WHERE ecosystem = ? AND name = ? AND deleted != 0`, table),
+		ecosystem, name).Int64()
+	if err != nil {
+		return 0, 0, logErrorDB(err, "getting soft-deleted object")
+	}
+	return row["id"], row["deleted"], nil
+}
+
+// checkTombstone returns ErrObjectDeleted if the object with the name is soft-deleted in the ecosystem
+func (sc *SmartContract) checkTombstone(table, name string, ecosystem int64) error {
+	id, deleted, err := tombstone(sc, table, name, ecosystem)
+	if err != nil || id == 0 {
+		return err
+	}
+	return tombstoneError(table, name, ecosystem, id, deleted)
+}
+
+// checkTombstoneInsert checks the name of the application object which is inserted
+func (sc *SmartContract) checkTombstoneInsert(table string, fields []string, values []any) error {
+	table = strings.TrimPrefix(table, "1_")
+	if _, ok := softDeleteTables[table]; !ok {
+		return nil
+	}
+	var name string
+	ecosystem := sc.TxSmart.EcosystemID
+	for i, field := range fields {
+		switch field {
+		case "name":
+			name = fmt.Sprint(values[i])
+		case "ecosystem":
+			ecosystem = converter.StrToInt64(fmt.Sprint(values[i]))
+		}
+	}
+	if len(name) == 0 {
+		return nil
+	}
+	return sc.checkTombstone(table, name, ecosystem)
+}
+
+// checkDeletedContract returns ErrObjectDeleted if the contract has been soft-deleted
+func (sc *SmartContract) checkDeletedContract(id, ecosystem int64) error {
+	row, err := sc.DbTransaction.GetOneRowTransaction(
+		`SELECT name, deleted FROM "1_contracts" WHERE id = ? AND ecosystem = ? AND deleted != 0`, id, ecosystem).String()
+	if err != nil {
+		return logErrorDB(err, "getting soft-deleted contract")
+	}
+	if len(row) == 0 {
+		return nil
+	}
+	return tombstoneError("contracts", row["name"], ecosystem, id, converter.StrToInt64(row["deleted"]))
+}
+
+// CheckTombstone returns the error if the application object with the name is soft-deleted in the
+// ecosystem of the transaction, so the contracts creating the objects point at the tombstone
+func CheckTombstone(sc *SmartContract, table, name string) error {
+	if _, err := softDeleteKind(table); err != nil {
+		return err
+	}
+	return sc.checkTombstone(strings.TrimPrefix(strings.ToLower(table), "@1"), name, sc.TxSmart.EcosystemID)
+}
+
+// SetObjectDeleted soft-deletes or restores the application object of the ecosystem of the transaction.
+// The row is kept, the deleted column is the block time of the delete
+func SetObjectDeleted(sc *SmartContract, table string, id int64, deleted bool) error {
+	if err := validateAccess(sc, "SetObjectDeleted"); err != nil {
+		return err
+	}
+	kind, err := softDeleteKind(table)
+	if err != nil {
+		return err
+	}
+	table = strings.TrimPrefix(strings.ToLower(table), "@1")
+	ecosystem := sc.TxSmart.EcosystemID
+	row, err := sc.DbTransaction.GetOneRowTransaction(
+		fmt.Sprintf(`SELECT name, deleted FROM "1_%s" WHERE id = ? AND ecosystem = ?`, table), id, ecosystem).String()
+	if err != nil {
+		return logErrorDB(err, "getting application object")
+	}
+	if len(row) == 0 {
+		return logErrorfShort(eRecordNotFound, id, consts.NotFound)
+	}
+	cur := converter.StrToInt64(row["deleted"])
+	switch {
+	case cur == purgedContract:
+		return tombstoneError(table, row["name"], ecosystem, id, cur)
+	case deleted && cur != 0:
+		return fmt.Errorf("%s %s has already been deleted", kind, row["name"])
+	case !deleted && cur == 0:
+		return fmt.Errorf("%s %s isn't deleted", kind, row["name"])
+	case deleted && table == "contracts" && ecosystem == consts.DefaultTokenEcosystem && undeletableContracts[row["name"]]:
+		return fmt.Errorf("contract %s can't be deleted", row["name"])
+	}
+
+	var value int64
+	if deleted {
+		value = sc.BlockHeader.Timestamp
+	}
+	if table == "contracts" {
+		if !sc.CLB {
+			rollType := "DeleteContract"
+			if !deleted {
+				rollType = "RestoreContract"
+			}
+			if err = SysRollback(sc, SysRollData{Type: rollType, ID: id, EcosystemID: ecosystem}); err != nil {
+				return err
+			}
+		}
+		setContractDeleted(id, ecosystem, deleted)
+	}
+	_, _, err = sc.updateWhere([]string{"deleted"}, []any{value}, "1_"+table,
+		types.LoadMap(map[string]any{"id": id, "ecosystem": ecosystem}))
+	return err
+}
+
+// setContractDeleted marks the contract in the VM, the soft-deleted contract can't be called
+func setContractDeleted(tblid, state int64, deleted bool) {
+	for i, item := range script.GetVM().CodeBlock.Children {
+		if item != nil && item.Type == script.ObjectType_Contract {
+			cinfo := item.GetContractInfo()
+			if cinfo.Owner.TableID == tblid && cinfo.Owner.StateID == uint32(state) {
+				script.GetVM().Children[i].GetContractInfo().Owner.Deleted = deleted
+			}
+		}
+	}
+}
+
+// PurgeDeletedObjects permanently removes the application objects which have been soft-deleted
+// longer than the deleted_objects_retention seconds, it returns the count of the purged objects
+func PurgeDeletedObjects(sc *SmartContract) (int64, error) {
+	if err := validateAccess(sc, "PurgeDeletedObjects"); err != nil {
+		return 0, err
+	}
+	retention := syspar.GetDeletedObjectsRetention()
+	if retention <= 0 {
+		return 0, nil
+	}
+	before := sc.BlockHeader.Timestamp - retention
+	var count int64
+	for _, table := range []string{"contracts", "menu", "pages", "snippets"} {
+		rows, err := sc.DbTransaction.GetAllTransaction(fmt.Sprintf(`SELECT id, ecosystem, to_jsonb(t)::text AS data
+			FROM "1_%s" AS t WHERE deleted > 0 AND deleted <= ? ORDER BY ecosystem, id`, table), -1, before)
+		if err != nil {
+			return count, logErrorDB(err, "getting deleted objects")
+		}
+		for _, row := range rows {
+			id, ecosystem := converter.StrToInt64(row["id"]), converter.StrToInt64(row["ecosystem"])
+			if table == "contracts" {
+				// the contract stays in the VM as deleted, only its row is marked
+				if _, _, err = sc.updateWhere([]string{"deleted"}, []any{purgedContract}, "1_contracts",
+					types.LoadMap(map[string]any{"id": id, "ecosystem": ecosystem})); err != nil {
+					return count, err
+				}
+				count++
+				continue
+			}
+			if !sc.CLB {
+				if err = SysRollback(sc, SysRollData{Type: "PurgeObject", TableName: "1_" + table, Data: row["data"]}); err != nil {
+					return count, err
+				}
+			}
+			if err = sc.DbTransaction.Delete("1_"+table, fmt.Sprintf(`WHERE id = '%d' AND ecosystem = '%d'`, id, ecosystem)); err != nil {
+				return count, logErrorDB(err, "purging deleted object")
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SysRollbackDeleteContract restores the contract in the VM when the delete is rolled back
+func SysRollbackDeleteContract(sysData SysRollData) error {
+	setContractDeleted(sysData.ID, sysData.EcosystemID, false)
+	return nil
+}
+
+// SysRollbackRestoreContract deletes the contract in the VM when the restore is rolled back
+func SysRollbackRestoreContract(sysData SysRollData) error {
+	setContractDeleted(sysData.ID, sysData.EcosystemID, true)
+	return nil
+}
+
+// SysRollbackPurgeObject inserts the purged row again
+func SysRollbackPurgeObject(dbTx *sqldb.DbTransaction, sysData SysRollData) error {
+	if _, ok := softDeleteTables[strings.TrimPrefix(sysData.TableName, "1_")]; !ok {
+		return fmt.Errorf("table %s doesn't support soft delete", sysData.TableName)
+	}
+	err := dbTx.ExecSql(fmt.Sprintf(`INSERT INTO "%[1]s" SELECT * FROM jsonb_populate_record(NULL::"%[1]s", '%[2]s'::jsonb)`,
+		sysData.TableName, strings.ReplaceAll(sysData.Data, `'`, `''`)))
+	if err != nil {
+		return logErrorDB(err, "restoring purged object")
+	}
+	return nil
+}
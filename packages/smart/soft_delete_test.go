@@ -0,0 +1,94 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestSoftDeletedContract(t *testing.T) {
+	const tableID = 901
+	InitVM()
+	owner := script.OwnerInfo{StateID: 1, TableID: tableID}
+	if err := script.GetVM().Compile([]rune(`contract SoftDeleted {
+		action {
+			$result = "done"
+		}
+	}`), &owner); err != nil {
+		t.Fatal(err)
+	}
+	run := func() error {
+		return script.RunContractByName(script.GetVM(), "@1SoftDeleted", []string{"action"},
+			map[string]any{script.Extend_txcost: int64(100000)}, nil)
+	}
+	if GetContract("SoftDeleted", 1) == nil {
+		t.Fatal("contract hasn't been compiled")
+	}
+	if err := run(); err != nil {
+		t.Fatal(err)
+	}
+
+	setContractDeleted(tableID, 1, true)
+	if GetContract("SoftDeleted", 1) != nil {
+		t.Error("deleted contract is resolved")
+	}
+	if err := run(); err == nil || !strings.Contains(err.Error(), "unknown contract") {
+		t.Errorf("calling deleted contract: got %v", err)
+	}
+	// the contract of the other ecosystem isn't deleted
+	setContractDeleted(tableID, 2, false)
+	if GetContract("SoftDeleted", 1) != nil {
+		t.Error("contract is restored in the other ecosystem")
+	}
+
+	setContractDeleted(tableID, 1, false)
+	if GetContract("SoftDeleted", 1) == nil {
+		t.Error("restored contract isn't resolved")
+	}
+	if err := run(); err != nil {
+		t.Errorf("calling restored contract: %v", err)
+	}
+}
+
+func TestTombstoneNameCollision(t *testing.T) {
+	prev := tombstone
+	tombstone = func(sc *SmartContract, table, name string, ecosystem int64) (int64, int64, error) {
+		switch {
+		case table == "pages" && name == "home" && ecosystem == 2:
+			return 7, 1650000000, nil
+		case table == "contracts" && name == "Old" && ecosystem == 2:
+			return 12, purgedContract, nil
+		}
+		return 0, 0, nil
+	}
+	t.Cleanup(func() { tombstone = prev })
+
+	sc := &SmartContract{TxSmart: &types.SmartTransaction{Header: &types.Header{EcosystemID: 2}}}
+	err := sc.checkTombstoneInsert("1_pages", []string{"name", "value"}, []any{"home", ""})
+	if !errors.Is(err, ErrObjectDeleted) || !strings.Contains(err.Error(), "tombstone pages id 7") {
+		t.Errorf("reusing name of deleted page: got %v", err)
+	}
+	if err = sc.checkTombstoneInsert("1_pages", []string{"name", "ecosystem"}, []any{"home", 3}); err != nil {
+		t.Errorf("name in other ecosystem: %v", err)
+	}
+	if err = sc.checkTombstoneInsert("1_keys", []string{"name"}, []any{"home"}); err != nil {
+		t.Errorf("table without soft delete: %v", err)
+	}
+	if err = CheckTombstone(sc, "contracts", "Old"); !errors.Is(err, ErrObjectDeleted) || !strings.Contains(err.Error(), "purged") {
+		t.Errorf("reusing name of purged contract: got %v", err)
+	}
+	if err = CheckTombstone(sc, "@1menu", "home"); err != nil {
+		t.Errorf("menu with the name of deleted page: %v", err)
+	}
+	if err = CheckTombstone(sc, "keys", "home"); err == nil {
+		t.Error("expected error of the table without soft delete")
+	}
+}
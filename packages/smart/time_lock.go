@@ -0,0 +1,121 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrTimeLock is returned when the parameters of the time lock are wrong
+	ErrTimeLock = errors.New("wrong time lock")
+	// ErrTimeLockNotMatured is returned when the time lock doesn't have the matured tokens to release
+	ErrTimeLockNotMatured = errors.New("time lock isn't matured")
+)
+
+// newTimeLock checks the parameters of the lock created at the block
+func newTimeLock(amount decimal.Decimal, unlockTime, unlockBlock, vestingEnd int64, header *types.BlockHeader) (*sqldb.TimeLock, error) {
+	switch {
+	case !amount.IsPositive() || !amount.Equal(amount.Truncate(0)):
+		return nil, fmt.Errorf("%w: amount %s must be a positive integer", ErrTimeLock, amount)
+	case unlockTime <= header.Timestamp && unlockBlock <= header.BlockId && vestingEnd == 0:
+		return nil, fmt.Errorf("%w: unlock time or block must be in the future", ErrTimeLock)
+	case vestingEnd != 0 && (vestingEnd <= header.Timestamp || vestingEnd < unlockTime):
+		return nil, fmt.Errorf("%w: vesting end %d must be in the future and not before the unlock time", ErrTimeLock, vestingEnd)
+	}
+	return &sqldb.TimeLock{
+		Amount:      amount,
+		Released:    decimal.Zero,
+		CreatedAt:   header.Timestamp,
+		UnlockTime:  unlockTime,
+		UnlockBlock: unlockBlock,
+		VestingEnd:  vestingEnd,
+		BlockID:     header.BlockId,
+	}, nil
+}
+
+// SetTimeLock moves the amount from the account of the transaction key to the time lock for the
+// recipient, so it's excluded from the balance of the sender immediately. The tokens mature at
+// unlockTime and unlockBlock, the zero values are not checked. If vestingEnd is set the tokens vest
+// linearly from the block time until vestingEnd. It returns the id of the lock
+func SetTimeLock(sc *SmartContract, recipient int64, amount decimal.Decimal, unlockTime, unlockBlock, vestingEnd int64) (int64, error) {
+	if err := validateAccess(sc, "SetTimeLock"); err != nil {
+		return 0, err
+	}
+	if recipient == 0 {
+		return 0, logError(fmt.Errorf("%w: recipient is empty", ErrTimeLock), consts.InvalidObject, "checking time lock")
+	}
+	lock, err := newTimeLock(amount, unlockTime, unlockBlock, vestingEnd, sc.BlockHeader)
+	if err != nil {
+		return 0, logError(err, consts.InvalidObject, "checking time lock")
+	}
+	ecosystem, sender := sc.TxSmart.EcosystemID, sc.TxSmart.KeyID
+	if err = sc.checkFrozen(ecosystem, sender); err != nil {
+		return 0, err
+	}
+	balance, err := sc.accountBalanceSingle(ecosystem, sender)
+	if err != nil {
+		return 0, err
+	}
+	if balance.LessThan(amount) {
+		return 0, fmt.Errorf(eEcoCurrentBalance, converter.IDToAddress(sender), ecosystem)
+	}
+	if _, _, err = sc.updateWhere([]string{"-amount"}, []any{amount}, "1_keys",
+		types.LoadMap(map[string]any{"id": sender, "ecosystem": ecosystem})); err != nil {
+		return 0, err
+	}
+	_, id, err := sc.insert([]string{"sender_id", "recipient_id", "amount", "released", "created_at",
+		"unlock_time", "unlock_block", "vesting_end", "block_id", "ecosystem"},
+		[]any{sender, recipient, lock.Amount, lock.Released, lock.CreatedAt,
+			lock.UnlockTime, lock.UnlockBlock, lock.VestingEnd, lock.BlockID, ecosystem}, "1_time_locks")
+	if err != nil {
+		return 0, err
+	}
+	return converter.StrToInt64(id), nil
+}
+
+// ReleaseTimeLock credits the matured part of the lock to its recipient, it can be called by any key.
+// It returns the released amount
+func ReleaseTimeLock(sc *SmartContract, id int64) (decimal.Decimal, error) {
+	if err := validateAccess(sc, "ReleaseTimeLock"); err != nil {
+		return decimal.Zero, err
+	}
+	ecosystem := sc.TxSmart.EcosystemID
+	lock := &sqldb.TimeLock{}
+	found, err := lock.Get(sc.DbTransaction, ecosystem, id)
+	if err != nil {
+		return decimal.Zero, logErrorDB(err, "getting time lock")
+	}
+	if !found {
+		return decimal.Zero, logErrorfShort(eRecordNotFound, id, consts.NotFound)
+	}
+	amount := lock.Releasable(sc.BlockHeader.Timestamp, sc.BlockHeader.BlockId)
+	if !amount.IsPositive() {
+		if lock.Released.Equal(lock.Amount) {
+			return decimal.Zero, fmt.Errorf("%w: time lock %d has been released", ErrTimeLockNotMatured, id)
+		}
+		return decimal.Zero, fmt.Errorf("%w: time lock %d", ErrTimeLockNotMatured, id)
+	}
+	if err = sc.hasExistKeyID(ecosystem, lock.RecipientID); err != nil {
+		return decimal.Zero, err
+	}
+	if _, _, err = sc.updateWhere([]string{"released"}, []any{lock.Released.Add(amount)}, "1_time_locks",
+		types.LoadMap(map[string]any{"id": id, "ecosystem": ecosystem})); err != nil {
+		return decimal.Zero, err
+	}
+	if _, _, err = sc.updateWhere([]string{"+amount"}, []any{amount}, "1_keys",
+		types.LoadMap(map[string]any{"id": lock.RecipientID, "ecosystem": ecosystem})); err != nil {
+		return decimal.Zero, err
+	}
+	return amount, nil
+}
@@ -0,0 +1,46 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewTimeLock(t *testing.T) {
+	header := &types.BlockHeader{BlockId: 10, Timestamp: 1000}
+	amount := decimal.NewFromInt(100)
+	for name, item := range map[string]struct {
+		amount                              decimal.Decimal
+		unlockTime, unlockBlock, vestingEnd int64
+		ok                                  bool
+	}{
+		"cliff by time":        {amount, 2000, 0, 0, true},
+		"cliff by block":       {amount, 0, 11, 0, true},
+		"linear":               {amount, 0, 0, 2000, true},
+		"cliff and linear":     {amount, 1500, 0, 2000, true},
+		"zero amount":          {decimal.Zero, 2000, 0, 0, false},
+		"negative amount":      {decimal.NewFromInt(-1), 2000, 0, 0, false},
+		"fractional amount":    {decimal.NewFromFloat(1.5), 2000, 0, 0, false},
+		"matured":              {amount, 1000, 10, 0, false},
+		"vesting in the past":  {amount, 0, 0, 1000, false},
+		"vesting before cliff": {amount, 1500, 0, 1200, false},
+	} {
+		lock, err := newTimeLock(item.amount, item.unlockTime, item.unlockBlock, item.vestingEnd, header)
+		if item.ok {
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+			} else if lock.CreatedAt != header.Timestamp || lock.BlockID != header.BlockId || !lock.Released.IsZero() {
+				t.Errorf("%s: wrong lock %+v", name, lock)
+			}
+		} else if !errors.Is(err, ErrTimeLock) {
+			t.Errorf("%s: expected %v, got %v", name, ErrTimeLock, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package smart
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// TestTransferSelfOutputSpent checks that the transfer from the outputs spent by the previous
+// transaction of the key fails with ErrOutputSpent
+func TestTransferSelfOutputSpent(t *testing.T) {
+	blockKeys{}.install(t)
+	key := sqldb.KeyUTXO{Ecosystem: 1, KeyId: 5}
+	for _, outputs := range [][]sqldb.SpentInfo{
+		nil,
+		{{OutputKeyId: 5, OutputValue: "100", Ecosystem: 1, InputTxHash: []byte{1}}},
+		{{OutputKeyId: 5, OutputValue: "40", Ecosystem: 1}},
+	} {
+		sc := newFreezeContract(1, 5)
+		sc.OutputsMap = map[sqldb.KeyUTXO][]sqldb.SpentInfo{key: outputs}
+		if _, err := TransferSelf(sc, "50", "UTXO", "Account"); !errors.Is(err, ErrOutputSpent) {
+			t.Errorf("expected %v, got %v", ErrOutputSpent, err)
+		}
+	}
+}
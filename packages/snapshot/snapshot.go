@@ -0,0 +1,460 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package snapshot exports the state database of the node at the block boundary into the chunked
+// archive and restores it on the new node, so the node continues syncing from the block of the
+// snapshot instead of playing the blocks from the genesis
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// ManifestFile is the name of the manifest of the archive in its directory
+	ManifestFile = "manifest.json"
+	// formatVersion is the version of the format of the archive
+	formatVersion = 1
+	// chunkRows is the maximum number of the rows of the chunk
+	chunkRows = 10000
+	// maxRowSize is the maximum size of the json row of the chunk
+	maxRowSize = 256 << 20
+)
+
+var (
+	// ErrChunkHash is returned if the chunk of the archive doesn't match the hash of the manifest
+	ErrChunkHash = errors.New("snapshot chunk hash doesn't match")
+	// ErrSchemaVersion is returned if the archive is exported from the database of the other migrations
+	ErrSchemaVersion = errors.New("snapshot schema version doesn't match")
+	// ErrNotEmpty is returned if the node already has the blocks, the snapshot is imported by the new node only
+	ErrNotEmpty = errors.New("node already has blocks")
+)
+
+// localTables are the tables of the node which aren't the state, they aren't exported
+var localTables = map[string]bool{
+	"install":               true,
+	"migration_history":     true,
+	"queue_blocks":          true,
+	"queue_tx":              true,
+	"stop_daemons":          true,
+	"transactions":          true,
+	"transactions_attempts": true,
+	"transactions_status":   true,
+}
+
+// lastBlockTables are the tables of the history of the blocks, only the rows of the block of the snapshot
+// are exported, they are needed to check the next block and to roll back the block of the snapshot
+var lastBlockTables = map[string]string{
+	"block_chain": "id = ?",
+	"rollback_tx": "block_id = ?",
+}
+
+// Manifest is the description of the archive, the chunks are imported in its order
+type Manifest struct {
+	Version       int       `json:"version"`
+	BlockID       int64     `json:"block_id"`
+	BlockHash     string    `json:"block_hash"`
+	SchemaVersion string    `json:"schema_version"`
+	Created       time.Time `json:"created"`
+	Tables        []Table   `json:"tables"`
+	Chunks        []Chunk   `json:"chunks"`
+}
+
+// Table is the schema of the exported table, the tables of the ecosystems which the new node doesn't
+// have are created by it
+type Table struct {
+	Name    string   `json:"name"`
+	Create  string   `json:"create"`
+	Indexes []string `json:"indexes"`
+}
+
+// Chunk is the file of the rows of the table, Hash is sha256 of the file
+type Chunk struct {
+	Table string `json:"table"`
+	File  string `json:"file"`
+	Rows  int    `json:"rows"`
+	Hash  string `json:"hash"`
+}
+
+// Export writes the state of the database at the last committed block into the directory dir. The rows
+// are read in one repeatable read transaction, so the blocks committed meanwhile aren't exported
+func Export(ctx context.Context, dir string) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	schema, err := new(sqldb.MigrationHistory).CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	tx := sqldb.DBConn.WithContext(ctx).Begin(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer tx.Rollback()
+
+	info := &sqldb.InfoBlock{}
+	if err = tx.Last(info).Error; err != nil {
+		return nil, fmt.Errorf("getting info block: %w", err)
+	}
+	m := &Manifest{
+		Version:       formatVersion,
+		BlockID:       info.BlockID,
+		BlockHash:     hex.EncodeToString(info.Hash),
+		SchemaVersion: schema,
+		Created:       time.Now().UTC(),
+	}
+	var tables []string
+	if err = tx.Raw(`SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY tablename`).
+		Scan(&tables).Error; err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		if localTables[table] {
+			continue
+		}
+		schema, err := tableSchema(tx, table)
+		if err != nil {
+			return nil, fmt.Errorf("getting schema of %s: %w", table, err)
+		}
+		m.Tables = append(m.Tables, *schema)
+		if err = exportTable(tx, dir, table, m); err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", table, err)
+		}
+	}
+	if err = writeManifest(dir, m); err != nil {
+		return nil, err
+	}
+	log.WithFields(log.Fields{"block_id": m.BlockID, "chunks": len(m.Chunks), "dir": dir}).Info("snapshot exported")
+	return m, nil
+}
+
+// tableSchema returns the statements which create the table and its indexes
+func tableSchema(tx *gorm.DB, table string) (*Table, error) {
+	var columns []struct {
+		Name    string
+		Type    string
+		NotNull bool
+		Def     *string
+	}
+	if err := tx.Raw(`SELECT a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type,
+		a.attnotnull AS not_null, pg_get_expr(d.adbin, d.adrelid) AS def
+		FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = ?::regclass AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum`,
+		`"`+table+`"`).Scan(&columns).Error; err != nil {
+		return nil, err
+	}
+	defs := make([]string, 0, len(columns))
+	for _, c := range columns {
+		def := fmt.Sprintf(`"%s" %s`, c.Name, c.Type)
+		if c.NotNull {
+			def += " NOT NULL"
+		}
+		if c.Def != nil {
+			def += " DEFAULT " + *c.Def
+		}
+		defs = append(defs, def)
+	}
+	t := &Table{Name: table, Create: fmt.Sprintf(`CREATE TABLE "%s" (%s)`, table, strings.Join(defs, ", "))}
+	if err := tx.Raw(`SELECT indexdef FROM pg_indexes WHERE schemaname = 'public' AND tablename = ? ORDER BY indexname`,
+		table).Scan(&t.Indexes).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// createTables creates the tables of the manifest which the database doesn't have
+func createTables(tx *gorm.DB, tables []Table) error {
+	for _, t := range tables {
+		var exists bool
+		if err := tx.Raw(`SELECT to_regclass(?) IS NOT NULL`, `"`+t.Name+`"`).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := tx.Exec(t.Create).Error; err != nil {
+			return fmt.Errorf("creating %s: %w", t.Name, err)
+		}
+		for _, index := range t.Indexes {
+			if err := tx.Exec(index).Error; err != nil {
+				return fmt.Errorf("creating index of %s: %w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// exportTable writes the rows of the table as the json lines of the gzipped chunks
+func exportTable(tx *gorm.DB, dir, table string, m *Manifest) error {
+	query := fmt.Sprintf(`SELECT row_to_json(t)::text FROM "%s" t`, table)
+	var args []any
+	if where, ok := lastBlockTables[table]; ok {
+		query += " WHERE " + where
+		args = append(args, m.BlockID)
+	}
+	rows, err := tx.Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var w *chunkWriter
+	for rows.Next() {
+		var row string
+		if err = rows.Scan(&row); err != nil {
+			if w != nil {
+				w.close()
+			}
+			return err
+		}
+		if w == nil {
+			if w, err = newChunkWriter(dir, table, len(m.Chunks)); err != nil {
+				return err
+			}
+		}
+		if err = w.write(row); err != nil {
+			w.close()
+			return err
+		}
+		if w.rows < chunkRows {
+			continue
+		}
+		if err = w.finish(m); err != nil {
+			return err
+		}
+		w = nil
+	}
+	if err = rows.Err(); err != nil {
+		if w != nil {
+			w.close()
+		}
+		return err
+	}
+	if w != nil {
+		return w.finish(m)
+	}
+	return nil
+}
+
+type chunkWriter struct {
+	chunk Chunk
+	file  *os.File
+	hash  hash.Hash
+	gz    *gzip.Writer
+	rows  int
+}
+
+func newChunkWriter(dir, table string, index int) (*chunkWriter, error) {
+	name := fmt.Sprintf("%06d.jsonl.gz", index)
+	f, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	w := &chunkWriter{chunk: Chunk{Table: table, File: name}, file: f, hash: sha256.New()}
+	w.gz = gzip.NewWriter(io.MultiWriter(f, w.hash))
+	return w, nil
+}
+
+func (w *chunkWriter) write(row string) error {
+	w.rows++
+	_, err := io.WriteString(w.gz, row+"\n")
+	return err
+}
+
+func (w *chunkWriter) close() {
+	w.gz.Close()
+	w.file.Close()
+}
+
+// finish closes the chunk and adds it to the manifest
+func (w *chunkWriter) finish(m *Manifest) error {
+	if err := w.gz.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	w.chunk.Rows = w.rows
+	w.chunk.Hash = hex.EncodeToString(w.hash.Sum(nil))
+	m.Chunks = append(m.Chunks, w.chunk)
+	return nil
+}
+
+func writeManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
+
+// ReadManifest reads the manifest of the archive of the directory dir
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.Version != formatVersion {
+		return nil, fmt.Errorf("unknown snapshot format version %d", m.Version)
+	}
+	return m, nil
+}
+
+// Verify checks the hashes of all the chunks of the archive
+func Verify(dir string, m *Manifest) error {
+	for _, c := range m.Chunks {
+		f, err := os.Open(filepath.Join(dir, filepath.Base(c.File)))
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if hex.EncodeToString(h.Sum(nil)) != c.Hash {
+			return fmt.Errorf("%w: %s", ErrChunkHash, c.File)
+		}
+	}
+	return nil
+}
+
+// Import restores the state of the archive of the directory dir. The database must have the same migrations
+// and no blocks, the missing tables are created and the tables of the archive are replaced in one transaction.
+// The node continues syncing from the block of the snapshot
+func Import(ctx context.Context, dir string) (*Manifest, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err = Verify(dir, m); err != nil {
+		return nil, err
+	}
+	schema, err := new(sqldb.MigrationHistory).CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if schema != m.SchemaVersion {
+		return nil, fmt.Errorf("%w: %s of snapshot, %s of node", ErrSchemaVersion, m.SchemaVersion, schema)
+	}
+	last := &sqldb.BlockChain{}
+	found, err := last.GetMaxBlock()
+	if err != nil {
+		return nil, err
+	}
+	if found && last.ID > 0 {
+		return nil, fmt.Errorf("%w: last block %d", ErrNotEmpty, last.ID)
+	}
+
+	err = sqldb.DBConn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createTables(tx, m.Tables); err != nil {
+			return err
+		}
+		truncated := make(map[string]bool)
+		for _, c := range m.Chunks {
+			if !truncated[c.Table] {
+				truncated[c.Table] = true
+				if err := tx.Exec(fmt.Sprintf(`TRUNCATE "%s"`, c.Table)).Error; err != nil {
+					return fmt.Errorf("truncating %s: %w", c.Table, err)
+				}
+			}
+			if err := importChunk(tx, dir, c); err != nil {
+				return fmt.Errorf("importing %s: %w", c.File, err)
+			}
+		}
+		for table := range truncated {
+			if err := resetSequences(tx, table); err != nil {
+				return fmt.Errorf("resetting sequences of %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "dir": dir}).Error("importing snapshot")
+		return nil, err
+	}
+	log.WithFields(log.Fields{"block_id": m.BlockID, "chunks": len(m.Chunks), "dir": dir}).Info("snapshot imported")
+	return m, nil
+}
+
+// importChunk inserts the json rows of the chunk, the columns are matched by their names
+func importChunk(tx *gorm.DB, dir string, c Chunk) error {
+	f, err := os.Open(filepath.Join(dir, filepath.Base(c.File)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	rows := make([]json.RawMessage, 0, c.Rows)
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRowSize)
+	for scanner.Scan() {
+		rows = append(rows, json.RawMessage(append([]byte{}, scanner.Bytes()...)))
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if len(rows) != c.Rows {
+		return fmt.Errorf("%w: %d rows of %d", ErrChunkHash, len(rows), c.Rows)
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(`INSERT INTO "%[1]s" SELECT * FROM json_populate_recordset(NULL::"%[1]s", ?::json)`, c.Table),
+		string(data)).Error
+}
+
+// resetSequences sets the sequences of the columns of the table after their greatest values
+func resetSequences(tx *gorm.DB, table string) error {
+	var columns []struct {
+		Sequence string
+		Column   string
+	}
+	if err := tx.Raw(`SELECT s.relname AS sequence, a.attname AS column FROM pg_class s
+		JOIN pg_depend d ON d.objid = s.oid AND d.deptype = 'a'
+		JOIN pg_class t ON t.oid = d.refobjid
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = d.refobjsubid
+		WHERE s.relkind = 'S' AND t.relname = ?`, table).Scan(&columns).Error; err != nil {
+		return err
+	}
+	for _, c := range columns {
+		if err := tx.Exec(fmt.Sprintf(`SELECT setval('"%s"', COALESCE(MAX("%s"), 0) + 1, false) FROM "%s"`,
+			c.Sequence, c.Column, table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	m := &Manifest{Version: formatVersion, BlockID: 10}
+	for i, table := range []string{"1_keys", "block_chain"} {
+		w, err := newChunkWriter(dir, table, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = w.write(`{"id":1}`); err != nil {
+			t.Fatal(err)
+		}
+		if err = w.finish(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writeManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Chunks) != 2 || read.Chunks[1].Table != "block_chain" || read.Chunks[1].Rows != 1 {
+		t.Fatalf("wrong chunks %+v", read.Chunks)
+	}
+	if err = Verify(dir, read); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, read.Chunks[0].File), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = Verify(dir, read); !errors.Is(err, ErrChunkHash) {
+		t.Errorf("expected %v got %v", ErrChunkHash, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/converter"
 
 	"github.com/cactus/go-statsd-client/v5/statsd"
 )
@@ -16,6 +17,17 @@ import (
 const (
 	Count = ".count"
 	Time  = ".time"
+
+	// KeyTxsMax is the max count of the transactions of one key in the generated block
+	KeyTxsMax = "block.key_txs.max"
+	// SlowStatements is the count of the slow statements of the block transactions
+	SlowStatements = "db.slow_statements"
+	// PriorityInversions is the count of the high-fee transactions which wait behind the low-fee ones
+	PriorityInversions = "txpool.priority_inversions"
+	// DBSavepoint is the latency of the savepoints of the block transactions
+	DBSavepoint = "db.savepoint"
+	// DBSavepointRollback is the latency of the rollbacks to the savepoints of the block transactions
+	DBSavepointRollback = "db.savepoint_rollback"
 )
 
 var Client statsd.Statter
@@ -48,3 +60,21 @@ func APIRouteCounterName(method, pattern string) string {
 func DaemonCounterName(daemonName string) string {
 	return "daemon." + daemonName
 }
+
+// KeyTxsCounterName is the count of the transactions of the key in the generated block
+func KeyTxsCounterName(keyID int64) string {
+	return "block.key_txs." + converter.AddressToString(keyID)
+}
+
+// SkippedKeyTxsCounterName is the count of the transactions of the key skipped by the block limits
+func SkippedKeyTxsCounterName(keyID int64) string {
+	return "block.key_txs_skipped." + converter.AddressToString(keyID)
+}
+
+// SuccessCounterName splits the counter by the result of the measured call
+func SuccessCounterName(counterName string, success bool) string {
+	if success {
+		return counterName + ".success"
+	}
+	return counterName + ".failure"
+}
@@ -0,0 +1,137 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package dastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	fieldElementsPerBlob = 4096
+	bytesPerFieldElement = 32
+	// BlobSize is the size of the blob of EIP-4844
+	BlobSize = fieldElementsPerBlob * bytesPerFieldElement
+	// the first byte of the field element is zero, so the element is less than the modulus of BLS12-381
+	bytesPerElementData = bytesPerFieldElement - 1
+	blobDataSize        = fieldElementsPerBlob * bytesPerElementData
+
+	lengthSize = 8 // the data is prefixed with its length in the first blob
+	refSize    = 8 + 32
+)
+
+// BlobRef is the blob of the blob transaction, the blob is found by the slot of the beacon chain
+// and the versioned hash of its KZG commitment
+type BlobRef struct {
+	Slot          uint64
+	VersionedHash [32]byte
+}
+
+// BlobClient sends and gets the blobs. The blob transactions are built and signed by the relayer,
+// the blob transaction of the node needs the KZG commitments and the key of the ethereum account
+type BlobClient interface {
+	// SubmitBlobs sends the blobs of the block and returns their references in the same order
+	SubmitBlobs(blockID int64, blobs [][]byte) ([]BlobRef, error)
+	// GetBlob returns the blob of the reference, it fails with ErrNotAvailable if the blob is pruned
+	GetBlob(ref BlobRef) ([]byte, error)
+}
+
+// BlobStore keeps the data in the blobs of EIP-4844. The commitment is the hash of the data and the
+// references of its blobs
+type BlobStore struct {
+	client BlobClient
+}
+
+// NewBlobStore creates the store which sends the blobs by the client
+func NewBlobStore(client BlobClient) *BlobStore {
+	return &BlobStore{client: client}
+}
+
+func (s *BlobStore) Store(blockID int64, data []byte) ([]byte, error) {
+	blobs := encodeBlobs(data)
+	refs, err := s.client.SubmitBlobs(blockID, blobs)
+	if err != nil {
+		return nil, fmt.Errorf("submitting blobs of block %d: %w", blockID, err)
+	}
+	if len(refs) != len(blobs) {
+		return nil, fmt.Errorf("submitting blobs of block %d: %d references for %d blobs", blockID, len(refs), len(blobs))
+	}
+	commitment := make([]byte, 0, len(dataHash(nil))+len(refs)*refSize)
+	commitment = append(commitment, dataHash(data)...)
+	for _, ref := range refs {
+		commitment = binary.BigEndian.AppendUint64(commitment, ref.Slot)
+		commitment = append(commitment, ref.VersionedHash[:]...)
+	}
+	return commitment, nil
+}
+
+func (s *BlobStore) Retrieve(commitment []byte) ([]byte, error) {
+	hashSize := len(dataHash(nil))
+	if len(commitment) <= hashSize || (len(commitment)-hashSize)%refSize != 0 {
+		return nil, fmt.Errorf("%w: wrong size %d", ErrCommitment, len(commitment))
+	}
+	hash, refs := commitment[:hashSize], commitment[hashSize:]
+	blobs := make([][]byte, 0, len(refs)/refSize)
+	for ; len(refs) > 0; refs = refs[refSize:] {
+		ref := BlobRef{Slot: binary.BigEndian.Uint64(refs)}
+		copy(ref.VersionedHash[:], refs[8:refSize])
+		blob, err := s.client.GetBlob(ref)
+		if err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	data, err := decodeBlobs(blobs)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(dataHash(data), hash) {
+		return nil, fmt.Errorf("%w: %x", ErrCommitment, hash)
+	}
+	return data, nil
+}
+
+// encodeBlobs splits the data prefixed with its length into the field elements of the blobs
+func encodeBlobs(data []byte) [][]byte {
+	framed := binary.BigEndian.AppendUint64(make([]byte, 0, lengthSize+len(data)), uint64(len(data)))
+	framed = append(framed, data...)
+	blobs := make([][]byte, 0, (len(framed)+blobDataSize-1)/blobDataSize)
+	for len(framed) > 0 {
+		blob := make([]byte, BlobSize)
+		for i := 0; i < fieldElementsPerBlob && len(framed) > 0; i++ {
+			n := copy(blob[i*bytesPerFieldElement+1:(i+1)*bytesPerFieldElement], framed)
+			framed = framed[n:]
+		}
+		blobs = append(blobs, blob)
+	}
+	return blobs
+}
+
+// decodeBlobs joins the data of the blobs made by encodeBlobs
+func decodeBlobs(blobs [][]byte) ([]byte, error) {
+	framed := make([]byte, 0, len(blobs)*blobDataSize)
+	for _, blob := range blobs {
+		if len(blob) != BlobSize {
+			return nil, fmt.Errorf("%w: blob size %d", ErrCommitment, len(blob))
+		}
+		for i := 0; i < fieldElementsPerBlob; i++ {
+			element := blob[i*bytesPerFieldElement : (i+1)*bytesPerFieldElement]
+			if element[0] != 0 {
+				return nil, fmt.Errorf("%w: wrong field element %d", ErrCommitment, i)
+			}
+			framed = append(framed, element[1:]...)
+		}
+	}
+	if len(framed) < lengthSize {
+		return nil, fmt.Errorf("%w: no data length", ErrCommitment)
+	}
+	size := binary.BigEndian.Uint64(framed)
+	if size > uint64(len(framed)-lengthSize) {
+		return nil, fmt.Errorf("%w: data length %d exceeds blobs", ErrCommitment, size)
+	}
+	return framed[lengthSize : lengthSize+size], nil
+}
@@ -0,0 +1,140 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package dastore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// blobCommitmentVersion is the version of the versioned hash of the KZG commitment
+const blobCommitmentVersion = 0x01
+
+// HTTPBlobClient sends the blobs to the relayer and gets them from the beacon node.
+//
+// The relayer accepts POST /blobs with {"block_id": 1, "blobs": ["0x..."]}, sends the blob
+// transactions and replies {"blobs": [{"slot": "1", "versioned_hash": "0x..."}]}. The blobs are
+// got by GET /eth/v1/beacon/blob_sidecars/{slot} of the beacon API
+type HTTPBlobClient struct {
+	relayer string
+	beacon  string
+	client  *http.Client
+}
+
+// NewHTTPBlobClient creates the client of the relayer and the beacon node
+func NewHTTPBlobClient(relayer, beacon string, timeout time.Duration) *HTTPBlobClient {
+	return &HTTPBlobClient{
+		relayer: strings.TrimSuffix(relayer, "/"),
+		beacon:  strings.TrimSuffix(beacon, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type submitBlobsRequest struct {
+	BlockID int64    `json:"block_id"`
+	Blobs   []string `json:"blobs"`
+}
+
+type blobRefResponse struct {
+	Slot          uint64 `json:"slot,string"`
+	VersionedHash string `json:"versioned_hash"`
+}
+
+type submitBlobsResponse struct {
+	Blobs []blobRefResponse `json:"blobs"`
+}
+
+type blobSidecarsResponse struct {
+	Data []struct {
+		Blob          string `json:"blob"`
+		KzgCommitment string `json:"kzg_commitment"`
+	} `json:"data"`
+}
+
+func (c *HTTPBlobClient) SubmitBlobs(blockID int64, blobs [][]byte) ([]BlobRef, error) {
+	req := submitBlobsRequest{BlockID: blockID, Blobs: make([]string, len(blobs))}
+	for i, blob := range blobs {
+		req.Blobs[i] = encodeHex(blob)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Post(c.relayer+"/blobs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("relayer replies %s", resp.Status)
+	}
+	var submitted submitBlobsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&submitted); err != nil {
+		return nil, fmt.Errorf("decoding relayer response: %w", err)
+	}
+	refs := make([]BlobRef, len(submitted.Blobs))
+	for i, b := range submitted.Blobs {
+		hash, err := decodeHex(b.VersionedHash)
+		if err != nil || len(hash) != len(refs[i].VersionedHash) {
+			return nil, fmt.Errorf("wrong versioned hash %s", b.VersionedHash)
+		}
+		refs[i].Slot = b.Slot
+		copy(refs[i].VersionedHash[:], hash)
+	}
+	return refs, nil
+}
+
+// GetBlob gets the sidecars of the slot and returns the blob of the versioned hash. The beacon
+// nodes prune the blobs after 4096 epochs, so the older blobs are got from the archival node only
+func (c *HTTPBlobClient) GetBlob(ref BlobRef) ([]byte, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/eth/v1/beacon/blob_sidecars/%d", c.beacon, ref.Slot))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: slot %d", ErrNotAvailable, ref.Slot)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("beacon node replies %s", resp.Status)
+	}
+	var sidecars blobSidecarsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&sidecars); err != nil {
+		return nil, fmt.Errorf("decoding blob sidecars: %w", err)
+	}
+	for _, sidecar := range sidecars.Data {
+		commitment, err := decodeHex(sidecar.KzgCommitment)
+		if err != nil {
+			return nil, fmt.Errorf("wrong kzg commitment %s", sidecar.KzgCommitment)
+		}
+		if versionedHash(commitment) != ref.VersionedHash {
+			continue
+		}
+		return decodeHex(sidecar.Blob)
+	}
+	return nil, fmt.Errorf("%w: blob %x at slot %d", ErrNotAvailable, ref.VersionedHash, ref.Slot)
+}
+
+// versionedHash returns the versioned hash of the KZG commitment as kzg_to_versioned_hash of EIP-4844
+func versionedHash(commitment []byte) (hash [32]byte) {
+	hash = sha256.Sum256(commitment)
+	hash[0] = blobCommitmentVersion
+	return
+}
+
+func encodeHex(data []byte) string {
+	return "0x" + hex.EncodeToString(data)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
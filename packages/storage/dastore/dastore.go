@@ -0,0 +1,64 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package dastore keeps the transactions of the blocks off-chain in the data availability layer.
+// block_chain table keeps the block without the transactions and the commitment of the block data,
+// the data is retrieved by the commitment and checked against it.
+//
+// The layer is local to the node, the blocks are sent to the other nodes with the transactions
+package dastore
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+)
+
+const (
+	TypeFile = "file" // the files of the local directory
+	TypeBlob = "blob" // the blobs of the ethereum blob transactions
+)
+
+var (
+	ErrNotAvailable = errors.New("block data isn't available")
+	ErrCommitment   = errors.New("block data doesn't match the commitment")
+	ErrDisabled     = errors.New("data availability layer is disabled")
+)
+
+// DataAvailability is the off-chain storage of the block data
+type DataAvailability interface {
+	// Store stores the data of the block and returns its commitment
+	Store(blockID int64, data []byte) (commitment []byte, err error)
+	// Retrieve returns the data of the commitment, it fails with ErrNotAvailable if the data
+	// isn't stored and with ErrCommitment if the stored data doesn't match the commitment
+	Retrieve(commitment []byte) ([]byte, error)
+}
+
+// DA is the layer of the node, it's nil if it isn't configured
+var DA DataAvailability
+
+// Init creates the layer of the node by the config
+func Init(cfg conf.DAConfig) (err error) {
+	switch cfg.Type {
+	case "":
+		DA = nil
+	case TypeFile:
+		DA, err = NewFileStore(cfg.Path)
+	case TypeBlob:
+		DA = NewBlobStore(NewHTTPBlobClient(cfg.BlobRelayer, cfg.BeaconNode, time.Duration(cfg.Timeout)*time.Second))
+	default:
+		err = fmt.Errorf("unknown data availability layer %s", cfg.Type)
+	}
+	return
+}
+
+// dataHash is the hash of the data in the commitments
+func dataHash(data []byte) []byte {
+	hash := sha256.Sum256(data)
+	return hash[:]
+}
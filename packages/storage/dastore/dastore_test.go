@@ -0,0 +1,183 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package dastore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("block data")
+	commitment, err := s.Store(2, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.Store(3, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(commitment, again) {
+		t.Errorf("the same data has the different commitments %x and %x", commitment, again)
+	}
+	got, err := s.Retrieve(commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected %q, got %q", data, got)
+	}
+
+	if _, err = s.Retrieve(dataHash([]byte("other"))); !errors.Is(err, ErrNotAvailable) {
+		t.Errorf("expected %v, got %v", ErrNotAvailable, err)
+	}
+	if _, err = s.Retrieve(commitment[1:]); !errors.Is(err, ErrCommitment) {
+		t.Errorf("expected %v, got %v", ErrCommitment, err)
+	}
+	if err = os.WriteFile(s.path(commitment), []byte("corrupted"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Retrieve(commitment); !errors.Is(err, ErrCommitment) {
+		t.Errorf("expected %v, got %v", ErrCommitment, err)
+	}
+	tmp, _ := filepath.Glob(filepath.Join(dir, "*", "*.tmp"))
+	if len(tmp) > 0 {
+		t.Errorf("temporary files are left %v", tmp)
+	}
+}
+
+func TestBlobEncoding(t *testing.T) {
+	for _, size := range []int{0, 1, blobDataSize - lengthSize, blobDataSize - lengthSize + 1, 2*blobDataSize + 100} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i*7 + 0xe0)
+		}
+		blobs := encodeBlobs(data)
+		if count := (size + lengthSize + blobDataSize - 1) / blobDataSize; len(blobs) != count {
+			t.Errorf("%d: expected %d blobs, got %d", size, count, len(blobs))
+		}
+		for _, blob := range blobs {
+			for i := 0; i < len(blob); i += bytesPerFieldElement {
+				if blob[i] != 0 {
+					t.Fatalf("%d: field element %d overflows", size, i/bytesPerFieldElement)
+				}
+			}
+		}
+		got, err := decodeBlobs(blobs)
+		if err != nil {
+			t.Fatalf("%d: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("%d: the decoded data differs", size)
+		}
+	}
+	blob := encodeBlobs([]byte("data"))[0]
+	blob[bytesPerFieldElement] = 1
+	if _, err := decodeBlobs([][]byte{blob}); !errors.Is(err, ErrCommitment) {
+		t.Errorf("expected %v, got %v", ErrCommitment, err)
+	}
+}
+
+// testBeacon is the relayer and the beacon node which keep the blobs in memory, the KZG commitment
+// of the blob is faked by its hash
+type testBeacon struct {
+	slots map[uint64][][]byte
+	next  uint64
+}
+
+func fakeKzgCommitment(blob []byte) []byte {
+	return append(dataHash(blob), make([]byte, 16)...)
+}
+
+func (b *testBeacon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/relayer/blobs":
+		var req submitBlobsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// the blobs are split into the transactions of two blobs
+		var resp submitBlobsResponse
+		for i, s := range req.Blobs {
+			if i%2 == 0 {
+				b.next++
+			}
+			blob, _ := decodeHex(s)
+			b.slots[b.next] = append(b.slots[b.next], blob)
+			hash := versionedHash(fakeKzgCommitment(blob))
+			resp.Blobs = append(resp.Blobs, blobRefResponse{Slot: b.next, VersionedHash: encodeHex(hash[:])})
+		}
+		json.NewEncoder(w).Encode(resp)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/beacon/eth/v1/beacon/blob_sidecars/"):
+		var slot uint64
+		fmt.Sscan(strings.TrimPrefix(r.URL.Path, "/beacon/eth/v1/beacon/blob_sidecars/"), &slot)
+		blobs, ok := b.slots[slot]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var resp blobSidecarsResponse
+		for _, blob := range blobs {
+			resp.Data = append(resp.Data, struct {
+				Blob          string `json:"blob"`
+				KzgCommitment string `json:"kzg_commitment"`
+			}{encodeHex(blob), encodeHex(fakeKzgCommitment(blob))})
+		}
+		json.NewEncoder(w).Encode(resp)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestBlobStore(t *testing.T) {
+	beacon := &testBeacon{slots: make(map[uint64][][]byte)}
+	server := httptest.NewServer(beacon)
+	defer server.Close()
+	s := NewBlobStore(NewHTTPBlobClient(server.URL+"/relayer/", server.URL+"/beacon", time.Second))
+
+	data := bytes.Repeat([]byte("transactions"), 3*blobDataSize/12)
+	commitment, err := s.Store(5, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs := (len(commitment) - len(dataHash(nil))) / refSize; refs != 4 {
+		t.Fatalf("expected 4 blobs, got %d", refs)
+	}
+	got, err := s.Retrieve(commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("the retrieved data differs")
+	}
+
+	pruned := append([]byte{}, commitment...)
+	binary.BigEndian.PutUint64(pruned[len(dataHash(nil)):], 100)
+	if _, err = s.Retrieve(pruned); !errors.Is(err, ErrNotAvailable) {
+		t.Errorf("expected %v, got %v", ErrNotAvailable, err)
+	}
+	wrong := append([]byte{}, commitment...)
+	wrong[0] ^= 1
+	if _, err = s.Retrieve(wrong); !errors.Is(err, ErrCommitment) {
+		t.Errorf("expected %v, got %v", ErrCommitment, err)
+	}
+}
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package dastore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps the data in the files of the directory. The commitment is the hash of the data and
+// the file is named by it, so the data of the block which is rolled back and played again is stored once
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates the store in dir
+func NewFileStore(dir string) (*FileStore, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("directory of the data availability files isn't set")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path returns the file of the hash, the files are split into the subdirectories by the first byte
+func (s *FileStore) path(hash []byte) string {
+	name := hex.EncodeToString(hash)
+	return filepath.Join(s.dir, name[:2], name)
+}
+
+// Store writes the data to the temporary file and renames it, so the file of the hash is always complete
+func (s *FileStore) Store(blockID int64, data []byte) ([]byte, error) {
+	hash := dataHash(data)
+	path := s.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", path, blockID)
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	return hash, nil
+}
+
+func (s *FileStore) Retrieve(commitment []byte) ([]byte, error) {
+	if len(commitment) != len(dataHash(nil)) {
+		return nil, fmt.Errorf("%w: wrong size %d", ErrCommitment, len(commitment))
+	}
+	data, err := os.ReadFile(s.path(commitment))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %x", ErrNotAvailable, commitment)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(dataHash(data), commitment) {
+		return nil, fmt.Errorf("%w: %x", ErrCommitment, commitment)
+	}
+	return data, nil
+}
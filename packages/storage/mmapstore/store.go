@@ -0,0 +1,366 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+// Package mmapstore keeps the copy of block_chain table in memory-mapped files for the fast
+// reading of historical blocks. The blocks are appended to the data file sequentially, the
+// index file has the fixed-size entry of each block id with the offset of its record.
+//
+// The data file is the source of truth. Every record has the header with block id, length and
+// checksum, so the index is fixed and the torn tail is cut by Open after the crash
+package mmapstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	DataFilename  = "blocks.dat"
+	IndexFilename = "blocks.idx"
+
+	headerSize     = 16 // block id 8 bytes, length 4 bytes, crc32 4 bytes
+	indexEntrySize = 16 // offset 8 bytes, length 4 bytes, crc32 4 bytes
+	dataGrowth     = 64 << 20
+	indexGrowth    = 1 << 20
+)
+
+var (
+	ErrNotFound  = errors.New("block not found")
+	ErrCorrupted = errors.New("block record is corrupted")
+	ErrClosed    = errors.New("block store is closed")
+)
+
+// Block is the record of block_chain table
+type Block = sqldb.BlockChain
+
+// Store is the store of the node, it's nil if MMAPBlockStorePath isn't configured
+var Store *BlockStore
+
+// Init opens the store of the node in dir
+func Init(dir string) (err error) {
+	Store, err = Open(dir)
+	return
+}
+
+type mappedFile struct {
+	f    *os.File
+	data []byte
+}
+
+func openMapped(path string, growth int64) (*mappedFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	m := &mappedFile{f: f}
+	info, err := f.Stat()
+	if err == nil {
+		size := roundUp(info.Size(), growth)
+		err = m.resize(size, size)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func roundUp(size, growth int64) int64 {
+	if size <= 0 {
+		return growth
+	}
+	return (size + growth - 1) / growth * growth
+}
+
+// resize changes the size of the file and maps it again, the new space is zeroed. The data
+// after keep is zeroed too
+func (m *mappedFile) resize(keep, size int64) error {
+	if m.data != nil {
+		if err := munmap(m.data); err != nil {
+			return err
+		}
+		m.data = nil
+	}
+	if keep < size {
+		if err := m.f.Truncate(keep); err != nil {
+			return err
+		}
+	}
+	if err := m.f.Truncate(size); err != nil {
+		return err
+	}
+	data, err := mmap(m.f, int(size))
+	if err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+// ensure grows the file if it's less than size
+func (m *mappedFile) ensure(size, growth int64) error {
+	if size <= int64(len(m.data)) {
+		return nil
+	}
+	size = roundUp(size, growth)
+	return m.resize(size, size)
+}
+
+// close truncates the file to size and closes it
+func (m *mappedFile) close(size int64) (err error) {
+	if m.data != nil {
+		err = msync(m.data)
+		if e := munmap(m.data); err == nil {
+			err = e
+		}
+		m.data = nil
+	}
+	if e := m.f.Truncate(size); err == nil {
+		err = e
+	}
+	if e := m.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// BlockStore is the append-only store of blocks
+type BlockStore struct {
+	mu    sync.RWMutex
+	data  *mappedFile
+	index *mappedFile
+	end   int64 // the size of the written records
+	last  int64 // id of the last written block
+}
+
+// Open opens or creates the store in dir and recovers it after the crash
+func Open(dir string) (*BlockStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	data, err := openMapped(filepath.Join(dir, DataFilename), dataGrowth)
+	if err != nil {
+		return nil, err
+	}
+	index, err := openMapped(filepath.Join(dir, IndexFilename), indexGrowth)
+	if err != nil {
+		data.close(0)
+		return nil, err
+	}
+	s := &BlockStore{data: data, index: index}
+	if err = s.recover(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("recovering block store: %w", err)
+	}
+	return s, nil
+}
+
+type entry struct {
+	offset int64
+	length uint32
+	crc    uint32
+}
+
+// the index entry and the record header have the same layout
+// with the offset or the block id in the first field
+func decode(buf []byte) (first int64, length, crc uint32) {
+	return int64(binary.BigEndian.Uint64(buf)), binary.BigEndian.Uint32(buf[8:]), binary.BigEndian.Uint32(buf[12:])
+}
+
+func encode(buf []byte, first int64, length, crc uint32) {
+	binary.BigEndian.PutUint64(buf, uint64(first))
+	binary.BigEndian.PutUint32(buf[8:], length)
+	binary.BigEndian.PutUint32(buf[12:], crc)
+}
+
+func (s *BlockStore) entryAt(blockID int64) (e entry, ok bool) {
+	pos := (blockID - 1) * indexEntrySize
+	if blockID < 1 || pos+indexEntrySize > int64(len(s.index.data)) {
+		return
+	}
+	e.offset, e.length, e.crc = decode(s.index.data[pos:])
+	return e, e.length > 0
+}
+
+func (s *BlockStore) setEntry(blockID int64, e entry) error {
+	pos := (blockID - 1) * indexEntrySize
+	if err := s.index.ensure(pos+indexEntrySize, indexGrowth); err != nil {
+		return err
+	}
+	encode(s.index.data[pos:], e.offset, e.length, e.crc)
+	return nil
+}
+
+// record returns the payload of the record at offset if it's valid
+func (s *BlockStore) record(offset int64) (blockID int64, e entry, payload []byte, ok bool) {
+	if offset < 0 || offset+headerSize > int64(len(s.data.data)) {
+		return
+	}
+	blockID, e.length, e.crc = decode(s.data.data[offset:])
+	e.offset = offset
+	start, stop := offset+headerSize, offset+headerSize+int64(e.length)
+	if blockID < 1 || e.length == 0 || stop > int64(len(s.data.data)) {
+		return
+	}
+	payload = s.data.data[start:stop]
+	return blockID, e, payload, crc32.ChecksumIEEE(payload) == e.crc
+}
+
+// recover scans the records of the data file. The scan stops at the first torn record, the
+// data after it is cut. The index entries of the records are fixed and the entries after the
+// last record are cleared
+func (s *BlockStore) recover() error {
+	var offset, last int64
+	for {
+		blockID, e, _, ok := s.record(offset)
+		if !ok || blockID <= last {
+			break
+		}
+		if cur, _ := s.entryAt(blockID); cur != e {
+			if err := s.setEntry(blockID, e); err != nil {
+				return err
+			}
+		}
+		last = blockID
+		offset += headerSize + int64(e.length)
+	}
+
+	// the tail is zeroed because it could contain the stale records
+	if err := s.data.resize(offset, roundUp(offset+1, dataGrowth)); err != nil {
+		return err
+	}
+	if err := s.index.resize(last*indexEntrySize, roundUp(last*indexEntrySize+1, indexGrowth)); err != nil {
+		return err
+	}
+	s.end, s.last = offset, last
+	return nil
+}
+
+// truncate removes the blocks starting from blockID, it's used when the block is written again
+// after the rollback or the fork
+func (s *BlockStore) truncate(blockID int64) error {
+	end := s.end
+	for id := blockID; id <= s.last; id++ {
+		if e, ok := s.entryAt(id); ok {
+			end = e.offset
+			break
+		}
+	}
+	for i := end; i < s.end; i++ {
+		s.data.data[i] = 0
+	}
+	start := (blockID - 1) * indexEntrySize
+	stop := s.last * indexEntrySize
+	if stop > int64(len(s.index.data)) {
+		stop = int64(len(s.index.data))
+	}
+	for i := start; i < stop; i++ {
+		s.index.data[i] = 0
+	}
+	s.end, s.last = end, blockID-1
+	return nil
+}
+
+// Write appends the block to the store. The blocks with the same or greater id are removed
+// if the block has been written already
+func (s *BlockStore) Write(b *Block) error {
+	if b.ID < 1 {
+		return fmt.Errorf("wrong block id %d", b.ID)
+	}
+	payload, err := msgpack.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return ErrClosed
+	}
+	if b.ID <= s.last {
+		if err = s.truncate(b.ID); err != nil {
+			return err
+		}
+	}
+
+	size := headerSize + int64(len(payload))
+	if err = s.data.ensure(s.end+size, dataGrowth); err != nil {
+		return err
+	}
+	e := entry{offset: s.end, length: uint32(len(payload)), crc: crc32.ChecksumIEEE(payload)}
+	copy(s.data.data[s.end+headerSize:], payload)
+	encode(s.data.data[s.end:], b.ID, e.length, e.crc)
+	if err = s.setEntry(b.ID, e); err != nil {
+		return err
+	}
+	s.end += size
+	s.last = b.ID
+	return nil
+}
+
+// Read returns the block by id
+func (s *BlockStore) Read(blockID int64) (*Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return nil, ErrClosed
+	}
+	e, ok := s.entryAt(blockID)
+	if !ok || blockID > s.last {
+		return nil, ErrNotFound
+	}
+	id, rec, payload, ok := s.record(e.offset)
+	if !ok || id != blockID || rec != e {
+		return nil, ErrCorrupted
+	}
+	b := &Block{}
+	if err := msgpack.Unmarshal(payload, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// LastID returns id of the last written block
+func (s *BlockStore) LastID() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.last
+}
+
+// Sync flushes the mapped files to the disk
+func (s *BlockStore) Sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return ErrClosed
+	}
+	if err := msync(s.data.data); err != nil {
+		return err
+	}
+	return msync(s.index.data)
+}
+
+// Close flushes and closes the files
+func (s *BlockStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return nil
+	}
+	err := s.data.close(s.end)
+	if e := s.index.close(s.last * indexEntrySize); err == nil {
+		err = e
+	}
+	s.data, s.index = nil, nil
+	return err
+}
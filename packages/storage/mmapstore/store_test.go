@@ -0,0 +1,110 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package mmapstore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testBlock(id int64, fork string) *Block {
+	return &Block{
+		ID:            id,
+		Hash:          []byte(fmt.Sprintf("hash%d%s", id, fork)),
+		RollbacksHash: []byte("rollbacks"),
+		Data:          bytes.Repeat([]byte{byte(id)}, 100+int(id)),
+		Time:          1000 + id,
+		Tx:            int32(id),
+	}
+}
+
+func writeBlocks(t *testing.T, s *BlockStore, from, to int64, fork string) {
+	for id := from; id <= to; id++ {
+		require.NoError(t, s.Write(testBlock(id, fork)))
+	}
+}
+
+func requireBlocks(t *testing.T, s *BlockStore, from, to int64, fork string) {
+	t.Helper()
+	for id := from; id <= to; id++ {
+		b, err := s.Read(id)
+		require.NoError(t, err, "block %d", id)
+		require.Equal(t, testBlock(id, fork), b)
+	}
+}
+
+func TestBlockStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	require.NoError(t, err)
+	writeBlocks(t, s, 1, 50, "")
+	requireBlocks(t, s, 1, 50, "")
+	_, err = s.Read(51)
+	require.Equal(t, ErrNotFound, err)
+
+	// the fork replaces the blocks after 40
+	writeBlocks(t, s, 41, 45, "fork")
+	require.Equal(t, int64(45), s.LastID())
+	requireBlocks(t, s, 1, 40, "")
+	requireBlocks(t, s, 41, 45, "fork")
+	_, err = s.Read(46)
+	require.Equal(t, ErrNotFound, err)
+	require.NoError(t, s.Close())
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(45), s.LastID())
+	requireBlocks(t, s, 41, 45, "fork")
+	writeBlocks(t, s, 46, 60, "fork")
+	requireBlocks(t, s, 1, 40, "")
+	requireBlocks(t, s, 41, 60, "fork")
+	require.NoError(t, s.Close())
+	_, err = s.Read(1)
+	require.Equal(t, ErrClosed, err)
+}
+
+func TestBlockStoreRecovery(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	require.NoError(t, err)
+	writeBlocks(t, s, 1, 20, "")
+	require.NoError(t, s.Close())
+
+	dataPath := filepath.Join(dir, DataFilename)
+	indexPath := filepath.Join(dir, IndexFilename)
+	info, err := os.Stat(dataPath)
+	require.NoError(t, err)
+
+	// the crash has torn the last record and has left the garbage in the index
+	require.NoError(t, os.Truncate(dataPath, info.Size()-10))
+	index, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	copy(index[5*indexEntrySize:], bytes.Repeat([]byte{0xff}, indexEntrySize))
+	require.NoError(t, os.WriteFile(indexPath, index, 0600))
+
+	s, err = Open(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(19), s.LastID())
+	requireBlocks(t, s, 1, 19, "")
+	_, err = s.Read(20)
+	require.Equal(t, ErrNotFound, err)
+
+	writeBlocks(t, s, 20, 25, "")
+	requireBlocks(t, s, 1, 25, "")
+	require.NoError(t, s.Close())
+
+	// the lost index is rebuilt from the data file
+	require.NoError(t, os.Remove(indexPath))
+	s, err = Open(dir)
+	require.NoError(t, err)
+	requireBlocks(t, s, 1, 25, "")
+	require.NoError(t, s.Close())
+}
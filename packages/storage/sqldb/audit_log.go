@@ -0,0 +1,165 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+const (
+	// AuditSysUpdate is applying changed platform parameters
+	AuditSysUpdate = "sys_update"
+	// AuditStopNetwork is executing stop network transaction
+	AuditStopNetwork = "stop_network"
+	// AuditKeyRotation is replacing the node signing key
+	AuditKeyRotation = "key_rotation"
+	// AuditKeyBan is banning the key by bad transactions
+	AuditKeyBan = "key_ban"
+	// AuditKeyBanLift is lifting the ban of the key by the node administrator
+	AuditKeyBanLift = "key_ban_lift"
+	// AuditRollback is rollback of the blockchain by node administrator
+	AuditRollback = "rollback"
+	// AuditAccountFreeze is freezing the account in the ecosystem
+	AuditAccountFreeze = "account_freeze"
+	// AuditAccountUnfreeze is unfreezing the account in the ecosystem
+	AuditAccountUnfreeze = "account_unfreeze"
+	// AuditRewardDestination is registering the destination of the rewards of the block producer
+	AuditRewardDestination = "reward_destination"
+	// AuditServiceKey is creating or revoking the api key of the service account
+	AuditServiceKey = "service_key"
+
+	auditVerifyBatch = 1000
+)
+
+var auditMutex = &sync.Mutex{}
+
+// AuditLog is model of the append-only log of privileged operations.
+// Every entry contains the hash of the previous one so the rows form a hash chain
+type AuditLog struct {
+	ID          int64  `gorm:"primary_key;not null" json:"id"`
+	Time        int64  `gorm:"not null" json:"time"`
+	Action      string `gorm:"not null" json:"action"`
+	KeyID       int64  `gorm:"not null" json:"key_id"`
+	PayloadHash []byte `gorm:"not null" json:"payload_hash"`
+	BlockID     int64  `gorm:"not null" json:"block_id"`
+	TxHash      []byte `gorm:"not null" json:"tx_hash"`
+	PrevHash    []byte `gorm:"not null" json:"prev_hash"`
+	Hash        []byte `gorm:"not null" json:"hash"`
+}
+
+// TableName returns name of table
+func (a *AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// GenHash returns hash of the entry fields including the hash of the previous entry
+func (a *AuditLog) GenHash() []byte {
+	return crypto.Hash([]byte(fmt.Sprintf("%d,%d,%s,%d,%x,%d,%x,%x",
+		a.ID, a.Time, a.Action, a.KeyID, a.PayloadHash, a.BlockID, a.TxHash, a.PrevHash)))
+}
+
+// NewAuditLog returns the entry for action with the hash of payload
+func NewAuditLog(action string, keyID, blockID int64, txHash, payload []byte) *AuditLog {
+	return &AuditLog{
+		Time:        time.Now().Unix(),
+		Action:      action,
+		KeyID:       keyID,
+		PayloadHash: crypto.Hash(payload),
+		BlockID:     blockID,
+		TxHash:      txHash,
+	}
+}
+
+// Create links the entry to the last one and appends it to the log
+func (a *AuditLog) Create(dbTx *DbTransaction) error {
+	auditMutex.Lock()
+	defer auditMutex.Unlock()
+
+	last := &AuditLog{}
+	found, err := isFound(GetDB(dbTx).Order("id desc").First(last))
+	if err != nil {
+		return err
+	}
+	a.ID = 1
+	a.PrevHash = []byte{}
+	if found {
+		a.ID = last.ID + 1
+		a.PrevHash = last.Hash
+	}
+	if a.TxHash == nil {
+		a.TxHash = []byte{}
+	}
+	a.Hash = a.GenHash()
+	return GetDB(dbTx).Create(a).Error
+}
+
+// GetAuditLogs returns entries filtered by action and time range
+func GetAuditLogs(action string, from, to int64, offset, limit int) ([]AuditLog, int64, error) {
+	var (
+		list  []AuditLog
+		total int64
+	)
+	q := DBConn.Model(&AuditLog{})
+	if len(action) > 0 {
+		q = q.Where("action = ?", action)
+	}
+	if from > 0 {
+		q = q.Where("time >= ?", from)
+	}
+	if to > 0 {
+		q = q.Where("time <= ?", to)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := q.Order("id asc").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
+
+// VerifyAuditChain checks entries which follow the entry with prevID and prevHash.
+// It returns the id of the first broken or missing entry or zero if the chain is valid
+func VerifyAuditChain(prevID int64, prevHash []byte, list []AuditLog) int64 {
+	for i := range list {
+		item := &list[i]
+		if item.ID != prevID+1 {
+			return prevID + 1
+		}
+		if !bytes.Equal(item.PrevHash, prevHash) || !bytes.Equal(item.Hash, item.GenHash()) {
+			return item.ID
+		}
+		prevID, prevHash = item.ID, item.Hash
+	}
+	return 0
+}
+
+// VerifyAuditLog walks the whole audit log and returns the id of the first broken entry,
+// zero means the hash chain is valid. The second value is the count of valid entries
+func VerifyAuditLog() (int64, int64, error) {
+	var (
+		lastID   int64
+		prevHash = []byte{}
+	)
+	for {
+		var list []AuditLog
+		err := DBConn.Where("id > ?", lastID).Order("id asc").Limit(auditVerifyBatch).Find(&list).Error
+		if err != nil {
+			return 0, lastID, err
+		}
+		if len(list) == 0 {
+			return 0, lastID, nil
+		}
+		if broken := VerifyAuditChain(lastID, prevHash, list); broken != 0 {
+			return broken, broken - 1, nil
+		}
+		last := list[len(list)-1]
+		lastID, prevHash = last.ID, last.Hash
+	}
+}
@@ -0,0 +1,49 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAuditChain(actions ...string) []AuditLog {
+	list := make([]AuditLog, 0, len(actions))
+	prevHash := []byte{}
+	for i, action := range actions {
+		a := NewAuditLog(action, int64(i+1), int64(i+10), []byte{byte(i)}, []byte(action))
+		a.ID = int64(i + 1)
+		a.PrevHash = prevHash
+		a.Hash = a.GenHash()
+		prevHash = a.Hash
+		list = append(list, *a)
+	}
+	return list
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	actions := []string{AuditSysUpdate, AuditStopNetwork, AuditKeyBan, AuditRollback, AuditKeyRotation}
+
+	list := testAuditChain(actions...)
+	assert.Equal(t, int64(0), VerifyAuditChain(0, []byte{}, list))
+	assert.Equal(t, int64(0), VerifyAuditChain(2, list[1].Hash, list[2:]))
+
+	tampered := testAuditChain(actions...)
+	tampered[2].KeyID = 100
+	assert.Equal(t, int64(3), VerifyAuditChain(0, []byte{}, tampered))
+
+	rehashed := testAuditChain(actions...)
+	rehashed[1].Action = AuditKeyBan
+	rehashed[1].Hash = rehashed[1].GenHash()
+	assert.Equal(t, int64(3), VerifyAuditChain(0, []byte{}, rehashed))
+
+	deleted := testAuditChain(actions...)
+	deleted = append(deleted[:3], deleted[4:]...)
+	assert.Equal(t, int64(4), VerifyAuditChain(0, []byte{}, deleted))
+}
@@ -0,0 +1,147 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultMaxBinLogStatementBytes is the default limit of the statement of the binlog
+const DefaultMaxBinLogStatementBytes = 1 << 20
+
+// ErrBinLogStatementSize is returned by ExecSql for the statement over the limit which can't be split
+var ErrBinLogStatementSize = errors.New("binlog statement is too large")
+
+var maxBinLogStatement atomic.Int64
+
+func init() {
+	maxBinLogStatement.Store(DefaultMaxBinLogStatementBytes)
+}
+
+// SetMaxBinLogStatementBytes sets the limit of the statement of the binlog, zero disables the limit
+func SetMaxBinLogStatementBytes(limit int) {
+	maxBinLogStatement.Store(int64(limit))
+}
+
+// binLogStatements returns the statements of the binlog which are equivalent to stmt. The statement
+// over the limit is split by the rows of the multi-row insert or by the list of IN of the condition
+// of update and delete
+func (dbTx *DbTransaction) binLogStatements(stmt string) ([]string, error) {
+	limit := int(maxBinLogStatement.Load())
+	if limit <= 0 || len(stmt) <= limit {
+		return []string{stmt}, nil
+	}
+	list, ok := splitBinLogSQL(stmt, limit)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d bytes over %d", ErrBinLogStatementSize, len(stmt), limit)
+	}
+	dbTx.GetLogger().WithFields(log.Fields{"size": len(stmt), "limit": limit, "statements": len(list)}).
+		Debug("splitting binlog statement")
+	return list, nil
+}
+
+// splitBinLogSQL splits the statement into the statements under the limit, it returns false if the
+// statement can't be split
+func splitBinLogSQL(stmt string, limit int) ([]string, bool) {
+	stmt = strings.TrimRight(strings.TrimSpace(stmt), ";")
+	switch {
+	case hasPrefixFold(stmt, "INSERT INTO "):
+		return splitInsert(stmt, limit)
+	case hasPrefixFold(stmt, "UPDATE "), hasPrefixFold(stmt, "DELETE FROM "):
+		return splitInList(stmt, limit)
+	}
+	return nil, false
+}
+
+// splitInsert splits the rows of VALUES, the clauses after the rows go with every statement
+func splitInsert(stmt string, limit int) ([]string, bool) {
+	values := indexTop(stmt, " VALUES ")
+	if values < 0 {
+		return nil, false
+	}
+	head, rows := stmt[:values+len(" VALUES ")], strings.TrimSpace(stmt[values+len(" VALUES "):])
+	var list []string
+	for len(rows) > 0 {
+		row, rest, ok := cutParens(rows)
+		if !ok {
+			return nil, false
+		}
+		list = append(list, "("+row+")")
+		rows = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rows, ",") {
+			break
+		}
+		rows = strings.TrimSpace(rows[1:])
+	}
+	tail := ""
+	if len(rows) > 0 {
+		tail = " " + rows
+	}
+	return joinChunks(head, list, ",", tail, limit)
+}
+
+// splitInList splits the list of the condition `column IN (...)` of the top level of WHERE
+func splitInList(stmt string, limit int) ([]string, bool) {
+	where := indexTop(stmt, " WHERE ")
+	if where < 0 {
+		return nil, false
+	}
+	conds := splitTop(stmt[where+len(" WHERE "):], " AND ")
+	for i, cond := range conds {
+		cond = trimParens(cond)
+		in := indexTop(cond, " IN ")
+		if in <= 0 || strings.ContainsAny(cond[:in], "'()") {
+			continue
+		}
+		items, rest, ok := cutParens(strings.TrimSpace(cond[in+len(" IN "):]))
+		if !ok || len(strings.TrimSpace(rest)) > 0 {
+			continue
+		}
+		others := strings.Join(conds[i+1:], " AND ")
+		if len(others) > 0 {
+			others = ") AND " + others
+		} else {
+			others = ")"
+		}
+		head := stmt[:where+len(" WHERE ")] + strings.Join(conds[:i], " AND ")
+		if i > 0 {
+			head += " AND "
+		}
+		return joinChunks(head+"("+cond[:in]+" IN (", splitTop(items, ","), ",", ")"+others, limit)
+	}
+	return nil, false
+}
+
+// joinChunks joins the items into the statements head+items+tail under the limit
+func joinChunks(head string, items []string, sep, tail string, limit int) ([]string, bool) {
+	var (
+		list  []string
+		chunk strings.Builder
+	)
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if len(head)+len(item)+len(tail) > limit {
+			return nil, false
+		}
+		if chunk.Len() > 0 && len(head)+chunk.Len()+len(sep)+len(item)+len(tail) > limit {
+			list = append(list, head+chunk.String()+tail)
+			chunk.Reset()
+		}
+		if chunk.Len() > 0 {
+			chunk.WriteString(sep)
+		}
+		chunk.WriteString(item)
+	}
+	if chunk.Len() == 0 {
+		return nil, false
+	}
+	return append(list, head+chunk.String()+tail), true
+}
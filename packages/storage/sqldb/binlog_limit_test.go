@@ -0,0 +1,83 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBinLogStatements(t *testing.T) {
+	defer SetMaxBinLogStatementBytes(DefaultMaxBinLogStatementBytes)
+	SetMaxBinLogStatementBytes(120)
+	ids := make([]string, 40)
+	rows := make([]string, 10)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("'%d'", i+1)
+	}
+	for i := range rows {
+		rows[i] = fmt.Sprintf("('%d', 'name %d')", i+1, i+1)
+	}
+	dbTx := &DbTransaction{}
+	for _, c := range []struct {
+		stmt, prefix, suffix string
+		items                []string
+	}{
+		{
+			`UPDATE "1_keys" SET amount=amount+'10' WHERE ("ecosystem" = '1') AND ("id" IN (` + strings.Join(ids, ",") + `))`,
+			`UPDATE "1_keys" SET amount=amount+'10' WHERE ("ecosystem" = '1') AND ("id" IN (`, `))`, ids,
+		},
+		{
+			`DELETE FROM "1_notifications" WHERE id IN (` + strings.Join(ids, ", ") + `) AND "ecosystem" = '1'`,
+			`DELETE FROM "1_notifications" WHERE (id IN (`, `)) AND "ecosystem" = '1'`, ids,
+		},
+		{
+			`INSERT INTO "1_parameters" (id, name) VALUES ` + strings.Join(rows, ", "),
+			`INSERT INTO "1_parameters" (id, name) VALUES `, ``, rows,
+		},
+	} {
+		list, err := dbTx.binLogStatements(c.stmt)
+		if err != nil {
+			t.Fatalf("%s: %v", c.stmt, err)
+		}
+		if len(list) < 2 {
+			t.Fatalf("%s isn't split", c.stmt)
+		}
+		var items []string
+		for _, stmt := range list {
+			if len(stmt) > 120 || !strings.HasPrefix(stmt, c.prefix) || !strings.HasSuffix(stmt, c.suffix) {
+				t.Fatalf("wrong statement %s", stmt)
+			}
+			for _, item := range splitTop(stmt[len(c.prefix):len(stmt)-len(c.suffix)], ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+		if !reflect.DeepEqual(items, c.items) {
+			t.Errorf("%s: expected %v, got %v", c.stmt, c.items, items)
+		}
+	}
+
+	short := `UPDATE "1_keys" SET amount='1' WHERE id = '1'`
+	if list, err := dbTx.binLogStatements(short); err != nil || len(list) != 1 || list[0] != short {
+		t.Errorf("the short statement is changed %v %v", list, err)
+	}
+	for _, stmt := range []string{
+		`UPDATE "1_keys" SET info='` + strings.Repeat("a", 200) + `' WHERE id = '1'`,
+		`UPDATE "1_keys" SET amount='1' WHERE id IN ('1', '` + strings.Repeat("2", 200) + `')`,
+		`CREATE TABLE "t" (` + strings.Repeat("a", 200) + `)`,
+	} {
+		if _, err := dbTx.binLogStatements(stmt); !errors.Is(err, ErrBinLogStatementSize) {
+			t.Errorf("expected %v, got %v", ErrBinLogStatementSize, err)
+		}
+	}
+	SetMaxBinLogStatementBytes(0)
+	if list, err := dbTx.binLogStatements(strings.Repeat("a", 200)); err != nil || len(list) != 1 {
+		t.Errorf("the disabled limit splits the statement %v %v", list, err)
+	}
+}
@@ -0,0 +1,80 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// FeeStatsWindow is the number of the last blocks which are kept in block_fee_stats
+const FeeStatsWindow = 100
+
+// BlockFeeStats is model of the effective gas prices of the transactions of one type in the block
+type BlockFeeStats struct {
+	BlockID   int64  `gorm:"primary_key;not null" json:"block_id"`
+	TxType    int    `gorm:"primary_key;not null" json:"tx_type"`
+	TxCount   int    `gorm:"not null" json:"tx_count"`
+	GasPrices string `gorm:"not null" json:"gas_prices"` // json array sorted ascending
+}
+
+// TableName returns name of table
+func (s *BlockFeeStats) TableName() string {
+	return "block_fee_stats"
+}
+
+// Prices returns the sorted gas prices of the transactions
+func (s *BlockFeeStats) Prices() ([]int64, error) {
+	var prices []int64
+	if len(s.GasPrices) == 0 {
+		return prices, nil
+	}
+	err := json.Unmarshal([]byte(s.GasPrices), &prices)
+	return prices, err
+}
+
+// SaveBlockFeeStats replaces the stats of the block with prices by transaction type and
+// deletes the blocks which are out of FeeStatsWindow
+func SaveBlockFeeStats(dbTx *DbTransaction, blockID int64, prices map[int][]int64) error {
+	db := GetDB(dbTx)
+	if err := DeleteBlockFeeStats(dbTx, blockID); err != nil {
+		return err
+	}
+	for txType, list := range prices {
+		sorted := append([]int64(nil), list...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		data, err := json.Marshal(sorted)
+		if err != nil {
+			return err
+		}
+		stats := &BlockFeeStats{BlockID: blockID, TxType: txType, TxCount: len(sorted), GasPrices: string(data)}
+		if err = db.Create(stats).Error; err != nil {
+			return err
+		}
+	}
+	return db.Where("block_id <= ?", blockID-FeeStatsWindow).Delete(&BlockFeeStats{}).Error
+}
+
+// DeleteBlockFeeStats deletes the stats of the block
+func DeleteBlockFeeStats(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Where("block_id = ?", blockID).Delete(&BlockFeeStats{}).Error
+}
+
+// GetBlockFeeStats returns the stats of txType for the blocks after fromBlockID ordered by block_id
+func GetBlockFeeStats(txType int, fromBlockID int64) ([]BlockFeeStats, error) {
+	var list []BlockFeeStats
+	err := DBConn.Where("tx_type = ? AND block_id > ?", txType, fromBlockID).Order("block_id asc").Find(&list).Error
+	return list, err
+}
+
+// GetPendingTxCount returns the number of the unused transactions of txType in the mempool
+// which have expedite greater than or equal to expedite. They are included into the blocks
+// before the transaction with expedite
+func GetPendingTxCount(txType int, expedite string) (int64, error) {
+	var count int64
+	err := DBConn.Model(&Transaction{}).Where("used = ? AND type = ? AND expedite >= ?", 0, txType, expedite).Count(&count).Error
+	return count, err
+}
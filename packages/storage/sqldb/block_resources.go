@@ -0,0 +1,230 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The resolutions of block_resources, the row of the block is down-sampled to the rows of the hours
+// and then of the days
+const (
+	ResolutionBlock = "block"
+	ResolutionHour  = "hour"
+	ResolutionDay   = "day"
+)
+
+// BlockResources is model of the resources of the played block or the sums and the maxima of the
+// blocks of the period. The table is local to the node, the execution time depends on the hardware
+type BlockResources struct {
+	BlockID          int64         `gorm:"primary_key;not null" json:"block_id"` // the first block of the period
+	LastBlockID      int64         `gorm:"not null" json:"last_block_id"`
+	PeriodStart      time.Time     `gorm:"not null" json:"period_start"` // the block time for the row of the block
+	Resolution       string        `gorm:"not null" json:"resolution"`
+	Blocks           int64         `gorm:"not null" json:"blocks"`
+	TxTypes          string        `gorm:"not null" json:"-"` // json object of TxCounts
+	TxCount          int64         `gorm:"not null" json:"tx_count"`
+	TxCountMax       int64         `gorm:"not null" json:"tx_count_max"`
+	Size             int64         `gorm:"not null" json:"size"`
+	SizeMax          int64         `gorm:"not null" json:"size_max"`
+	Fuel             int64         `gorm:"not null" json:"fuel"`
+	FuelMax          int64         `gorm:"not null" json:"fuel_max"`
+	ExecNs           int64         `gorm:"not null" json:"exec_ns"`
+	ExecNsMax        int64         `gorm:"not null" json:"exec_ns_max"`
+	Savepoints       int64         `gorm:"not null" json:"savepoints"`
+	SavepointsMax    int64         `gorm:"not null" json:"savepoints_max"`
+	Notifications    int64         `gorm:"not null" json:"notifications"`
+	NotificationsMax int64         `gorm:"not null" json:"notifications_max"`
+	TxCounts         map[int]int64 `gorm:"-" json:"tx_types"` // the transactions by type
+}
+
+// TableName returns name of table
+func (r *BlockResources) TableName() string {
+	return "block_resources"
+}
+
+// IsResolution returns true if the resources can be aggregated by the resolution
+func IsResolution(resolution string) bool {
+	return resolution == ResolutionBlock || resolution == ResolutionHour || resolution == ResolutionDay
+}
+
+// PeriodStart returns the start of the period of the resolution which contains t
+func PeriodStart(t time.Time, resolution string) time.Time {
+	t = t.UTC()
+	switch resolution {
+	case ResolutionHour:
+		return t.Truncate(time.Hour)
+	case ResolutionDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t
+}
+
+// NewBlockResources returns the row of the block, its sums are the maxima
+func NewBlockResources(blockID int64, t time.Time, txCounts map[int]int64, size, fuel, execNs, savepoints, notifications int64) *BlockResources {
+	r := &BlockResources{
+		BlockID:       blockID,
+		LastBlockID:   blockID,
+		PeriodStart:   t.UTC(),
+		Resolution:    ResolutionBlock,
+		Blocks:        1,
+		Size:          size,
+		Fuel:          fuel,
+		ExecNs:        execNs,
+		Savepoints:    savepoints,
+		Notifications: notifications,
+		TxCounts:      make(map[int]int64, len(txCounts)),
+	}
+	for txType, count := range txCounts {
+		r.TxCounts[txType] = count
+		r.TxCount += count
+	}
+	r.TxCountMax, r.SizeMax, r.FuelMax = r.TxCount, size, fuel
+	r.ExecNsMax, r.SavepointsMax, r.NotificationsMax = execNs, savepoints, notifications
+	return r
+}
+
+// Merge adds the blocks of o
+func (r *BlockResources) Merge(o *BlockResources) {
+	if r.Blocks == 0 || o.BlockID < r.BlockID {
+		r.BlockID = o.BlockID
+	}
+	if o.LastBlockID > r.LastBlockID {
+		r.LastBlockID = o.LastBlockID
+	}
+	r.Blocks += o.Blocks
+	sumMax := func(sum, max *int64, oSum, oMax int64) {
+		*sum += oSum
+		if oMax > *max {
+			*max = oMax
+		}
+	}
+	sumMax(&r.TxCount, &r.TxCountMax, o.TxCount, o.TxCountMax)
+	sumMax(&r.Size, &r.SizeMax, o.Size, o.SizeMax)
+	sumMax(&r.Fuel, &r.FuelMax, o.Fuel, o.FuelMax)
+	sumMax(&r.ExecNs, &r.ExecNsMax, o.ExecNs, o.ExecNsMax)
+	sumMax(&r.Savepoints, &r.SavepointsMax, o.Savepoints, o.SavepointsMax)
+	sumMax(&r.Notifications, &r.NotificationsMax, o.Notifications, o.NotificationsMax)
+	if r.TxCounts == nil {
+		r.TxCounts = make(map[int]int64, len(o.TxCounts))
+	}
+	for txType, count := range o.TxCounts {
+		r.TxCounts[txType] += count
+	}
+}
+
+// AggregateBlockResources merges the rows ordered by period_start into the rows of the periods of
+// the resolution. The row of the coarser resolution is merged into the period of its start
+func AggregateBlockResources(list []BlockResources, resolution string) []*BlockResources {
+	var (
+		result []*BlockResources
+		cur    *BlockResources
+	)
+	for i := range list {
+		start := PeriodStart(list[i].PeriodStart, resolution)
+		if cur == nil || !cur.PeriodStart.Equal(start) {
+			cur = &BlockResources{PeriodStart: start, Resolution: resolution}
+			result = append(result, cur)
+		}
+		cur.Merge(&list[i])
+	}
+	return result
+}
+
+func (r *BlockResources) encodeTxTypes() error {
+	data, err := json.Marshal(r.TxCounts)
+	if err != nil {
+		return err
+	}
+	r.TxTypes = string(data)
+	return nil
+}
+
+func (r *BlockResources) decodeTxTypes() error {
+	r.TxCounts = make(map[int]int64)
+	if len(r.TxTypes) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(r.TxTypes), &r.TxCounts)
+}
+
+// SaveBlockResources replaces the row of the block
+func SaveBlockResources(dbTx *DbTransaction, r *BlockResources) error {
+	if err := r.encodeTxTypes(); err != nil {
+		return err
+	}
+	return GetDB(dbTx).Clauses(clause.OnConflict{UpdateAll: true}).Create(r).Error
+}
+
+// DeleteBlockResources deletes the row of the block, the down-sampled rows are kept
+func DeleteBlockResources(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Where("block_id = ? AND resolution = ?", blockID, ResolutionBlock).Delete(&BlockResources{}).Error
+}
+
+// GetBlockResources returns the rows of the periods which start within [from, to) ordered by
+// period_start, limit is the maximum of the rows, zero is no limit
+func GetBlockResources(from, to time.Time, limit int) ([]BlockResources, error) {
+	query := DBConn.Where("period_start >= ? AND period_start < ?", from.UTC(), to.UTC()).
+		Order("period_start asc, block_id asc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	return findBlockResources(query)
+}
+
+func findBlockResources(query *gorm.DB) ([]BlockResources, error) {
+	var list []BlockResources
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if err := list[i].decodeTxTypes(); err != nil {
+			return nil, fmt.Errorf("decoding tx types of block %d: %w", list[i].BlockID, err)
+		}
+	}
+	return list, nil
+}
+
+// DownsampleBlockResources replaces the rows of the resolution from which are older than before with
+// the rows of the resolution to. Only the complete periods are down-sampled, the row of the period
+// which has been down-sampled earlier is merged with the new rows of the period
+func DownsampleBlockResources(dbTx *DbTransaction, before time.Time, from, to string) error {
+	before = PeriodStart(before, to)
+	return GetDB(dbTx).Transaction(func(db *gorm.DB) error {
+		list, err := findBlockResources(db.Where("resolution = ? AND period_start < ?", from, before).
+			Order("period_start asc, block_id asc"))
+		if err != nil || len(list) == 0 {
+			return err
+		}
+		if err = db.Where("resolution = ? AND period_start < ?", from, before).Delete(&BlockResources{}).Error; err != nil {
+			return err
+		}
+		for _, row := range AggregateBlockResources(list, to) {
+			prev, err := findBlockResources(db.Where("resolution = ? AND period_start = ?", to, row.PeriodStart))
+			if err != nil {
+				return err
+			}
+			for i := range prev {
+				row.Merge(&prev[i])
+				if err = db.Where("block_id = ?", prev[i].BlockID).Delete(&BlockResources{}).Error; err != nil {
+					return err
+				}
+			}
+			if err = row.encodeTxTypes(); err != nil {
+				return err
+			}
+			if err = db.Create(row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateBlockResources(t *testing.T) {
+	start := time.Date(2024, 3, 10, 22, 0, 0, 0, time.UTC)
+	list := []BlockResources{
+		*NewBlockResources(10, start.Add(time.Minute), map[int]int64{1: 2, 5: 1}, 300, 1000, 50, 3, 1),
+		*NewBlockResources(11, start.Add(30*time.Minute), map[int]int64{1: 1}, 100, 4000, 20, 1, 0),
+		*NewBlockResources(12, start.Add(time.Hour+time.Second), map[int]int64{5: 4}, 900, 500, 70, 4, 2),
+		*NewBlockResources(13, start.Add(2*time.Hour+time.Second), nil, 10, 0, 5, 0, 0),
+	}
+	assert.Equal(t, int64(3), list[0].TxCount)
+	assert.Equal(t, list[0].Fuel, list[0].FuelMax)
+
+	hours := AggregateBlockResources(list, ResolutionHour)
+	if assert.Len(t, hours, 3) {
+		h := hours[0]
+		assert.Equal(t, start, h.PeriodStart)
+		assert.Equal(t, ResolutionHour, h.Resolution)
+		assert.Equal(t, int64(10), h.BlockID)
+		assert.Equal(t, int64(11), h.LastBlockID)
+		assert.Equal(t, int64(2), h.Blocks)
+		assert.Equal(t, map[int]int64{1: 3, 5: 1}, h.TxCounts)
+		assert.Equal(t, int64(4), h.TxCount)
+		assert.Equal(t, int64(3), h.TxCountMax)
+		assert.Equal(t, int64(400), h.Size)
+		assert.Equal(t, int64(300), h.SizeMax)
+		assert.Equal(t, int64(5000), h.Fuel)
+		assert.Equal(t, int64(4000), h.FuelMax)
+		assert.Equal(t, int64(70), h.ExecNs)
+		assert.Equal(t, int64(50), h.ExecNsMax)
+		assert.Equal(t, int64(4), h.Savepoints)
+		assert.Equal(t, int64(1), h.NotificationsMax)
+		assert.Equal(t, start.Add(time.Hour), hours[1].PeriodStart)
+		assert.Equal(t, int64(1), hours[2].Blocks)
+	}
+
+	// the hours are merged into the day as the blocks are, the next day starts at block 13
+	days := AggregateBlockResources(append(derefs(hours[:2]), list[3]), ResolutionDay)
+	if assert.Len(t, days, 2) {
+		d := days[0]
+		assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), d.PeriodStart)
+		assert.Equal(t, int64(3), d.Blocks)
+		assert.Equal(t, int64(12), d.LastBlockID)
+		assert.Equal(t, map[int]int64{1: 3, 5: 5}, d.TxCounts)
+		assert.Equal(t, int64(1300), d.Size)
+		assert.Equal(t, int64(900), d.SizeMax)
+		assert.Equal(t, int64(5500), d.Fuel)
+		assert.Equal(t, int64(4000), d.FuelMax)
+		assert.Equal(t, int64(140), d.ExecNs)
+		assert.Equal(t, int64(70), d.ExecNsMax)
+		assert.Equal(t, int64(13), days[1].BlockID)
+		assert.Equal(t, int64(1), days[1].Blocks)
+	}
+	assert.Empty(t, AggregateBlockResources(nil, ResolutionDay))
+}
+
+func derefs(list []*BlockResources) []BlockResources {
+	result := make([]BlockResources, len(list))
+	for i, r := range list {
+		result[i] = *r
+	}
+	return result
+}
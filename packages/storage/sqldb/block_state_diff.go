@@ -0,0 +1,77 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm/clause"
+)
+
+// BlockStateDiff is model of the state diff of the block which is parsed from its binlog. The hash of
+// the block is kept, so the diff of the block which has been replaced by the fork is parsed again
+type BlockStateDiff struct {
+	BlockID   int64  `gorm:"primary_key;not null"`
+	BlockHash []byte `gorm:"not null"`
+	Diffs     string `gorm:"not null;type:jsonb"`
+}
+
+// TableName returns name of table
+func (BlockStateDiff) TableName() string {
+	return "block_state_diffs"
+}
+
+// GetBlockStateDiffs returns the state diff of the block, found is false if there is no such block.
+// The statements which don't change the rows are skipped
+func GetBlockStateDiffs(blockID int64) (diffs []StateDiff, found bool, err error) {
+	block := &BlockChain{}
+	if found, err = isFound(DBConn.Select("id, hash, bin_log_sql, pruned").Where("id = ?", blockID).First(block)); !found || err != nil {
+		return nil, found, err
+	}
+
+	cached := &BlockStateDiff{}
+	ok, err := isFound(DBConn.Where("block_id = ?", blockID).First(cached))
+	if err != nil {
+		return nil, true, err
+	}
+	if ok && bytes.Equal(cached.BlockHash, block.Hash) {
+		err = json.Unmarshal([]byte(cached.Diffs), &diffs)
+		return diffs, true, err
+	}
+	// the binlog of the pruned block is deleted, only the parsed diff is kept
+	if block.Pruned {
+		return nil, true, fmt.Errorf("%w: block %d", ErrPruned, blockID)
+	}
+
+	stmts, err := UnmarshalBinLogSQL(block.BinLogSql)
+	if err != nil {
+		return nil, true, fmt.Errorf("unmarshalling binlog of block %d: %w", blockID, err)
+	}
+	diffs = make([]StateDiff, 0, len(stmts))
+	for i, stmt := range stmts {
+		diff, err := ParseBinLogSQL(stmt)
+		if errors.Is(err, ErrNotDML) {
+			continue
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("parsing statement %d of block %d: %w", i, blockID, err)
+		}
+		diffs = append(diffs, *diff)
+	}
+	data, err := json.Marshal(diffs)
+	if err != nil {
+		return nil, true, err
+	}
+	err = DBConn.Clauses(clause.OnConflict{UpdateAll: true}).Create(&BlockStateDiff{
+		BlockID:   blockID,
+		BlockHash: block.Hash,
+		Diffs:     string(data),
+	}).Error
+	return diffs, true, err
+}
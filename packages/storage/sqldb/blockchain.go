@@ -6,7 +6,13 @@
 package sqldb
 
 import (
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/storage/dastore"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"gorm.io/gorm"
 )
 
 // BlockChain is model
@@ -22,13 +28,47 @@ type BlockChain struct {
 	Tx             int32  `gorm:"not null"`
 	ConsensusMode  int32  `gorm:"not null"`
 	CandidateNodes []byte `gorm:"not null;default:null"`
+	BinLogSql      []byte `gorm:"column:bin_log_sql"` // the compressed DML statements of the played transactions
+	TxData         []byte `gorm:"column:tx_data"`     // the commitment of the block in the data availability layer, Data has no transactions then
+	BaseGasPrice   int64  `gorm:"not null"`
+	GasUsed        int64  `gorm:"not null"` // the fuel of the played transactions
+	RandomBeacon   []byte `gorm:"column:random_beacon"`
+	RandomCommit   []byte `gorm:"column:random_commit"` // the hash of the secret which the next block of the key reveals
+	StateLeaves    []byte `gorm:"column:state_leaves"`  // the leaves of the state root of the block one after another
+	EventsBloom    []byte `gorm:"column:events_bloom"`  // the bloom of the events of the block which is in its header
+	Pruned         bool   `gorm:"not null"`             // Data has the header only, the payload of the block is deleted
 }
 
+// ErrPruned is returned for the payload of the block which is deleted by the pruned node
+var ErrPruned = errors.New("block is pruned")
+
 // TableName returns name of table
 func (BlockChain) TableName() string {
 	return "block_chain"
 }
 
+// AfterFind decompresses the binary of the block, the blocks which are stored before the compression is
+// enabled are kept as is
+func (b *BlockChain) AfterFind(db *gorm.DB) (err error) {
+	b.Data, err = types.DecompressBlock(b.Data, 0)
+	return
+}
+
+// BlockData returns the binary of the block with the transactions, they are retrieved from the data
+// availability layer if the block keeps the commitment
+func (b *BlockChain) BlockData() ([]byte, error) {
+	if b.Pruned {
+		return nil, fmt.Errorf("%w: block %d", ErrPruned, b.ID)
+	}
+	if len(b.TxData) == 0 {
+		return b.Data, nil
+	}
+	if dastore.DA == nil {
+		return nil, fmt.Errorf("%w: block %d", dastore.ErrDisabled, b.ID)
+	}
+	return dastore.DA.Retrieve(b.TxData)
+}
+
 // Create is creating record of model
 func (b *BlockChain) Create(dbTx *DbTransaction) error {
 	return GetDB(dbTx).Create(b).Error
@@ -39,6 +79,11 @@ func (b *BlockChain) Get(blockID int64) (bool, error) {
 	return isFound(DBConn.Where("id = ?", blockID).First(b))
 }
 
+// GetTransaction is retrieving model from database in the transaction
+func (b *BlockChain) GetTransaction(dbTx *DbTransaction, blockID int64) (bool, error) {
+	return isFound(GetDB(dbTx).Where("id = ?", blockID).First(b))
+}
+
 // GetByHash is retrieving model from database
 func (b *BlockChain) GetByHash(BlockHash []byte) (bool, error) {
 	return isFound(DBConn.Where("hash = ?", BlockHash).First(b))
@@ -49,6 +94,11 @@ func (b *BlockChain) GetMaxBlock() (bool, error) {
 	return isFound(DBConn.Last(b))
 }
 
+// GetLastOfKey returns the last block generated by key_id before the block beforeID
+func (b *BlockChain) GetLastOfKey(keyID, beforeID int64) (bool, error) {
+	return isFound(DBConn.Order("id DESC").Where("key_id = ? AND id < ?", keyID, beforeID).First(b))
+}
+
 // GetMaxForeignBlock returns last block generated not by key_id
 func (b *BlockChain) GetMaxForeignBlock(keyId int64) (bool, error) {
 	return isFound(DBConn.Order("id DESC").Where("key_id != ?", keyId).First(b))
@@ -112,6 +162,39 @@ func (b *BlockChain) GetNodeBlocksAtTime(from, to time.Time, node int64) ([]Bloc
 	return *blockchain, err
 }
 
+// BlockBloom is the bloom of the events of the block
+type BlockBloom struct {
+	ID          int64
+	EventsBloom []byte
+}
+
+// GetEventsBlooms returns the blooms of the events of the blocks from fromID to toID inclusive,
+// the blocks without the bloom are skipped
+func GetEventsBlooms(fromID, toID int64) ([]BlockBloom, error) {
+	var list []BlockBloom
+	err := DBConn.Table(BlockChain{}.TableName()).Select("id, events_bloom").
+		Where("id >= ? AND id <= ? AND events_bloom IS NOT NULL", fromID, toID).Order("id asc").Scan(&list).Error
+	return list, err
+}
+
+// GetPrunedHeight returns the last pruned block, zero if no block is pruned
+func GetPrunedHeight() (int64, error) {
+	var height int64
+	err := DBConn.Raw(`SELECT COALESCE(MAX(id), 0) FROM block_chain WHERE pruned`).Row().Scan(&height)
+	return height, err
+}
+
+// Prune replaces the binary of the block with data without the transactions and deletes the payload
+// of the block. The hash, the header columns and the state leaves are kept
+func (b *BlockChain) Prune(dbTx *DbTransaction, data []byte) error {
+	return GetDB(dbTx).Model(&BlockChain{}).Where("id = ?", b.ID).Updates(map[string]any{
+		"data":        data,
+		"tx_data":     nil,
+		"bin_log_sql": nil,
+		"pruned":      true,
+	}).Error
+}
+
 // DeleteById is deleting block by ID
 func (b *BlockChain) DeleteById(dbTx *DbTransaction, id int64) error {
 	return GetDB(dbTx).Where("id = ?", id).Delete(BlockChain{}).Error
@@ -0,0 +1,41 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"time"
+)
+
+// Checkpoint is model of the block which is finalized by the attestations of the honor nodes, the chain
+// isn't rolled back below it. The table is local to the node, Attestations is the JSON of the attestations
+type Checkpoint struct {
+	BlockID      int64     `gorm:"primary_key;not null"`
+	Hash         []byte    `gorm:"not null"`
+	Attestations []byte    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (c *Checkpoint) TableName() string {
+	return "checkpoints"
+}
+
+// Create saves the checkpoint, the existing checkpoint of the block is kept
+func (c *Checkpoint) Create() error {
+	return DBConn.Where("block_id = ?", c.BlockID).FirstOrCreate(c).Error
+}
+
+// Get returns the checkpoint of the block
+func (c *Checkpoint) Get(blockID int64) (bool, error) {
+	return isFound(DBConn.Where("block_id = ?", blockID).First(c))
+}
+
+// GetLastCheckpoint returns the checkpoint with the greatest block
+func GetLastCheckpoint() (*Checkpoint, bool, error) {
+	c := &Checkpoint{}
+	found, err := isFound(DBConn.Order("block_id desc").First(c))
+	return c, found, err
+}
@@ -18,6 +18,7 @@ type Contract struct {
 	Conditions  string `json:"conditions,omitempty"`
 	AppID       int64  `json:"app_id,omitempty"`
 	EcosystemID int64  `gorm:"column:ecosystem" json:"ecosystem_id,omitempty"`
+	Deleted     int64  `json:"deleted,omitempty"` // the block time of the soft delete, -1 if the contract is purged
 }
 
 // TableName returns name of table
@@ -0,0 +1,82 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+// ContractEvent is model of the event emitted by the contract of the transaction with EmitEvent.
+// LogIndex is the order of the event in the transaction
+type ContractEvent struct {
+	BlockID      int64  `gorm:"not null"`
+	TxHash       []byte `gorm:"primary_key;not null"`
+	LogIndex     int64  `gorm:"primary_key;not null"`
+	EcosystemID  int64  `gorm:"not null"`
+	KeyID        int64  `gorm:"not null"` // the key of the transaction
+	ContractName string `gorm:"not null"`
+	EventName    string `gorm:"not null"`
+	Data         string `gorm:"not null;type:jsonb"`
+}
+
+// TableName returns name of table
+func (ContractEvent) TableName() string {
+	return "contract_events"
+}
+
+// ContractEventFilter is the conditions of the events, the zero fields aren't checked
+type ContractEventFilter struct {
+	EcosystemID  int64
+	ContractName string
+	EventName    string
+	KeyID        int64
+	FromBlock    int64
+}
+
+// SaveContractEvents inserts the events of the transaction
+func SaveContractEvents(dbTx *DbTransaction, list []*ContractEvent) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return GetDB(dbTx).Create(&list).Error
+}
+
+// DeleteContractEvents deletes the events of the transactions of the block
+func DeleteContractEvents(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Where("block_id = ?", blockID).Delete(&ContractEvent{}).Error
+}
+
+// GetBlockContractEvents returns the events of the transactions of the block
+func GetBlockContractEvents(dbTx *DbTransaction, blockID int64) ([]ContractEvent, error) {
+	var list []ContractEvent
+	err := GetDB(dbTx).Where("block_id = ?", blockID).Order("tx_hash asc, log_index asc").Find(&list).Error
+	return list, err
+}
+
+// GetContractEvents returns the events matching the filter in the order of the blocks and the total count
+func GetContractEvents(filter ContractEventFilter, offset, limit int) ([]ContractEvent, int64, error) {
+	var (
+		list  []ContractEvent
+		total int64
+	)
+	q := DBConn.Model(&ContractEvent{})
+	if filter.EcosystemID > 0 {
+		q = q.Where("ecosystem_id = ?", filter.EcosystemID)
+	}
+	if len(filter.ContractName) > 0 {
+		q = q.Where("contract_name = ?", filter.ContractName)
+	}
+	if len(filter.EventName) > 0 {
+		q = q.Where("event_name = ?", filter.EventName)
+	}
+	if filter.KeyID != 0 {
+		q = q.Where("key_id = ?", filter.KeyID)
+	}
+	if filter.FromBlock > 0 {
+		q = q.Where("block_id >= ?", filter.FromBlock)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := q.Order("block_id asc, tx_hash asc, log_index asc").Offset(offset).Limit(limit).Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,188 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"encoding/json"
+	"math/bits"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatsDayLayout is the format of the day of contract_stats, the days are compared as strings
+const StatsDayLayout = "2006-01-02"
+
+// ContractStatsKey is the contract and the ecosystem of the caller
+type ContractStatsKey struct {
+	Ecosystem int64
+	Contract  string
+}
+
+// ContractStats is model of the daily execution statistics of the contract. The table is local to
+// the node, it isn't a part of the consensus state and the rolled back blocks aren't subtracted
+type ContractStats struct {
+	Day         string  `gorm:"primary_key;not null" json:"day"`
+	Ecosystem   int64   `gorm:"primary_key;not null" json:"ecosystem"`
+	Contract    string  `gorm:"primary_key;not null" json:"contract"`
+	Success     int64   `gorm:"not null" json:"success"`
+	Failed      int64   `gorm:"not null" json:"failed"`
+	FuelTotal   int64   `gorm:"not null" json:"fuel_total"`
+	FuelMax     int64   `gorm:"not null" json:"fuel_max"`
+	FuelBuckets string  `gorm:"not null" json:"-"` // json array of Buckets
+	RowsWritten int64   `gorm:"not null" json:"rows_written"`
+	Buckets     []int64 `gorm:"-" json:"-"` // the invocations by bits.Len64 of the fuel
+}
+
+// TableName returns name of table
+func (s *ContractStats) TableName() string {
+	return "contract_stats"
+}
+
+// StatsDay returns the day of t in contract_stats
+func StatsDay(t time.Time) string {
+	return t.UTC().Format(StatsDayLayout)
+}
+
+// Add counts the invocation of the contract
+func (s *ContractStats) Add(success bool, fuel int64, rows int) {
+	if success {
+		s.Success++
+	} else {
+		s.Failed++
+	}
+	if fuel < 0 {
+		fuel = 0
+	}
+	s.FuelTotal += fuel
+	if fuel > s.FuelMax {
+		s.FuelMax = fuel
+	}
+	s.RowsWritten += int64(rows)
+	bucket := bits.Len64(uint64(fuel))
+	for len(s.Buckets) <= bucket {
+		s.Buckets = append(s.Buckets, 0)
+	}
+	s.Buckets[bucket]++
+}
+
+// Merge adds the invocations of o
+func (s *ContractStats) Merge(o *ContractStats) {
+	s.Success += o.Success
+	s.Failed += o.Failed
+	s.FuelTotal += o.FuelTotal
+	if o.FuelMax > s.FuelMax {
+		s.FuelMax = o.FuelMax
+	}
+	s.RowsWritten += o.RowsWritten
+	for len(s.Buckets) < len(o.Buckets) {
+		s.Buckets = append(s.Buckets, 0)
+	}
+	for i, count := range o.Buckets {
+		s.Buckets[i] += count
+	}
+}
+
+// Invocations returns the number of the invocations
+func (s *ContractStats) Invocations() int64 {
+	return s.Success + s.Failed
+}
+
+// FuelP95 returns the 95th percentile of the fuel. It's the upper bound of the bucket of the
+// percentile, so it's exact only within the power of two, and it doesn't exceed FuelMax
+func (s *ContractStats) FuelP95() int64 {
+	rank := (s.Invocations()*95 + 99) / 100
+	var count int64
+	for i, n := range s.Buckets {
+		count += n
+		if count < rank || n == 0 {
+			continue
+		}
+		if i >= 63 || int64(1)<<i-1 > s.FuelMax {
+			return s.FuelMax
+		}
+		return int64(1)<<i - 1
+	}
+	return s.FuelMax
+}
+
+func (s *ContractStats) decodeBuckets() error {
+	s.Buckets = nil
+	if len(s.FuelBuckets) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(s.FuelBuckets), &s.Buckets)
+}
+
+// SaveContractStats adds the invocations of the block to the stats of the day and deletes the
+// days before from
+func SaveContractStats(dbTx *DbTransaction, day, from string, stats map[ContractStatsKey]*ContractStats) error {
+	return GetDB(dbTx).Transaction(func(db *gorm.DB) error {
+		for key, item := range stats {
+			row := &ContractStats{}
+			found, err := isFound(db.Where("day = ? AND ecosystem = ? AND contract = ?", day, key.Ecosystem, key.Contract).First(row))
+			if err != nil {
+				return err
+			}
+			if found {
+				if err = row.decodeBuckets(); err != nil {
+					return err
+				}
+			} else {
+				row = &ContractStats{Day: day, Ecosystem: key.Ecosystem, Contract: key.Contract}
+			}
+			row.Merge(item)
+			data, err := json.Marshal(row.Buckets)
+			if err != nil {
+				return err
+			}
+			row.FuelBuckets = string(data)
+			if !found {
+				err = db.Create(row).Error
+			} else {
+				err = db.Model(&ContractStats{}).Where("day = ? AND ecosystem = ? AND contract = ?", day, key.Ecosystem, key.Contract).
+					Updates(map[string]any{"success": row.Success, "failed": row.Failed, "fuel_total": row.FuelTotal,
+						"fuel_max": row.FuelMax, "fuel_buckets": row.FuelBuckets, "rows_written": row.RowsWritten}).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return db.Where("day < ?", from).Delete(&ContractStats{}).Error
+	})
+}
+
+// GetContractStats returns the daily stats of the contract from the day ordered by day. The stats
+// of all the ecosystems are returned if ecosystem is zero
+func GetContractStats(contract string, ecosystem int64, from string) ([]ContractStats, error) {
+	query := DBConn.Where("contract = ? AND day >= ?", contract, from)
+	if ecosystem != 0 {
+		query = query.Where("ecosystem = ?", ecosystem)
+	}
+	return findContractStats(query.Order("day asc, ecosystem asc"))
+}
+
+// GetContractStatsFrom returns the daily stats of all the contracts from the day
+func GetContractStatsFrom(ecosystem int64, from string) ([]ContractStats, error) {
+	query := DBConn.Where("day >= ?", from)
+	if ecosystem != 0 {
+		query = query.Where("ecosystem = ?", ecosystem)
+	}
+	return findContractStats(query.Order("day asc, ecosystem asc, contract asc"))
+}
+
+func findContractStats(query *gorm.DB) ([]ContractStats, error) {
+	var list []ContractStats
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if err := list[i].decodeBuckets(); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
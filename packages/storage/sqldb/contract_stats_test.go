@@ -0,0 +1,44 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractStats(t *testing.T) {
+	s := &ContractStats{}
+	assert.Equal(t, int64(0), s.FuelP95())
+
+	for i := 0; i < 19; i++ {
+		s.Add(true, 100, 2)
+	}
+	s.Add(false, 5000, 0)
+	assert.Equal(t, int64(19), s.Success)
+	assert.Equal(t, int64(1), s.Failed)
+	assert.Equal(t, int64(19*100+5000), s.FuelTotal)
+	assert.Equal(t, int64(5000), s.FuelMax)
+	assert.Equal(t, int64(38), s.RowsWritten)
+	// 100 is in the bucket of 64..127
+	assert.Equal(t, int64(127), s.FuelP95())
+
+	other := &ContractStats{}
+	other.Add(true, 5000, 1)
+	other.Add(true, 6000, 1)
+	s.Merge(other)
+	assert.Equal(t, int64(22), s.Invocations())
+	assert.Equal(t, int64(6000), s.FuelMax)
+	assert.Equal(t, int64(40), s.RowsWritten)
+	// the percentile is in the bucket of 4096..8191 which is capped by the max fuel
+	assert.Equal(t, int64(6000), s.FuelP95())
+
+	same := &ContractStats{}
+	same.Add(true, 300, 1)
+	same.Add(true, 300, 1)
+	assert.Equal(t, int64(300), same.FuelP95())
+}
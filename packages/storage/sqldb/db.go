@@ -6,9 +6,11 @@
 package sqldb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -124,7 +126,7 @@ open:
 	}), &gorm.Config{
 		AllowGlobalUpdate: true, //allow global update
 		//PrepareStmt:       true,
-		Logger: logger.Default.LogMode(logger.Silent), // start Logger, show detail log
+		Logger: statementLogger{logger.Default.LogMode(logger.Silent)}, // start Logger, show detail log
 	})
 	//DBConn, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
@@ -149,11 +151,16 @@ open:
 	sqlDB.SetConnMaxLifetime(time.Minute * 10)
 	sqlDB.SetMaxIdleConns(conf.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(conf.MaxOpenConns)
+	SetSlowStatementThreshold(time.Duration(conf.SlowStatementThreshold) * time.Millisecond)
+	SetMaxBinLogStatementBytes(conf.MaxBinLogStatementBytes)
 
 	if err = setupConnOptions(DBConn); err != nil {
 		return err
 	}
-	return nil
+	if err = registerContextCallbacks(DBConn); err != nil {
+		return err
+	}
+	return openReadConn(dsn, conf)
 }
 
 func createDatabase(dsn string, dbName string) error {
@@ -201,14 +208,39 @@ func GormClose() error {
 
 // DbTransaction is gorm.DB wrapper
 type DbTransaction struct {
-	conn      *gorm.DB
-	BinLogSql [][]byte
+	conn         *gorm.DB
+	BinLogSql    [][]byte
+	logger       *log.Entry
+	writtenBytes int64
+	source       atomic.Pointer[statementSource]
+	ctx          atomic.Pointer[txContext]
 }
 
 func NewDbTransaction(conn *gorm.DB) *DbTransaction {
 	return &DbTransaction{conn: conn}
 }
 
+// SetLogger sets the logger of the entries of the transaction, e.g. the logger of the played block
+func (tr *DbTransaction) SetLogger(logger *log.Entry) {
+	tr.logger = logger
+}
+
+// GetLogger returns the logger of the transaction, nil transaction logs with the standard logger
+func (tr *DbTransaction) GetLogger() *log.Entry {
+	if tr == nil || tr.logger == nil {
+		return log.NewEntry(log.StandardLogger())
+	}
+	return tr.logger
+}
+
+// WrittenBytes returns the size of the sql statements which are executed by ExecSql
+func (tr *DbTransaction) WrittenBytes() int64 {
+	if tr == nil {
+		return 0
+	}
+	return tr.writtenBytes
+}
+
 func (d *DbTransaction) Debug() *DbTransaction {
 	d.conn = d.conn.Debug()
 	return d
@@ -226,9 +258,10 @@ func StartTransaction() (*DbTransaction, error) {
 		return nil, err
 	}
 
-	return &DbTransaction{
-		conn: conn,
-	}, nil
+	// the statements of the transaction are recorded by the slow statement log
+	tr := &DbTransaction{}
+	tr.conn = conn.WithContext(context.WithValue(conn.Statement.Context, dbTxKey{}, tr))
+	return tr, nil
 }
 
 // Rollback is transaction rollback
@@ -248,12 +281,12 @@ func (tr *DbTransaction) Connection() *gorm.DB {
 
 // Savepoint creates PostgreSQL Savepoint
 func (tr *DbTransaction) Savepoint(mark string) error {
-	return tr.Connection().SavePoint(mark).Error
+	return tr.control().SavePoint(mark).Error
 }
 
 // RollbackSavepoint rollbacks PostgreSQL Savepoint
 func (tr *DbTransaction) RollbackSavepoint(mark string) error {
-	return tr.Connection().RollbackTo(mark).Error
+	return tr.control().RollbackTo(mark).Error
 }
 
 func (tr *DbTransaction) ResetSavepoint(mark string) error {
@@ -308,16 +341,24 @@ func (dbTx *DbTransaction) Update(tblname, set, where string) error {
 	return dbTx.ExecSql(sql)
 }
 
-// ExecSql is exec sql
+// ExecSql is exec sql. The statement over the limit of the binlog is kept in the binlog as the
+// smaller statements, the statement which can't be split isn't executed
 func (dbTx *DbTransaction) ExecSql(sql string) error {
+	stmts, err := dbTx.binLogStatements(sql)
+	if err != nil {
+		return err
+	}
 	queryFn := func(tx *gorm.DB) *gorm.DB {
 		return tx.Exec(sql)
 	}
-	err := queryFn(GetDB(dbTx)).Error
+	err = queryFn(GetDB(dbTx)).Error
 	if err != nil {
 		return err
 	}
-	dbTx.BinLogSql = append(dbTx.BinLogSql, []byte(sql))
+	for _, stmt := range stmts {
+		dbTx.BinLogSql = append(dbTx.BinLogSql, []byte(stmt))
+	}
+	dbTx.writtenBytes += int64(len(sql))
 	return nil
 }
 
@@ -334,7 +375,7 @@ func (dbTx *DbTransaction) GetColumnCount(tableName string) (int64, error) {
 		return 0, nil
 	}
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing raw query")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing raw query")
 		return 0, err
 	}
 	return count, nil
@@ -439,7 +480,7 @@ func (dbTx *DbTransaction) GetNextID(table string) (int64, error) {
 	var id int64
 	rows, err := GetDB(dbTx).Raw(`select id from "` + table + `" order by id desc limit 1`).Rows()
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("selecting next id from table")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err, "table": table}).Error("selecting next id from table")
 		return 0, err
 	}
 	rows.Next()
@@ -512,12 +553,12 @@ func (dbTx *DbTransaction) DropDatabase(name string) error {
 	pg_stat_activity.datname = ?`
 
 	if err := GetDB(dbTx).Exec(query, name).Error; err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "dbname": name}).Error("on kill db process")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err, "dbname": name}).Error("on kill db process")
 		return err
 	}
 
 	if err := GetDB(dbTx).Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", name)).Error; err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err, "dbname": name}).Error("on drop db")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err, "dbname": name}).Error("on drop db")
 		return err
 	}
 
@@ -528,7 +569,7 @@ func (dbTx *DbTransaction) DropDatabase(name string) error {
 func (dbTx *DbTransaction) GetSumColumn(table, column, where string) (result string, err error) {
 	err = GetDB(dbTx).Table(table).Select("sum(" + column + ")").Where(where).Row().Scan(&result)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("sum column")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("sum column")
 	}
 	return
 }
@@ -537,7 +578,7 @@ func (dbTx *DbTransaction) GetSumColumn(table, column, where string) (result str
 func (dbTx *DbTransaction) GetSumColumnCount(table, column, where string) (result int, err error) {
 	err = GetDB(dbTx).Table(table).Select("count(*)").Where(where).Row().Scan(&result)
 	if err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("sum column")
+		dbTx.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("sum column")
 	}
 	return
 }
@@ -566,3 +607,30 @@ func (v Namer) HasExists(tr *DbTransaction, names string) bool {
 		Select("table_name").Row().Scan(&name)
 	return name == names
 }
+
+// PingDB checks the connection to the database
+func PingDB(ctx context.Context) error {
+	if DBConn == nil {
+		return ErrDBConn
+	}
+	sqlDB, err := DBConn.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// GetReplicationLag returns true and the time since the last replayed transaction if the
+// database is the replica
+func GetReplicationLag(ctx context.Context) (replica bool, lag time.Duration, err error) {
+	var row struct {
+		Replica bool
+		Lag     float64
+	}
+	err = DBConn.WithContext(ctx).Raw(`SELECT pg_is_in_recovery() AS replica,
+		COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) AS lag`).Scan(&row).Error
+	if err != nil {
+		return false, 0, err
+	}
+	return row.Replica, time.Duration(row.Lag * float64(time.Second)), nil
+}
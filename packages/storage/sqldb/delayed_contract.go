@@ -20,6 +20,10 @@ type DelayedContract struct {
 	Limit      int64  `gorm:"not null"`
 	Delete     bool   `gorm:"not null"`
 	Conditions string `gorm:"not null"`
+	Callback   string `gorm:"not null"`
+	// the transaction and its block which create the delayed contract
+	OriginTxHash  []byte
+	OriginBlockID int64 `gorm:"not null"`
 }
 
 // TableName returns name of table
@@ -42,11 +46,22 @@ func (dc *DelayedContract) Get(id int64) (bool, error) {
 	return isFound(DBConn.Where("id = ?", id).First(dc))
 }
 
+// GetAvailable is retrieving the not deleted model by id in the transaction
+func (dc *DelayedContract) GetAvailable(dbTx *DbTransaction, id int64) (bool, error) {
+	return isFound(GetDB(dbTx).Where("id = ? AND deleted = ?", id, availableDelayedContracts).First(dc))
+}
+
 // GetByContract is retrieving model by contract from database
 func (dc *DelayedContract) GetByContract(dbTx *DbTransaction, contract string) (bool, error) {
 	return isFound(GetDB(dbTx).Where("contract = ? AND deleted = ?", contract, availableDelayedContracts).First(dc))
 }
 
+// GetByContractKey is retrieving the not deleted and not limited model by contract and key in the transaction
+func (dc *DelayedContract) GetByContractKey(dbTx *DbTransaction, contract string, keyID int64) (bool, error) {
+	return isFound(GetDB(dbTx).Where(`contract = ? AND key_id = ? AND deleted = ? AND (counter < "1_delayed_contracts".limit OR "1_delayed_contracts".limit = 0)`,
+		contract, keyID, availableDelayedContracts).Order("high_rate desc, id").First(dc))
+}
+
 func GetAllDelayedContract() ([]*DelayedContract, error) {
 	var contracts []*DelayedContract
 	if err := DBConn.Where(" deleted = ?", availableDelayedContracts).Find(&contracts).Error; err != nil {
@@ -54,3 +69,73 @@ func GetAllDelayedContract() ([]*DelayedContract, error) {
 	}
 	return contracts, nil
 }
+
+// DelayedResult represents record of 1_delayed_results table, it's the outcome of the delayed contract
+type DelayedResult struct {
+	ID            int64  `gorm:"primary_key;not null"`
+	DelayedID     int64  `gorm:"not null"`
+	KeyID         int64  `gorm:"not null"`
+	Contract      string `gorm:"not null"`
+	BlockID       int64  `gorm:"not null"`
+	TxHash        []byte `gorm:"not null"`
+	Success       int64  `gorm:"not null"`
+	Result        string `gorm:"not null"`
+	Fuel          int64  `gorm:"not null"`
+	Callback      string `gorm:"not null"`
+	CallbackError string `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (DelayedResult) TableName() string {
+	return "1_delayed_results"
+}
+
+// GetDelayedResults returns the outcomes of the delayed contract from the latest one
+func GetDelayedResults(dbTx *DbTransaction, delayedID int64) ([]DelayedResult, error) {
+	var results []DelayedResult
+	if err := GetDB(dbTx).Where("delayed_id = ?", delayedID).Order("id desc").Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CausedDelay is the delayed contract which is created by the traced transaction, Depth is 1 for the contracts
+// of the transaction, 2 for the contracts of the transactions of these contracts and so on
+type CausedDelay struct {
+	*DelayedContract
+	Depth int64
+}
+
+// GetCausedDelays returns the delayed contracts which are created by the transaction txHash and, transitively,
+// by the transactions of the delayed contracts, the number of the contracts is limited by limit
+func GetCausedDelays(dbTx *DbTransaction, txHash []byte, limit int) ([]CausedDelay, error) {
+	var (
+		result []CausedDelay
+		seen   = make(map[int64]bool)
+		hashes = [][]byte{txHash}
+	)
+	for depth := int64(1); len(hashes) > 0 && len(result) < limit; depth++ {
+		var contracts []*DelayedContract
+		if err := GetDB(dbTx).Where("origin_tx_hash IN ?", hashes).Order("id").Limit(limit - len(result)).
+			Find(&contracts).Error; err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(contracts))
+		for _, dc := range contracts {
+			if !seen[dc.ID] {
+				seen[dc.ID] = true
+				ids = append(ids, dc.ID)
+				result = append(result, CausedDelay{DelayedContract: dc, Depth: depth})
+			}
+		}
+		hashes = nil
+		if len(ids) == 0 {
+			break
+		}
+		if err := GetDB(dbTx).Model(&DelayedResult{}).Where("delayed_id IN ?", ids).Order("id").
+			Pluck("tx_hash", &hashes).Error; err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
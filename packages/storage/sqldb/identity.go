@@ -0,0 +1,29 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+// Identity is model of the registered identity of the key in the ecosystem. VerifiedByKeyID is
+// the key of the verifier, it's reset when the identity is changed by its key
+type Identity struct {
+	ID              int64  `gorm:"primary_key;not null" json:"id"`
+	KeyID           int64  `gorm:"not null" json:"key_id"`
+	DisplayName     string `gorm:"not null" json:"display_name"`
+	EmailHash       string `gorm:"not null" json:"email_hash"`
+	Website         string `gorm:"not null" json:"website"`
+	VerifiedByKeyID int64  `gorm:"not null" json:"verified_by_key_id"`
+	BlockID         int64  `gorm:"not null" json:"block_id"`
+	Ecosystem       int64  `gorm:"not null" json:"ecosystem"`
+}
+
+// TableName returns name of table
+func (i *Identity) TableName() string {
+	return "1_identities"
+}
+
+// Get is retrieving the identity of the key in the ecosystem
+func (i *Identity) Get(ecosystem, keyID int64) (bool, error) {
+	return isFound(DBConn.Where("ecosystem = ? AND key_id = ?", ecosystem, keyID).First(i))
+}
@@ -6,8 +6,11 @@
 package sqldb
 
 import (
+	"database/sql"
 	"fmt"
 
+	"gorm.io/gorm"
+
 	"github.com/shopspring/decimal"
 
 	"github.com/IBAX-io/go-ibax/packages/converter"
@@ -25,6 +28,12 @@ type Key struct {
 	Maxpay    string `gorm:"not null"`
 	Deleted   int64  `gorm:"not null"`
 	Blocked   int64  `gorm:"not null"`
+	Frozen    int64  `gorm:"not null"`
+	// AuthContract authorizes the abstract account transactions of the key
+	AuthContract string `gorm:"column:auth_contract;not null"`
+	// ParentKeyID is the key which the key is derived from, zero if the key isn't derived
+	ParentKeyID     int64 `gorm:"column:parent_key_id;not null"`
+	DerivationIndex int64 `gorm:"column:derivation_index;not null"`
 }
 
 // SetTablePrefix is setting table prefix
@@ -70,6 +79,23 @@ func (m *Key) AccountKeyID() int64 {
 	return m.accountKeyID
 }
 
+// GetPublicKeys returns the public keys of the ids in all the ecosystems by the ecosystem and the id
+func GetPublicKeys(dbTx *DbTransaction, ids []int64) (map[[2]int64][]byte, error) {
+	var list []struct {
+		ID        int64
+		Ecosystem int64
+		Pub       []byte
+	}
+	if err := GetDB(dbTx).Table(KeyTableName(1)).Select("id, ecosystem, pub").Where("id IN ?", ids).Scan(&list).Error; err != nil {
+		return nil, err
+	}
+	keys := make(map[[2]int64][]byte, len(list))
+	for _, k := range list {
+		keys[[2]int64{k.Ecosystem, k.ID}] = k.Pub
+	}
+	return keys, nil
+}
+
 // KeyTableName returns name of key table
 func KeyTableName(prefix int64) string {
 	return fmt.Sprintf("%d_keys", prefix)
@@ -82,3 +108,27 @@ func GetKeysCount() (int64, error) {
 	err := row.Scan(&cnt)
 	return cnt, err
 }
+
+// GetChildKeys returns the keys of the ecosystem which are derived from the parent key ordered by
+// their derivation index
+func GetChildKeys(ecosystem, parentID int64, limit, offset int) ([]Key, error) {
+	var list []Key
+	err := DBConn.Table(KeyTableName(1)).Where("ecosystem = ? AND parent_key_id = ?", ecosystem, parentID).
+		Order("derivation_index asc, id asc").Limit(limit).Offset(offset).Find(&list).Error
+	return list, err
+}
+
+// GetEcosystemMembers returns the ids of the keys of the ecosystem which are neither deleted nor blocked
+// ordered by id and the last block of the chain, they are read from the same snapshot
+func GetEcosystemMembers(ecosystem int64) (blockID int64, ids []int64, err error) {
+	err = DBConn.Transaction(func(db *gorm.DB) error {
+		info := &InfoBlock{}
+		if err := db.Last(info).Error; err != nil {
+			return err
+		}
+		blockID = info.BlockID
+		return db.Table(KeyTableName(1)).Where("ecosystem = ? AND deleted = 0 AND blocked = 0", ecosystem).
+			Order("id asc").Pluck("id", &ids).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	return
+}
@@ -30,7 +30,7 @@ func (m Menu) TableName() string {
 	return `1_menu`
 }
 
-// Get is retrieving model from database
+// Get is retrieving model from database, the soft-deleted row isn't found
 func (m *Menu) Get(name string) (bool, error) {
-	return isFound(DBConn.Where("ecosystem=? and name = ?", m.ecosystem, name).First(m))
+	return isFound(DBConn.Where("ecosystem=? and name = ? and deleted = 0", m.ecosystem, name).First(m))
 }
@@ -0,0 +1,65 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+const (
+	// OracleStatusPending is waiting of the answers
+	OracleStatusPending = 0
+	// OracleStatusDone is the request with the agreed value
+	OracleStatusDone = 1
+	// OracleStatusFailed is the request without the agreed value before the deadline
+	OracleStatusFailed = 2
+)
+
+// OracleRequest is model of the request of the external data
+type OracleRequest struct {
+	ID        int64
+	Url       string
+	JsonPath  string
+	Callback  string
+	Threshold int64
+	Deadline  int64
+	Status    int64
+	Value     string
+	KeyID     int64
+	Ecosystem int64
+	BlockID   int64
+}
+
+// TableName returns name of table
+func (r OracleRequest) TableName() string {
+	return "1_oracle_requests"
+}
+
+// GetPendingOracleRequests returns the requests which are waiting of the answers
+func GetPendingOracleRequests() ([]OracleRequest, error) {
+	var list []OracleRequest
+	err := DBConn.Where("status = ?", OracleStatusPending).Order("id").Find(&list).Error
+	return list, err
+}
+
+// OracleAnswer is model of the commit and the revealed value of the honor node
+type OracleAnswer struct {
+	ID         int64
+	RequestID  int64
+	NodeID     int64
+	CommitHash string
+	Value      string
+	Revealed   int64
+	BlockID    int64
+}
+
+// TableName returns name of table
+func (a OracleAnswer) TableName() string {
+	return "1_oracle_answers"
+}
+
+// GetOracleAnswers returns the answers of the request
+func GetOracleAnswers(requestID int64) ([]OracleAnswer, error) {
+	var list []OracleAnswer
+	err := DBConn.Where("request_id = ?", requestID).Order("id").Find(&list).Error
+	return list, err
+}
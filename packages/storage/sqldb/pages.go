@@ -32,9 +32,9 @@ func (p *Page) TableName() string {
 	return `1_pages`
 }
 
-// Get is retrieving model from database
+// Get is retrieving model from database, the soft-deleted row isn't found
 func (p *Page) Get(name string) (bool, error) {
-	return isFound(DBConn.Where("ecosystem=? and name = ?", p.ecosystem, name).First(p))
+	return isFound(DBConn.Where("ecosystem=? and name = ? and deleted = 0", p.ecosystem, name).First(p))
 }
 
 // Count returns count of records in table
@@ -46,6 +46,6 @@ func (p *Page) Count() (count int64, err error) {
 // GetByApp returns all pages belonging to selected app
 func (p *Page) GetByApp(appID int64, ecosystemID int64) ([]Page, error) {
 	var result []Page
-	err := DBConn.Select("id, name").Where("app_id = ? and ecosystem = ?", appID, ecosystemID).Find(&result).Error
+	err := DBConn.Select("id, name").Where("app_id = ? and ecosystem = ? and deleted = 0", appID, ecosystemID).Find(&result).Error
 	return result, err
 }
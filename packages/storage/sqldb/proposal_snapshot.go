@@ -0,0 +1,52 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// ProposalSnapshot is model of the balance of the voter at the block where the proposal was created
+type ProposalSnapshot struct {
+	ID              int64           `gorm:"primary_key;not null" json:"id"`
+	KeyID           int64           `gorm:"not null" json:"key_id"`
+	Amount          decimal.Decimal `gorm:"not null" json:"amount"`
+	SnapshotBlockID int64           `gorm:"not null" json:"snapshot_block_id"`
+	Ecosystem       int64           `gorm:"not null" json:"ecosystem"`
+}
+
+// TableName returns name of table
+func (s *ProposalSnapshot) TableName() string {
+	return "1_proposal_snapshots"
+}
+
+// HasProposalSnapshot returns true if the balances of the ecosystem have been recorded at the block
+func HasProposalSnapshot(dbTx *DbTransaction, ecosystem, blockID int64) (bool, error) {
+	var count int64
+	err := GetDB(dbTx).Model(&ProposalSnapshot{}).
+		Where("ecosystem = ? AND snapshot_block_id = ?", ecosystem, blockID).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// CreateProposalSnapshot records the balances of the keys of the ecosystem at the block,
+// the keys without tokens aren't recorded
+func CreateProposalSnapshot(dbTx *DbTransaction, ecosystem, blockID int64) error {
+	return GetDB(dbTx).Exec(`INSERT INTO "1_proposal_snapshots" (id, key_id, amount, snapshot_block_id, ecosystem)
+		SELECT (SELECT coalesce(max(id), 0) FROM "1_proposal_snapshots") + row_number() OVER (ORDER BY id), id, amount, ?, ecosystem
+		FROM "1_keys" WHERE ecosystem = ? AND amount > 0 AND deleted = 0 AND blocked = 0`, blockID, ecosystem).Error
+}
+
+// DeleteProposalSnapshot removes the balances recorded at the block
+func DeleteProposalSnapshot(dbTx *DbTransaction, ecosystem, blockID int64) error {
+	return GetDB(dbTx).Where("ecosystem = ? AND snapshot_block_id = ?", ecosystem, blockID).
+		Delete(&ProposalSnapshot{}).Error
+}
+
+// Get is retrieving the recorded balance of the key
+func (s *ProposalSnapshot) Get(dbTx *DbTransaction, ecosystem, blockID, keyID int64) (bool, error) {
+	return isFound(GetDB(dbTx).Where("ecosystem = ? AND snapshot_block_id = ? AND key_id = ?",
+		ecosystem, blockID, keyID).First(s))
+}
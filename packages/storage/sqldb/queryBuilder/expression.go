@@ -2,12 +2,70 @@ package queryBuilder
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/IBAX-io/go-ibax/packages/converter"
 	"github.com/IBAX-io/go-ibax/packages/types"
 )
 
+// sortedKeys returns the keys of the map in the same order on all nodes
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// eachOrder calls fn for the items of the order in the order they are specified,
+// the keys of the Go maps are sorted
+func eachOrder(inOrder any, fn func(string, any)) {
+	switch v := inOrder.(type) {
+	case string:
+		fn(v, nil)
+	case *types.Map:
+		for _, ikey := range v.Keys() {
+			item, _ := v.Get(ikey)
+			fn(ikey, item)
+		}
+	case map[string]any:
+		for _, ikey := range sortedKeys(v) {
+			fn(ikey, v[ikey])
+		}
+	case []any:
+		for _, item := range v {
+			switch param := item.(type) {
+			case string:
+				fn(param, nil)
+			case *types.Map:
+				for _, ikey := range param.Keys() {
+					item, _ := param.Get(ikey)
+					fn(ikey, item)
+				}
+			case map[string]any:
+				for _, key := range sortedKeys(param) {
+					fn(key, param[key])
+				}
+			}
+		}
+	}
+}
+
+// OrderColumns returns the sanitized columns of the order
+func OrderColumns(inOrder any) []string {
+	var columns []string
+	eachOrder(inOrder, func(in string, _ any) {
+		if in = converter.Sanitize(strings.ToLower(in), ``); len(in) > 0 {
+			columns = append(columns, in)
+		}
+	})
+	return columns
+}
+
+// GetOrder returns the order of the table. If withDefault is true the primary key is appended,
+// so the rows are returned in the same order on all nodes
 func GetOrder(tblname string, inOrder any, withDefault bool) (string, error) {
 	var (
 		orders           []string
@@ -44,35 +102,7 @@ func GetOrder(tblname string, inOrder any, withDefault bool) (string, error) {
 			cols.Set(`id`, false)
 		}
 	}
-	switch v := inOrder.(type) {
-	case string:
-		sanitize(v, nil)
-	case *types.Map:
-		for _, ikey := range v.Keys() {
-			item, _ := v.Get(ikey)
-			sanitize(ikey, item)
-		}
-	case map[string]any:
-		for ikey, item := range v {
-			sanitize(ikey, item)
-		}
-	case []any:
-		for _, item := range v {
-			switch param := item.(type) {
-			case string:
-				sanitize(param, nil)
-			case *types.Map:
-				for _, ikey := range param.Keys() {
-					item, _ := param.Get(ikey)
-					sanitize(ikey, item)
-				}
-			case map[string]any:
-				for key, value := range param {
-					sanitize(key, value)
-				}
-			}
-		}
-	}
+	eachOrder(inOrder, sanitize)
 	for _, key := range cols.Keys() {
 		if state, found := cols.Get(key); !found || !state.(bool) {
 			orders = append(orders, key)
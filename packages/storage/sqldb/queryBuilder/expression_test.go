@@ -0,0 +1,42 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package queryBuilder
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestGetOrder(t *testing.T) {
+	for _, item := range []struct {
+		table string
+		order any
+		want  string
+	}{
+		{"1_pages", nil, "id"},
+		{"1_keys", nil, "ecosystem,id"},
+		{"1_pages", "name", `"name" asc,id`},
+		{"1_pages", "id", `"id" asc`},
+		{"1_pages", types.LoadMap(map[string]any{"name": -1}), `"name" desc,id`},
+		{"1_pages", map[string]any{"value": 1, "name": -1, "app_id": 1}, `"app_id" asc,"name" desc,"value" asc,id`},
+		{"1_keys", []any{"amount", map[string]any{"pub": -1, "id": 1}}, `"amount" asc,"id" asc,"pub" desc,ecosystem`},
+	} {
+		// the maps are iterated in the random order, the order of the query must be the same
+		for i := 0; i < 20; i++ {
+			got, err := GetOrder(item.table, item.order, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != item.want {
+				t.Fatalf("order %v: expected %s, got %s", item.order, item.want, got)
+			}
+		}
+	}
+	if cols := OrderColumns([]any{"Name", "", map[string]any{"b": 1, "a": -1}}); len(cols) != 3 ||
+		cols[0] != "name" || cols[1] != "a" || cols[2] != "b" {
+		t.Errorf("wrong order columns %v", cols)
+	}
+}
@@ -6,6 +6,8 @@
 package sqldb
 
 import (
+	"context"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -83,3 +85,11 @@ func DeleteQueueTxs(dbTx *DbTransaction, hs [][]byte) error {
 	}
 	return GetDB(dbTx).Delete(&QueueTx{}, hs).Error
 }
+
+// GetPendingTransactionsCount counting the queued and the unused transactions
+func GetPendingTransactionsCount(ctx context.Context) (int64, error) {
+	var rowsCount int64
+	err := DBConn.WithContext(ctx).Raw(`SELECT (SELECT count(*) FROM queue_tx) + (SELECT count(*) FROM transactions WHERE used = 0)`).
+		Scan(&rowsCount).Error
+	return rowsCount, err
+}
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// ReadConn is the pool of the read only connections of the API, so the reads don't wait for the connections
+// which are held by the play of the block. The reads use DBConn if it's nil
+var ReadConn *gorm.DB
+
+// ReadDB returns the pool of the reads of the API
+func ReadDB() *gorm.DB {
+	if ReadConn != nil {
+		return ReadConn
+	}
+	return DBConn
+}
+
+// openReadConn opens the pool of the read only connections if cfg.ReadConns is positive
+func openReadConn(dsn string, cfg conf.DBConfig) error {
+	if cfg.ReadConns <= 0 {
+		ReadConn = nil
+		return nil
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  dsn + " default_transaction_read_only=on",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("cant open read connection to DB")
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("cant get sql DB")
+		return err
+	}
+	sqlDB.SetConnMaxLifetime(time.Minute * 10)
+	sqlDB.SetMaxIdleConns(cfg.ReadConns)
+	sqlDB.SetMaxOpenConns(cfg.ReadConns)
+	ReadConn = db
+	return nil
+}
+
+// StartReadTransaction begins the read only transaction of the repeatable read on ReadDB. All the statements
+// of the transaction see the state of the last committed block, which is returned. The block which is played
+// holds its own write transaction, its changes aren't seen until it's committed and the reads don't wait for
+// it. The transaction is finished by Rollback
+func StartReadTransaction() (*DbTransaction, int64, error) {
+	conn := ReadDB().Begin(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if conn.Error != nil {
+		log.WithFields(log.Fields{"type": consts.DBError, "error": conn.Error}).Error("cannot start read transaction")
+		return nil, 0, conn.Error
+	}
+	info := &InfoBlock{}
+	if err := conn.Last(info).Error; err != nil {
+		conn.Rollback()
+		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting info block of read transaction")
+		return nil, 0, err
+	}
+	return NewDbTransaction(conn), info.BlockID, nil
+}
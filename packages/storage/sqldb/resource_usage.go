@@ -0,0 +1,104 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// The metrics of the top consumers
+const (
+	ResourceMetricCPU     = "cpu"
+	ResourceMetricDBWrite = "db_write"
+	ResourceMetricMem     = "mem"
+	ResourceMetricGas     = "gas"
+)
+
+// resourceMetrics are the aggregated columns of the metrics, the peak memory is the maximum
+var resourceMetrics = map[string]string{
+	ResourceMetricCPU:     "cpu_ns",
+	ResourceMetricDBWrite: "db_write_bytes",
+	ResourceMetricMem:     "mem_peak_bytes",
+	ResourceMetricGas:     "gas_used",
+}
+
+// ResourceUsage is model of the resources consumed by the contract call of the transaction. The table
+// is local to the node, the values depend on the hardware and they aren't a part of the consensus state
+type ResourceUsage struct {
+	BlockID      int64     `gorm:"primary_key;not null" json:"block_id"`
+	TxHash       []byte    `gorm:"primary_key;not null" json:"tx_hash"`
+	ContractName string    `gorm:"not null" json:"contract_name"`
+	EcosystemID  int64     `gorm:"not null" json:"ecosystem_id"`
+	CPUNs        int64     `gorm:"column:cpu_ns;not null" json:"cpu_ns"`
+	DBWriteBytes int64     `gorm:"column:db_write_bytes;not null" json:"db_write_bytes"`
+	MemPeakBytes int64     `gorm:"not null" json:"mem_peak_bytes"`
+	GasUsed      int64     `gorm:"not null" json:"gas_used"`
+	RecordedAt   time.Time `gorm:"not null" json:"recorded_at"`
+}
+
+// TableName returns name of table
+func (r *ResourceUsage) TableName() string {
+	return "resource_usage"
+}
+
+// ResourceConsumer is the resources consumed by the contract within the window
+type ResourceConsumer struct {
+	ContractName string `json:"contract_name"`
+	EcosystemID  int64  `json:"ecosystem_id"`
+	Calls        int64  `json:"calls"`
+	CPUNs        int64  `gorm:"column:cpu_ns" json:"cpu_ns"`
+	DBWriteBytes int64  `gorm:"column:db_write_bytes" json:"db_write_bytes"`
+	MemPeakBytes int64  `json:"mem_peak_bytes"`
+	GasUsed      int64  `json:"gas_used"`
+}
+
+// IsResourceMetric returns true if the top consumers can be ordered by the metric
+func IsResourceMetric(metric string) bool {
+	_, ok := resourceMetrics[metric]
+	return ok
+}
+
+// SaveResourceUsage inserts the usage of the transactions of the block and deletes the records
+// before the time from. The zero from keeps all the records
+func SaveResourceUsage(dbTx *DbTransaction, list []*ResourceUsage, from time.Time) error {
+	db := GetDB(dbTx)
+	if len(list) > 0 {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&list).Error; err != nil {
+			return err
+		}
+	}
+	if from.IsZero() {
+		return nil
+	}
+	return db.Where("recorded_at < ?", from).Delete(&ResourceUsage{}).Error
+}
+
+// DeleteResourceUsage deletes the usage of the transactions of the block
+func DeleteResourceUsage(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Where("block_id = ?", blockID).Delete(&ResourceUsage{}).Error
+}
+
+// GetTopConsumers returns the contracts which have consumed the most of the metric since the time
+// from. The stats of all the ecosystems are returned if ecosystem is zero
+func GetTopConsumers(metric string, from time.Time, ecosystem int64, limit int) ([]ResourceConsumer, error) {
+	column, ok := resourceMetrics[metric]
+	if !ok {
+		column = resourceMetrics[ResourceMetricCPU]
+	}
+	query := DBConn.Model(&ResourceUsage{}).
+		Select(`contract_name, ecosystem_id, count(*) AS calls, sum(cpu_ns) AS cpu_ns, sum(db_write_bytes) AS db_write_bytes,
+			max(mem_peak_bytes) AS mem_peak_bytes, sum(gas_used) AS gas_used`).
+		Where("recorded_at >= ?", from)
+	if ecosystem != 0 {
+		query = query.Where("ecosystem_id = ?", ecosystem)
+	}
+	var list []ResourceConsumer
+	err := query.Group("contract_name, ecosystem_id").
+		Order(column + " desc, contract_name asc, ecosystem_id asc").Limit(limit).Scan(&list).Error
+	return list, err
+}
@@ -0,0 +1,33 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+// RewardDestination is model of the registered account which receives the rewards of the block
+// producer. The row is inserted by the transaction signed by the producer key, it applies to the
+// blocks starting from BlockID
+type RewardDestination struct {
+	ID          int64 `gorm:"primary_key;not null" json:"id"`
+	KeyID       int64 `gorm:"not null" json:"key_id"`
+	Destination int64 `gorm:"not null" json:"destination"`
+	BlockID     int64 `gorm:"not null" json:"block_id"`
+}
+
+// TableName returns name of table
+func (r *RewardDestination) TableName() string {
+	return "1_reward_destinations"
+}
+
+// GetRewardDestination returns the destination of the rewards of keyID which applies to blockID.
+// The latest registration before or within the block wins
+func GetRewardDestination(dbTx *DbTransaction, keyID, blockID int64) (int64, bool, error) {
+	r := &RewardDestination{}
+	found, err := isFound(GetDB(dbTx).Where("key_id = ? AND block_id <= ?", keyID, blockID).
+		Order("block_id desc, id desc").First(r))
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return r.Destination, true, nil
+}
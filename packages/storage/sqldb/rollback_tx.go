@@ -8,6 +8,10 @@ package sqldb
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
 
 	"gorm.io/gorm"
 )
@@ -28,6 +32,62 @@ func (*RollbackTx) TableName() string {
 	return "rollback_tx"
 }
 
+// RowRef is the row which is changed by the rollback record
+type RowRef struct {
+	Table     string
+	ID        string
+	Ecosystem int64
+	Shared    bool // the row of the first ecosystem table, the tables are shared by the ecosystems
+}
+
+// Key returns the key of the row within its table
+func (r RowRef) Key() string {
+	if r.Shared {
+		return fmt.Sprintf("%s,%d", r.ID, r.Ecosystem)
+	}
+	return r.ID
+}
+
+// Where returns the condition of the row
+func (r RowRef) Where() string {
+	if r.Shared {
+		return fmt.Sprintf(` WHERE "id"='%s' AND "ecosystem"='%d'`, r.ID, r.Ecosystem)
+	}
+	return ` WHERE "id"='` + r.ID + `'`
+}
+
+// Row returns the changed row. The rows of the first ecosystem tables are in the 1_ tables,
+// the ecosystem is taken from the old values or from the id of the inserted row
+func (rt *RollbackTx) Row() (RowRef, error) {
+	row := RowRef{Table: rt.NameTable, ID: rt.TableID}
+	var ecoID string
+	if len(rt.Data) > 0 {
+		var rollbackInfo map[string]string
+		if err := json.Unmarshal([]byte(rt.Data), &rollbackInfo); err != nil {
+			return row, err
+		}
+		ecoID = rollbackInfo["ecosystem"]
+	}
+	under := strings.IndexByte(rt.NameTable, '_')
+	if under <= 0 {
+		return row, nil
+	}
+	keyName := rt.NameTable[under+1:]
+	if v, ok := converter.FirstEcosystemTables[keyName]; !ok || !v {
+		return row, nil
+	}
+	if len(rt.Data) == 0 {
+		row.ID = ""
+		if a := strings.Split(rt.TableID, ","); len(a) > 1 {
+			row.ID, ecoID = a[0], a[1]
+		}
+	}
+	row.Table = `1_` + keyName
+	row.Ecosystem = converter.StrToInt64(ecoID)
+	row.Shared = true
+	return row, nil
+}
+
 // GetRollbackTransactions is returns rollback transactions
 func (rt *RollbackTx) GetRollbackTransactions(dbTx *DbTransaction, transactionHash []byte) ([]map[string]string, error) {
 	return dbTx.GetAllTransaction("SELECT * from rollback_tx WHERE tx_hash = ? ORDER BY ID DESC", -1, transactionHash)
@@ -55,6 +115,12 @@ func (rt *RollbackTx) DeleteByHash(dbTx *DbTransaction) error {
 	return GetDB(dbTx).Exec("DELETE FROM rollback_tx WHERE tx_hash = ?", rt.TxHash).Error
 }
 
+// DeleteRollbackTxsTo deletes the rollback records of the blocks up to blockID inclusive, the blocks
+// can't be rolled back then
+func DeleteRollbackTxsTo(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Exec("DELETE FROM rollback_tx WHERE block_id <= ?", blockID).Error
+}
+
 // DeleteByHashAndTableName is deleting tx by hash and table name
 func (rt *RollbackTx) DeleteByHashAndTableName(dbTx *DbTransaction) error {
 	return GetDB(dbTx).Where("tx_hash = ? and table_name = ?", rt.TxHash, rt.NameTable).Delete(rt).Error
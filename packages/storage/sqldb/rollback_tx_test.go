@@ -0,0 +1,39 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackRow(t *testing.T) {
+	for _, item := range []struct {
+		rt    RollbackTx
+		table string
+		key   string
+		where string
+	}{
+		{RollbackTx{NameTable: "2_keys", TableID: "7,2", Data: ""},
+			"1_keys", "7,2", ` WHERE "id"='7' AND "ecosystem"='2'`},
+		{RollbackTx{NameTable: "1_keys", TableID: "7", Data: `{"amount":"10","ecosystem":"3"}`},
+			"1_keys", "7,3", ` WHERE "id"='7' AND "ecosystem"='3'`},
+		{RollbackTx{NameTable: "1_reward_destinations", TableID: "4", Data: ""},
+			"1_reward_destinations", "4", ` WHERE "id"='4'`},
+		{RollbackTx{NameTable: "3_orders", TableID: "9", Data: `{"price":"1"}`},
+			"3_orders", "9", ` WHERE "id"='9'`},
+	} {
+		row, err := item.rt.Row()
+		assert.NoError(t, err)
+		assert.Equal(t, item.table, row.Table, item.rt.NameTable)
+		assert.Equal(t, item.key, row.Key(), item.rt.NameTable)
+		assert.Equal(t, item.where, row.Where(), item.rt.NameTable)
+	}
+
+	_, err := (&RollbackTx{NameTable: "1_keys", TableID: "7", Data: "{"}).Row()
+	assert.Error(t, err)
+}
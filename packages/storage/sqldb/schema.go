@@ -25,7 +25,7 @@ func ExecSchemaEcosystem(db *DbTransaction, data migration.SqlData) error {
 			return err
 		}
 		if err := db.ExecSql(q); err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing comma ecosystem schema")
+			db.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing comma ecosystem schema")
 			return err
 		}
 	}
@@ -34,7 +34,7 @@ func ExecSchemaEcosystem(db *DbTransaction, data migration.SqlData) error {
 		return err
 	}
 	if err := db.ExecSql(q); err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing ecosystem schema")
+		db.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing ecosystem schema")
 		return err
 	}
 	if data.Ecosystem == 1 {
@@ -43,14 +43,14 @@ func ExecSchemaEcosystem(db *DbTransaction, data migration.SqlData) error {
 			return err
 		}
 		if err := db.ExecSql(q); err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing first ecosystem schema")
+			db.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing first ecosystem schema")
 		}
 		q, err = migration.GetFirstTableScript(data)
 		if err != nil {
 			return err
 		}
 		if err := db.ExecSql(q); err != nil {
-			log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing first tables schema")
+			db.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("executing first tables schema")
 		}
 	}
 	return nil
@@ -0,0 +1,63 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"time"
+)
+
+// ServiceKey is model of the api key of the service account. The table is local to the node, only
+// the hash of the secret is stored
+type ServiceKey struct {
+	Name        string     `gorm:"primary_key;not null" json:"name"`
+	KeyHash     []byte     `gorm:"not null" json:"-"`
+	Permission  string     `gorm:"not null" json:"permission"`
+	AllowedIPs  string     `gorm:"column:allowed_ips;not null" json:"allowed_ips"` // comma separated ips and cidrs, empty allows all
+	KeyID       int64      `gorm:"not null" json:"key_id"`                         // the account of the requests
+	EcosystemID int64      `gorm:"not null" json:"ecosystem_id"`
+	CreatedAt   time.Time  `gorm:"not null" json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+}
+
+// TableName returns name of table
+func (k *ServiceKey) TableName() string {
+	return "service_keys"
+}
+
+// Create inserts the key, the name must be unique
+func (k *ServiceKey) Create() error {
+	return DBConn.Create(k).Error
+}
+
+// GetServiceKeyByHash returns the key which isn't revoked by the hash of the secret, nil if the
+// key isn't found
+func GetServiceKeyByHash(hash []byte) (*ServiceKey, error) {
+	key := &ServiceKey{}
+	found, err := isFound(DBConn.Where("key_hash = ? AND revoked_at IS NULL", hash).First(key))
+	if !found || err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetServiceKeys returns all the keys including the revoked ones ordered by name
+func GetServiceKeys() ([]ServiceKey, error) {
+	var list []ServiceKey
+	err := DBConn.Order("name asc").Find(&list).Error
+	return list, err
+}
+
+// RevokeServiceKey revokes the key with the name, it returns false if there is no such active key
+func RevokeServiceKey(name string, t time.Time) (bool, error) {
+	db := DBConn.Model(&ServiceKey{}).Where("name = ? AND revoked_at IS NULL", name).Update("revoked_at", t)
+	return db.RowsAffected > 0, db.Error
+}
+
+// TouchServiceKey sets the last use of the key
+func TouchServiceKey(name string, t time.Time) error {
+	return DBConn.Model(&ServiceKey{}).Where("name = ?", name).Update("last_used_at", t).Error
+}
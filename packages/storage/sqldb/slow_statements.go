@@ -0,0 +1,123 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/statsd"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm/logger"
+)
+
+// slowStatementsSize is the count of the recent slow statements which are kept
+const slowStatementsSize = 100
+
+// SlowStatement is the statement of the block transaction which has exceeded the threshold
+type SlowStatement struct {
+	Time     time.Time `json:"time"`
+	Duration int64     `json:"duration"` // in milliseconds
+	SQL      string    `json:"sql"`
+	Rows     int64     `json:"rows"`
+	TxHash   string    `json:"tx_hash"`
+	Contract string    `json:"contract"`
+}
+
+// statementSource is the transaction which executes the statements of DbTransaction
+type statementSource struct {
+	hash     []byte
+	contract string
+}
+
+// dbTxKey is the context key of DbTransaction of the gorm statements
+type dbTxKey struct{}
+
+var (
+	// slowThreshold is the threshold of the slow statements, zero disables the log
+	slowThreshold atomic.Int64
+
+	slowStatements struct {
+		sync.Mutex
+		list []SlowStatement
+		next int
+	}
+)
+
+// SetSlowStatementThreshold sets the threshold of the slow statements, zero disables the log
+func SetSlowStatementThreshold(threshold time.Duration) {
+	slowThreshold.Store(int64(threshold))
+}
+
+// GetSlowStatements returns the recent slow statements from the latest one
+func GetSlowStatements() []SlowStatement {
+	slowStatements.Lock()
+	defer slowStatements.Unlock()
+	list, count := make([]SlowStatement, 0, len(slowStatements.list)), len(slowStatements.list)
+	for i := 1; i <= count; i++ {
+		list = append(list, slowStatements.list[(slowStatements.next-i+count)%count])
+	}
+	return list
+}
+
+func addSlowStatement(s SlowStatement) {
+	slowStatements.Lock()
+	defer slowStatements.Unlock()
+	if len(slowStatements.list) < slowStatementsSize {
+		slowStatements.list = append(slowStatements.list, s)
+		slowStatements.next = len(slowStatements.list) % slowStatementsSize
+		return
+	}
+	slowStatements.list[slowStatements.next] = s
+	slowStatements.next = (slowStatements.next + 1) % slowStatementsSize
+}
+
+// SetStatementSource sets the transaction which executes the following statements, they are
+// attributed to it in the slow statements
+func (tr *DbTransaction) SetStatementSource(hash []byte, contract string) {
+	tr.source.Store(&statementSource{hash: hash, contract: contract})
+}
+
+// statementLogger records the slow statements of DbTransaction, the fast statements cost one time check
+type statementLogger struct {
+	logger.Interface
+}
+
+func (l statementLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return statementLogger{l.Interface.LogMode(level)}
+}
+
+func (l statementLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if threshold := slowThreshold.Load(); threshold > 0 {
+		if elapsed := time.Since(begin); elapsed >= time.Duration(threshold) {
+			recordSlowStatement(ctx, begin, elapsed, fc)
+		}
+	}
+	l.Interface.Trace(ctx, begin, fc, err)
+}
+
+func recordSlowStatement(ctx context.Context, begin time.Time, elapsed time.Duration, fc func() (string, int64)) {
+	tr, ok := ctx.Value(dbTxKey{}).(*DbTransaction)
+	if !ok {
+		return
+	}
+	sql, rows := fc()
+	s := SlowStatement{Time: begin, Duration: elapsed.Milliseconds(), SQL: sql, Rows: rows}
+	if src := tr.source.Load(); src != nil {
+		s.TxHash, s.Contract = hex.EncodeToString(src.hash), src.contract
+	}
+	addSlowStatement(s)
+	if statsd.Client != nil {
+		statsd.Client.Inc(statsd.SlowStatements, 1, 1.0)
+	}
+	tr.GetLogger().WithFields(log.Fields{"type": consts.DBError, "duration": s.Duration, "rows": rows,
+		"tx_hash": s.TxHash, "contract": s.Contract, "query": sql}).Warning("slow statement")
+}
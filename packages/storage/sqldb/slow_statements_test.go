@@ -0,0 +1,78 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+func resetSlowStatements(threshold time.Duration) {
+	SetSlowStatementThreshold(threshold)
+	slowStatements.Lock()
+	slowStatements.list, slowStatements.next = nil, 0
+	slowStatements.Unlock()
+}
+
+// TestSlowStatementSource checks that the slow statement of the transaction is recorded
+// with the hash and the contract of the block transaction
+func TestSlowStatementSource(t *testing.T) {
+	resetSlowStatements(20 * time.Millisecond)
+	defer resetSlowStatements(0)
+
+	l := statementLogger{logger.Default.LogMode(logger.Silent)}
+	tr := &DbTransaction{}
+	ctx := context.WithValue(context.Background(), dbTxKey{}, tr)
+	hash := []byte{1, 2, 3}
+	tr.SetStatementSource(hash, "@1MainCondition")
+
+	trace := func(ctx context.Context, sql string, duration time.Duration) {
+		begin := time.Now()
+		time.Sleep(duration)
+		l.Trace(ctx, begin, func() (string, int64) { return sql, 1 }, nil)
+	}
+	trace(ctx, "SELECT pg_sleep(0.03)", 30*time.Millisecond)
+	trace(ctx, "SELECT 1", 0)
+	// the statements of the other connections aren't recorded
+	trace(context.Background(), "SELECT pg_sleep(0.03)", 30*time.Millisecond)
+
+	list := GetSlowStatements()
+	if len(list) != 1 {
+		t.Fatalf("expected 1 slow statement, got %d", len(list))
+	}
+	s := list[0]
+	if s.SQL != "SELECT pg_sleep(0.03)" || s.TxHash != hex.EncodeToString(hash) ||
+		s.Contract != "@1MainCondition" || s.Duration < 20 {
+		t.Errorf("wrong slow statement %+v", s)
+	}
+}
+
+// TestSlowStatementsSize checks that only the recent slow statements are kept
+func TestSlowStatementsSize(t *testing.T) {
+	resetSlowStatements(time.Millisecond)
+	defer resetSlowStatements(0)
+
+	l := statementLogger{logger.Default.LogMode(logger.Silent)}
+	ctx := context.WithValue(context.Background(), dbTxKey{}, &DbTransaction{})
+	for i := 0; i < slowStatementsSize+10; i++ {
+		sql := fmt.Sprintf("SELECT %d", i)
+		l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return sql, 0 }, nil)
+	}
+	list := GetSlowStatements()
+	if len(list) != slowStatementsSize {
+		t.Fatalf("expected %d slow statements, got %d", slowStatementsSize, len(list))
+	}
+	for i, s := range list {
+		if want := fmt.Sprintf("SELECT %d", slowStatementsSize+9-i); s.SQL != want {
+			t.Fatalf("%d: expected %s, got %s", i, want, s.SQL)
+		}
+	}
+}
@@ -29,14 +29,14 @@ func (bi *Snippet) TableName() string {
 	return `1_snippets`
 }
 
-// Get is retrieving model from database
+// Get is retrieving model from database, the soft-deleted row isn't found
 func (bi *Snippet) Get(name string) (bool, error) {
-	return isFound(DBConn.Where("ecosystem=? and name = ?", bi.ecosystem, name).First(bi))
+	return isFound(DBConn.Where("ecosystem=? and name = ? and deleted = 0", bi.ecosystem, name).First(bi))
 }
 
 // GetByApp returns all interface blocks belonging to selected app
 func (bi *Snippet) GetByApp(appID int64, ecosystemID int64) ([]Snippet, error) {
 	var result []Snippet
-	err := DBConn.Select("id, name").Where("app_id = ? and ecosystem = ?", appID, ecosystemID).Find(&result).Error
+	err := DBConn.Select("id, name").Where("app_id = ? and ecosystem = ? and deleted = 0", appID, ecosystemID).Find(&result).Error
 	return result, err
 }
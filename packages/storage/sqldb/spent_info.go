@@ -29,6 +29,8 @@ type SpentInfo struct {
 	Ecosystem    int64
 	BlockId      int64
 	Type         int32
+	// UtxoCommitments is the Pedersen commitment of the confidential output, its OutputValue is 0
+	UtxoCommitments []byte
 }
 
 type KeyUTXO struct {
@@ -38,6 +40,11 @@ type KeyUTXO struct {
 	// Asset        string
 }
 
+// Confidential returns true if the amount of the output is hidden by the commitment
+func (si *SpentInfo) Confidential() bool {
+	return len(si.UtxoCommitments) > 0
+}
+
 func (k *KeyUTXO) String() string {
 	return fmt.Sprintf("%d%s%d", k.Ecosystem, "@", k.KeyId)
 }
@@ -66,7 +73,7 @@ func CreateSpentInfoBatches(dbTx *gorm.DB, spentInfos []SpentInfo) error {
 
 func GetTxOutputsEcosystem(db *DbTransaction, ecosystem int64, keyIds []int64) ([]SpentInfo, error) {
 	query :=
-		` SELECT si.output_tx_hash, si.output_index, si.output_key_id, si.output_value, si.ecosystem, si.block_id
+		` SELECT si.output_tx_hash, si.output_index, si.output_key_id, si.output_value, si.ecosystem, si.block_id, si.utxo_commitments
 		FROM spent_info si LEFT JOIN log_transactions AS tr ON si.output_tx_hash = tr.hash
 		WHERE si.ecosystem = ? AND si.output_key_id IN ? AND  si.input_tx_hash IS NULL
 		ORDER BY si.output_key_id, si.block_id ASC, tr.timestamp ASC `
@@ -80,7 +87,7 @@ func GetTxOutputsEcosystem(db *DbTransaction, ecosystem int64, keyIds []int64) (
 
 func GetTxOutputs(db *DbTransaction, keyIds []int64) ([]SpentInfo, error) {
 	query :=
-		` SELECT si.output_tx_hash, si.output_index, si.output_key_id, si.output_value, si.ecosystem, si.block_id
+		` SELECT si.output_tx_hash, si.output_index, si.output_key_id, si.output_value, si.ecosystem, si.block_id, si.utxo_commitments
 		FROM spent_info si LEFT JOIN log_transactions AS tr ON si.output_tx_hash = tr.hash
 		WHERE si.output_key_id IN ? AND si.input_tx_hash IS NULL
 		ORDER BY si.output_key_id, si.block_id ASC, tr.timestamp ASC `
@@ -0,0 +1,288 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The operations of the state diff
+const (
+	StateInsert = "insert"
+	StateUpdate = "update"
+	StateDelete = "delete"
+)
+
+var (
+	// ErrNotDML is returned by ParseBinLogSQL for the statements which don't change the rows, e.g. DDL
+	ErrNotDML = errors.New("statement isn't insert, update or delete")
+	// ErrBinLogSQL is returned for the malformed statement
+	ErrBinLogSQL = errors.New("malformed statement")
+)
+
+// StateDiff is the change of the table rows by the DML statement of the block. The statement doesn't
+// keep the old values, so Before is the conditions of the changed rows, the old values are in rollback_tx
+type StateDiff struct {
+	Table     string            `json:"table"`
+	Operation string            `json:"operation"`
+	Before    map[string]string `json:"before,omitempty"`
+	After     map[string]string `json:"after,omitempty"`
+	// Where is the condition which isn't the list of the equalities, e.g. with like
+	Where string `json:"where,omitempty"`
+}
+
+// ParseBinLogSQL parses the statement of the binlog of the block. The values are unquoted literals or
+// the expressions as is, e.g. amount+'10'
+func ParseBinLogSQL(stmt string) (*StateDiff, error) {
+	stmt = strings.TrimRight(strings.TrimSpace(stmt), ";")
+	switch {
+	case hasPrefixFold(stmt, "INSERT INTO "):
+		return parseInsert(stmt[len("INSERT INTO "):])
+	case hasPrefixFold(stmt, "UPDATE "):
+		return parseUpdate(stmt[len("UPDATE "):])
+	case hasPrefixFold(stmt, "DELETE FROM "):
+		return parseDelete(stmt[len("DELETE FROM "):])
+	}
+	return nil, ErrNotDML
+}
+
+func parseInsert(stmt string) (*StateDiff, error) {
+	open := indexTop(stmt, "(")
+	if open < 0 {
+		return nil, errBinLogSQL("columns", stmt)
+	}
+	diff := &StateDiff{Table: unquoteIdent(stmt[:open]), Operation: StateInsert, After: make(map[string]string)}
+	columns, rest, ok := cutParens(stmt[open:])
+	if !ok {
+		return nil, errBinLogSQL("columns", stmt)
+	}
+	rest = strings.TrimSpace(rest)
+	if !hasPrefixFold(rest, "VALUES") {
+		return nil, errBinLogSQL("values", stmt)
+	}
+	values, _, ok := cutParens(strings.TrimSpace(rest[len("VALUES"):]))
+	if !ok {
+		return nil, errBinLogSQL("values", stmt)
+	}
+	names, list := splitTop(columns, ","), splitTop(values, ",")
+	if len(names) != len(list) {
+		return nil, errBinLogSQL("count of values", stmt)
+	}
+	for i, name := range names {
+		diff.After[unquoteIdent(name)] = unquoteValue(list[i])
+	}
+	return diff, nil
+}
+
+func parseUpdate(stmt string) (*StateDiff, error) {
+	set := indexTop(stmt, " SET ")
+	if set < 0 {
+		return nil, errBinLogSQL("set", stmt)
+	}
+	diff := &StateDiff{Table: unquoteIdent(stmt[:set]), Operation: StateUpdate, After: make(map[string]string)}
+	exprs, where := cutWhere(stmt[set+len(" SET "):])
+	for _, expr := range splitTop(exprs, ",") {
+		eq := indexTop(expr, "=")
+		if eq < 0 {
+			return nil, errBinLogSQL("set", stmt)
+		}
+		diff.After[unquoteIdent(expr[:eq])] = unquoteValue(expr[eq+1:])
+	}
+	diff.parseWhere(where)
+	return diff, nil
+}
+
+func parseDelete(stmt string) (*StateDiff, error) {
+	table, where := cutWhere(stmt)
+	diff := &StateDiff{Table: unquoteIdent(table), Operation: StateDelete}
+	diff.parseWhere(where)
+	return diff, nil
+}
+
+// parseWhere sets the equalities of the condition to Before, the other condition is kept in Where
+func (d *StateDiff) parseWhere(where string) {
+	where = trimParens(where)
+	if len(where) == 0 {
+		return
+	}
+	d.Before = make(map[string]string)
+	for _, cond := range splitTop(where, " AND ") {
+		cond = trimParens(cond)
+		eq := indexTop(cond, "=")
+		if eq <= 0 || strings.ContainsAny(cond[eq-1:eq], "<>!") || strings.ContainsAny(cond[:eq], "'(") ||
+			indexTop(cond, " OR ") >= 0 {
+			d.Before, d.Where = nil, where
+			return
+		}
+		d.Before[unquoteIdent(cond[:eq])] = unquoteValue(cond[eq+1:])
+	}
+}
+
+func errBinLogSQL(part, stmt string) error {
+	if len(stmt) > 100 {
+		stmt = stmt[:100] + "..."
+	}
+	return fmt.Errorf("%w: %s of %s", ErrBinLogSQL, part, stmt)
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// indexTop returns the index of sep which is out of the quotes and the parentheses, the case of sep is
+// ignored. It returns -1 if there is no sep
+func indexTop(s, sep string) int {
+	var (
+		depth int
+		quote byte
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				if i+1 < len(s) && s[i+1] == quote {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+			continue
+		}
+		if depth == 0 && hasPrefixFold(s[i:], sep) {
+			return i
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+	}
+	return -1
+}
+
+func splitTop(s, sep string) (list []string) {
+	for {
+		i := indexTop(s, sep)
+		if i < 0 {
+			return append(list, s)
+		}
+		list = append(list, s[:i])
+		s = s[i+len(sep):]
+	}
+}
+
+// cutParens returns the content of the parentheses at the beginning of s and the rest of s
+func cutParens(s string) (inside, rest string, ok bool) {
+	if !strings.HasPrefix(s, "(") {
+		return "", s, false
+	}
+	end := indexTop(s[1:], ")")
+	if end < 0 {
+		return "", s, false
+	}
+	return s[1 : end+1], s[end+2:], true
+}
+
+// trimParens removes the parentheses which enclose all s
+func trimParens(s string) string {
+	s = strings.TrimSpace(s)
+	for {
+		inside, rest, ok := cutParens(s)
+		if !ok || len(strings.TrimSpace(rest)) > 0 {
+			return s
+		}
+		s = strings.TrimSpace(inside)
+	}
+}
+
+func cutWhere(s string) (before, where string) {
+	if i := indexTop(s, " WHERE "); i >= 0 {
+		return s[:i], s[i+len(" WHERE "):]
+	}
+	return s, ""
+}
+
+func unquoteIdent(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
+
+// unquoteValue returns the string literal without the quotes or the expression as is
+func unquoteValue(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '\'' {
+		return s
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			i++
+			continue
+		}
+		if i != len(s)-1 {
+			// the literal is a part of the expression
+			return s
+		}
+	}
+	return strings.ReplaceAll(s[1:len(s)-1], `''`, `'`)
+}
+
+// MarshalBinLogSQL returns the binlog of the block which is stored in block_chain
+func MarshalBinLogSQL(stmts [][]byte) ([]byte, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+	list := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		list[i] = string(stmt)
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinLogSQL returns the statements of the binlog of the block
+func UnmarshalBinLogSQL(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	err = json.Unmarshal(data, &list)
+	return list, err
+}
@@ -0,0 +1,76 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseBinLogSQL(t *testing.T) {
+	for _, c := range []struct {
+		stmt string
+		diff StateDiff
+	}{
+		{
+			`INSERT INTO "1_keys" (id,"pub","amount",ecosystem) VALUES ('12', decode('0a','HEX'), '100', '1')`,
+			StateDiff{Table: "1_keys", Operation: StateInsert,
+				After: map[string]string{"id": "12", "pub": "decode('0a','HEX')", "amount": "100", "ecosystem": "1"}},
+		},
+		{
+			`UPDATE "1_keys" SET amount=amount+'10',"name"='it''s, mine' WHERE ("id" = '12') and ("ecosystem" = '1')`,
+			StateDiff{Table: "1_keys", Operation: StateUpdate,
+				After:  map[string]string{"amount": "amount+'10'", "name": "it's, mine"},
+				Before: map[string]string{"id": "12", "ecosystem": "1"}},
+		},
+		{
+			`UPDATE "2_pages" SET "value"= NULL,info=info::jsonb || '{"a":"1"}'::jsonb WHERE "name" like '%main%'`,
+			StateDiff{Table: "2_pages", Operation: StateUpdate,
+				After: map[string]string{"value": "NULL", "info": `info::jsonb || '{"a":"1"}'::jsonb`},
+				Where: `"name" like '%main%'`},
+		},
+		{
+			`DELETE FROM "1_notifications" WHERE "id"='5';`,
+			StateDiff{Table: "1_notifications", Operation: StateDelete, Before: map[string]string{"id": "5"}},
+		},
+		{
+			`DELETE FROM "1_buffer_data" WHERE ("id" >= '5')`,
+			StateDiff{Table: "1_buffer_data", Operation: StateDelete, Where: `"id" >= '5'`},
+		},
+	} {
+		diff, err := ParseBinLogSQL(c.stmt)
+		if err != nil {
+			t.Errorf("%s: %v", c.stmt, err)
+			continue
+		}
+		if !reflect.DeepEqual(*diff, c.diff) {
+			t.Errorf("%s:\nexpected %+v\ngot      %+v", c.stmt, c.diff, *diff)
+		}
+	}
+
+	if _, err := ParseBinLogSQL(`CREATE TABLE "1_test" (id bigint)`); !errors.Is(err, ErrNotDML) {
+		t.Errorf("expected %v got %v", ErrNotDML, err)
+	}
+	if _, err := ParseBinLogSQL(`INSERT INTO "1_keys" (id,amount) VALUES ('1')`); !errors.Is(err, ErrBinLogSQL) {
+		t.Errorf("expected %v got %v", ErrBinLogSQL, err)
+	}
+}
+
+func TestBinLogSQL(t *testing.T) {
+	stmts := [][]byte{[]byte(`UPDATE "1_keys" SET amount='1' WHERE "id"='2'`), []byte(`DELETE FROM "1_keys" WHERE "id"='3'`)}
+	data, err := MarshalBinLogSQL(stmts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := UnmarshalBinLogSQL(data)
+	if err != nil || len(list) != 2 || list[1] != string(stmts[1]) {
+		t.Errorf("expected statements back, got %q, %v", list, err)
+	}
+	if data, _ = MarshalBinLogSQL(nil); data != nil {
+		t.Errorf("expected empty binlog, got %x", data)
+	}
+}
@@ -0,0 +1,59 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SysparSnapshot is model of the snapshot of the platform parameters which has been imported by the node.
+// The table is local to the node, the changes of the parameters are replayed since the block of the last snapshot
+type SysparSnapshot struct {
+	BlockID    int64     `gorm:"primary_key;not null"`
+	NodeKeyID  int64     `gorm:"not null"`
+	ImportedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (s *SysparSnapshot) TableName() string {
+	return "syspar_snapshots"
+}
+
+// GetPlatformParametersSnapshot returns the platform parameters ordered by id and the last block of the
+// chain, they are read from the same snapshot
+func GetPlatformParametersSnapshot() (blockID int64, list []PlatformParameter, err error) {
+	err = DBConn.Transaction(func(db *gorm.DB) error {
+		info := &InfoBlock{}
+		if err := db.Last(info).Error; err != nil {
+			return err
+		}
+		blockID = info.BlockID
+		return db.Order("id asc").Find(&list).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	return
+}
+
+// ImportPlatformParameters replaces the platform parameters with the same ids by list and records the snapshot
+func ImportPlatformParameters(snapshot *SysparSnapshot, list []PlatformParameter) error {
+	return DBConn.Transaction(func(db *gorm.DB) error {
+		for i := range list {
+			if err := db.Save(&list[i]).Error; err != nil {
+				return err
+			}
+		}
+		return db.Save(snapshot).Error
+	})
+}
+
+// GetLastSysparSnapshot returns the snapshot of the platform parameters with the greatest block
+func GetLastSysparSnapshot() (*SysparSnapshot, bool, error) {
+	s := &SysparSnapshot{}
+	found, err := isFound(DBConn.Order("block_id desc").First(s))
+	return s, found, err
+}
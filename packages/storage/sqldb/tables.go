@@ -179,18 +179,18 @@ func (dbTx *DbTransaction) GetRowConditionsByTableNameAndID(tblname string, id i
 	return dbTx.Single(sql, id).String()
 }
 
-func GetTableQuery(table string, ecosystemID int64) *gorm.DB {
+func GetTableQuery(dbTx *DbTransaction, table string, ecosystemID int64) *gorm.DB {
 	if converter.FirstEcosystemTables[table] {
-		return GetDB(nil).Table("1_"+table).Where("ecosystem = ?", ecosystemID)
+		return GetDB(dbTx).Table("1_"+table).Where("ecosystem = ?", ecosystemID)
 	}
 
-	return GetDB(nil).Table(converter.ParseTable(table, ecosystemID))
+	return GetDB(dbTx).Table(converter.ParseTable(table, ecosystemID))
 }
 
-func GetTableListQuery(table string, ecosystemID int64) *gorm.DB {
+func GetTableListQuery(dbTx *DbTransaction, table string, ecosystemID int64) *gorm.DB {
 	if converter.FirstEcosystemTables[table] {
-		return DBConn.Table("1_" + table)
+		return GetDB(dbTx).Table("1_" + table)
 	}
 
-	return GetDB(nil).Table(converter.ParseTable(table, ecosystemID))
+	return GetDB(dbTx).Table(converter.ParseTable(table, ecosystemID))
 }
@@ -0,0 +1,67 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// TimeLock is model of the tokens locked by the sender for the recipient. The tokens mature when
+// both the block time reaches UnlockTime and the block reaches UnlockBlock. If VestingEnd is set the
+// tokens vest linearly from CreatedAt to VestingEnd and the matured part can be released
+type TimeLock struct {
+	ID          int64           `gorm:"primary_key;not null" json:"id"`
+	SenderID    int64           `gorm:"not null" json:"sender_id"`
+	RecipientID int64           `gorm:"not null" json:"recipient_id"`
+	Amount      decimal.Decimal `gorm:"not null" json:"amount"`
+	Released    decimal.Decimal `gorm:"not null" json:"released"`
+	CreatedAt   int64           `gorm:"not null" json:"created_at"`
+	UnlockTime  int64           `gorm:"not null" json:"unlock_time"`
+	UnlockBlock int64           `gorm:"not null" json:"unlock_block"`
+	VestingEnd  int64           `gorm:"not null" json:"vesting_end"`
+	BlockID     int64           `gorm:"not null" json:"block_id"`
+	Ecosystem   int64           `gorm:"not null" json:"ecosystem"`
+}
+
+// TableName returns name of table
+func (l *TimeLock) TableName() string {
+	return "1_time_locks"
+}
+
+// Get is retrieving the lock of the ecosystem within the transaction
+func (l *TimeLock) Get(dbTx *DbTransaction, ecosystem, id int64) (bool, error) {
+	return isFound(GetDB(dbTx).Where("ecosystem = ? AND id = ?", ecosystem, id).First(l))
+}
+
+// GetTimeLocks returns the locks of the recipient in the ecosystem
+func GetTimeLocks(ecosystem, recipientID int64) ([]TimeLock, error) {
+	var locks []TimeLock
+	err := DBConn.Where("ecosystem = ? AND recipient_id = ?", ecosystem, recipientID).Order("id").Find(&locks).Error
+	return locks, err
+}
+
+// Vested returns the matured part of the amount at the block. The linear part is rounded down
+// to the integer amount, so every node computes the same value from the block time
+func (l *TimeLock) Vested(blockTime, blockID int64) decimal.Decimal {
+	if blockTime < l.UnlockTime || blockID < l.UnlockBlock {
+		return decimal.Zero
+	}
+	if l.VestingEnd <= l.CreatedAt || blockTime >= l.VestingEnd {
+		return l.Amount
+	}
+	vested, _ := l.Amount.Mul(decimal.NewFromInt(blockTime-l.CreatedAt)).
+		QuoRem(decimal.NewFromInt(l.VestingEnd-l.CreatedAt), 0)
+	return vested
+}
+
+// Releasable returns the matured part which hasn't been released yet
+func (l *TimeLock) Releasable(blockTime, blockID int64) decimal.Decimal {
+	amount := l.Vested(blockTime, blockID).Sub(l.Released)
+	if amount.IsNegative() {
+		return decimal.Zero
+	}
+	return amount
+}
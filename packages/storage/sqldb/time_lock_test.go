@@ -0,0 +1,48 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeLockCliff(t *testing.T) {
+	lock := &TimeLock{Amount: decimal.NewFromInt(1000), CreatedAt: 100, UnlockTime: 200, UnlockBlock: 10}
+	assert.True(t, lock.Releasable(199, 20).IsZero(), "released before the unlock time")
+	assert.True(t, lock.Releasable(300, 9).IsZero(), "released before the unlock block")
+	assert.Equal(t, "1000", lock.Releasable(200, 10).String())
+
+	lock.Released = lock.Amount
+	assert.True(t, lock.Releasable(300, 20).IsZero(), "released twice")
+}
+
+func TestTimeLockLinear(t *testing.T) {
+	lock := &TimeLock{Amount: decimal.NewFromInt(1000), CreatedAt: 100, VestingEnd: 400}
+	for _, item := range []struct {
+		time     int64
+		released int64
+		want     string
+	}{
+		{100, 0, "0"},
+		{101, 0, "3"},
+		{250, 0, "500"},
+		{250, 300, "200"},
+		{399, 500, "496"},
+		{400, 500, "500"},
+		{1000, 0, "1000"},
+	} {
+		lock.Released = decimal.NewFromInt(item.released)
+		assert.Equal(t, item.want, lock.Releasable(item.time, 1).String(), "time %d released %d", item.time, item.released)
+	}
+
+	// the vesting starts at the cliff, the vested part before it isn't released
+	lock = &TimeLock{Amount: decimal.NewFromInt(1000), CreatedAt: 100, UnlockTime: 200, VestingEnd: 400}
+	assert.True(t, lock.Releasable(199, 1).IsZero())
+	assert.Equal(t, "333", lock.Releasable(200, 1).String())
+}
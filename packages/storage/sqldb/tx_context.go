@@ -0,0 +1,82 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// controlKey marks the statements of the savepoints, they aren't canceled by the context of DbTransaction
+const controlKey = "ibax:control"
+
+type txContext struct {
+	ctx context.Context
+}
+
+// SetContext sets the context which cancels the following statements of the transaction, nil doesn't cancel
+// them. The running statement isn't interrupted, so the connection and the savepoints stay usable
+func (tr *DbTransaction) SetContext(ctx context.Context) {
+	if tr == nil {
+		return
+	}
+	if ctx == nil {
+		tr.ctx.Store(nil)
+		return
+	}
+	tr.ctx.Store(&txContext{ctx: ctx})
+}
+
+// contextErr returns the cause of the done context of the transaction
+func (tr *DbTransaction) contextErr() error {
+	c := tr.ctx.Load()
+	if c == nil || c.ctx.Err() == nil {
+		return nil
+	}
+	return context.Cause(c.ctx)
+}
+
+// control returns the connection of the statements which aren't canceled
+func (tr *DbTransaction) control() *gorm.DB {
+	return tr.Connection().Set(controlKey, true)
+}
+
+// checkContext fails the statement of DbTransaction whose context is done
+func checkContext(db *gorm.DB) {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return
+	}
+	tr, ok := db.Statement.Context.Value(dbTxKey{}).(*DbTransaction)
+	if !ok {
+		return
+	}
+	if _, ok := db.Get(controlKey); ok {
+		return
+	}
+	if err := tr.contextErr(); err != nil {
+		db.AddError(err)
+	}
+}
+
+// registerContextCallbacks checks the context of DbTransaction before all the statements
+func registerContextCallbacks(db *gorm.DB) error {
+	const name = "ibax:check_context"
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("*").Register(name, checkContext),
+		cb.Query().Before("*").Register(name, checkContext),
+		cb.Update().Before("*").Register(name, checkContext),
+		cb.Delete().Before("*").Register(name, checkContext),
+		cb.Row().Before("*").Register(name, checkContext),
+		cb.Raw().Before("*").Register(name, checkContext),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package sqldb
+
+import (
+	"gorm.io/gorm"
+)
+
+// TxReceipt is model of the execution receipt of the played transaction. Fuel is the fuel used by the
+// contract, RowsTouched is the number of the rows written by the transaction
+type TxReceipt struct {
+	TxHash       []byte `gorm:"primary_key;not null"`
+	BlockID      int64  `gorm:"not null"`
+	EcosystemID  int64  `gorm:"not null"`
+	ContractName string `gorm:"not null"`
+	Fuel         int64  `gorm:"not null"`
+	RowsTouched  int64  `gorm:"not null"`
+	Events       int64  `gorm:"not null"`
+	Status       int64  `gorm:"not null"`
+}
+
+// TableName returns name of table
+func (TxReceipt) TableName() string {
+	return "tx_receipts"
+}
+
+// CreateTxReceiptBatches inserts the receipts of the transactions of the block
+func CreateTxReceiptBatches(dbTx *gorm.DB, list []*TxReceipt) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return dbTx.Create(&list).Error
+}
+
+// DeleteTxReceipts deletes the receipts of the transactions of the block
+func DeleteTxReceipts(dbTx *DbTransaction, blockID int64) error {
+	return GetDB(dbTx).Where("block_id = ?", blockID).Delete(&TxReceipt{}).Error
+}
+
+// GetByHash returns the receipt of the transaction
+func (r *TxReceipt) GetByHash(dbTx *DbTransaction, hash []byte) (bool, error) {
+	return isFound(GetDB(dbTx).Where("tx_hash = ?", hash).First(r))
+}
@@ -1,6 +1,7 @@
 package sqldb
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -25,7 +26,8 @@ func UpdateTxInputs(inputTxHash []byte, txInputsMapCtx map[KeyUTXO][]SpentInfo,
 	lock.Lock()
 	defer lock.Unlock()
 	var inputIndex int32
-	for txKeyUTXO, _ := range txInputsMapCtx {
+	// the input indexes are stored so the keys are walked in the same order on every node
+	for _, txKeyUTXO := range sortedKeysUTXO(txInputsMapCtx) {
 		spentInfos := outputsMap[txKeyUTXO]
 		for i, info := range spentInfos {
 			if len(info.InputTxHash) == 0 {
@@ -37,6 +39,20 @@ func UpdateTxInputs(inputTxHash []byte, txInputsMapCtx map[KeyUTXO][]SpentInfo,
 	}
 }
 
+func sortedKeysUTXO(m map[KeyUTXO][]SpentInfo) []KeyUTXO {
+	keys := make([]KeyUTXO, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Ecosystem != keys[j].Ecosystem {
+			return keys[i].Ecosystem < keys[j].Ecosystem
+		}
+		return keys[i].KeyId < keys[j].KeyId
+	})
+	return keys
+}
+
 func PutAllOutputsMap(outputs []SpentInfo, outputsMap map[KeyUTXO][]SpentInfo) {
 	lock.Lock()
 	defer lock.Unlock()
@@ -6,82 +6,370 @@
 package transaction
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/IBAX-io/go-ibax/packages/conf"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	log "github.com/sirupsen/logrus"
 )
 
-type banKey struct {
-	Time time.Time   // banned till
-	Bad  []time.Time // time of bad tx
-}
+// BanReason is the source of the bad transaction which is counted for the ban of the key
+type BanReason string
 
-var (
-	banList = make(map[int64]banKey)
-	mutex   = &sync.RWMutex{}
+const (
+	// BanReasonIngress is the transaction which is rejected by the checks of the api
+	BanReasonIngress BanReason = "ingress"
+	// BanReasonQueue is the transaction of the queue which fails the checks
+	BanReasonQueue BanReason = "queue"
+	// BanReasonBlock is the transaction which fails in the block
+	BanReasonBlock BanReason = "block"
 )
 
-// IsKeyBanned returns true if the key has been banned
-func IsKeyBanned(keyID int64) bool {
-	mutex.RLock()
-	if ban, ok := banList[keyID]; ok {
-		mutex.RUnlock()
-		now := time.Now()
-		if now.Before(ban.Time) {
+// BanPolicy is the thresholds of the ban of the keys which send the bad transactions
+type BanPolicy struct {
+	BadTx   int               // the bad transactions within BadTime which ban the key
+	BadTime time.Duration     // the decay window of the bad transactions
+	BanTime time.Duration     // the duration of the ban
+	MaxKeys int               // the limit of the tracked keys, zero doesn't limit them
+	Reasons map[BanReason]int // the bad transactions of the reason within BadTime which ban the key
+}
+
+// ConfigBanPolicy returns the ban policy of the node config
+func ConfigBanPolicy() BanPolicy {
+	c := conf.Config.BanKey
+	p := BanPolicy{
+		BadTx:   c.BadTx,
+		BadTime: time.Duration(c.BadTime) * time.Minute,
+		BanTime: time.Duration(c.BanTime) * time.Minute,
+		MaxKeys: c.MaxKeys,
+		Reasons: make(map[BanReason]int, len(c.ReasonBadTx)),
+	}
+	for reason, bad := range c.ReasonBadTx {
+		p.Reasons[BanReason(reason)] = bad
+	}
+	return p
+}
+
+// BanInfo is the state of the tracked key
+type BanInfo struct {
+	KeyID  int64             `json:"key_id"`
+	Till   int64             `json:"till,omitempty"`   // unix time of the end of the ban
+	Reason BanReason         `json:"reason,omitempty"` // the reason of the bad transaction which bans the key
+	Bad    map[BanReason]int `json:"bad"`              // the bad transactions within the decay window
+}
+
+// BanStats is the counters of the ban manager since the start of the node
+type BanStats struct {
+	Tracked int                 `json:"tracked"`
+	Banned  int                 `json:"banned"`
+	BadTx   map[BanReason]int64 `json:"bad_tx"`
+	Bans    map[BanReason]int64 `json:"bans"`
+	Lifts   int64               `json:"lifts"`
+}
+
+type badTx struct {
+	time   time.Time
+	reason BanReason
+}
+
+type keyBan struct {
+	till   time.Time // banned till
+	reason BanReason
+	bad    []badTx // the bad transactions in the order of the time
+}
+
+// decay drops the bad transactions before the window
+func (kb *keyBan) decay(now time.Time, window time.Duration) {
+	i := 0
+	for i < len(kb.bad) && !kb.bad[i].time.Add(window).After(now) {
+		i++
+	}
+	kb.bad = kb.bad[i:]
+}
+
+func (kb *keyBan) expired(now time.Time) bool {
+	return !now.Before(kb.till) && len(kb.bad) == 0
+}
+
+func (kb *keyBan) last() time.Time {
+	if len(kb.bad) == 0 {
+		return kb.till
+	}
+	return kb.bad[len(kb.bad)-1].time
+}
+
+func (kb *keyBan) counts() map[BanReason]int {
+	counts := make(map[BanReason]int)
+	for _, b := range kb.bad {
+		counts[b.reason]++
+	}
+	return counts
+}
+
+// BanManager bans the keys which send the bad transactions. The list is kept in the memory of the node,
+// the ban expires after BanTime and the key is forgotten after its bad transactions leave the decay window
+type BanManager struct {
+	mutex  sync.RWMutex
+	policy func() BanPolicy
+	keys   map[int64]*keyBan
+	stats  BanStats
+}
+
+// NewBanManager returns the ban manager which reads the thresholds from policy
+func NewBanManager(policy func() BanPolicy) *BanManager {
+	return &BanManager{
+		policy: policy,
+		keys:   make(map[int64]*keyBan),
+		stats:  BanStats{BadTx: make(map[BanReason]int64), Bans: make(map[BanReason]int64)},
+	}
+}
+
+var bans = NewBanManager(ConfigBanPolicy)
+
+// Bans returns the ban manager of the node
+func Bans() *BanManager {
+	return bans
+}
+
+// IsBanned returns true if the key is banned now
+func (m *BanManager) IsBanned(keyID int64) bool {
+	now := time.Now()
+	m.mutex.RLock()
+	kb, ok := m.keys[keyID]
+	banned := ok && now.Before(kb.till)
+	m.mutex.RUnlock()
+	if !ok || banned {
+		return banned
+	}
+	window := m.policy().BadTime
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if kb, ok = m.keys[keyID]; ok {
+		kb.decay(now, window)
+		if kb.expired(now) {
+			delete(m.keys, keyID)
+		}
+	}
+	return false
+}
+
+// BannedTill returns the time which the key is banned till, it's zero if the key isn't tracked
+func (m *BanManager) BannedTill(keyID int64) time.Time {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if kb, ok := m.keys[keyID]; ok {
+		return kb.till
+	}
+	return time.Time{}
+}
+
+// Add counts the bad transaction of the key and returns the time the key is banned till
+// or zero time if the key isn't banned by it
+func (m *BanManager) Add(keyID int64, reason BanReason) (till time.Time) {
+	p := m.policy()
+	now := time.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	kb, ok := m.keys[keyID]
+	if !ok {
+		kb = &keyBan{}
+		m.keys[keyID] = kb
+	}
+	kb.decay(now, p.BadTime)
+	kb.bad = append(kb.bad, badTx{time: now, reason: reason})
+	m.stats.BadTx[reason]++
+	if p.exceeded(len(kb.bad), reason, kb.counts()[reason]) {
+		kb.till, kb.reason = now.Add(p.BanTime), reason
+		m.stats.Bans[reason]++
+		till = kb.till
+	}
+	m.bound(now, p, keyID)
+	return
+}
+
+// exceeded returns true if bad transactions of which reasonBad have the reason ban the key
+func (p BanPolicy) exceeded(bad int, reason BanReason, reasonBad int) bool {
+	if p.BadTx > 0 && bad >= p.BadTx {
+		return true
+	}
+	limit := p.Reasons[reason]
+	return limit > 0 && reasonBad >= limit
+}
+
+// bound evicts the keys over MaxKeys, the expired keys go first, then the keys without the ban
+// and then the keys whose last bad transaction is the oldest. The key which is added isn't evicted
+func (m *BanManager) bound(now time.Time, p BanPolicy, added int64) {
+	if p.MaxKeys <= 0 || len(m.keys) <= p.MaxKeys {
+		return
+	}
+	for keyID, kb := range m.keys {
+		kb.decay(now, p.BadTime)
+		if keyID != added && kb.expired(now) {
+			delete(m.keys, keyID)
+		}
+	}
+	for len(m.keys) > p.MaxKeys {
+		var (
+			victimID int64
+			victim   *keyBan
+		)
+		for keyID, kb := range m.keys {
+			if keyID == added {
+				continue
+			}
+			if victim == nil || evictBefore(now, kb, victim) {
+				victimID, victim = keyID, kb
+			}
+		}
+		if victim == nil {
+			return
+		}
+		delete(m.keys, victimID)
+	}
+}
+
+func evictBefore(now time.Time, a, b *keyBan) bool {
+	if bannedA, bannedB := now.Before(a.till), now.Before(b.till); bannedA != bannedB {
+		return bannedB
+	}
+	return a.last().Before(b.last())
+}
+
+// WouldBeBanned returns true if the key is banned now or it's banned after bad more bad transactions
+// of the reasons, the ban list isn't changed
+func (m *BanManager) WouldBeBanned(keyID int64, bad map[BanReason]int) bool {
+	p := m.policy()
+	now := time.Now()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	counts := make(map[BanReason]int)
+	var total int
+	if kb, ok := m.keys[keyID]; ok {
+		if now.Before(kb.till) {
 			return true
 		}
-		for i := 0; i < conf.Config.BanKey.BadTx; i++ {
-			if ban.Bad[i].Add(time.Duration(conf.Config.BanKey.BadTime) * time.Minute).After(now) {
-				return false
+		for _, b := range kb.bad {
+			if b.time.Add(p.BadTime).After(now) {
+				counts[b.reason]++
+				total++
 			}
 		}
-		// Delete if time of all bad tx is old
-		mutex.Lock()
-		delete(banList, keyID)
-		mutex.Unlock()
-	} else {
-		mutex.RUnlock()
+	}
+	for reason, n := range bad {
+		if n <= 0 {
+			continue
+		}
+		counts[reason] += n
+		total += n
+		if p.exceeded(total, reason, counts[reason]) {
+			return true
+		}
 	}
 	return false
 }
 
+// Lift removes the key from the list and returns true if the key has been banned
+func (m *BanManager) Lift(keyID int64) bool {
+	now := time.Now()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	kb, ok := m.keys[keyID]
+	if !ok {
+		return false
+	}
+	delete(m.keys, keyID)
+	m.stats.Lifts++
+	return now.Before(kb.till)
+}
+
+// List returns the keys which are banned now in the order of the end of the ban
+func (m *BanManager) List() []BanInfo {
+	p := m.policy()
+	now := time.Now()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	list := make([]BanInfo, 0)
+	for keyID, kb := range m.keys {
+		if !now.Before(kb.till) {
+			continue
+		}
+		info := BanInfo{KeyID: keyID, Till: kb.till.Unix(), Reason: kb.reason, Bad: make(map[BanReason]int)}
+		for _, b := range kb.bad {
+			if b.time.Add(p.BadTime).After(now) {
+				info.Bad[b.reason]++
+			}
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Till != list[j].Till {
+			return list[i].Till < list[j].Till
+		}
+		return list[i].KeyID < list[j].KeyID
+	})
+	return list
+}
+
+// Stats returns the counters of the ban manager
+func (m *BanManager) Stats() BanStats {
+	now := time.Now()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	stats := BanStats{Tracked: len(m.keys), Lifts: m.stats.Lifts,
+		BadTx: make(map[BanReason]int64, len(m.stats.BadTx)), Bans: make(map[BanReason]int64, len(m.stats.Bans))}
+	for reason, n := range m.stats.BadTx {
+		stats.BadTx[reason] = n
+	}
+	for reason, n := range m.stats.Bans {
+		stats.Bans[reason] = n
+	}
+	for _, kb := range m.keys {
+		if now.Before(kb.till) {
+			stats.Banned++
+		}
+	}
+	return stats
+}
+
+// IsKeyBanned returns true if the key has been banned
+func IsKeyBanned(keyID int64) bool {
+	return bans.IsBanned(keyID)
+}
+
 // BannedTill returns the time that the user has been banned till
 func BannedTill(keyID int64) string {
-	mutex.RLock()
-	defer mutex.RUnlock()
-	if ban, ok := banList[keyID]; ok {
-		return ban.Time.Format(`2006-01-02 15:04:05`)
+	if till := bans.BannedTill(keyID); !till.IsZero() {
+		return till.Format(`2006-01-02 15:04:05`)
 	}
 	return ``
 }
 
+// WouldBeBanned returns true if the key is banned now or it's banned after bad more bad transactions
+// of the reasons, the ban list isn't changed
+func WouldBeBanned(keyID int64, bad map[BanReason]int) bool {
+	return bans.WouldBeBanned(keyID, bad)
+}
+
+// createAudit appends the entry to the audit log within the db transaction
+var createAudit = (*sqldb.AuditLog).Create
+
 // BadTxForBan adds info about bad tx of the key
-func BadTxForBan(keyID int64) {
-	var (
-		ban banKey
-		ok  bool
-	)
-	mutex.Lock()
-	defer mutex.Unlock()
-	now := time.Now()
-	if ban, ok = banList[keyID]; ok {
-		var bMin, count int
-		for i := 0; i < conf.Config.BanKey.BadTx; i++ {
-			if ban.Bad[i].Add(time.Duration(conf.Config.BanKey.BadTime) * time.Minute).After(now) {
-				count++
-			}
-			if i > bMin && ban.Bad[i].Before(ban.Bad[bMin]) {
-				bMin = i
-			}
-		}
-		ban.Bad[bMin] = now
-		if count >= conf.Config.BanKey.BadTx-1 {
-			ban.Time = now.Add(time.Duration(conf.Config.BanKey.BanTime) * time.Minute)
+func BadTxForBan(keyID int64, reason BanReason) {
+	if till := bans.Add(keyID, reason); !till.IsZero() {
+		audit := sqldb.NewAuditLog(sqldb.AuditKeyBan, keyID, 0, nil, []byte(fmt.Sprintf("%d,%d,%s", keyID, till.Unix(), reason)))
+		if err := createAudit(audit, nil); err != nil {
+			log.WithFields(log.Fields{"type": consts.DBError, "error": err, "action": audit.Action}).Error("writing audit log")
 		}
-	} else {
-		ban = banKey{Bad: make([]time.Time, conf.Config.BanKey.BadTx)}
-		ban.Bad[0] = time.Now()
 	}
-	banList[keyID] = ban
+}
+
+// LiftBan lifts the ban of the key, the lift is written to the audit log by the block of the transaction
+func LiftBan(keyID int64) {
+	if bans.Lift(keyID) {
+		log.WithFields(log.Fields{"key_id": keyID}).Info("ban of the key is lifted")
+	}
 }
@@ -0,0 +1,160 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ErrBanLiftSign is returned if the lift of the ban isn't signed by the node key
+var ErrBanLiftSign = errors.New("ban lift must be signed by the node key")
+
+// BanLiftParser lifts the ban of the key on the nodes which play the block of the transaction.
+// The ban isn't the state of the chain, so the transaction doesn't change the tables and the ban is
+// lifted after the block is committed
+type BanLiftParser struct {
+	Logger  *log.Entry     `msgpack:"-"`
+	Data    *types.BanLift `msgpack:"-"`
+	TxHash  []byte         `msgpack:"-"`
+	Payload []byte         // msgpack of Data
+}
+
+func (l *BanLiftParser) txType() byte                { return l.Data.TxType() }
+func (l *BanLiftParser) txHash() []byte              { return l.TxHash }
+func (l *BanLiftParser) txPayload() []byte           { return l.Payload }
+func (l *BanLiftParser) txTime() int64               { return l.Data.Time }
+func (l *BanLiftParser) txKeyID() int64              { return l.Data.KeyID }
+func (l *BanLiftParser) txExpedite() decimal.Decimal { return decimal.Decimal{} }
+
+func (l *BanLiftParser) Init(in *InToCxt) error {
+	l.Logger = log.WithFields(log.Fields{"tx_hash": fmt.Sprintf("%x", l.TxHash), "tx_type": l.txType()})
+	if in.Logger != nil {
+		l.Logger = in.Logger.WithFields(log.Fields{"tx_hash": fmt.Sprintf("%x", l.TxHash), "tx_type": l.txType()})
+	}
+	return nil
+}
+
+func (l *BanLiftParser) TxRollback() error { return nil }
+
+// Validate checks that the lift is signed by the key of the honor node
+func (l *BanLiftParser) Validate() error {
+	data := l.Data
+	if len(data.PublicKey) != consts.PubkeySizeLength {
+		return fmt.Errorf("wrong length of public key")
+	}
+	if data.KeyID != crypto.Address(data.PublicKey) {
+		return fmt.Errorf("key id %d doesn't match public key", data.KeyID)
+	}
+	if data.BannedID == 0 {
+		return ErrEmptyKey
+	}
+	if ok, err := crypto.Verify(data.PublicKey, data.Announcement(), data.Sign); !ok || err != nil {
+		return ErrBanLiftSign
+	}
+	if !syspar.IsHonorNodeKey(data.PublicKey) {
+		return syspar.ErrHonorNodeKey
+	}
+	return nil
+}
+
+func (l *BanLiftParser) Action(in *InToCxt, out *OutCtx) error {
+	if err := l.Validate(); err != nil {
+		l.Logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("validating ban lift")
+		return err
+	}
+	return nil
+}
+
+// NewBanLift returns the lift of the ban of bannedID which is signed by the node key
+func NewBanLift(nodeKey keystore.Signer, bannedID, timestamp int64) (*types.BanLift, error) {
+	data := &types.BanLift{
+		KeyID:     crypto.Address(nodeKey.PublicKey()),
+		Time:      timestamp,
+		BannedID:  bannedID,
+		PublicKey: nodeKey.PublicKey(),
+	}
+	var err error
+	if data.Sign, err = keystore.SignData(nodeKey, data.Announcement()); err != nil {
+		return nil, fmt.Errorf("signing by node key: %w", err)
+	}
+	return data, nil
+}
+
+// QueueBanLift validates the lift of the ban and adds its transaction to the queue
+func QueueBanLift(data *types.BanLift) ([]byte, error) {
+	l := &BanLiftParser{}
+	buf, err := l.BinMarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = l.Validate(); err != nil {
+		return nil, err
+	}
+	t := &sqldb.Transaction{
+		Hash:     l.TxHash,
+		Data:     buf,
+		Type:     types.BanLiftTxType,
+		KeyID:    data.KeyID,
+		HighRate: sqldb.TransactionRateApiContract,
+		Time:     data.Time,
+	}
+	if err = t.Create(nil); err != nil {
+		log.WithFields(log.Fields{"error": err, "type": consts.DBError}).Error("inserting tx to database")
+		return nil, err
+	}
+	return l.TxHash, nil
+}
+
+// BinMarshal returns the binary of the ban lift transaction
+func (l *BanLiftParser) BinMarshal(data *types.BanLift) ([]byte, error) {
+	payload, err := msgpack.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	l.Data, l.Payload, l.TxHash = data, payload, crypto.DoubleHash(payload)
+	buf, err := msgpack.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{data.TxType()}, buf...), nil
+}
+
+// Unmarshal decodes the transaction, the type byte has been read from the buffer
+func (l *BanLiftParser) Unmarshal(buffer *bytes.Buffer) error {
+	buffer.UnreadByte()
+	return l.decode(buffer.Bytes())
+}
+
+func (l *BanLiftParser) decode(data []byte) error {
+	if err := msgpack.Unmarshal(data[1:], l); err != nil {
+		return err
+	}
+	if err := checkEncoding(l.Payload); err != nil {
+		return err
+	}
+	l.Data = new(types.BanLift)
+	if err := msgpack.Unmarshal(l.Payload, l.Data); err != nil {
+		return err
+	}
+	l.TxHash = crypto.DoubleHash(l.Payload)
+	return nil
+}
+
+// BanLift returns the data of the ban lift transaction
+func (t *Transaction) BanLift() *types.BanLift {
+	return t.Inner.(*BanLiftParser).Data
+}
@@ -0,0 +1,127 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+func newTestBans(p BanPolicy) *BanManager {
+	return NewBanManager(func() BanPolicy { return p })
+}
+
+func TestWouldBeBanned(t *testing.T) {
+	m := newTestBans(BanPolicy{BadTx: 3, BadTime: 5 * time.Minute, BanTime: 15 * time.Minute})
+	const keyID = -101
+	if m.WouldBeBanned(keyID, map[BanReason]int{BanReasonBlock: 2}) || !m.WouldBeBanned(keyID, map[BanReason]int{BanReasonBlock: 3}) {
+		t.Fatal("wrong ban of the new key")
+	}
+	// the simulation doesn't change the ban list and matches the bans of the bad transactions
+	for i := 1; i <= 3; i++ {
+		expected := m.WouldBeBanned(keyID, map[BanReason]int{BanReasonQueue: 1})
+		if banned := !m.Add(keyID, BanReasonQueue).IsZero(); banned != expected {
+			t.Fatalf("bad tx %d: expected ban %v, got %v", i, expected, banned)
+		}
+	}
+	if !m.WouldBeBanned(keyID, nil) || !m.IsBanned(keyID) {
+		t.Error("banned key isn't reported")
+	}
+}
+
+func TestBanManager(t *testing.T) {
+	m := newTestBans(BanPolicy{BadTx: 5, BadTime: time.Minute, BanTime: time.Hour, MaxKeys: 2,
+		Reasons: map[BanReason]int{BanReasonIngress: 2}})
+	m.Add(1, BanReasonBlock)
+	if !m.Add(1, BanReasonIngress).IsZero() {
+		t.Fatal("key is banned before the threshold of the reason")
+	}
+	if m.Add(1, BanReasonIngress).IsZero() || !m.IsBanned(1) {
+		t.Fatal("key isn't banned by the threshold of the reason")
+	}
+	list := m.List()
+	if len(list) != 1 || list[0].KeyID != 1 || list[0].Reason != BanReasonIngress || list[0].Bad[BanReasonIngress] != 2 {
+		t.Fatalf("wrong list %+v", list)
+	}
+
+	// the keys without the ban are evicted over the limit
+	m.Add(2, BanReasonBlock)
+	m.Add(3, BanReasonBlock)
+	if stats := m.Stats(); stats.Tracked != 2 || stats.Banned != 1 || stats.BadTx[BanReasonIngress] != 2 || stats.Bans[BanReasonIngress] != 1 {
+		t.Errorf("wrong stats %+v", stats)
+	}
+	if !m.IsBanned(1) {
+		t.Error("banned key is evicted")
+	}
+
+	if !m.Lift(1) || m.IsBanned(1) || m.Lift(1) {
+		t.Error("ban isn't lifted")
+	}
+	if len(m.List()) != 0 || m.Stats().Lifts != 1 {
+		t.Error("lifted ban is listed")
+	}
+}
+
+func TestBanLift(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	priv, _, err := crypto.GenKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := keystore.NewKeySigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := NewBanLift(key, 100, time.Now().UnixMilli())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.KeyID != crypto.Address(key.PublicKey()) || data.BannedID != 100 {
+		t.Fatalf("wrong ban lift %+v", data)
+	}
+	l := &BanLiftParser{}
+	buf, err := l.BinMarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := DecodeTransaction(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.BanLift().BannedID != 100 || string(tx.Hash()) != string(l.TxHash) {
+		t.Errorf("wrong decoded ban lift %+v", tx.BanLift())
+	}
+	data.BannedID = 101
+	if err = (&BanLiftParser{Data: data}).Validate(); err != ErrBanLiftSign {
+		t.Errorf("expected %v, got %v", ErrBanLiftSign, err)
+	}
+}
+
+func TestBadTxForBanAudit(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	prevBans, prevAudit := bans, createAudit
+	defer func() { bans, createAudit = prevBans, prevAudit }()
+	bans = newTestBans(BanPolicy{BadTx: 2, BadTime: time.Minute, BanTime: time.Hour})
+	var audits []*sqldb.AuditLog
+	createAudit = func(a *sqldb.AuditLog, dbTx *sqldb.DbTransaction) error {
+		audits = append(audits, a)
+		return nil
+	}
+
+	const keyID = -102
+	BadTxForBan(keyID, BanReasonBlock)
+	if len(audits) != 0 {
+		t.Fatal("bad transaction is audited before the ban")
+	}
+	BadTxForBan(keyID, BanReasonBlock)
+	if len(audits) != 1 || audits[0].Action != sqldb.AuditKeyBan || audits[0].KeyID != keyID || len(audits[0].PayloadHash) == 0 {
+		t.Fatalf("wrong audit of the ban %+v", audits)
+	}
+}
@@ -5,6 +5,7 @@
 package transaction
 
 import (
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/smart"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
@@ -34,6 +35,26 @@ func NewTransactionInProc(smartTx types.SmartTransaction, privateKey []byte) (da
 	return newTransaction(smartTx, privateKey, false)
 }
 
+// NewAbstractAccountTransaction returns the transaction which is authorized by the auth contract of the key,
+// authData is attached instead of the signature
+func NewAbstractAccountTransaction(smartTx types.SmartTransaction, authData []byte) (data, hash []byte, err error) {
+	smartTx.AbstractAccount = true
+	stp := &SmartTransactionParser{
+		SmartContract: &smart.SmartContract{TxSmart: &smartTx},
+	}
+	if stp.Payload, err = smartTx.Marshal(); err != nil {
+		log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
+		return
+	}
+	stp.Hash = crypto.DoubleHash(stp.Payload)
+	stp.TxSignature = authData
+	if data, err = stp.Marshal(); err != nil {
+		log.WithFields(log.Fields{"type": consts.MarshallingError, "error": err}).Error("marshalling smart contract to msgpack")
+		return
+	}
+	return data, stp.Hash, nil
+}
+
 // CreateTransaction creates transaction
 func CreateTransaction(data, hash []byte, keyID, tnow int64) error {
 	tx := &sqldb.Transaction{
@@ -0,0 +1,34 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestNewAbstractAccountTransaction(t *testing.T) {
+	authData := []byte("auth data")
+	data, hash, err := NewAbstractAccountTransaction(types.SmartTransaction{
+		Header: &types.Header{ID: 5, EcosystemID: 1, KeyID: 100},
+	}, authData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != types.AbstractAccountTxType || !bytes.Equal(tx.Hash(), hash) {
+		t.Errorf("wrong transaction type %d or hash %x", tx.Type(), tx.Hash())
+	}
+	// the auth data is attached instead of the signature, it isn't a part of the hash
+	if sc := tx.SmartContract(); !bytes.Equal(sc.TxSignature, authData) || bytes.Contains(sc.Payload, authData) {
+		t.Errorf("wrong auth data %q", sc.TxSignature)
+	}
+}
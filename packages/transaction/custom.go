@@ -0,0 +1,94 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ErrCustomTxHandler is returned if the custom transaction is played without the handler
+var ErrCustomTxHandler = errors.New("custom transaction must be executed by the handler")
+
+// CustomTxParser is the parser of the custom transaction types. It only decodes the envelope,
+// the transaction is executed by the handler which is registered for its type
+type CustomTxParser struct {
+	Data    *types.CustomTransaction `msgpack:"-"`
+	TxHash  []byte                   `msgpack:"-"`
+	Payload []byte                   // msgpack of Data
+}
+
+func (c *CustomTxParser) txType() byte                { return c.Data.TxType() }
+func (c *CustomTxParser) txHash() []byte              { return c.TxHash }
+func (c *CustomTxParser) txPayload() []byte           { return c.Payload }
+func (c *CustomTxParser) txTime() int64               { return c.Data.Time }
+func (c *CustomTxParser) txKeyID() int64              { return c.Data.KeyID }
+func (c *CustomTxParser) txExpedite() decimal.Decimal { return decimal.Decimal{} }
+
+func (c *CustomTxParser) Init(in *InToCxt) error { return nil }
+func (c *CustomTxParser) Validate() error        { return nil }
+func (c *CustomTxParser) TxRollback() error      { return nil }
+
+func (c *CustomTxParser) Action(in *InToCxt, out *OutCtx) error {
+	return ErrCustomTxHandler
+}
+
+// BinMarshal returns the binary of the custom transaction
+func (c *CustomTxParser) BinMarshal(data *types.CustomTransaction) ([]byte, error) {
+	if !types.IsCustomTxType(int(data.Type)) {
+		return nil, fmt.Errorf("unsupported custom tx type %d", data.Type)
+	}
+	payload, err := msgpack.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	c.Data, c.Payload, c.TxHash = data, payload, crypto.DoubleHash(payload)
+	buf, err := msgpack.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{data.Type}, buf...), nil
+}
+
+// Unmarshal decodes the envelope, the type byte has been read from the buffer
+func (c *CustomTxParser) Unmarshal(buffer *bytes.Buffer) error {
+	buffer.UnreadByte()
+	return c.decode(buffer.Bytes())
+}
+
+func (c *CustomTxParser) decode(data []byte) error {
+	if err := msgpack.Unmarshal(data[1:], c); err != nil {
+		return err
+	}
+	if err := checkEncoding(c.Payload); err != nil {
+		return err
+	}
+	c.Data = new(types.CustomTransaction)
+	if err := msgpack.Unmarshal(c.Payload, c.Data); err != nil {
+		return err
+	}
+	if c.Data.Type != data[0] {
+		return fmt.Errorf("custom tx type %d doesn't match envelope type %d", data[0], c.Data.Type)
+	}
+	c.TxHash = crypto.DoubleHash(c.Payload)
+	return nil
+}
+
+// IsCustom returns true if the transaction is the custom type which is executed by the handler
+func (t *Transaction) IsCustom() bool {
+	_, ok := t.Inner.(*CustomTxParser)
+	return ok
+}
+
+// Custom returns the envelope of the custom transaction
+func (t *Transaction) Custom() *types.CustomTransaction {
+	return t.Inner.(*CustomTxParser).Data
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/network"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/utils"
 
@@ -83,6 +84,8 @@ func MarkTransactionBad(hash []byte, errText string) error {
 		errText = errText[:255] + "..."
 	}
 	log.WithFields(log.Fields{"type": consts.BadTxError, "tx_hash": hash, "error": errText}).Debug("tx marked as bad")
+	// the peers mustn't send the bad transaction again
+	network.KnownTxs.Add(hash)
 
 	return sqldb.NewDbTransaction(sqldb.DBConn).Connection().Transaction(func(tx *gorm.DB) error {
 		// looks like there is no hash in queue_tx at this moment
@@ -6,11 +6,13 @@
 package transaction
 
 import (
+	"context"
 	"math/rand"
 
 	"github.com/IBAX-io/go-ibax/packages/pbgo"
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/types"
+	log "github.com/sirupsen/logrus"
 )
 
 type DeliverProvider interface {
@@ -31,6 +33,10 @@ type InToCxt struct {
 	OutputsMap     map[sqldb.KeyUTXO][]sqldb.SpentInfo
 	PrevSysPar     map[string]string
 	EcoParams      []sqldb.EcoParam
+	Logger         *log.Entry // the logger of the block, nil outside of the block
+	TraceCalls     bool       // the nested calls of the contracts are recorded
+	// Ctx cancels the contract and the following statements of DbTransaction, nil doesn't cancel them
+	Ctx context.Context
 }
 
 type OutCtx struct {
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/IBAX-io/go-ibax/packages/genesis"
 	"github.com/IBAX-io/go-ibax/packages/migration"
 
 	"github.com/pkg/errors"
@@ -61,6 +62,11 @@ func (f *FirstBlockParser) Init(in *InToCxt) error {
 }
 
 func (f *FirstBlockParser) Validate() error {
+	if len(f.Data.Genesis) > 0 {
+		if _, err := genesis.Parse(f.Data.Genesis); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -150,6 +156,21 @@ func (f *FirstBlockParser) Action(in *InToCxt, out *OutCtx) (err error) {
 		logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("inserting default menu")
 		return err
 	}
+	if len(data.Genesis) > 0 {
+		spec, err := genesis.Parse(data.Genesis)
+		if err != nil {
+			logger.WithFields(log.Fields{"type": consts.ParserError, "error": err}).Error("parsing genesis spec")
+			return err
+		}
+		if err = spec.Apply(dbTx, f.TxHash); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("applying genesis spec")
+			return err
+		}
+		if err = syspar.SysUpdate(dbTx); err != nil {
+			logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+			return err
+		}
+	}
 	err = smart.LoadContract(dbTx, 1)
 	if err != nil {
 		return err
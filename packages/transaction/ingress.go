@@ -0,0 +1,254 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	// ErrTxEmpty is returned for the empty transaction
+	ErrTxEmpty = errors.New("empty transaction")
+	// ErrTxSize is returned when the transaction is larger than max_tx_size
+	ErrTxSize = errors.New("transaction size exceeds the limit")
+	// ErrTxDepth is returned when arrays and maps of the transaction are nested too deep
+	ErrTxDepth = errors.New("transaction nesting depth exceeds the limit")
+	// ErrTxMalformed is returned when the transaction is not a valid encoded data
+	ErrTxMalformed = errors.New("malformed transaction")
+	// ErrTxParams is returned when the transaction has too many contract parameters
+	ErrTxParams = errors.New("count of transaction parameters exceeds the limit")
+	// ErrTxParamSize is returned when the contract parameter is too large
+	ErrTxParamSize = errors.New("transaction parameter size exceeds the limit")
+)
+
+// IngressLimits are the limits which are checked before the transaction is decoded
+type IngressLimits struct {
+	MaxSize      int64
+	MaxDepth     int
+	MaxParams    int64
+	MaxParamSize int64
+}
+
+// GetIngressLimits returns the limits from the platform parameters
+func GetIngressLimits() IngressLimits {
+	return IngressLimits{
+		MaxSize:      syspar.GetMaxTxSize(),
+		MaxDepth:     consts.MaxTxDepth,
+		MaxParams:    syspar.GetMaxTxParams(),
+		MaxParamSize: syspar.GetMaxTxParamSize(),
+	}
+}
+
+// CheckIngress checks the transaction binary with the limits of the platform parameters
+func CheckIngress(data []byte) error {
+	return GetIngressLimits().Check(data)
+}
+
+// Check validates the size and the structure of the transaction binary without full decoding.
+// The returned errors wrap ErrTxEmpty, ErrTxSize, ErrTxDepth, ErrTxMalformed, ErrTxParams or ErrTxParamSize
+func (l IngressLimits) Check(data []byte) error {
+	if len(data) == 0 {
+		return ErrTxEmpty
+	}
+	if int64(len(data)) > l.MaxSize {
+		return fmt.Errorf("%w: %d > %d", ErrTxSize, len(data), l.MaxSize)
+	}
+	switch data[0] {
+	case types.SmartContractTxType, types.TransferSelfTxType, types.UtxoTxType, types.AbstractAccountTxType,
+		types.ConfidentialUTXOTxType:
+		if err := l.checkStructure(data[1:]); err != nil {
+			return err
+		}
+		var tx struct {
+			TxSmart *types.SmartTransaction
+		}
+		if err := msgpack.Unmarshal(data[1:], &tx); err != nil {
+			return fmt.Errorf("%w: %v", ErrTxMalformed, err)
+		}
+		if tx.TxSmart == nil {
+			return fmt.Errorf("%w: empty tx body", ErrTxMalformed)
+		}
+		return l.checkParams(tx.TxSmart.Params)
+	case byte(128):
+		var payload []byte
+		if err := converter.BinUnmarshalBuff(bytes.NewBuffer(data[1:]), &payload); err != nil {
+			return fmt.Errorf("%w: %v", ErrTxMalformed, err)
+		}
+		if err := l.checkStructure(payload); err != nil {
+			return err
+		}
+		tx := &types.SmartTransaction{}
+		if err := msgpack.Unmarshal(payload, tx); err != nil {
+			return fmt.Errorf("%w: %v", ErrTxMalformed, err)
+		}
+		return l.checkParams(tx.Params)
+	case types.FirstBlockTxType, types.StopNetworkTxType, types.KeyRotationTxType, types.BanLiftTxType:
+		return l.checkStructure(data[1:])
+	}
+	if types.IsCustomTxType(int(data[0])) {
+		if _, ok := syspar.GetRegisteredTxType(int(data[0])); !ok {
+			return fmt.Errorf("%w: unregistered tx type %d", ErrTxMalformed, data[0])
+		}
+		return l.checkStructure(data[1:])
+	}
+	return fmt.Errorf("%w: unsupported tx type %d", ErrTxMalformed, data[0])
+}
+
+func (l IngressLimits) checkParams(params map[string]any) error {
+	if int64(len(params)) > l.MaxParams {
+		return fmt.Errorf("%w: %d > %d", ErrTxParams, len(params), l.MaxParams)
+	}
+	for name, v := range params {
+		out, err := msgpack.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%w: parameter %s: %v", ErrTxMalformed, name, err)
+		}
+		if int64(len(out)) > l.MaxParamSize {
+			return fmt.Errorf("%w: parameter %s %d > %d", ErrTxParamSize, name, len(out), l.MaxParamSize)
+		}
+	}
+	return nil
+}
+
+// checkStructure walks msgpack encoded data and checks that the declared lengths fit into it
+// and the containers are not nested deeper than MaxDepth. Nothing is allocated so the check
+// is cheap for any input
+func (l IngressLimits) checkStructure(data []byte) error {
+	s := &msgpackScanner{data: data, maxDepth: l.MaxDepth}
+	return s.skip(0)
+}
+
+// checkEncoding checks the msgpack data before it is decoded, otherwise the declared lengths
+// which don't fit into the data make the decoder allocate them
+func checkEncoding(data []byte) error {
+	return IngressLimits{MaxDepth: consts.MaxTxDepth}.checkStructure(data)
+}
+
+type msgpackScanner struct {
+	data     []byte
+	pos      int
+	maxDepth int
+}
+
+func (s *msgpackScanner) malformed(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at %d", ErrTxMalformed, fmt.Sprintf(format, args...), s.pos)
+}
+
+// uint reads the big-endian unsigned integer of size bytes
+func (s *msgpackScanner) uint(size int) (uint64, error) {
+	if len(s.data)-s.pos < size {
+		return 0, s.malformed("unexpected end of data")
+	}
+	b := s.data[s.pos : s.pos+size]
+	s.pos += size
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (s *msgpackScanner) bytes(n uint64) error {
+	if uint64(len(s.data)-s.pos) < n {
+		return s.malformed("length %d exceeds data", n)
+	}
+	s.pos += int(n)
+	return nil
+}
+
+func (s *msgpackScanner) container(n uint64, perItem int, depth int) error {
+	if depth >= s.maxDepth {
+		return fmt.Errorf("%w: %d", ErrTxDepth, s.maxDepth)
+	}
+	// every item takes one byte at least
+	if uint64(len(s.data)-s.pos) < n*uint64(perItem) {
+		return s.malformed("count %d exceeds data", n)
+	}
+	for i := uint64(0); i < n*uint64(perItem); i++ {
+		if err := s.skip(depth + 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *msgpackScanner) skip(depth int) error {
+	if s.pos >= len(s.data) {
+		return s.malformed("unexpected end of data")
+	}
+	c := s.data[s.pos]
+	s.pos++
+	switch {
+	case c <= 0x7f || c >= 0xe0, c == 0xc0, c == 0xc2, c == 0xc3:
+		return nil
+	case c <= 0x8f:
+		return s.container(uint64(c&0x0f), 2, depth)
+	case c <= 0x9f:
+		return s.container(uint64(c&0x0f), 1, depth)
+	case c <= 0xbf:
+		return s.bytes(uint64(c & 0x1f))
+	}
+	switch c {
+	case 0xc4, 0xc5, 0xc6:
+		n, err := s.uint(1 << (c - 0xc4))
+		if err != nil {
+			return err
+		}
+		return s.bytes(n)
+	case 0xd9, 0xda, 0xdb:
+		n, err := s.uint(1 << (c - 0xd9))
+		if err != nil {
+			return err
+		}
+		return s.bytes(n)
+	case 0xc7, 0xc8, 0xc9:
+		n, err := s.uint(1 << (c - 0xc7))
+		if err != nil {
+			return err
+		}
+		return s.bytes(n + 1)
+	case 0xca, 0xd2:
+		return s.bytes(4)
+	case 0xcb, 0xd3:
+		return s.bytes(8)
+	case 0xcc, 0xd0:
+		return s.bytes(1)
+	case 0xcd, 0xd1:
+		return s.bytes(2)
+	case 0xce:
+		return s.bytes(4)
+	case 0xcf:
+		return s.bytes(8)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return s.bytes(1 + 1<<(c-0xd4))
+	case 0xdc, 0xde:
+		n, err := s.uint(2)
+		if err != nil {
+			return err
+		}
+		return s.container(n, 1+int(c-0xdc)/2, depth)
+	case 0xdd, 0xdf:
+		n, err := s.uint(4)
+		if err != nil {
+			return err
+		}
+		return s.container(n, 1+int(c-0xdd)/2, depth)
+	}
+	return s.malformed("unknown code 0x%x", c)
+}
@@ -0,0 +1,138 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var testLimits = IngressLimits{
+	MaxSize:      4096,
+	MaxDepth:     8,
+	MaxParams:    4,
+	MaxParamSize: 64,
+}
+
+func smartTxData(t testing.TB, params map[string]any) []byte {
+	out, err := msgpack.Marshal(struct {
+		TxSmart *types.SmartTransaction
+	}{&types.SmartTransaction{
+		Header: &types.Header{ID: 1, EcosystemID: 1, KeyID: 1},
+		Params: params,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append([]byte{types.SmartContractTxType}, out...)
+}
+
+// nested returns the param value with depth levels of arrays
+func nested(depth int) any {
+	var v any = int64(1)
+	for i := 0; i < depth; i++ {
+		v = []any{v}
+	}
+	return v
+}
+
+func TestIngressSize(t *testing.T) {
+	data := smartTxData(t, map[string]any{"Value": "1"})
+	l := testLimits
+	l.MaxSize = int64(len(data))
+	if err := l.Check(data); err != nil {
+		t.Errorf("size at the limit: %v", err)
+	}
+	l.MaxSize--
+	if err := l.Check(data); !errors.Is(err, ErrTxSize) {
+		t.Errorf("size over the limit: got %v", err)
+	}
+	if err := l.Check(nil); !errors.Is(err, ErrTxEmpty) {
+		t.Errorf("empty: got %v", err)
+	}
+}
+
+func TestIngressDepth(t *testing.T) {
+	// TxSmart, SmartTransaction and Params maps take three levels
+	const outer = 3
+	data := smartTxData(t, map[string]any{"Value": nested(testLimits.MaxDepth - outer)})
+	if err := testLimits.Check(data); err != nil {
+		t.Errorf("depth at the limit: %v", err)
+	}
+	data = smartTxData(t, map[string]any{"Value": nested(testLimits.MaxDepth - outer + 1)})
+	if err := testLimits.Check(data); !errors.Is(err, ErrTxDepth) {
+		t.Errorf("depth over the limit: got %v", err)
+	}
+	// the nesting is rejected before the data is decoded
+	deep := append([]byte{types.SmartContractTxType}, bytes.Repeat([]byte{0x91}, 1000)...)
+	if err := testLimits.Check(append(deep, 0x01)); !errors.Is(err, ErrTxDepth) {
+		t.Errorf("deep envelope: got %v", err)
+	}
+}
+
+func TestIngressParams(t *testing.T) {
+	params := map[string]any{"A": 1, "B": 2, "C": 3, "D": 4}
+	if err := testLimits.Check(smartTxData(t, params)); err != nil {
+		t.Errorf("params at the limit: %v", err)
+	}
+	params["E"] = 5
+	if err := testLimits.Check(smartTxData(t, params)); !errors.Is(err, ErrTxParams) {
+		t.Errorf("params over the limit: got %v", err)
+	}
+
+	// a string of 62 bytes is encoded with 2 bytes of the header
+	value := strings.Repeat("a", int(testLimits.MaxParamSize)-2)
+	if err := testLimits.Check(smartTxData(t, map[string]any{"Value": value})); err != nil {
+		t.Errorf("param size at the limit: %v", err)
+	}
+	value += "a"
+	if err := testLimits.Check(smartTxData(t, map[string]any{"Value": value})); !errors.Is(err, ErrTxParamSize) {
+		t.Errorf("param size over the limit: got %v", err)
+	}
+}
+
+func TestIngressMalformed(t *testing.T) {
+	data := smartTxData(t, map[string]any{"Value": "1"})
+	cases := map[string][]byte{
+		"truncated":   data[:len(data)-1],
+		"unknown tx":  {0x7f, 0x80},
+		"unknown":     {types.SmartContractTxType, 0xc1},
+		"huge string": {types.SmartContractTxType, 0xdb, 0xff, 0xff, 0xff, 0xff, 'a'},
+		"huge array":  {types.SmartContractTxType, 0xdd, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"huge map":    {types.SmartContractTxType, 0xdf, 0x00, 0x01, 0x00, 0x00, 0x01, 0x01},
+		"no body":     {types.SmartContractTxType, 0x80},
+		"payload":     {128, 0x05, 0x01},
+	}
+	for name, data := range cases {
+		if err := testLimits.Check(data); !errors.Is(err, ErrTxMalformed) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func FuzzIngressCheck(f *testing.F) {
+	f.Add(smartTxData(f, map[string]any{"Value": "1", "List": []any{1, "a", nested(3)}}))
+	f.Add([]byte{types.SmartContractTxType, 0xde, 0xff, 0xff})
+	f.Add([]byte{128, 0x02, 0x91, 0x01})
+	f.Add([]byte{types.StopNetworkTxType, 0xc7, 0x01, 0x05, 0x00})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := testLimits.Check(data)
+		if err == nil {
+			return
+		}
+		for _, e := range []error{ErrTxEmpty, ErrTxSize, ErrTxDepth, ErrTxMalformed, ErrTxParams, ErrTxParamSize} {
+			if errors.Is(err, e) {
+				return
+			}
+		}
+		t.Errorf("untyped error: %v", err)
+	})
+}
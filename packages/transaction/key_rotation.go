@@ -0,0 +1,181 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ErrKeyRotationSign is returned if the announcement of the key rotation isn't signed by both keys
+var ErrKeyRotationSign = errors.New("key rotation must be signed by the old and the new keys")
+
+// KeyRotationParser replaces the public key of the honor node in honor_nodes. The old key signs
+// the blocks of the node for key_rotation_blocks after the block of the transaction
+type KeyRotationParser struct {
+	Logger  *log.Entry         `msgpack:"-"`
+	Data    *types.KeyRotation `msgpack:"-"`
+	TxHash  []byte             `msgpack:"-"`
+	Payload []byte             // msgpack of Data
+}
+
+func (k *KeyRotationParser) txType() byte                { return k.Data.TxType() }
+func (k *KeyRotationParser) txHash() []byte              { return k.TxHash }
+func (k *KeyRotationParser) txPayload() []byte           { return k.Payload }
+func (k *KeyRotationParser) txTime() int64               { return k.Data.Time }
+func (k *KeyRotationParser) txKeyID() int64              { return k.Data.KeyID }
+func (k *KeyRotationParser) txExpedite() decimal.Decimal { return decimal.Decimal{} }
+
+func (k *KeyRotationParser) Init(in *InToCxt) error {
+	k.Logger = log.WithFields(log.Fields{"tx_hash": fmt.Sprintf("%x", k.TxHash), "tx_type": k.txType()})
+	if in.Logger != nil {
+		k.Logger = in.Logger.WithFields(log.Fields{"tx_hash": fmt.Sprintf("%x", k.TxHash), "tx_type": k.txType()})
+	}
+	return nil
+}
+
+func (k *KeyRotationParser) TxRollback() error { return nil }
+
+// Validate checks the signatures of the announcement and that the old key is the key of the honor node
+func (k *KeyRotationParser) Validate() error {
+	data := k.Data
+	if len(data.OldPublicKey) != consts.PubkeySizeLength || len(data.NewPublicKey) != consts.PubkeySizeLength {
+		return fmt.Errorf("wrong length of public key")
+	}
+	if data.KeyID != crypto.Address(data.OldPublicKey) {
+		return fmt.Errorf("key id %d doesn't match old public key", data.KeyID)
+	}
+	if bytes.Equal(data.OldPublicKey, data.NewPublicKey) {
+		return fmt.Errorf("new public key is the old one")
+	}
+	announcement := data.Announcement()
+	for _, item := range []struct{ pub, sign []byte }{{data.OldPublicKey, data.OldSign}, {data.NewPublicKey, data.NewSign}} {
+		if ok, err := crypto.Verify(item.pub, announcement, item.sign); !ok || err != nil {
+			return ErrKeyRotationSign
+		}
+	}
+	if !syspar.IsHonorNodeKey(data.OldPublicKey) {
+		return syspar.ErrHonorNodeKey
+	}
+	if syspar.IsHonorNodeKey(data.NewPublicKey) {
+		return fmt.Errorf("%w: new key is in use", syspar.ErrHonorNodeKey)
+	}
+	return nil
+}
+
+func (k *KeyRotationParser) Action(in *InToCxt, out *OutCtx) error {
+	if err := k.Validate(); err != nil {
+		k.Logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("validating key rotation")
+		return err
+	}
+	dbTx := in.DbTransaction
+	param := &sqldb.PlatformParameter{}
+	found, err := param.GetTransaction(dbTx, syspar.HonorNodes)
+	if err != nil {
+		k.Logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("getting honor nodes")
+		return err
+	}
+	if !found {
+		return syspar.ErrHonorNodeKey
+	}
+	until := in.BlockHeader.BlockId + syspar.GetKeyRotationBlocks()
+	value, err := syspar.RotateHonorNodeKey(param.Value, k.Data.OldPublicKey, k.Data.NewPublicKey, until)
+	if err != nil {
+		k.Logger.WithFields(log.Fields{"type": consts.InvalidObject, "error": err}).Error("rotating honor node key")
+		return err
+	}
+	rollbackData, err := json.Marshal(map[string]string{"value": param.Value})
+	if err != nil {
+		return err
+	}
+	tableID := strconv.FormatInt(param.ID, 10)
+	err = dbTx.Update(param.TableName(), `"value"='`+strings.ReplaceAll(value, `'`, `''`)+`'`, ` WHERE "id"='`+tableID+`'`)
+	if err != nil {
+		k.Logger.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating honor nodes")
+		return err
+	}
+	k.Logger.WithFields(log.Fields{"old_key": crypto.PubToHex(k.Data.OldPublicKey), "new_key": crypto.PubToHex(k.Data.NewPublicKey),
+		"until": until}).Info("honor node key is rotated")
+	out.Apply(
+		WithOutCtxSysUpdate(true),
+		WithOutCtxRollBackTx([]*types.RollbackTx{{
+			BlockId:   in.BlockHeader.BlockId,
+			TxHash:    k.TxHash,
+			NameTable: param.TableName(),
+			TableId:   tableID,
+			Data:      string(rollbackData),
+			DataHash:  crypto.Hash(rollbackData),
+		}}),
+	)
+	return nil
+}
+
+// NewKeyRotation returns the announcement of the rotation of the node key from the signer oldKey
+// to newKey, it's signed by both keys
+func NewKeyRotation(oldKey, newKey keystore.Signer, timestamp int64) (*types.KeyRotation, error) {
+	data := &types.KeyRotation{
+		KeyID:        crypto.Address(oldKey.PublicKey()),
+		Time:         timestamp,
+		OldPublicKey: oldKey.PublicKey(),
+		NewPublicKey: newKey.PublicKey(),
+	}
+	var err error
+	if data.OldSign, err = keystore.SignData(oldKey, data.Announcement()); err != nil {
+		return nil, fmt.Errorf("signing by old key: %w", err)
+	}
+	if data.NewSign, err = keystore.SignData(newKey, data.Announcement()); err != nil {
+		return nil, fmt.Errorf("signing by new key: %w", err)
+	}
+	return data, nil
+}
+
+// BinMarshal returns the binary of the key rotation transaction
+func (k *KeyRotationParser) BinMarshal(data *types.KeyRotation) ([]byte, error) {
+	payload, err := msgpack.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	k.Data, k.Payload, k.TxHash = data, payload, crypto.DoubleHash(payload)
+	buf, err := msgpack.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{data.TxType()}, buf...), nil
+}
+
+// Unmarshal decodes the transaction, the type byte has been read from the buffer
+func (k *KeyRotationParser) Unmarshal(buffer *bytes.Buffer) error {
+	buffer.UnreadByte()
+	return k.decode(buffer.Bytes())
+}
+
+func (k *KeyRotationParser) decode(data []byte) error {
+	if err := msgpack.Unmarshal(data[1:], k); err != nil {
+		return err
+	}
+	if err := checkEncoding(k.Payload); err != nil {
+		return err
+	}
+	k.Data = new(types.KeyRotation)
+	if err := msgpack.Unmarshal(k.Payload, k.Data); err != nil {
+		return err
+	}
+	k.TxHash = crypto.DoubleHash(k.Payload)
+	return nil
+}
@@ -0,0 +1,60 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func TestKeyRotationTransaction(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	newSigner := func() keystore.Signer {
+		priv, _, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := keystore.NewKeySigner(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signer
+	}
+	oldKey, newKey := newSigner(), newSigner()
+	rotation, err := NewKeyRotation(oldKey, newKey, time.Now().UnixMilli())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := new(KeyRotationParser).BinMarshal(rotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = testLimits.Check(data); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := DecodeTransaction(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != types.KeyRotationTxType || tx.KeyID() != crypto.Address(oldKey.PublicKey()) {
+		t.Fatalf("wrong transaction type %d or key %d", tx.Type(), tx.KeyID())
+	}
+	krp := tx.Inner.(*KeyRotationParser)
+	// the signatures are valid, the old key isn't the key of the honor node here
+	if err = krp.Validate(); !errors.Is(err, syspar.ErrHonorNodeKey) {
+		t.Errorf("key of not honor node: got %v", err)
+	}
+	krp.Data.NewPublicKey = newSigner().PublicKey()
+	if err = krp.Validate(); !errors.Is(err, ErrKeyRotationSign) {
+		t.Errorf("replaced new key: got %v", err)
+	}
+}
@@ -41,12 +41,16 @@ func GetLetParsing() LimitMode {
 type Limits struct {
 	Mode     LimitMode
 	Limiters []Limiter // the list of limiters
+	origin   []Limiter // the state of the limiters when the limits have been cloned
 }
 
 // Limiter describes interface functions for limits
 type Limiter interface {
 	init()
 	check(TransactionCaller, LimitMode) error
+	clone() Limiter
+	// merge adds the usage of used limiter since its origin state
+	merge(used, origin Limiter)
 }
 
 type limiterModes struct {
@@ -61,19 +65,20 @@ var (
 	ErrLimitStop = errors.New(`stop generating block`)
 )
 
-// NewLimits initializes Limits structure.
-func NewLimits(b LimitMode) (limits *Limits) {
+// NewLimits initializes Limits structure for the block with blockID.
+func NewLimits(b LimitMode, blockID int64) (limits *Limits) {
 	limits = &Limits{Limiters: make([]Limiter, 0, 8)}
 
 	limits.Mode = b
 
 	allLimiters := []limiterModes{
-		{limiter: &txMaxSize{}, modes: letPreprocess | letParsing},
+		{limiter: &txMaxSize{BlockID: blockID}, modes: letPreprocess | letParsing},
 		{limiter: &txUserLimit{}, modes: letPreprocess | letParsing},
-		{limiter: &txMaxLimit{}, modes: letPreprocess | letParsing},
+		{limiter: &txMaxWeight{BlockID: blockID}, modes: letPreprocess | letGenBlock | letParsing},
 		{limiter: &txUserEcosysLimit{}, modes: letPreprocess | letParsing},
 		{limiter: &timeBlockLimit{}, modes: letGenBlock},
 		{limiter: &txMaxFuel{}, modes: letGenBlock | letParsing},
+		{limiter: &txMaxRollbacks{BlockID: blockID}, modes: letGenBlock | letParsing},
 	}
 	for _, limiter := range allLimiters {
 		if limiter.modes&limits.Mode == 0 {
@@ -95,33 +100,73 @@ func (limits *Limits) CheckLimit(t TransactionCaller) error {
 	return nil
 }
 
+// Clone returns the deep copy of the limits, the copy is checked by the goroutine
+// and its usage is added back by Merge
+func (limits *Limits) Clone() *Limits {
+	c := &Limits{
+		Mode:     limits.Mode,
+		Limiters: make([]Limiter, len(limits.Limiters)),
+		origin:   make([]Limiter, len(limits.Limiters)),
+	}
+	for i, limiter := range limits.Limiters {
+		c.Limiters[i] = limiter.clone()
+		c.origin[i] = limiter.clone()
+	}
+	return c
+}
+
+// Merge adds the usage of the limits returned by Clone since they have been cloned
+func (limits *Limits) Merge(other *Limits) {
+	for i, limiter := range limits.Limiters {
+		limiter.merge(other.Limiters[i], other.origin[i])
+	}
+}
+
+// Reset returns the limiters to their state in the snapshot returned by Clone, the origin of the limits
+// isn't changed
+func (limits *Limits) Reset(snapshot *Limits) {
+	for i, limiter := range snapshot.Limiters {
+		limits.Limiters[i] = limiter.clone()
+	}
+}
+
 func limitError(limitName, msg string, args ...any) error {
 	err := fmt.Errorf(msg, args...)
 	log.WithFields(log.Fields{"type": consts.BlockError, "error": err}).Error(limitName)
 	return script.SetVMError(`panic`, err)
 }
 
-// Checking the max tx in the block
-type txMaxLimit struct {
-	Count int // the current count
-	Limit int // max count of tx in the block
+// Checking the max weight of tx in the block
+type txMaxWeight struct {
+	BlockID int64 // the block which max weight is effective
+	Weight  int64 // the current weight
+	Limit   int64 // max weight of tx in the block
 }
 
-func (bl *txMaxLimit) init() {
-	bl.Limit = syspar.GetMaxTxCount()
+func (bl *txMaxWeight) init() {
+	bl.Limit = syspar.GetMaxBlockWeightAt(bl.BlockID)
 }
 
-func (bl *txMaxLimit) check(t TransactionCaller, mode LimitMode) error {
-	bl.Count++
-	if bl.Count+1 > bl.Limit && mode == letPreprocess {
-		return errors.WithMessage(ErrLimitStop, "txMaxLimit")
-	}
-	if bl.Count > bl.Limit {
-		return limitError(`txMaxLimit`, `Max tx in the block`)
+func (bl *txMaxWeight) check(t TransactionCaller, mode LimitMode) error {
+	bl.Weight += weight(t)
+	if bl.Weight > bl.Limit {
+		if mode != letParsing {
+			return errors.WithMessage(ErrLimitStop, "txMaxWeight")
+		}
+		return limitError(`txMaxWeight`, `Max weight of the block`)
 	}
 	return nil
 }
 
+func (bl *txMaxWeight) clone() Limiter {
+	c := *bl
+	return &c
+}
+
+func (bl *txMaxWeight) merge(used, origin Limiter) {
+	bl.Weight += used.(*txMaxWeight).Weight - origin.(*txMaxWeight).Weight
+}
+
 // Checking the time of the start of generating block
 type timeBlockLimit struct {
 	Start time.Time     // the time of the start of generating block
@@ -145,6 +190,13 @@ func (bl *timeBlockLimit) check(t TransactionCaller, mode LimitMode) error {
 	return limitError("txBlockTimeLimit", "Block generation time exceeded")
 }
 
+func (bl *timeBlockLimit) clone() Limiter {
+	c := *bl
+	return &c
+}
+
+func (bl *timeBlockLimit) merge(used, origin Limiter) {}
+
 // Checking the max tx from one user in the block
 type txUserLimit struct {
 	TxUsers map[int64]int // the counter of tx from one user
@@ -174,6 +226,23 @@ func (bl *txUserLimit) check(t TransactionCaller, mode LimitMode) error {
 	return nil
 }
 
+func (bl *txUserLimit) clone() Limiter {
+	c := &txUserLimit{TxUsers: make(map[int64]int, len(bl.TxUsers)), Limit: bl.Limit}
+	for keyID, count := range bl.TxUsers {
+		c.TxUsers[keyID] = count
+	}
+	return c
+}
+
+func (bl *txUserLimit) merge(used, origin Limiter) {
+	prev := origin.(*txUserLimit).TxUsers
+	for keyID, count := range used.(*txUserLimit).TxUsers {
+		if delta := count - prev[keyID]; delta != 0 {
+			bl.TxUsers[keyID] += delta
+		}
+	}
+}
+
 // Checking the max tx from one user in the ecosystem contracts
 type ecosysLimit struct {
 	TxUsers map[int64]int // the counter of tx from one user in the ecosystem
@@ -216,15 +285,44 @@ func (bl *txUserEcosysLimit) check(t TransactionCaller, mode LimitMode) error {
 	return nil
 }
 
+func (bl *txUserEcosysLimit) clone() Limiter {
+	c := &txUserEcosysLimit{TxEcosys: make(map[int64]ecosysLimit, len(bl.TxEcosys))}
+	for ecosystemID, val := range bl.TxEcosys {
+		users := make(map[int64]int, len(val.TxUsers))
+		for keyID, count := range val.TxUsers {
+			users[keyID] = count
+		}
+		c.TxEcosys[ecosystemID] = ecosysLimit{TxUsers: users, Limit: val.Limit}
+	}
+	return c
+}
+
+func (bl *txUserEcosysLimit) merge(used, origin Limiter) {
+	prev := origin.(*txUserEcosysLimit).TxEcosys
+	for ecosystemID, val := range used.(*txUserEcosysLimit).TxEcosys {
+		dst, ok := bl.TxEcosys[ecosystemID]
+		if !ok {
+			dst = ecosysLimit{TxUsers: make(map[int64]int), Limit: val.Limit}
+			bl.TxEcosys[ecosystemID] = dst
+		}
+		for keyID, count := range val.TxUsers {
+			if delta := count - prev[ecosystemID].TxUsers[keyID]; delta != 0 {
+				dst.TxUsers[keyID] += delta
+			}
+		}
+	}
+}
+
 // Checking the max tx & block size
 type txMaxSize struct {
+	BlockID    int64 // the block which max size is effective
 	Size       int64 // the current size of the block
 	LimitBlock int64 // max size of the block
 	LimitTx    int64 // max size of tx
 }
 
 func (bl *txMaxSize) init() {
-	bl.LimitBlock = syspar.GetMaxBlockSize()
+	bl.LimitBlock = syspar.GetMaxBlockSizeAt(bl.BlockID)
 	bl.LimitTx = syspar.GetMaxTxSize()
 }
 
@@ -243,6 +341,15 @@ func (bl *txMaxSize) check(t TransactionCaller, mode LimitMode) error {
 	return nil
 }
 
+func (bl *txMaxSize) clone() Limiter {
+	c := *bl
+	return &c
+}
+
+func (bl *txMaxSize) merge(used, origin Limiter) {
+	bl.Size += used.(*txMaxSize).Size - origin.(*txMaxSize).Size
+}
+
 // Checking the max tx & block size
 type txMaxFuel struct {
 	Fuel       int64 // the current fuel of the block
@@ -273,3 +380,46 @@ func (bl *txMaxFuel) check(t TransactionCaller, mode LimitMode) error {
 	}
 	return nil
 }
+
+func (bl *txMaxFuel) clone() Limiter {
+	c := *bl
+	return &c
+}
+
+func (bl *txMaxFuel) merge(used, origin Limiter) {
+	bl.Fuel += used.(*txMaxFuel).Fuel - origin.(*txMaxFuel).Fuel
+}
+
+// Checking the max rollback entries of tx, the contract fails on the entry over the limit and
+// the limiter checks the played tx
+type txMaxRollbacks struct {
+	BlockID   int64 // the block which max rollback entries are effective
+	Rollbacks int64 // the rollback entries of the block
+	LimitTx   int64 // max rollback entries of tx
+}
+
+func (bl *txMaxRollbacks) init() {
+	bl.LimitTx = syspar.GetMaxTxRollbacksAt(bl.BlockID)
+}
+
+func (bl *txMaxRollbacks) check(t TransactionCaller, mode LimitMode) error {
+	smart, ok := t.(*SmartTransactionParser)
+	if !ok {
+		return nil
+	}
+	count := int64(len(smart.RollBackTx))
+	if count > bl.LimitTx {
+		return limitError(`txMaxRollbacks`, `Max rollback entries of tx %d > %d`, count, bl.LimitTx)
+	}
+	bl.Rollbacks += count
+	return nil
+}
+
+func (bl *txMaxRollbacks) clone() Limiter {
+	c := *bl
+	return &c
+}
+
+func (bl *txMaxRollbacks) merge(used, origin Limiter) {
+	bl.Rollbacks += used.(*txMaxRollbacks).Rollbacks - origin.(*txMaxRollbacks).Rollbacks
+}
@@ -0,0 +1,117 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func newLimitsTx(keyID, fuel int64) *SmartTransactionParser {
+	tx := newWeightTx(&types.SmartTransaction{Header: &types.Header{KeyID: keyID, EcosystemID: 1}}, nil)
+	tx.TxFuel = fuel
+	return tx
+}
+
+// TestLimitsClone checks the clones of the limits concurrently, run it with -race
+func TestLimitsClone(t *testing.T) {
+	const groups, txs = 8, 10
+	limits := &Limits{Mode: letParsing, Limiters: []Limiter{
+		&txMaxSize{LimitBlock: 1 << 20, LimitTx: 1 << 10},
+		&txUserLimit{TxUsers: make(map[int64]int), Limit: 100},
+		&txMaxWeight{Limit: 1000},
+		&txUserEcosysLimit{TxEcosys: map[int64]ecosysLimit{1: {TxUsers: make(map[int64]int), Limit: 100}}},
+		&txMaxFuel{LimitBlock: 10000, LimitTx: 100},
+	}}
+	if err := limits.CheckLimit(newLimitsTx(1, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	clones := make([]*Limits, groups)
+	for i := range clones {
+		clones[i] = limits.Clone()
+		wg.Add(1)
+		go func(c *Limits, keyID int64) {
+			defer wg.Done()
+			for j := 0; j < txs; j++ {
+				if err := c.CheckLimit(newLimitsTx(keyID, 10)); err != nil {
+					t.Error(err)
+				}
+			}
+		}(clones[i], int64(i%2+1))
+	}
+	wg.Wait()
+	for _, c := range clones {
+		limits.Merge(c)
+	}
+
+	if fuel := limits.Limiters[4].(*txMaxFuel).Fuel; fuel != (groups*txs+1)*10 {
+		t.Errorf("expected fuel %d, got %d", (groups*txs+1)*10, fuel)
+	}
+	if w := limits.Limiters[2].(*txMaxWeight).Weight; w != groups*txs+1 {
+		t.Errorf("expected weight %d, got %d", groups*txs+1, w)
+	}
+	users := limits.Limiters[1].(*txUserLimit).TxUsers
+	if users[1] != groups/2*txs+1 || users[2] != groups/2*txs {
+		t.Errorf("wrong counters of the users %v", users)
+	}
+	if eco := limits.Limiters[3].(*txUserEcosysLimit).TxEcosys[1].TxUsers; eco[1] != users[1] || eco[2] != users[2] {
+		t.Errorf("wrong counters of the users in the ecosystem %v", eco)
+	}
+	// the clone doesn't share the state of the limits
+	if clones[0].Limiters[1].(*txUserLimit).TxUsers[1] > txs+1 {
+		t.Error("clone is changed by merge")
+	}
+}
+
+// TestTxUserLimitSkip checks that the transactions of the key over the limit are skipped by the
+// generator and rejected in the received block
+func TestTxUserLimitSkip(t *testing.T) {
+	const limit = 3
+	generator := &Limits{Mode: letPreprocess, Limiters: []Limiter{&txUserLimit{TxUsers: make(map[int64]int), Limit: limit}}}
+	for i := 0; i < limit; i++ {
+		if err := generator.CheckLimit(newLimitsTx(1, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := generator.CheckLimit(newLimitsTx(1, 10)); err != ErrLimitSkip {
+		t.Errorf("expected %v, got %v", ErrLimitSkip, err)
+	}
+	// the other keys aren't starved
+	if err := generator.CheckLimit(newLimitsTx(2, 10)); err != nil {
+		t.Error(err)
+	}
+
+	parser := &Limits{Mode: letParsing, Limiters: []Limiter{&txUserLimit{TxUsers: map[int64]int{1: limit + 1}, Limit: limit}}}
+	if err := parser.CheckLimit(newLimitsTx(1, 10)); err == nil || err == ErrLimitSkip {
+		t.Errorf("expected the limit error, got %v", err)
+	}
+}
+
+// TestTxMaxRollbacks checks the rollback entries of the transaction just under and just over the limit
+// in the generated and the received blocks
+func TestTxMaxRollbacks(t *testing.T) {
+	const limit = 5
+	for _, mode := range []LimitMode{letGenBlock, letParsing} {
+		limits := &Limits{Mode: mode, Limiters: []Limiter{&txMaxRollbacks{LimitTx: limit}}}
+		tx := newLimitsTx(1, 10)
+		tx.RollBackTx = make([]*types.RollbackTx, limit)
+		if err := limits.CheckLimit(tx); err != nil {
+			t.Fatalf("%d: %v", mode, err)
+		}
+		tx.RollBackTx = append(tx.RollBackTx, &types.RollbackTx{})
+		if err := limits.CheckLimit(tx); err == nil || !strings.Contains(err.Error(), "Max rollback entries") {
+			t.Errorf("%d: expected the rollback limit, got %v", mode, err)
+		}
+		if rollbacks := limits.Limiters[0].(*txMaxRollbacks).Rollbacks; rollbacks != limit {
+			t.Errorf("%d: expected %d rollback entries of the block, got %d", mode, limit, rollbacks)
+		}
+	}
+}
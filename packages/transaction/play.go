@@ -9,11 +9,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// GetLogger returns logger
+// GetLogger returns logger, it's based on the logger of the block when the transaction is played
 func (t *Transaction) GetLogger() *log.Entry {
-	var logger *log.Entry
+	logger := log.NewEntry(log.StandardLogger())
 	if t.Inner != nil {
-		logger = log.WithFields(log.Fields{"tx_type": t.Type(), "tx_time": t.Timestamp(), "tx_wallet_id": t.KeyID()})
+		logger = logger.WithFields(log.Fields{"tx_type": t.Type(), "tx_time": t.Timestamp(), "tx_wallet_id": t.KeyID()})
+	}
+	if t.InToCxt == nil {
+		return logger
+	}
+	if t.Logger != nil {
+		return t.Logger.WithFields(logger.Data)
 	}
 	if t.BlockHeader != nil {
 		logger = logger.WithFields(log.Fields{"block_id": t.BlockHeader.BlockId, "block_time": t.BlockHeader.Timestamp, "block_wallet_id": t.BlockHeader.KeyId, "block_state_id": t.BlockHeader.EcosystemId, "block_hash": t.BlockHeader.BlockHash, "block_version": t.BlockHeader.Version})
@@ -25,7 +25,7 @@ func ProcessQueueTransactionBatches(dbTx *sqldb.DbTransaction, qs []*sqldb.Queue
 
 		go func() {
 			for badTxItem := range ch {
-				BadTxForBan(badTxItem.keyID)
+				BadTxForBan(badTxItem.keyID, BanReasonQueue)
 				_ = MarkTransactionBad(badTxItem.hash, badTxItem.msg)
 			}
 		}()
@@ -31,10 +31,16 @@ func (rtx *Transaction) Unmarshall(buffer *bytes.Buffer, fill bool) error {
 	if err != nil {
 		return err
 	}
+	if txT != byte(128) {
+		if err = checkEncoding(buffer.Bytes()); err != nil {
+			return err
+		}
+	}
 
 	var inner TransactionCaller
 	switch txT {
-	case types.SmartContractTxType, types.TransferSelfTxType, types.UtxoTxType:
+	case types.SmartContractTxType, types.TransferSelfTxType, types.UtxoTxType, types.AbstractAccountTxType,
+		types.ConfidentialUTXOTxType:
 		itx := &SmartTransactionParser{
 			SmartContract: &smart.SmartContract{TxSmart: new(types.SmartTransaction)},
 		}
@@ -50,11 +56,17 @@ func (rtx *Transaction) Unmarshall(buffer *bytes.Buffer, fill bool) error {
 		if err := converter.BinUnmarshalBuff(buffer, &itx.Payload); err != nil {
 			return err
 		}
+		if err := checkEncoding(itx.Payload); err != nil {
+			return err
+		}
 		itx.Hash = crypto.DoubleHash(itx.Payload)
 		itx.TxSignature = buffer.Bytes()
 		if err := msgpack.Unmarshal(itx.Payload, &itx.TxSmart); err != nil {
 			return err
 		}
+		if itx.TxSmart == nil {
+			return fmt.Errorf("empty transaction body")
+		}
 
 		var newbuf []byte
 		newbuf, err = itx.Marshal()
@@ -78,11 +90,33 @@ func (rtx *Transaction) Unmarshall(buffer *bytes.Buffer, fill bool) error {
 		inner = &itx
 
 		if err := itx.Unmarshal(buffer); err != nil {
-			log.WithFields(log.Fields{"error": err, "type": consts.UnmarshallingError, "tx_type": rtx.Type()}).Error("getting parser for tx type")
+			log.WithFields(log.Fields{"error": err, "type": consts.UnmarshallingError, "tx_type": itx.txType()}).Error("getting parser for tx type")
+			return err
+		}
+	case types.KeyRotationTxType:
+		var itx = KeyRotationParser{}
+		inner = &itx
+		if err := itx.Unmarshal(buffer); err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.UnmarshallingError, "tx_type": txT}).Error("getting parser for tx type")
+			return err
+		}
+	case types.BanLiftTxType:
+		var itx = BanLiftParser{}
+		inner = &itx
+		if err := itx.Unmarshal(buffer); err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.UnmarshallingError, "tx_type": txT}).Error("getting parser for tx type")
 			return err
 		}
 	default:
-		return fmt.Errorf("unsupported tx type %d", txT)
+		if !types.IsCustomTxType(int(txT)) {
+			return fmt.Errorf("unsupported tx type %d", txT)
+		}
+		var itx = CustomTxParser{}
+		inner = &itx
+		if err := itx.Unmarshal(buffer); err != nil {
+			log.WithFields(log.Fields{"error": err, "type": consts.UnmarshallingError, "tx_type": txT}).Error("getting parser for tx type")
+			return err
+		}
 	}
 	rtx.Inner = inner
 	if cache, ok := txCache.Get(fmt.Sprintf("%x", rtx.Hash())); ok {
@@ -93,6 +127,61 @@ func (rtx *Transaction) Unmarshall(buffer *bytes.Buffer, fill bool) error {
 	return nil
 }
 
+// DecodeTransaction parses the transaction binary without loading contracts and
+// without access to the database. TxContract and TxData are not filled for smart contracts
+func DecodeTransaction(data []byte) (*Transaction, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty transaction buffer")
+	}
+	rtx := &Transaction{FullData: data}
+	var err error
+	switch data[0] {
+	case types.SmartContractTxType, types.TransferSelfTxType, types.UtxoTxType, types.AbstractAccountTxType,
+		types.ConfidentialUTXOTxType:
+		itx := &SmartTransactionParser{
+			SmartContract: &smart.SmartContract{TxSmart: new(types.SmartTransaction)},
+		}
+		rtx.Inner = itx
+		err = msgpack.Unmarshal(data[1:], itx)
+		if err == nil && itx.TxSmart.Header == nil {
+			err = fmt.Errorf("empty transaction header")
+		}
+	case types.FirstBlockTxType:
+		itx := &FirstBlockParser{}
+		rtx.Inner = itx
+		err = msgpack.Unmarshal(data[1:], itx)
+		if err == nil && itx.Data == nil {
+			err = fmt.Errorf("empty first block data")
+		}
+	case types.StopNetworkTxType:
+		itx := &StopNetworkParser{}
+		rtx.Inner = itx
+		err = msgpack.Unmarshal(data[1:], itx)
+		if err == nil && itx.Data == nil {
+			err = fmt.Errorf("empty stop network data")
+		}
+	case types.KeyRotationTxType:
+		itx := &KeyRotationParser{}
+		rtx.Inner = itx
+		err = itx.decode(data)
+	case types.BanLiftTxType:
+		itx := &BanLiftParser{}
+		rtx.Inner = itx
+		err = itx.decode(data)
+	default:
+		if !types.IsCustomTxType(int(data[0])) {
+			return nil, fmt.Errorf("unsupported tx type %d", data[0])
+		}
+		itx := &CustomTxParser{}
+		rtx.Inner = itx
+		err = itx.decode(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse transaction error: %w", err)
+	}
+	return rtx, nil
+}
+
 func (rtx *Transaction) SetRawTx() *sqldb.RawTx {
 	return &sqldb.RawTx{
 		Hash:     rtx.Hash(),
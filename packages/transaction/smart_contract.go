@@ -54,9 +54,14 @@ func (s *SmartTransactionParser) Init(t *InToCxt) error {
 	s.CLB = false
 	s.Rollback = true
 	s.SysUpdate = false
+	s.TxMemPeak = 0
+	s.TraceCalls = t.TraceCalls
+	s.Ctx = t.Ctx
+	s.Calls = nil
 	s.OutputsMap = t.OutputsMap
 	s.PrevSysPar = t.PrevSysPar
 	s.EcoParams = t.EcoParams
+	s.Logger = t.Logger
 	s.TxInputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
 	s.TxOutputsMap = make(map[sqldb.KeyUTXO][]sqldb.SpentInfo)
 	s.RollBackTx = make([]*types.RollbackTx, 0)
@@ -71,6 +76,10 @@ func (s *SmartTransactionParser) Validate() error {
 	if err := s.TxSmart.Validate(); err != nil {
 		return err
 	}
+	if s.TxSmart.AbstractAccount {
+		// the auth data is checked by the auth contract of the key
+		return nil
+	}
 	_, err := utils.CheckSign([][]byte{crypto.CutPub(s.TxSmart.PublicKey)}, s.Hash, s.TxSignature, false)
 	if err != nil {
 		return err
@@ -91,13 +100,16 @@ func (s *SmartTransactionParser) Action(in *InToCxt, out *OutCtx) (err error) {
 		if s.Penalty {
 			ret.Code = pbgo.TxInvokeStatusCode_PENALTY
 			ret.BlockId = s.BlockHeader.BlockId
+		} else if err == nil && s.TxSmart.DryRun {
+			ret.Code = pbgo.TxInvokeStatusCode_DRYRUN
+			ret.BlockId = s.BlockHeader.BlockId
 		}
 		out.Apply(
 			WithOutCtxTxResult(ret),
 			WithOutCtxSysUpdate(s.SysUpdate),
 			WithOutCtxRollBackTx(s.RollBackTx),
 		)
-		if err != nil || s.Penalty {
+		if err != nil || s.Penalty || s.TxSmart.DryRun {
 			if s.FlushRollback != nil {
 				flush := s.FlushRollback
 				for i := len(flush) - 1; i >= 0; i-- {
@@ -140,6 +152,12 @@ func (s *SmartTransactionParser) Action(in *InToCxt, out *OutCtx) (err error) {
 		}
 		return
 	}
+	if s.TxSmart.ConfidentialUTXO != nil {
+		if err = smart.ConfidentialUtxoToken(s.SmartContract, s.TxSmart.ConfidentialUTXO); err != nil {
+			return err
+		}
+		return in.TxCheckLimits.CheckLimit(s)
+	}
 	res, err = s.CallContract(in.SqlDbSavePoint)
 	if err == nil && s.TxSmart != nil {
 		err = in.TxCheckLimits.CheckLimit(s)
@@ -204,7 +222,11 @@ func (s *SmartTransactionParser) Unmarshal(buffer *bytes.Buffer, fill bool) erro
 	if err := msgpack.Unmarshal(buffer.Bytes()[1:], s); err != nil {
 		return err
 	}
-	if s.SmartContract.TxSmart.UTXO != nil || s.SmartContract.TxSmart.TransferSelf != nil {
+	if s.SmartContract == nil || s.TxSmart == nil || s.TxSmart.Header == nil {
+		return fmt.Errorf("empty transaction header")
+	}
+	if s.SmartContract.TxSmart.UTXO != nil || s.SmartContract.TxSmart.TransferSelf != nil ||
+		s.SmartContract.TxSmart.ConfidentialUTXO != nil {
 		return nil
 	}
 	if err := s.parseFromContract(fill); err != nil {
@@ -258,7 +280,7 @@ func (s *SmartTransactionParser) SysUpdateWorker(dbTx *sqldb.DbTransaction) erro
 		return nil
 	}
 	if err := syspar.SysUpdate(dbTx); err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+		s.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
 		return err
 	}
 	return nil
@@ -266,7 +288,7 @@ func (s *SmartTransactionParser) SysUpdateWorker(dbTx *sqldb.DbTransaction) erro
 
 func (s *SmartTransactionParser) SysTableColByteaWorker(dbTx *sqldb.DbTransaction) error {
 	if err := syspar.SysTableColType(dbTx); err != nil {
-		log.WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
+		s.GetLogger().WithFields(log.Fields{"type": consts.DBError, "error": err}).Error("updating syspar")
 		return err
 	}
 	return nil
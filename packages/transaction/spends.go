@@ -0,0 +1,57 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"strings"
+
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
+)
+
+// Spends is the set of the outputs reserved by the UTXO transactions of the generated block.
+// The transaction spends all the unused outputs of its key in the ecosystem, so the outputs
+// are reserved by the key and the ecosystem
+type Spends map[sqldb.KeyUTXO]bool
+
+// spentOutputs returns the outputs which are spent by the transaction
+func spentOutputs(tx *SmartTransactionParser) []sqldb.KeyUTXO {
+	txSmart := tx.TxSmart
+	if txSmart == nil || txSmart.Header == nil {
+		return nil
+	}
+	key := sqldb.KeyUTXO{Ecosystem: txSmart.EcosystemID, KeyId: txSmart.KeyID}
+	switch {
+	case txSmart.UTXO != nil:
+		// the fee is paid by the outputs of the default ecosystem
+		if key.Ecosystem != consts.DefaultTokenEcosystem {
+			return []sqldb.KeyUTXO{key, {Ecosystem: consts.DefaultTokenEcosystem, KeyId: key.KeyId}}
+		}
+		return []sqldb.KeyUTXO{key}
+	case txSmart.TransferSelf != nil && strings.EqualFold(txSmart.TransferSelf.Source, "UTXO"),
+		txSmart.ConfidentialUTXO != nil:
+		return []sqldb.KeyUTXO{key}
+	}
+	return nil
+}
+
+// Reserved returns true if any output spent by the transaction is reserved by the previous transaction,
+// such transaction is skipped and stays in the queue for the next blocks
+func (s Spends) Reserved(tx *SmartTransactionParser) bool {
+	for _, key := range spentOutputs(tx) {
+		if s[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// Reserve reserves the outputs spent by the transaction
+func (s Spends) Reserve(tx *SmartTransactionParser) {
+	for _, key := range spentOutputs(tx) {
+		s[key] = true
+	}
+}
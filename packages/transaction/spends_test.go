@@ -0,0 +1,52 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func newSpendTx(keyID, ecosystem int64, utxo *types.UTXO, transferSelf *types.TransferSelf) *SmartTransactionParser {
+	return newWeightTx(&types.SmartTransaction{Header: &types.Header{KeyID: keyID, EcosystemID: ecosystem},
+		UTXO: utxo, TransferSelf: transferSelf}, nil)
+}
+
+// TestSpendsReserved checks that the second spend of the outputs is skipped by the generator
+func TestSpendsReserved(t *testing.T) {
+	spends := make(Spends)
+	first := newSpendTx(1, 1, &types.UTXO{ToID: 2, Value: "10"}, nil)
+	if spends.Reserved(first) {
+		t.Fatal("first spend is reserved")
+	}
+	spends.Reserve(first)
+
+	for i, tc := range []struct {
+		tx       *SmartTransactionParser
+		reserved bool
+	}{
+		{newSpendTx(1, 1, &types.UTXO{ToID: 3, Value: "20"}, nil), true},
+		{newSpendTx(1, 1, nil, &types.TransferSelf{Value: "5", Source: "UTXO", Target: "Account"}), true},
+		// the fee of the other ecosystem is paid by the outputs of the first ecosystem
+		{newSpendTx(1, 2, &types.UTXO{ToID: 3, Value: "20"}, nil), true},
+		{newSpendTx(2, 1, &types.UTXO{ToID: 1, Value: "20"}, nil), false},
+		// the transfer to the outputs doesn't spend them
+		{newSpendTx(1, 1, nil, &types.TransferSelf{Value: "5", Source: "Account", Target: "UTXO"}), false},
+		{newSpendTx(1, 1, nil, nil), false},
+	} {
+		if got := spends.Reserved(tc.tx); got != tc.reserved {
+			t.Errorf("%d: expected reserved %v, got %v", i, tc.reserved, got)
+		}
+	}
+
+	// the spend of the other ecosystem reserves the outputs of both ecosystems
+	spends = make(Spends)
+	spends.Reserve(newSpendTx(1, 2, &types.UTXO{ToID: 3, Value: "20"}, nil))
+	if !spends.Reserved(first) {
+		t.Error("outputs of the first ecosystem aren't reserved")
+	}
+}
@@ -6,6 +6,7 @@ package transaction
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math/rand"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/storage/sqldb"
 	"github.com/IBAX-io/go-ibax/packages/types"
 	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
 )
 
 // Transaction is a structure for parsing transactions
@@ -53,6 +55,11 @@ func (t *Transaction) SmartContract() *SmartTransactionParser {
 	return t.Inner.(*SmartTransactionParser)
 }
 
+// IsDryRun returns true if the played transaction is the dry run of the contract
+func (t *Transaction) IsDryRun() bool {
+	return t.OutCtx != nil && t.TxResult != nil && t.TxResult.Code == pbgo.TxInvokeStatusCode_DRYRUN
+}
+
 // UnmarshallTransaction is unmarshalling transaction
 func UnmarshallTransaction(buffer *bytes.Buffer, fill bool) (*Transaction, error) {
 	tx := &Transaction{}
@@ -107,6 +114,30 @@ func (tr *Transaction) WithOption(
 
 type TransactionOption func(b *Transaction) error
 
+// WithLogger sets the logger which is propagated to the contract and the db transaction
+func WithLogger(logger *log.Entry) TransactionOption {
+	return func(b *Transaction) error {
+		b.Logger = logger
+		return nil
+	}
+}
+
+// WithTraceCalls records the nested calls of the contracts of the transaction
+func WithTraceCalls(on bool) TransactionOption {
+	return func(b *Transaction) error {
+		b.TraceCalls = on
+		return nil
+	}
+}
+
+// WithContext sets the context which cancels the play of the transaction
+func WithContext(ctx context.Context) TransactionOption {
+	return func(b *Transaction) error {
+		b.Ctx = ctx
+		return nil
+	}
+}
+
 func (tr *Transaction) Apply(opts ...TransactionOption) error {
 	for _, opt := range opts {
 		if opt == nil {
@@ -0,0 +1,50 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/types"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func fuzzSeed(f *testing.F, txType byte, v any) []byte {
+	out, err := msgpack.Marshal(v)
+	if err != nil {
+		f.Fatal(err)
+	}
+	return append([]byte{txType}, out...)
+}
+
+// FuzzUnmarshallTransaction checks that the malformed transactions from the network are rejected
+// with the error, run it with -fuzz FuzzUnmarshallTransaction
+func FuzzUnmarshallTransaction(f *testing.F) {
+	f.Add(smartTxData(f, map[string]any{"Value": "1", "List": []any{int64(1), "2"}}))
+	f.Add(fuzzSeed(f, types.TransferSelfTxType, struct{ TxSmart *types.SmartTransaction }{&types.SmartTransaction{
+		Header:       &types.Header{ID: 1, EcosystemID: 1, KeyID: 1},
+		TransferSelf: &types.TransferSelf{Value: "100", Source: "Account", Target: "UTXO"},
+	}}))
+	f.Add(fuzzSeed(f, types.FirstBlockTxType, &FirstBlockParser{Data: &types.FirstBlock{KeyID: 1}, Timestamp: 1}))
+	f.Add(fuzzSeed(f, types.StopNetworkTxType, &StopNetworkParser{Data: &types.StopNetwork{KeyID: 1}, Timestamp: 1}))
+	f.Add(fuzzSeed(f, types.CustomTxTypeMin, &CustomTxParser{
+		Payload: fuzzSeed(f, types.CustomTxTypeMin, &types.CustomTransaction{Type: types.CustomTxTypeMin, KeyID: 1})[1:],
+	}))
+	payload, err := msgpack.Marshal(&types.SmartTransaction{Header: &types.Header{ID: 1, EcosystemID: 1, KeyID: 1}})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(append([]byte{128}, converter.EncodeLengthPlusData(payload)...))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx, err := UnmarshallTransaction(bytes.NewBuffer(data), true)
+		if err == nil && tx.Inner == nil {
+			t.Error("transaction without parser")
+		}
+	})
+}
@@ -0,0 +1,39 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import "github.com/IBAX-io/go-ibax/packages/script"
+
+// Weight returns the weight of the transaction in the block. The contract transactions weigh
+// the count of the compiled instructions of the contract, utxo, transfer self, delayed and
+// the other transactions weigh 1
+func Weight(t *Transaction) int64 {
+	return weight(t.Inner)
+}
+
+func weight(t TransactionCaller) int64 {
+	s, ok := t.(*SmartTransactionParser)
+	if !ok || s.Delayed || s.TxSmart == nil || s.TxSmart.TransferSelf != nil || s.TxSmart.UTXO != nil ||
+		s.TxContract == nil {
+		return 1
+	}
+	if w := codeWeight(s.TxContract.Block); w > 0 {
+		return w
+	}
+	return 1
+}
+
+// codeWeight returns the count of the instructions of the block and its functions
+func codeWeight(block *script.CodeBlock) int64 {
+	if block == nil {
+		return 0
+	}
+	w := int64(len(block.Code))
+	for _, child := range block.Children {
+		w += codeWeight(child)
+	}
+	return w
+}
@@ -0,0 +1,70 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/IBAX-io/go-ibax/packages/script"
+	"github.com/IBAX-io/go-ibax/packages/smart"
+	"github.com/IBAX-io/go-ibax/packages/types"
+)
+
+func newWeightTx(tx *types.SmartTransaction, block *script.CodeBlock) *SmartTransactionParser {
+	sc := &smart.SmartContract{TxSmart: tx}
+	if block != nil {
+		sc.TxContract = &smart.Contract{Name: "@1Test", Block: block}
+	}
+	return &SmartTransactionParser{SmartContract: sc}
+}
+
+func TestWeight(t *testing.T) {
+	block := &script.CodeBlock{
+		Code: make(script.ByteCodes, 3),
+		Children: script.CodeBlocks{
+			{Code: make(script.ByteCodes, 10)},
+			{Code: make(script.ByteCodes, 5), Children: script.CodeBlocks{{Code: make(script.ByteCodes, 2)}}},
+		},
+	}
+	contract := newWeightTx(&types.SmartTransaction{Header: &types.Header{}}, block)
+	delayed := newWeightTx(&types.SmartTransaction{Header: &types.Header{}}, block)
+	delayed.Delayed = true
+	for _, c := range []struct {
+		tx     TransactionCaller
+		weight int64
+	}{
+		{contract, 20},
+		{delayed, 1},
+		{newWeightTx(&types.SmartTransaction{Header: &types.Header{}, UTXO: &types.UTXO{}}, nil), 1},
+		{newWeightTx(&types.SmartTransaction{Header: &types.Header{}, TransferSelf: &types.TransferSelf{}}, nil), 1},
+		{&StopNetworkParser{Data: &types.StopNetwork{}}, 1},
+	} {
+		if w := weight(c.tx); w != c.weight {
+			t.Errorf("expected weight %d, got %d", c.weight, w)
+		}
+	}
+	if w := Weight(&Transaction{Inner: contract}); w != 20 {
+		t.Errorf("expected weight 20, got %d", w)
+	}
+
+	// the generation stops when the weight is over, the parsed block is invalid
+	limit := &txMaxWeight{Limit: 50}
+	for i := 0; i < 2; i++ {
+		if err := limit.check(contract, letGenBlock); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := limit.check(contract, letGenBlock); !errors.Is(pkgerrors.Cause(err), ErrLimitStop) {
+		t.Errorf("expected stop, got %v", err)
+	}
+	limit = &txMaxWeight{Limit: 50, Weight: 40}
+	if err := limit.check(contract, letParsing); err == nil || errors.Is(pkgerrors.Cause(err), ErrLimitStop) {
+		t.Errorf("expected limit error, got %v", err)
+	}
+}
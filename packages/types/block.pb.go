@@ -63,6 +63,22 @@ type BlockHeader struct {
 	ConsensusMode  int32  `protobuf:"varint,10,opt,name=consensus_mode,json=consensusMode,proto3" json:"consensus_mode,omitempty"`
 	CandidateNodes []byte `protobuf:"bytes,11,opt,name=candidate_nodes,json=candidateNodes,proto3" json:"candidate_nodes,omitempty"`
 	NetworkId      int64  `protobuf:"varint,12,opt,name=network_id,json=networkId,proto3" json:"network_id,omitempty"`
+	// the nonce of the proof of work of the block which is generated out of the slot of its node
+	ProofOfWork uint64 `protobuf:"varint,13,opt,name=proof_of_work,json=proofOfWork,proto3" json:"proof_of_work,omitempty"`
+	// the base gas price of the block which is adjusted by the fuel of the previous block
+	BaseGasPrice int64 `protobuf:"varint,14,opt,name=base_gas_price,json=baseGasPrice,proto3" json:"base_gas_price,omitempty"`
+	// the signatures of the validators of the block, they aren't signed and hashed with the header
+	BlockSignatures []*BlockSignature `protobuf:"bytes,15,rep,name=block_signatures,json=blockSignatures,proto3" json:"block_signatures,omitempty"`
+	// the random beacon of the block, it's the hash of the beacon of the previous block and the reveal
+	RandomBeacon []byte `protobuf:"bytes,16,opt,name=random_beacon,json=randomBeacon,proto3" json:"random_beacon,omitempty"`
+	// the secret of the previous block of the node whose hash is its commit
+	RandomReveal []byte `protobuf:"bytes,17,opt,name=random_reveal,json=randomReveal,proto3" json:"random_reveal,omitempty"`
+	// the hash of the secret which is revealed by the next block of the node
+	RandomCommit []byte `protobuf:"bytes,18,opt,name=random_commit,json=randomCommit,proto3" json:"random_commit,omitempty"`
+	// the merkle root of the rows which are changed by the block with their values after the block
+	StateRoot []byte `protobuf:"bytes,19,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	// the bloom filter of the topics of the events which are emitted by the transactions of the block
+	EventsBloom []byte `protobuf:"bytes,20,opt,name=events_bloom,json=eventsBloom,proto3" json:"events_bloom,omitempty"`
 }
 
 func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
@@ -182,6 +198,62 @@ func (m *BlockHeader) GetNetworkId() int64 {
 	return 0
 }
 
+func (m *BlockHeader) GetProofOfWork() uint64 {
+	if m != nil {
+		return m.ProofOfWork
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetBaseGasPrice() int64 {
+	if m != nil {
+		return m.BaseGasPrice
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetBlockSignatures() []*BlockSignature {
+	if m != nil {
+		return m.BlockSignatures
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetRandomBeacon() []byte {
+	if m != nil {
+		return m.RandomBeacon
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetRandomReveal() []byte {
+	if m != nil {
+		return m.RandomReveal
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetRandomCommit() []byte {
+	if m != nil {
+		return m.RandomCommit
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetEventsBloom() []byte {
+	if m != nil {
+		return m.EventsBloom
+	}
+	return nil
+}
+
 // BlockData is a structure of the block's
 type BlockData struct {
 	Header     *BlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -275,51 +347,117 @@ func (m *BlockData) GetSysUpdate() bool {
 	return false
 }
 
+// BlockSignature is a signature of the block by the validator
+type BlockSignature struct {
+	ValidatorKeyId int64  `protobuf:"varint,1,opt,name=validator_key_id,json=validatorKeyId,proto3" json:"validator_key_id,omitempty"`
+	Signature      []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *BlockSignature) Reset()         { *m = BlockSignature{} }
+func (m *BlockSignature) String() string { return proto.CompactTextString(m) }
+func (*BlockSignature) ProtoMessage()    {}
+func (*BlockSignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8e550b1f5926e92d, []int{2}
+}
+func (m *BlockSignature) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BlockSignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BlockSignature.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *BlockSignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockSignature.Merge(m, src)
+}
+func (m *BlockSignature) XXX_Size() int {
+	return m.Size()
+}
+func (m *BlockSignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockSignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockSignature proto.InternalMessageInfo
+
+func (m *BlockSignature) GetValidatorKeyId() int64 {
+	if m != nil {
+		return m.ValidatorKeyId
+	}
+	return 0
+}
+
+func (m *BlockSignature) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("types.BlockSyncMethod", BlockSyncMethod_name, BlockSyncMethod_value)
 	proto.RegisterType((*BlockHeader)(nil), "types.BlockHeader")
 	proto.RegisterType((*BlockData)(nil), "types.BlockData")
+	proto.RegisterType((*BlockSignature)(nil), "types.BlockSignature")
 }
 
 func init() { proto.RegisterFile("block.proto", fileDescriptor_8e550b1f5926e92d) }
 
 var fileDescriptor_8e550b1f5926e92d = []byte{
-	// 554 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0xcd, 0x6e, 0xd3, 0x40,
-	0x14, 0x85, 0xe3, 0xa6, 0xf9, 0xf1, 0xb5, 0xfb, 0xa3, 0x91, 0x90, 0x0c, 0x02, 0x13, 0x8a, 0x10,
-	0xa5, 0xa2, 0x89, 0xd4, 0x3e, 0x41, 0x9b, 0x0a, 0x35, 0x52, 0x53, 0xc0, 0x0d, 0x08, 0xb1, 0xb1,
-	0xc6, 0x9e, 0x69, 0x6c, 0xf9, 0x67, 0x2c, 0xcf, 0xa4, 0xc4, 0x6f, 0xc0, 0x92, 0x1d, 0xaf, 0xc4,
-	0xb2, 0x4b, 0x96, 0x28, 0x79, 0x11, 0x34, 0xd7, 0x26, 0xb0, 0x61, 0x37, 0xf3, 0x9d, 0x73, 0x33,
-	0x27, 0xf7, 0x24, 0x60, 0x05, 0xa9, 0x08, 0x93, 0x61, 0x51, 0x0a, 0x25, 0x48, 0x47, 0x55, 0x05,
-	0x97, 0x8f, 0xa0, 0x48, 0x69, 0x55, 0xa3, 0x83, 0xaf, 0x6d, 0xb0, 0xce, 0xb5, 0xe5, 0x92, 0x53,
-	0xc6, 0x4b, 0xf2, 0x10, 0xfa, 0x38, 0xe1, 0xc7, 0xcc, 0x31, 0x06, 0xc6, 0x61, 0xdb, 0xeb, 0xe1,
-	0x7d, 0xc2, 0xc8, 0x63, 0x30, 0x55, 0x9c, 0x71, 0xa9, 0x68, 0x56, 0x38, 0x5b, 0xa8, 0xfd, 0x05,
-	0xe4, 0x19, 0xd8, 0x3c, 0x14, 0xb2, 0x92, 0x8a, 0x67, 0x7a, 0xb8, 0x8d, 0x06, 0x6b, 0xc3, 0x26,
-	0x8c, 0x3c, 0x80, 0x6e, 0xc2, 0x2b, 0x2d, 0x6e, 0xa3, 0xd8, 0x49, 0x78, 0x35, 0x61, 0xe4, 0x39,
-	0xec, 0xe4, 0x82, 0x71, 0xbf, 0x10, 0x32, 0x56, 0xb1, 0xc8, 0x9d, 0x0e, 0xaa, 0xb6, 0x86, 0xef,
-	0x1a, 0x46, 0x08, 0x6c, 0xcb, 0x78, 0x9e, 0x3b, 0xdd, 0x81, 0x71, 0x68, 0x7b, 0x78, 0x26, 0x4f,
-	0x00, 0xea, 0xac, 0x11, 0x95, 0x91, 0xd3, 0x43, 0xc5, 0x44, 0x72, 0x49, 0x65, 0x44, 0x5e, 0xc0,
-	0x6e, 0x29, 0xd2, 0x34, 0xa0, 0x61, 0x22, 0x6b, 0x4b, 0x1f, 0x2d, 0x3b, 0x1b, 0x8a, 0x36, 0x07,
-	0x7a, 0x77, 0xbc, 0x94, 0xfa, 0x61, 0x73, 0x60, 0x1c, 0x76, 0xbc, 0x3f, 0x57, 0xfd, 0x01, 0xa1,
-	0xc8, 0x25, 0xcf, 0xe5, 0x42, 0xfa, 0x99, 0x60, 0xdc, 0x01, 0x34, 0xec, 0x6c, 0xe8, 0x54, 0x30,
-	0x4e, 0x5e, 0xc2, 0x5e, 0x48, 0x73, 0x16, 0x33, 0xaa, 0xb8, 0xaf, 0x43, 0x4b, 0xc7, 0xc2, 0x87,
-	0x76, 0x37, 0xf8, 0x5a, 0x53, 0x9d, 0x37, 0xe7, 0xea, 0x8b, 0x28, 0x71, 0xbb, 0x76, 0xbd, 0xc1,
-	0x86, 0x4c, 0xd8, 0xc1, 0xf7, 0x2d, 0x30, 0xb1, 0x8a, 0x0b, 0xaa, 0x28, 0x39, 0x82, 0x6e, 0x84,
-	0x95, 0x60, 0x0d, 0xd6, 0x09, 0x19, 0x62, 0x79, 0xc3, 0x7f, 0xca, 0xf2, 0x1a, 0x07, 0x39, 0x05,
-	0xab, 0x28, 0xf9, 0x9d, 0xdf, 0x0c, 0x6c, 0xfd, 0x77, 0x00, 0xb4, 0xad, 0x69, 0xfa, 0x29, 0x58,
-	0x19, 0x2f, 0x93, 0x94, 0xfb, 0xa5, 0x10, 0x0a, 0xfb, 0xb2, 0x3d, 0xa8, 0x91, 0x27, 0x84, 0xc2,
-	0x9f, 0x42, 0x9c, 0xfb, 0x8c, 0x2a, 0x8a, 0x85, 0xd9, 0x5e, 0x2f, 0x88, 0x73, 0x0c, 0x37, 0x00,
-	0x5b, 0x2d, 0xfd, 0xdb, 0x45, 0x9a, 0xd6, 0x72, 0x67, 0xd0, 0xd6, 0xc3, 0x6a, 0xf9, 0x66, 0x91,
-	0xa6, 0xe8, 0x78, 0x0d, 0x26, 0xbd, 0x55, 0xbc, 0xf4, 0xd5, 0x52, 0x62, 0x69, 0xd6, 0xc9, 0x5e,
-	0x13, 0xe8, 0x4c, 0xf3, 0xd9, 0x52, 0x7a, 0x7d, 0xda, 0x9c, 0xf4, 0x66, 0x64, 0x25, 0xfd, 0x45,
-	0xa1, 0x97, 0x85, 0x4d, 0xf6, 0x3d, 0x53, 0x56, 0xf2, 0x03, 0x82, 0xa3, 0x63, 0xd8, 0xc3, 0x6f,
-	0x71, 0x53, 0xe5, 0xe1, 0x94, 0xab, 0x48, 0x30, 0xb2, 0x0b, 0x30, 0x7e, 0x7b, 0x3d, 0xf3, 0xce,
-	0xc6, 0xb3, 0x8f, 0xd3, 0xfd, 0x16, 0x01, 0xe8, 0xde, 0xbc, 0xbf, 0xba, 0x98, 0x5e, 0xed, 0x1b,
-	0xe7, 0xe3, 0x1f, 0x2b, 0xd7, 0xb8, 0x5f, 0xb9, 0xc6, 0xaf, 0x95, 0x6b, 0x7c, 0x5b, 0xbb, 0xad,
-	0xfb, 0xb5, 0xdb, 0xfa, 0xb9, 0x76, 0x5b, 0x9f, 0x5f, 0xcd, 0x63, 0x15, 0x2d, 0x82, 0x61, 0x28,
-	0xb2, 0xd1, 0xe4, 0xfc, 0xec, 0xd3, 0x71, 0x2c, 0x46, 0x73, 0x71, 0x1c, 0x07, 0x74, 0x39, 0x2a,
-	0x68, 0x98, 0xd0, 0x39, 0x97, 0x23, 0x4c, 0x19, 0x74, 0xf1, 0xff, 0x71, 0xfa, 0x3b, 0x00, 0x00,
-	0xff, 0xff, 0x6d, 0xf2, 0x4f, 0x4d, 0x41, 0x03, 0x00, 0x00,
+	// 743 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x94, 0xcd, 0x8e, 0xe3, 0x44,
+	0x10, 0xc7, 0xe3, 0x64, 0xf2, 0x55, 0xce, 0x17, 0x0d, 0x2b, 0x35, 0x68, 0x09, 0x21, 0x80, 0x08,
+	0x2b, 0x26, 0x91, 0x66, 0x5f, 0x80, 0x49, 0x56, 0xb0, 0x11, 0x9b, 0xdd, 0xc5, 0x3b, 0xc0, 0x8a,
+	0x4b, 0xab, 0x6d, 0x77, 0x12, 0x2b, 0xb6, 0xdb, 0xea, 0xee, 0x84, 0xf8, 0x2d, 0xb8, 0xf1, 0x44,
+	0x48, 0x1c, 0xf7, 0xc8, 0x11, 0xcd, 0xbc, 0x08, 0xea, 0xb2, 0x27, 0x64, 0x0e, 0x7b, 0xeb, 0xfe,
+	0xd5, 0xbf, 0xe2, 0xea, 0xaa, 0x7f, 0x05, 0x5c, 0x3f, 0x96, 0xc1, 0x6e, 0x9a, 0x29, 0x69, 0x24,
+	0xa9, 0x9b, 0x3c, 0x13, 0xfa, 0x13, 0xc8, 0x62, 0x9e, 0x17, 0x68, 0xfc, 0x57, 0x1d, 0xdc, 0xb9,
+	0x95, 0x3c, 0x17, 0x3c, 0x14, 0x8a, 0x7c, 0x0c, 0x2d, 0xcc, 0x60, 0x51, 0x48, 0x9d, 0x91, 0x33,
+	0xa9, 0x79, 0x4d, 0xbc, 0x2f, 0x43, 0xf2, 0x18, 0xda, 0x26, 0x4a, 0x84, 0x36, 0x3c, 0xc9, 0x68,
+	0x15, 0x63, 0xff, 0x03, 0xf2, 0x39, 0x74, 0x44, 0x20, 0x75, 0xae, 0x8d, 0x48, 0x6c, 0x72, 0x0d,
+	0x05, 0xee, 0x89, 0x2d, 0x43, 0xf2, 0x08, 0x1a, 0x3b, 0x91, 0xdb, 0xe0, 0x05, 0x06, 0xeb, 0x3b,
+	0x91, 0x2f, 0x43, 0xf2, 0x05, 0x74, 0x53, 0x19, 0x0a, 0x96, 0x49, 0x1d, 0x99, 0x48, 0xa6, 0xb4,
+	0x8e, 0xd1, 0x8e, 0x85, 0xaf, 0x4b, 0x46, 0x08, 0x5c, 0xe8, 0x68, 0x93, 0xd2, 0xc6, 0xc8, 0x99,
+	0x74, 0x3c, 0x3c, 0x93, 0x4f, 0x01, 0x8a, 0x5a, 0xb7, 0x5c, 0x6f, 0x69, 0x13, 0x23, 0x6d, 0x24,
+	0xcf, 0xb9, 0xde, 0x92, 0xaf, 0xa0, 0xa7, 0x64, 0x1c, 0xfb, 0x3c, 0xd8, 0xe9, 0x42, 0xd2, 0x42,
+	0x49, 0xf7, 0x44, 0x51, 0x46, 0xa1, 0x79, 0x10, 0x4a, 0xdb, 0x0f, 0xb7, 0x47, 0xce, 0xa4, 0xee,
+	0xdd, 0x5f, 0xed, 0x0f, 0x04, 0x32, 0xd5, 0x22, 0xd5, 0x7b, 0xcd, 0x12, 0x19, 0x0a, 0x0a, 0x28,
+	0xe8, 0x9e, 0xe8, 0x4a, 0x86, 0x82, 0x7c, 0x0d, 0xfd, 0x80, 0xa7, 0x61, 0x14, 0x72, 0x23, 0x98,
+	0x2d, 0x5a, 0x53, 0x17, 0x3f, 0xd4, 0x3b, 0xe1, 0x97, 0x96, 0xda, 0x7a, 0x53, 0x61, 0x7e, 0x97,
+	0x0a, 0xbb, 0xdb, 0x29, 0x3a, 0x58, 0x92, 0x65, 0x48, 0xc6, 0xd0, 0xcd, 0x94, 0x94, 0x6b, 0x26,
+	0xd7, 0xcc, 0x22, 0xda, 0x1d, 0x39, 0x93, 0x0b, 0xcf, 0x45, 0xf8, 0x6a, 0xfd, 0xab, 0x54, 0x3b,
+	0xf2, 0x25, 0xf4, 0x7c, 0xae, 0x05, 0xdb, 0x70, 0xcd, 0x32, 0x15, 0x05, 0x82, 0xf6, 0x8a, 0x66,
+	0x59, 0xfa, 0x03, 0xd7, 0xaf, 0x2d, 0x23, 0xdf, 0xc1, 0xa0, 0x68, 0x8c, 0x6d, 0x13, 0x37, 0x7b,
+	0x25, 0x34, 0xed, 0x8f, 0x6a, 0x13, 0xf7, 0xea, 0xd1, 0x14, 0x2d, 0x30, 0xc5, 0x91, 0xbf, 0xb9,
+	0x8f, 0x7a, 0x7d, 0xff, 0xc1, 0x5d, 0xdb, 0x99, 0x28, 0x9e, 0x86, 0x32, 0x61, 0xbe, 0xe0, 0x81,
+	0x4c, 0xe9, 0x00, 0x5f, 0xd4, 0x29, 0xe0, 0x1c, 0xd9, 0x99, 0x48, 0x89, 0x83, 0xe0, 0x31, 0xfd,
+	0xe0, 0x5c, 0xe4, 0x21, 0x3b, 0x13, 0x05, 0x32, 0x49, 0x22, 0x43, 0xc9, 0xb9, 0x68, 0x81, 0xcc,
+	0x76, 0x46, 0x1b, 0xdb, 0x3e, 0x25, 0xa5, 0xa1, 0x1f, 0x16, 0x93, 0x44, 0xe2, 0x49, 0x69, 0xd0,
+	0x5b, 0x07, 0x91, 0x1a, 0xcd, 0xfc, 0x58, 0xca, 0x84, 0x7e, 0x84, 0x02, 0xb7, 0x60, 0x73, 0x8b,
+	0xc6, 0x7f, 0x56, 0xa1, 0x8d, 0x8f, 0x7a, 0xc6, 0x0d, 0x27, 0x4f, 0xa0, 0xb1, 0x45, 0x3f, 0xa3,
+	0x87, 0xdd, 0x2b, 0x72, 0xfe, 0xec, 0xc2, 0xe9, 0x5e, 0xa9, 0x20, 0x4f, 0xc1, 0xcd, 0x94, 0x38,
+	0xb0, 0x32, 0xa1, 0xfa, 0xde, 0x04, 0xb0, 0xb2, 0xe2, 0x4c, 0x3e, 0x03, 0x37, 0x11, 0x6a, 0x17,
+	0x97, 0x15, 0xd7, 0xb0, 0x20, 0x28, 0x10, 0x96, 0x6c, 0xf7, 0x28, 0x4a, 0x59, 0xc8, 0x0d, 0x47,
+	0xb7, 0x77, 0xbc, 0xa6, 0x1f, 0xa5, 0x58, 0xdc, 0x08, 0x3a, 0xe6, 0xc8, 0xd6, 0xfb, 0x38, 0x2e,
+	0xc2, 0xf5, 0x51, 0xcd, 0x26, 0x9b, 0xe3, 0xf7, 0xfb, 0x38, 0x46, 0xc5, 0xb7, 0xd0, 0xe6, 0x6b,
+	0x23, 0x14, 0x33, 0x47, 0x8d, 0x8e, 0x77, 0xaf, 0xfa, 0x65, 0x41, 0xd7, 0x96, 0xdf, 0x1c, 0xb5,
+	0xd7, 0xe2, 0xe5, 0x09, 0x9b, 0x97, 0x6b, 0xb6, 0xcf, 0xac, 0xd3, 0x70, 0x0d, 0x5a, 0x5e, 0x5b,
+	0xe7, 0xfa, 0x67, 0x04, 0xe3, 0xb7, 0xd0, 0x7b, 0x38, 0x6d, 0x32, 0x81, 0xc1, 0x81, 0xc7, 0xd6,
+	0x98, 0x52, 0xb1, 0x72, 0x23, 0x8b, 0x5d, 0xef, 0x9d, 0xf8, 0x8f, 0xb8, 0x9a, 0x8f, 0xa1, 0x7d,
+	0xb2, 0x10, 0xad, 0x96, 0x63, 0xb9, 0x07, 0x4f, 0x2e, 0xa1, 0x5f, 0xfc, 0x72, 0x9e, 0x06, 0x2b,
+	0x61, 0xb6, 0x32, 0x24, 0x3d, 0x80, 0xc5, 0xab, 0x97, 0x37, 0xde, 0xf5, 0xe2, 0xe6, 0x97, 0xd5,
+	0xa0, 0x42, 0x00, 0x1a, 0x6f, 0x7e, 0x7a, 0xf1, 0x6c, 0xf5, 0x62, 0xe0, 0xcc, 0x17, 0x7f, 0xdf,
+	0x0e, 0x9d, 0x77, 0xb7, 0x43, 0xe7, 0xdf, 0xdb, 0xa1, 0xf3, 0xc7, 0xdd, 0xb0, 0xf2, 0xee, 0x6e,
+	0x58, 0xf9, 0xe7, 0x6e, 0x58, 0xf9, 0xed, 0x9b, 0x4d, 0x64, 0xb6, 0x7b, 0x7f, 0x1a, 0xc8, 0x64,
+	0xb6, 0x9c, 0x5f, 0xbf, 0xbd, 0x8c, 0xe4, 0x6c, 0x23, 0x2f, 0x23, 0x9f, 0x1f, 0x67, 0x19, 0x0f,
+	0x76, 0x7c, 0x23, 0xf4, 0x0c, 0xdf, 0xef, 0x37, 0xf0, 0x6f, 0xeb, 0xe9, 0x7f, 0x03, 0x00, 0xd6,
+	0xe0, 0x31, 0x57, 0xd8, 0x04, 0x00, 0x00,
 }
 
 func (m *BlockHeader) Marshal() (dAtA []byte, err error) {
@@ -342,6 +480,75 @@ func (m *BlockHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.EventsBloom) > 0 {
+		i -= len(m.EventsBloom)
+		copy(dAtA[i:], m.EventsBloom)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.EventsBloom)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	if len(m.StateRoot) > 0 {
+		i -= len(m.StateRoot)
+		copy(dAtA[i:], m.StateRoot)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.StateRoot)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if len(m.RandomCommit) > 0 {
+		i -= len(m.RandomCommit)
+		copy(dAtA[i:], m.RandomCommit)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.RandomCommit)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if len(m.RandomReveal) > 0 {
+		i -= len(m.RandomReveal)
+		copy(dAtA[i:], m.RandomReveal)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.RandomReveal)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if len(m.RandomBeacon) > 0 {
+		i -= len(m.RandomBeacon)
+		copy(dAtA[i:], m.RandomBeacon)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.RandomBeacon)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if len(m.BlockSignatures) > 0 {
+		for iNdEx := len(m.BlockSignatures) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.BlockSignatures[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintBlock(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x7a
+		}
+	}
+	if m.BaseGasPrice != 0 {
+		i = encodeVarintBlock(dAtA, i, uint64(m.BaseGasPrice))
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.ProofOfWork != 0 {
+		i = encodeVarintBlock(dAtA, i, uint64(m.ProofOfWork))
+		i--
+		dAtA[i] = 0x68
+	}
 	if m.NetworkId != 0 {
 		i = encodeVarintBlock(dAtA, i, uint64(m.NetworkId))
 		i--
@@ -505,6 +712,41 @@ func (m *BlockData) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *BlockSignature) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockSignature) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BlockSignature) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintBlock(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ValidatorKeyId != 0 {
+		i = encodeVarintBlock(dAtA, i, uint64(m.ValidatorKeyId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintBlock(dAtA []byte, offset int, v uint64) int {
 	offset -= sovBlock(v)
 	base := offset
@@ -562,6 +804,38 @@ func (m *BlockHeader) Size() (n int) {
 	if m.NetworkId != 0 {
 		n += 1 + sovBlock(uint64(m.NetworkId))
 	}
+	if m.ProofOfWork != 0 {
+		n += 1 + sovBlock(uint64(m.ProofOfWork))
+	}
+	if m.BaseGasPrice != 0 {
+		n += 1 + sovBlock(uint64(m.BaseGasPrice))
+	}
+	if len(m.BlockSignatures) > 0 {
+		for _, e := range m.BlockSignatures {
+			l = e.Size()
+			n += 1 + l + sovBlock(uint64(l))
+		}
+	}
+	l = len(m.RandomBeacon)
+	if l > 0 {
+		n += 2 + l + sovBlock(uint64(l))
+	}
+	l = len(m.RandomReveal)
+	if l > 0 {
+		n += 2 + l + sovBlock(uint64(l))
+	}
+	l = len(m.RandomCommit)
+	if l > 0 {
+		n += 2 + l + sovBlock(uint64(l))
+	}
+	l = len(m.StateRoot)
+	if l > 0 {
+		n += 2 + l + sovBlock(uint64(l))
+	}
+	l = len(m.EventsBloom)
+	if l > 0 {
+		n += 2 + l + sovBlock(uint64(l))
+	}
 	return n
 }
 
@@ -603,6 +877,22 @@ func (m *BlockData) Size() (n int) {
 	return n
 }
 
+func (m *BlockSignature) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ValidatorKeyId != 0 {
+		n += 1 + sovBlock(uint64(m.ValidatorKeyId))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovBlock(uint64(l))
+	}
+	return n
+}
+
 func sovBlock(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -926,6 +1216,248 @@ func (m *BlockHeader) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofOfWork", wireType)
+			}
+			m.ProofOfWork = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProofOfWork |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BaseGasPrice", wireType)
+			}
+			m.BaseGasPrice = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BaseGasPrice |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockSignatures", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockSignatures = append(m.BlockSignatures, &BlockSignature{})
+			if err := m.BlockSignatures[len(m.BlockSignatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RandomBeacon", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RandomBeacon = append(m.RandomBeacon[:0], dAtA[iNdEx:postIndex]...)
+			if m.RandomBeacon == nil {
+				m.RandomBeacon = []byte{}
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RandomReveal", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RandomReveal = append(m.RandomReveal[:0], dAtA[iNdEx:postIndex]...)
+			if m.RandomReveal == nil {
+				m.RandomReveal = []byte{}
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RandomCommit", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RandomCommit = append(m.RandomCommit[:0], dAtA[iNdEx:postIndex]...)
+			if m.RandomCommit == nil {
+				m.RandomCommit = []byte{}
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateRoot", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateRoot = append(m.StateRoot[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateRoot == nil {
+				m.StateRoot = []byte{}
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EventsBloom", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EventsBloom = append(m.EventsBloom[:0], dAtA[iNdEx:postIndex]...)
+			if m.EventsBloom == nil {
+				m.EventsBloom = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipBlock(dAtA[iNdEx:])
@@ -1225,6 +1757,109 @@ func (m *BlockData) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *BlockSignature) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowBlock
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockSignature: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockSignature: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidatorKeyId", wireType)
+			}
+			m.ValidatorKeyId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ValidatorKeyId |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowBlock
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthBlock
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipBlock(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthBlock
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipBlock(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,60 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// blockCompressionMagic is the magic number of the zstd frame. It can't be the start of the legacy binary,
+// the tag of the field 5 of BlockData has the wire type 2, and the formatted binary starts with blockFormatMarker
+var blockCompressionMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// ErrCompressedBlock is returned if the compressed block can't be decompressed within the size
+var ErrCompressedBlock = errors.New("wrong compressed block")
+
+var (
+	blockEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	blockDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecodeAllCapLimit(true))
+)
+
+// IsCompressedBlock returns true if the block binary is compressed
+func IsCompressedBlock(data []byte) bool {
+	return bytes.HasPrefix(data, blockCompressionMagic)
+}
+
+// CompressBlock returns the block binary compressed by zstd, the compressed binary is returned as is
+func CompressBlock(data []byte) []byte {
+	if len(data) == 0 || IsCompressedBlock(data) {
+		return data
+	}
+	return blockEncoder.EncodeAll(data, make([]byte, 0, len(data)/2))
+}
+
+// DecompressBlock returns the block binary of the compressed one, the uncompressed legacy binary is returned
+// as is. The block larger than maxSize isn't decompressed, zero maxSize doesn't limit it
+func DecompressBlock(data []byte, maxSize int64) ([]byte, error) {
+	if !IsCompressedBlock(data) {
+		return data, nil
+	}
+	var h zstd.Header
+	if err := h.Decode(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCompressedBlock, err)
+	}
+	if !h.HasFCS || maxSize > 0 && h.FrameContentSize > uint64(maxSize) {
+		return nil, fmt.Errorf("%w: size %d, max %d", ErrCompressedBlock, h.FrameContentSize, maxSize)
+	}
+	// the decoder doesn't decode more than the content size of the frame
+	out, err := blockDecoder.DecodeAll(data, make([]byte, 0, h.FrameContentSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCompressedBlock, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,48 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCompressBlock(t *testing.T) {
+	b := &BlockData{
+		Header:     &BlockHeader{BlockId: 10, Timestamp: 1700000020, BlockHash: []byte("hash")},
+		PrevHeader: &BlockHeader{BlockId: 9, BlockHash: []byte("prev")},
+		TxFullData: [][]byte{bytes.Repeat([]byte("tx"), 1000)},
+	}
+	legacy, err := b.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted, err := WithBlockFormat(BlockFormatV1, legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]byte{legacy, formatted} {
+		if IsCompressedBlock(data) {
+			t.Fatalf("uncompressed block %x is detected as compressed", data[:2])
+		}
+		compressed := CompressBlock(data)
+		if !IsCompressedBlock(compressed) || len(compressed) >= len(data) {
+			t.Fatalf("block isn't compressed, %d bytes of %d", len(compressed), len(data))
+		}
+		got, err := DecompressBlock(compressed, int64(len(data)))
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("wrong decompressed block %v", err)
+		}
+		// the legacy blocks are decoded as is
+		if got, err = DecompressBlock(data, 0); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("wrong uncompressed block %v", err)
+		}
+		if _, err = DecompressBlock(compressed, int64(len(data)-1)); !errors.Is(err, ErrCompressedBlock) {
+			t.Errorf("expected %v, got %v", ErrCompressedBlock, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/gogo/protobuf/proto"
@@ -9,6 +10,7 @@ import (
 	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/consts"
 	"github.com/IBAX-io/go-ibax/packages/converter"
+	"github.com/IBAX-io/go-ibax/packages/keystore"
 	"github.com/pkg/errors"
 )
 
@@ -27,10 +29,24 @@ var (
 	ErrUnmarshallBlock = errors.New("Unmarshall block")
 )
 
+// blockVer returns the fields of the hash and the signature which are added by the later versions.
+// The base gas price is added if the fee market is enabled, so the hashes of the old blocks are kept
 func blockVer(cur, prev *BlockHeader) (ret string) {
 	if cur.Version >= consts.BvRollbackHash {
 		ret = fmt.Sprintf(",%x", prev.RollbacksHash)
 	}
+	if cur.BaseGasPrice != 0 {
+		ret += fmt.Sprintf(",%d", cur.BaseGasPrice)
+	}
+	if len(cur.RandomBeacon) > 0 {
+		ret += fmt.Sprintf(",%x,%x,%x", cur.RandomBeacon, cur.RandomReveal, cur.RandomCommit)
+	}
+	if len(cur.StateRoot) > 0 {
+		ret += fmt.Sprintf(",%x", cur.StateRoot)
+	}
+	if len(cur.EventsBloom) > 0 {
+		ret += fmt.Sprintf(",%x", cur.EventsBloom)
+	}
 	return
 }
 
@@ -116,7 +132,8 @@ func (b BlockData) ForSign() string {
 	return b.Header.ForSign(b.PrevHeader, b.MerkleRoot)
 }
 
-func (b *BlockData) GenMerkleRoot() []byte {
+// LeafHashes returns the items of Merkle tree, one for each transaction of the block
+func (b *BlockData) LeafHashes() [][]byte {
 	var mrklArray [][]byte
 	for _, tr := range b.TxFullData {
 		mrklArray = append(mrklArray, converter.BinToHex(crypto.DoubleHash(tr)))
@@ -124,12 +141,16 @@ func (b *BlockData) GenMerkleRoot() []byte {
 	if len(mrklArray) == 0 {
 		mrklArray = append(mrklArray, []byte("0"))
 	}
-	return MerkleTreeRoot(mrklArray)
+	return mrklArray
+}
+
+func (b *BlockData) GenMerkleRoot() []byte {
+	return MerkleTreeRoot(b.LeafHashes())
 }
 
-func (b *BlockData) GetSign(key []byte) ([]byte, error) {
+func (b *BlockData) GetSign(signer keystore.Signer) ([]byte, error) {
 	forSign := b.ForSign()
-	signed, err := crypto.Sign(key, []byte(forSign))
+	signed, err := keystore.SignData(signer, []byte(forSign))
 	if err != nil {
 		return nil, errors.Wrap(err, "signing block")
 	}
@@ -137,7 +158,13 @@ func (b *BlockData) GetSign(key []byte) ([]byte, error) {
 }
 
 // MarshallBlock is marshalling block
-func (b *BlockData) MarshallBlock(key []byte) ([]byte, error) {
+func (b *BlockData) MarshallBlock(signer keystore.Signer) ([]byte, error) {
+	return b.MarshallProvedBlock(context.Background(), signer, 0)
+}
+
+// MarshallProvedBlock is marshalling the block with the proof of work of minBits, the proof isn't
+// searched if minBits is zero
+func (b *BlockData) MarshallProvedBlock(ctx context.Context, signer keystore.Signer, minBits int) ([]byte, error) {
 	//if b.AfterTxs != nil {
 	//	for i := 0; i < len(b.AfterTxs.TxBinLogSql); i++ {
 	//		b.AfterTxs.TxBinLogSql[i] = DoZlibCompress(b.AfterTxs.TxBinLogSql[i])
@@ -147,7 +174,12 @@ func (b *BlockData) MarshallBlock(key []byte) ([]byte, error) {
 		b.TxFullData[i] = DoZlibCompress(b.TxFullData[i])
 	}
 	b.MerkleRoot = b.GenMerkleRoot()
-	signed, err := b.GetSign(key)
+	if minBits > 0 {
+		if err := b.FindProofOfWork(ctx, minBits); err != nil {
+			return nil, errors.Wrap(err, "searching proof of work")
+		}
+	}
+	signed, err := b.GetSign(signer)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +195,10 @@ func (b *BlockData) UnmarshallBlock(data []byte) error {
 	if len(data) < minBlockSize {
 		return ErrMinBlockSize(len(data), minBlockSize)
 	}
-	if err := proto.Unmarshal(data, b); err != nil {
+	if err := b.decodeBlockFormat(data); err != nil {
+		if errors.Is(err, ErrUnknownBlockVersion) {
+			return err
+		}
 		return errors.Wrap(err, "unmarshalling block")
 	}
 	//if b.AfterTxs != nil {
@@ -178,6 +213,41 @@ func (b *BlockData) UnmarshallBlock(data []byte) error {
 	return nil
 }
 
+// StripTxs returns the binary of the block without the transactions, the header and the merkle
+// root of the transactions and the format of the binary are kept
+func StripTxs(data []byte) ([]byte, error) {
+	b := &BlockData{}
+	if err := b.decodeBlockFormat(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling block")
+	}
+	b.TxFullData = nil
+	stripped, err := proto.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	version, _ := BlockFormatOf(data)
+	return WithBlockFormat(version, stripped)
+}
+
+// WithBlockSignatures returns the binary of the block with the signatures of the validators in the header,
+// the rest of the block and the format of the binary are kept
+func WithBlockSignatures(data []byte, signs []*BlockSignature) ([]byte, error) {
+	b := &BlockData{}
+	if err := b.decodeBlockFormat(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling block")
+	}
+	if b.Header == nil {
+		return nil, ErrUnmarshallBlock
+	}
+	b.Header.BlockSignatures = signs
+	signed, err := proto.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	version, _ := BlockFormatOf(data)
+	return WithBlockFormat(version, signed)
+}
+
 // MerkleTreeRoot return Merkle value
 func MerkleTreeRoot(dataArray [][]byte) []byte {
 	result := make(map[int32][][]byte)
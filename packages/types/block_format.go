@@ -0,0 +1,73 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// The formats of the block binary. The legacy binary is the protobuf of BlockData without the format,
+// the other formats start with blockFormatMarker and the version byte
+const (
+	BlockFormatLegacy byte = 0
+	BlockFormatV1     byte = 1
+	// MaxBlockFormat is the latest format which is known by the node
+	MaxBlockFormat = BlockFormatV1
+)
+
+// blockFormatMarker can't be the first byte of the protobuf, it's the tag with the wire type 7
+const blockFormatMarker = 0xff
+
+// ErrUnknownBlockVersion is returned if the format of the block binary is newer than the node knows
+var ErrUnknownBlockVersion = errors.New("unknown block format version")
+
+// blockDecoders decode the block binary without the format by its version
+var blockDecoders = map[byte]func(b *BlockData, data []byte) error{
+	BlockFormatLegacy: unmarshallProto,
+	BlockFormatV1:     unmarshallProto,
+}
+
+func unmarshallProto(b *BlockData, data []byte) error {
+	return proto.Unmarshal(data, b)
+}
+
+// BlockFormatOf returns the format version of the block binary and the binary without the format
+func BlockFormatOf(data []byte) (version byte, payload []byte) {
+	if len(data) > 1 && data[0] == blockFormatMarker {
+		return data[1], data[2:]
+	}
+	return BlockFormatLegacy, data
+}
+
+// IsKnownBlockFormat returns true if the node decodes the blocks of the format version
+func IsKnownBlockFormat(version byte) bool {
+	_, ok := blockDecoders[version]
+	return ok
+}
+
+// WithBlockFormat returns the binary of the block marshalled as protobuf in the format version
+func WithBlockFormat(version byte, data []byte) ([]byte, error) {
+	if !IsKnownBlockFormat(version) {
+		return nil, fmt.Errorf("%w %d", ErrUnknownBlockVersion, version)
+	}
+	if version == BlockFormatLegacy {
+		return data, nil
+	}
+	return append([]byte{blockFormatMarker, version}, data...), nil
+}
+
+// decodeBlockFormat decodes the block binary in any known format
+func (b *BlockData) decodeBlockFormat(data []byte) error {
+	version, payload := BlockFormatOf(data)
+	decode, ok := blockDecoders[version]
+	if !ok {
+		return fmt.Errorf("%w %d", ErrUnknownBlockVersion, version)
+	}
+	return decode(b, payload)
+}
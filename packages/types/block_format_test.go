@@ -0,0 +1,51 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestBlockFormat(t *testing.T) {
+	legacy, err := proto.Marshal(&BlockData{Header: &BlockHeader{BlockId: 7, BlockHash: []byte("hash")}, TxFullData: [][]byte{DoZlibCompress([]byte("tx"))}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version, _ := BlockFormatOf(legacy); version != BlockFormatLegacy {
+		t.Fatalf("protobuf is read as format %d", version)
+	}
+	v1, err := WithBlockFormat(BlockFormatV1, legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for version, data := range map[byte][]byte{BlockFormatLegacy: legacy, BlockFormatV1: v1} {
+		b := &BlockData{}
+		if err = b.UnmarshallBlock(data); err != nil {
+			t.Fatalf("format %d: %v", version, err)
+		}
+		if b.Header.BlockId != 7 || len(b.TxFullData) != 1 || string(b.TxFullData[0]) != "tx" {
+			t.Errorf("format %d: wrong block %v", version, b)
+		}
+		stripped, err := StripTxs(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := BlockFormatOf(stripped); v != version {
+			t.Errorf("format %d is changed to %d by StripTxs", version, v)
+		}
+	}
+
+	unknown := append([]byte{blockFormatMarker, MaxBlockFormat + 1}, legacy...)
+	if err = new(BlockData).UnmarshallBlock(unknown); !errors.Is(err, ErrUnknownBlockVersion) {
+		t.Errorf("expected unknown version, got %v", err)
+	}
+	if _, err = WithBlockFormat(MaxBlockFormat+1, legacy); !errors.Is(err, ErrUnknownBlockVersion) {
+		t.Errorf("unknown format must not be written, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/bits"
+	"runtime"
+	"strconv"
+)
+
+// powCheckInterval is the number of the nonces which are tried between the checks of the context
+const powCheckInterval = 1 << 12
+
+func powHash(forSign string, nonce uint64) []byte {
+	hash := sha256.Sum256([]byte(forSign + "," + strconv.FormatUint(nonce, 10)))
+	return hash[:]
+}
+
+// LeadingZeroBits returns the number of the leading zero bits of the hash
+func LeadingZeroBits(hash []byte) int {
+	var n int
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+// ProofOfWorkHash returns the hash of the proof of work of the block. It covers the signed fields of
+// the header, so the nonce is still valid when the generated block is marshalled again with the
+// rollbacks hash. sha256 is used whatever the hasher of the network is, so the bits cost the same
+func (b BlockData) ProofOfWorkHash() []byte {
+	return powHash(b.ForSign(), b.Header.ProofOfWork)
+}
+
+// CheckProofOfWork returns true if the block has the proof of work of at least minBits, zero minBits
+// disables the proof of work
+func (b BlockData) CheckProofOfWork(minBits int) bool {
+	return minBits > 0 && LeadingZeroBits(b.ProofOfWorkHash()) >= minBits
+}
+
+// FindProofOfWork searches the nonce of the proof of work of minBits and sets it in the header. The
+// Merkle root must be counted before it. The search is run by a goroutine on each cpu, it's stopped
+// when ctx is done
+func (b *BlockData) FindProofOfWork(ctx context.Context, minBits int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	forSign := b.ForSign()
+	workers := runtime.NumCPU()
+	found := make(chan uint64, workers)
+	for i := 0; i < workers; i++ {
+		go func(nonce uint64) {
+			for n := 0; ; n++ {
+				if n%powCheckInterval == 0 && ctx.Err() != nil {
+					return
+				}
+				if LeadingZeroBits(powHash(forSign, nonce)) >= minBits {
+					found <- nonce
+					return
+				}
+				nonce += uint64(workers)
+			}
+		}(uint64(i))
+	}
+	select {
+	case nonce := <-found:
+		b.Header.ProofOfWork = nonce
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLeadingZeroBits(t *testing.T) {
+	for _, c := range []struct {
+		hash []byte
+		bits int
+	}{
+		{[]byte{0x80, 0}, 0},
+		{[]byte{0x01, 0}, 7},
+		{[]byte{0, 0x10}, 11},
+		{[]byte{0, 0}, 16},
+	} {
+		if got := LeadingZeroBits(c.hash); got != c.bits {
+			t.Errorf("%x: expected %d got %d", c.hash, c.bits, got)
+		}
+	}
+}
+
+func TestFindProofOfWork(t *testing.T) {
+	b := &BlockData{
+		Header:     &BlockHeader{BlockId: 10, Timestamp: 1700000020, NodePosition: 1},
+		PrevHeader: &BlockHeader{BlockId: 9, BlockHash: []byte("prev")},
+		MerkleRoot: []byte("root"),
+	}
+	if err := b.FindProofOfWork(context.Background(), 12); err != nil {
+		t.Fatal(err)
+	}
+	if !b.CheckProofOfWork(12) || b.CheckProofOfWork(0) {
+		t.Errorf("nonce %d: expected valid proof", b.Header.ProofOfWork)
+	}
+	// the rollbacks hash of the block itself isn't covered by the proof
+	b.Header.RollbacksHash = []byte("rollbacks")
+	if !b.CheckProofOfWork(12) {
+		t.Error("proof is broken by rollbacks hash")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.FindProofOfWork(ctx, 256); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled search, got %v", err)
+	}
+}
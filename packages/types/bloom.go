@@ -0,0 +1,92 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+const (
+	// BloomLength is the size of the bloom filter of the events of the block in bytes
+	BloomLength = 256
+	// bloomHashes is the number of the bits which are set by the topic
+	bloomHashes = 3
+)
+
+// Bloom is the 2048-bit filter of the topics of the events of the block. The topic of the event is in
+// the block if its bits are set, the false positives are possible but the false negatives aren't
+type Bloom [BloomLength]byte
+
+// BytesToBloom returns the bloom of the bytes, it is empty if the length is wrong
+func BytesToBloom(data []byte) (b Bloom) {
+	if len(data) == BloomLength {
+		copy(b[:], data)
+	}
+	return
+}
+
+// bloomBits returns the bits of the topic, they are taken from the pairs of the bytes of its hash
+func bloomBits(topic []byte) (bits [bloomHashes]uint) {
+	h := crypto.Hash(topic)
+	for i := range bits {
+		bits[i] = (uint(h[2*i])<<8 | uint(h[2*i+1])) & (BloomLength*8 - 1)
+	}
+	return
+}
+
+// Add sets the bits of the topic
+func (b *Bloom) Add(topic []byte) {
+	for _, bit := range bloomBits(topic) {
+		b[BloomLength-1-bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test returns true if the topic may be in the bloom
+func (b *Bloom) Test(topic []byte) bool {
+	for _, bit := range bloomBits(topic) {
+		if b[BloomLength-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the bytes of the bloom
+func (b *Bloom) Bytes() []byte {
+	return b[:]
+}
+
+// EcosystemTopic returns the topic of the events of the ecosystem
+func EcosystemTopic(ecosystemID int64) []byte {
+	return []byte(fmt.Sprintf("ecosystem:%d", ecosystemID))
+}
+
+// ContractTopic returns the topic of the events of the contract of the ecosystem
+func ContractTopic(ecosystemID int64, contract string) []byte {
+	return []byte(fmt.Sprintf("contract:%d,%s", ecosystemID, contract))
+}
+
+// EventTopic returns the topic of the events with the name which are emitted by the contract of the ecosystem
+func EventTopic(ecosystemID int64, contract, event string) []byte {
+	return []byte(fmt.Sprintf("event:%d,%s,%s", ecosystemID, contract, event))
+}
+
+// AddressTopic returns the topic of the events of the transactions of the key
+func AddressTopic(keyID int64) []byte {
+	return []byte(fmt.Sprintf("address:%d", keyID))
+}
+
+// EventTopics returns the topics of the event which are added to the bloom of the block
+func EventTopics(ecosystemID int64, contract, event string, keyID int64) [][]byte {
+	return [][]byte{
+		EcosystemTopic(ecosystemID),
+		ContractTopic(ecosystemID, contract),
+		EventTopic(ecosystemID, contract, event),
+		AddressTopic(keyID),
+	}
+}
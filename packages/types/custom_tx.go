@@ -28,8 +28,38 @@ const (
 	DelayTxType
 	UtxoTxType
 	TransferSelfTxType
+	AbstractAccountTxType
+	KeyRotationTxType
+	ConfidentialUTXOTxType
+	BanLiftTxType
 )
 
+// txTypeNames are the names of the transaction types, every new type must be added here
+var txTypeNames = map[byte]string{
+	FirstBlockTxType:       "FirstBlock",
+	StopNetworkTxType:      "StopNetwork",
+	SmartContractTxType:    "SmartContract",
+	DelayTxType:            "Delay",
+	UtxoTxType:             "UTXO",
+	TransferSelfTxType:     "TransferSelf",
+	AbstractAccountTxType:  "AbstractAccount",
+	KeyRotationTxType:      "KeyRotation",
+	ConfidentialUTXOTxType: "ConfidentialUTXO",
+	BanLiftTxType:          "BanLift",
+}
+
+// TxTypeName returns the name of the transaction type, the custom types are named Custom<type>.
+// It returns false for the unknown type
+func TxTypeName(txType byte) (string, bool) {
+	if name, ok := txTypeNames[txType]; ok {
+		return name, true
+	}
+	if IsCustomTxType(int(txType)) {
+		return fmt.Sprintf("Custom%d", txType), true
+	}
+	return "", false
+}
+
 // FirstBlock is the header of first block transaction
 type FirstBlock struct {
 	KeyID                 int64
@@ -39,6 +69,8 @@ type FirstBlock struct {
 	StopNetworkCertBundle []byte
 	Test                  int64
 	PrivateBlockchain     uint64
+	// Genesis is the JSON of the genesis spec v2 which is applied after the first ecosystem is created
+	Genesis []byte `msgpack:",omitempty"`
 }
 
 func (t *FirstBlock) TxType() byte { return FirstBlockTxType }
@@ -51,6 +83,62 @@ type StopNetwork struct {
 
 func (t *StopNetwork) TxType() byte { return StopNetworkTxType }
 
+// KeyRotation replaces the public key of the honor node, the announcement is signed by both keys
+type KeyRotation struct {
+	KeyID        int64 // the address of the old key
+	Time         int64 // unix time in milliseconds
+	OldPublicKey []byte
+	NewPublicKey []byte
+	OldSign      []byte
+	NewSign      []byte
+}
+
+func (t *KeyRotation) TxType() byte { return KeyRotationTxType }
+
+// Announcement returns the data which is signed by the old and the new keys
+func (t *KeyRotation) Announcement() []byte {
+	return []byte(fmt.Sprintf("key_rotation,%d,%d,%x,%x", t.KeyID, t.Time, t.OldPublicKey, t.NewPublicKey))
+}
+
+// BanLift lifts the ban of the key which is banned by the bad transactions on all nodes,
+// it's signed by the key of the honor node
+type BanLift struct {
+	KeyID     int64 // the address of the node key
+	Time      int64 // unix time in milliseconds
+	BannedID  int64 // the banned key
+	PublicKey []byte
+	Sign      []byte
+}
+
+func (t *BanLift) TxType() byte { return BanLiftTxType }
+
+// Announcement returns the data which is signed by the node key
+func (t *BanLift) Announcement() []byte {
+	return []byte(fmt.Sprintf("ban_lift,%d,%d,%d,%x", t.KeyID, t.Time, t.BannedID, t.PublicKey))
+}
+
+// The custom transaction types are registered by the registered_tx_types platform parameter,
+// they are executed by the handlers of the plugins without the upgrade of the nodes
+const (
+	CustomTxTypeMin = 16
+	CustomTxTypeMax = 127
+)
+
+// IsCustomTxType returns true if the type is in the range of the custom transaction types
+func IsCustomTxType(txType int) bool {
+	return txType >= CustomTxTypeMin && txType <= CustomTxTypeMax
+}
+
+// CustomTransaction is the envelope of the custom transaction, Data is decoded by the handler
+type CustomTransaction struct {
+	Type  byte
+	KeyID int64
+	Time  int64
+	Data  []byte
+}
+
+func (t *CustomTransaction) TxType() byte { return t.Type }
+
 // Header is contain header data
 type Header struct {
 	ID          int
@@ -74,6 +162,25 @@ type UTXO struct {
 	Comment string
 }
 
+// MaxConfidentialOutputs is the limit of the outputs of the confidential UTXO transfer
+const MaxConfidentialOutputs = 16
+
+// ConfidentialOutput is the output of the confidential UTXO transfer. The amount is hidden by the Pedersen
+// commitment with its range proof, or it's the plain Value without the commitment
+type ConfidentialOutput struct {
+	ToID       int64
+	Value      string `msgpack:",omitempty"`
+	Commitment []byte `msgpack:",omitempty"`
+	RangeProof []byte `msgpack:",omitempty"`
+}
+
+// ConfidentialUTXO transfers the amounts of the commitments, the fee is public
+type ConfidentialUTXO struct {
+	Outputs []ConfidentialOutput
+	Fee     string
+	Comment string
+}
+
 // SmartTransaction is storing smart contract data
 type SmartTransaction struct {
 	*Header
@@ -85,6 +192,13 @@ type SmartTransaction struct {
 	TransferSelf *TransferSelf
 	UTXO         *UTXO
 	Params       map[string]any
+	// DryRun executes the contract and charges the fee but discards its state changes
+	DryRun bool `msgpack:",omitempty"`
+	// AbstractAccount is set when the transaction is authorized by the auth contract of the key,
+	// the auth data is attached instead of the signature
+	AbstractAccount bool `msgpack:",omitempty"`
+	// ConfidentialUTXO spends the outputs of the key by the commitments of the amounts
+	ConfidentialUTXO *ConfidentialUTXO `msgpack:",omitempty"`
 }
 
 func (s *SmartTransaction) TxType() byte {
@@ -94,6 +208,12 @@ func (s *SmartTransaction) TxType() byte {
 	if s.UTXO != nil {
 		return UtxoTxType
 	}
+	if s.ConfidentialUTXO != nil {
+		return ConfidentialUTXOTxType
+	}
+	if s.AbstractAccount {
+		return AbstractAccountTxType
+	}
 	return SmartContractTxType
 }
 
@@ -133,6 +253,9 @@ func (t SmartTransaction) Hash() ([]byte, error) {
 }
 
 func (txSmart *SmartTransaction) Validate() error {
+	if txSmart.Header == nil {
+		return errors.New("empty transaction header")
+	}
 	if len(txSmart.Expedite) > 0 {
 		expedite, err := decimal.NewFromString(txSmart.Expedite)
 		if err != nil {
@@ -152,6 +275,13 @@ func (txSmart *SmartTransaction) Validate() error {
 		return fmt.Errorf("error networkid invalid")
 	}
 
+	if txSmart.DryRun && (txSmart.TransferSelf != nil || txSmart.UTXO != nil || txSmart.ConfidentialUTXO != nil) {
+		return errors.New("error dry run is supported by the contracts only")
+	}
+	if txSmart.AbstractAccount && (txSmart.TransferSelf != nil || txSmart.UTXO != nil || txSmart.ConfidentialUTXO != nil ||
+		txSmart.SignedBy != 0) {
+		return errors.New("error abstract account is supported by the contracts of the key only")
+	}
 	if txSmart.TransferSelf != nil {
 		if ok, _ := regexp.MatchString("^\\d+$", txSmart.TransferSelf.Value); !ok {
 			return errors.New("error TransferSelf Value must be a positive integer")
@@ -173,5 +303,44 @@ func (txSmart *SmartTransaction) Validate() error {
 		}
 		return nil
 	}
+	if txSmart.ConfidentialUTXO != nil {
+		return txSmart.ConfidentialUTXO.validate(txSmart)
+	}
+	return nil
+}
+
+func (c *ConfidentialUTXO) validate(txSmart *SmartTransaction) error {
+	if txSmart.TransferSelf != nil || txSmart.UTXO != nil {
+		return errors.New("error ConfidentialUTXO can't be sent with UTXO or TransferSelf")
+	}
+	if txSmart.EcosystemID != consts.DefaultTokenEcosystem {
+		return fmt.Errorf("error ConfidentialUTXO is supported in ecosystem %d only", consts.DefaultTokenEcosystem)
+	}
+	if len(c.Outputs) == 0 || len(c.Outputs) > MaxConfidentialOutputs {
+		return fmt.Errorf("error ConfidentialUTXO must have 1-%d outputs", MaxConfidentialOutputs)
+	}
+	for _, out := range c.Outputs {
+		if converter.IDToAddress(out.ToID) == `invalid` {
+			return errors.New("error ConfidentialUTXO ToID must be a valid address")
+		}
+		if len(out.Commitment) == 0 {
+			if ok, _ := regexp.MatchString("^\\d+$", out.Value); !ok {
+				return errors.New("error ConfidentialUTXO Value must be a positive integer")
+			}
+			if value, err := decimal.NewFromString(out.Value); err != nil || value.LessThanOrEqual(decimal.Zero) {
+				return errors.New("error ConfidentialUTXO Value must be greater than zero")
+			}
+			continue
+		}
+		if len(out.Value) > 0 {
+			return errors.New("error ConfidentialUTXO output must have either Value or Commitment")
+		}
+		if len(out.Commitment) != crypto.CommitmentLength || len(out.RangeProof) != crypto.RangeProofLength {
+			return errors.New("error ConfidentialUTXO commitment or range proof is malformed")
+		}
+	}
+	if ok, _ := regexp.MatchString("^\\d+$", c.Fee); !ok {
+		return errors.New("error ConfidentialUTXO Fee must be a non-negative integer")
+	}
 	return nil
 }
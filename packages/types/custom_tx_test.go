@@ -0,0 +1,92 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDryRunEncoding(t *testing.T) {
+	tx := SmartTransaction{Header: &Header{ID: 5, EcosystemID: 1, KeyID: 100}, Params: map[string]any{"Name": "test"}}
+	data, err := tx.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the transactions without the flag keep the encoding and the hash
+	if bytes.Contains(data, []byte("DryRun")) {
+		t.Fatalf("DryRun is encoded for the regular transaction")
+	}
+
+	tx.DryRun = true
+	if data, err = tx.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	var decoded SmartTransaction
+	if err = decoded.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.DryRun {
+		t.Errorf("DryRun is lost")
+	}
+	if err = decoded.Validate(); err != nil {
+		t.Errorf("dry run of the contract is rejected: %v", err)
+	}
+	decoded.UTXO = &UTXO{ToID: 100, Value: "1"}
+	if err = decoded.Validate(); err == nil {
+		t.Errorf("dry run of utxo transfer is accepted")
+	}
+}
+
+func TestAbstractAccountEncoding(t *testing.T) {
+	tx := SmartTransaction{Header: &Header{ID: 5, EcosystemID: 1, KeyID: 100}}
+	data, err := tx.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("AbstractAccount")) {
+		t.Fatalf("AbstractAccount is encoded for the regular transaction")
+	}
+
+	tx.AbstractAccount = true
+	if data, err = tx.Marshal(); err != nil {
+		t.Fatal(err)
+	}
+	var decoded SmartTransaction
+	if err = decoded.Unmarshal(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.TxType() != AbstractAccountTxType {
+		t.Errorf("expected type %d, got %d", AbstractAccountTxType, decoded.TxType())
+	}
+	if err = decoded.Validate(); err != nil {
+		t.Errorf("abstract account transaction is rejected: %v", err)
+	}
+	decoded.SignedBy = 200
+	if err = decoded.Validate(); err == nil {
+		t.Errorf("abstract account transaction signed by the other key is accepted")
+	}
+}
+
+func TestTxTypeName(t *testing.T) {
+	// every type below the custom ones which has the constant has the name
+	for txType := byte(FirstBlockTxType); txType <= BanLiftTxType; txType++ {
+		if name, ok := TxTypeName(txType); !ok || len(name) == 0 {
+			t.Errorf("type %d has no name", txType)
+		}
+	}
+	for txType, want := range map[byte]string{KeyRotationTxType: "KeyRotation", BanLiftTxType: "BanLift",
+		DelayTxType: "Delay", CustomTxTypeMin: "Custom16", CustomTxTypeMax: "Custom127"} {
+		if name, _ := TxTypeName(txType); name != want {
+			t.Errorf("wrong name of type %d: %s", txType, name)
+		}
+	}
+	for _, txType := range []byte{0, BanLiftTxType + 1, CustomTxTypeMin - 1, CustomTxTypeMax + 1} {
+		if name, ok := TxTypeName(txType); ok {
+			t.Errorf("unknown type %d has the name %s", txType, name)
+		}
+	}
+}
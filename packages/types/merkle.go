@@ -0,0 +1,71 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"bytes"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+)
+
+// MerkleProofItem is the sibling hash on the path from the leaf to the root of Merkle tree
+type MerkleProofItem struct {
+	Hash []byte `json:"hash"`
+	Left bool   `json:"left"` // true if the sibling is on the left side
+}
+
+func merkleLeaf(data []byte) []byte {
+	return converter.BinToHex(crypto.DoubleHash(data))
+}
+
+func merkleNode(left, right []byte) []byte {
+	return converter.BinToHex(crypto.DoubleHash(append(append([]byte{}, left...), right...)))
+}
+
+// MerkleTreeProof returns the proof of dataArray[index] for the root which is calculated by MerkleTreeRoot
+func MerkleTreeProof(dataArray [][]byte, index int) []MerkleProofItem {
+	if index < 0 || index >= len(dataArray) {
+		return nil
+	}
+	level := make([][]byte, len(dataArray))
+	for i, v := range dataArray {
+		level[i] = merkleLeaf(v)
+	}
+	proof := make([]MerkleProofItem, 0)
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 >= len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			switch index {
+			case i:
+				proof = append(proof, MerkleProofItem{Hash: level[i+1]})
+			case i + 1:
+				proof = append(proof, MerkleProofItem{Hash: level[i], Left: true})
+			}
+			next = append(next, merkleNode(level[i], level[i+1]))
+		}
+		index /= 2
+		level = next
+	}
+	return proof
+}
+
+// VerifyMerkleProof checks that data is included in the tree with the root
+func VerifyMerkleProof(root, data []byte, proof []MerkleProofItem) bool {
+	hash := merkleLeaf(data)
+	for _, item := range proof {
+		if item.Left {
+			hash = merkleNode(item.Hash, hash)
+		} else {
+			hash = merkleNode(hash, item.Hash)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
@@ -0,0 +1,41 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+)
+
+func TestMerkleTreeProof(t *testing.T) {
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	for count := 1; count <= 9; count++ {
+		block := &BlockData{}
+		for i := 0; i < count; i++ {
+			block.TxFullData = append(block.TxFullData, []byte(fmt.Sprintf("tx %d", i)))
+		}
+		root := block.GenMerkleRoot()
+		for i, tx := range block.TxFullData {
+			leaf := converter.BinToHex(crypto.DoubleHash(tx))
+			proof := MerkleTreeProof(block.LeafHashes(), i)
+			if !VerifyMerkleProof(root, leaf, proof) {
+				t.Fatalf("count %d: proof of %d is not valid", count, i)
+			}
+			if len(proof) == 0 {
+				continue
+			}
+			flipped := append([]byte{}, proof[0].Hash...)
+			flipped[0] ^= 1
+			proof[0].Hash = flipped
+			if VerifyMerkleProof(root, leaf, proof) {
+				t.Fatalf("count %d: proof of %d with flipped byte is valid", count, i)
+			}
+		}
+	}
+}
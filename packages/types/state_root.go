@@ -0,0 +1,37 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+)
+
+// StateLeaf returns the leaf of the state root for the row of the table with the key, value is the json
+// of the row after the block and it's empty for the removed row
+func StateLeaf(table, key string, value []byte) []byte {
+	return crypto.Hash(append([]byte(fmt.Sprintf("%s,%s,", table, key)), value...))
+}
+
+// StateRoot returns the state root of the leaves, the block without the changed rows has the root of the
+// empty leaf
+func StateRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return MerkleTreeRoot([][]byte{{}})
+	}
+	return MerkleTreeRoot(leaves)
+}
+
+// SplitStateLeaves returns the leaves which are joined one after another
+func SplitStateLeaves(data []byte) [][]byte {
+	size := len(crypto.Hash(nil))
+	leaves := make([][]byte, 0, len(data)/size)
+	for i := 0; i+size <= len(data); i += size {
+		leaves = append(leaves, data[i:i+size])
+	}
+	return leaves
+}
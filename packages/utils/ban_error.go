@@ -16,6 +16,10 @@ func (b *BanError) Error() string {
 	return b.err.Error()
 }
 
+func (b *BanError) Unwrap() error {
+	return b.err
+}
+
 func WithBan(err error) error {
 	return &BanError{
 		err: err,
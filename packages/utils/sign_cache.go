@@ -0,0 +1,125 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+
+package utils
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/consts"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+	log "github.com/sirupsen/logrus"
+)
+
+// SignCheck is the signature which is checked by CheckSign, Signs is the same as its argument
+type SignCheck struct {
+	PublicKey      []byte
+	ForSign        []byte
+	Signs          []byte
+	NodeKeyOrLogin bool
+}
+
+type signResult struct {
+	ok  bool
+	err error
+}
+
+var signCache = struct {
+	sync.RWMutex
+	results map[string]signResult
+}{results: make(map[string]signResult)}
+
+func signCacheKey(public, forSign, sign []byte) string {
+	key := make([]byte, 0, len(public)+len(forSign)+len(sign)+24)
+	for _, data := range [][]byte{public, forSign, sign} {
+		key = append(key, converter.EncodeLengthPlusData(data)...)
+	}
+	return string(key)
+}
+
+// firstSign returns the signature which is verified by CheckSign
+func firstSign(signs []byte, nodeKeyOrLogin bool) ([]byte, error) {
+	if nodeKeyOrLogin {
+		return signs, nil
+	}
+	length, err := converter.DecodeLength(&signs)
+	if err != nil {
+		return nil, err
+	}
+	return converter.BytesShift(&signs, length), nil
+}
+
+// PreVerifySigns verifies the signatures by the workers at once and keeps the results for CheckSign till
+// ResetSignCache. The ECDSA signatures can't be verified as the sum like the Schnorr ones, so the same
+// signature is verified once and the others are shared by the workers. It returns the verified signatures
+func PreVerifySigns(checks []SignCheck, workers int) int {
+	var (
+		wg   sync.WaitGroup
+		next = make(chan SignCheck)
+		keys = make(map[string]bool, len(checks))
+	)
+	if workers > len(checks) {
+		workers = len(checks)
+	}
+	verify := func(c SignCheck) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.WithFields(log.Fields{"type": consts.PanicRecoveredError, "error": r, "stack": string(debug.Stack())}).Error("recovered panic in pre-verifying sign")
+			}
+		}()
+		sign, err := firstSign(c.Signs, c.NodeKeyOrLogin)
+		if err != nil || len(sign) == 0 || len(c.PublicKey) == 0 || len(c.ForSign) == 0 {
+			// CheckSign reports the error of the signature
+			return
+		}
+		key := signCacheKey(c.PublicKey, c.ForSign, sign)
+		ok, err := crypto.Verify(c.PublicKey, c.ForSign, sign)
+		signCache.Lock()
+		signCache.results[key] = signResult{ok: ok, err: err}
+		signCache.Unlock()
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range next {
+				verify(c)
+			}
+		}()
+	}
+	var count int
+	for _, c := range checks {
+		id := signCacheKey(c.PublicKey, c.ForSign, c.Signs)
+		if keys[id] {
+			continue
+		}
+		keys[id] = true
+		count++
+		next <- c
+	}
+	close(next)
+	wg.Wait()
+	return count
+}
+
+// ResetSignCache drops the results of PreVerifySigns, the signatures are verified by CheckSign again
+func ResetSignCache() {
+	signCache.Lock()
+	signCache.results = make(map[string]signResult)
+	signCache.Unlock()
+}
+
+// verifySign returns the result of the signature which is verified in advance or verifies it
+func verifySign(public, forSign, sign []byte) (bool, error) {
+	signCache.RLock()
+	res, ok := signCache.results[signCacheKey(public, forSign, sign)]
+	signCache.RUnlock()
+	if ok {
+		return res.ok, res.err
+	}
+	return crypto.Verify(public, forSign, sign)
+}
@@ -0,0 +1,54 @@
+/*---------------------------------------------------------------------------------------------
+ *  Copyright (c) IBAX. All rights reserved.
+ *  See LICENSE in the project root for license information.
+ *--------------------------------------------------------------------------------------------*/
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/IBAX-io/go-ibax/packages/common/crypto"
+	"github.com/IBAX-io/go-ibax/packages/converter"
+)
+
+func TestPreVerifySigns(t *testing.T) {
+	crypto.InitAsymAlgo(crypto.AsymAlgo_ECC_Secp256k1.String())
+	crypto.InitHashAlgo(crypto.HashAlgo_KECCAK256.String())
+	defer ResetSignCache()
+	var checks []SignCheck
+	for i := 0; i < 8; i++ {
+		priv, pub, err := crypto.GenKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := crypto.DoubleHash([]byte(fmt.Sprintf("tx %d", i)))
+		sign, err := crypto.Sign(priv, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i%2 == 1 {
+			sign[len(sign)/2] ^= 1
+		}
+		checks = append(checks, SignCheck{PublicKey: pub, ForSign: hash, Signs: converter.EncodeLengthPlusData(sign)})
+	}
+	// the same signature is verified once
+	if n := PreVerifySigns(append(checks, checks[0]), 3); n != len(checks) {
+		t.Fatalf("expected %d verified signatures, got %d", len(checks), n)
+	}
+	if len(signCache.results) != len(checks) {
+		t.Fatalf("expected %d results, got %d", len(checks), len(signCache.results))
+	}
+	for i, c := range checks {
+		cached, cachedErr := CheckSign([][]byte{c.PublicKey}, c.ForSign, c.Signs, false)
+		ResetSignCache()
+		ok, err := CheckSign([][]byte{c.PublicKey}, c.ForSign, c.Signs, false)
+		if cached != ok || (cachedErr == nil) != (err == nil) {
+			t.Errorf("sign %d: cached %v %v, verified %v %v", i, cached, cachedErr, ok, err)
+		}
+		if ok != (i%2 == 0) {
+			t.Errorf("sign %d: wrong result %v", i, ok)
+		}
+		PreVerifySigns(checks[i+1:], 2)
+	}
+}
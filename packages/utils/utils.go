@@ -20,7 +20,6 @@ import (
 	"time"
 	"unicode"
 
-	"github.com/IBAX-io/go-ibax/packages/common/crypto"
 	"github.com/IBAX-io/go-ibax/packages/conf"
 	"github.com/IBAX-io/go-ibax/packages/conf/syspar"
 	"github.com/IBAX-io/go-ibax/packages/consts"
@@ -201,7 +200,7 @@ func CheckSign(publicKeys [][]byte, forSign []byte, signs []byte, nodeKeyOrLogin
 		}
 	}
 
-	return crypto.Verify(publicKeys[0], forSign, signsSlice[0])
+	return verifySign(publicKeys[0], forSign, signsSlice[0])
 }
 
 // GetCurrentDir returns the current directory